@@ -5,6 +5,7 @@ package config
 
 import (
 	"net/http"
+	"time"
 
 	"go.opentelemetry.io/collector/config/configtls"
 )
@@ -15,6 +16,7 @@ type Supervisor struct {
 	Agent        *Agent
 	Capabilities *Capabilities `mapstructure:"capabilities"`
 	Storage      *Storage      `mapstructure:"storage"`
+	Packages     *Packages     `mapstructure:"packages"`
 }
 
 type Storage struct {
@@ -31,6 +33,23 @@ type Capabilities struct {
 	ReportsOwnMetrics              *bool `mapstructure:"reports_own_metrics"`
 	ReportsHealth                  *bool `mapstructure:"reports_health"`
 	ReportsRemoteConfig            *bool `mapstructure:"reports_remote_config"`
+	AcceptsPackages                *bool `mapstructure:"accepts_packages"`
+	// ForwardsCustomMessages forwards the custom capabilities and messages between the
+	// OpAMP server and the opamp extension of the Collector.
+	ForwardsCustomMessages *bool `mapstructure:"forwards_custom_messages"`
+}
+
+// Packages configures how the Supervisor installs the Collector packages offered by the OpAMP server.
+type Packages struct {
+	// PublicKeyFile is the path to a PEM-encoded Ed25519 public key. The Supervisor only
+	// installs packages signed with the matching private key.
+	PublicKeyFile string `mapstructure:"public_key_file"`
+	// InsecureSkipSignature allows installing packages without verifying their signature
+	// when no PublicKeyFile is set. The Supervisor then executes any package the server offers.
+	InsecureSkipSignature bool `mapstructure:"insecure_skip_signature"`
+	// HealthCheckTimeout is how long the updated Collector has to become healthy before
+	// the Supervisor reverts the update.
+	HealthCheckTimeout time.Duration `mapstructure:"health_check_timeout"`
 }
 
 type OpAMPServer struct {
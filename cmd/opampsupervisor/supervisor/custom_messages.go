@@ -0,0 +1,185 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/open-telemetry/opamp-go/client"
+	"github.com/open-telemetry/opamp-go/client/types"
+	"github.com/open-telemetry/opamp-go/protobufs"
+	"github.com/open-telemetry/opamp-go/server"
+	serverTypes "github.com/open-telemetry/opamp-go/server/types"
+	"go.uber.org/zap"
+)
+
+// customMessagesQueueSize is the number of custom messages of the Collector that can
+// wait to be sent to the OpAMP server before new ones are dropped.
+const customMessagesQueueSize = 16
+
+// customMessages forwards the custom capabilities and messages between the OpAMP server
+// and the Collector. The opamp extension of the Collector connects to a local OpAMP server
+// of the Supervisor, and what it exchanges with the Supervisor is relayed through the
+// OpAMP client of the Supervisor.
+type customMessages struct {
+	logger   *zap.Logger
+	endpoint string
+	client   client.OpAMPClient
+	server   server.OpAMPServer
+
+	// toServer queues the custom messages of the Collector, as the OpAMP client only
+	// sends one custom message at a time.
+	toServer chan *protobufs.CustomMessage
+	done     chan struct{}
+	wg       sync.WaitGroup
+
+	mu sync.Mutex
+	// conn is the connection of the Collector, nil while it is not connected.
+	conn        serverTypes.Connection
+	instanceUID string
+	// serverCapabilities are the last custom capabilities of the OpAMP server, sent to the
+	// Collector when it connects.
+	serverCapabilities *protobufs.CustomCapabilities
+}
+
+func newCustomMessages(logger *zap.Logger, endpoint string) *customMessages {
+	return &customMessages{
+		logger:   logger,
+		endpoint: endpoint,
+		toServer: make(chan *protobufs.CustomMessage, customMessagesQueueSize),
+		done:     make(chan struct{}),
+	}
+}
+
+// start starts the local OpAMP server the Collector connects to. The custom messages of
+// the Collector are sent to the OpAMP server with the given client.
+func (c *customMessages) start(opampClient client.OpAMPClient) error {
+	c.client = opampClient
+	c.server = server.New(newLoggerFromZap(c.logger))
+	err := c.server.Start(newServerSettings(flattenedSettings{
+		endpoint:              c.endpoint,
+		onMessageFunc:         c.onAgentMessage,
+		onConnectionCloseFunc: c.onAgentConnectionClose,
+	}))
+	if err != nil {
+		return err
+	}
+
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		for {
+			select {
+			case <-c.done:
+				return
+			case msg := <-c.toServer:
+				c.sendToServer(msg)
+			}
+		}
+	}()
+	return nil
+}
+
+func (c *customMessages) stop(ctx context.Context) error {
+	close(c.done)
+	c.wg.Wait()
+	return c.server.Stop(ctx)
+}
+
+// onAgentMessage forwards the custom capabilities and messages of the Collector to the
+// OpAMP server. The custom capabilities of the Collector are the ones the Supervisor
+// reports, so the server only sends the custom messages the Collector supports.
+func (c *customMessages) onAgentMessage(conn serverTypes.Connection, msg *protobufs.AgentToServer) {
+	c.mu.Lock()
+	connected := c.conn != conn
+	c.conn = conn
+	c.instanceUID = msg.InstanceUid
+	serverCapabilities := c.serverCapabilities
+	c.mu.Unlock()
+
+	if connected && serverCapabilities != nil {
+		c.sendToAgent(conn, msg.InstanceUid, &protobufs.ServerToAgent{CustomCapabilities: serverCapabilities})
+	}
+
+	if msg.CustomCapabilities != nil {
+		if err := c.client.SetCustomCapabilities(msg.CustomCapabilities); err != nil {
+			c.logger.Error("Could not set the custom capabilities of the Collector", zap.Error(err))
+		}
+	}
+
+	if msg.CustomMessage != nil {
+		select {
+		case c.toServer <- msg.CustomMessage:
+		default:
+			c.logger.Warn("Dropping a custom message of the Collector, too many are waiting to be sent to the server",
+				zap.String("capability", msg.CustomMessage.Capability),
+				zap.String("type", msg.CustomMessage.Type))
+		}
+	}
+}
+
+func (c *customMessages) onAgentConnectionClose(conn serverTypes.Connection) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn == conn {
+		c.conn = nil
+	}
+}
+
+// sendToServer sends a custom message of the Collector to the OpAMP server, once the
+// previous custom message has been sent.
+func (c *customMessages) sendToServer(msg *protobufs.CustomMessage) {
+	for {
+		sent, err := c.client.SendCustomMessage(msg)
+		if !errors.Is(err, types.ErrCustomMessagePending) {
+			if err != nil {
+				c.logger.Error("Could not send a custom message of the Collector to the server",
+					zap.String("capability", msg.Capability), zap.Error(err))
+			}
+			return
+		}
+		select {
+		case <-sent:
+		case <-c.done:
+			return
+		}
+	}
+}
+
+// onServerCapabilities forwards the custom capabilities of the OpAMP server to the Collector.
+func (c *customMessages) onServerCapabilities(capabilities *protobufs.CustomCapabilities) {
+	c.mu.Lock()
+	c.serverCapabilities = capabilities
+	conn, instanceUID := c.conn, c.instanceUID
+	c.mu.Unlock()
+
+	if conn != nil {
+		c.sendToAgent(conn, instanceUID, &protobufs.ServerToAgent{CustomCapabilities: capabilities})
+	}
+}
+
+// onServerMessage forwards a custom message of the OpAMP server to the Collector. The
+// message is dropped if the Collector is not connected.
+func (c *customMessages) onServerMessage(msg *protobufs.CustomMessage) {
+	c.mu.Lock()
+	conn, instanceUID := c.conn, c.instanceUID
+	c.mu.Unlock()
+
+	if conn == nil {
+		c.logger.Warn("Dropping a custom message of the server, the Collector is not connected",
+			zap.String("capability", msg.Capability),
+			zap.String("type", msg.Type))
+		return
+	}
+	c.sendToAgent(conn, instanceUID, &protobufs.ServerToAgent{CustomMessage: msg})
+}
+
+func (c *customMessages) sendToAgent(conn serverTypes.Connection, instanceUID string, msg *protobufs.ServerToAgent) {
+	msg.InstanceUid = instanceUID
+	if err := conn.Send(context.Background(), msg); err != nil {
+		c.logger.Error("Could not send a custom message to the Collector", zap.Error(err))
+	}
+}
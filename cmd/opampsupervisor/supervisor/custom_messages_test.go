@@ -0,0 +1,189 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/open-telemetry/opamp-go/client"
+	"github.com/open-telemetry/opamp-go/client/types"
+	"github.com/open-telemetry/opamp-go/protobufs"
+	"github.com/open-telemetry/opamp-go/server"
+	serverTypes "github.com/open-telemetry/opamp-go/server/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/config/configtls"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/cmd/opampsupervisor/supervisor/config"
+)
+
+const testCustomCapability = "io.opentelemetry.test"
+
+// customMessagesServer is a local OpAMP server recording the custom capabilities and
+// messages reported by the agent, and sending a custom message to the agent once the agent
+// reports the test custom capability.
+type customMessagesServer struct {
+	endpoint string
+	sent     atomic.Bool
+
+	mu           sync.Mutex
+	capabilities *protobufs.CustomCapabilities
+	messages     []*protobufs.CustomMessage
+}
+
+func newCustomMessagesServer(t *testing.T) *customMessagesServer {
+	srv := &customMessagesServer{}
+	s := server.New(newLoggerFromZap(zap.NewNop()))
+	handler, _, err := s.Attach(server.Settings{
+		Callbacks: server.CallbacksStruct{
+			OnConnectingFunc: func(_ *http.Request) serverTypes.ConnectionResponse {
+				return serverTypes.ConnectionResponse{
+					Accept: true,
+					ConnectionCallbacks: server.ConnectionCallbacksStruct{
+						OnMessageFunc: func(_ context.Context, _ serverTypes.Connection, msg *protobufs.AgentToServer) *protobufs.ServerToAgent {
+							resp := &protobufs.ServerToAgent{InstanceUid: msg.InstanceUid}
+							srv.mu.Lock()
+							defer srv.mu.Unlock()
+							if msg.CustomCapabilities != nil {
+								srv.capabilities = msg.CustomCapabilities
+							}
+							if msg.CustomMessage != nil {
+								srv.messages = append(srv.messages, msg.CustomMessage)
+							}
+							if len(srv.capabilities.GetCapabilities()) > 0 && srv.sent.CompareAndSwap(false, true) {
+								resp.CustomCapabilities = &protobufs.CustomCapabilities{Capabilities: []string{testCustomCapability}}
+								resp.CustomMessage = &protobufs.CustomMessage{Capability: testCustomCapability, Type: "ping", Data: []byte("from server")}
+							}
+							return resp
+						},
+					},
+				}
+			},
+		},
+	})
+	require.NoError(t, err)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/opamp", handler)
+	httpSrv := httptest.NewServer(mux)
+	t.Cleanup(func() {
+		assert.NoError(t, s.Stop(context.Background()))
+		httpSrv.Close()
+	})
+	srv.endpoint = "ws://" + strings.TrimPrefix(httpSrv.URL, "http://") + "/v1/opamp"
+	return srv
+}
+
+func (s *customMessagesServer) received() (*protobufs.CustomCapabilities, []*protobufs.CustomMessage) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.capabilities, s.messages
+}
+
+func TestSupervisorForwardsCustomMessages(t *testing.T) {
+	srv := newCustomMessagesServer(t)
+
+	forwardsCustomMessages := true
+	s := &Supervisor{
+		logger: zap.NewNop(),
+		config: config.Supervisor{
+			Server: &config.OpAMPServer{
+				Endpoint:   srv.endpoint,
+				TLSSetting: configtls.ClientConfig{Insecure: true},
+			},
+			Capabilities: &config.Capabilities{ForwardsCustomMessages: &forwardsCustomMessages},
+		},
+		agentDescription: &protobufs.AgentDescription{
+			IdentifyingAttributes: []*protobufs.KeyValue{{
+				Key:   "service.name",
+				Value: &protobufs.AnyValue{Value: &protobufs.AnyValue_StringValue{StringValue: "otelcol"}},
+			}},
+		},
+		effectiveConfig:        &atomic.Value{},
+		connectedToOpAMPServer: make(chan struct{}),
+	}
+	port, err := s.findRandomPort()
+	require.NoError(t, err)
+	s.customMessages = newCustomMessages(s.logger, fmt.Sprintf("localhost:%d", port))
+
+	require.NoError(t, s.startOpAMP())
+	t.Cleanup(func() {
+		assert.NoError(t, s.stopOpAMP())
+	})
+	require.NoError(t, s.waitForOpAMPConnection())
+	require.NoError(t, s.customMessages.start(s.opampClient))
+	t.Cleanup(func() {
+		assert.NoError(t, s.customMessages.stop(context.Background()))
+	})
+
+	// The Collector connects to the Supervisor with its opamp extension.
+	var fromServer atomic.Value
+	agent := client.NewWebSocket(newLoggerFromZap(zap.NewNop()))
+	require.NoError(t, agent.SetCustomCapabilities(&protobufs.CustomCapabilities{Capabilities: []string{testCustomCapability}}))
+	require.NoError(t, agent.SetAgentDescription(s.agentDescription))
+	require.NoError(t, agent.Start(context.Background(), types.StartSettings{
+		OpAMPServerURL: "ws://" + s.customMessages.endpoint + "/v1/opamp",
+		InstanceUid:    ulid.Make().String(),
+		Callbacks: types.CallbacksStruct{
+			OnMessageFunc: func(_ context.Context, msg *types.MessageData) {
+				if msg.CustomMessage != nil {
+					fromServer.Store(msg.CustomMessage)
+				}
+			},
+		},
+	}))
+	t.Cleanup(func() {
+		assert.NoError(t, agent.Stop(context.Background()))
+	})
+
+	// The custom capabilities of the Collector are reported by the Supervisor.
+	require.Eventually(t, func() bool {
+		capabilities, _ := srv.received()
+		return capabilities != nil && proto.Equal(capabilities, &protobufs.CustomCapabilities{Capabilities: []string{testCustomCapability}})
+	}, 10*time.Second, 10*time.Millisecond)
+
+	// The custom messages of the server are forwarded to the Collector.
+	require.Eventually(t, func() bool {
+		msg, ok := fromServer.Load().(*protobufs.CustomMessage)
+		return ok && string(msg.Data) == "from server"
+	}, 10*time.Second, 10*time.Millisecond)
+
+	// The custom messages of the Collector are forwarded to the server.
+	require.Eventually(t, func() bool {
+		_, err = agent.SendCustomMessage(&protobufs.CustomMessage{Capability: testCustomCapability, Type: "pong", Data: []byte("from agent")})
+		return err == nil || errors.Is(err, types.ErrCustomMessagePending)
+	}, 10*time.Second, 10*time.Millisecond)
+	require.Eventually(t, func() bool {
+		_, messages := srv.received()
+		return len(messages) == 1 && string(messages[0].Data) == "from agent"
+	}, 10*time.Second, 10*time.Millisecond)
+}
+
+func TestComposeExtraLocalConfigConnectsCollector(t *testing.T) {
+	s := &Supervisor{
+		logger:                   zap.NewNop(),
+		agentDescription:         &protobufs.AgentDescription{},
+		agentHealthCheckEndpoint: "localhost:8000",
+		instanceID:               ulid.Make(),
+	}
+	require.NoError(t, s.createTemplates())
+	assert.NotContains(t, string(s.composeExtraLocalConfig()), "  opamp:")
+
+	s.customMessages = newCustomMessages(zap.NewNop(), "localhost:4321")
+	cfg := string(s.composeExtraLocalConfig())
+	assert.Contains(t, cfg, "extensions: [health_check, opamp]")
+	assert.Contains(t, cfg, `endpoint: "ws://localhost:4321/v1/opamp"`)
+	assert.Contains(t, cfg, fmt.Sprintf("instance_uid: %q", s.instanceID.String()))
+}
@@ -0,0 +1,393 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package supervisor
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/open-telemetry/opamp-go/client/types"
+	"github.com/open-telemetry/opamp-go/protobufs"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	packagesStateFile               = "packages_state.json"
+	lastReportedPackageStatusesFile = "last_reported_package_statuses.dat"
+)
+
+var _ types.PackagesStateProvider = (*agentPackages)(nil)
+
+// packagesState is the local state of the packages, persisted in the storage directory.
+type packagesState struct {
+	AllPackagesHash []byte                        `json:"all_packages_hash,omitempty"`
+	Packages        map[string]types.PackageState `json:"packages,omitempty"`
+	// FailedHashes are the hex-encoded content hashes of the packages that were reverted
+	// because the updated Collector didn't become healthy. They are not installed again.
+	FailedHashes []string `json:"failed_hashes,omitempty"`
+}
+
+// agentPackages keeps the local state of the packages offered by the OpAMP server. The
+// Collector executable is the only package that can be installed: the top-level package
+// offered by the server atomically replaces the agent executable, and the update is
+// reverted when the updated Collector doesn't become healthy.
+type agentPackages struct {
+	logger *zap.Logger
+	// storageDir is the directory the state is persisted to. The state is only kept in
+	// memory when it is empty.
+	storageDir string
+	executable string
+	publicKey  ed25519.PublicKey
+	// restartAgent restarts the Collector and returns an error if it doesn't become healthy.
+	restartAgent func(ctx context.Context) error
+
+	// installMu serializes the updates of the agent executable.
+	installMu sync.Mutex
+
+	mu                   sync.Mutex
+	state                packagesState
+	lastReportedStatuses *protobufs.PackageStatuses
+	// available is the last offer of the server, used to look up the package signatures.
+	available *protobufs.PackagesAvailable
+}
+
+func newAgentPackages(
+	logger *zap.Logger,
+	storageDir string,
+	executable string,
+	publicKeyFile string,
+	restartAgent func(ctx context.Context) error,
+) (*agentPackages, error) {
+	p := &agentPackages{
+		logger:       logger,
+		storageDir:   storageDir,
+		executable:   executable,
+		restartAgent: restartAgent,
+		state:        packagesState{Packages: map[string]types.PackageState{}},
+	}
+
+	if publicKeyFile != "" {
+		publicKey, err := loadPublicKey(publicKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("cannot load packages public key: %w", err)
+		}
+		p.publicKey = publicKey
+	}
+
+	if storageDir == "" {
+		return p, nil
+	}
+
+	if b, err := os.ReadFile(filepath.Join(storageDir, packagesStateFile)); err == nil {
+		if err = json.Unmarshal(b, &p.state); err != nil {
+			return nil, fmt.Errorf("cannot parse packages state: %w", err)
+		}
+		if p.state.Packages == nil {
+			p.state.Packages = map[string]types.PackageState{}
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	if b, err := os.ReadFile(filepath.Join(storageDir, lastReportedPackageStatusesFile)); err == nil {
+		statuses := &protobufs.PackageStatuses{}
+		if err = proto.Unmarshal(b, statuses); err != nil {
+			return nil, fmt.Errorf("cannot parse last reported package statuses: %w", err)
+		}
+		p.lastReportedStatuses = statuses
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+func loadPublicKey(file string) (ed25519.PublicKey, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data found in %s", file)
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	publicKey, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("unsupported public key type %T, only Ed25519 keys are supported", key)
+	}
+	return publicKey, nil
+}
+
+// setAvailable records the packages offered by the server before they are synced.
+func (p *agentPackages) setAvailable(available *protobufs.PackagesAvailable) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.available = available
+}
+
+// AllPackagesHash implements types.PackagesStateProvider.
+func (p *agentPackages) AllPackagesHash() ([]byte, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.state.AllPackagesHash, nil
+}
+
+// SetAllPackagesHash implements types.PackagesStateProvider.
+func (p *agentPackages) SetAllPackagesHash(hash []byte) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.state.AllPackagesHash = hash
+	return p.saveState()
+}
+
+// Packages implements types.PackagesStateProvider.
+func (p *agentPackages) Packages() ([]string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	names := make([]string, 0, len(p.state.Packages))
+	for name := range p.state.Packages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// PackageState implements types.PackagesStateProvider.
+func (p *agentPackages) PackageState(packageName string) (types.PackageState, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.state.Packages[packageName], nil
+}
+
+// SetPackageState implements types.PackagesStateProvider.
+func (p *agentPackages) SetPackageState(packageName string, state types.PackageState) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.state.Packages[packageName] = state
+	return p.saveState()
+}
+
+// CreatePackage implements types.PackagesStateProvider. Only the top-level package,
+// which is the Collector executable, is supported.
+func (p *agentPackages) CreatePackage(packageName string, typ protobufs.PackageType) error {
+	if typ != protobufs.PackageType_PackageType_TopLevel {
+		return fmt.Errorf("cannot create package %s: only top-level packages are supported", packageName)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for name, pkg := range p.state.Packages {
+		if name != packageName && pkg.Type == protobufs.PackageType_PackageType_TopLevel {
+			return fmt.Errorf("cannot create package %s: top-level package %s is already installed", packageName, name)
+		}
+	}
+	p.state.Packages[packageName] = types.PackageState{Exists: true, Type: typ}
+	return p.saveState()
+}
+
+// FileContentHash implements types.PackagesStateProvider. It returns the hash of the
+// agent executable, so that an offered package is not downloaded again if it is
+// already installed.
+func (p *agentPackages) FileContentHash(string) ([]byte, error) {
+	f, err := os.Open(p.executable)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err = io.Copy(h, f); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
+
+// UpdateContent implements types.PackagesStateProvider. It verifies the downloaded
+// package and installs it as the agent executable, then restarts the Collector. The
+// previous executable is restored if the updated Collector doesn't become healthy.
+func (p *agentPackages) UpdateContent(ctx context.Context, packageName string, data io.Reader, contentHash []byte) error {
+	p.installMu.Lock()
+	defer p.installMu.Unlock()
+
+	if p.hasFailed(contentHash) {
+		return fmt.Errorf("package %s with hash %x was reverted before and is not installed again", packageName, contentHash)
+	}
+
+	info, err := os.Stat(p.executable)
+	if err != nil {
+		return fmt.Errorf("cannot stat the agent executable: %w", err)
+	}
+
+	// Download next to the executable so that it can be replaced atomically.
+	tmp, err := os.CreateTemp(filepath.Dir(p.executable), filepath.Base(p.executable)+".download-*")
+	if err != nil {
+		return fmt.Errorf("cannot create the package file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	h := sha256.New()
+	_, err = io.Copy(io.MultiWriter(tmp, h), data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("cannot write the package file: %w", err)
+	}
+	if err = p.verify(packageName, h.Sum(nil), contentHash); err != nil {
+		return err
+	}
+	if err = os.Chmod(tmp.Name(), info.Mode().Perm()); err != nil {
+		return err
+	}
+
+	backup := p.executable + ".bak"
+	if err = copyFile(p.executable, backup, info.Mode().Perm()); err != nil {
+		return fmt.Errorf("cannot back up the agent executable: %w", err)
+	}
+	if err = os.Rename(tmp.Name(), p.executable); err != nil {
+		return fmt.Errorf("cannot replace the agent executable: %w", err)
+	}
+
+	p.logger.Info("Agent executable updated, restarting the agent", zap.String("package", packageName))
+	if err = p.restartAgent(ctx); err != nil {
+		p.logger.Error("Updated agent is not healthy, reverting the update", zap.String("package", packageName), zap.Error(err))
+		if saveErr := p.setFailed(contentHash); saveErr != nil {
+			p.logger.Error("Cannot save the packages state", zap.Error(saveErr))
+		}
+		if revertErr := os.Rename(backup, p.executable); revertErr != nil {
+			return fmt.Errorf("updated agent is not healthy: %w; cannot revert the update: %v", err, revertErr)
+		}
+		if restartErr := p.restartAgent(context.WithoutCancel(ctx)); restartErr != nil {
+			p.logger.Error("Reverted agent is not healthy", zap.Error(restartErr))
+		}
+		return fmt.Errorf("updated agent is not healthy, the update was reverted: %w", err)
+	}
+
+	if err = os.Remove(backup); err != nil {
+		p.logger.Warn("Cannot remove the backup of the agent executable", zap.Error(err))
+	}
+	return nil
+}
+
+// verify checks the hash of the downloaded package and the signature offered by the
+// server for it. The signature is only skipped without a public key, which requires
+// insecure_skip_signature to be set.
+func (p *agentPackages) verify(packageName string, hash, contentHash []byte) error {
+	if len(contentHash) == 0 {
+		return fmt.Errorf("package %s was offered without a content hash", packageName)
+	}
+	if !bytes.Equal(hash, contentHash) {
+		return fmt.Errorf("hash of the downloaded package %s is %x, expected %x", packageName, hash, contentHash)
+	}
+	if p.publicKey == nil {
+		return nil
+	}
+
+	p.mu.Lock()
+	signature := p.available.GetPackages()[packageName].GetFile().GetSignature()
+	p.mu.Unlock()
+	if !ed25519.Verify(p.publicKey, hash, signature) {
+		return fmt.Errorf("invalid signature of package %s", packageName)
+	}
+	return nil
+}
+
+// DeletePackage implements types.PackagesStateProvider. The agent executable is kept,
+// since the Collector cannot run without it.
+func (p *agentPackages) DeletePackage(packageName string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.state.Packages, packageName)
+	return p.saveState()
+}
+
+// LastReportedStatuses implements types.PackagesStateProvider.
+func (p *agentPackages) LastReportedStatuses() (*protobufs.PackageStatuses, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.lastReportedStatuses, nil
+}
+
+// SetLastReportedStatuses implements types.PackagesStateProvider.
+func (p *agentPackages) SetLastReportedStatuses(statuses *protobufs.PackageStatuses) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.lastReportedStatuses = statuses
+	if p.storageDir == "" {
+		return nil
+	}
+
+	b, err := proto.Marshal(statuses)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(p.storageDir, lastReportedPackageStatusesFile), b, 0600)
+}
+
+func (p *agentPackages) hasFailed(contentHash []byte) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, hash := range p.state.FailedHashes {
+		if hash == hex.EncodeToString(contentHash) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *agentPackages) setFailed(contentHash []byte) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.state.FailedHashes = append(p.state.FailedHashes, hex.EncodeToString(contentHash))
+	return p.saveState()
+}
+
+// saveState persists the packages state. Must be called with the lock held.
+func (p *agentPackages) saveState() error {
+	if p.storageDir == "" {
+		return nil
+	}
+
+	b, err := json.Marshal(p.state)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(p.storageDir, packagesStateFile), b, 0600)
+}
+
+func copyFile(src, dst string, perm os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,387 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package supervisor
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/open-telemetry/opamp-go/client/types"
+	"github.com/open-telemetry/opamp-go/protobufs"
+	"github.com/open-telemetry/opamp-go/server"
+	serverTypes "github.com/open-telemetry/opamp-go/server/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/config/configtls"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/cmd/opampsupervisor/supervisor/config"
+)
+
+func sha256Sum(b []byte) []byte {
+	h := sha256.Sum256(b)
+	return h[:]
+}
+
+// newTestAgentPackages creates agentPackages managing an executable with the "old" content.
+func newTestAgentPackages(t *testing.T, publicKeyFile string, restartAgent func(context.Context) error) (*agentPackages, string) {
+	dir := t.TempDir()
+	executable := filepath.Join(dir, "otelcol")
+	require.NoError(t, os.WriteFile(executable, []byte("old"), 0700))
+	p, err := newAgentPackages(zap.NewNop(), dir, executable, publicKeyFile, restartAgent)
+	require.NoError(t, err)
+	return p, executable
+}
+
+func writePublicKey(t *testing.T, publicKey ed25519.PublicKey) string {
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	require.NoError(t, err)
+	file := filepath.Join(t.TempDir(), "packages.pub")
+	require.NoError(t, os.WriteFile(file, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0600))
+	return file
+}
+
+func TestAgentPackagesUpdateContent(t *testing.T) {
+	restarts := 0
+	p, executable := newTestAgentPackages(t, "", func(context.Context) error {
+		restarts++
+		return nil
+	})
+
+	hash, err := p.FileContentHash("")
+	require.NoError(t, err)
+	assert.Equal(t, sha256Sum([]byte("old")), hash)
+
+	require.NoError(t, p.UpdateContent(context.Background(), "", bytes.NewReader([]byte("new")), sha256Sum([]byte("new"))))
+	content, err := os.ReadFile(executable)
+	require.NoError(t, err)
+	assert.Equal(t, "new", string(content))
+	assert.Equal(t, 1, restarts)
+	assert.NoFileExists(t, executable+".bak")
+
+	err = p.UpdateContent(context.Background(), "", bytes.NewReader([]byte("corrupted")), sha256Sum([]byte("newer")))
+	assert.ErrorContains(t, err, "hash of the downloaded package")
+	content, err = os.ReadFile(executable)
+	require.NoError(t, err)
+	assert.Equal(t, "new", string(content))
+	assert.Equal(t, 1, restarts)
+}
+
+func TestAgentPackagesRevertsUnhealthyUpdate(t *testing.T) {
+	var executable string
+	var restartedContents []string
+	p, executable := newTestAgentPackages(t, "", func(context.Context) error {
+		content, err := os.ReadFile(executable)
+		require.NoError(t, err)
+		restartedContents = append(restartedContents, string(content))
+		if string(content) == "new" {
+			return errors.New("health check returned 503")
+		}
+		return nil
+	})
+
+	err := p.UpdateContent(context.Background(), "", bytes.NewReader([]byte("new")), sha256Sum([]byte("new")))
+	assert.ErrorContains(t, err, "the update was reverted: health check returned 503")
+	content, err := os.ReadFile(executable)
+	require.NoError(t, err)
+	assert.Equal(t, "old", string(content))
+	assert.Equal(t, []string{"new", "old"}, restartedContents)
+
+	// The reverted package is not installed again, even after the Supervisor restarts.
+	reloaded, err := newAgentPackages(zap.NewNop(), p.storageDir, executable, "", p.restartAgent)
+	require.NoError(t, err)
+	err = reloaded.UpdateContent(context.Background(), "", bytes.NewReader([]byte("new")), sha256Sum([]byte("new")))
+	assert.ErrorContains(t, err, "was reverted before")
+	assert.Len(t, restartedContents, 2)
+}
+
+func TestAgentPackagesVerifiesSignature(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	p, executable := newTestAgentPackages(t, writePublicKey(t, publicKey), func(context.Context) error { return nil })
+
+	hash := sha256Sum([]byte("new"))
+	offer := func(signature []byte) {
+		p.setAvailable(&protobufs.PackagesAvailable{
+			Packages: map[string]*protobufs.PackageAvailable{
+				"": {File: &protobufs.DownloadableFile{ContentHash: hash, Signature: signature}},
+			},
+		})
+	}
+
+	offer(nil)
+	err = p.UpdateContent(context.Background(), "", bytes.NewReader([]byte("new")), hash)
+	assert.ErrorContains(t, err, "invalid signature")
+
+	offer(ed25519.Sign(privateKey, sha256Sum([]byte("other"))))
+	err = p.UpdateContent(context.Background(), "", bytes.NewReader([]byte("new")), hash)
+	assert.ErrorContains(t, err, "invalid signature")
+
+	content, err := os.ReadFile(executable)
+	require.NoError(t, err)
+	assert.Equal(t, "old", string(content))
+
+	offer(ed25519.Sign(privateKey, hash))
+	require.NoError(t, p.UpdateContent(context.Background(), "", bytes.NewReader([]byte("new")), hash))
+	content, err = os.ReadFile(executable)
+	require.NoError(t, err)
+	assert.Equal(t, "new", string(content))
+}
+
+func TestSupervisorRequiresPackagesPublicKey(t *testing.T) {
+	publicKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		packages      *config.Packages
+		expectedError string
+	}{
+		{
+			name:          "no packages config",
+			expectedError: "accepts_packages requires packages::public_key_file",
+		},
+		{
+			name:          "no public key",
+			packages:      &config.Packages{},
+			expectedError: "accepts_packages requires packages::public_key_file",
+		},
+		{
+			name:     "public key",
+			packages: &config.Packages{PublicKeyFile: writePublicKey(t, publicKey)},
+		},
+		{
+			name:     "insecure skip signature",
+			packages: &config.Packages{InsecureSkipSignature: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Supervisor{
+				logger: zap.NewNop(),
+				config: config.Supervisor{
+					Agent:    &config.Agent{Executable: filepath.Join(t.TempDir(), "otelcol")},
+					Packages: tt.packages,
+				},
+			}
+			p, err := s.createAgentPackages()
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.packages.PublicKeyFile != "", p.publicKey != nil)
+		})
+	}
+}
+
+func TestAgentPackagesState(t *testing.T) {
+	p, executable := newTestAgentPackages(t, "", func(context.Context) error { return nil })
+
+	assert.ErrorContains(t, p.CreatePackage("addon", protobufs.PackageType_PackageType_Addon), "only top-level packages are supported")
+	require.NoError(t, p.CreatePackage("otelcol", protobufs.PackageType_PackageType_TopLevel))
+	assert.ErrorContains(t, p.CreatePackage("otelcol-contrib", protobufs.PackageType_PackageType_TopLevel), "is already installed")
+
+	installed := types.PackageState{
+		Exists:  true,
+		Type:    protobufs.PackageType_PackageType_TopLevel,
+		Hash:    []byte("otelcol-1.2.3"),
+		Version: "1.2.3",
+	}
+	require.NoError(t, p.SetPackageState("otelcol", installed))
+	require.NoError(t, p.SetAllPackagesHash([]byte("all")))
+	statuses := &protobufs.PackageStatuses{
+		ServerProvidedAllPackagesHash: []byte("all"),
+		Packages: map[string]*protobufs.PackageStatus{
+			"otelcol": {Name: "otelcol", Status: protobufs.PackageStatusEnum_PackageStatusEnum_Installed},
+		},
+	}
+	require.NoError(t, p.SetLastReportedStatuses(statuses))
+
+	// The state is restored when the Supervisor restarts.
+	reloaded, err := newAgentPackages(zap.NewNop(), p.storageDir, executable, "", p.restartAgent)
+	require.NoError(t, err)
+	names, err := reloaded.Packages()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"otelcol"}, names)
+	state, err := reloaded.PackageState("otelcol")
+	require.NoError(t, err)
+	assert.Equal(t, installed, state)
+	hash, err := reloaded.AllPackagesHash()
+	require.NoError(t, err)
+	assert.Equal(t, []byte("all"), hash)
+	lastReported, err := reloaded.LastReportedStatuses()
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(statuses, lastReported))
+
+	require.NoError(t, reloaded.DeletePackage("otelcol"))
+	names, err = reloaded.Packages()
+	require.NoError(t, err)
+	assert.Empty(t, names)
+	assert.FileExists(t, executable)
+}
+
+// packagesServer is a local OpAMP server offering a collector package to the first agent
+// that connects, and recording the package statuses reported by the agent.
+type packagesServer struct {
+	endpoint string
+	offered  atomic.Bool
+
+	mu       sync.Mutex
+	statuses *protobufs.PackageStatuses
+}
+
+func newPackagesServer(t *testing.T, available *protobufs.PackagesAvailable) *packagesServer {
+	srv := &packagesServer{}
+	s := server.New(newLoggerFromZap(zap.NewNop()))
+	handler, _, err := s.Attach(server.Settings{
+		Callbacks: server.CallbacksStruct{
+			OnConnectingFunc: func(_ *http.Request) serverTypes.ConnectionResponse {
+				return serverTypes.ConnectionResponse{
+					Accept: true,
+					ConnectionCallbacks: server.ConnectionCallbacksStruct{
+						OnMessageFunc: func(_ context.Context, _ serverTypes.Connection, msg *protobufs.AgentToServer) *protobufs.ServerToAgent {
+							if msg.PackageStatuses != nil {
+								srv.mu.Lock()
+								srv.statuses = msg.PackageStatuses
+								srv.mu.Unlock()
+							}
+							resp := &protobufs.ServerToAgent{InstanceUid: msg.InstanceUid}
+							if srv.offered.CompareAndSwap(false, true) {
+								resp.PackagesAvailable = available
+							}
+							return resp
+						},
+					},
+				}
+			},
+		},
+	})
+	require.NoError(t, err)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/opamp", handler)
+	httpSrv := httptest.NewServer(mux)
+	t.Cleanup(func() {
+		assert.NoError(t, s.Stop(context.Background()))
+		httpSrv.Close()
+	})
+	srv.endpoint = "ws://" + strings.TrimPrefix(httpSrv.URL, "http://") + "/v1/opamp"
+	return srv
+}
+
+func (s *packagesServer) packageStatus(name string) *protobufs.PackageStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.statuses.GetPackages()[name]
+}
+
+func TestSupervisorAcceptsPackages(t *testing.T) {
+	tests := []struct {
+		name            string
+		healthy         bool
+		expectedStatus  protobufs.PackageStatusEnum
+		expectedContent string
+		expectedError   string
+	}{
+		{
+			name:            "healthy update",
+			healthy:         true,
+			expectedStatus:  protobufs.PackageStatusEnum_PackageStatusEnum_Installed,
+			expectedContent: "new",
+		},
+		{
+			name:            "reverted update",
+			expectedStatus:  protobufs.PackageStatusEnum_PackageStatusEnum_InstallFailed,
+			expectedContent: "old",
+			expectedError:   "the update was reverted",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fileSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte("new"))
+			}))
+			t.Cleanup(fileSrv.Close)
+
+			srv := newPackagesServer(t, &protobufs.PackagesAvailable{
+				AllPackagesHash: []byte("all"),
+				Packages: map[string]*protobufs.PackageAvailable{
+					"otelcol": {
+						Type:    protobufs.PackageType_PackageType_TopLevel,
+						Version: "1.2.3",
+						Hash:    []byte("otelcol-1.2.3"),
+						File: &protobufs.DownloadableFile{
+							DownloadUrl: fileSrv.URL,
+							ContentHash: sha256Sum([]byte("new")),
+						},
+					},
+				},
+			})
+
+			acceptsPackages := true
+			s := &Supervisor{
+				logger: zap.NewNop(),
+				config: config.Supervisor{
+					Server: &config.OpAMPServer{
+						Endpoint:   srv.endpoint,
+						TLSSetting: configtls.ClientConfig{Insecure: true},
+					},
+					Capabilities: &config.Capabilities{AcceptsPackages: &acceptsPackages},
+				},
+				agentDescription: &protobufs.AgentDescription{
+					IdentifyingAttributes: []*protobufs.KeyValue{{
+						Key:   "service.name",
+						Value: &protobufs.AnyValue{Value: &protobufs.AnyValue_StringValue{StringValue: "otelcol"}},
+					}},
+				},
+				effectiveConfig:        &atomic.Value{},
+				connectedToOpAMPServer: make(chan struct{}),
+			}
+			var executable string
+			s.packages, executable = newTestAgentPackages(t, "", func(context.Context) error {
+				if !tt.healthy {
+					content, err := os.ReadFile(executable)
+					if err == nil && string(content) == "new" {
+						return errors.New("agent is not healthy")
+					}
+				}
+				return nil
+			})
+
+			require.NoError(t, s.startOpAMP())
+			t.Cleanup(func() {
+				assert.NoError(t, s.stopOpAMP())
+			})
+			require.NoError(t, s.waitForOpAMPConnection())
+
+			var status *protobufs.PackageStatus
+			require.Eventually(t, func() bool {
+				status = srv.packageStatus("otelcol")
+				return status != nil && status.Status == tt.expectedStatus
+			}, 10*time.Second, 10*time.Millisecond)
+			assert.Equal(t, "1.2.3", status.ServerOfferedVersion)
+			assert.Contains(t, status.ErrorMessage, tt.expectedError)
+
+			content, err := os.ReadFile(executable)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedContent, string(content))
+		})
+	}
+}
@@ -13,9 +13,10 @@ import (
 )
 
 type flattenedSettings struct {
-	onMessageFunc    func(conn serverTypes.Connection, message *protobufs.AgentToServer)
-	onConnectingFunc func(request *http.Request)
-	endpoint         string
+	onMessageFunc         func(conn serverTypes.Connection, message *protobufs.AgentToServer)
+	onConnectingFunc      func(request *http.Request)
+	onConnectionCloseFunc func(conn serverTypes.Connection)
+	endpoint              string
 }
 
 func newServerSettings(fs flattenedSettings) server.StartSettings {
@@ -36,6 +37,11 @@ func newServerSettings(fs flattenedSettings) server.StartSettings {
 
 								return &protobufs.ServerToAgent{}
 							},
+							OnConnectionCloseFunc: func(conn serverTypes.Connection) {
+								if fs.onConnectionCloseFunc != nil {
+									fs.onConnectionCloseFunc(conn)
+								}
+							},
 						},
 					}
 				},
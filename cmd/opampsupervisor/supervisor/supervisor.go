@@ -56,6 +56,10 @@ var (
 	lastRecvOwnMetricsConfigFile = "last_recv_own_metrics_config.dat"
 )
 
+// defaultPackageHealthCheckTimeout is how long the Collector has to become healthy after
+// a package update before the update is reverted, if not configured.
+const defaultPackageHealthCheckTimeout = 30 * time.Second
+
 // Supervisor implements supervising of OpenTelemetry Collector and uses OpAMPClient
 // to work with an OpAMP Server.
 type Supervisor struct {
@@ -107,6 +111,14 @@ type Supervisor struct {
 	// The OpAMP client to connect to the OpAMP Server.
 	opampClient client.OpAMPClient
 
+	// Local state of the packages offered by the OpAMP Server, nil unless
+	// the AcceptsPackages capability is enabled.
+	packages *agentPackages
+
+	// Forwards the custom messages between the OpAMP server and the Collector, nil
+	// unless the forwards_custom_messages capability is enabled.
+	customMessages *customMessages
+
 	shuttingDown bool
 	supervisorWG sync.WaitGroup
 
@@ -154,11 +166,36 @@ func NewSupervisor(logger *zap.Logger, configFile string) (*Supervisor, error) {
 
 	s.agentHealthCheckEndpoint = fmt.Sprintf("localhost:%d", healthCheckPort)
 
+	if s.forwardsCustomMessages() {
+		agentOpAMPPort, portErr := s.findRandomPort()
+		if portErr != nil {
+			return nil, fmt.Errorf("could not find port for the Collector's OpAMP connection: %w", portErr)
+		}
+		s.customMessages = newCustomMessages(s.logger, fmt.Sprintf("localhost:%d", agentOpAMPPort))
+	}
+
 	logger.Debug("Supervisor starting",
 		zap.String("id", s.instanceID.String()))
 
 	s.loadAgentEffectiveConfig()
 
+	// The commander is created before connecting to the OpAMP server,
+	// since installing offered packages restarts the agent.
+	s.commander, err = commander.NewCommander(
+		s.logger,
+		s.config.Agent,
+		"--config", s.effectiveConfigFilePath,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.acceptsPackages() {
+		if s.packages, err = s.createAgentPackages(); err != nil {
+			return nil, err
+		}
+	}
+
 	if err = s.startOpAMP(); err != nil {
 		return nil, fmt.Errorf("cannot start OpAMP client: %w", err)
 	}
@@ -167,13 +204,10 @@ func NewSupervisor(logger *zap.Logger, configFile string) (*Supervisor, error) {
 		return nil, fmt.Errorf("failed to connect to the OpAMP server: %w", connErr)
 	}
 
-	s.commander, err = commander.NewCommander(
-		s.logger,
-		s.config.Agent,
-		"--config", s.effectiveConfigFilePath,
-	)
-	if err != nil {
-		return nil, err
+	if s.customMessages != nil {
+		if err = s.customMessages.start(s.opampClient); err != nil {
+			return nil, fmt.Errorf("cannot start the OpAMP server for the Collector: %w", err)
+		}
 	}
 
 	s.startHealthCheckTicker()
@@ -358,10 +392,46 @@ func (s *Supervisor) Capabilities() protobufs.AgentCapabilities {
 		if c.AcceptsOpAMPConnectionSettings != nil && *c.AcceptsOpAMPConnectionSettings {
 			supportedCapabilities |= protobufs.AgentCapabilities_AgentCapabilities_AcceptsOpAMPConnectionSettings
 		}
+
+		// The OpAMP client requires package statuses to be reported when packages are accepted.
+		if c.AcceptsPackages != nil && *c.AcceptsPackages {
+			supportedCapabilities |= protobufs.AgentCapabilities_AgentCapabilities_AcceptsPackages
+			supportedCapabilities |= protobufs.AgentCapabilities_AgentCapabilities_ReportsPackageStatuses
+		}
 	}
 	return supportedCapabilities
 }
 
+func (s *Supervisor) acceptsPackages() bool {
+	return s.config.Capabilities != nil && s.config.Capabilities.AcceptsPackages != nil &&
+		*s.config.Capabilities.AcceptsPackages
+}
+
+func (s *Supervisor) forwardsCustomMessages() bool {
+	return s.config.Capabilities != nil && s.config.Capabilities.ForwardsCustomMessages != nil &&
+		*s.config.Capabilities.ForwardsCustomMessages
+}
+
+func (s *Supervisor) createAgentPackages() (*agentPackages, error) {
+	var storageDir, publicKeyFile string
+	var insecureSkipSignature bool
+	if s.config.Storage != nil {
+		storageDir = s.config.Storage.Directory
+	}
+	if s.config.Packages != nil {
+		publicKeyFile = s.config.Packages.PublicKeyFile
+		insecureSkipSignature = s.config.Packages.InsecureSkipSignature
+	}
+	if publicKeyFile == "" {
+		if !insecureSkipSignature {
+			return nil, errors.New("accepts_packages requires packages::public_key_file to verify the signature of the packages, " +
+				"or packages::insecure_skip_signature to install them without verification")
+		}
+		s.logger.Warn("The signature of the packages is not verified, any package offered by the OpAMP server will be executed")
+	}
+	return newAgentPackages(s.logger, storageDir, s.config.Agent.Executable, publicKeyFile, s.restartUpdatedAgent)
+}
+
 func (s *Supervisor) startOpAMP() error {
 	s.opampClient = client.NewWebSocket(newLoggerFromZap(s.logger))
 
@@ -409,6 +479,9 @@ func (s *Supervisor) startOpAMP() error {
 		},
 		Capabilities: s.Capabilities(),
 	}
+	if s.packages != nil {
+		settings.PackagesStateProvider = s.packages
+	}
 	err = s.opampClient.SetAgentDescription(s.agentDescription)
 	if err != nil {
 		return err
@@ -538,6 +611,10 @@ func (s *Supervisor) composeExtraLocalConfig() []byte {
 	tplVars := map[string]any{
 		"Healthcheck":        s.agentHealthCheckEndpoint,
 		"ResourceAttributes": resourceAttrs,
+		"InstanceUid":        s.instanceID.String(),
+	}
+	if s.customMessages != nil {
+		tplVars["SupervisorOpAMPEndpoint"] = s.customMessages.endpoint
 	}
 	err := s.extraConfigTemplate.Execute(
 		&cfg,
@@ -770,6 +847,39 @@ func (s *Supervisor) handleRestartCommand() error {
 	return err
 }
 
+// restartUpdatedAgent restarts the agent after its executable was updated, and waits
+// for it to become healthy.
+func (s *Supervisor) restartUpdatedAgent(ctx context.Context) error {
+	s.agentRestarting.Store(true)
+	defer s.agentRestarting.Store(false)
+	s.logger.Debug("Restarting the updated agent")
+	if err := s.commander.Restart(context.Background()); err != nil {
+		return fmt.Errorf("cannot restart the agent: %w", err)
+	}
+
+	timeout := defaultPackageHealthCheckTimeout
+	if s.config.Packages != nil && s.config.Packages.HealthCheckTimeout > 0 {
+		timeout = s.config.Packages.HealthCheckTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	checker := healthchecker.NewHTTPHealthChecker(fmt.Sprintf("http://%s", s.agentHealthCheckEndpoint))
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		err := checker.Check(ctx)
+		if err == nil {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("agent did not become healthy within %s: %w", timeout, err)
+		case <-ticker.C:
+		}
+	}
+}
+
 func (s *Supervisor) startAgent() {
 	err := s.commander.Start(context.Background())
 	if err != nil {
@@ -959,6 +1069,14 @@ func (s *Supervisor) Shutdown() {
 		}
 	}
 
+	if s.customMessages != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		if err := s.customMessages.stop(ctx); err != nil {
+			s.logger.Error("Could not stop the OpAMP server for the Collector", zap.Error(err))
+		}
+		cancel()
+	}
+
 	if s.healthCheckTicker != nil {
 		s.healthCheckTicker.Stop()
 	}
@@ -1048,6 +1166,25 @@ func (s *Supervisor) onMessage(ctx context.Context, msg *types.MessageData) {
 		configChanged = true
 	}
 
+	if s.customMessages != nil {
+		if msg.CustomCapabilities != nil {
+			s.customMessages.onServerCapabilities(msg.CustomCapabilities)
+		}
+		if msg.CustomMessage != nil {
+			s.customMessages.onServerMessage(msg.CustomMessage)
+		}
+	}
+
+	if msg.PackageSyncer != nil && s.packages != nil {
+		s.logger.Debug("Received packages offer from server", zap.String("hash", fmt.Sprintf("%x", msg.PackagesAvailable.GetAllPackagesHash())))
+		s.packages.setAvailable(msg.PackagesAvailable)
+		// The packages are synced in the background, and their statuses are reported
+		// to the server as they are installed.
+		if err := msg.PackageSyncer.Sync(ctx); err != nil {
+			s.logger.Error("Could not sync the packages offered by the server", zap.Error(err))
+		}
+	}
+
 	if configChanged {
 		err := s.opampClient.UpdateEffectiveConfig(ctx)
 		if err != nil {
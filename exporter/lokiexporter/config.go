@@ -10,6 +10,8 @@ import (
 	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/config/configretry"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/wal"
 )
 
 // Config defines configuration for Loki exporter.
@@ -19,6 +21,9 @@ type Config struct {
 	configretry.BackOffConfig    `mapstructure:"retry_on_failure"`
 
 	DefaultLabelsEnabled map[string]bool `mapstructure:"default_labels_enabled"`
+
+	// WAL enables a write-ahead log, giving ordered delivery of logs which is replayed after a restart.
+	WAL *wal.Config `mapstructure:"wal"`
 }
 
 func (c *Config) Validate() error {
@@ -7,6 +7,7 @@ package lokiexporter // import "github.com/open-telemetry/opentelemetry-collecto
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"go.opentelemetry.io/collector/component"
@@ -17,6 +18,7 @@ import (
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/lokiexporter/internal/metadata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/wal"
 )
 
 // NewFactory creates a factory for the legacy Loki exporter.
@@ -56,16 +58,27 @@ func createLogsExporter(ctx context.Context, set exporter.CreateSettings, config
 		return nil, err
 	}
 
+	// When the write-ahead log is enabled, logs are persisted before they are acknowledged,
+	// and replayed in order to Loki until they are accepted or permanently rejected.
+	pusher := wal.NewPusher(exporterConfig.WAL, wal.Name(set.ID), set.Logger, wal.LogsCodec{}, exp.pushLogData)
+
 	return exporterhelper.NewLogsExporter(
 		ctx,
 		set,
 		config,
-		exp.pushLogData,
+		pusher.Push,
 		// explicitly disable since we rely on http.Client timeout logic.
 		exporterhelper.WithTimeout(exporterhelper.TimeoutSettings{Timeout: 0}),
 		exporterhelper.WithRetry(exporterConfig.BackOffConfig),
 		exporterhelper.WithQueue(exporterConfig.QueueSettings),
-		exporterhelper.WithStart(exp.start),
-		exporterhelper.WithShutdown(exp.stop),
+		exporterhelper.WithStart(func(ctx context.Context, host component.Host) error {
+			if err := exp.start(ctx, host); err != nil {
+				return err
+			}
+			return pusher.Start(ctx)
+		}),
+		exporterhelper.WithShutdown(func(ctx context.Context) error {
+			return errors.Join(pusher.Shutdown(ctx), exp.stop(ctx))
+		}),
 	)
 }
@@ -6,12 +6,25 @@ package lokiexporter
 import (
 	"context"
 	"fmt"
+	"io"
 	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/snappy"
+	"github.com/grafana/loki/pkg/push"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/pdata/plog"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/wal"
 )
 
 const (
@@ -69,3 +82,67 @@ func TestExporter_stopAlwaysReturnsNil(t *testing.T) {
 	require.NotNil(t, exp)
 	require.NoError(t, exp.stop(context.Background()))
 }
+
+func TestExporter_WALDeliversInOrderAfterFailure(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		lines    []string
+		failures = 1
+	)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if failures > 0 {
+			failures--
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		encPayload, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		decPayload, err := snappy.Decode(nil, encPayload)
+		assert.NoError(t, err)
+		pushReq := &push.PushRequest{}
+		assert.NoError(t, proto.Unmarshal(decPayload, pushReq))
+		for _, stream := range pushReq.Streams {
+			for _, entry := range stream.Entries {
+				lines = append(lines, entry.Line)
+			}
+		}
+	}))
+	defer ts.Close()
+
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.Endpoint = ts.URL
+	cfg.QueueSettings.Enabled = false
+	cfg.BackOffConfig.Enabled = false
+	cfg.WAL = &wal.Config{
+		Directory:         t.TempDir(),
+		BufferSize:        1,
+		TruncateFrequency: 10 * time.Millisecond,
+	}
+
+	exp, err := factory.CreateLogsExporter(context.Background(), exportertest.NewNopCreateSettings(), cfg)
+	require.NoError(t, err)
+	require.NoError(t, exp.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, exp.Shutdown(context.Background()))
+	})
+
+	for i := 0; i < 3; i++ {
+		ld := plog.NewLogs()
+		ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStr(strconv.Itoa(i))
+		// The first export fails, but the logs are already persisted.
+		require.NoError(t, exp.ConsumeLogs(context.Background(), ld))
+	}
+
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(lines) == 3
+	}, 10*time.Second, 10*time.Millisecond)
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{`{"body":"0"}`, `{"body":"1"}`, `{"body":"2"}`}, lines)
+}
@@ -7,6 +7,7 @@ require (
 	github.com/gogo/protobuf v1.3.2
 	github.com/golang/snappy v0.0.4
 	github.com/grafana/loki/pkg/push v0.0.0-20231127162423-bd505f8e2d37
+	github.com/open-telemetry/opentelemetry-collector-contrib/internal/wal v0.99.0
 	github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/loki v0.99.0
 	github.com/prometheus/common v0.53.0
 	github.com/stretchr/testify v1.9.0
@@ -59,6 +60,11 @@ require (
 	github.com/prometheus/procfs v0.12.0 // indirect
 	github.com/prometheus/prometheus v0.51.2-0.20240405174432-b4a973753c6e // indirect
 	github.com/rs/cors v1.10.1 // indirect
+	github.com/tidwall/gjson v1.10.2 // indirect
+	github.com/tidwall/match v1.1.1 // indirect
+	github.com/tidwall/pretty v1.2.0 // indirect
+	github.com/tidwall/tinylru v1.1.0 // indirect
+	github.com/tidwall/wal v1.1.7 // indirect
 	go.opentelemetry.io/collector/config/configauth v0.99.0 // indirect
 	go.opentelemetry.io/collector/config/configcompression v1.6.0 // indirect
 	go.opentelemetry.io/collector/config/configtelemetry v0.99.0 // indirect
@@ -103,3 +109,5 @@ retract (
 )
 
 replace github.com/open-telemetry/opentelemetry-collector-contrib/pkg/golden => ../../pkg/golden
+
+replace github.com/open-telemetry/opentelemetry-collector-contrib/internal/wal => ../../internal/wal
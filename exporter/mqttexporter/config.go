@@ -0,0 +1,51 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package mqttexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/mqttexporter"
+
+import (
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configretry"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+	"go.uber.org/multierr"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/mqtt"
+)
+
+// Config defines configuration for the MQTT exporter.
+type Config struct {
+	exporterhelper.TimeoutSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct.
+	exporterhelper.QueueSettings   `mapstructure:"sending_queue"`
+	configretry.BackOffConfig      `mapstructure:"retry_on_failure"`
+
+	// ClientConfig configures the connection to the broker. The signal is
+	// appended to the client ID, as each signal has its own connection.
+	mqtt.ClientConfig `mapstructure:",squash"`
+
+	// Topic is the template of the topics to which the telemetry is
+	// published. The {signal} placeholder is replaced by logs, metrics or
+	// traces, and the {resource.<key>} placeholders by the values of the
+	// resource attributes.
+	Topic string `mapstructure:"topic"`
+	// QoS is the QoS of the published messages.
+	QoS byte `mapstructure:"qos"`
+	// Retain makes the broker retain the last message published to each
+	// topic, which is sent to the new subscribers.
+	Retain bool `mapstructure:"retain"`
+	// EncodingExtensionID is the encoding extension which marshals the
+	// telemetry, which is published as OTLP protobuf messages by default.
+	EncodingExtensionID *component.ID `mapstructure:"encoding_extension"`
+}
+
+var _ component.Config = (*Config)(nil)
+
+func (cfg *Config) Validate() error {
+	var errs error
+	if err := mqtt.ValidateQoS(cfg.QoS); err != nil {
+		errs = multierr.Append(errs, err)
+	}
+	if _, err := parseTopicTemplate(cfg.Topic); err != nil {
+		errs = multierr.Append(errs, err)
+	}
+	return errs
+}
@@ -0,0 +1,95 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package mqttexporter
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configretry"
+	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/confmap/confmaptest"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+	"go.uber.org/multierr"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/mqttexporter/internal/metadata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/mqtt"
+)
+
+func TestLoadConfig(t *testing.T) {
+	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config.yaml"))
+	require.NoError(t, err)
+
+	encodingID := component.MustNewID("otlp_encoding")
+	tests := []struct {
+		id          component.ID
+		expected    component.Config
+		expectedErr error
+	}{
+		{
+			id:       component.NewID(metadata.Type),
+			expected: createDefaultConfig(),
+		},
+		{
+			id: component.NewIDWithName(metadata.Type, "custom"),
+			expected: &Config{
+				TimeoutSettings: exporterhelper.TimeoutSettings{Timeout: 10 * time.Second},
+				QueueSettings: func() exporterhelper.QueueSettings {
+					queue := exporterhelper.NewDefaultQueueSettings()
+					queue.Enabled = false
+					return queue
+				}(),
+				BackOffConfig: func() configretry.BackOffConfig {
+					retry := configretry.NewDefaultBackOffConfig()
+					retry.Enabled = false
+					return retry
+				}(),
+				ClientConfig: mqtt.ClientConfig{
+					Endpoint: "mqtts://broker.example.com:8883",
+					ClientID: "gateway",
+					Username: "gateway",
+					Password: "secret",
+					TLSSetting: &configtls.ClientConfig{
+						Config: configtls.Config{CAFile: "ca.pem"},
+					},
+					ConnectTimeout: 30 * time.Second,
+					KeepAlive:      30 * time.Second,
+				},
+				Topic:               "gateways/{resource.host.name}/{signal}",
+				QoS:                 2,
+				Retain:              true,
+				EncodingExtensionID: &encodingID,
+			},
+		},
+		{
+			id: component.NewIDWithName(metadata.Type, "invalid"),
+			expectedErr: multierr.Combine(
+				errors.New("invalid qos 3: must be 0, 1 or 2"),
+				errors.New(`invalid topic "gateways/#/{resource}": unknown placeholder "resource"`),
+				errors.New("endpoint must be specified"),
+			),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id.String(), func(t *testing.T) {
+			cfg := createDefaultConfig()
+			sub, err := cm.Sub(tt.id.String())
+			require.NoError(t, err)
+			require.NoError(t, sub.Unmarshal(cfg))
+
+			if tt.expectedErr != nil {
+				assert.EqualError(t, component.ValidateConfig(cfg), tt.expectedErr.Error())
+				return
+			}
+			assert.NoError(t, component.ValidateConfig(cfg))
+			assert.Equal(t, tt.expected, cfg)
+		})
+	}
+}
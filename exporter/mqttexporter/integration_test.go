@@ -0,0 +1,110 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build integration
+
+package mqttexporter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/mqtt/mqtttest"
+)
+
+func TestIntegration(t *testing.T) {
+	broker := mqtttest.NewBroker(t)
+	messages := broker.Subscribe(t, "gateways/#")
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = broker.Endpoint()
+	cfg.Topic = "gateways/{resource.host.name}/{signal}"
+	cfg.QoS = 1
+
+	exp := startExporter(t, cfg, component.DataTypeLogs)
+	require.NoError(t, exp.publishLogs(context.Background(), newLogs("gw-1", "gw-2", "gw-1")))
+
+	// The messages of a topic are delivered in order, but not the messages of
+	// distinct topics.
+	received := map[string]mqtttest.Message{}
+	for i := 0; i < 2; i++ {
+		msg := receive(t, messages)
+		received[msg.Topic] = msg
+	}
+	require.Contains(t, received, "gateways/gw-1/logs")
+	ld, err := (&plog.ProtoUnmarshaler{}).UnmarshalLogs(received["gateways/gw-1/logs"].Payload)
+	require.NoError(t, err)
+	assert.Equal(t, newLogs("gw-1", "gw-1"), ld)
+	require.Contains(t, received, "gateways/gw-2/logs")
+	ld, err = (&plog.ProtoUnmarshaler{}).UnmarshalLogs(received["gateways/gw-2/logs"].Payload)
+	require.NoError(t, err)
+	assert.Equal(t, newLogs("gw-2"), ld)
+}
+
+func TestIntegrationRetain(t *testing.T) {
+	broker := mqtttest.NewBroker(t)
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = broker.Endpoint()
+	cfg.QoS = 2
+	cfg.Retain = true
+
+	exp := startExporter(t, cfg, component.DataTypeTraces)
+	require.NoError(t, exp.publishTraces(context.Background(), newTraces("gw-1")))
+
+	// The broker delivers the retained message to the clients subscribing
+	// after it was published.
+	msg := receive(t, broker.Subscribe(t, "otlp/traces"))
+	assert.True(t, msg.Retain)
+	td, err := (&ptrace.ProtoUnmarshaler{}).UnmarshalTraces(msg.Payload)
+	require.NoError(t, err)
+	assert.Equal(t, newTraces("gw-1"), td)
+}
+
+func TestIntegrationTLSAndCredentials(t *testing.T) {
+	broker := mqtttest.NewBroker(t, mqtttest.WithTLS(), mqtttest.WithCredentials("gateway", "secret"))
+	messages := broker.Subscribe(t, "otlp/traces")
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = broker.Endpoint()
+	cfg.ClientID = "gateway"
+	cfg.Username = "gateway"
+	cfg.Password = "secret"
+	cfg.TLSSetting = &configtls.ClientConfig{Config: configtls.Config{CAFile: broker.CAFile()}}
+
+	exp := startExporter(t, cfg, component.DataTypeTraces)
+	require.NoError(t, exp.publishTraces(context.Background(), newTraces("gw-1")))
+	assert.Equal(t, "otlp/traces", receive(t, messages).Topic)
+}
+
+// startExporter starts an exporter, and waits for it to be connected.
+func startExporter(t *testing.T, cfg *Config, signal component.DataType) *mqttExporter {
+	exp, err := newMQTTExporter(cfg, exportertest.NewNopCreateSettings(), signal)
+	require.NoError(t, err)
+	require.NoError(t, exp.start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, exp.shutdown(context.Background()))
+	})
+	require.Eventually(t, exp.client.IsConnectionOpen, 30*time.Second, 10*time.Millisecond)
+	return exp
+}
+
+func receive(t *testing.T, messages <-chan mqtttest.Message) mqtttest.Message {
+	select {
+	case msg := <-messages:
+		return msg
+	case <-time.After(10 * time.Second):
+		t.Fatal("no message published")
+		return mqtttest.Message{}
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package mqttexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/mqttexporter"
+
+import (
+	"fmt"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/encoding"
+)
+
+// marshaler marshals the telemetry of the signal of an exporter.
+type marshaler struct {
+	logsMarshaler    plog.Marshaler
+	metricsMarshaler pmetric.Marshaler
+	tracesMarshaler  ptrace.Marshaler
+}
+
+func newMarshaler(signal component.DataType, id *component.ID, host component.Host) (*marshaler, error) {
+	m := &marshaler{
+		logsMarshaler:    &plog.ProtoMarshaler{},
+		metricsMarshaler: &pmetric.ProtoMarshaler{},
+		tracesMarshaler:  &ptrace.ProtoMarshaler{},
+	}
+	if id == nil {
+		return m, nil
+	}
+
+	ext, ok := host.GetExtensions()[*id]
+	if !ok {
+		return nil, fmt.Errorf("unknown encoding %q", id)
+	}
+	switch signal {
+	case component.DataTypeLogs:
+		m.logsMarshaler, ok = ext.(encoding.LogsMarshalerExtension)
+	case component.DataTypeMetrics:
+		m.metricsMarshaler, ok = ext.(encoding.MetricsMarshalerExtension)
+	case component.DataTypeTraces:
+		m.tracesMarshaler, ok = ext.(encoding.TracesMarshalerExtension)
+	}
+	if !ok {
+		return nil, fmt.Errorf("extension %q is not a %s marshaler", id, signal)
+	}
+	return m, nil
+}
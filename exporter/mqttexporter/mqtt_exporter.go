@@ -0,0 +1,209 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package mqttexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/mqttexporter"
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/mqtt"
+)
+
+// The time given to the in-flight messages when disconnecting.
+const disconnectQuiesce = 250 * time.Millisecond
+
+var errNotConnected = errors.New("not connected to the broker")
+
+// mqttExporter publishes the telemetry of a signal to the broker, over its
+// own connection.
+type mqttExporter struct {
+	cfg      *Config
+	settings exporter.CreateSettings
+	signal   component.DataType
+	topic    topicTemplate
+	*marshaler
+
+	client paho.Client
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func newMQTTExporter(cfg *Config, set exporter.CreateSettings, signal component.DataType) (*mqttExporter, error) {
+	topic, err := parseTopicTemplate(cfg.Topic)
+	if err != nil {
+		return nil, err
+	}
+	return &mqttExporter{
+		cfg:      cfg,
+		settings: set,
+		signal:   signal,
+		topic:    topic,
+	}, nil
+}
+
+func (e *mqttExporter) start(ctx context.Context, host component.Host) error {
+	m, err := newMarshaler(e.signal, e.cfg.EncodingExtensionID, host)
+	if err != nil {
+		return err
+	}
+	e.marshaler = m
+
+	opts, err := e.cfg.NewClientOptions(ctx)
+	if err != nil {
+		return err
+	}
+	// The broker disconnects the clients sharing a client ID.
+	if e.cfg.ClientID != "" {
+		opts.SetClientID(e.cfg.ClientID + "-" + e.signal.String())
+	}
+	opts.SetConnectionLostHandler(func(_ paho.Client, err error) {
+		e.settings.Logger.Warn("Lost the connection to the broker", zap.Error(err))
+	})
+	e.client = paho.NewClient(opts)
+
+	// The context of start must not be used beyond start.
+	ctx, e.cancel = context.WithCancel(context.Background())
+	e.wg.Add(1)
+	go func() {
+		defer e.wg.Done()
+		mqtt.Connect(ctx, e.client, e.settings.Logger)
+	}()
+	return nil
+}
+
+func (e *mqttExporter) shutdown(context.Context) error {
+	if e.client == nil {
+		return nil
+	}
+	e.cancel()
+	e.wg.Wait()
+	e.client.Disconnect(uint(disconnectQuiesce / time.Millisecond))
+	return nil
+}
+
+func (e *mqttExporter) publishLogs(ctx context.Context, ld plog.Logs) error {
+	if !e.topic.perResource {
+		return e.marshalAndPublish(ctx, e.topic.execute(e.signal.String(), pcommon.NewResource()), func() ([]byte, error) {
+			return e.logsMarshaler.MarshalLogs(ld)
+		})
+	}
+
+	// The resources are published to the topics they are assigned.
+	var topics []string
+	byTopic := map[string]plog.Logs{}
+	for i := 0; i < ld.ResourceLogs().Len(); i++ {
+		rl := ld.ResourceLogs().At(i)
+		topic := e.topic.execute(e.signal.String(), rl.Resource())
+		logs, ok := byTopic[topic]
+		if !ok {
+			logs = plog.NewLogs()
+			byTopic[topic] = logs
+			topics = append(topics, topic)
+		}
+		rl.CopyTo(logs.ResourceLogs().AppendEmpty())
+	}
+	var errs error
+	for _, topic := range topics {
+		errs = multierr.Append(errs, e.marshalAndPublish(ctx, topic, func() ([]byte, error) {
+			return e.logsMarshaler.MarshalLogs(byTopic[topic])
+		}))
+	}
+	return errs
+}
+
+func (e *mqttExporter) publishMetrics(ctx context.Context, md pmetric.Metrics) error {
+	if !e.topic.perResource {
+		return e.marshalAndPublish(ctx, e.topic.execute(e.signal.String(), pcommon.NewResource()), func() ([]byte, error) {
+			return e.metricsMarshaler.MarshalMetrics(md)
+		})
+	}
+
+	var topics []string
+	byTopic := map[string]pmetric.Metrics{}
+	for i := 0; i < md.ResourceMetrics().Len(); i++ {
+		rm := md.ResourceMetrics().At(i)
+		topic := e.topic.execute(e.signal.String(), rm.Resource())
+		metrics, ok := byTopic[topic]
+		if !ok {
+			metrics = pmetric.NewMetrics()
+			byTopic[topic] = metrics
+			topics = append(topics, topic)
+		}
+		rm.CopyTo(metrics.ResourceMetrics().AppendEmpty())
+	}
+	var errs error
+	for _, topic := range topics {
+		errs = multierr.Append(errs, e.marshalAndPublish(ctx, topic, func() ([]byte, error) {
+			return e.metricsMarshaler.MarshalMetrics(byTopic[topic])
+		}))
+	}
+	return errs
+}
+
+func (e *mqttExporter) publishTraces(ctx context.Context, td ptrace.Traces) error {
+	if !e.topic.perResource {
+		return e.marshalAndPublish(ctx, e.topic.execute(e.signal.String(), pcommon.NewResource()), func() ([]byte, error) {
+			return e.tracesMarshaler.MarshalTraces(td)
+		})
+	}
+
+	var topics []string
+	byTopic := map[string]ptrace.Traces{}
+	for i := 0; i < td.ResourceSpans().Len(); i++ {
+		rs := td.ResourceSpans().At(i)
+		topic := e.topic.execute(e.signal.String(), rs.Resource())
+		traces, ok := byTopic[topic]
+		if !ok {
+			traces = ptrace.NewTraces()
+			byTopic[topic] = traces
+			topics = append(topics, topic)
+		}
+		rs.CopyTo(traces.ResourceSpans().AppendEmpty())
+	}
+	var errs error
+	for _, topic := range topics {
+		errs = multierr.Append(errs, e.marshalAndPublish(ctx, topic, func() ([]byte, error) {
+			return e.tracesMarshaler.MarshalTraces(byTopic[topic])
+		}))
+	}
+	return errs
+}
+
+func (e *mqttExporter) marshalAndPublish(ctx context.Context, topic string, marshal func() ([]byte, error)) error {
+	payload, err := marshal()
+	if err != nil {
+		return consumererror.NewPermanent(err)
+	}
+	return e.publish(ctx, topic, payload)
+}
+
+// publish publishes a message, and waits for the broker to acknowledge it
+// according to its QoS.
+func (e *mqttExporter) publish(ctx context.Context, topic string, payload []byte) error {
+	// The client stores the messages published while it is not connected,
+	// which are rather left to the sending queue.
+	if !e.client.IsConnectionOpen() {
+		return errNotConnected
+	}
+	token := e.client.Publish(topic, e.cfg.QoS, e.cfg.Retain, payload)
+	select {
+	case <-token.Done():
+		return token.Error()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
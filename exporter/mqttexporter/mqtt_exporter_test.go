@@ -0,0 +1,261 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package mqttexporter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+func TestPublish(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.QoS = 2
+	cfg.Retain = true
+
+	logs, client := newTestExporter(t, cfg, component.DataTypeLogs, componenttest.NewNopHost())
+	require.NoError(t, logs.publishLogs(context.Background(), newLogs("gw-1", "gw-2")))
+	metrics, _ := newTestExporter(t, cfg, component.DataTypeMetrics, componenttest.NewNopHost())
+	metrics.client = client
+	require.NoError(t, metrics.publishMetrics(context.Background(), newMetrics("gw-1")))
+	traces, _ := newTestExporter(t, cfg, component.DataTypeTraces, componenttest.NewNopHost())
+	traces.client = client
+	require.NoError(t, traces.publishTraces(context.Background(), newTraces("gw-1")))
+
+	require.Len(t, client.messages, 3)
+	msg := client.messages[0]
+	assert.Equal(t, "otlp/logs", msg.topic)
+	assert.Equal(t, byte(2), msg.qos)
+	assert.True(t, msg.retain)
+	ld, err := (&plog.ProtoUnmarshaler{}).UnmarshalLogs(msg.payload)
+	require.NoError(t, err)
+	assert.Equal(t, newLogs("gw-1", "gw-2"), ld)
+
+	msg = client.messages[1]
+	assert.Equal(t, "otlp/metrics", msg.topic)
+	md, err := (&pmetric.ProtoUnmarshaler{}).UnmarshalMetrics(msg.payload)
+	require.NoError(t, err)
+	assert.Equal(t, newMetrics("gw-1"), md)
+
+	msg = client.messages[2]
+	assert.Equal(t, "otlp/traces", msg.topic)
+	td, err := (&ptrace.ProtoUnmarshaler{}).UnmarshalTraces(msg.payload)
+	require.NoError(t, err)
+	assert.Equal(t, newTraces("gw-1"), td)
+}
+
+func TestPublishTopicPerResource(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Topic = "gateways/{resource.host.name}/{signal}"
+
+	exp, client := newTestExporter(t, cfg, component.DataTypeLogs, componenttest.NewNopHost())
+	require.NoError(t, exp.publishLogs(context.Background(), newLogs("gw-1", "gw-2", "gw-1")))
+
+	require.Len(t, client.messages, 2)
+	msg := client.messages[0]
+	assert.Equal(t, "gateways/gw-1/logs", msg.topic)
+	ld, err := (&plog.ProtoUnmarshaler{}).UnmarshalLogs(msg.payload)
+	require.NoError(t, err)
+	assert.Equal(t, newLogs("gw-1", "gw-1"), ld)
+
+	msg = client.messages[1]
+	assert.Equal(t, "gateways/gw-2/logs", msg.topic)
+	ld, err = (&plog.ProtoUnmarshaler{}).UnmarshalLogs(msg.payload)
+	require.NoError(t, err)
+	assert.Equal(t, newLogs("gw-2"), ld)
+}
+
+func TestPublishEncodingExtension(t *testing.T) {
+	encodingID := component.MustNewID("text_encoding")
+	cfg := createDefaultConfig().(*Config)
+	cfg.EncodingExtensionID = &encodingID
+	host := &testHost{Host: componenttest.NewNopHost(), extensions: map[component.ID]component.Component{
+		encodingID: &textEncoding{},
+	}}
+
+	exp, client := newTestExporter(t, cfg, component.DataTypeLogs, host)
+	require.NoError(t, exp.publishLogs(context.Background(), newLogs("gw-1")))
+	require.Len(t, client.messages, 1)
+	assert.Equal(t, "gw-1 booted", string(client.messages[0].payload))
+}
+
+func TestStartInvalidEncodingExtension(t *testing.T) {
+	encodingID := component.MustNewID("text_encoding")
+	unknownID := component.MustNewID("unknown")
+	host := &testHost{Host: componenttest.NewNopHost(), extensions: map[component.ID]component.Component{
+		encodingID: &textEncoding{},
+	}}
+
+	tests := []struct {
+		name        string
+		id          component.ID
+		signal      component.DataType
+		expectedErr string
+	}{
+		{name: "unknown", id: unknownID, signal: component.DataTypeLogs, expectedErr: `unknown encoding "unknown"`},
+		{name: "not_a_marshaler", id: encodingID, signal: component.DataTypeMetrics, expectedErr: `extension "text_encoding" is not a metrics marshaler`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.EncodingExtensionID = &tt.id
+			exp, err := newMQTTExporter(cfg, exportertest.NewNopCreateSettings(), tt.signal)
+			require.NoError(t, err)
+			assert.EqualError(t, exp.start(context.Background(), host), tt.expectedErr)
+			assert.NoError(t, exp.shutdown(context.Background()))
+		})
+	}
+}
+
+func TestPublishNotConnected(t *testing.T) {
+	exp, client := newTestExporter(t, createDefaultConfig().(*Config), component.DataTypeLogs, componenttest.NewNopHost())
+	client.disconnected = true
+
+	// The messages are left to the sending queue.
+	err := exp.publishLogs(context.Background(), newLogs("gw-1"))
+	assert.ErrorIs(t, err, errNotConnected)
+	assert.False(t, consumererror.IsPermanent(err))
+	assert.Empty(t, client.messages)
+}
+
+func TestShutdownWithoutStart(t *testing.T) {
+	exp, err := newMQTTExporter(createDefaultConfig().(*Config), exportertest.NewNopCreateSettings(), component.DataTypeLogs)
+	require.NoError(t, err)
+	assert.NoError(t, exp.shutdown(context.Background()))
+}
+
+// newTestExporter creates an exporter publishing the messages with a test
+// client, without starting it.
+func newTestExporter(t *testing.T, cfg *Config, signal component.DataType, host component.Host) (*mqttExporter, *testClient) {
+	exp, err := newMQTTExporter(cfg, exportertest.NewNopCreateSettings(), signal)
+	require.NoError(t, err)
+	exp.marshaler, err = newMarshaler(signal, cfg.EncodingExtensionID, host)
+	require.NoError(t, err)
+	client := &testClient{}
+	exp.client = client
+	return exp, client
+}
+
+type testMessage struct {
+	topic   string
+	qos     byte
+	retain  bool
+	payload []byte
+}
+
+// testClient is a client recording the messages published, which the broker
+// acknowledges at once.
+type testClient struct {
+	paho.Client
+	disconnected bool
+	messages     []testMessage
+}
+
+func (c *testClient) IsConnectionOpen() bool {
+	return !c.disconnected
+}
+
+func (c *testClient) Publish(topic string, qos byte, retained bool, payload any) paho.Token {
+	c.messages = append(c.messages, testMessage{topic: topic, qos: qos, retain: retained, payload: payload.([]byte)})
+	return &doneToken{}
+}
+
+type doneToken struct{}
+
+func (*doneToken) Wait() bool {
+	return true
+}
+
+func (*doneToken) WaitTimeout(time.Duration) bool {
+	return true
+}
+
+func (*doneToken) Done() <-chan struct{} {
+	done := make(chan struct{})
+	close(done)
+	return done
+}
+
+func (*doneToken) Error() error {
+	return nil
+}
+
+func newLogs(hosts ...string) plog.Logs {
+	logs := plog.NewLogs()
+	for _, host := range hosts {
+		rl := logs.ResourceLogs().AppendEmpty()
+		rl.Resource().Attributes().PutStr("host.name", host)
+		rl.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStr("booted")
+	}
+	return logs
+}
+
+func newMetrics(hosts ...string) pmetric.Metrics {
+	metrics := pmetric.NewMetrics()
+	for _, host := range hosts {
+		rm := metrics.ResourceMetrics().AppendEmpty()
+		rm.Resource().Attributes().PutStr("host.name", host)
+		m := rm.ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+		m.SetName("temperature")
+		m.SetEmptyGauge().DataPoints().AppendEmpty().SetDoubleValue(21.5)
+	}
+	return metrics
+}
+
+func newTraces(hosts ...string) ptrace.Traces {
+	traces := ptrace.NewTraces()
+	for _, host := range hosts {
+		rs := traces.ResourceSpans().AppendEmpty()
+		rs.Resource().Attributes().PutStr("host.name", host)
+		rs.ScopeSpans().AppendEmpty().Spans().AppendEmpty().SetName("calibrate")
+	}
+	return traces
+}
+
+type testHost struct {
+	component.Host
+	extensions map[component.ID]component.Component
+}
+
+func (h *testHost) GetExtensions() map[component.ID]component.Component {
+	return h.extensions
+}
+
+// textEncoding is an encoding extension marshaling the log records as lines
+// prefixed by the host name.
+type textEncoding struct{}
+
+func (*textEncoding) Start(context.Context, component.Host) error {
+	return nil
+}
+
+func (*textEncoding) Shutdown(context.Context) error {
+	return nil
+}
+
+func (*textEncoding) MarshalLogs(ld plog.Logs) ([]byte, error) {
+	var buf []byte
+	for i := 0; i < ld.ResourceLogs().Len(); i++ {
+		rl := ld.ResourceLogs().At(i)
+		host, _ := rl.Resource().Attributes().Get("host.name")
+		for j := 0; j < rl.ScopeLogs().Len(); j++ {
+			records := rl.ScopeLogs().At(j).LogRecords()
+			for k := 0; k < records.Len(); k++ {
+				buf = append(buf, host.Str()+" "+records.At(k).Body().AsString()...)
+			}
+		}
+	}
+	return buf, nil
+}
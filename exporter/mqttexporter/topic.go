@@ -0,0 +1,99 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package mqttexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/mqttexporter"
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+const resourcePrefix = "resource."
+
+var errEmptyTopic = errors.New("topic must not be empty")
+
+// topicTemplate is the template of the topics to which the telemetry is
+// published.
+type topicTemplate struct {
+	parts []templatePart
+	// perResource is true when the topics depend on the resources.
+	perResource bool
+}
+
+// templatePart is a literal part of a template, or a placeholder replaced
+// by the signal, or by a resource attribute when key is set.
+type templatePart struct {
+	literal     string
+	placeholder bool
+	key         string
+}
+
+// parseTopicTemplate parses a topic template, which may contain the
+// {signal} and {resource.<key>} placeholders.
+func parseTopicTemplate(template string) (topicTemplate, error) {
+	if template == "" {
+		return topicTemplate{}, errEmptyTopic
+	}
+
+	var t topicTemplate
+	rest := template
+	for rest != "" {
+		start := strings.IndexByte(rest, '{')
+		if start < 0 {
+			t.parts = append(t.parts, templatePart{literal: rest})
+			break
+		}
+		if start > 0 {
+			t.parts = append(t.parts, templatePart{literal: rest[:start]})
+		}
+		end := strings.IndexByte(rest[start:], '}')
+		if end < 0 {
+			return topicTemplate{}, fmt.Errorf("invalid topic %q: unclosed placeholder", template)
+		}
+		placeholder := rest[start+1 : start+end]
+		switch {
+		case placeholder == "signal":
+			t.parts = append(t.parts, templatePart{placeholder: true})
+		case strings.HasPrefix(placeholder, resourcePrefix) && len(placeholder) > len(resourcePrefix):
+			t.parts = append(t.parts, templatePart{placeholder: true, key: strings.TrimPrefix(placeholder, resourcePrefix)})
+			t.perResource = true
+		default:
+			return topicTemplate{}, fmt.Errorf("invalid topic %q: unknown placeholder %q", template, placeholder)
+		}
+		rest = rest[start+end+1:]
+	}
+
+	for _, part := range t.parts {
+		// Wildcards are only allowed in topic filters.
+		if strings.ContainsAny(part.literal, "+#}") {
+			return topicTemplate{}, fmt.Errorf("invalid topic %q: topics must not contain the +, # and } characters", template)
+		}
+	}
+	return t, nil
+}
+
+// execute returns the topic of the telemetry of a signal and resource. The
+// values of the resource attributes are not allowed to add levels nor
+// wildcards to the topic, and missing attributes are replaced by empty
+// strings.
+func (t topicTemplate) execute(signal string, resource pcommon.Resource) string {
+	var b strings.Builder
+	for _, part := range t.parts {
+		switch {
+		case !part.placeholder:
+			b.WriteString(part.literal)
+		case part.key == "":
+			b.WriteString(signal)
+		default:
+			if v, ok := resource.Attributes().Get(part.key); ok {
+				b.WriteString(levelReplacer.Replace(v.AsString()))
+			}
+		}
+	}
+	return b.String()
+}
+
+var levelReplacer = strings.NewReplacer("/", "_", "+", "_", "#", "_")
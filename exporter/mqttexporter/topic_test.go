@@ -0,0 +1,62 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package mqttexporter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+func TestTopicTemplate(t *testing.T) {
+	resource := pcommon.NewResource()
+	resource.Attributes().PutStr("host.name", "gw-1")
+	resource.Attributes().PutStr("site", "paris/north+#")
+	resource.Attributes().PutInt("rack", 4)
+
+	tests := []struct {
+		template    string
+		expected    string
+		perResource bool
+	}{
+		{template: "telemetry", expected: "telemetry"},
+		{template: "otlp/{signal}", expected: "otlp/logs"},
+		{template: "{signal}", expected: "logs"},
+		{template: "gateways/{resource.host.name}/{signal}", expected: "gateways/gw-1/logs", perResource: true},
+		{template: "sites/{resource.site}/racks/{resource.rack}", expected: "sites/paris_north__/racks/4", perResource: true},
+		{template: "gateways/{resource.missing}/logs", expected: "gateways//logs", perResource: true},
+		{template: "{signal}-{resource.host.name}", expected: "logs-gw-1", perResource: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			template, err := parseTopicTemplate(tt.template)
+			require.NoError(t, err)
+			assert.Equal(t, tt.perResource, template.perResource)
+			assert.Equal(t, tt.expected, template.execute("logs", resource))
+		})
+	}
+}
+
+func TestParseTopicTemplateErrors(t *testing.T) {
+	tests := []struct {
+		template    string
+		expectedErr string
+	}{
+		{template: "", expectedErr: "topic must not be empty"},
+		{template: "otlp/{signal", expectedErr: `invalid topic "otlp/{signal": unclosed placeholder`},
+		{template: "otlp/{service}", expectedErr: `invalid topic "otlp/{service}": unknown placeholder "service"`},
+		{template: "otlp/{resource.}", expectedErr: `invalid topic "otlp/{resource.}": unknown placeholder "resource."`},
+		{template: "otlp/+/{signal}", expectedErr: `invalid topic "otlp/+/{signal}": topics must not contain the +, # and } characters`},
+		{template: "otlp/#", expectedErr: `invalid topic "otlp/#": topics must not contain the +, # and } characters`},
+		{template: "otlp/signal}", expectedErr: `invalid topic "otlp/signal}": topics must not contain the +, # and } characters`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			_, err := parseTopicTemplate(tt.template)
+			assert.EqualError(t, err, tt.expectedErr)
+		})
+	}
+}
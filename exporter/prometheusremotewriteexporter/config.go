@@ -52,8 +52,22 @@ type Config struct {
 
 	// SendMetadata controls whether prometheus metadata will be generated and sent
 	SendMetadata bool `mapstructure:"send_metadata"`
+
+	// ProtobufMessage selects the Remote Write protocol version, by the protobuf message that is sent.
+	// Defaults to "prometheus.WriteRequest" (Remote Write 1.0).
+	ProtobufMessage ProtobufMessage `mapstructure:"protobuf_message"`
 }
 
+// ProtobufMessage is the fully qualified name of the protobuf message sent to the remote endpoint.
+type ProtobufMessage string
+
+const (
+	// ProtobufMessageV1 sends Remote Write 1.0 requests.
+	ProtobufMessageV1 ProtobufMessage = "prometheus.WriteRequest"
+	// ProtobufMessageV2 sends Remote Write 2.0 requests.
+	ProtobufMessageV2 ProtobufMessage = "io.prometheus.write.v2.Request"
+)
+
 type CreatedMetric struct {
 	// Enabled if true the _created metrics could be exported
 	Enabled bool `mapstructure:"enabled"`
@@ -115,5 +129,15 @@ func (cfg *Config) Validate() error {
 		cfg.MaxBatchSizeBytes = 3000000
 	}
 
+	switch cfg.ProtobufMessage {
+	case ProtobufMessageV1:
+	case ProtobufMessageV2:
+		if cfg.WAL != nil {
+			return fmt.Errorf("wal is not supported with protobuf_message %q", ProtobufMessageV2)
+		}
+	default:
+		return fmt.Errorf("invalid protobuf_message %q, must be one of %q or %q", cfg.ProtobufMessage, ProtobufMessageV1, ProtobufMessageV2)
+	}
+
 	return nil
 }
@@ -78,9 +78,31 @@ func TestLoadConfig(t *testing.T) {
 				TargetInfo: &TargetInfo{
 					Enabled: true,
 				},
-				CreatedMetric: &CreatedMetric{Enabled: true},
+				CreatedMetric:   &CreatedMetric{Enabled: true},
+				ProtobufMessage: ProtobufMessageV1,
 			},
 		},
+		{
+			id: component.NewIDWithName(metadata.Type, "remote_write_v2"),
+			expected: func() component.Config {
+				cfg := createDefaultConfig().(*Config)
+				cfg.ClientConfig.Endpoint = "localhost:8888"
+				cfg.ProtobufMessage = ProtobufMessageV2
+				return cfg
+			}(),
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "invalid_protobuf_message"),
+			errorMessage: `invalid protobuf_message "prometheus.WriteRequestV3", must be one of "prometheus.WriteRequest" or "io.prometheus.write.v2.Request"`,
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "empty_protobuf_message"),
+			errorMessage: `invalid protobuf_message "", must be one of "prometheus.WriteRequest" or "io.prometheus.write.v2.Request"`,
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "remote_write_v2_with_wal"),
+			errorMessage: `wal is not supported with protobuf_message "io.prometheus.write.v2.Request"`,
+		},
 		{
 			id:           component.NewIDWithName(metadata.Type, "negative_queue_size"),
 			errorMessage: "remote write queue size can't be negative",
@@ -16,7 +16,6 @@ import (
 	"sync"
 
 	"github.com/cenkalti/backoff/v4"
-	"github.com/gogo/protobuf/proto"
 	"github.com/golang/snappy"
 	"github.com/prometheus/prometheus/prompb"
 	"go.opentelemetry.io/collector/component"
@@ -31,8 +30,10 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/prometheusremotewriteexporter/internal/metadata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/wal"
 	prometheustranslator "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheus"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheusremotewrite"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheusremotewrite/writev2"
 )
 
 type prwTelemetry interface {
@@ -66,11 +67,17 @@ type prwExporter struct {
 	clientSettings    *confighttp.ClientConfig
 	settings          component.TelemetrySettings
 	retrySettings     configretry.BackOffConfig
-	wal               *prweWAL
+	wal               *wal.WAL[*prompb.WriteRequest]
 	exporterSettings  prometheusremotewrite.Settings
+	protobufMessage   ProtobufMessage
 	telemetry         prwTelemetry
 }
 
+// remoteWriteRequest is a Remote Write request of either protocol version.
+type remoteWriteRequest interface {
+	Marshal() ([]byte, error)
+}
+
 func newPRWTelemetry(set exporter.CreateSettings) (prwTelemetry, error) {
 
 	meter := metadata.Meter(set.TelemetrySettings)
@@ -132,10 +139,11 @@ func newPRWExporter(cfg *Config, set exporter.CreateSettings) (*prwExporter, err
 			AddMetricSuffixes:   cfg.AddMetricSuffixes,
 			SendMetadata:        cfg.SendMetadata,
 		},
-		telemetry: prwTelemetry,
+		protobufMessage: cfg.ProtobufMessage,
+		telemetry:       prwTelemetry,
 	}
 
-	prwe.wal = newWAL(cfg.WAL, prwe.export)
+	prwe.wal = newWAL(cfg.WAL, set.Logger.Named("prw.wal"), prwe.export)
 	return prwe, nil
 }
 
@@ -145,14 +153,14 @@ func (prwe *prwExporter) Start(ctx context.Context, host component.Host) (err er
 	if err != nil {
 		return err
 	}
-	return prwe.turnOnWALIfEnabled(contextWithLogger(ctx, prwe.settings.Logger.Named("prw.wal")))
+	return prwe.turnOnWALIfEnabled(ctx)
 }
 
 func (prwe *prwExporter) shutdownWALIfEnabled() error {
 	if !prwe.walEnabled() {
 		return nil
 	}
-	return prwe.wal.stop()
+	return prwe.wal.Stop()
 }
 
 // Shutdown stops the exporter from accepting incoming calls(and return error), and wait for current export operations
@@ -179,6 +187,9 @@ func (prwe *prwExporter) PushMetrics(ctx context.Context, md pmetric.Metrics) er
 	case <-prwe.closeChan:
 		return errors.New("shutdown has been called")
 	default:
+		if prwe.protobufMessage == ProtobufMessageV2 {
+			return prwe.pushMetricsV2(ctx, md)
+		}
 
 		tsMap, err := prometheusremotewrite.FromMetrics(md, prwe.exporterSettings)
 		if err != nil {
@@ -198,6 +209,29 @@ func (prwe *prwExporter) PushMetrics(ctx context.Context, md pmetric.Metrics) er
 	}
 }
 
+// pushMetricsV2 converts metrics to Remote Write 2.0 time series and sends them to the remote endpoint.
+func (prwe *prwExporter) pushMetricsV2(ctx context.Context, md pmetric.Metrics) error {
+	series, symbols, err := prometheusremotewrite.FromMetricsV2(md, prwe.exporterSettings)
+	if err != nil {
+		prwe.telemetry.recordTranslationFailure(ctx)
+		prwe.settings.Logger.Debug("failed to translate metrics, exporting remaining metrics", zap.Error(err), zap.Int("translated", len(series)))
+	}
+
+	prwe.telemetry.recordTranslatedTimeSeries(ctx, len(series))
+
+	// There are no metrics to export, so return.
+	if len(series) == 0 {
+		return nil
+	}
+
+	// Call export even if a conversion error, since there may be points that were successfully converted.
+	requests, err := batchTimeSeriesV2(series, symbols.Symbols(), prwe.maxBatchSizeBytes)
+	if err != nil {
+		return err
+	}
+	return exportRequests(ctx, prwe, requests)
+}
+
 func validateAndSanitizeExternalLabels(cfg *Config) (map[string]string, error) {
 	sanitizedLabels := make(map[string]string)
 	for key, value := range cfg.ExternalLabels {
@@ -228,7 +262,7 @@ func (prwe *prwExporter) handleExport(ctx context.Context, tsMap map[string]*pro
 
 	// Otherwise the WAL is enabled, and just persist the requests to the WAL
 	// and they'll be exported in another goroutine to the RemoteWrite endpoint.
-	if err = prwe.wal.persistToWAL(requests); err != nil {
+	if err = prwe.wal.Persist(requests); err != nil {
 		return consumererror.NewPermanent(err)
 	}
 	return nil
@@ -236,7 +270,12 @@ func (prwe *prwExporter) handleExport(ctx context.Context, tsMap map[string]*pro
 
 // export sends a Snappy-compressed WriteRequest containing TimeSeries to a remote write endpoint in order
 func (prwe *prwExporter) export(ctx context.Context, requests []*prompb.WriteRequest) error {
-	input := make(chan *prompb.WriteRequest, len(requests))
+	return exportRequests(ctx, prwe, requests)
+}
+
+// exportRequests sends requests to the remote write endpoint using up to the configured number of concurrent workers.
+func exportRequests[T remoteWriteRequest](ctx context.Context, prwe *prwExporter, requests []T) error {
+	input := make(chan T, len(requests))
 	for _, request := range requests {
 		input <- request
 	}
@@ -277,9 +316,9 @@ func (prwe *prwExporter) export(ctx context.Context, requests []*prompb.WriteReq
 	return errs
 }
 
-func (prwe *prwExporter) execute(ctx context.Context, writeReq *prompb.WriteRequest) error {
-	// Uses proto.Marshal to convert the WriteRequest into bytes array
-	data, errMarshal := proto.Marshal(writeReq)
+func (prwe *prwExporter) execute(ctx context.Context, writeReq remoteWriteRequest) error {
+	// Uses Marshal to convert the WriteRequest into bytes array
+	data, errMarshal := writeReq.Marshal()
 	if errMarshal != nil {
 		return consumererror.NewPermanent(errMarshal)
 	}
@@ -306,8 +345,14 @@ func (prwe *prwExporter) execute(ctx context.Context, writeReq *prompb.WriteRequ
 		// Add necessary headers specified by:
 		// https://cortexmetrics.io/docs/apis/#remote-api
 		req.Header.Add("Content-Encoding", "snappy")
-		req.Header.Set("Content-Type", "application/x-protobuf")
-		req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+		if prwe.protobufMessage == ProtobufMessageV2 {
+			// https://prometheus.io/docs/specs/remote_write_spec_2_0/
+			req.Header.Set("Content-Type", writev2.ContentType)
+			req.Header.Set("X-Prometheus-Remote-Write-Version", writev2.Version)
+		} else {
+			req.Header.Set("Content-Type", "application/x-protobuf")
+			req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+		}
 		req.Header.Set("User-Agent", prwe.userAgentHeader)
 
 		resp, err := prwe.client.Do(req)
@@ -361,10 +406,5 @@ func (prwe *prwExporter) turnOnWALIfEnabled(ctx context.Context) error {
 	if !prwe.walEnabled() {
 		return nil
 	}
-	cancelCtx, cancel := context.WithCancel(ctx)
-	go func() {
-		<-prwe.closeChan
-		cancel()
-	}()
-	return prwe.wal.run(cancelCtx)
+	return prwe.wal.Start(ctx)
 }
@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"path/filepath"
 	"sync"
 	"testing"
 	"time"
@@ -19,6 +20,7 @@ import (
 	"github.com/prometheus/prometheus/prompb"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	tidwallwal "github.com/tidwall/wal"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config/confighttp"
@@ -28,9 +30,11 @@ import (
 	"go.opentelemetry.io/collector/exporter"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
 	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/testdata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheusremotewrite/writev2"
 )
 
 // Test_NewPRWExporter checks that a new exporter instance with non-nil fields is initialized
@@ -964,7 +968,7 @@ func TestWALOnExporterRoundTrip(t *testing.T) {
 
 	// 3. Let's now read back all of the WAL records and ensure
 	// that all the prompb.WriteRequest values exist as we sent them.
-	wal, _, werr := cfg.WAL.createWAL()
+	wal, werr := tidwallwal.Open(filepath.Join(tempDir, walName), nil)
 	assert.NoError(t, werr)
 	assert.NotNil(t, wal)
 	t.Cleanup(func() {
@@ -1117,3 +1121,70 @@ func TestRetries(t *testing.T) {
 		})
 	}
 }
+
+func TestPushMetricsRemoteWriteV2(t *testing.T) {
+	var mu sync.Mutex
+	var received []*writev2.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, writev2.ContentType, r.Header.Get("Content-Type"))
+		assert.Equal(t, writev2.Version, r.Header.Get("X-Prometheus-Remote-Write-Version"))
+		assert.Equal(t, "snappy", r.Header.Get("Content-Encoding"))
+
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		data, err := snappy.Decode(nil, body)
+		assert.NoError(t, err)
+		req := &writev2.Request{}
+		assert.NoError(t, req.Unmarshal(data))
+
+		mu.Lock()
+		received = append(received, req)
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.ClientConfig.Endpoint = server.URL
+	cfg.ProtobufMessage = ProtobufMessageV2
+	cfg.TargetInfo.Enabled = false
+	// Small enough to split the series over multiple requests.
+	cfg.MaxBatchSizeBytes = 100
+
+	prwe, err := newPRWExporter(cfg, exportertest.NewNopCreateSettings())
+	require.NoError(t, err)
+	require.NoError(t, prwe.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() {
+		require.NoError(t, prwe.Shutdown(context.Background()))
+	}()
+
+	md := pmetric.NewMetrics()
+	metrics := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics()
+	for _, name := range []string{"first", "second", "third"} {
+		m := metrics.AppendEmpty()
+		m.SetName(name)
+		m.SetDescription("The " + name + " gauge")
+		dp := m.SetEmptyGauge().DataPoints().AppendEmpty()
+		dp.SetTimestamp(pcommon.NewTimestampFromTime(time.UnixMilli(1000)))
+		dp.SetDoubleValue(1)
+	}
+	require.NoError(t, prwe.PushMetrics(context.Background(), md))
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, received, 3)
+	var names []string
+	for _, req := range received {
+		require.Len(t, req.Timeseries, 1)
+		ts := req.Timeseries[0]
+		lbls, err := writev2.DesymbolizeLabels(ts.LabelsRefs, req.Symbols)
+		require.NoError(t, err)
+		require.Len(t, lbls, 1)
+		names = append(names, lbls[0].Value)
+		assert.Equal(t, writev2.Metadata_METRIC_TYPE_GAUGE, ts.Metadata.Type)
+		assert.Equal(t, "The "+lbls[0].Value+" gauge", req.Symbols[ts.Metadata.HelpRef])
+		// Every request only carries the symbols of its own series.
+		assert.Len(t, req.Symbols, 4)
+	}
+	assert.ElementsMatch(t, []string{"first", "second", "third"}, names)
+}
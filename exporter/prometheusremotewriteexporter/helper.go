@@ -8,6 +8,8 @@ import (
 	"sort"
 
 	"github.com/prometheus/prometheus/prompb"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheusremotewrite/writev2"
 )
 
 // batchTimeSeries splits series into multiple batch write requests.
@@ -96,3 +98,96 @@ func orderBySampleTimestamp(tsArray []prompb.TimeSeries) []prompb.TimeSeries {
 	}
 	return tsArray
 }
+
+// batchTimeSeriesV2 splits series into multiple Remote Write 2.0 requests. The
+// references of series are resolved against symbols, and every request gets
+// its own symbol table with only the strings its series reference.
+func batchTimeSeriesV2(series []writev2.TimeSeries, symbols []string, maxBatchByteSize int) ([]*writev2.Request, error) {
+	if len(series) == 0 {
+		return nil, errors.New("invalid series: cannot be empty")
+	}
+
+	var requests []*writev2.Request
+	table := writev2.NewSymbolTable()
+	batch := make([]writev2.TimeSeries, 0, len(series))
+	sizeOfCurrentBatch := 0
+
+	for i := range series {
+		// The size of the referenced strings is an upper bound, as strings
+		// shared with other series of the batch are only sent once.
+		sizeOfSeries := series[i].Size() + symbolsSize(&series[i], symbols)
+
+		if len(batch) > 0 && sizeOfCurrentBatch+sizeOfSeries >= maxBatchByteSize {
+			requests = append(requests, convertTimeseriesToRequestV2(batch, &table))
+
+			table = writev2.NewSymbolTable()
+			batch = make([]writev2.TimeSeries, 0, len(series)-i)
+			sizeOfCurrentBatch = 0
+		}
+
+		batch = append(batch, resymbolize(&series[i], symbols, &table))
+		sizeOfCurrentBatch += sizeOfSeries
+	}
+
+	if len(batch) != 0 {
+		requests = append(requests, convertTimeseriesToRequestV2(batch, &table))
+	}
+	return requests, nil
+}
+
+func convertTimeseriesToRequestV2(batch []writev2.TimeSeries, table *writev2.SymbolsTable) *writev2.Request {
+	for i := range batch {
+		sL := batch[i].Samples
+		sort.Slice(sL, func(i, j int) bool {
+			return sL[i].Timestamp < sL[j].Timestamp
+		})
+	}
+	return &writev2.Request{
+		Symbols:    table.Symbols(),
+		Timeseries: batch,
+	}
+}
+
+// symbolsSize returns the encoded size of the strings referenced by ts.
+func symbolsSize(ts *writev2.TimeSeries, symbols []string) int {
+	size := 0
+	add := func(ref uint32) {
+		// Each symbol is encoded with a tag and a length prefix.
+		size += len(symbols[ref]) + 2
+	}
+	for _, ref := range ts.LabelsRefs {
+		add(ref)
+	}
+	for i := range ts.Exemplars {
+		for _, ref := range ts.Exemplars[i].LabelsRefs {
+			add(ref)
+		}
+	}
+	add(ts.Metadata.HelpRef)
+	add(ts.Metadata.UnitRef)
+	return size
+}
+
+// resymbolize returns a copy of ts whose references point into table instead of symbols.
+func resymbolize(ts *writev2.TimeSeries, symbols []string, table *writev2.SymbolsTable) writev2.TimeSeries {
+	remap := func(refs []uint32) []uint32 {
+		out := make([]uint32, len(refs))
+		for i, ref := range refs {
+			out[i] = table.Symbolize(symbols[ref])
+		}
+		return out
+	}
+
+	out := *ts
+	out.LabelsRefs = remap(ts.LabelsRefs)
+	if len(ts.Exemplars) > 0 {
+		out.Exemplars = make([]writev2.Exemplar, len(ts.Exemplars))
+		for i, e := range ts.Exemplars {
+			e.LabelsRefs = remap(e.LabelsRefs)
+			out.Exemplars[i] = e
+		}
+	}
+	out.Metadata.HelpRef = table.Symbolize(symbols[ts.Metadata.HelpRef])
+	out.Metadata.UnitRef = table.Symbolize(symbols[ts.Metadata.UnitRef])
+	return out
+}
@@ -6,12 +6,14 @@ package prometheusremotewriteexporter
 import (
 	"context"
 	"sort"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/prometheus/prometheus/prompb"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
 )
 
 func doNothingExportSink(_ context.Context, reqL []*prompb.WriteRequest) error {
@@ -21,15 +23,16 @@ func doNothingExportSink(_ context.Context, reqL []*prompb.WriteRequest) error {
 
 func TestWALCreation_nilConfig(t *testing.T) {
 	config := (*WALConfig)(nil)
-	pwal := newWAL(config, doNothingExportSink)
+	pwal := newWAL(config, zap.NewNop(), doNothingExportSink)
 	require.Nil(t, pwal)
 }
 
 func TestWALCreation_nonNilConfig(t *testing.T) {
 	config := &WALConfig{Directory: t.TempDir()}
-	pwal := newWAL(config, doNothingExportSink)
+	pwal := newWAL(config, zap.NewNop(), doNothingExportSink)
 	require.NotNil(t, pwal)
-	assert.NoError(t, pwal.stop())
+	require.NoError(t, pwal.Start(context.Background()))
+	assert.NoError(t, pwal.Stop())
 }
 
 func orderByLabelValueForEach(reqL []*prompb.WriteRequest) {
@@ -71,30 +74,23 @@ func orderByLabelValue(wreq *prompb.WriteRequest) {
 	})
 }
 
-func TestWALStopManyTimes(t *testing.T) {
-	tempDir := t.TempDir()
+func TestWAL_persist(t *testing.T) {
+	// Unit tests that requests written to the WAL persist and are exported in order.
 	config := &WALConfig{
-		Directory:         tempDir,
-		TruncateFrequency: 60 * time.Microsecond,
-		BufferSize:        1,
-	}
-	pwal := newWAL(config, doNothingExportSink)
-	require.NotNil(t, pwal)
-
-	// Ensure that invoking .stop() multiple times doesn't cause a panic, but actually
-	// First close should NOT return an error.
-	require.NoError(t, pwal.stop())
-	for i := 0; i < 4; i++ {
-		// Every invocation to .stop() should return an errAlreadyClosed.
-		require.ErrorIs(t, pwal.stop(), errAlreadyClosed)
+		Directory:         t.TempDir(),
+		TruncateFrequency: 10 * time.Millisecond,
 	}
-}
 
-func TestWAL_persist(t *testing.T) {
-	// Unit tests that requests written to the WAL persist.
-	config := &WALConfig{Directory: t.TempDir()}
-
-	pwal := newWAL(config, doNothingExportSink)
+	var (
+		mu          sync.Mutex
+		reqLFromWAL []*prompb.WriteRequest
+	)
+	pwal := newWAL(config, zap.NewNop(), func(_ context.Context, reqL []*prompb.WriteRequest) error {
+		mu.Lock()
+		defer mu.Unlock()
+		reqLFromWAL = append(reqLFromWAL, reqL...)
+		return nil
+	})
 	require.NotNil(t, pwal)
 
 	// 1. Write out all the entries.
@@ -121,29 +117,23 @@ func TestWAL_persist(t *testing.T) {
 		},
 	}
 
-	ctx := context.Background()
-	require.NoError(t, pwal.retrieveWALIndices())
+	require.NoError(t, pwal.Start(context.Background()))
 	t.Cleanup(func() {
-		assert.NoError(t, pwal.stop())
+		assert.NoError(t, pwal.Stop())
 	})
 
-	require.NoError(t, pwal.persistToWAL(reqL))
+	require.NoError(t, pwal.Persist(reqL))
 
-	// 2. Read all the entries from the WAL itself, guided by the indices available,
+	// 2. Wait for the entries to be read back from the WAL,
 	// and ensure that they are exactly in order as we'd expect them.
-	wal := pwal.wal
-	start, err := wal.FirstIndex()
-	require.NoError(t, err)
-	end, err := wal.LastIndex()
-	require.NoError(t, err)
-
-	var reqLFromWAL []*prompb.WriteRequest
-	for i := start; i <= end; i++ {
-		req, err := pwal.readPrompbFromWAL(ctx, i)
-		require.NoError(t, err)
-		reqLFromWAL = append(reqLFromWAL, req)
-	}
-
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(reqLFromWAL) == len(reqL)
+	}, 5*time.Second, 10*time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
 	orderByLabelValueForEach(reqL)
 	orderByLabelValueForEach(reqLFromWAL)
 	require.Equal(t, reqLFromWAL[0], reqL[0])
@@ -16,6 +16,7 @@ import (
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/splunk"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/wal"
 )
 
 const (
@@ -133,6 +134,9 @@ type Config struct {
 
 	// Telemetry is the configuration for splunk hec exporter telemetry
 	Telemetry HecTelemetry `mapstructure:"telemetry"`
+
+	// WAL enables a write-ahead log, giving ordered delivery of data which is replayed after a restart.
+	WAL *wal.Config `mapstructure:"wal"`
 }
 
 func (cfg *Config) getURL() (out *url.URL, err error) {
@@ -5,6 +5,7 @@ package splunkhecexporter // import "github.com/open-telemetry/opentelemetry-col
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"go.opentelemetry.io/collector/component"
@@ -17,6 +18,7 @@ import (
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/splunkhecexporter/internal/metadata"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/splunk"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/wal"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/batchperresourceattr"
 )
 
@@ -47,6 +49,21 @@ type baseTracesExporter struct {
 	consumer.Traces
 }
 
+func startWithWAL[T any](c *client, pusher *wal.Pusher[T]) component.StartFunc {
+	return func(ctx context.Context, host component.Host) error {
+		if err := c.start(ctx, host); err != nil {
+			return err
+		}
+		return pusher.Start(ctx)
+	}
+}
+
+func shutdownWithWAL[T any](c *client, pusher *wal.Pusher[T]) component.ShutdownFunc {
+	return func(ctx context.Context) error {
+		return errors.Join(pusher.Shutdown(ctx), c.stop(ctx))
+	}
+}
+
 // NewFactory creates a factory for Splunk HEC exporter.
 func NewFactory() exporter.Factory {
 	return exporter.NewFactory(
@@ -108,18 +125,19 @@ func createTracesExporter(
 	cfg := config.(*Config)
 
 	c := newTracesClient(set, cfg)
+	pusher := wal.NewPusher(cfg.WAL, wal.Name(set.ID, "traces"), set.Logger, wal.TracesCodec{}, c.pushTraceData)
 
 	e, err := exporterhelper.NewTracesExporter(
 		ctx,
 		set,
 		cfg,
-		c.pushTraceData,
+		pusher.Push,
 		// explicitly disable since we rely on http.Client timeout logic.
 		exporterhelper.WithTimeout(exporterhelper.TimeoutSettings{Timeout: 0}),
 		exporterhelper.WithRetry(cfg.BackOffConfig),
 		exporterhelper.WithQueue(cfg.QueueSettings),
-		exporterhelper.WithStart(c.start),
-		exporterhelper.WithShutdown(c.stop))
+		exporterhelper.WithStart(startWithWAL(c, pusher)),
+		exporterhelper.WithShutdown(shutdownWithWAL(c, pusher)))
 
 	if err != nil {
 		return nil, err
@@ -141,18 +159,19 @@ func createMetricsExporter(
 	cfg := config.(*Config)
 
 	c := newMetricsClient(set, cfg)
+	pusher := wal.NewPusher(cfg.WAL, wal.Name(set.ID, "metrics"), set.Logger, wal.MetricsCodec{}, c.pushMetricsData)
 
 	e, err := exporterhelper.NewMetricsExporter(
 		ctx,
 		set,
 		cfg,
-		c.pushMetricsData,
+		pusher.Push,
 		// explicitly disable since we rely on http.Client timeout logic.
 		exporterhelper.WithTimeout(exporterhelper.TimeoutSettings{Timeout: 0}),
 		exporterhelper.WithRetry(cfg.BackOffConfig),
 		exporterhelper.WithQueue(cfg.QueueSettings),
-		exporterhelper.WithStart(c.start),
-		exporterhelper.WithShutdown(c.stop))
+		exporterhelper.WithStart(startWithWAL(c, pusher)),
+		exporterhelper.WithShutdown(shutdownWithWAL(c, pusher)))
 	if err != nil {
 		return nil, err
 	}
@@ -173,18 +192,19 @@ func createLogsExporter(
 	cfg := config.(*Config)
 
 	c := newLogsClient(set, cfg)
+	pusher := wal.NewPusher(cfg.WAL, wal.Name(set.ID, "logs"), set.Logger, wal.LogsCodec{}, c.pushLogData)
 
 	logsExporter, err := exporterhelper.NewLogsExporter(
 		ctx,
 		set,
 		cfg,
-		c.pushLogData,
+		pusher.Push,
 		// explicitly disable since we rely on http.Client timeout logic.
 		exporterhelper.WithTimeout(exporterhelper.TimeoutSettings{Timeout: 0}),
 		exporterhelper.WithRetry(cfg.BackOffConfig),
 		exporterhelper.WithQueue(cfg.QueueSettings),
-		exporterhelper.WithStart(c.start),
-		exporterhelper.WithShutdown(c.stop))
+		exporterhelper.WithStart(startWithWAL(c, pusher)),
+		exporterhelper.WithShutdown(shutdownWithWAL(c, pusher)))
 
 	if err != nil {
 		return nil, err
@@ -5,13 +5,24 @@ package splunkhecexporter
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/pdata/plog"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/wal"
 )
 
 func TestCreateDefaultConfig(t *testing.T) {
@@ -88,3 +99,137 @@ func TestFactory_CreateMetricsExporter(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, te)
 }
+
+func TestCreateLogsExporterWithWAL(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		events   []string
+		failures = 1
+	)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if failures > 0 {
+			failures--
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		dec := json.NewDecoder(r.Body)
+		for {
+			var event struct {
+				Event string `json:"event"`
+			}
+			err := dec.Decode(&event)
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			assert.NoError(t, err)
+			events = append(events, event.Event)
+		}
+	}))
+	defer ts.Close()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.ClientConfig.Endpoint = ts.URL
+	cfg.Token = "1234-1234"
+	cfg.DisableCompression = true
+	cfg.QueueSettings.Enabled = false
+	cfg.BackOffConfig.Enabled = false
+	cfg.WAL = &wal.Config{
+		Directory:         t.TempDir(),
+		BufferSize:        1,
+		TruncateFrequency: 10 * time.Millisecond,
+	}
+
+	exp, err := createLogsExporter(context.Background(), exportertest.NewNopCreateSettings(), cfg)
+	require.NoError(t, err)
+	require.NoError(t, exp.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, exp.Shutdown(context.Background()))
+	})
+
+	for i := 0; i < 3; i++ {
+		ld := plog.NewLogs()
+		ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStr(strconv.Itoa(i))
+		// The first request fails, but the logs are already persisted.
+		require.NoError(t, exp.ConsumeLogs(context.Background(), ld))
+	}
+
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(events) == 3
+	}, 10*time.Second, 10*time.Millisecond)
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"0", "1", "2"}, events)
+}
+
+func TestCreateLogsExporterWithWALResendsFailedLogs(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		requests int
+		events   []string
+	)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		requests++
+		if requests == 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		dec := json.NewDecoder(r.Body)
+		for {
+			var event struct {
+				Event string `json:"event"`
+			}
+			err := dec.Decode(&event)
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			assert.NoError(t, err)
+			events = append(events, event.Event)
+		}
+	}))
+	defer ts.Close()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.ClientConfig.Endpoint = ts.URL
+	cfg.Token = "1234-1234"
+	cfg.DisableCompression = true
+	// The logs are split into several requests.
+	cfg.MaxContentLengthLogs = 60
+	cfg.QueueSettings.Enabled = false
+	cfg.BackOffConfig.Enabled = false
+	cfg.WAL = &wal.Config{
+		Directory:         t.TempDir(),
+		TruncateFrequency: 10 * time.Millisecond,
+	}
+
+	exp, err := createLogsExporter(context.Background(), exportertest.NewNopCreateSettings(), cfg)
+	require.NoError(t, err)
+	require.NoError(t, exp.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, exp.Shutdown(context.Background()))
+	})
+
+	ld := plog.NewLogs()
+	records := ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords()
+	for i := 0; i < 3; i++ {
+		records.AppendEmpty().Body().SetStr(strconv.Itoa(i))
+	}
+	require.NoError(t, exp.ConsumeLogs(context.Background(), ld))
+
+	// The second request fails, and only the logs which were not sent are sent again.
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(events) >= 3
+	}, 10*time.Second, 10*time.Millisecond)
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"0", "1", "2"}, events)
+}
@@ -0,0 +1,36 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package apikeyauthextension // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/apikeyauthextension"
+
+import (
+	"sort"
+
+	"go.opentelemetry.io/collector/client"
+)
+
+var _ client.AuthData = (*authData)(nil)
+
+// authData exposes the identifier and the attributes of the API key of the request.
+type authData struct {
+	key *apiKey
+}
+
+func (a *authData) GetAttribute(name string) any {
+	if name == keyIDAttribute {
+		return a.key.ID
+	}
+	if value, ok := a.key.Attributes[name]; ok {
+		return value
+	}
+	return nil
+}
+
+func (a *authData) GetAttributeNames() []string {
+	names := make([]string, 0, len(a.key.Attributes)+1)
+	for name := range a.key.Attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return append([]string{keyIDAttribute}, names...)
+}
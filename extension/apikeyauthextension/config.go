@@ -0,0 +1,45 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package apikeyauthextension // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/apikeyauthextension"
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	errNoKeysFile            = errors.New("no keys_file provided")
+	errNoHeader              = errors.New("no header provided")
+	errInvalidReloadInterval = errors.New("reload_interval must be greater than 0")
+)
+
+// Config has the configuration for the API key Authenticator extension.
+type Config struct {
+	// Header is the name of the header holding the API key. Optional, default value: "x-api-key".
+	Header string `mapstructure:"header"`
+
+	// Scheme is the prefix expected before the API key in the header value, such as "ApiKey"
+	// when the key is sent in the authorization header. Optional.
+	Scheme string `mapstructure:"scheme"`
+
+	// KeysFile is the path of the file holding the hashes of the accepted API keys and their attributes.
+	// Required.
+	KeysFile string `mapstructure:"keys_file"`
+
+	// ReloadInterval is how often the keys file is checked for changes. Optional, default value: 30s.
+	ReloadInterval time.Duration `mapstructure:"reload_interval"`
+}
+
+func (cfg *Config) Validate() error {
+	if cfg.KeysFile == "" {
+		return errNoKeysFile
+	}
+	if cfg.Header == "" {
+		return errNoHeader
+	}
+	if cfg.ReloadInterval <= 0 {
+		return errInvalidReloadInterval
+	}
+	return nil
+}
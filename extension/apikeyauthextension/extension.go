@@ -0,0 +1,120 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package apikeyauthextension // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/apikeyauthextension"
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"go.opentelemetry.io/collector/client"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/extension"
+	"go.opentelemetry.io/collector/extension/auth"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/apikeyauthextension/internal/metadata"
+)
+
+var (
+	errNoAPIKey            = errors.New("no API key provided")
+	errInvalidSchemePrefix = errors.New("invalid API key scheme prefix")
+	errInvalidAPIKey       = errors.New("invalid API key")
+)
+
+const (
+	resultAuthenticated = "authenticated"
+	resultMissing       = "missing"
+	resultInvalid       = "invalid"
+)
+
+type apiKeyAuth struct {
+	cfg  *Config
+	keys *keyStore
+
+	extensionAttr attribute.KeyValue
+	requests      metric.Int64Counter
+}
+
+func newServerAuthExtension(cfg *Config, set extension.CreateSettings) (auth.Server, error) {
+	requests, err := metadata.Meter(set.TelemetrySettings).Int64Counter(
+		"apikeyauth_requests",
+		metric.WithDescription("Number of requests authenticated by the extension, by API key and result"),
+		metric.WithUnit("{requests}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &apiKeyAuth{
+		cfg:           cfg,
+		keys:          newKeyStore(cfg.KeysFile, cfg.ReloadInterval, set.Logger),
+		extensionAttr: attribute.String("extension", set.ID.String()),
+		requests:      requests,
+	}
+	return auth.NewServer(
+		auth.WithServerStart(a.start),
+		auth.WithServerAuthenticate(a.authenticate),
+		auth.WithServerShutdown(a.shutdown),
+	), nil
+}
+
+func (a *apiKeyAuth) start(context.Context, component.Host) error {
+	return a.keys.start()
+}
+
+func (a *apiKeyAuth) shutdown(context.Context) error {
+	a.keys.shutdown()
+	return nil
+}
+
+func (a *apiKeyAuth) authenticate(ctx context.Context, headers map[string][]string) (context.Context, error) {
+	raw, err := a.getAPIKey(headers)
+	if err != nil {
+		a.record(ctx, resultMissing)
+		return ctx, err
+	}
+
+	key, ok := a.keys.lookup(raw)
+	if !ok {
+		a.record(ctx, resultInvalid)
+		return ctx, errInvalidAPIKey
+	}
+	a.record(ctx, resultAuthenticated, attribute.String(keyIDAttribute, key.ID))
+
+	cl := client.FromContext(ctx)
+	cl.Auth = &authData{key: key}
+	return client.NewContext(ctx, cl), nil
+}
+
+// getAPIKey returns the API key of the configured header, without the scheme prefix.
+func (a *apiKeyAuth) getAPIKey(headers map[string][]string) (string, error) {
+	var values []string
+	for k, v := range headers {
+		if strings.EqualFold(k, a.cfg.Header) {
+			values = v
+			break
+		}
+	}
+	// we only use the first header, if multiple values exist
+	if len(values) == 0 || values[0] == "" {
+		return "", errNoAPIKey
+	}
+
+	value := values[0]
+	if a.cfg.Scheme == "" {
+		return value, nil
+	}
+	prefix := a.cfg.Scheme + " "
+	if len(value) <= len(prefix) || !strings.EqualFold(value[:len(prefix)], prefix) {
+		return "", errInvalidSchemePrefix
+	}
+	return value[len(prefix):], nil
+}
+
+func (a *apiKeyAuth) record(ctx context.Context, result string, attrs ...attribute.KeyValue) {
+	attrs = append(attrs, a.extensionAttr, attribute.String("result", result))
+	a.requests.Add(ctx, 1, metric.WithAttributes(attrs...))
+}
@@ -0,0 +1,178 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package apikeyauthextension
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/client"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/extension/auth"
+	"go.opentelemetry.io/collector/extension/extensiontest"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func newTestExtension(t *testing.T, cfg *Config) (auth.Server, *sdkmetric.ManualReader) {
+	reader := sdkmetric.NewManualReader()
+	set := extensiontest.NewNopCreateSettings()
+	set.MeterProvider = sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	ext, err := newServerAuthExtension(cfg, set)
+	require.NoError(t, err)
+	require.NoError(t, ext.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		require.NoError(t, ext.Shutdown(context.Background()))
+	})
+	return ext, reader
+}
+
+// collectRequests returns the number of requests reported by the extension, by API key id and result.
+func collectRequests(t *testing.T, reader *sdkmetric.ManualReader) map[[2]string]int64 {
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+
+	requests := map[[2]string]int64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			require.Equal(t, "apikeyauth_requests", m.Name)
+			for _, point := range m.Data.(metricdata.Sum[int64]).DataPoints {
+				keyID, _ := point.Attributes.Value(keyIDAttribute)
+				result, _ := point.Attributes.Value("result")
+				requests[[2]string{keyID.AsString(), result.AsString()}] += point.Value
+			}
+		}
+	}
+	return requests
+}
+
+func TestAuthenticate(t *testing.T) {
+	ext, reader := newTestExtension(t, &Config{
+		Header:         defaultHeader,
+		KeysFile:       filepath.Join("testdata", "keys.yaml"),
+		ReloadInterval: time.Minute,
+	})
+
+	// test
+	ctx, err := ext.Authenticate(context.Background(), map[string][]string{"X-Api-Key": {"acme-secret"}})
+
+	// verify
+	require.NoError(t, err)
+	authData := client.FromContext(ctx).Auth
+	require.NotNil(t, authData)
+	assert.Equal(t, "acme-ingest", authData.GetAttribute("api_key.id"))
+	assert.Equal(t, "acme", authData.GetAttribute("tenant.id"))
+	assert.Equal(t, "gold", authData.GetAttribute("tier"))
+	assert.Nil(t, authData.GetAttribute("unknown"))
+	assert.Equal(t, []string{"api_key.id", "tenant.id", "tier"}, authData.GetAttributeNames())
+
+	// test, lower-case header
+	ctx, err = ext.Authenticate(context.Background(), map[string][]string{"x-api-key": {"globex-secret"}})
+
+	// verify
+	require.NoError(t, err)
+	assert.Equal(t, "globex", client.FromContext(ctx).Auth.GetAttribute("tenant.id"))
+	assert.Nil(t, client.FromContext(ctx).Auth.GetAttribute("tier"))
+
+	// test, invalid and missing keys
+	_, err = ext.Authenticate(context.Background(), map[string][]string{"x-api-key": {"initech-secret"}})
+	assert.ErrorIs(t, err, errInvalidAPIKey)
+	_, err = ext.Authenticate(context.Background(), map[string][]string{"authorization": {"acme-secret"}})
+	assert.ErrorIs(t, err, errNoAPIKey)
+
+	// verify
+	assert.Equal(t, map[[2]string]int64{
+		{"acme-ingest", "authenticated"}:   1,
+		{"globex-ingest", "authenticated"}: 1,
+		{"", "invalid"}:                    1,
+		{"", "missing"}:                    1,
+	}, collectRequests(t, reader))
+}
+
+func TestAuthenticateWithScheme(t *testing.T) {
+	ext, _ := newTestExtension(t, &Config{
+		Header:         "authorization",
+		Scheme:         "ApiKey",
+		KeysFile:       filepath.Join("testdata", "keys.yaml"),
+		ReloadInterval: time.Minute,
+	})
+
+	for _, tt := range []struct {
+		casename    string
+		value       string
+		expectedErr error
+	}{
+		{"valid", "ApiKey acme-secret", nil},
+		{"caseInsensitiveScheme", "apikey acme-secret", nil},
+		{"otherScheme", "Bearer acme-secret", errInvalidSchemePrefix},
+		{"noScheme", "acme-secret", errInvalidSchemePrefix},
+		{"onlyScheme", "ApiKey ", errInvalidSchemePrefix},
+		{"invalidKey", "ApiKey globex", errInvalidAPIKey},
+	} {
+		t.Run(tt.casename, func(t *testing.T) {
+			_, err := ext.Authenticate(context.Background(), map[string][]string{"Authorization": {tt.value}})
+			if tt.expectedErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			}
+		})
+	}
+}
+
+func TestReloadKeysFile(t *testing.T) {
+	keysFile := filepath.Join(t.TempDir(), "keys.yaml")
+	keys, err := os.ReadFile(filepath.Join("testdata", "keys.yaml"))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(keysFile, keys, 0600))
+
+	ext, _ := newTestExtension(t, &Config{
+		Header:         defaultHeader,
+		KeysFile:       keysFile,
+		ReloadInterval: 10 * time.Millisecond,
+	})
+	authenticate := func(key string) error {
+		_, err := ext.Authenticate(context.Background(), map[string][]string{"x-api-key": {key}})
+		return err
+	}
+	require.NoError(t, authenticate("acme-secret"))
+
+	// test, the key of acme is revoked
+	require.NoError(t, os.WriteFile(keysFile, []byte(`
+keys:
+  - id: globex-ingest
+    hash: sha256:4fe6ae1bd397d68b149f8a86069f5e6806a937d7d0b2f31830c48008b268bda0
+`), 0600))
+
+	// verify
+	assert.Eventually(t, func() bool {
+		return authenticate("acme-secret") != nil
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.NoError(t, authenticate("globex-secret"))
+
+	// test, an invalid file keeps the previous keys
+	require.NoError(t, os.WriteFile(keysFile, []byte("keys: [{id: globex-ingest, hash: md5:abc}]"), 0600))
+	time.Sleep(50 * time.Millisecond)
+
+	// verify
+	assert.NoError(t, authenticate("globex-secret"))
+}
+
+func TestStartWithInvalidKeysFile(t *testing.T) {
+	ext, err := newServerAuthExtension(&Config{
+		Header:         defaultHeader,
+		KeysFile:       filepath.Join("testdata", "missing.yaml"),
+		ReloadInterval: time.Minute,
+	}, extensiontest.NewNopCreateSettings())
+	require.NoError(t, err)
+
+	assert.Error(t, ext.Start(context.Background(), componenttest.NewNopHost()))
+	assert.NoError(t, ext.Shutdown(context.Background()))
+}
@@ -0,0 +1,181 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package apikeyauthextension // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/apikeyauthextension"
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	sha256Prefix = "sha256:"
+
+	// keyIDAttribute is the auth attribute holding the identifier of the API key.
+	keyIDAttribute = "api_key.id"
+)
+
+// apiKey is an accepted API key, as configured in the keys file.
+type apiKey struct {
+	// ID identifies the key in the auth attributes and the metrics, without revealing it.
+	ID string `yaml:"id"`
+	// Hash is the hex-encoded SHA-256 hash of the key, prefixed with "sha256:".
+	Hash string `yaml:"hash"`
+	// Attributes are the auth attributes of the requests authenticated with the key.
+	Attributes map[string]string `yaml:"attributes"`
+}
+
+type keysFile struct {
+	Keys []*apiKey `yaml:"keys"`
+}
+
+// keySet holds the accepted API keys, by hash.
+type keySet map[[sha256.Size]byte]*apiKey
+
+// lookup returns the accepted API key matching the raw key.
+func (s keySet) lookup(raw string) (*apiKey, bool) {
+	key, ok := s[sha256.Sum256([]byte(raw))]
+	return key, ok
+}
+
+// readKeysFile returns the API keys of the file.
+func readKeysFile(path string) (keySet, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read the keys file %q: %w", path, err)
+	}
+
+	var file keysFile
+	if err = yaml.Unmarshal(raw, &file); err != nil {
+		return nil, fmt.Errorf("cannot decode the contents of the keys file %q: %w", path, err)
+	}
+
+	keys := keySet{}
+	ids := map[string]bool{}
+	for i, key := range file.Keys {
+		if key == nil || key.ID == "" {
+			return nil, fmt.Errorf("key #%d of the keys file %q has no id", i, path)
+		}
+		if ids[key.ID] {
+			return nil, fmt.Errorf("duplicate key id %q in the keys file %q", key.ID, path)
+		}
+		ids[key.ID] = true
+
+		if !strings.HasPrefix(key.Hash, sha256Prefix) {
+			return nil, fmt.Errorf("the hash of the key %q must start with %q", key.ID, sha256Prefix)
+		}
+		decoded, err := hex.DecodeString(strings.TrimPrefix(key.Hash, sha256Prefix))
+		if err != nil || len(decoded) != sha256.Size {
+			return nil, fmt.Errorf("the hash of the key %q isn't a hex-encoded SHA-256 hash", key.ID)
+		}
+		hash := [sha256.Size]byte(decoded)
+		if _, ok := keys[hash]; ok {
+			return nil, fmt.Errorf("the key %q has the same hash as another key", key.ID)
+		}
+
+		if _, ok := key.Attributes[keyIDAttribute]; ok {
+			return nil, fmt.Errorf("the key %q cannot set the reserved attribute %q", key.ID, keyIDAttribute)
+		}
+		keys[hash] = key
+	}
+	return keys, nil
+}
+
+// keyStore holds the API keys of the keys file, reloading them when the file changes.
+type keyStore struct {
+	path     string
+	interval time.Duration
+	logger   *zap.Logger
+
+	keys    atomic.Pointer[keySet]
+	modTime time.Time
+	size    int64
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+func newKeyStore(path string, interval time.Duration, logger *zap.Logger) *keyStore {
+	return &keyStore{
+		path:     filepath.Clean(path),
+		interval: interval,
+		logger:   logger,
+		done:     make(chan struct{}),
+	}
+}
+
+// start loads the keys, which must be valid, and watches the file for changes until shutdown is called.
+func (s *keyStore) start() error {
+	if _, err := s.reload(); err != nil {
+		return err
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				reloaded, err := s.reload()
+				if err != nil {
+					s.logger.Warn("failed to reload the keys file, keeping the previous keys", zap.String("path", s.path), zap.Error(err))
+				} else if reloaded {
+					s.logger.Info("reloaded the keys file", zap.String("path", s.path), zap.Int("keys", len(*s.keys.Load())))
+				}
+			case <-s.done:
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+func (s *keyStore) shutdown() {
+	select {
+	case <-s.done:
+	default:
+		close(s.done)
+	}
+	s.wg.Wait()
+}
+
+// lookup returns the accepted API key matching the raw key.
+func (s *keyStore) lookup(raw string) (*apiKey, bool) {
+	keys := s.keys.Load()
+	if keys == nil {
+		return nil, false
+	}
+	return keys.lookup(raw)
+}
+
+// reload loads the keys of the file if it changed since it was last loaded.
+func (s *keyStore) reload() (bool, error) {
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return false, fmt.Errorf("could not read the keys file %q: %w", s.path, err)
+	}
+	if s.keys.Load() != nil && info.ModTime().Equal(s.modTime) && info.Size() == s.size {
+		return false, nil
+	}
+
+	keys, err := readKeysFile(s.path)
+	if err != nil {
+		return false, err
+	}
+	s.keys.Store(&keys)
+	s.modTime = info.ModTime()
+	s.size = info.Size()
+	return true, nil
+}
@@ -0,0 +1,81 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package apikeyauthextension
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadKeysFile(t *testing.T) {
+	keys, err := readKeysFile(filepath.Join("testdata", "keys.yaml"))
+	require.NoError(t, err)
+	assert.Len(t, keys, 2)
+
+	key, ok := keys.lookup("acme-secret")
+	require.True(t, ok)
+	assert.Equal(t, "acme-ingest", key.ID)
+	assert.Equal(t, map[string]string{"tenant.id": "acme", "tier": "gold"}, key.Attributes)
+
+	_, ok = keys.lookup("acme")
+	assert.False(t, ok)
+}
+
+func TestReadInvalidKeysFile(t *testing.T) {
+	const acmeHash = "sha256:307c609f87da43c3d563428a4f7efdf9857f4871fd10465732c4ab11a985a08c"
+
+	for _, tt := range []struct {
+		casename    string
+		content     string
+		expectedErr string
+	}{
+		{
+			casename:    "invalidYAML",
+			content:     "keys: {",
+			expectedErr: "cannot decode the contents of the keys file",
+		},
+		{
+			casename:    "missingID",
+			content:     "keys: [{hash: " + acmeHash + "}]",
+			expectedErr: "key #0 of the keys file",
+		},
+		{
+			casename:    "duplicateID",
+			content:     "keys: [{id: acme, hash: " + acmeHash + "}, {id: acme, hash: " + acmeHash + "}]",
+			expectedErr: `duplicate key id "acme"`,
+		},
+		{
+			casename:    "unsupportedHash",
+			content:     "keys: [{id: acme, hash: md5:d41d8cd98f00b204e9800998ecf8427e}]",
+			expectedErr: `the hash of the key "acme" must start with "sha256:"`,
+		},
+		{
+			casename:    "invalidHash",
+			content:     "keys: [{id: acme, hash: sha256:abc}]",
+			expectedErr: `the hash of the key "acme" isn't a hex-encoded SHA-256 hash`,
+		},
+		{
+			casename:    "duplicateHash",
+			content:     "keys: [{id: acme, hash: " + acmeHash + "}, {id: globex, hash: " + acmeHash + "}]",
+			expectedErr: `the key "globex" has the same hash as another key`,
+		},
+		{
+			casename:    "reservedAttribute",
+			content:     "keys: [{id: acme, hash: " + acmeHash + ", attributes: {api_key.id: other}}]",
+			expectedErr: `the key "acme" cannot set the reserved attribute "api_key.id"`,
+		},
+	} {
+		t.Run(tt.casename, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "keys.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0600))
+
+			_, err := readKeysFile(path)
+			assert.ErrorContains(t, err, tt.expectedErr)
+		})
+	}
+}
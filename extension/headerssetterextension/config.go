@@ -10,8 +10,8 @@ import (
 var (
 	errMissingHeader        = fmt.Errorf("missing header name")
 	errMissingHeadersConfig = fmt.Errorf("missing headers configuration")
-	errMissingSource        = fmt.Errorf("missing header source, must be 'from_context' or 'value'")
-	errConflictingSources   = fmt.Errorf("invalid header source, must either 'from_context' or 'value'")
+	errMissingSource        = fmt.Errorf("missing header source, must be 'from_context', 'from_auth' or 'value'")
+	errConflictingSources   = fmt.Errorf("invalid header source, must either 'from_context', 'from_auth' or 'value'")
 )
 
 type Config struct {
@@ -23,6 +23,7 @@ type HeaderConfig struct {
 	Key         *string     `mapstructure:"key"`
 	Value       *string     `mapstructure:"value"`
 	FromContext *string     `mapstructure:"from_context"`
+	FromAuth    *string     `mapstructure:"from_auth"`
 }
 
 // ActionValue is the enum to capture the four types of actions to perform on a header
@@ -54,10 +55,16 @@ func (cfg *Config) Validate() error {
 		}
 
 		if header.Action != DELETE {
-			if header.FromContext == nil && header.Value == nil {
+			sources := 0
+			for _, source := range []*string{header.Value, header.FromContext, header.FromAuth} {
+				if source != nil {
+					sources++
+				}
+			}
+			if sources == 0 {
 				return errMissingSource
 			}
-			if header.FromContext != nil && header.Value != nil {
+			if sources > 1 {
 				return errConflictingSources
 			}
 		}
@@ -138,6 +138,29 @@ func TestValidateConfig(t *testing.T) {
 			},
 			errConflictingSources,
 		},
+		{
+			"header value from context and auth",
+			[]HeaderConfig{
+				{
+					Key:         stringp("name"),
+					Action:      INSERT,
+					FromContext: stringp("from context"),
+					FromAuth:    stringp("from auth"),
+				},
+			},
+			errConflictingSources,
+		},
+		{
+			"header value from auth",
+			[]HeaderConfig{
+				{
+					Key:      stringp("name"),
+					Action:   INSERT,
+					FromAuth: stringp("tenant.id"),
+				},
+			},
+			nil,
+		},
 		{
 			"header value source is missing",
 			[]HeaderConfig{
@@ -38,6 +38,10 @@ func newHeadersSetterExtension(cfg *Config, logger *zap.Logger) (auth.Client, er
 			s = &source.ContextSource{
 				Key: *header.FromContext,
 			}
+		} else if header.FromAuth != nil {
+			s = &source.AuthSource{
+				Key: *header.FromAuth,
+			}
 		}
 
 		var a action.Action
@@ -0,0 +1,41 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package source // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/headerssetterextension/internal/source"
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/collector/client"
+)
+
+var _ Source = (*AuthSource)(nil)
+
+// AuthSource looks up the header value from the attributes set by the server
+// authenticator of the request.
+type AuthSource struct {
+	Key string
+}
+
+func (ts *AuthSource) Get(ctx context.Context) (string, error) {
+	cl := client.FromContext(ctx)
+	if cl.Auth == nil {
+		return "", nil
+	}
+
+	switch v := cl.Auth.GetAttribute(ts.Key).(type) {
+	case string:
+		return v, nil
+	case []string:
+		if len(v) == 0 {
+			return "", nil
+		}
+		if len(v) > 1 {
+			return "", fmt.Errorf("%d source keys found in the auth attributes, can't determine which one to use", len(v))
+		}
+		return v[0], nil
+	default:
+		return "", nil
+	}
+}
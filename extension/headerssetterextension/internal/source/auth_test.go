@@ -0,0 +1,58 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package source
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/client"
+)
+
+type testAuthData map[string]any
+
+func (a testAuthData) GetAttribute(name string) any {
+	return a[name]
+}
+
+func (a testAuthData) GetAttributeNames() []string {
+	names := make([]string, 0, len(a))
+	for name := range a {
+		names = append(names, name)
+	}
+	return names
+}
+
+func TestAuthSource(t *testing.T) {
+	cl := client.FromContext(context.Background())
+	cl.Auth = testAuthData{"tenant.id": "acme", "groups": []string{"admins", "devs"}, "tier": []string{"gold"}, "count": 1}
+	cl.Metadata = client.NewMetadata(map[string][]string{"tenant.id": {"globex"}})
+	ctx := client.NewContext(context.Background(), cl)
+
+	header, err := (&AuthSource{Key: "tenant.id"}).Get(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "acme", header)
+
+	header, err = (&AuthSource{Key: "tier"}).Get(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "gold", header)
+
+	header, err = (&AuthSource{Key: "groups"}).Get(ctx)
+	assert.Error(t, err)
+	assert.Empty(t, header)
+
+	// attributes that are missing or not strings are not set
+	header, err = (&AuthSource{Key: "count"}).Get(ctx)
+	assert.NoError(t, err)
+	assert.Empty(t, header)
+	header, err = (&AuthSource{Key: "missing"}).Get(ctx)
+	assert.NoError(t, err)
+	assert.Empty(t, header)
+
+	// requests that are not authenticated have no auth attributes
+	header, err = (&AuthSource{Key: "tenant.id"}).Get(client.NewContext(context.Background(), client.Info{}))
+	assert.NoError(t, err)
+	assert.Empty(t, header)
+}
@@ -68,6 +68,9 @@ func (c *Config) Validate() error {
 		if c.HTTPConfig.Status.Enabled && !strings.HasPrefix(c.HTTPConfig.Status.Path, "/") {
 			return errInvalidPath
 		}
+		if c.HTTPConfig.Ready.Enabled && !strings.HasPrefix(c.HTTPConfig.Ready.Path, "/") {
+			return errInvalidPath
+		}
 		if c.HTTPConfig.Config.Enabled && !strings.HasPrefix(c.HTTPConfig.Config.Path, "/") {
 			return errInvalidPath
 		}
@@ -93,6 +93,10 @@ func TestLoadConfig(t *testing.T) {
 						Enabled: true,
 						Path:    "/status",
 					},
+					Ready: http.PathConfig{
+						Enabled: true,
+						Path:    "/ready",
+					},
 					Config: http.PathConfig{
 						Enabled: false,
 						Path:    "/config",
@@ -131,6 +135,10 @@ func TestLoadConfig(t *testing.T) {
 						Enabled: true,
 						Path:    "/health",
 					},
+					Ready: http.PathConfig{
+						Enabled: true,
+						Path:    "/ready",
+					},
 					Config: http.PathConfig{
 						Enabled: true,
 						Path:    "/conf",
@@ -170,6 +178,49 @@ func TestLoadConfig(t *testing.T) {
 			id:          component.NewIDWithName(metadata.Type, "v2noprotocols"),
 			expectedErr: errMissingProtocol,
 		},
+		{
+			id: component.NewIDWithName(metadata.Type, "v2readiness"),
+			expected: &Config{
+				LegacyConfig: http.LegacyConfig{
+					UseV2: true,
+					ServerConfig: confighttp.ServerConfig{
+						Endpoint: localhostgate.EndpointForPort(defaultHTTPPort),
+					},
+					Path: "/",
+				},
+				GRPCConfig: &grpc.Config{
+					ServerConfig: configgrpc.ServerConfig{
+						NetAddr: confignet.AddrConfig{
+							Endpoint:  localhostgate.EndpointForPort(defaultGRPCPort),
+							Transport: "tcp",
+						},
+					},
+				},
+				ComponentHealthConfig: &common.ComponentHealthConfig{
+					IncludeRecoverable: true,
+					RecoveryDuration:   30 * time.Second,
+					Readiness: &common.ReadinessConfig{
+						MetricsEndpoint:             "http://localhost:8888/metrics",
+						Interval:                    5 * time.Second,
+						ExporterQueueUtilization:    0.9,
+						ExporterRefusedItemsRate:    100,
+						ExporterConsecutiveFailures: 3,
+					},
+				},
+			},
+		},
+		{
+			id:          component.NewIDWithName(metadata.Type, "v2readinessnorules"),
+			expectedErr: common.ErrNoReadinessRules,
+		},
+		{
+			id:          component.NewIDWithName(metadata.Type, "v2readinessinvalidutilization"),
+			expectedErr: common.ErrInvalidQueueUtilization,
+		},
+		{
+			id:          component.NewIDWithName(metadata.Type, "v2readinessnotrecoverable"),
+			expectedErr: common.ErrReadinessNotRecoverable,
+		},
 	}
 
 	for _, tt := range tests {
@@ -5,38 +5,101 @@ package healthcheckv2extension // import "github.com/open-telemetry/opentelemetr
 
 import (
 	"context"
+	"errors"
 
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap"
 	"go.opentelemetry.io/collector/extension"
 	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/healthcheckv2extension/internal/common"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/healthcheckv2extension/internal/grpc"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/healthcheckv2extension/internal/http"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/healthcheckv2extension/internal/readiness"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/healthcheckv2extension/internal/status"
 )
 
 type healthCheckExtension struct {
-	config    Config
-	telemetry component.TelemetrySettings
+	config     Config
+	telemetry  component.TelemetrySettings
+	aggregator *status.Aggregator
+	httpServer *http.Server
+	components []component.Component
 }
 
-var _ component.Component = (*healthCheckExtension)(nil)
+var (
+	_ component.Component     = (*healthCheckExtension)(nil)
+	_ extension.StatusWatcher = (*healthCheckExtension)(nil)
+	_ extension.ConfigWatcher = (*healthCheckExtension)(nil)
+)
 
 func newExtension(
 	_ context.Context,
 	config Config,
 	set extension.CreateSettings,
 ) *healthCheckExtension {
-	return &healthCheckExtension{
-		config:    config,
-		telemetry: set.TelemetrySettings,
+	var healthConfig common.ComponentHealthConfig
+	if config.ComponentHealthConfig != nil {
+		healthConfig = *config.ComponentHealthConfig
+	}
+
+	hc := &healthCheckExtension{
+		config:     config,
+		telemetry:  set.TelemetrySettings,
+		aggregator: status.NewAggregator(healthConfig),
+	}
+
+	if !config.UseV2 {
+		return hc
+	}
+
+	if config.HTTPConfig != nil {
+		hc.httpServer = http.NewServer(config.HTTPConfig, set.TelemetrySettings, hc.aggregator)
+		hc.components = append(hc.components, hc.httpServer)
+	}
+	if config.GRPCConfig != nil {
+		hc.components = append(hc.components, grpc.NewServer(config.GRPCConfig, set.TelemetrySettings, hc.aggregator))
+	}
+	if healthConfig.Readiness != nil {
+		hc.components = append(hc.components, readiness.NewMonitor(*healthConfig.Readiness, set.TelemetrySettings, hc.aggregator))
 	}
+
+	return hc
 }
 
 // Start implements the component.Component interface.
-func (hc *healthCheckExtension) Start(context.Context, component.Host) error {
+func (hc *healthCheckExtension) Start(ctx context.Context, host component.Host) error {
 	hc.telemetry.Logger.Debug("Starting health check extension V2", zap.Any("config", hc.config))
 
+	for _, comp := range hc.components {
+		if err := comp.Start(ctx, host); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
 // Shutdown implements the component.Component interface.
-func (hc *healthCheckExtension) Shutdown(context.Context) error {
-	return nil
+func (hc *healthCheckExtension) Shutdown(ctx context.Context) error {
+	var err error
+	for _, comp := range hc.components {
+		err = errors.Join(err, comp.Shutdown(ctx))
+	}
+	return err
+}
+
+// ComponentStatusChanged implements the extension.StatusWatcher interface.
+func (hc *healthCheckExtension) ComponentStatusChanged(
+	source *component.InstanceID,
+	event *component.StatusEvent,
+) {
+	hc.aggregator.RecordStatus(source, event)
+}
+
+// NotifyConfig implements the extension.ConfigWatcher interface.
+func (hc *healthCheckExtension) NotifyConfig(ctx context.Context, conf *confmap.Conf) error {
+	if hc.httpServer == nil || !hc.config.HTTPConfig.Config.Enabled {
+		return nil
+	}
+	return hc.httpServer.NotifyConfig(ctx, conf)
 }
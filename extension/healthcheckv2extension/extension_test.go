@@ -0,0 +1,93 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package healthcheckv2extension
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/extension/extensiontest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/healthcheckv2extension/internal/common"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/testutil"
+)
+
+const saturatedQueueTelemetry = `# TYPE otelcol_exporter_queue_capacity gauge
+otelcol_exporter_queue_capacity{exporter="otlp/staging"} 100
+# TYPE otelcol_exporter_queue_size gauge
+otelcol_exporter_queue_size{exporter="otlp/staging"} 100
+`
+
+func TestReadinessDegradedByExporterQueue(t *testing.T) {
+	telemetry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(saturatedQueueTelemetry))
+	}))
+	defer telemetry.Close()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.UseV2 = true
+	cfg.HTTPConfig.Endpoint = testutil.GetAvailableLocalAddress(t)
+	cfg.GRPCConfig.NetAddr.Endpoint = testutil.GetAvailableLocalAddress(t)
+	cfg.ComponentHealthConfig = &common.ComponentHealthConfig{
+		IncludeRecoverable: true,
+		Readiness: &common.ReadinessConfig{
+			MetricsEndpoint:          telemetry.URL,
+			Interval:                 10 * time.Millisecond,
+			ExporterQueueUtilization: 0.9,
+		},
+	}
+	require.NoError(t, component.ValidateConfig(cfg))
+
+	hc := newExtension(context.Background(), *cfg, extensiontest.NewNopCreateSettings())
+	require.NoError(t, hc.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { require.NoError(t, hc.Shutdown(context.Background())) }()
+
+	pipelines := map[component.ID]struct{}{component.MustNewID("traces"): {}}
+	hc.ComponentStatusChanged(&component.InstanceID{
+		ID:          component.MustNewID("otlp"),
+		Kind:        component.KindReceiver,
+		PipelineIDs: pipelines,
+	}, component.NewStatusEvent(component.StatusOK))
+	hc.ComponentStatusChanged(&component.InstanceID{
+		ID:          component.MustNewIDWithName("otlp", "staging"),
+		Kind:        component.KindExporter,
+		PipelineIDs: pipelines,
+	}, component.NewStatusEvent(component.StatusOK))
+
+	readyURL := "http://" + cfg.HTTPConfig.Endpoint + "/ready?pipeline=traces"
+	assert.Eventually(t, func() bool {
+		resp, err := http.Get(readyURL) //nolint:gosec
+		if err != nil {
+			return false
+		}
+		defer resp.Body.Close()
+		return resp.StatusCode == http.StatusServiceUnavailable
+	}, 5*time.Second, 10*time.Millisecond)
+
+	conn, err := grpc.NewClient(cfg.GRPCConfig.NetAddr.Endpoint, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	resp, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{Service: "traces"})
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, resp.Status)
+}
+
+func TestLegacyModeDoesNotStartServices(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	hc := newExtension(context.Background(), *cfg, extensiontest.NewNopCreateSettings())
+	assert.Empty(t, hc.components)
+	require.NoError(t, hc.Start(context.Background(), componenttest.NewNopHost()))
+	require.NoError(t, hc.Shutdown(context.Background()))
+}
@@ -51,6 +51,10 @@ func createDefaultConfig() component.Config {
 				Enabled: true,
 				Path:    "/status",
 			},
+			Ready: http.PathConfig{
+				Enabled: true,
+				Path:    "/ready",
+			},
 			Config: http.PathConfig{
 				Enabled: false,
 				Path:    "/config",
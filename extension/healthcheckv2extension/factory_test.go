@@ -38,6 +38,10 @@ func TestCreateDefaultConfig(t *testing.T) {
 				Enabled: true,
 				Path:    "/status",
 			},
+			Ready: http.PathConfig{
+				Enabled: true,
+				Path:    "/ready",
+			},
 			Config: http.PathConfig{
 				Enabled: false,
 				Path:    "/config",
@@ -3,14 +3,83 @@
 
 package common // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/healthcheckv2extension/internal/common"
 
-import "time"
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrNoReadinessRules          = errors.New("readiness requires at least one exporter rule")
+	ErrInvalidQueueUtilization   = errors.New("exporter_queue_utilization must be between 0 and 1")
+	ErrInvalidRefusedItemsRate   = errors.New("exporter_refused_items_rate cannot be less than 0")
+	ErrInvalidConsecutiveFailure = errors.New("exporter_consecutive_failures cannot be less than 0")
+	ErrInvalidReadinessInterval  = errors.New("readiness interval cannot be less than 0")
+	ErrReadinessNotRecoverable   = errors.New("readiness requires include_recoverable_errors to be true")
+)
 
 type ComponentHealthConfig struct {
 	IncludePermanent   bool          `mapstructure:"include_permanent_errors"`
 	IncludeRecoverable bool          `mapstructure:"include_recoverable_errors"`
 	RecoveryDuration   time.Duration `mapstructure:"recovery_duration"`
+
+	// Readiness contains rules that mark exporters, and the pipelines they
+	// belong to, as degraded based on the collector's own exporter telemetry.
+	Readiness *ReadinessConfig `mapstructure:"readiness"`
 }
 
 func (c ComponentHealthConfig) Enabled() bool {
 	return c.IncludePermanent || c.IncludeRecoverable
 }
+
+// Validate checks that the readiness rules can affect the health. Degraded
+// exporters are reported as recoverable errors, which are ignored unless
+// IncludeRecoverable is set.
+func (c *ComponentHealthConfig) Validate() error {
+	if c.Readiness != nil && !c.IncludeRecoverable {
+		return ErrReadinessNotRecoverable
+	}
+	return nil
+}
+
+// ReadinessConfig contains the thresholds used to detect exporters that are
+// unable to keep up. A rule with a zero threshold is disabled.
+type ReadinessConfig struct {
+	// MetricsEndpoint is the URL of the collector's internal telemetry in the
+	// Prometheus text format. The default is http://localhost:8888/metrics.
+	MetricsEndpoint string `mapstructure:"metrics_endpoint"`
+
+	// Interval is how often the exporter telemetry is evaluated. The default is 10s.
+	Interval time.Duration `mapstructure:"interval"`
+
+	// ExporterQueueUtilization is the fraction (0, 1] of the sending queue
+	// capacity at which an exporter is considered degraded.
+	ExporterQueueUtilization float64 `mapstructure:"exporter_queue_utilization"`
+
+	// ExporterRefusedItemsRate is the number of items per second that can fail
+	// to be enqueued before an exporter is considered degraded.
+	ExporterRefusedItemsRate float64 `mapstructure:"exporter_refused_items_rate"`
+
+	// ExporterConsecutiveFailures is the number of consecutive evaluations with
+	// failed sends after which an exporter is considered degraded.
+	ExporterConsecutiveFailures int `mapstructure:"exporter_consecutive_failures"`
+}
+
+// Validate checks that the readiness rules are usable.
+func (c *ReadinessConfig) Validate() error {
+	if c.ExporterQueueUtilization < 0 || c.ExporterQueueUtilization > 1 {
+		return ErrInvalidQueueUtilization
+	}
+	if c.ExporterRefusedItemsRate < 0 {
+		return ErrInvalidRefusedItemsRate
+	}
+	if c.ExporterConsecutiveFailures < 0 {
+		return ErrInvalidConsecutiveFailure
+	}
+	if c.Interval < 0 {
+		return ErrInvalidReadinessInterval
+	}
+	if c.ExporterQueueUtilization == 0 && c.ExporterRefusedItemsRate == 0 && c.ExporterConsecutiveFailures == 0 {
+		return ErrNoReadinessRules
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package grpc // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/healthcheckv2extension/internal/grpc"
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	grpcstatus "google.golang.org/grpc/status"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/healthcheckv2extension/internal/status"
+)
+
+// recheckInterval bounds how long a watcher waits before re-evaluating a
+// status whose health depends on the passage of time, such as a recoverable
+// error approaching the end of its recovery duration.
+const recheckInterval = time.Second
+
+// Server implements the grpc_health_v1 health service.
+type Server struct {
+	healthpb.UnimplementedHealthServer
+	config     *Config
+	telemetry  component.TelemetrySettings
+	aggregator *status.Aggregator
+	server     *grpc.Server
+	doneCh     chan struct{}
+	wg         sync.WaitGroup
+}
+
+var _ healthpb.HealthServer = (*Server)(nil)
+
+// NewServer returns a server that reports the statuses recorded by aggregator.
+func NewServer(config *Config, telemetry component.TelemetrySettings, aggregator *status.Aggregator) *Server {
+	return &Server{
+		config:     config,
+		telemetry:  telemetry,
+		aggregator: aggregator,
+		doneCh:     make(chan struct{}),
+	}
+}
+
+// Start starts the health service.
+func (s *Server) Start(ctx context.Context, host component.Host) error {
+	var err error
+	s.server, err = s.config.ToServer(ctx, host, s.telemetry)
+	if err != nil {
+		return err
+	}
+	healthpb.RegisterHealthServer(s.server, s)
+
+	ln, err := s.config.NetAddr.Listen(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		if err := s.server.Serve(ln); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+			s.telemetry.ReportStatus(component.NewFatalErrorEvent(err))
+		}
+	}()
+	return nil
+}
+
+// Shutdown stops the health service and closes all watch streams.
+func (s *Server) Shutdown(context.Context) error {
+	if s.server == nil {
+		return nil
+	}
+	close(s.doneCh)
+	s.server.GracefulStop()
+	s.wg.Wait()
+	return nil
+}
+
+// Check implements the grpc_health_v1 Check RPC.
+func (s *Server) Check(_ context.Context, req *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	st, ok := s.aggregator.AggregateStatus(req.Service, false)
+	if !ok {
+		return nil, grpcstatus.Error(codes.NotFound, "unknown service")
+	}
+	return &healthpb.HealthCheckResponse{Status: servingStatus(st)}, nil
+}
+
+// Watch implements the grpc_health_v1 Watch RPC. The current status is sent
+// immediately and again whenever it changes.
+func (s *Server) Watch(req *healthpb.HealthCheckRequest, stream healthpb.Health_WatchServer) error {
+	notifications, unsubscribe := s.aggregator.Subscribe()
+	defer unsubscribe()
+
+	ticker := time.NewTicker(recheckInterval)
+	defer ticker.Stop()
+
+	sent := false
+	var last healthpb.HealthCheckResponse_ServingStatus
+	for {
+		current := healthpb.HealthCheckResponse_SERVICE_UNKNOWN
+		if st, ok := s.aggregator.AggregateStatus(req.Service, false); ok {
+			current = servingStatus(st)
+		}
+		if !sent || current != last {
+			if err := stream.Send(&healthpb.HealthCheckResponse{Status: current}); err != nil {
+				return grpcstatus.Error(codes.Canceled, "stream has ended")
+			}
+			sent = true
+			last = current
+		}
+
+		select {
+		case <-notifications:
+		case <-ticker.C:
+		case <-s.doneCh:
+			return grpcstatus.Error(codes.Canceled, "server is shutting down")
+		case <-stream.Context().Done():
+			return grpcstatus.Error(codes.Canceled, "stream has ended")
+		}
+	}
+}
+
+func servingStatus(st *status.AggregateStatus) healthpb.HealthCheckResponse_ServingStatus {
+	if st.Healthy {
+		return healthpb.HealthCheckResponse_SERVING
+	}
+	return healthpb.HealthCheckResponse_NOT_SERVING
+}
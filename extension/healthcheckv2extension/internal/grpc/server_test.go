@@ -0,0 +1,107 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package grpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configgrpc"
+	"go.opentelemetry.io/collector/config/confignet"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	grpcstatus "google.golang.org/grpc/status"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/healthcheckv2extension/internal/common"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/healthcheckv2extension/internal/status"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/testutil"
+)
+
+func newTestClient(t *testing.T, aggregator *status.Aggregator) healthpb.HealthClient {
+	endpoint := testutil.GetAvailableLocalAddress(t)
+	server := NewServer(&Config{
+		ServerConfig: configgrpc.ServerConfig{
+			NetAddr: confignet.AddrConfig{Endpoint: endpoint, Transport: "tcp"},
+		},
+	}, componenttest.NewNopTelemetrySettings(), aggregator)
+	require.NoError(t, server.Start(context.Background(), componenttest.NewNopHost()))
+
+	conn, err := grpc.NewClient(endpoint, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, conn.Close())
+		require.NoError(t, server.Shutdown(context.Background()))
+	})
+	return healthpb.NewHealthClient(conn)
+}
+
+func TestCheck(t *testing.T) {
+	aggregator := status.NewAggregator(common.ComponentHealthConfig{IncludeRecoverable: true})
+	client := newTestClient(t, aggregator)
+	ctx := context.Background()
+
+	resp, err := client.Check(ctx, &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, resp.Status)
+
+	exporter := &component.InstanceID{
+		ID:          component.MustNewID("otlp"),
+		Kind:        component.KindExporter,
+		PipelineIDs: map[component.ID]struct{}{component.MustNewID("traces"): {}},
+	}
+	aggregator.RecordStatus(exporter, component.NewStatusEvent(component.StatusOK))
+
+	resp, err = client.Check(ctx, &healthpb.HealthCheckRequest{Service: "traces"})
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.Status)
+
+	aggregator.SetDegraded("otlp", errors.New("queue is full"))
+	resp, err = client.Check(ctx, &healthpb.HealthCheckRequest{Service: "traces"})
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, resp.Status)
+
+	_, err = client.Check(ctx, &healthpb.HealthCheckRequest{Service: "logs"})
+	assert.Equal(t, codes.NotFound, grpcstatus.Code(err))
+}
+
+func TestWatch(t *testing.T) {
+	aggregator := status.NewAggregator(common.ComponentHealthConfig{IncludeRecoverable: true})
+	client := newTestClient(t, aggregator)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := client.Watch(ctx, &healthpb.HealthCheckRequest{Service: "traces"})
+	require.NoError(t, err)
+
+	resp, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVICE_UNKNOWN, resp.Status)
+
+	exporter := &component.InstanceID{
+		ID:          component.MustNewID("otlp"),
+		Kind:        component.KindExporter,
+		PipelineIDs: map[component.ID]struct{}{component.MustNewID("traces"): {}},
+	}
+	aggregator.RecordStatus(exporter, component.NewStatusEvent(component.StatusOK))
+	resp, err = stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.Status)
+
+	aggregator.SetDegraded("otlp", errors.New("queue is full"))
+	resp, err = stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, resp.Status)
+
+	aggregator.ClearDegraded("otlp")
+	resp, err = stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.Status)
+}
@@ -11,6 +11,7 @@ type Config struct {
 
 	Config PathConfig `mapstructure:"config"`
 	Status PathConfig `mapstructure:"status"`
+	Ready  PathConfig `mapstructure:"ready"`
 }
 
 type PathConfig struct {
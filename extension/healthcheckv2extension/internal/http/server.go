@@ -0,0 +1,183 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package http // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/healthcheckv2extension/internal/http"
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/healthcheckv2extension/internal/status"
+)
+
+const (
+	pipelineParam = "pipeline"
+	verboseParam  = "verbose"
+)
+
+// Server serves the collector's status, readiness and configuration over HTTP.
+type Server struct {
+	config     *Config
+	telemetry  component.TelemetrySettings
+	aggregator *status.Aggregator
+	server     *http.Server
+	colconf    atomic.Value
+	wg         sync.WaitGroup
+}
+
+// NewServer returns a server that reports the statuses recorded by aggregator.
+func NewServer(config *Config, telemetry component.TelemetrySettings, aggregator *status.Aggregator) *Server {
+	return &Server{
+		config:     config,
+		telemetry:  telemetry,
+		aggregator: aggregator,
+	}
+}
+
+// Start starts serving the configured endpoints.
+func (s *Server) Start(ctx context.Context, host component.Host) error {
+	mux := http.NewServeMux()
+	if s.config.Status.Enabled {
+		mux.HandleFunc(s.config.Status.Path, s.handleStatus)
+	}
+	if s.config.Ready.Enabled {
+		mux.HandleFunc(s.config.Ready.Path, s.handleReady)
+	}
+	if s.config.Config.Enabled {
+		mux.HandleFunc(s.config.Config.Path, s.handleConfig)
+	}
+
+	var err error
+	s.server, err = s.config.ToServer(ctx, host, s.telemetry, mux)
+	if err != nil {
+		return err
+	}
+	ln, err := s.config.ToListener(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		if err := s.server.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.telemetry.ReportStatus(component.NewFatalErrorEvent(err))
+		}
+	}()
+	return nil
+}
+
+// Shutdown stops the server.
+func (s *Server) Shutdown(ctx context.Context) error {
+	if s.server == nil {
+		return nil
+	}
+	err := s.server.Shutdown(ctx)
+	s.wg.Wait()
+	return err
+}
+
+// NotifyConfig stores the collector configuration for the config endpoint.
+func (s *Server) NotifyConfig(_ context.Context, conf *confmap.Conf) error {
+	confBytes, err := json.Marshal(conf.ToStringMap())
+	if err != nil {
+		s.telemetry.Logger.Warn("could not marshal config", zap.Error(err))
+		return err
+	}
+	s.colconf.Store(confBytes)
+	return nil
+}
+
+type serializableStatus struct {
+	StartTimestamp *time.Time                     `json:"start_time,omitempty"`
+	Healthy        bool                           `json:"healthy"`
+	Status         string                         `json:"status"`
+	Error          string                         `json:"error,omitempty"`
+	Timestamp      time.Time                      `json:"status_time"`
+	Components     map[string]*serializableStatus `json:"components,omitempty"`
+}
+
+func toSerializableStatus(st *status.AggregateStatus) *serializableStatus {
+	s := &serializableStatus{
+		Healthy:   st.Healthy,
+		Status:    st.Status().String(),
+		Timestamp: st.Timestamp(),
+	}
+	if err := st.Err(); err != nil {
+		s.Error = err.Error()
+	}
+	if len(st.ComponentStatusMap) > 0 {
+		s.Components = make(map[string]*serializableStatus, len(st.ComponentStatusMap))
+		for key, cst := range st.ComponentStatusMap {
+			s.Components[key] = toSerializableStatus(cst)
+		}
+	}
+	return s
+}
+
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	st, ok := s.aggregator.AggregateStatus(r.URL.Query().Get(pipelineParam), r.URL.Query().Has(verboseParam))
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	body := toSerializableStatus(st)
+	startTime := s.aggregator.StartTime()
+	body.StartTimestamp = &startTime
+	writeJSON(w, statusCode(st), body)
+}
+
+// handleReady reports readiness with a 503 for every status that should take
+// the collector, or the given pipeline, out of a load balancer rotation.
+func (s *Server) handleReady(w http.ResponseWriter, r *http.Request) {
+	st, ok := s.aggregator.AggregateStatus(r.URL.Query().Get(pipelineParam), r.URL.Query().Has(verboseParam))
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	code := http.StatusOK
+	if !st.Healthy {
+		code = http.StatusServiceUnavailable
+	}
+	writeJSON(w, code, toSerializableStatus(st))
+}
+
+func (s *Server) handleConfig(w http.ResponseWriter, _ *http.Request) {
+	confBytes, _ := s.colconf.Load().([]byte)
+	if confBytes == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(confBytes)
+}
+
+func statusCode(st *status.AggregateStatus) int {
+	if st.Healthy {
+		return http.StatusOK
+	}
+	switch st.Status() {
+	case component.StatusStarting, component.StatusStopping, component.StatusStopped:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func writeJSON(w http.ResponseWriter, code int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(body)
+}
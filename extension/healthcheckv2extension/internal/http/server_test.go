@@ -0,0 +1,127 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/confmap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/healthcheckv2extension/internal/common"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/healthcheckv2extension/internal/status"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/testutil"
+)
+
+func newTestServer(t *testing.T, aggregator *status.Aggregator) (*Server, string) {
+	endpoint := testutil.GetAvailableLocalAddress(t)
+	server := NewServer(&Config{
+		ServerConfig: confighttp.ServerConfig{Endpoint: endpoint},
+		Status:       PathConfig{Enabled: true, Path: "/status"},
+		Ready:        PathConfig{Enabled: true, Path: "/ready"},
+		Config:       PathConfig{Enabled: true, Path: "/config"},
+	}, componenttest.NewNopTelemetrySettings(), aggregator)
+	require.NoError(t, server.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { require.NoError(t, server.Shutdown(context.Background())) })
+	return server, "http://" + endpoint
+}
+
+func get(t *testing.T, url string) (int, map[string]any) {
+	resp, err := http.Get(url) //nolint:gosec
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	var result map[string]any
+	if len(body) > 0 {
+		require.NoError(t, json.Unmarshal(body, &result))
+	}
+	return resp.StatusCode, result
+}
+
+func TestStatus(t *testing.T) {
+	aggregator := status.NewAggregator(common.ComponentHealthConfig{IncludePermanent: true})
+	_, url := newTestServer(t, aggregator)
+
+	code, body := get(t, url+"/status")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "StatusStarting", body["status"])
+	assert.Contains(t, body, "start_time")
+
+	exporter := &component.InstanceID{
+		ID:          component.MustNewID("otlp"),
+		Kind:        component.KindExporter,
+		PipelineIDs: map[component.ID]struct{}{component.MustNewID("traces"): {}},
+	}
+	aggregator.RecordStatus(exporter, component.NewStatusEvent(component.StatusOK))
+
+	code, body = get(t, url+"/status?verbose")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, true, body["healthy"])
+	pipelines := body["components"].(map[string]any)
+	pipeline := pipelines["pipeline:traces"].(map[string]any)
+	assert.Contains(t, pipeline["components"], "exporter:otlp")
+
+	aggregator.RecordStatus(exporter, component.NewPermanentErrorEvent(errors.New("bad config")))
+	code, body = get(t, url+"/status?pipeline=traces")
+	assert.Equal(t, http.StatusInternalServerError, code)
+	assert.Equal(t, "bad config", body["error"])
+	assert.NotContains(t, body, "components")
+
+	code, _ = get(t, url+"/status?pipeline=logs")
+	assert.Equal(t, http.StatusNotFound, code)
+}
+
+func TestReady(t *testing.T) {
+	aggregator := status.NewAggregator(common.ComponentHealthConfig{IncludeRecoverable: true})
+	_, url := newTestServer(t, aggregator)
+
+	exporter := &component.InstanceID{
+		ID:          component.MustNewIDWithName("otlp", "staging"),
+		Kind:        component.KindExporter,
+		PipelineIDs: map[component.ID]struct{}{component.MustNewID("traces"): {}},
+	}
+	aggregator.RecordStatus(exporter, component.NewStatusEvent(component.StatusOK))
+
+	code, body := get(t, url+"/ready")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, true, body["healthy"])
+
+	aggregator.SetDegraded("otlp/staging", errors.New("sending queue utilization 100% exceeds 90%"))
+	code, body = get(t, url+"/ready?pipeline=traces")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "StatusRecoverableError", body["status"])
+	assert.Equal(t, "sending queue utilization 100% exceeds 90%", body["error"])
+
+	code, _ = get(t, url+"/status")
+	assert.Equal(t, http.StatusInternalServerError, code)
+
+	aggregator.ClearDegraded("otlp/staging")
+	code, _ = get(t, url+"/ready")
+	assert.Equal(t, http.StatusOK, code)
+}
+
+func TestConfig(t *testing.T) {
+	server, url := newTestServer(t, status.NewAggregator(common.ComponentHealthConfig{}))
+
+	code, _ := get(t, url+"/config")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+
+	require.NoError(t, server.NotifyConfig(context.Background(), confmap.NewFromStringMap(map[string]any{
+		"exporters": map[string]any{"debug": nil},
+	})))
+	code, body := get(t, url+"/config")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, map[string]any{"exporters": map[string]any{"debug": nil}}, body)
+}
@@ -0,0 +1,277 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package readiness // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/healthcheckv2extension/internal/readiness"
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"go.opentelemetry.io/collector/component"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/healthcheckv2extension/internal/common"
+)
+
+const (
+	defaultMetricsEndpoint = "http://localhost:8888/metrics"
+	defaultInterval        = 10 * time.Second
+
+	exporterLabel = "exporter"
+
+	metricQueueSize     = "otelcol_exporter_queue_size"
+	metricQueueCapacity = "otelcol_exporter_queue_capacity"
+)
+
+var (
+	enqueueFailedMetrics = []string{
+		"otelcol_exporter_enqueue_failed_spans",
+		"otelcol_exporter_enqueue_failed_metric_points",
+		"otelcol_exporter_enqueue_failed_log_records",
+	}
+	sendFailedMetrics = []string{
+		"otelcol_exporter_send_failed_spans",
+		"otelcol_exporter_send_failed_metric_points",
+		"otelcol_exporter_send_failed_log_records",
+	}
+)
+
+// Recorder receives the outcome of the readiness rules for each exporter.
+type Recorder interface {
+	SetDegraded(exporter string, err error)
+	ClearDegraded(exporter string)
+}
+
+// exporterSample contains the telemetry of a single exporter at one point in time.
+type exporterSample struct {
+	queueSize     float64
+	queueCapacity float64
+	enqueueFailed float64
+	sendFailed    float64
+}
+
+// Monitor periodically scrapes the collector's internal telemetry and applies
+// the readiness rules to every exporter found in it.
+type Monitor struct {
+	config    common.ReadinessConfig
+	telemetry component.TelemetrySettings
+	recorder  Recorder
+	client    *http.Client
+
+	// failing is set while the telemetry can't be scraped, during which the
+	// monitor reports itself as a recoverable error.
+	failing bool
+
+	previous     map[string]exporterSample
+	previousTime time.Time
+	failures     map[string]int
+	degraded     map[string]bool
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewMonitor creates a monitor that reports to recorder.
+func NewMonitor(config common.ReadinessConfig, settings component.TelemetrySettings, recorder Recorder) *Monitor {
+	if config.MetricsEndpoint == "" {
+		config.MetricsEndpoint = defaultMetricsEndpoint
+	}
+	if config.Interval == 0 {
+		config.Interval = defaultInterval
+	}
+	return &Monitor{
+		config:    config,
+		telemetry: settings,
+		recorder:  recorder,
+		client:    &http.Client{Timeout: config.Interval},
+		failures:  make(map[string]int),
+		degraded:  make(map[string]bool),
+	}
+}
+
+// Start begins evaluating the readiness rules in the background.
+func (m *Monitor) Start(_ context.Context, _ component.Host) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancel = cancel
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		ticker := time.NewTicker(m.config.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				m.reportCheck(m.check(ctx))
+			}
+		}
+	}()
+	return nil
+}
+
+// Shutdown stops the monitor.
+func (m *Monitor) Shutdown(context.Context) error {
+	if m.cancel != nil {
+		m.cancel()
+	}
+	m.wg.Wait()
+	return nil
+}
+
+// reportCheck reports the outcome of a check. The readiness of the exporters
+// is unknown while the telemetry can't be scraped, so the extension reports
+// a recoverable error until a check succeeds again.
+func (m *Monitor) reportCheck(err error) {
+	if err != nil {
+		m.telemetry.Logger.Warn("Failed to evaluate exporter readiness", zap.Error(err))
+		if !m.failing {
+			m.failing = true
+			m.telemetry.ReportStatus(component.NewRecoverableErrorEvent(fmt.Errorf("failed to evaluate exporter readiness: %w", err)))
+		}
+		return
+	}
+	if m.failing {
+		m.failing = false
+		m.telemetry.ReportStatus(component.NewStatusEvent(component.StatusOK))
+	}
+}
+
+func (m *Monitor) check(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, m.config.MetricsEndpoint, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code %d from %s", resp.StatusCode, m.config.MetricsEndpoint)
+	}
+
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(resp.Body)
+	if err != nil {
+		return err
+	}
+	m.evaluate(samplesFromFamilies(families), time.Now())
+	return nil
+}
+
+// evaluate applies the readiness rules to the samples and reports every
+// exporter whose state changed.
+func (m *Monitor) evaluate(samples map[string]exporterSample, now time.Time) {
+	elapsed := now.Sub(m.previousTime).Seconds()
+	for exporter, sample := range samples {
+		prev, hasPrev := m.previous[exporter]
+		if hasPrev && sample.sendFailed > prev.sendFailed {
+			m.failures[exporter]++
+		} else {
+			m.failures[exporter] = 0
+		}
+
+		reason := m.reason(exporter, sample, prev, hasPrev, elapsed)
+		switch {
+		case reason != nil:
+			m.degraded[exporter] = true
+			m.recorder.SetDegraded(exporter, reason)
+		case m.degraded[exporter]:
+			delete(m.degraded, exporter)
+			m.recorder.ClearDegraded(exporter)
+		}
+	}
+
+	for exporter := range m.degraded {
+		if _, ok := samples[exporter]; !ok {
+			delete(m.degraded, exporter)
+			m.recorder.ClearDegraded(exporter)
+		}
+	}
+	for exporter := range m.failures {
+		if _, ok := samples[exporter]; !ok {
+			delete(m.failures, exporter)
+		}
+	}
+
+	m.previous = samples
+	m.previousTime = now
+}
+
+func (m *Monitor) reason(exporter string, sample, prev exporterSample, hasPrev bool, elapsed float64) error {
+	if threshold := m.config.ExporterQueueUtilization; threshold > 0 && sample.queueCapacity > 0 {
+		if utilization := sample.queueSize / sample.queueCapacity; utilization >= threshold {
+			return fmt.Errorf("sending queue utilization %.0f%% exceeds %.0f%%", utilization*100, threshold*100)
+		}
+	}
+	if threshold := m.config.ExporterRefusedItemsRate; threshold > 0 && hasPrev && elapsed > 0 {
+		if rate := (sample.enqueueFailed - prev.enqueueFailed) / elapsed; rate > threshold {
+			return fmt.Errorf("refused items rate %.2f/s exceeds %.2f/s", rate, threshold)
+		}
+	}
+	if threshold := m.config.ExporterConsecutiveFailures; threshold > 0 && m.failures[exporter] >= threshold {
+		return fmt.Errorf("exports failed %d consecutive times", m.failures[exporter])
+	}
+	return nil
+}
+
+// samplesFromFamilies extracts exporter telemetry from the scraped metric
+// families. Counters are accepted with or without the _total suffix, and
+// gauges reported by several instances of the same exporter are combined by
+// taking the fullest queue.
+func samplesFromFamilies(families map[string]*dto.MetricFamily) map[string]exporterSample {
+	samples := make(map[string]exporterSample)
+	for name, family := range families {
+		name = strings.TrimSuffix(name, "_total")
+		for _, metric := range family.GetMetric() {
+			exporter := labelValue(metric, exporterLabel)
+			if exporter == "" {
+				continue
+			}
+			value := metricValue(metric)
+			sample := samples[exporter]
+			switch {
+			case name == metricQueueSize:
+				sample.queueSize = max(sample.queueSize, value)
+			case name == metricQueueCapacity:
+				sample.queueCapacity = max(sample.queueCapacity, value)
+			case slices.Contains(enqueueFailedMetrics, name):
+				sample.enqueueFailed += value
+			case slices.Contains(sendFailedMetrics, name):
+				sample.sendFailed += value
+			default:
+				continue
+			}
+			samples[exporter] = sample
+		}
+	}
+	return samples
+}
+
+func labelValue(metric *dto.Metric, name string) string {
+	for _, label := range metric.GetLabel() {
+		if label.GetName() == name {
+			return label.GetValue()
+		}
+	}
+	return ""
+}
+
+func metricValue(metric *dto.Metric) float64 {
+	switch {
+	case metric.Counter != nil:
+		return metric.GetCounter().GetValue()
+	case metric.Gauge != nil:
+		return metric.GetGauge().GetValue()
+	default:
+		return metric.GetUntyped().GetValue()
+	}
+}
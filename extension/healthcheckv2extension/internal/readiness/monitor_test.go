@@ -0,0 +1,168 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package readiness
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/healthcheckv2extension/internal/common"
+)
+
+type recorder struct {
+	degraded map[string]error
+	cleared  []string
+}
+
+func newRecorder() *recorder {
+	return &recorder{degraded: make(map[string]error)}
+}
+
+func (r *recorder) SetDegraded(exporter string, err error) {
+	r.degraded[exporter] = err
+}
+
+func (r *recorder) ClearDegraded(exporter string) {
+	delete(r.degraded, exporter)
+	r.cleared = append(r.cleared, exporter)
+}
+
+const telemetry = `# TYPE otelcol_exporter_queue_capacity gauge
+otelcol_exporter_queue_capacity{exporter="otlp/full",service_name="otelcol"} 1000
+otelcol_exporter_queue_capacity{exporter="otlp/ok",service_name="otelcol"} 1000
+# TYPE otelcol_exporter_queue_size gauge
+otelcol_exporter_queue_size{exporter="otlp/full",service_name="otelcol"} 1000
+otelcol_exporter_queue_size{exporter="otlp/ok",service_name="otelcol"} 10
+# TYPE otelcol_exporter_enqueue_failed_spans counter
+otelcol_exporter_enqueue_failed_spans{exporter="otlp/ok",service_name="otelcol"} %d
+# TYPE otelcol_exporter_send_failed_log_records_total counter
+otelcol_exporter_send_failed_log_records_total{exporter="otlp/ok",service_name="otelcol"} 5
+# TYPE otelcol_receiver_refused_spans counter
+otelcol_receiver_refused_spans{receiver="otlp",service_name="otelcol"} 100
+`
+
+func TestSamplesFromTelemetry(t *testing.T) {
+	var refused int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintf(w, telemetry, refused)
+	}))
+	defer srv.Close()
+
+	rec := newRecorder()
+	m := NewMonitor(common.ReadinessConfig{
+		MetricsEndpoint:          srv.URL,
+		ExporterQueueUtilization: 0.9,
+		ExporterRefusedItemsRate: 1,
+	}, componenttest.NewNopTelemetrySettings(), rec)
+
+	require.NoError(t, m.check(context.Background()))
+	assert.Equal(t, exporterSample{queueSize: 1000, queueCapacity: 1000}, m.previous["otlp/full"])
+	assert.Equal(t, exporterSample{queueSize: 10, queueCapacity: 1000, sendFailed: 5}, m.previous["otlp/ok"])
+	assert.Len(t, m.previous, 2)
+	assert.EqualError(t, rec.degraded["otlp/full"], "sending queue utilization 100% exceeds 90%")
+	assert.NotContains(t, rec.degraded, "otlp/ok")
+
+	// Backdate the previous scrape so that the rate is computed over a known period.
+	m.previousTime = time.Now().Add(-10 * time.Second)
+	refused = 1000
+	require.NoError(t, m.check(context.Background()))
+	require.Contains(t, rec.degraded, "otlp/ok")
+	assert.Contains(t, rec.degraded["otlp/ok"].Error(), "refused items rate")
+}
+
+func TestEvaluate(t *testing.T) {
+	rec := newRecorder()
+	m := NewMonitor(common.ReadinessConfig{ExporterConsecutiveFailures: 2}, componenttest.NewNopTelemetrySettings(), rec)
+
+	now := time.Now()
+	m.evaluate(map[string]exporterSample{"otlp": {sendFailed: 1}}, now)
+	assert.Empty(t, rec.degraded)
+
+	m.evaluate(map[string]exporterSample{"otlp": {sendFailed: 2}}, now.Add(time.Second))
+	assert.Empty(t, rec.degraded)
+
+	m.evaluate(map[string]exporterSample{"otlp": {sendFailed: 3}}, now.Add(2*time.Second))
+	assert.EqualError(t, rec.degraded["otlp"], "exports failed 2 consecutive times")
+
+	// An evaluation without new failures resets the count and clears the degradation.
+	m.evaluate(map[string]exporterSample{"otlp": {sendFailed: 3}}, now.Add(3*time.Second))
+	assert.Empty(t, rec.degraded)
+	assert.Equal(t, []string{"otlp"}, rec.cleared)
+
+	m.evaluate(map[string]exporterSample{"otlp": {sendFailed: 4}}, now.Add(4*time.Second))
+	m.evaluate(map[string]exporterSample{"otlp": {sendFailed: 5}}, now.Add(5*time.Second))
+	assert.Contains(t, rec.degraded, "otlp")
+
+	// Exporters that disappear from the telemetry are no longer degraded.
+	m.evaluate(map[string]exporterSample{}, now.Add(6*time.Second))
+	assert.Empty(t, rec.degraded)
+	assert.Empty(t, m.failures)
+}
+
+func TestReportCheck(t *testing.T) {
+	var statuses []*component.StatusEvent
+	settings := componenttest.NewNopTelemetrySettings()
+	settings.ReportStatus = func(ev *component.StatusEvent) {
+		statuses = append(statuses, ev)
+	}
+	m := NewMonitor(common.ReadinessConfig{}, settings, newRecorder())
+
+	// Failures are reported once, and the recovery once they stop.
+	m.reportCheck(errors.New("connection refused"))
+	m.reportCheck(errors.New("connection refused"))
+	require.Len(t, statuses, 1)
+	assert.Equal(t, component.StatusRecoverableError, statuses[0].Status())
+	assert.EqualError(t, statuses[0].Err(), "failed to evaluate exporter readiness: connection refused")
+
+	m.reportCheck(nil)
+	m.reportCheck(nil)
+	require.Len(t, statuses, 2)
+	assert.Equal(t, component.StatusOK, statuses[1].Status())
+}
+
+func TestMonitorLifecycle(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintf(w, telemetry, 0)
+	}))
+	defer srv.Close()
+
+	rec := &syncRecorder{degraded: make(chan string, 10)}
+	m := NewMonitor(common.ReadinessConfig{
+		MetricsEndpoint:          srv.URL,
+		Interval:                 10 * time.Millisecond,
+		ExporterQueueUtilization: 0.5,
+	}, componenttest.NewNopTelemetrySettings(), rec)
+
+	require.NoError(t, m.Start(context.Background(), componenttest.NewNopHost()))
+	select {
+	case exporter := <-rec.degraded:
+		assert.Equal(t, "otlp/full", exporter)
+	case <-time.After(5 * time.Second):
+		t.Fatal("exporter was not reported as degraded")
+	}
+	require.NoError(t, m.Shutdown(context.Background()))
+}
+
+type syncRecorder struct {
+	degraded chan string
+}
+
+func (r *syncRecorder) SetDegraded(exporter string, _ error) {
+	select {
+	case r.degraded <- exporter:
+	default:
+	}
+}
+
+func (r *syncRecorder) ClearDegraded(string) {}
@@ -0,0 +1,296 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package status // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/healthcheckv2extension/internal/status"
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/healthcheckv2extension/internal/common"
+)
+
+const (
+	// ScopeAll is the scope of the overall collector status.
+	ScopeAll = ""
+
+	extensionsKey  = "extensions"
+	pipelinePrefix = "pipeline:"
+)
+
+// AggregateStatus is the status of a collector, pipeline or component along
+// with, when requested, the statuses it was aggregated from.
+type AggregateStatus struct {
+	*component.StatusEvent
+
+	// Healthy reports whether the status is healthy according to the
+	// component health configuration.
+	Healthy bool
+
+	// ComponentStatusMap contains the statuses this status was aggregated
+	// from. It is only populated for verbose requests.
+	ComponentStatusMap map[string]*AggregateStatus
+}
+
+type componentStatus struct {
+	kind     component.Kind
+	id       component.ID
+	reported *component.StatusEvent
+	degraded *component.StatusEvent
+}
+
+// event returns the effective status of the component. A degradation only
+// takes effect while the component itself reports StatusOK.
+func (cs *componentStatus) event() *component.StatusEvent {
+	if cs.degraded != nil && cs.reported.Status() == component.StatusOK {
+		return cs.degraded
+	}
+	return cs.reported
+}
+
+// Aggregator records component status events and aggregates them into
+// per-pipeline and overall collector status.
+type Aggregator struct {
+	mu            sync.RWMutex
+	config        common.ComponentHealthConfig
+	startTime     time.Time
+	groups        map[string]map[string]*componentStatus
+	degradations  map[string]*component.StatusEvent
+	subscriptions map[chan struct{}]struct{}
+}
+
+// NewAggregator returns an aggregator that evaluates health using config.
+func NewAggregator(config common.ComponentHealthConfig) *Aggregator {
+	return &Aggregator{
+		config:        config,
+		startTime:     time.Now(),
+		groups:        make(map[string]map[string]*componentStatus),
+		degradations:  make(map[string]*component.StatusEvent),
+		subscriptions: make(map[chan struct{}]struct{}),
+	}
+}
+
+// StartTime returns the time the aggregator was created.
+func (a *Aggregator) StartTime() time.Time {
+	return a.startTime
+}
+
+// RecordStatus records the status event of the source component.
+func (a *Aggregator) RecordStatus(source *component.InstanceID, event *component.StatusEvent) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	key := componentKey(source.Kind, source.ID)
+	for _, group := range groupKeys(source) {
+		components, ok := a.groups[group]
+		if !ok {
+			components = make(map[string]*componentStatus)
+			a.groups[group] = components
+		}
+		cs, ok := components[key]
+		if !ok {
+			cs = &componentStatus{kind: source.Kind, id: source.ID}
+			components[key] = cs
+		}
+		cs.reported = event
+		if source.Kind == component.KindExporter {
+			cs.degraded = a.degradations[source.ID.String()]
+		}
+	}
+	a.notify()
+}
+
+// SetDegraded marks every instance of the named exporter as degraded with a
+// recoverable error. Calling it again for an exporter that is already
+// degraded keeps the original degradation time so that the recovery duration
+// is measured from when the exporter first became degraded.
+func (a *Aggregator) SetDegraded(exporter string, err error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if _, ok := a.degradations[exporter]; ok {
+		return
+	}
+	a.degradations[exporter] = component.NewRecoverableErrorEvent(err)
+	a.updateDegradation(exporter)
+}
+
+// ClearDegraded removes the degradation of the named exporter.
+func (a *Aggregator) ClearDegraded(exporter string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if _, ok := a.degradations[exporter]; !ok {
+		return
+	}
+	delete(a.degradations, exporter)
+	a.updateDegradation(exporter)
+}
+
+func (a *Aggregator) updateDegradation(exporter string) {
+	for _, components := range a.groups {
+		for _, cs := range components {
+			if cs.kind == component.KindExporter && cs.id.String() == exporter {
+				cs.degraded = a.degradations[exporter]
+			}
+		}
+	}
+	a.notify()
+}
+
+// AggregateStatus returns the status of the collector when scope is ScopeAll,
+// or of the named pipeline otherwise. The returned boolean is false when the
+// pipeline is unknown.
+func (a *Aggregator) AggregateStatus(scope string, verbose bool) (*AggregateStatus, bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	now := time.Now()
+	if scope != ScopeAll {
+		components, ok := a.groups[pipelinePrefix+scope]
+		if !ok {
+			return nil, false
+		}
+		return a.aggregateGroup(components, verbose, now), true
+	}
+
+	if len(a.groups) == 0 {
+		ev := component.NewStatusEvent(component.StatusStarting)
+		return &AggregateStatus{StatusEvent: ev, Healthy: a.healthy(ev, now)}, true
+	}
+
+	events := make(map[string]*component.StatusEvent, len(a.groups))
+	statuses := make(map[string]*AggregateStatus, len(a.groups))
+	for key, components := range a.groups {
+		st := a.aggregateGroup(components, verbose, now)
+		events[key] = st.StatusEvent
+		statuses[key] = st
+	}
+
+	ev := a.aggregateEvents(events)
+	st := &AggregateStatus{StatusEvent: ev, Healthy: a.healthy(ev, now)}
+	if verbose {
+		st.ComponentStatusMap = statuses
+	}
+	return st, true
+}
+
+func (a *Aggregator) aggregateGroup(components map[string]*componentStatus, verbose bool, now time.Time) *AggregateStatus {
+	events := make(map[string]*component.StatusEvent, len(components))
+	for key, cs := range components {
+		events[key] = cs.event()
+	}
+
+	ev := a.aggregateEvents(events)
+	st := &AggregateStatus{StatusEvent: ev, Healthy: a.healthy(ev, now)}
+	if verbose {
+		st.ComponentStatusMap = make(map[string]*AggregateStatus, len(events))
+		for key, cev := range events {
+			st.ComponentStatusMap[key] = &AggregateStatus{StatusEvent: cev, Healthy: a.healthy(cev, now)}
+		}
+	}
+	return st
+}
+
+// aggregateEvents follows the rules of component.AggregateStatusEvent with two
+// exceptions. A recoverable error is represented by the earliest recoverable
+// error so that the recovery duration is measured from when the problem
+// started, and recoverable errors take precedence over permanent errors when
+// only recoverable errors are included in the health evaluation.
+func (a *Aggregator) aggregateEvents(events map[string]*component.StatusEvent) *component.StatusEvent {
+	ev := component.AggregateStatusEvent(events)
+	switch ev.Status() {
+	case component.StatusRecoverableError:
+		return earliest(events, component.StatusRecoverableError)
+	case component.StatusPermanentError:
+		if a.config.IncludeRecoverable && !a.config.IncludePermanent {
+			if rev := earliest(events, component.StatusRecoverableError); rev != nil {
+				return rev
+			}
+		}
+	}
+	return ev
+}
+
+func (a *Aggregator) healthy(ev *component.StatusEvent, now time.Time) bool {
+	switch ev.Status() {
+	case component.StatusOK:
+		return true
+	case component.StatusRecoverableError:
+		return !a.config.IncludeRecoverable || now.Sub(ev.Timestamp()) < a.config.RecoveryDuration
+	case component.StatusPermanentError:
+		return !a.config.IncludePermanent
+	default:
+		return false
+	}
+}
+
+// Subscribe returns a channel that receives a notification whenever a status
+// changes, and a function to cancel the subscription. Notifications are
+// coalesced, so subscribers should call AggregateStatus after each one.
+func (a *Aggregator) Subscribe() (<-chan struct{}, func()) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	ch := make(chan struct{}, 1)
+	a.subscriptions[ch] = struct{}{}
+	return ch, func() {
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		delete(a.subscriptions, ch)
+	}
+}
+
+func (a *Aggregator) notify() {
+	for ch := range a.subscriptions {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func earliest(events map[string]*component.StatusEvent, status component.Status) *component.StatusEvent {
+	var result *component.StatusEvent
+	for _, ev := range events {
+		if ev.Status() == status && (result == nil || ev.Timestamp().Before(result.Timestamp())) {
+			result = ev
+		}
+	}
+	return result
+}
+
+func componentKey(kind component.Kind, id component.ID) string {
+	return fmt.Sprintf("%s:%s", kindString(kind), id)
+}
+
+func groupKeys(source *component.InstanceID) []string {
+	if source.Kind == component.KindExtension || len(source.PipelineIDs) == 0 {
+		return []string{extensionsKey}
+	}
+	keys := make([]string, 0, len(source.PipelineIDs))
+	for pipelineID := range source.PipelineIDs {
+		keys = append(keys, pipelinePrefix+pipelineID.String())
+	}
+	return keys
+}
+
+func kindString(kind component.Kind) string {
+	switch kind {
+	case component.KindReceiver:
+		return "receiver"
+	case component.KindProcessor:
+		return "processor"
+	case component.KindExporter:
+		return "exporter"
+	case component.KindExtension:
+		return "extension"
+	case component.KindConnector:
+		return "connector"
+	default:
+		return "unknown"
+	}
+}
@@ -0,0 +1,194 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package status
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/healthcheckv2extension/internal/common"
+)
+
+var (
+	tracesID  = component.MustNewID("traces")
+	metricsID = component.MustNewID("metrics")
+)
+
+func newInstanceID(kind component.Kind, id string, pipelines ...component.ID) *component.InstanceID {
+	instanceID := &component.InstanceID{
+		ID:          component.MustNewIDWithName("otlp", id),
+		Kind:        kind,
+		PipelineIDs: make(map[component.ID]struct{}),
+	}
+	for _, pipelineID := range pipelines {
+		instanceID.PipelineIDs[pipelineID] = struct{}{}
+	}
+	return instanceID
+}
+
+func TestAggregateStatus(t *testing.T) {
+	agg := NewAggregator(common.ComponentHealthConfig{})
+
+	st, ok := agg.AggregateStatus(ScopeAll, false)
+	require.True(t, ok)
+	assert.Equal(t, component.StatusStarting, st.Status())
+	assert.False(t, st.Healthy)
+
+	receiver := newInstanceID(component.KindReceiver, "in", tracesID, metricsID)
+	exporter := newInstanceID(component.KindExporter, "out", tracesID)
+	agg.RecordStatus(receiver, component.NewStatusEvent(component.StatusOK))
+	agg.RecordStatus(exporter, component.NewStatusEvent(component.StatusOK))
+
+	st, ok = agg.AggregateStatus(ScopeAll, true)
+	require.True(t, ok)
+	assert.Equal(t, component.StatusOK, st.Status())
+	assert.True(t, st.Healthy)
+	require.Contains(t, st.ComponentStatusMap, "pipeline:traces")
+	require.Contains(t, st.ComponentStatusMap, "pipeline:metrics")
+	assert.Len(t, st.ComponentStatusMap["pipeline:traces"].ComponentStatusMap, 2)
+	assert.Len(t, st.ComponentStatusMap["pipeline:metrics"].ComponentStatusMap, 1)
+
+	st, ok = agg.AggregateStatus("traces", false)
+	require.True(t, ok)
+	assert.Equal(t, component.StatusOK, st.Status())
+	assert.Nil(t, st.ComponentStatusMap)
+
+	_, ok = agg.AggregateStatus("logs", false)
+	assert.False(t, ok)
+}
+
+func TestAggregateStatusHealth(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		config  common.ComponentHealthConfig
+		event   *component.StatusEvent
+		healthy bool
+	}{
+		{
+			name:    "recoverable not included",
+			event:   component.NewRecoverableErrorEvent(errors.New("boom")),
+			healthy: true,
+		},
+		{
+			name:    "recoverable within recovery duration",
+			config:  common.ComponentHealthConfig{IncludeRecoverable: true, RecoveryDuration: time.Hour},
+			event:   component.NewRecoverableErrorEvent(errors.New("boom")),
+			healthy: true,
+		},
+		{
+			name:    "recoverable after recovery duration",
+			config:  common.ComponentHealthConfig{IncludeRecoverable: true},
+			event:   component.NewRecoverableErrorEvent(errors.New("boom")),
+			healthy: false,
+		},
+		{
+			name:    "permanent not included",
+			event:   component.NewPermanentErrorEvent(errors.New("boom")),
+			healthy: true,
+		},
+		{
+			name:    "permanent included",
+			config:  common.ComponentHealthConfig{IncludePermanent: true},
+			event:   component.NewPermanentErrorEvent(errors.New("boom")),
+			healthy: false,
+		},
+		{
+			name:    "fatal",
+			event:   component.NewFatalErrorEvent(errors.New("boom")),
+			healthy: false,
+		},
+		{
+			name:    "stopping",
+			event:   component.NewStatusEvent(component.StatusStopping),
+			healthy: false,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			agg := NewAggregator(tt.config)
+			agg.RecordStatus(newInstanceID(component.KindExporter, "out", tracesID), tt.event)
+
+			st, ok := agg.AggregateStatus(ScopeAll, false)
+			require.True(t, ok)
+			assert.Equal(t, tt.event.Status(), st.Status())
+			assert.Equal(t, tt.healthy, st.Healthy)
+		})
+	}
+}
+
+func TestAggregateStatusPrecedence(t *testing.T) {
+	permanent := component.NewPermanentErrorEvent(errors.New("permanent"))
+	recoverable := component.NewRecoverableErrorEvent(errors.New("recoverable"))
+
+	agg := NewAggregator(common.ComponentHealthConfig{IncludeRecoverable: true, RecoveryDuration: time.Hour})
+	agg.RecordStatus(newInstanceID(component.KindReceiver, "in", tracesID), permanent)
+	agg.RecordStatus(newInstanceID(component.KindExporter, "out", tracesID), recoverable)
+
+	st, ok := agg.AggregateStatus("traces", false)
+	require.True(t, ok)
+	assert.Equal(t, recoverable, st.StatusEvent)
+
+	agg = NewAggregator(common.ComponentHealthConfig{IncludePermanent: true, IncludeRecoverable: true})
+	agg.RecordStatus(newInstanceID(component.KindReceiver, "in", tracesID), permanent)
+	agg.RecordStatus(newInstanceID(component.KindExporter, "out", tracesID), recoverable)
+
+	st, ok = agg.AggregateStatus("traces", false)
+	require.True(t, ok)
+	assert.Equal(t, component.StatusPermanentError, st.Status())
+}
+
+func TestDegraded(t *testing.T) {
+	agg := NewAggregator(common.ComponentHealthConfig{IncludeRecoverable: true})
+	notifications, unsubscribe := agg.Subscribe()
+	defer unsubscribe()
+
+	exporter := newInstanceID(component.KindExporter, "out", tracesID)
+	agg.RecordStatus(newInstanceID(component.KindReceiver, "in", tracesID, metricsID), component.NewStatusEvent(component.StatusOK))
+	agg.RecordStatus(exporter, component.NewStatusEvent(component.StatusOK))
+	<-notifications
+
+	agg.SetDegraded("otlp/out", errors.New("queue is full"))
+	<-notifications
+
+	st, ok := agg.AggregateStatus("traces", true)
+	require.True(t, ok)
+	assert.Equal(t, component.StatusRecoverableError, st.Status())
+	assert.EqualError(t, st.Err(), "queue is full")
+	assert.False(t, st.Healthy)
+	assert.Equal(t, component.StatusRecoverableError, st.ComponentStatusMap["exporter:otlp/out"].Status())
+	assert.Equal(t, component.StatusOK, st.ComponentStatusMap["receiver:otlp/in"].Status())
+
+	st, ok = agg.AggregateStatus("metrics", false)
+	require.True(t, ok)
+	assert.Equal(t, component.StatusOK, st.Status())
+
+	// A status reported by the exporter itself takes precedence over the degradation.
+	agg.RecordStatus(exporter, component.NewPermanentErrorEvent(errors.New("bad config")))
+	st, _ = agg.AggregateStatus("traces", false)
+	assert.Equal(t, component.StatusPermanentError, st.Status())
+
+	agg.RecordStatus(exporter, component.NewStatusEvent(component.StatusOK))
+	st, _ = agg.AggregateStatus("traces", false)
+	assert.Equal(t, component.StatusRecoverableError, st.Status())
+
+	agg.ClearDegraded("otlp/out")
+	st, _ = agg.AggregateStatus(ScopeAll, false)
+	assert.Equal(t, component.StatusOK, st.Status())
+	assert.True(t, st.Healthy)
+}
+
+func TestDegradedBeforeReported(t *testing.T) {
+	agg := NewAggregator(common.ComponentHealthConfig{})
+	agg.SetDegraded("otlp/out", errors.New("queue is full"))
+	agg.RecordStatus(newInstanceID(component.KindExporter, "out", tracesID), component.NewStatusEvent(component.StatusOK))
+
+	st, ok := agg.AggregateStatus("traces", false)
+	require.True(t, ok)
+	assert.Equal(t, component.StatusRecoverableError, st.Status())
+	assert.True(t, st.Healthy)
+}
@@ -10,24 +10,35 @@ import (
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configgrpc"
 	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/confmap"
 )
 
 var (
-	errTooManySources     = errors.New("too many sources specified, has to be either 'file' or 'remote'")
-	errNoSources          = errors.New("no sources specified, has to be either 'file' or 'remote'")
+	errTooManySources     = errors.New("too many sources specified, has to be one of 'file', 'remote' or 'adaptive'")
+	errNoSources          = errors.New("no sources specified, has to be one of 'file', 'remote' or 'adaptive'")
 	errAtLeastOneProtocol = errors.New("no protocols selected to serve the strategies, use 'grpc', 'http', or both")
 )
 
+const adaptiveKey = "source::adaptive"
+
 // Config has the configuration for the extension enabling the health check
 // extension, used to report the health status of the service.
 type Config struct {
 	HTTPServerConfig *confighttp.ServerConfig `mapstructure:"http"`
 	GRPCServerConfig *configgrpc.ServerConfig `mapstructure:"grpc"`
 
-	// Source configures the source for the strategies file. One of `remote` or `file` has to be specified.
+	// Source configures the source for the strategies file. One of `remote`, `file` or `adaptive` has to be specified.
 	Source Source `mapstructure:"source"`
 }
 
+// Unmarshal a confmap.Conf into the config struct, applying the adaptive defaults when the source is configured.
+func (cfg *Config) Unmarshal(conf *confmap.Conf) error {
+	if conf.IsSet(adaptiveKey) && cfg.Source.Adaptive == nil {
+		cfg.Source.Adaptive = createDefaultAdaptiveConfig()
+	}
+	return conf.Unmarshal(cfg)
+}
+
 type Source struct {
 	// Remote defines the remote location for the file
 	Remote *configgrpc.ClientConfig `mapstructure:"remote"`
@@ -37,6 +48,46 @@ type Source struct {
 
 	// ReloadInterval determines the periodicity to refresh the strategies
 	ReloadInterval time.Duration `mapstructure:"reload_interval"`
+
+	// Adaptive computes the strategies from the throughput recorded by other components
+	Adaptive *AdaptiveConfig `mapstructure:"adaptive"`
+}
+
+// AdaptiveConfig configures the computation of per-operation sampling probabilities from
+// the observed throughput, following Jaeger's adaptive sampling.
+type AdaptiveConfig struct {
+	// StorageID is the storage extension used to persist the throughput and the probabilities
+	// across restarts. They are only kept in memory when not set.
+	StorageID *component.ID `mapstructure:"storage"`
+
+	// TargetSamplesPerSecond is the target rate of sampled traces per operation.
+	TargetSamplesPerSecond float64 `mapstructure:"target_samples_per_second"`
+
+	// DeltaTolerance is the deviation from the target rate, as a ratio, under which the
+	// probability of an operation is not changed.
+	DeltaTolerance float64 `mapstructure:"delta_tolerance"`
+
+	// CalculationInterval determines how often the probabilities are computed.
+	CalculationInterval time.Duration `mapstructure:"calculation_interval"`
+
+	// AggregationBuckets is the number of calculation intervals of throughput kept.
+	AggregationBuckets int `mapstructure:"aggregation_buckets"`
+
+	// BucketsForCalculation is the number of the most recent buckets used to compute the throughput.
+	BucketsForCalculation int `mapstructure:"buckets_for_calculation"`
+
+	// Delay is how long the throughput is kept before being used, to leave time for the clients
+	// to apply the probabilities computed previously.
+	Delay time.Duration `mapstructure:"delay"`
+
+	// InitialSamplingProbability is the probability of the operations that have no throughput yet.
+	InitialSamplingProbability float64 `mapstructure:"initial_sampling_probability"`
+
+	// MinSamplingProbability is the lower bound of the computed probabilities.
+	MinSamplingProbability float64 `mapstructure:"min_sampling_probability"`
+
+	// MinSamplesPerSecond is the rate of traces sampled per operation regardless of their probability.
+	MinSamplesPerSecond float64 `mapstructure:"min_samples_per_second"`
 }
 
 var _ component.Config = (*Config)(nil)
@@ -47,13 +98,57 @@ func (cfg *Config) Validate() error {
 		return errAtLeastOneProtocol
 	}
 
-	if cfg.Source.File != "" && cfg.Source.Remote != nil {
+	sources := 0
+	if cfg.Source.File != "" {
+		sources++
+	}
+	if cfg.Source.Remote != nil {
+		sources++
+	}
+	if cfg.Source.Adaptive != nil {
+		sources++
+	}
+	switch {
+	case sources > 1:
 		return errTooManySources
+	case sources == 0:
+		return errNoSources
 	}
 
-	if cfg.Source.File == "" && cfg.Source.Remote == nil {
-		return errNoSources
+	if cfg.Source.Adaptive != nil {
+		return cfg.Source.Adaptive.Validate()
 	}
+	return nil
+}
 
+// Validate checks if the adaptive configuration is valid
+func (cfg *AdaptiveConfig) Validate() error {
+	if cfg.TargetSamplesPerSecond <= 0 {
+		return errors.New("'target_samples_per_second' must be greater than 0")
+	}
+	if cfg.DeltaTolerance < 0 {
+		return errors.New("'delta_tolerance' cannot be less than 0")
+	}
+	if cfg.CalculationInterval <= 0 {
+		return errors.New("'calculation_interval' must be greater than 0")
+	}
+	if cfg.AggregationBuckets <= 0 {
+		return errors.New("'aggregation_buckets' must be greater than 0")
+	}
+	if cfg.BucketsForCalculation <= 0 || cfg.BucketsForCalculation > cfg.AggregationBuckets {
+		return errors.New("'buckets_for_calculation' must be greater than 0 and at most 'aggregation_buckets'")
+	}
+	if cfg.Delay < 0 {
+		return errors.New("'delay' cannot be less than 0")
+	}
+	if cfg.InitialSamplingProbability <= 0 || cfg.InitialSamplingProbability > 1 {
+		return errors.New("'initial_sampling_probability' must be greater than 0 and at most 1")
+	}
+	if cfg.MinSamplingProbability <= 0 || cfg.MinSamplingProbability > cfg.InitialSamplingProbability {
+		return errors.New("'min_sampling_probability' must be greater than 0 and at most 'initial_sampling_probability'")
+	}
+	if cfg.MinSamplesPerSecond < 0 {
+		return errors.New("'min_samples_per_second' cannot be less than 0")
+	}
 	return nil
 }
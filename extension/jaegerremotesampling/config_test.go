@@ -4,6 +4,7 @@
 package jaegerremotesampling
 
 import (
+	"errors"
 	"path/filepath"
 	"testing"
 	"time"
@@ -55,6 +56,26 @@ func TestLoadConfig(t *testing.T) {
 				},
 			},
 		},
+		{
+			id: component.NewIDWithName(metadata.Type, "adaptive"),
+			expected: &Config{
+				HTTPServerConfig: &confighttp.ServerConfig{Endpoint: "0.0.0.0:5778"},
+				GRPCServerConfig: &configgrpc.ServerConfig{NetAddr: confignet.AddrConfig{
+					Endpoint:  "0.0.0.0:14250",
+					Transport: confignet.TransportTypeTCP,
+				}},
+				Source: Source{
+					Adaptive: func() *AdaptiveConfig {
+						cfg := createDefaultAdaptiveConfig()
+						storageID := component.MustNewID("file_storage")
+						cfg.StorageID = &storageID
+						cfg.TargetSamplesPerSecond = 5
+						cfg.CalculationInterval = 30 * time.Second
+						return cfg
+					}(),
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.id.String(), func(t *testing.T) {
@@ -101,6 +122,54 @@ func TestValidate(t *testing.T) {
 			},
 			expected: errTooManySources,
 		},
+		{
+			desc: "file and adaptive sources",
+			cfg: Config{
+				GRPCServerConfig: &configgrpc.ServerConfig{},
+				Source: Source{
+					File:     "/tmp/some-file",
+					Adaptive: createDefaultAdaptiveConfig(),
+				},
+			},
+			expected: errTooManySources,
+		},
+		{
+			desc: "invalid adaptive probabilities",
+			cfg: Config{
+				GRPCServerConfig: &configgrpc.ServerConfig{},
+				Source: Source{
+					Adaptive: func() *AdaptiveConfig {
+						cfg := createDefaultAdaptiveConfig()
+						cfg.MinSamplingProbability = 0.1
+						return cfg
+					}(),
+				},
+			},
+			expected: errors.New("'min_sampling_probability' must be greater than 0 and at most 'initial_sampling_probability'"),
+		},
+		{
+			desc: "invalid adaptive buckets",
+			cfg: Config{
+				GRPCServerConfig: &configgrpc.ServerConfig{},
+				Source: Source{
+					Adaptive: func() *AdaptiveConfig {
+						cfg := createDefaultAdaptiveConfig()
+						cfg.BucketsForCalculation = 11
+						return cfg
+					}(),
+				},
+			},
+			expected: errors.New("'buckets_for_calculation' must be greater than 0 and at most 'aggregation_buckets'"),
+		},
+		{
+			desc: "valid adaptive source",
+			cfg: Config{
+				GRPCServerConfig: &configgrpc.ServerConfig{},
+				Source: Source{
+					Adaptive: createDefaultAdaptiveConfig(),
+				},
+			},
+		},
 	}
 	for _, tC := range testCases {
 		t.Run(tC.desc, func(t *testing.T) {
@@ -8,31 +8,48 @@ import (
 	"fmt"
 
 	"github.com/jaegertracing/jaeger/cmd/collector/app/sampling/strategystore"
+	"github.com/jaegertracing/jaeger/plugin/sampling/strategystore/adaptive"
 	"github.com/jaegertracing/jaeger/plugin/sampling/strategystore/static"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/extension"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/jaegerremotesampling/internal"
 )
 
 var _ extension.Extension = (*jrsExtension)(nil)
+var _ ThroughputRecorder = (*jrsExtension)(nil)
+
+// ThroughputRecorder is implemented by the extension to compute the strategies of the `adaptive`
+// source. Components in the same collector that receive the sampled traces, such as processors or
+// connectors, can find the extension with component.Host.GetExtensions and record the traces.
+type ThroughputRecorder interface {
+	// RecordTraces records the throughput of the root spans that were sampled by a Jaeger remote
+	// sampler, identified by their `sampler.type` and `sampler.param` attributes. It does nothing
+	// when the `adaptive` source is not configured.
+	RecordTraces(td ptrace.Traces)
+}
 
 type jrsExtension struct {
 	cfg       *Config
+	id        component.ID
 	telemetry component.TelemetrySettings
 
 	httpServer    component.Component
 	grpcServer    component.Component
 	samplingStore strategystore.StrategyStore
+	adaptiveStore *internal.AdaptiveStrategyStore
 
 	closers []func() error
 }
 
-func newExtension(cfg *Config, telemetry component.TelemetrySettings) *jrsExtension {
+func newExtension(cfg *Config, set extension.CreateSettings) *jrsExtension {
 	jrse := &jrsExtension{
 		cfg:       cfg,
-		telemetry: telemetry,
+		id:        set.ID,
+		telemetry: set.TelemetrySettings,
 	}
 	return jrse
 }
@@ -42,6 +59,7 @@ func (jrse *jrsExtension) Start(ctx context.Context, host component.Host) error
 	// source of the sampling config:
 	// - remote (gRPC)
 	// - local file
+	// - adaptive
 	// we can then use a simplified logic here to assign the appropriate store
 	if jrse.cfg.Source.File != "" {
 		opts := static.Options{
@@ -73,6 +91,12 @@ func (jrse *jrsExtension) Start(ctx context.Context, host component.Host) error
 		jrse.samplingStore = remoteStore
 	}
 
+	if jrse.cfg.Source.Adaptive != nil {
+		if err := jrse.startAdaptiveStore(ctx, host); err != nil {
+			return fmt.Errorf("failed to create the adaptive strategy store: %w", err)
+		}
+	}
+
 	if jrse.cfg.HTTPServerConfig != nil {
 		httpServer, err := internal.NewHTTP(jrse.telemetry, *jrse.cfg.HTTPServerConfig, jrse.samplingStore)
 		if err != nil {
@@ -100,6 +124,62 @@ func (jrse *jrsExtension) Start(ctx context.Context, host component.Host) error
 	return nil
 }
 
+func (jrse *jrsExtension) startAdaptiveStore(ctx context.Context, host component.Host) error {
+	client, err := jrse.getStorageClient(ctx, host)
+	if err != nil {
+		return err
+	}
+	jrse.closers = append(jrse.closers, func() error {
+		return client.Close(context.Background())
+	})
+
+	cfg := jrse.cfg.Source.Adaptive
+	opts := adaptive.Options{
+		TargetSamplesPerSecond:     cfg.TargetSamplesPerSecond,
+		DeltaTolerance:             cfg.DeltaTolerance,
+		CalculationInterval:        cfg.CalculationInterval,
+		AggregationBuckets:         cfg.AggregationBuckets,
+		BucketsForCalculation:      cfg.BucketsForCalculation,
+		Delay:                      cfg.Delay,
+		InitialSamplingProbability: cfg.InitialSamplingProbability,
+		MinSamplingProbability:     cfg.MinSamplingProbability,
+		MinSamplesPerSecond:        cfg.MinSamplesPerSecond,
+	}
+	adaptiveStore, err := internal.NewAdaptiveStrategyStore(ctx, opts, client, jrse.telemetry.Logger)
+	if err != nil {
+		return err
+	}
+	// closed before the storage client, which it writes to
+	jrse.closers = append([]func() error{adaptiveStore.Close}, jrse.closers...)
+	jrse.adaptiveStore = adaptiveStore
+	jrse.samplingStore = adaptiveStore
+	return nil
+}
+
+func (jrse *jrsExtension) getStorageClient(ctx context.Context, host component.Host) (storage.Client, error) {
+	storageID := jrse.cfg.Source.Adaptive.StorageID
+	if storageID == nil {
+		return storage.NewNopClient(), nil
+	}
+
+	ext, ok := host.GetExtensions()[*storageID]
+	if !ok {
+		return nil, fmt.Errorf("storage extension '%s' not found", storageID)
+	}
+	storageExt, ok := ext.(storage.Extension)
+	if !ok {
+		return nil, fmt.Errorf("non-storage extension '%s' found", storageID)
+	}
+	return storageExt.GetClient(ctx, component.KindExtension, jrse.id, "")
+}
+
+// RecordTraces records the throughput of the sampled root spans to compute the adaptive strategies.
+func (jrse *jrsExtension) RecordTraces(td ptrace.Traces) {
+	if jrse.adaptiveStore != nil {
+		jrse.adaptiveStore.RecordTraces(td)
+	}
+}
+
 func (jrse *jrsExtension) Shutdown(ctx context.Context) error {
 	// we probably don't want to break whenever an error occurs, we want to continue and close the other resources
 	if jrse.httpServer != nil {
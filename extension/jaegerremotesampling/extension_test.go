@@ -12,6 +12,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/gogo/protobuf/jsonpb"
 	"github.com/jaegertracing/jaeger/proto-gen/api_v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -19,15 +20,19 @@ import (
 	"go.opentelemetry.io/collector/config/configgrpc"
 	"go.opentelemetry.io/collector/config/configopaque"
 	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/extension/extensiontest"
+	"go.opentelemetry.io/collector/pdata/ptrace"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/metadata"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/storagetest"
 )
 
 func TestNewExtension(t *testing.T) {
 	// test
 	cfg := testConfig()
 	cfg.Source.File = filepath.Join("testdata", "strategy.json")
-	e := newExtension(cfg, componenttest.NewNopTelemetrySettings())
+	e := newExtension(cfg, extensiontest.NewNopCreateSettings())
 
 	// verify
 	assert.NotNil(t, e)
@@ -38,7 +43,7 @@ func TestStartAndShutdownLocalFile(t *testing.T) {
 	cfg := testConfig()
 	cfg.Source.File = filepath.Join("testdata", "strategy.json")
 
-	e := newExtension(cfg, componenttest.NewNopTelemetrySettings())
+	e := newExtension(cfg, extensiontest.NewNopCreateSettings())
 	require.NotNil(t, e)
 	require.NoError(t, e.Start(context.Background(), componenttest.NewNopHost()))
 
@@ -110,7 +115,7 @@ func TestRemote(t *testing.T) {
 			}
 
 			// create the extension
-			e := newExtension(cfg, componenttest.NewNopTelemetrySettings())
+			e := newExtension(cfg, extensiontest.NewNopCreateSettings())
 			require.NotNil(t, e)
 
 			// start the server
@@ -143,6 +148,54 @@ func TestRemote(t *testing.T) {
 	}
 }
 
+func TestAdaptive(t *testing.T) {
+	storageID := storagetest.NewStorageID("adaptive")
+	host := storagetest.NewStorageHost().WithInMemoryStorageExtension("adaptive")
+
+	cfg := testConfig()
+	cfg.GRPCServerConfig = nil
+	cfg.Source.Adaptive = createDefaultAdaptiveConfig()
+	cfg.Source.Adaptive.StorageID = &storageID
+
+	e := newExtension(cfg, extensiontest.NewNopCreateSettings())
+	require.NoError(t, e.Start(context.Background(), host))
+	t.Cleanup(func() {
+		assert.NoError(t, e.Shutdown(context.Background()))
+	})
+
+	var recorder ThroughputRecorder = e
+	td := ptrace.NewTraces()
+	rs := td.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().PutStr("service.name", "foo")
+	span := rs.ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+	span.SetName("op1")
+	span.Attributes().PutStr("sampler.type", "probabilistic")
+	span.Attributes().PutDouble("sampler.param", 0.001)
+	recorder.RecordTraces(td)
+
+	// The initial probability is served until the throughput is used
+	resp, err := http.Get("http://127.0.0.1:5778/sampling?service=foo")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	var strategy api_v2.SamplingStrategyResponse
+	require.NoError(t, jsonpb.Unmarshal(resp.Body, &strategy))
+	assert.Equal(t, 0.001, strategy.OperationSampling.DefaultSamplingProbability)
+}
+
+func TestAdaptiveMissingStorage(t *testing.T) {
+	storageID := storagetest.NewStorageID("missing")
+	cfg := testConfig()
+	cfg.GRPCServerConfig = nil
+	cfg.HTTPServerConfig = nil
+	cfg.Source.Adaptive = createDefaultAdaptiveConfig()
+	cfg.Source.Adaptive.StorageID = &storageID
+
+	e := newExtension(cfg, extensiontest.NewNopCreateSettings())
+	assert.EqualError(t, e.Start(context.Background(), componenttest.NewNopHost()), "failed to create the adaptive strategy store: storage extension 'test_storage/missing' not found")
+	assert.NoError(t, e.Shutdown(context.Background()))
+}
+
 type samplingServer struct {
 	api_v2.UnimplementedSamplingManagerServer
 	observedCalls []observedCall
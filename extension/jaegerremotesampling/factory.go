@@ -6,6 +6,7 @@ package jaegerremotesampling // import "github.com/open-telemetry/opentelemetry-
 import (
 	"context"
 	"sync"
+	"time"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configgrpc"
@@ -44,6 +45,21 @@ func createDefaultConfig() component.Config {
 	}
 }
 
+// createDefaultAdaptiveConfig returns the defaults of Jaeger's adaptive sampling.
+func createDefaultAdaptiveConfig() *AdaptiveConfig {
+	return &AdaptiveConfig{
+		TargetSamplesPerSecond:     1,
+		DeltaTolerance:             0.3,
+		CalculationInterval:        time.Minute,
+		AggregationBuckets:         10,
+		BucketsForCalculation:      1,
+		Delay:                      2 * time.Minute,
+		InitialSamplingProbability: 0.001,
+		MinSamplingProbability:     1e-5,
+		MinSamplesPerSecond:        1.0 / 60,
+	}
+}
+
 var once sync.Once
 
 func logDeprecation(logger *zap.Logger) {
@@ -64,5 +80,5 @@ var protoGate = featuregate.GlobalRegistry().MustRegister(
 
 func createExtension(_ context.Context, set extension.CreateSettings, cfg component.Config) (extension.Extension, error) {
 	logDeprecation(set.Logger)
-	return newExtension(cfg.(*Config), set.TelemetrySettings), nil
+	return newExtension(cfg.(*Config), set), nil
 }
@@ -0,0 +1,147 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package internal // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/jaegerremotesampling/internal"
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/jaegertracing/jaeger/cmd/collector/app/sampling/strategystore"
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/metrics"
+	"github.com/jaegertracing/jaeger/plugin/sampling/strategystore/adaptive"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
+)
+
+const (
+	samplerTypeAttribute  = "sampler.type"
+	samplerParamAttribute = "sampler.param"
+	serviceNameAttribute  = "service.name"
+
+	// the lock is always acquired, so the lease refresh intervals only need to be valid
+	leaderLeaseRefreshInterval   = 5 * time.Second
+	followerLeaseRefreshInterval = time.Minute
+)
+
+var samplerTypes = map[string]model.SamplerType{
+	"probabilistic": model.SamplerTypeProbabilistic,
+	"lowerbound":    model.SamplerTypeLowerBound,
+	"ratelimiting":  model.SamplerTypeRateLimiting,
+	"const":         model.SamplerTypeConst,
+}
+
+// AdaptiveStrategyStore computes the strategies from the throughput of the root spans it records,
+// using Jaeger's adaptive sampling processor.
+type AdaptiveStrategyStore struct {
+	*adaptive.Processor
+	aggregator strategystore.Aggregator
+	logger     *zap.Logger
+}
+
+// NewAdaptiveStrategyStore returns a started AdaptiveStrategyStore, restoring and persisting its state with
+// the storage client. The collector is always the leader computing the probabilities, as the state is not
+// shared with other collectors.
+func NewAdaptiveStrategyStore(ctx context.Context, opts adaptive.Options, client storage.Client, logger *zap.Logger) (*AdaptiveStrategyStore, error) {
+	retention := time.Duration(opts.AggregationBuckets)*opts.CalculationInterval + opts.Delay
+	store, err := newPersistentSamplingStore(ctx, client, retention, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	opts.LeaderLeaseRefreshInterval = leaderLeaseRefreshInterval
+	opts.FollowerLeaseRefreshInterval = followerLeaseRefreshInterval
+	processor, err := adaptive.NewStrategyStore(opts, metrics.NullFactory, logger, leaderLock{}, store)
+	if err != nil {
+		return nil, err
+	}
+	if err = processor.Start(); err != nil {
+		return nil, err
+	}
+
+	aggregator := adaptive.NewAggregator(metrics.NullFactory, opts.CalculationInterval, store)
+	aggregator.Start()
+	return &AdaptiveStrategyStore{Processor: processor, aggregator: aggregator, logger: logger}, nil
+}
+
+// RecordTraces records the throughput of the root spans that were sampled by a Jaeger sampler,
+// identified by their sampler.type and sampler.param attributes.
+func (s *AdaptiveStrategyStore) RecordTraces(td ptrace.Traces) {
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		service, ok := rs.Resource().Attributes().Get(serviceNameAttribute)
+		if !ok || service.Str() == "" {
+			continue
+		}
+		ilss := rs.ScopeSpans()
+		for j := 0; j < ilss.Len(); j++ {
+			spans := ilss.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				if !span.ParentSpanID().IsEmpty() || span.Name() == "" {
+					continue
+				}
+				samplerType, probability := s.samplerParams(span)
+				if samplerType == model.SamplerTypeUnrecognized {
+					continue
+				}
+				s.aggregator.RecordThroughput(service.Str(), span.Name(), samplerType, probability)
+			}
+		}
+	}
+}
+
+// samplerParams returns the type and the parameter of the sampler of the span, like Jaeger
+// does for its spans.
+func (s *AdaptiveStrategyStore) samplerParams(span ptrace.Span) (model.SamplerType, float64) {
+	typeAttr, ok := span.Attributes().Get(samplerTypeAttribute)
+	if !ok {
+		return model.SamplerTypeUnrecognized, 0
+	}
+	samplerType, ok := samplerTypes[typeAttr.AsString()]
+	if !ok {
+		return model.SamplerTypeUnrecognized, 0
+	}
+
+	paramAttr, ok := span.Attributes().Get(samplerParamAttribute)
+	if !ok {
+		return model.SamplerTypeUnrecognized, 0
+	}
+	switch paramAttr.Type() {
+	case pcommon.ValueTypeDouble:
+		return samplerType, paramAttr.Double()
+	case pcommon.ValueTypeInt:
+		return samplerType, float64(paramAttr.Int())
+	case pcommon.ValueTypeStr:
+		if param, err := strconv.ParseFloat(paramAttr.Str(), 64); err == nil {
+			return samplerType, param
+		}
+	}
+	s.logger.Warn("sampler.param attribute is not a number",
+		zap.String("traceID", span.TraceID().String()),
+		zap.String("spanID", span.SpanID().String()),
+		zap.String("value", paramAttr.AsString()))
+	return model.SamplerTypeUnrecognized, 0
+}
+
+// Close stops computing the strategies.
+func (s *AdaptiveStrategyStore) Close() error {
+	return errors.Join(s.aggregator.Close(), s.Processor.Close())
+}
+
+// leaderLock is always acquired, as the state is local to the collector.
+type leaderLock struct{}
+
+func (leaderLock) Acquire(string, time.Duration) (bool, error) {
+	return true, nil
+}
+
+func (leaderLock) Forfeit(string) (bool, error) {
+	return true, nil
+}
@@ -0,0 +1,129 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package internal // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/jaegerremotesampling/internal"
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jaegertracing/jaeger/cmd/collector/app/sampling/model"
+	"github.com/jaegertracing/jaeger/storage/samplingstore"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+	"go.uber.org/zap"
+)
+
+const (
+	throughputKey    = "throughput"
+	probabilitiesKey = "probabilities"
+)
+
+var _ samplingstore.Store = (*persistentSamplingStore)(nil)
+
+// throughputBucket is the throughput aggregated during an interval, stored at the end of the interval.
+type throughputBucket struct {
+	Timestamp  time.Time           `json:"timestamp"`
+	Throughput []*model.Throughput `json:"throughput"`
+}
+
+// persistentSamplingStore keeps the throughput and the latest probabilities of the adaptive sampling
+// in memory, writing them to a storage client so that they are restored after a restart.
+type persistentSamplingStore struct {
+	client    storage.Client
+	retention time.Duration
+	logger    *zap.Logger
+
+	mu            sync.RWMutex
+	buckets       []throughputBucket
+	probabilities model.ServiceOperationProbabilities
+}
+
+// newPersistentSamplingStore returns a sampling store restoring the state saved in the client,
+// keeping the throughput for the retention.
+func newPersistentSamplingStore(ctx context.Context, client storage.Client, retention time.Duration, logger *zap.Logger) (*persistentSamplingStore, error) {
+	s := &persistentSamplingStore{
+		client:        client,
+		retention:     retention,
+		logger:        logger,
+		probabilities: model.ServiceOperationProbabilities{},
+	}
+
+	if err := s.load(ctx, throughputKey, &s.buckets); err != nil {
+		return nil, err
+	}
+	if err := s.load(ctx, probabilitiesKey, &s.probabilities); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *persistentSamplingStore) load(ctx context.Context, key string, v any) error {
+	data, err := s.client.Get(ctx, key)
+	if err != nil {
+		return fmt.Errorf("failed to read the adaptive sampling %s: %w", key, err)
+	}
+	if data == nil {
+		return nil
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		// the state is recomputed from the new throughput, so a corrupted state is not fatal
+		s.logger.Warn("discarding invalid adaptive sampling state", zap.String("key", key), zap.Error(err))
+	}
+	return nil
+}
+
+func (s *persistentSamplingStore) save(key string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return s.client.Set(context.Background(), key, data)
+}
+
+// InsertThroughput stores the throughput aggregated since the previous insertion.
+func (s *persistentSamplingStore) InsertThroughput(throughput []*model.Throughput) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.buckets = append(s.buckets, throughputBucket{Timestamp: now, Throughput: throughput})
+	cutoff := now.Add(-s.retention)
+	for len(s.buckets) > 0 && s.buckets[0].Timestamp.Before(cutoff) {
+		s.buckets = s.buckets[1:]
+	}
+	return s.save(throughputKey, s.buckets)
+}
+
+// GetThroughput returns the throughput stored within the time range.
+func (s *persistentSamplingStore) GetThroughput(start, end time.Time) ([]*model.Throughput, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var throughput []*model.Throughput
+	for _, bucket := range s.buckets {
+		if !bucket.Timestamp.Before(start) && !bucket.Timestamp.After(end) {
+			throughput = append(throughput, bucket.Throughput...)
+		}
+	}
+	return throughput, nil
+}
+
+// InsertProbabilitiesAndQPS stores the latest probabilities. The QPS are only used by Jaeger's UI,
+// so they are not stored.
+func (s *persistentSamplingStore) InsertProbabilitiesAndQPS(_ string, probabilities model.ServiceOperationProbabilities, _ model.ServiceOperationQPS) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.probabilities = probabilities
+	return s.save(probabilitiesKey, probabilities)
+}
+
+// GetLatestProbabilities returns the latest probabilities.
+func (s *persistentSamplingStore) GetLatestProbabilities() (model.ServiceOperationProbabilities, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.probabilities, nil
+}
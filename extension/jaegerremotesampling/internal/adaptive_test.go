@@ -0,0 +1,177 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	samplingmodel "github.com/jaegertracing/jaeger/cmd/collector/app/sampling/model"
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/plugin/sampling/strategystore/adaptive"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/storagetest"
+)
+
+type recordedThroughput struct {
+	service     string
+	operation   string
+	samplerType model.SamplerType
+	probability float64
+}
+
+type mockAggregator struct {
+	recorded []recordedThroughput
+}
+
+func (m *mockAggregator) RecordThroughput(service, operation string, samplerType model.SamplerType, probability float64) {
+	m.recorded = append(m.recorded, recordedThroughput{service, operation, samplerType, probability})
+}
+
+func (m *mockAggregator) Start() {}
+
+func (m *mockAggregator) Close() error {
+	return nil
+}
+
+// appendRootSpan appends a root span sampled by a Jaeger sampler to the traces of the service.
+func appendRootSpan(td ptrace.Traces, service, operation string, samplerAttrs map[string]any) ptrace.Span {
+	rs := td.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().PutStr("service.name", service)
+	span := rs.ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+	span.SetName(operation)
+	span.SetTraceID(pcommon.TraceID{1})
+	span.SetSpanID(pcommon.SpanID{1})
+	_ = span.Attributes().FromRaw(samplerAttrs)
+	return span
+}
+
+func testAdaptiveOptions() adaptive.Options {
+	return adaptive.Options{
+		TargetSamplesPerSecond:     1,
+		DeltaTolerance:             0.3,
+		CalculationInterval:        50 * time.Millisecond,
+		AggregationBuckets:         10,
+		BucketsForCalculation:      1,
+		InitialSamplingProbability: 0.001,
+		MinSamplingProbability:     1e-5,
+		MinSamplesPerSecond:        1.0 / 60,
+	}
+}
+
+func TestRecordTraces(t *testing.T) {
+	td := ptrace.NewTraces()
+	appendRootSpan(td, "foo", "op1", map[string]any{"sampler.type": "probabilistic", "sampler.param": 0.1})
+	appendRootSpan(td, "foo", "op2", map[string]any{"sampler.type": "lowerbound", "sampler.param": "0.5"})
+	appendRootSpan(td, "bar", "op1", map[string]any{"sampler.type": "ratelimiting", "sampler.param": 2})
+	// Spans that are not sampled by a Jaeger sampler or are not root spans are ignored
+	appendRootSpan(td, "bar", "op2", map[string]any{})
+	appendRootSpan(td, "bar", "op3", map[string]any{"sampler.type": "unknown", "sampler.param": 0.1})
+	appendRootSpan(td, "bar", "op4", map[string]any{"sampler.type": "probabilistic", "sampler.param": "not a number"})
+	appendRootSpan(td, "", "op1", map[string]any{"sampler.type": "probabilistic", "sampler.param": 0.1})
+	child := appendRootSpan(td, "bar", "op5", map[string]any{"sampler.type": "probabilistic", "sampler.param": 0.1})
+	child.SetParentSpanID(pcommon.SpanID{2})
+
+	aggregator := &mockAggregator{}
+	store := &AdaptiveStrategyStore{aggregator: aggregator, logger: zap.NewNop()}
+	store.RecordTraces(td)
+
+	assert.Equal(t, []recordedThroughput{
+		{"foo", "op1", model.SamplerTypeProbabilistic, 0.1},
+		{"foo", "op2", model.SamplerTypeLowerBound, 0.5},
+		{"bar", "op1", model.SamplerTypeRateLimiting, 2},
+	}, aggregator.recorded)
+}
+
+func TestPersistentSamplingStore(t *testing.T) {
+	ctx := context.Background()
+	client := storagetest.NewInMemoryClient(component.KindExtension, component.MustNewID("jaegerremotesampling"), "")
+
+	store, err := newPersistentSamplingStore(ctx, client, time.Hour, zap.NewNop())
+	require.NoError(t, err)
+	start := time.Now()
+	throughput := []*samplingmodel.Throughput{{Service: "foo", Operation: "op1", Count: 10, Probabilities: map[string]struct{}{"0.001000": {}}}}
+	require.NoError(t, store.InsertThroughput(throughput))
+	probabilities := samplingmodel.ServiceOperationProbabilities{"foo": {"op1": 0.0005}}
+	require.NoError(t, store.InsertProbabilitiesAndQPS("host", probabilities, nil))
+
+	// The state is restored from the storage client
+	restored, err := newPersistentSamplingStore(ctx, client, time.Hour, zap.NewNop())
+	require.NoError(t, err)
+	got, err := restored.GetThroughput(start, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, throughput, got)
+	got, err = restored.GetThroughput(time.Now(), time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, got)
+	gotProbabilities, err := restored.GetLatestProbabilities()
+	require.NoError(t, err)
+	assert.Equal(t, probabilities, gotProbabilities)
+
+	// Throughput older than the retention is dropped
+	expiring, err := newPersistentSamplingStore(ctx, client, time.Nanosecond, zap.NewNop())
+	require.NoError(t, err)
+	require.NoError(t, expiring.InsertThroughput(nil))
+	assert.Len(t, expiring.buckets, 1)
+
+	// An invalid state is discarded
+	require.NoError(t, client.Set(ctx, probabilitiesKey, []byte("not json")))
+	restored, err = newPersistentSamplingStore(ctx, client, time.Hour, zap.NewNop())
+	require.NoError(t, err)
+	gotProbabilities, err = restored.GetLatestProbabilities()
+	require.NoError(t, err)
+	assert.Empty(t, gotProbabilities)
+}
+
+func TestAdaptiveStrategyStore(t *testing.T) {
+	ctx := context.Background()
+	client := storagetest.NewInMemoryClient(component.KindExtension, component.MustNewID("jaegerremotesampling"), "")
+
+	store, err := NewAdaptiveStrategyStore(ctx, testAdaptiveOptions(), client, zap.NewNop())
+	require.NoError(t, err)
+
+	// Unknown services get the default strategy
+	resp, err := store.GetSamplingStrategy(ctx, "foo")
+	require.NoError(t, err)
+	assert.Equal(t, 0.001, resp.OperationSampling.DefaultSamplingProbability)
+	assert.Empty(t, resp.OperationSampling.PerOperationStrategies)
+
+	// An operation sampled above the target rate gets a lower probability
+	assert.Eventually(t, func() bool {
+		td := ptrace.NewTraces()
+		for i := 0; i < 100; i++ {
+			appendRootSpan(td, "foo", "op1", map[string]any{"sampler.type": "probabilistic", "sampler.param": 0.001})
+		}
+		store.RecordTraces(td)
+
+		resp, err = store.GetSamplingStrategy(ctx, "foo")
+		require.NoError(t, err)
+		for _, strategy := range resp.OperationSampling.PerOperationStrategies {
+			if strategy.Operation == "op1" && strategy.ProbabilisticSampling.SamplingRate < 0.001 {
+				return true
+			}
+		}
+		return false
+	}, 10*time.Second, 10*time.Millisecond)
+	require.NoError(t, store.Close())
+
+	// The computed probabilities are served after a restart
+	restarted, err := NewAdaptiveStrategyStore(ctx, testAdaptiveOptions(), client, zap.NewNop())
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, restarted.Close())
+	})
+	restartedResp, err := restarted.GetSamplingStrategy(ctx, "foo")
+	require.NoError(t, err)
+	require.Len(t, restartedResp.OperationSampling.PerOperationStrategies, 1)
+	assert.Equal(t, "op1", restartedResp.OperationSampling.PerOperationStrategies[0].Operation)
+	assert.Less(t, restartedResp.OperationSampling.PerOperationStrategies[0].ProbabilisticSampling.SamplingRate, 0.001)
+}
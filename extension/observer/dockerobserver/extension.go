@@ -177,6 +177,7 @@ func (d *dockerObserver) endpointForPort(portObj nat.Port, c *dtypes.ContainerJS
 		ContainerID: c.ID,
 		Transport:   portProtoToTransport(proto),
 		Labels:      c.Config.Labels,
+		LogPath:     c.LogPath,
 	}
 
 	// Set our hostname based on config settings
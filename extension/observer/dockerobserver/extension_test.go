@@ -77,6 +77,7 @@ func TestCollectEndpointsDefaultConfig(t *testing.T) {
 				Tag:         "1.17",
 				Command:     "nginx -g daemon off;",
 				ContainerID: "babc5a6d7af2a48e7f52e1da26047024dcf98b737e754c9c3459bb84d1e4f80c",
+				LogPath:     "/var/lib/docker/containers/babc5a6d7af2a48e7f52e1da26047024dcf98b737e754c9c3459bb84d1e4f80c/babc5a6d7af2a48e7f52e1da26047024dcf98b737e754c9c3459bb84d1e4f80c-json.log",
 				Transport:   observer.ProtocolTCP,
 				Labels: map[string]string{
 					"hello":      "world",
@@ -114,6 +115,7 @@ func TestCollectEndpointsAllConfigSettings(t *testing.T) {
 				Tag:         "1.17",
 				Command:     "nginx -g daemon off;",
 				ContainerID: "babc5a6d7af2a48e7f52e1da26047024dcf98b737e754c9c3459bb84d1e4f80c",
+				LogPath:     "/var/lib/docker/containers/babc5a6d7af2a48e7f52e1da26047024dcf98b737e754c9c3459bb84d1e4f80c/babc5a6d7af2a48e7f52e1da26047024dcf98b737e754c9c3459bb84d1e4f80c-json.log",
 				Transport:   observer.ProtocolTCP,
 				Labels: map[string]string{
 					"hello":      "world",
@@ -151,6 +153,7 @@ func TestCollectEndpointsUseHostnameIfPresent(t *testing.T) {
 				Tag:         "1.17",
 				Command:     "nginx -g daemon off;",
 				ContainerID: "babc5a6d7af2a48e7f52e1da26047024dcf98b737e754c9c3459bb84d1e4f80c",
+				LogPath:     "/var/lib/docker/containers/babc5a6d7af2a48e7f52e1da26047024dcf98b737e754c9c3459bb84d1e4f80c/babc5a6d7af2a48e7f52e1da26047024dcf98b737e754c9c3459bb84d1e4f80c-json.log",
 				Transport:   observer.ProtocolTCP,
 				Labels: map[string]string{
 					"hello":      "world",
@@ -188,6 +191,7 @@ func TestCollectEndpointsUseHostBindings(t *testing.T) {
 				Tag:         "1.17",
 				Command:     "nginx -g daemon off;",
 				ContainerID: "babc5a6d7af2a48e7f52e1da26047024dcf98b737e754c9c3459bb84d1e4f80c",
+				LogPath:     "/var/lib/docker/containers/babc5a6d7af2a48e7f52e1da26047024dcf98b737e754c9c3459bb84d1e4f80c/babc5a6d7af2a48e7f52e1da26047024dcf98b737e754c9c3459bb84d1e4f80c-json.log",
 				Transport:   observer.ProtocolTCP,
 				Labels: map[string]string{
 					"hello":      "world",
@@ -225,6 +229,7 @@ func TestCollectEndpointsIgnoreNonHostBindings(t *testing.T) {
 				Tag:         "1.17",
 				Command:     "nginx -g daemon off;",
 				ContainerID: "babc5a6d7af2a48e7f52e1da26047024dcf98b737e754c9c3459bb84d1e4f80c",
+				LogPath:     "/var/lib/docker/containers/babc5a6d7af2a48e7f52e1da26047024dcf98b737e754c9c3459bb84d1e4f80c/babc5a6d7af2a48e7f52e1da26047024dcf98b737e754c9c3459bb84d1e4f80c-json.log",
 				Transport:   observer.ProtocolTCP,
 				Labels: map[string]string{
 					"hello":      "world",
@@ -23,6 +23,8 @@ const (
 	PortType EndpointType = "port"
 	// PodType is a pod endpoint.
 	PodType EndpointType = "pod"
+	// PodContainerType is a pod's container endpoint.
+	PodContainerType EndpointType = "pod.container"
 	// K8sServiceType is a service endpoint.
 	K8sServiceType EndpointType = "k8s.service"
 	// K8sNodeType is a Kubernetes Node endpoint.
@@ -36,6 +38,7 @@ const (
 var (
 	_ EndpointDetails = (*Pod)(nil)
 	_ EndpointDetails = (*Port)(nil)
+	_ EndpointDetails = (*PodContainer)(nil)
 	_ EndpointDetails = (*K8sService)(nil)
 	_ EndpointDetails = (*K8sNode)(nil)
 	_ EndpointDetails = (*HostPort)(nil)
@@ -157,6 +160,34 @@ func (p *Pod) Type() EndpointType {
 	return PodType
 }
 
+// PodContainer is a discovered k8s pod's container.
+type PodContainer struct {
+	// Name of the container.
+	Name string
+	// Image of the container.
+	Image string
+	// ContainerID is the id of the container, without the container runtime prefix.
+	ContainerID string
+	// LogPath is the glob matching the log files of the container on the node.
+	LogPath string
+	// Pod is the k8s pod in which the container is running.
+	Pod Pod
+}
+
+func (p *PodContainer) Env() EndpointEnv {
+	return map[string]any{
+		"container_name":  p.Name,
+		"container_image": p.Image,
+		"container_id":    p.ContainerID,
+		"log_path":        p.LogPath,
+		"pod":             p.Pod.Env(),
+	}
+}
+
+func (p *PodContainer) Type() EndpointType {
+	return PodContainerType
+}
+
 // Port is an endpoint that has a target as well as a port.
 type Port struct {
 	// Name is the name of the container port.
@@ -235,6 +266,8 @@ type Container struct {
 	Transport Transport
 	// Labels is a map of user-specified metadata on the container.
 	Labels map[string]string
+	// LogPath is the path of the log file of the container on the host, if any.
+	LogPath string
 }
 
 func (c *Container) Env() EndpointEnv {
@@ -249,6 +282,7 @@ func (c *Container) Env() EndpointEnv {
 		"host":           c.Host,
 		"transport":      c.Transport,
 		"labels":         c.Labels,
+		"log_path":       c.LogPath,
 	}
 }
 
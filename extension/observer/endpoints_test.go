@@ -168,6 +168,7 @@ func TestEndpointEnv(t *testing.T) {
 					Labels: map[string]string{
 						"label_key": "label_val",
 					},
+					LogPath: "/var/lib/docker/containers/abcdefg123456/abcdefg123456-json.log",
 				},
 			},
 			want: EndpointEnv{
@@ -185,9 +186,44 @@ func TestEndpointEnv(t *testing.T) {
 				"labels": map[string]string{
 					"label_key": "label_val",
 				},
+				"log_path": "/var/lib/docker/containers/abcdefg123456/abcdefg123456-json.log",
 				"endpoint": "127.0.0.1",
 			},
 		},
+		{
+			name: "K8s pod container",
+			endpoint: Endpoint{
+				ID:     EndpointID("container_id"),
+				Target: "192.68.73.2",
+				Details: &PodContainer{
+					Name:        "otel-collector",
+					Image:       "otel-collector-image",
+					ContainerID: "abcdefg123456",
+					LogPath:     "/var/log/pods/pod-namespace_pod_name_pod-uid/otel-collector/*.log",
+					Pod: Pod{
+						Name:      "pod_name",
+						Namespace: "pod-namespace",
+						UID:       "pod-uid",
+					},
+				},
+			},
+			want: EndpointEnv{
+				"type":            "pod.container",
+				"endpoint":        "192.68.73.2",
+				"id":              "container_id",
+				"container_name":  "otel-collector",
+				"container_image": "otel-collector-image",
+				"container_id":    "abcdefg123456",
+				"log_path":        "/var/log/pods/pod-namespace_pod_name_pod-uid/otel-collector/*.log",
+				"pod": EndpointEnv{
+					"name":        "pod_name",
+					"namespace":   "pod-namespace",
+					"uid":         "pod-uid",
+					"labels":      map[string]string(nil),
+					"annotations": map[string]string(nil),
+				},
+			},
+		},
 		{
 			name: "Kubernetes Node",
 			endpoint: Endpoint{
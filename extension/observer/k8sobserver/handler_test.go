@@ -36,6 +36,21 @@ func TestPodEndpointsAdded(t *testing.T) {
 				UID:       "pod-2-UID",
 				Labels:    map[string]string{"env": "prod"},
 			},
+		}, {
+			ID:     "test-1/pod-2-UID/container-2",
+			Target: "1.2.3.4",
+			Details: &observer.PodContainer{
+				Name:        "container-2",
+				Image:       "container-image-2",
+				ContainerID: "a808232bfad6",
+				LogPath:     "/var/log/pods/default_pod-2_pod-2-UID/container-2/*.log",
+				Pod: observer.Pod{
+					Name:      "pod-2",
+					Namespace: "default",
+					UID:       "pod-2-UID",
+					Labels:    map[string]string{"env": "prod"},
+				},
+			},
 		}, {
 			ID:     "test-1/pod-2-UID/https(443)",
 			Target: "1.2.3.4:443",
@@ -73,8 +88,8 @@ func TestPodEndpointsChanged(t *testing.T) {
 
 	endpoints := th.ListEndpoints()
 	require.ElementsMatch(t,
-		[]observer.EndpointID{"test-1/pod-2-UID", "test-1/pod-2-UID/https(443)"},
-		[]observer.EndpointID{endpoints[0].ID, endpoints[1].ID},
+		[]observer.EndpointID{"test-1/pod-2-UID", "test-1/pod-2-UID/container-2", "test-1/pod-2-UID/https(443)"},
+		[]observer.EndpointID{endpoints[0].ID, endpoints[1].ID, endpoints[2].ID},
 	)
 
 	// Running state changed, one added and one removed.
@@ -90,6 +105,19 @@ func TestPodEndpointsChanged(t *testing.T) {
 				Namespace: "default",
 				UID:       "pod-2-UID",
 				Labels:    map[string]string{"env": "prod", "updated-label": "true"}}},
+		{
+			ID:     "test-1/pod-2-UID/container-2",
+			Target: "1.2.3.4",
+			Details: &observer.PodContainer{
+				Name:        "container-2",
+				Image:       "container-image-2",
+				ContainerID: "a808232bfad6",
+				LogPath:     "/var/log/pods/default_pod-2_pod-2-UID/container-2/*.log",
+				Pod: observer.Pod{
+					Name:      "pod-2",
+					Namespace: "default",
+					UID:       "pod-2-UID",
+					Labels:    map[string]string{"env": "prod", "updated-label": "true"}}}},
 		{
 			ID:     "test-1/pod-2-UID/https(443)",
 			Target: "1.2.3.4:443",
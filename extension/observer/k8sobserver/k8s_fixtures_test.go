@@ -79,9 +79,10 @@ var container2StatusRunning = v1.ContainerStatus{
 	State: v1.ContainerState{
 		Running: &v1.ContainerStateRunning{StartedAt: metav1.Now()},
 	},
-	Ready:   true,
-	Image:   "container-image-1",
-	Started: pointerBool(true),
+	Ready:       true,
+	Image:       "container-image-1",
+	ContainerID: "containerd://a808232bfad6",
+	Started:     pointerBool(true),
 }
 
 var podWithNamedPorts = func() *v1.Pod {
@@ -5,6 +5,7 @@ package k8sobserver // import "github.com/open-telemetry/opentelemetry-collector
 
 import (
 	"fmt"
+	"strings"
 
 	v1 "k8s.io/api/core/v1"
 
@@ -12,8 +13,8 @@ import (
 )
 
 // convertPodToEndpoints converts a pod instance into a slice of endpoints. The endpoints
-// include the pod itself as well as an endpoint for each container port that is mapped
-// to a container that is in a running state.
+// include the pod itself as well as an endpoint for each container in a running state
+// and for each container port that is mapped to a running container.
 func convertPodToEndpoints(idNamespace string, pod *v1.Pod) []observer.Endpoint {
 	podID := observer.EndpointID(fmt.Sprintf("%s/%s", idNamespace, pod.UID))
 	podIP := pod.Status.PodIP
@@ -37,21 +38,34 @@ func convertPodToEndpoints(idNamespace string, pod *v1.Pod) []observer.Endpoint
 		Details: &podDetails,
 	}}
 
-	// Map of running containers by name.
-	containerRunning := map[string]bool{}
+	// Map of running containers by name to their runtime container ID.
+	containerIDs := map[string]string{}
 
 	for _, container := range pod.Status.ContainerStatuses {
 		if container.State.Running != nil {
-			containerRunning[container.Name] = true
+			containerIDs[container.Name] = stripContainerIDPrefix(container.ContainerID)
 		}
 	}
 
-	// Create endpoint for each named container port.
+	// Create endpoint for each running container and each of its named ports.
 	for _, container := range pod.Spec.Containers {
-		if !containerRunning[container.Name] {
+		containerID, running := containerIDs[container.Name]
+		if !running {
 			continue
 		}
 
+		endpoints = append(endpoints, observer.Endpoint{
+			ID:     observer.EndpointID(fmt.Sprintf("%s/%s", podID, container.Name)),
+			Target: podIP,
+			Details: &observer.PodContainer{
+				Name:        container.Name,
+				Image:       container.Image,
+				ContainerID: containerID,
+				LogPath:     containerLogPath(pod, container.Name),
+				Pod:         podDetails,
+			},
+		})
+
 		for _, port := range container.Ports {
 			endpointID := observer.EndpointID(
 				fmt.Sprintf(
@@ -74,6 +88,21 @@ func convertPodToEndpoints(idNamespace string, pod *v1.Pod) []observer.Endpoint
 	return endpoints
 }
 
+// stripContainerIDPrefix removes the runtime prefix (e.g. "containerd://") of
+// a container ID reported in the pod status.
+func stripContainerIDPrefix(containerID string) string {
+	if _, id, found := strings.Cut(containerID, "://"); found {
+		return id
+	}
+	return containerID
+}
+
+// containerLogPath returns the glob matching the log files the kubelet writes
+// for the container on the node running the pod.
+func containerLogPath(pod *v1.Pod, containerName string) string {
+	return fmt.Sprintf("/var/log/pods/%s_%s_%s/%s/*.log", pod.Namespace, pod.Name, pod.UID, containerName)
+}
+
 func getTransport(protocol v1.Protocol) observer.Transport {
 	switch protocol {
 	case v1.ProtocolTCP:
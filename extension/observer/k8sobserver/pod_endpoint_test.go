@@ -21,6 +21,19 @@ func TestPodObjectToPortEndpoint(t *testing.T) {
 				Namespace: "default",
 				UID:       "pod-2-UID",
 				Labels:    map[string]string{"env": "prod"}}},
+		{
+			ID:     "namespace/pod-2-UID/container-2",
+			Target: "1.2.3.4",
+			Details: &observer.PodContainer{
+				Name:        "container-2",
+				Image:       "container-image-2",
+				ContainerID: "a808232bfad6",
+				LogPath:     "/var/log/pods/default_pod-2_pod-2-UID/container-2/*.log",
+				Pod: observer.Pod{
+					Name:      "pod-2",
+					Namespace: "default",
+					UID:       "pod-2-UID",
+					Labels:    map[string]string{"env": "prod"}}}},
 		{
 			ID:     "namespace/pod-2-UID/https(443)",
 			Target: "1.2.3.4:443",
@@ -3,7 +3,11 @@
 
 package oidcauthextension // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/oidcauthextension"
 
-import "go.opentelemetry.io/collector/client"
+import (
+	"sort"
+
+	"go.opentelemetry.io/collector/client"
+)
 
 var _ client.AuthData = (*authData)(nil)
 
@@ -11,6 +15,8 @@ type authData struct {
 	raw        string
 	subject    string
 	membership []string
+	// attributes are the attributes mapped from the claims of the token
+	attributes map[string]any
 }
 
 func (a *authData) GetAttribute(name string) any {
@@ -22,10 +28,15 @@ func (a *authData) GetAttribute(name string) any {
 	case "raw":
 		return a.raw
 	default:
-		return nil
+		return a.attributes[name]
 	}
 }
 
-func (*authData) GetAttributeNames() []string {
-	return []string{"subject", "membership", "raw"}
+func (a *authData) GetAttributeNames() []string {
+	names := make([]string, 0, len(a.attributes))
+	for name := range a.attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return append([]string{"subject", "membership", "raw"}, names...)
 }
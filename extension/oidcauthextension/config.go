@@ -3,6 +3,12 @@
 
 package oidcauthextension // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/oidcauthextension"
 
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
 // Config has the configuration for the OIDC Authenticator extension.
 type Config struct {
 
@@ -10,11 +16,39 @@ type Config struct {
 	Attribute string `mapstructure:"attribute"`
 
 	// IssuerURL is the base URL for the OIDC provider.
-	// Required.
+	// Required, unless Providers is set.
 	IssuerURL string `mapstructure:"issuer_url"`
 
 	// Audience of the token, used during the verification.
 	// For example: "https://accounts.google.com" or "https://login.salesforce.com".
+	// Required, unless Providers is set.
+	Audience string `mapstructure:"audience"`
+
+	// The local path for the issuer CA's TLS server cert.
+	// Optional.
+	IssuerCAPath string `mapstructure:"issuer_ca_path"`
+
+	// The claim to use as the username, in case the token's 'sub' isn't the suitable source.
+	// Optional.
+	UsernameClaim string `mapstructure:"username_claim"`
+
+	// The claim that holds the subject's group membership information.
+	// Optional.
+	GroupsClaim string `mapstructure:"groups_claim"`
+
+	// Providers are the OIDC providers whose tokens are accepted, selected by the token's issuer.
+	// When IssuerURL is also set, it is accepted in addition to the providers.
+	// Optional.
+	Providers []ProviderConfig `mapstructure:"providers"`
+}
+
+// ProviderConfig has the configuration of one of the OIDC providers accepted by the extension.
+type ProviderConfig struct {
+	// IssuerURL is the base URL for the OIDC provider, matched against the token's 'iss' claim.
+	// Required.
+	IssuerURL string `mapstructure:"issuer_url"`
+
+	// Audience of the token, used during the verification.
 	// Required.
 	Audience string `mapstructure:"audience"`
 
@@ -22,6 +56,15 @@ type Config struct {
 	// Optional.
 	IssuerCAPath string `mapstructure:"issuer_ca_path"`
 
+	// JWKSFile is the local path of a JSON Web Key Set used to verify the tokens, instead of
+	// discovering the keys from the OIDC provider. The file is reloaded when it changes.
+	// Optional.
+	JWKSFile string `mapstructure:"jwks_file"`
+
+	// JWKSReloadInterval is how often the JWKS file is checked for changes.
+	// Optional, default value: 30s.
+	JWKSReloadInterval time.Duration `mapstructure:"jwks_reload_interval"`
+
 	// The claim to use as the username, in case the token's 'sub' isn't the suitable source.
 	// Optional.
 	UsernameClaim string `mapstructure:"username_claim"`
@@ -29,14 +72,74 @@ type Config struct {
 	// The claim that holds the subject's group membership information.
 	// Optional.
 	GroupsClaim string `mapstructure:"groups_claim"`
+
+	// ClaimAttributes maps the claims of the token to the auth attributes of the request,
+	// for example to route the data per tenant. Claims missing from the token are skipped.
+	// Optional.
+	ClaimAttributes map[string]string `mapstructure:"claim_attributes"`
 }
 
+// reservedAttributes are the auth attributes always set by the extension.
+var reservedAttributes = map[string]bool{"subject": true, "membership": true, "raw": true}
+
 func (c *Config) Validate() error {
-	if c.Audience == "" {
+	if len(c.Providers) == 0 || c.IssuerURL != "" || c.Audience != "" {
+		if c.Audience == "" {
+			return errNoAudienceProvided
+		}
+		if c.IssuerURL == "" {
+			return errNoIssuerURL
+		}
+	}
+
+	var errs error
+	issuers := map[string]bool{}
+	for _, p := range c.providers() {
+		if issuers[p.IssuerURL] {
+			errs = errors.Join(errs, fmt.Errorf("duplicate issuer_url %q", p.IssuerURL))
+		}
+		issuers[p.IssuerURL] = true
+		errs = errors.Join(errs, p.validate())
+	}
+	return errs
+}
+
+func (p *ProviderConfig) validate() error {
+	if p.Audience == "" {
 		return errNoAudienceProvided
 	}
-	if c.IssuerURL == "" {
+	if p.IssuerURL == "" {
 		return errNoIssuerURL
 	}
+	if p.JWKSReloadInterval < 0 {
+		return fmt.Errorf("jwks_reload_interval of issuer %q cannot be less than 0", p.IssuerURL)
+	}
+	attributes := map[string]bool{}
+	for claim, attribute := range p.ClaimAttributes {
+		if attribute == "" {
+			return fmt.Errorf("claim %q of issuer %q is mapped to an empty attribute", claim, p.IssuerURL)
+		}
+		if reservedAttributes[attribute] {
+			return fmt.Errorf("claim %q of issuer %q cannot be mapped to the reserved attribute %q", claim, p.IssuerURL, attribute)
+		}
+		if attributes[attribute] {
+			return fmt.Errorf("more than one claim of issuer %q is mapped to the attribute %q", p.IssuerURL, attribute)
+		}
+		attributes[attribute] = true
+	}
 	return nil
 }
+
+// providers returns the configured providers, including the one configured by the top-level settings.
+func (c *Config) providers() []ProviderConfig {
+	if c.IssuerURL == "" && c.Audience == "" {
+		return c.Providers
+	}
+	return append([]ProviderConfig{{
+		IssuerURL:     c.IssuerURL,
+		Audience:      c.Audience,
+		IssuerCAPath:  c.IssuerCAPath,
+		UsernameClaim: c.UsernameClaim,
+		GroupsClaim:   c.GroupsClaim,
+	}}, c.Providers...)
+}
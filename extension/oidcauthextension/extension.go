@@ -7,6 +7,8 @@ import (
 	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
 	"encoding/pem"
 	"errors"
 	"fmt"
@@ -27,12 +29,19 @@ import (
 type oidcExtension struct {
 	cfg *Config
 
-	provider *oidc.Provider
-	verifier *oidc.IDTokenVerifier
+	// issuers are the accepted issuers, by issuer URL
+	issuers map[string]*issuer
+	keySets []*fileKeySet
 
 	logger *zap.Logger
 }
 
+// issuer verifies the tokens of one of the configured providers.
+type issuer struct {
+	cfg      ProviderConfig
+	verifier *oidc.IDTokenVerifier
+}
+
 var (
 	errNoAudienceProvided                = errors.New("no Audience provided for the OIDC configuration")
 	errNoIssuerURL                       = errors.New("no IssuerURL provided for the OIDC configuration")
@@ -42,6 +51,8 @@ var (
 	errUsernameNotString                 = errors.New("the username returned by the OIDC provider isn't a regular string")
 	errGroupsClaimNotFound               = errors.New("groups claim from the OIDC configuration not found on the token returned by the OIDC provider")
 	errNotAuthenticated                  = errors.New("authentication didn't succeed")
+	errInvalidToken                      = errors.New("the token isn't a valid JWT")
+	errUnknownIssuer                     = errors.New("the token wasn't issued by any of the configured OIDC providers")
 )
 
 func newExtension(cfg *Config, logger *zap.Logger) auth.Server {
@@ -53,20 +64,46 @@ func newExtension(cfg *Config, logger *zap.Logger) auth.Server {
 		cfg:    cfg,
 		logger: logger,
 	}
-	return auth.NewServer(auth.WithServerStart(oe.start), auth.WithServerAuthenticate(oe.authenticate))
+	return auth.NewServer(
+		auth.WithServerStart(oe.start),
+		auth.WithServerAuthenticate(oe.authenticate),
+		auth.WithServerShutdown(oe.shutdown),
+	)
 }
 
 func (e *oidcExtension) start(context.Context, component.Host) error {
-	provider, err := getProviderForConfig(e.cfg)
-	if err != nil {
-		return fmt.Errorf("failed to get configuration from the auth server: %w", err)
+	e.issuers = map[string]*issuer{}
+	for _, cfg := range e.cfg.providers() {
+		verifierConfig := &oidc.Config{
+			ClientID: cfg.Audience,
+		}
+
+		if cfg.JWKSFile != "" {
+			keySet, err := newFileKeySet(cfg.JWKSFile, cfg.JWKSReloadInterval, e.logger)
+			if err != nil {
+				return fmt.Errorf("failed to load the keys of the issuer %q: %w", cfg.IssuerURL, err)
+			}
+			keySet.start()
+			e.keySets = append(e.keySets, keySet)
+			e.issuers[cfg.IssuerURL] = &issuer{cfg: cfg, verifier: oidc.NewVerifier(cfg.IssuerURL, keySet, verifierConfig)}
+			continue
+		}
+
+		provider, err := getProviderForConfig(cfg)
+		if err != nil {
+			return fmt.Errorf("failed to get configuration from the auth server: %w", err)
+		}
+		e.issuers[cfg.IssuerURL] = &issuer{cfg: cfg, verifier: provider.Verifier(verifierConfig)}
 	}
-	e.provider = provider
 
-	e.verifier = e.provider.Verifier(&oidc.Config{
-		ClientID: e.cfg.Audience,
-	})
+	return nil
+}
 
+func (e *oidcExtension) shutdown(context.Context) error {
+	for _, keySet := range e.keySets {
+		keySet.shutdown()
+	}
+	e.keySets = nil
 	return nil
 }
 
@@ -90,7 +127,16 @@ func (e *oidcExtension) authenticate(ctx context.Context, headers map[string][]s
 	}
 
 	raw := parts[1]
-	idToken, err := e.verifier.Verify(ctx, raw)
+	iss, err := getIssuerFromToken(raw)
+	if err != nil {
+		return ctx, err
+	}
+	tokenIssuer, ok := e.issuers[iss]
+	if !ok {
+		return ctx, errUnknownIssuer
+	}
+
+	idToken, err := tokenIssuer.verifier.Verify(ctx, raw)
 	if err != nil {
 		return ctx, fmt.Errorf("failed to verify token: %w", err)
 	}
@@ -106,11 +152,11 @@ func (e *oidcExtension) authenticate(ctx context.Context, headers map[string][]s
 		return ctx, errFailedToObtainClaimsFromToken
 	}
 
-	subject, err := getSubjectFromClaims(claims, e.cfg.UsernameClaim, idToken.Subject)
+	subject, err := getSubjectFromClaims(claims, tokenIssuer.cfg.UsernameClaim, idToken.Subject)
 	if err != nil {
 		return ctx, fmt.Errorf("failed to get subject from claims in the token: %w", err)
 	}
-	membership, err := getGroupsFromClaims(claims, e.cfg.GroupsClaim)
+	membership, err := getGroupsFromClaims(claims, tokenIssuer.cfg.GroupsClaim)
 	if err != nil {
 		return ctx, fmt.Errorf("failed to get groups from claims in the token: %w", err)
 	}
@@ -120,10 +166,31 @@ func (e *oidcExtension) authenticate(ctx context.Context, headers map[string][]s
 		raw:        raw,
 		subject:    subject,
 		membership: membership,
+		attributes: getAttributesFromClaims(claims, tokenIssuer.cfg.ClaimAttributes),
 	}
 	return client.NewContext(ctx, cl), nil
 }
 
+// getIssuerFromToken returns the issuer of the token, before its signature is verified,
+// to select the provider verifying it.
+func getIssuerFromToken(raw string) (string, error) {
+	parts := strings.Split(raw, ".")
+	if len(parts) != 3 {
+		return "", errInvalidToken
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", errInvalidToken
+	}
+	var claims struct {
+		Issuer string `json:"iss"`
+	}
+	if err = json.Unmarshal(payload, &claims); err != nil {
+		return "", errInvalidToken
+	}
+	return claims.Issuer, nil
+}
+
 func getSubjectFromClaims(claims map[string]any, usernameClaim string, fallback string) (string, error) {
 	if len(usernameClaim) > 0 {
 		username, found := claims[usernameClaim]
@@ -167,7 +234,21 @@ func getGroupsFromClaims(claims map[string]any, groupsClaim string) ([]string, e
 	return []string{}, nil
 }
 
-func getProviderForConfig(config *Config) (*oidc.Provider, error) {
+// getAttributesFromClaims returns the auth attributes mapped from the claims of the token.
+func getAttributesFromClaims(claims map[string]any, claimAttributes map[string]string) map[string]any {
+	if len(claimAttributes) == 0 {
+		return nil
+	}
+	attributes := make(map[string]any, len(claimAttributes))
+	for claim, attribute := range claimAttributes {
+		if value, ok := claims[claim]; ok {
+			attributes[attribute] = value
+		}
+	}
+	return attributes
+}
+
+func getProviderForConfig(config ProviderConfig) (*oidc.Provider, error) {
 	t := &http.Transport{
 		Proxy: http.ProxyFromEnvironment,
 		DialContext: (&net.Dialer{
@@ -20,6 +20,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/client"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.uber.org/zap"
 )
@@ -69,6 +70,78 @@ func TestOIDCAuthenticationSucceeded(t *testing.T) {
 	// TODO(jpkroehling): assert that the authentication routine set the subject/membership to the resource
 }
 
+func TestOIDCMultipleIssuers(t *testing.T) {
+	// prepare
+	first, err := newOIDCServer()
+	require.NoError(t, err)
+	first.Start()
+	defer first.Close()
+	second, err := newOIDCServer()
+	require.NoError(t, err)
+	second.Start()
+	defer second.Close()
+	unknown, err := newOIDCServer()
+	require.NoError(t, err)
+	unknown.Start()
+	defer unknown.Close()
+
+	config := &Config{
+		IssuerURL: first.URL,
+		Audience:  "unit-test",
+		Providers: []ProviderConfig{{
+			IssuerURL:       second.URL,
+			Audience:        "other-unit-test",
+			GroupsClaim:     "memberships",
+			ClaimAttributes: map[string]string{"tenant_id": "tenant", "missing": "missing"},
+		}},
+	}
+	p := newExtension(config, zap.NewNop())
+	require.NoError(t, p.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { require.NoError(t, p.Shutdown(context.Background())) }()
+
+	authenticate := func(server *oidcServer, claims map[string]any) (context.Context, error) {
+		claims["iss"] = server.URL
+		claims["exp"] = time.Now().Add(time.Minute).Unix()
+		payload, _ := json.Marshal(claims)
+		token, err := server.token(payload)
+		require.NoError(t, err)
+		return p.Authenticate(context.Background(), map[string][]string{"authorization": {fmt.Sprintf("Bearer %s", token)}})
+	}
+
+	// test
+	ctx, err := authenticate(first, map[string]any{"sub": "jdoe", "aud": "unit-test", "tenant_id": "acme"})
+
+	// verify
+	require.NoError(t, err)
+	authData := client.FromContext(ctx).Auth
+	assert.Equal(t, "jdoe", authData.GetAttribute("subject"))
+	assert.Nil(t, authData.GetAttribute("tenant"))
+	assert.Equal(t, []string{"subject", "membership", "raw"}, authData.GetAttributeNames())
+
+	// test, the claims of the second issuer are mapped to attributes
+	ctx, err = authenticate(second, map[string]any{"sub": "jdoe", "aud": "other-unit-test", "tenant_id": "acme", "memberships": "department-1"})
+
+	// verify
+	require.NoError(t, err)
+	authData = client.FromContext(ctx).Auth
+	assert.Equal(t, []string{"department-1"}, authData.GetAttribute("membership"))
+	assert.Equal(t, "acme", authData.GetAttribute("tenant"))
+	assert.Nil(t, authData.GetAttribute("missing"))
+	assert.Equal(t, []string{"subject", "membership", "raw", "tenant"}, authData.GetAttributeNames())
+
+	// test, the audience is specific to the issuer
+	_, err = authenticate(second, map[string]any{"sub": "jdoe", "aud": "unit-test", "memberships": "department-1"})
+
+	// verify
+	assert.Error(t, err)
+
+	// test, tokens signed by another issuer are rejected
+	_, err = authenticate(unknown, map[string]any{"sub": "jdoe", "aud": "unit-test"})
+
+	// verify
+	assert.ErrorIs(t, err, errUnknownIssuer)
+}
+
 func TestOIDCProviderForConfigWithTLS(t *testing.T) {
 	// prepare the CA cert for the TLS handler
 	cert := x509.Certificate{
@@ -105,7 +178,7 @@ func TestOIDCProviderForConfigWithTLS(t *testing.T) {
 	oidcServer.StartTLS()
 
 	// prepare the processor configuration
-	config := &Config{
+	config := ProviderConfig{
 		IssuerURL:    oidcServer.URL,
 		IssuerCAPath: caFile.Name(),
 		Audience:     "unit-test",
@@ -180,7 +253,7 @@ func TestOIDCFailedToLoadIssuerCAFromPathInvalidContent(t *testing.T) {
 	_, err = file.Write([]byte("foobar"))
 	require.NoError(t, err)
 
-	config := &Config{
+	config := ProviderConfig{
 		IssuerCAPath: file.Name(),
 	}
 
@@ -427,6 +500,80 @@ func TestMissingIssuerURL(t *testing.T) {
 	assert.Equal(t, errNoIssuerURL, err)
 }
 
+func TestConfigValidate(t *testing.T) {
+	for _, tt := range []struct {
+		casename    string
+		config      *Config
+		expectedErr string
+	}{
+		{
+			casename: "providers",
+			config: &Config{
+				Providers: []ProviderConfig{
+					{IssuerURL: "https://first.example.com", Audience: "first"},
+					{IssuerURL: "https://second.example.com", Audience: "second", JWKSFile: "jwks.json", ClaimAttributes: map[string]string{"tenant_id": "tenant"}},
+				},
+			},
+		},
+		{
+			casename: "providersWithIssuerURL",
+			config: &Config{
+				IssuerURL: "https://first.example.com",
+				Audience:  "first",
+				Providers: []ProviderConfig{{IssuerURL: "https://second.example.com", Audience: "second"}},
+			},
+		},
+		{
+			casename:    "providerWithoutAudience",
+			config:      &Config{Providers: []ProviderConfig{{IssuerURL: "https://first.example.com"}}},
+			expectedErr: errNoAudienceProvided.Error(),
+		},
+		{
+			casename:    "providerWithoutIssuerURL",
+			config:      &Config{Providers: []ProviderConfig{{Audience: "first"}}},
+			expectedErr: errNoIssuerURL.Error(),
+		},
+		{
+			casename: "duplicateIssuerURL",
+			config: &Config{
+				IssuerURL: "https://first.example.com",
+				Audience:  "first",
+				Providers: []ProviderConfig{{IssuerURL: "https://first.example.com", Audience: "second"}},
+			},
+			expectedErr: `duplicate issuer_url "https://first.example.com"`,
+		},
+		{
+			casename:    "negativeReloadInterval",
+			config:      &Config{Providers: []ProviderConfig{{IssuerURL: "https://first.example.com", Audience: "first", JWKSReloadInterval: -time.Second}}},
+			expectedErr: `jwks_reload_interval of issuer "https://first.example.com" cannot be less than 0`,
+		},
+		{
+			casename:    "reservedAttribute",
+			config:      &Config{Providers: []ProviderConfig{{IssuerURL: "https://first.example.com", Audience: "first", ClaimAttributes: map[string]string{"email": "subject"}}}},
+			expectedErr: `claim "email" of issuer "https://first.example.com" cannot be mapped to the reserved attribute "subject"`,
+		},
+		{
+			casename:    "emptyAttribute",
+			config:      &Config{Providers: []ProviderConfig{{IssuerURL: "https://first.example.com", Audience: "first", ClaimAttributes: map[string]string{"email": ""}}}},
+			expectedErr: `claim "email" of issuer "https://first.example.com" is mapped to an empty attribute`,
+		},
+		{
+			casename:    "duplicateAttribute",
+			config:      &Config{Providers: []ProviderConfig{{IssuerURL: "https://first.example.com", Audience: "first", ClaimAttributes: map[string]string{"org": "tenant", "tenant_id": "tenant"}}}},
+			expectedErr: `more than one claim of issuer "https://first.example.com" is mapped to the attribute "tenant"`,
+		},
+	} {
+		t.Run(tt.casename, func(t *testing.T) {
+			err := tt.config.Validate()
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expectedErr)
+			}
+		})
+	}
+}
+
 func TestShutdown(t *testing.T) {
 	// prepare
 	config := &Config{
@@ -0,0 +1,135 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package oidcauthextension // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/oidcauthextension"
+
+import (
+	"context"
+	"crypto"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/go-jose/go-jose/v4"
+	"go.uber.org/zap"
+)
+
+const defaultJWKSReloadInterval = 30 * time.Second
+
+var _ oidc.KeySet = (*fileKeySet)(nil)
+
+// fileKeySet verifies the tokens with the keys of a local JWKS file, reloading the keys
+// when the file changes.
+type fileKeySet struct {
+	path     string
+	interval time.Duration
+	logger   *zap.Logger
+
+	keys    atomic.Pointer[oidc.StaticKeySet]
+	modTime time.Time
+	size    int64
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+// newFileKeySet returns a key set with the keys of the file, which must be valid.
+func newFileKeySet(path string, interval time.Duration, logger *zap.Logger) (*fileKeySet, error) {
+	if interval == 0 {
+		interval = defaultJWKSReloadInterval
+	}
+	s := &fileKeySet{
+		path:     filepath.Clean(path),
+		interval: interval,
+		logger:   logger,
+		done:     make(chan struct{}),
+	}
+	if _, err := s.reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *fileKeySet) VerifySignature(ctx context.Context, jwt string) ([]byte, error) {
+	return s.keys.Load().VerifySignature(ctx, jwt)
+}
+
+// start watches the file for changes until shutdown is called.
+func (s *fileKeySet) start() {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				reloaded, err := s.reload()
+				if err != nil {
+					s.logger.Warn("failed to reload the JWKS file, keeping the previous keys", zap.String("path", s.path), zap.Error(err))
+				} else if reloaded {
+					s.logger.Info("reloaded the JWKS file", zap.String("path", s.path))
+				}
+			case <-s.done:
+				return
+			}
+		}
+	}()
+}
+
+func (s *fileKeySet) shutdown() {
+	close(s.done)
+	s.wg.Wait()
+}
+
+// reload loads the keys of the file if it changed since it was last loaded.
+func (s *fileKeySet) reload() (bool, error) {
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return false, fmt.Errorf("could not read the JWKS file %q: %w", s.path, err)
+	}
+	if s.keys.Load() != nil && info.ModTime().Equal(s.modTime) && info.Size() == s.size {
+		return false, nil
+	}
+
+	keys, err := readJWKSFile(s.path)
+	if err != nil {
+		return false, err
+	}
+	s.keys.Store(keys)
+	s.modTime = info.ModTime()
+	s.size = info.Size()
+	return true, nil
+}
+
+// readJWKSFile returns the signing keys of the JWKS file.
+func readJWKSFile(path string) (*oidc.StaticKeySet, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read the JWKS file %q: %w", path, err)
+	}
+
+	var jwks jose.JSONWebKeySet
+	if err = json.Unmarshal(raw, &jwks); err != nil {
+		return nil, fmt.Errorf("cannot decode the contents of the JWKS file %q: %w", path, err)
+	}
+
+	keys := &oidc.StaticKeySet{}
+	for _, key := range jwks.Keys {
+		// symmetric keys have no public key, and are not supported
+		public := key.Public()
+		if key.Use == "enc" || public.Key == nil {
+			continue
+		}
+		keys.PublicKeys = append(keys.PublicKeys, crypto.PublicKey(public.Key))
+	}
+	if len(keys.PublicKeys) == 0 {
+		return nil, fmt.Errorf("the JWKS file %q has no signing keys", path)
+	}
+	return keys, nil
+}
@@ -0,0 +1,117 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package oidcauthextension
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.uber.org/zap"
+)
+
+func writeJWKSFile(t *testing.T, path string, jwks map[string]any) {
+	raw, err := json.Marshal(jwks)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, raw, 0600))
+}
+
+func TestOIDCJWKSFile(t *testing.T) {
+	// prepare, the servers are only used to sign the tokens and are never reached
+	oidcServer, err := newOIDCServer()
+	require.NoError(t, err)
+	rotated, err := newOIDCServer()
+	require.NoError(t, err)
+
+	issuerURL := "https://offline.example.com"
+	jwksFile := filepath.Join(t.TempDir(), "jwks.json")
+	writeJWKSFile(t, jwksFile, oidcServer.jwks)
+
+	p := newExtension(&Config{
+		Providers: []ProviderConfig{{
+			IssuerURL:          issuerURL,
+			Audience:           "unit-test",
+			JWKSFile:           jwksFile,
+			JWKSReloadInterval: 10 * time.Millisecond,
+		}},
+	}, zap.NewNop())
+	require.NoError(t, p.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { require.NoError(t, p.Shutdown(context.Background())) }()
+
+	payload, _ := json.Marshal(map[string]any{
+		"sub": "jdoe",
+		"iss": issuerURL,
+		"aud": "unit-test",
+		"exp": time.Now().Add(time.Minute).Unix(),
+	})
+	token, err := oidcServer.token(payload)
+	require.NoError(t, err)
+	rotatedToken, err := rotated.token(payload)
+	require.NoError(t, err)
+	authenticate := func(token string) error {
+		_, err := p.Authenticate(context.Background(), map[string][]string{"authorization": {fmt.Sprintf("Bearer %s", token)}})
+		return err
+	}
+
+	// test
+	assert.NoError(t, authenticate(token))
+	assert.Error(t, authenticate(rotatedToken))
+
+	// test, the keys are reloaded when the file changes
+	writeJWKSFile(t, jwksFile, rotated.jwks)
+
+	// verify
+	assert.Eventually(t, func() bool {
+		return authenticate(rotatedToken) == nil
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Error(t, authenticate(token))
+
+	// test, an invalid file keeps the previous keys
+	require.NoError(t, os.WriteFile(jwksFile, []byte("not json"), 0600))
+	time.Sleep(50 * time.Millisecond)
+
+	// verify
+	assert.NoError(t, authenticate(rotatedToken))
+}
+
+func TestOIDCInvalidJWKSFile(t *testing.T) {
+	dir := t.TempDir()
+	invalidFile := filepath.Join(dir, "invalid.json")
+	require.NoError(t, os.WriteFile(invalidFile, []byte("not json"), 0600))
+	emptyFile := filepath.Join(dir, "empty.json")
+	writeJWKSFile(t, emptyFile, map[string]any{"keys": []any{}})
+
+	for _, tt := range []struct {
+		casename string
+		path     string
+	}{
+		{"missingFile", filepath.Join(dir, "missing.json")},
+		{"invalidFile", invalidFile},
+		{"noKeys", emptyFile},
+	} {
+		t.Run(tt.casename, func(t *testing.T) {
+			p := newExtension(&Config{
+				Providers: []ProviderConfig{{
+					IssuerURL: "https://offline.example.com",
+					Audience:  "unit-test",
+					JWKSFile:  tt.path,
+				}},
+			}, zap.NewNop())
+
+			// test
+			err := p.Start(context.Background(), componenttest.NewNopHost())
+
+			// verify
+			assert.Error(t, err)
+			assert.NoError(t, p.Shutdown(context.Background()))
+		})
+	}
+}
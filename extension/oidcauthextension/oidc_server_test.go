@@ -28,6 +28,7 @@ type oidcServer struct {
 	*httptest.Server
 	x509Cert   []byte
 	privateKey *rsa.PrivateKey
+	jwks       map[string]any
 }
 
 func newOIDCServer() (*oidcServer, error) {
@@ -82,7 +83,7 @@ func newOIDCServer() (*oidcServer, error) {
 		"x5t": base64.RawURLEncoding.EncodeToString(sum[:]),
 	}}
 
-	return &oidcServer{server, x509Cert, privateKey}, nil
+	return &oidcServer{server, x509Cert, privateKey, jwks}, nil
 }
 
 func (s *oidcServer) token(jsonPayload []byte) (string, error) {
@@ -40,6 +40,11 @@ type AgentDescription struct {
 type Capabilities struct {
 	// ReportsEffectiveConfig enables the OpAMP ReportsEffectiveConfig Capability. (default: true)
 	ReportsEffectiveConfig bool `mapstructure:"reports_effective_config"`
+	// ReportsHealth enables the OpAMP ReportsHealth Capability. (default: false)
+	ReportsHealth bool `mapstructure:"reports_health"`
+	// ReportsAvailableComponents enables reporting the components the collector
+	// was built with in a custom message. (default: false)
+	ReportsAvailableComponents bool `mapstructure:"reports_available_components"`
 }
 
 func (caps Capabilities) toAgentCapabilities() protobufs.AgentCapabilities {
@@ -50,6 +55,10 @@ func (caps Capabilities) toAgentCapabilities() protobufs.AgentCapabilities {
 		agentCapabilities |= protobufs.AgentCapabilities_AgentCapabilities_ReportsEffectiveConfig
 	}
 
+	if caps.ReportsHealth {
+		agentCapabilities |= protobufs.AgentCapabilities_AgentCapabilities_ReportsHealth
+	}
+
 	return agentCapabilities
 }
 
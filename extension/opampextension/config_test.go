@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/open-telemetry/opamp-go/protobufs"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
@@ -258,3 +259,32 @@ func TestConfig_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestCapabilities_toAgentCapabilities(t *testing.T) {
+	tests := []struct {
+		name         string
+		capabilities Capabilities
+		expected     protobufs.AgentCapabilities
+	}{
+		{
+			name:     "only status",
+			expected: protobufs.AgentCapabilities_AgentCapabilities_ReportsStatus,
+		},
+		{
+			name: "all capabilities",
+			capabilities: Capabilities{
+				ReportsEffectiveConfig:     true,
+				ReportsHealth:              true,
+				ReportsAvailableComponents: true,
+			},
+			expected: protobufs.AgentCapabilities_AgentCapabilities_ReportsStatus |
+				protobufs.AgentCapabilities_AgentCapabilities_ReportsEffectiveConfig |
+				protobufs.AgentCapabilities_AgentCapabilities_ReportsHealth,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.capabilities.toAgentCapabilities())
+		})
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package opampextension // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/opampextension"
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/open-telemetry/opamp-go/protobufs"
+	"go.opentelemetry.io/collector/component"
+)
+
+// extensionsHealthKey groups the health of the extensions, which don't belong to pipelines.
+const extensionsHealthKey = "extensions"
+
+// statusPriority orders the statuses by severity, to report the most severe status
+// of a group of components as the status of the group.
+var statusPriority = map[component.Status]int{
+	component.StatusNone:             0,
+	component.StatusOK:               1,
+	component.StatusStopped:          2,
+	component.StatusStopping:         3,
+	component.StatusStarting:         4,
+	component.StatusRecoverableError: 5,
+	component.StatusPermanentError:   6,
+	component.StatusFatalError:       7,
+}
+
+// healthTracker keeps the latest status event of each component by pipeline, and
+// converts them into the OpAMP ComponentHealth tree reported to the server.
+type healthTracker struct {
+	mu        sync.Mutex
+	startTime time.Time
+	// statuses maps the pipeline keys to the latest status event by component key.
+	statuses map[string]map[string]*component.StatusEvent
+}
+
+func newHealthTracker(startTime time.Time) *healthTracker {
+	return &healthTracker{
+		startTime: startTime,
+		statuses:  map[string]map[string]*component.StatusEvent{},
+	}
+}
+
+// update records the status event of the source component and returns the
+// resulting health of the collector.
+func (h *healthTracker) update(source *component.InstanceID, event *component.StatusEvent) *protobufs.ComponentHealth {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	componentKey := fmt.Sprintf("%s:%s", strings.ToLower(source.Kind.String()), source.ID)
+	groups := []string{extensionsHealthKey}
+	if len(source.PipelineIDs) > 0 {
+		groups = groups[:0]
+		for pipelineID := range source.PipelineIDs {
+			groups = append(groups, "pipeline:"+pipelineID.String())
+		}
+	}
+	for _, group := range groups {
+		if h.statuses[group] == nil {
+			h.statuses[group] = map[string]*component.StatusEvent{}
+		}
+		h.statuses[group][componentKey] = event
+	}
+
+	return h.health()
+}
+
+// current returns the health of the collector.
+func (h *healthTracker) current() *protobufs.ComponentHealth {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.health()
+}
+
+// health aggregates the component statuses into the health of their pipelines and
+// of the collector. Must be called with the lock held.
+func (h *healthTracker) health() *protobufs.ComponentHealth {
+	agentHealth := &protobufs.ComponentHealth{
+		StartTimeUnixNano:  uint64(h.startTime.UnixNano()),
+		ComponentHealthMap: make(map[string]*protobufs.ComponentHealth, len(h.statuses)),
+	}
+	// The collector is starting until its components report their status.
+	agentStatus := component.StatusStarting
+	if len(h.statuses) > 0 {
+		agentStatus = component.StatusNone
+	}
+	var agentStatusTime time.Time
+
+	for group, components := range h.statuses {
+		groupHealth := &protobufs.ComponentHealth{
+			ComponentHealthMap: make(map[string]*protobufs.ComponentHealth, len(components)),
+		}
+		groupStatus := component.StatusNone
+		var groupStatusTime time.Time
+
+		for key, event := range components {
+			componentHealth := &protobufs.ComponentHealth{
+				Healthy:            event.Status() == component.StatusOK,
+				Status:             event.Status().String(),
+				StatusTimeUnixNano: uint64(event.Timestamp().UnixNano()),
+			}
+			if event.Err() != nil {
+				componentHealth.LastError = event.Err().Error()
+			}
+			groupHealth.ComponentHealthMap[key] = componentHealth
+
+			if statusPriority[event.Status()] > statusPriority[groupStatus] {
+				groupStatus = event.Status()
+				groupHealth.LastError = componentHealth.LastError
+			}
+			if event.Timestamp().After(groupStatusTime) {
+				groupStatusTime = event.Timestamp()
+			}
+		}
+
+		groupHealth.Healthy = groupStatus == component.StatusOK
+		groupHealth.Status = groupStatus.String()
+		groupHealth.StatusTimeUnixNano = uint64(groupStatusTime.UnixNano())
+		agentHealth.ComponentHealthMap[group] = groupHealth
+
+		if statusPriority[groupStatus] > statusPriority[agentStatus] {
+			agentStatus = groupStatus
+			agentHealth.LastError = groupHealth.LastError
+		}
+		if groupStatusTime.After(agentStatusTime) {
+			agentStatusTime = groupStatusTime
+		}
+	}
+
+	agentHealth.Healthy = agentStatus == component.StatusOK
+	agentHealth.Status = agentStatus.String()
+	if !agentStatusTime.IsZero() {
+		agentHealth.StatusTimeUnixNano = uint64(agentStatusTime.UnixNano())
+	}
+	return agentHealth
+}
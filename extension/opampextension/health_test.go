@@ -0,0 +1,72 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package opampextension
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/component"
+	"golang.org/x/exp/maps"
+)
+
+func TestHealthTracker(t *testing.T) {
+	startTime := time.Now()
+	tracker := newHealthTracker(startTime)
+	health := tracker.current()
+	assert.False(t, health.Healthy)
+	assert.Equal(t, "StatusStarting", health.Status)
+	tracesPipeline := component.MustNewID("traces")
+	metricsPipeline := component.MustNewIDWithName("metrics", "internal")
+
+	receiver := &component.InstanceID{
+		ID:          component.MustNewID("otlp"),
+		Kind:        component.KindReceiver,
+		PipelineIDs: map[component.ID]struct{}{tracesPipeline: {}, metricsPipeline: {}},
+	}
+	exporter := &component.InstanceID{
+		ID:          component.MustNewIDWithName("otlp", "backend"),
+		Kind:        component.KindExporter,
+		PipelineIDs: map[component.ID]struct{}{tracesPipeline: {}},
+	}
+	ext := &component.InstanceID{
+		ID:   component.MustNewID("zpages"),
+		Kind: component.KindExtension,
+	}
+
+	tracker.update(receiver, component.NewStatusEvent(component.StatusOK))
+	tracker.update(ext, component.NewStatusEvent(component.StatusOK))
+	health = tracker.update(exporter, component.NewStatusEvent(component.StatusOK))
+	assert.True(t, health.Healthy)
+	assert.Equal(t, "StatusOK", health.Status)
+	assert.Equal(t, uint64(startTime.UnixNano()), health.StartTimeUnixNano)
+	assert.ElementsMatch(t,
+		[]string{"pipeline:traces", "pipeline:metrics/internal", "extensions"},
+		maps.Keys(health.ComponentHealthMap))
+	assert.ElementsMatch(t,
+		[]string{"receiver:otlp", "exporter:otlp/backend"},
+		maps.Keys(health.ComponentHealthMap["pipeline:traces"].ComponentHealthMap))
+	assert.Contains(t, health.ComponentHealthMap["extensions"].ComponentHealthMap, "extension:zpages")
+
+	health = tracker.update(exporter, component.NewRecoverableErrorEvent(errors.New("connection refused")))
+	assert.False(t, health.Healthy)
+	assert.Equal(t, "StatusRecoverableError", health.Status)
+	assert.Equal(t, "connection refused", health.LastError)
+
+	traces := health.ComponentHealthMap["pipeline:traces"]
+	assert.False(t, traces.Healthy)
+	assert.Equal(t, "StatusRecoverableError", traces.Status)
+	assert.Equal(t, "connection refused", traces.LastError)
+	exporterHealth := traces.ComponentHealthMap["exporter:otlp/backend"]
+	assert.False(t, exporterHealth.Healthy)
+	assert.Equal(t, "connection refused", exporterHealth.LastError)
+	assert.NotZero(t, exporterHealth.StatusTimeUnixNano)
+	assert.True(t, traces.ComponentHealthMap["receiver:otlp"].Healthy)
+
+	metrics := health.ComponentHealthMap["pipeline:metrics/internal"]
+	assert.True(t, metrics.Healthy)
+	assert.Equal(t, "StatusOK", metrics.Status)
+}
@@ -5,12 +5,16 @@ package opampextension // import "github.com/open-telemetry/opentelemetry-collec
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"os"
+	"path"
 	"runtime"
+	"runtime/debug"
 	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/oklog/ulid/v2"
@@ -19,6 +23,7 @@ import (
 	"github.com/open-telemetry/opamp-go/protobufs"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/confmap"
+	"go.opentelemetry.io/collector/extension"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	semconv "go.opentelemetry.io/collector/semconv/v1.18.0"
 	"go.uber.org/zap"
@@ -26,6 +31,39 @@ import (
 	"gopkg.in/yaml.v3"
 )
 
+const (
+	// availableComponentsCapability is the custom capability of the messages reporting
+	// the component types available in the collector.
+	availableComponentsCapability = "io.opentelemetry.collector.available_components"
+	// availableComponentsMessageType is the type of the custom messages holding the
+	// YAML list of the available component types by kind.
+	availableComponentsMessageType = "available_components"
+)
+
+// componentKinds are the kinds of components reported as available, by their name in the
+// module paths of the components and their section in the collector config.
+var componentKinds = []struct {
+	kind    component.Kind
+	name    string
+	section string
+}{
+	{component.KindReceiver, "receiver", "receivers"},
+	{component.KindProcessor, "processor", "processors"},
+	{component.KindExporter, "exporter", "exporters"},
+	{component.KindConnector, "connector", "connectors"},
+	{component.KindExtension, "extension", "extensions"},
+}
+
+// availableComponent is a component module the collector was built with.
+type availableComponent struct {
+	// Type is the type of the component, when its factory was found in the collector.
+	Type    string `yaml:"type,omitempty"`
+	Module  string `yaml:"module"`
+	Version string `yaml:"version"`
+}
+
+var _ extension.StatusWatcher = (*opampAgent)(nil)
+
 type opampAgent struct {
 	cfg    *Config
 	logger *zap.Logger
@@ -42,10 +80,24 @@ type opampAgent struct {
 
 	agentDescription *protobufs.AgentDescription
 
+	health *healthTracker
+
 	opampClient client.OpAMPClient
+
+	// availableComponents is the message reporting the components of the collector.
+	availableComponents *protobufs.CustomMessage
+	// componentsMu guards componentsPending.
+	componentsMu sync.Mutex
+	// componentsPending is whether the available components wait for another custom
+	// message to be sent before being sent.
+	componentsPending bool
+
+	// done is closed on shutdown, to stop sending the pending messages.
+	done chan struct{}
+	wg   sync.WaitGroup
 }
 
-func (o *opampAgent) Start(ctx context.Context, _ component.Host) error {
+func (o *opampAgent) Start(ctx context.Context, host component.Host) error {
 	header := http.Header{}
 	for k, v := range o.cfg.Server.GetHeaders() {
 		header.Set(k, string(v))
@@ -87,6 +139,28 @@ func (o *opampAgent) Start(ctx context.Context, _ component.Host) error {
 		return err
 	}
 
+	// The health must be set before starting the client when reporting health.
+	if o.capabilities.ReportsHealth {
+		if err := o.opampClient.SetHealth(o.health.current()); err != nil {
+			return err
+		}
+	}
+
+	if o.capabilities.ReportsAvailableComponents {
+		info, ok := debug.ReadBuildInfo()
+		if !ok {
+			return errors.New("cannot report the available components without the build info of the collector")
+		}
+		if o.availableComponents, err = composeAvailableComponents(info, host); err != nil {
+			return err
+		}
+		if err := o.opampClient.SetCustomCapabilities(&protobufs.CustomCapabilities{
+			Capabilities: []string{availableComponentsCapability},
+		}); err != nil {
+			return err
+		}
+	}
+
 	o.logger.Debug("Starting OpAMP client...")
 
 	if err := o.opampClient.Start(context.Background(), settings); err != nil {
@@ -100,6 +174,8 @@ func (o *opampAgent) Start(ctx context.Context, _ component.Host) error {
 
 func (o *opampAgent) Shutdown(ctx context.Context) error {
 	o.logger.Debug("OpAMP agent shutting down...")
+	close(o.done)
+	o.wg.Wait()
 	if o.opampClient == nil {
 		return nil
 	}
@@ -116,11 +192,96 @@ func (o *opampAgent) Shutdown(ctx context.Context) error {
 func (o *opampAgent) NotifyConfig(ctx context.Context, conf *confmap.Conf) error {
 	if o.capabilities.ReportsEffectiveConfig {
 		o.updateEffectiveConfig(conf)
-		return o.opampClient.UpdateEffectiveConfig(ctx)
+		if err := o.opampClient.UpdateEffectiveConfig(ctx); err != nil {
+			return err
+		}
+	}
+	if o.capabilities.ReportsAvailableComponents {
+		return o.sendAvailableComponents()
+	}
+	return nil
+}
+
+// ComponentStatusChanged reports the health of the pipelines and components of the
+// collector to the OpAMP server.
+func (o *opampAgent) ComponentStatusChanged(source *component.InstanceID, event *component.StatusEvent) {
+	if !o.capabilities.ReportsHealth {
+		return
+	}
+	if err := o.opampClient.SetHealth(o.health.update(source, event)); err != nil {
+		o.logger.Error("Failed to report the health of the collector", zap.Error(err))
 	}
+}
+
+// sendAvailableComponents sends the components the collector was built with to the OpAMP server.
+// When another custom message is pending, the components are sent in the background once it has
+// been sent, without blocking the caller, and only once however many times they are sent meanwhile.
+func (o *opampAgent) sendAvailableComponents() error {
+	o.componentsMu.Lock()
+	defer o.componentsMu.Unlock()
+	if o.componentsPending {
+		return nil
+	}
+	sent, err := o.opampClient.SendCustomMessage(o.availableComponents)
+	if !errors.Is(err, types.ErrCustomMessagePending) {
+		return err
+	}
+
+	o.componentsPending = true
+	o.wg.Add(1)
+	go func() {
+		defer o.wg.Done()
+		select {
+		case <-sent:
+		case <-o.done:
+			return
+		}
+		o.componentsMu.Lock()
+		o.componentsPending = false
+		o.componentsMu.Unlock()
+		if err := o.sendAvailableComponents(); err != nil {
+			o.logger.Error("Failed to report the available components", zap.Error(err))
+		}
+	}()
 	return nil
 }
 
+// composeAvailableComponents lists the component modules of the build info by kind. Extensions
+// can't list the factories of the collector, so the modules named after their kind, like
+// go.opentelemetry.io/collector/receiver/otlpreceiver, are reported, with the type of the
+// component when the host has a factory for the type derived from the module name.
+func composeAvailableComponents(info *debug.BuildInfo, host component.Host) (*protobufs.CustomMessage, error) {
+	available := map[string][]availableComponent{}
+	for _, dep := range info.Deps {
+		parent, name := path.Split(dep.Path)
+		for _, k := range componentKinds {
+			if !strings.HasSuffix(name, k.name) || !strings.HasSuffix(parent, "/"+k.name+"/") {
+				continue
+			}
+			c := availableComponent{Module: dep.Path, Version: dep.Version}
+			if componentType, err := component.NewType(strings.TrimSuffix(name, k.name)); err == nil && host.GetFactory(k.kind, componentType) != nil {
+				c.Type = componentType.String()
+			}
+			available[k.section] = append(available[k.section], c)
+		}
+	}
+	for _, components := range available {
+		sort.Slice(components, func(i, j int) bool {
+			return components[i].Module < components[j].Module
+		})
+	}
+
+	data, err := yaml.Marshal(available)
+	if err != nil {
+		return nil, err
+	}
+	return &protobufs.CustomMessage{
+		Capability: availableComponentsCapability,
+		Type:       availableComponentsMessageType,
+		Data:       data,
+	}, nil
+}
+
 func (o *opampAgent) updateEffectiveConfig(conf *confmap.Conf) {
 	o.eclk.Lock()
 	defer o.eclk.Unlock()
@@ -169,7 +330,9 @@ func newOpampAgent(cfg *Config, logger *zap.Logger, build component.BuildInfo, r
 		agentVersion: agentVersion,
 		instanceID:   uid,
 		capabilities: cfg.Capabilities,
+		health:       newHealthTracker(time.Now()),
 		opampClient:  cfg.Server.GetClient(logger),
+		done:         make(chan struct{}),
 	}
 
 	return agent, nil
@@ -5,20 +5,34 @@ package opampextension
 
 import (
 	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"runtime/debug"
 	"runtime"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/oklog/ulid/v2"
+	"github.com/open-telemetry/opamp-go/client"
+	clientTypes "github.com/open-telemetry/opamp-go/client/types"
 	"github.com/open-telemetry/opamp-go/protobufs"
+	"github.com/open-telemetry/opamp-go/server"
+	"github.com/open-telemetry/opamp-go/server/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/confmap"
 	"go.opentelemetry.io/collector/confmap/confmaptest"
 	"go.opentelemetry.io/collector/extension/extensiontest"
 	semconv "go.opentelemetry.io/collector/semconv/v1.18.0"
+	"go.uber.org/zap/zaptest"
 )
 
 func TestNewOpampAgent(t *testing.T) {
@@ -31,6 +45,8 @@ func TestNewOpampAgent(t *testing.T) {
 	assert.Equal(t, "test version", o.agentVersion)
 	assert.NotEmpty(t, o.instanceID.String())
 	assert.True(t, o.capabilities.ReportsEffectiveConfig)
+	assert.False(t, o.capabilities.ReportsHealth)
+	assert.False(t, o.capabilities.ReportsAvailableComponents)
 	assert.Empty(t, o.effectiveConfig)
 	assert.Nil(t, o.agentDescription)
 }
@@ -204,3 +220,230 @@ func TestStart(t *testing.T) {
 	assert.NoError(t, o.Start(context.TODO(), componenttest.NewNopHost()))
 	assert.NoError(t, o.Shutdown(context.TODO()))
 }
+
+// factoriesHost is a host with a factory for each of its component types by kind.
+type factoriesHost struct {
+	component.Host
+	factories map[component.Kind]map[string]component.Factory
+}
+
+func (h *factoriesHost) GetFactory(kind component.Kind, componentType component.Type) component.Factory {
+	return h.factories[kind][componentType.String()]
+}
+
+func TestComposeAvailableComponents(t *testing.T) {
+	info := &debug.BuildInfo{
+		Deps: []*debug.Module{
+			{Path: "go.opentelemetry.io/collector/receiver/otlpreceiver", Version: "v0.99.0"},
+			{Path: "go.opentelemetry.io/collector/receiver", Version: "v0.99.0"},
+			{Path: "go.opentelemetry.io/collector/extension/zpagesextension", Version: "v0.99.0"},
+			{Path: "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/k8sclusterreceiver", Version: "v0.99.0"},
+			{Path: "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/kafkaexporter", Version: "v0.99.0"},
+			{Path: "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza", Version: "v0.99.0"},
+			{Path: "go.uber.org/zap", Version: "v1.27.0"},
+		},
+	}
+	host := &factoriesHost{
+		Host: componenttest.NewNopHost(),
+		factories: map[component.Kind]map[string]component.Factory{
+			component.KindReceiver:  {"otlp": extensiontest.NewNopFactory()},
+			component.KindExtension: {"zpages": extensiontest.NewNopFactory()},
+			component.KindExporter:  {"kafka": extensiontest.NewNopFactory()},
+		},
+	}
+
+	msg, err := composeAvailableComponents(info, host)
+	require.NoError(t, err)
+	assert.Equal(t, availableComponentsCapability, msg.Capability)
+	assert.Equal(t, availableComponentsMessageType, msg.Type)
+	assert.YAMLEq(t, `
+receivers:
+  - module: github.com/open-telemetry/opentelemetry-collector-contrib/receiver/k8sclusterreceiver
+    version: v0.99.0
+  - type: otlp
+    module: go.opentelemetry.io/collector/receiver/otlpreceiver
+    version: v0.99.0
+exporters:
+  - type: kafka
+    module: github.com/open-telemetry/opentelemetry-collector-contrib/exporter/kafkaexporter
+    version: v0.99.0
+extensions:
+  - type: zpages
+    module: go.opentelemetry.io/collector/extension/zpagesextension
+    version: v0.99.0
+`, string(msg.Data))
+}
+
+// opampServer is an in-process OpAMP server recording the messages of the agent.
+type opampServer struct {
+	endpoint string
+
+	mu       sync.Mutex
+	messages []*protobufs.AgentToServer
+}
+
+func newOpAMPServer(t *testing.T) *opampServer {
+	srv := &opampServer{}
+	s := server.New(newLoggerFromZap(zaptest.NewLogger(t)))
+	handler, _, err := s.Attach(server.Settings{
+		Callbacks: server.CallbacksStruct{
+			OnConnectingFunc: func(_ *http.Request) types.ConnectionResponse {
+				return types.ConnectionResponse{
+					Accept: true,
+					ConnectionCallbacks: server.ConnectionCallbacksStruct{
+						OnMessageFunc: func(_ context.Context, _ types.Connection, msg *protobufs.AgentToServer) *protobufs.ServerToAgent {
+							srv.mu.Lock()
+							srv.messages = append(srv.messages, msg)
+							srv.mu.Unlock()
+							return &protobufs.ServerToAgent{InstanceUid: msg.InstanceUid}
+						},
+					},
+				}
+			},
+		},
+	})
+	require.NoError(t, err)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/opamp", handler)
+	httpSrv := httptest.NewServer(mux)
+	t.Cleanup(func() {
+		assert.NoError(t, s.Stop(context.Background()))
+		httpSrv.Close()
+	})
+	srv.endpoint = "ws://" + strings.TrimPrefix(httpSrv.URL, "http://") + "/v1/opamp"
+	return srv
+}
+
+// lastMatching returns the last message received by the server satisfying match.
+func (s *opampServer) lastMatching(match func(*protobufs.AgentToServer) bool) *protobufs.AgentToServer {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := len(s.messages) - 1; i >= 0; i-- {
+		if match(s.messages[i]) {
+			return s.messages[i]
+		}
+	}
+	return nil
+}
+
+func TestReportHealthAndAvailableComponents(t *testing.T) {
+	srv := newOpAMPServer(t)
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Server.WS = &commonFields{
+		Endpoint:   srv.endpoint,
+		TLSSetting: configtls.ClientConfig{Insecure: true},
+	}
+	cfg.Capabilities.ReportsHealth = true
+	cfg.Capabilities.ReportsAvailableComponents = true
+	set := extensiontest.NewNopCreateSettings()
+	o, err := newOpampAgent(cfg, set.Logger, set.BuildInfo, set.Resource)
+	require.NoError(t, err)
+	require.NoError(t, o.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, o.Shutdown(context.Background()))
+	})
+
+	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "effective.yaml"))
+	require.NoError(t, err)
+	require.NoError(t, o.NotifyConfig(context.Background(), cm))
+
+	exporter := &component.InstanceID{
+		ID:          component.MustNewID("otlp"),
+		Kind:        component.KindExporter,
+		PipelineIDs: map[component.ID]struct{}{component.MustNewID("traces"): {}},
+	}
+	o.ComponentStatusChanged(exporter, component.NewPermanentErrorEvent(errors.New("invalid endpoint")))
+
+	var components *protobufs.AgentToServer
+	require.Eventually(t, func() bool {
+		components = srv.lastMatching(func(msg *protobufs.AgentToServer) bool {
+			return msg.CustomMessage != nil
+		})
+		return components != nil
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, availableComponentsCapability, components.CustomMessage.Capability)
+	assert.Equal(t, o.availableComponents.Data, components.CustomMessage.Data)
+
+	var healthMsg *protobufs.AgentToServer
+	require.Eventually(t, func() bool {
+		healthMsg = srv.lastMatching(func(msg *protobufs.AgentToServer) bool {
+			return msg.Health != nil && msg.Health.Status == "StatusPermanentError"
+		})
+		return healthMsg != nil
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.False(t, healthMsg.Health.Healthy)
+	assert.Equal(t, "invalid endpoint", healthMsg.Health.ComponentHealthMap["pipeline:traces"].ComponentHealthMap["exporter:otlp"].LastError)
+	assert.NotZero(t, healthMsg.Capabilities&uint64(protobufs.AgentCapabilities_AgentCapabilities_ReportsHealth))
+}
+
+// pendingClient is an OpAMP client whose custom messages are pending until the previous
+// one is marked as sent.
+type pendingClient struct {
+	client.OpAMPClient
+
+	mu      sync.Mutex
+	sending chan struct{}
+	sent    []*protobufs.CustomMessage
+}
+
+func (c *pendingClient) SendCustomMessage(msg *protobufs.CustomMessage) (chan struct{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.sending != nil {
+		return c.sending, clientTypes.ErrCustomMessagePending
+	}
+	c.sending = make(chan struct{})
+	c.sent = append(c.sent, msg)
+	return c.sending, nil
+}
+
+func (*pendingClient) Stop(context.Context) error {
+	return nil
+}
+
+// markSent marks the message being sent as sent.
+func (c *pendingClient) markSent() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	close(c.sending)
+	c.sending = nil
+}
+
+func (c *pendingClient) sentMessages() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.sent)
+}
+
+func TestSendAvailableComponentsWhilePending(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Capabilities.ReportsEffectiveConfig = false
+	cfg.Capabilities.ReportsAvailableComponents = true
+	set := extensiontest.NewNopCreateSettings()
+	o, err := newOpampAgent(cfg, set.Logger, set.BuildInfo, set.Resource)
+	require.NoError(t, err)
+	fake := &pendingClient{}
+	o.opampClient = fake
+	o.availableComponents = &protobufs.CustomMessage{Capability: availableComponentsCapability, Type: availableComponentsMessageType}
+
+	// Another custom message is pending.
+	_, err = fake.SendCustomMessage(&protobufs.CustomMessage{Capability: "io.opentelemetry.test"})
+	require.NoError(t, err)
+
+	// The components are sent once the pending message is sent, without blocking the
+	// configuration notifications, and only once.
+	require.NoError(t, o.NotifyConfig(context.Background(), confmap.New()))
+	require.NoError(t, o.NotifyConfig(context.Background(), confmap.New()))
+	assert.Equal(t, 1, fake.sentMessages())
+	fake.markSent()
+	require.Eventually(t, func() bool {
+		return fake.sentMessages() == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, availableComponentsCapability, fake.sent[1].Capability)
+
+	// The components still pending are dropped on shutdown.
+	require.NoError(t, o.NotifyConfig(context.Background(), confmap.New()))
+	require.NoError(t, o.Shutdown(context.Background()))
+	assert.Equal(t, 2, fake.sentMessages())
+}
@@ -7,8 +7,10 @@ import (
 	"context"
 	"embed"
 	"errors"
+	"fmt"
 	"io/fs"
 	"net/http"
+	"sync"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/extension"
@@ -21,23 +23,30 @@ type remoteObserverExtension struct {
 	config   *Config
 	settings extension.CreateSettings
 	server   *http.Server
+	wg       sync.WaitGroup
 }
 
 func (s *remoteObserverExtension) Start(ctx context.Context, host component.Host) error {
 
-	htmlContent, err := fs.Sub(httpFS, "html")
+	htmlContent, err := fs.Sub(httpFS, "http")
 	if err != nil {
 		return err
 	}
+	ln, err := s.config.ServerConfig.ToListener(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to bind to address %s: %w", s.config.Endpoint, err)
+	}
 	mux := http.NewServeMux()
 	mux.Handle("/", http.FileServer(http.FS(htmlContent)))
 	s.server, err = s.config.ServerConfig.ToServer(ctx, host, s.settings.TelemetrySettings, mux)
 	if err != nil {
-		return err
+		return errors.Join(err, ln.Close())
 	}
 
+	s.wg.Add(1)
 	go func() {
-		err := s.server.ListenAndServe()
+		defer s.wg.Done()
+		err := s.server.Serve(ln)
 		if err != nil && !errors.Is(err, http.ErrServerClosed) {
 			s.settings.TelemetrySettings.ReportStatus(component.NewFatalErrorEvent(err))
 		}
@@ -49,5 +58,7 @@ func (s *remoteObserverExtension) Shutdown(_ context.Context) error {
 	if s.server == nil {
 		return nil
 	}
-	return s.server.Close()
+	err := s.server.Close()
+	s.wg.Wait()
+	return err
 }
@@ -8,6 +8,8 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"sync/atomic"
+	"time"
 
 	// Postgres driver
 	_ "github.com/jackc/pgx/v5/stdlib"
@@ -28,9 +30,16 @@ type dbStorageClient struct {
 	getQuery    *sql.Stmt
 	setQuery    *sql.Stmt
 	deleteQuery *sql.Stmt
+
+	tableName string
+	// quota limits the size and age of the stored data, or is nil when they are unbounded
+	quota *storageQuota
+	usage *atomic.Int64
+	// nextExpiry is the time from which the next operation deletes the expired entries
+	nextExpiry atomic.Int64
 }
 
-func newClient(ctx context.Context, db *sql.DB, tableName string) (*dbStorageClient, error) {
+func newClient(ctx context.Context, db *sql.DB, tableName string, quota *storageQuota) (*dbStorageClient, error) {
 	var err error
 	_, err = db.ExecContext(ctx, fmt.Sprintf(createTable, tableName))
 	if err != nil {
@@ -49,11 +58,23 @@ func newClient(ctx context.Context, db *sql.DB, tableName string) (*dbStorageCli
 	if err != nil {
 		return nil, err
 	}
-	return &dbStorageClient{db, selectQuery, setQuery, deleteQuery}, nil
+	client := &dbStorageClient{db: db, getQuery: selectQuery, setQuery: setQuery, deleteQuery: deleteQuery, tableName: tableName}
+	if quota != nil {
+		if client.usage, err = initQuota(ctx, db, tableName, quota); err != nil {
+			return nil, err
+		}
+		client.quota = quota
+	}
+	return client, nil
 }
 
 // Get will retrieve data from storage that corresponds to the specified key
 func (c *dbStorageClient) Get(ctx context.Context, key string) ([]byte, error) {
+	if c.quota != nil {
+		op := storage.GetOperation(key)
+		err := c.quotaBatch(ctx, op)
+		return op.Value, err
+	}
 	rows, err := c.getQuery.QueryContext(ctx, key)
 	if err != nil {
 		return nil, err
@@ -72,18 +93,27 @@ func (c *dbStorageClient) Get(ctx context.Context, key string) ([]byte, error) {
 
 // Set will store data. The data can be retrieved using the same key
 func (c *dbStorageClient) Set(ctx context.Context, key string, value []byte) error {
+	if c.quota != nil {
+		return c.quotaBatch(ctx, storage.SetOperation(key, value))
+	}
 	_, err := c.setQuery.ExecContext(ctx, key, value, value)
 	return err
 }
 
 // Delete will delete data associated with the specified key
 func (c *dbStorageClient) Delete(ctx context.Context, key string) error {
+	if c.quota != nil {
+		return c.quotaBatch(ctx, storage.DeleteOperation(key))
+	}
 	_, err := c.deleteQuery.ExecContext(ctx, key)
 	return err
 }
 
 // Batch executes the specified operations in order. Get operation results are updated in place
 func (c *dbStorageClient) Batch(ctx context.Context, ops ...storage.Operation) error {
+	if c.quota != nil {
+		return c.quotaBatch(ctx, ops...)
+	}
 	var err error
 	for _, op := range ops {
 		switch op.Type {
@@ -104,6 +134,43 @@ func (c *dbStorageClient) Batch(ctx context.Context, ops ...storage.Operation) e
 	return err
 }
 
+// quotaBatch executes the specified operations in a transaction, after deleting the expired
+// entries when they are due, and keeps the stored data within the quota.
+func (c *dbStorageClient) quotaBatch(ctx context.Context, ops ...storage.Operation) error {
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	qtx := &quotaTx{ctx: ctx, tx: tx, tableName: c.tableName, quota: c.quota, usage: c.usage, now: time.Now()}
+
+	if c.quota.dueForExpiry(&c.nextExpiry, qtx.now) {
+		err = qtx.expire()
+	}
+	for _, op := range ops {
+		if err != nil {
+			break
+		}
+		switch op.Type {
+		case storage.Get:
+			op.Value, err = qtx.get(op.Key)
+		case storage.Set:
+			err = qtx.put(op.Key, op.Value)
+		case storage.Delete:
+			err = qtx.remove(op.Key)
+		default:
+			err = errors.New("wrong operation type")
+		}
+	}
+
+	if err != nil {
+		qtx.commit(false)
+		return errors.Join(err, tx.Rollback())
+	}
+	err = tx.Commit()
+	qtx.commit(err == nil)
+	return err
+}
+
 // Close will close the database
 func (c *dbStorageClient) Close(_ context.Context) error {
 	if err := c.setQuery.Close(); err != nil {
@@ -5,12 +5,44 @@ package dbstorage // import "github.com/open-telemetry/opentelemetry-collector-c
 
 import (
 	"errors"
+	"fmt"
+	"time"
 )
 
 // Config defines configuration for dbstorage extension.
 type Config struct {
 	DriverName string `mapstructure:"driver,omitempty"`
 	DataSource string `mapstructure:"datasource,omitempty"`
+
+	// Quota bounds the size and age of the stored data
+	Quota *QuotaConfig `mapstructure:"quota,omitempty"`
+}
+
+// OverflowPolicy defines what happens to a write that would exceed a quota.
+type OverflowPolicy string
+
+const (
+	// OverflowReject rejects the writes exceeding a quota.
+	OverflowReject OverflowPolicy = "reject"
+	// OverflowEvictOldest deletes the least recently written entries of the client until the write fits.
+	OverflowEvictOldest OverflowPolicy = "evict_oldest"
+)
+
+// QuotaConfig defines configuration for optional bounds of the stored data.
+type QuotaConfig struct {
+	// MaxSizeMiB is the maximum total size of the keys and values stored by all the clients
+	// of the extension. Zero means no limit.
+	MaxSizeMiB int64 `mapstructure:"max_size_mib,omitempty"`
+	// MaxClientSizeMiB is the maximum total size of the keys and values stored by each client.
+	// Zero means no limit.
+	MaxClientSizeMiB int64 `mapstructure:"max_client_size_mib,omitempty"`
+	// TTL is the time after which entries that were not written again are deleted. Zero means no limit.
+	TTL time.Duration `mapstructure:"ttl,omitempty"`
+	// OnOverflow specifies what happens to writes that would exceed a size quota, rejecting them by default
+	OnOverflow OverflowPolicy `mapstructure:"on_overflow,omitempty"`
+	// ProtectedKeys are the keys that are never expired nor evicted. They default to the keys where the
+	// persistent queue of the exporters keeps its indexes.
+	ProtectedKeys []string `mapstructure:"protected_keys,omitempty"`
 }
 
 func (cfg *Config) Validate() error {
@@ -20,6 +52,24 @@ func (cfg *Config) Validate() error {
 	if cfg.DriverName == "" {
 		return errors.New("missing driver name")
 	}
+	if cfg.Quota != nil {
+		return cfg.Quota.validate()
+	}
 
 	return nil
 }
+
+func (q *QuotaConfig) validate() error {
+	if q.MaxSizeMiB < 0 || q.MaxClientSizeMiB < 0 {
+		return errors.New("quota sizes cannot be less than 0")
+	}
+	if q.TTL < 0 {
+		return errors.New("quota ttl cannot be less than 0")
+	}
+	switch q.OnOverflow {
+	case "", OverflowReject, OverflowEvictOldest:
+	default:
+		return fmt.Errorf("invalid quota on_overflow policy %q, must be %q or %q", q.OnOverflow, OverflowReject, OverflowEvictOldest)
+	}
+	return nil
+}
@@ -6,6 +6,7 @@ package dbstorage // import "github.com/open-telemetry/opentelemetry-collector-c
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -31,6 +32,26 @@ func TestConfigValidate(t *testing.T) {
 			Config{DriverName: "foo", DataSource: "bar"},
 			nil,
 		},
+		{
+			"Negative quota size",
+			Config{DriverName: "foo", DataSource: "bar", Quota: &QuotaConfig{MaxSizeMiB: -1}},
+			errors.New("quota sizes cannot be less than 0"),
+		},
+		{
+			"Negative quota ttl",
+			Config{DriverName: "foo", DataSource: "bar", Quota: &QuotaConfig{TTL: -time.Second}},
+			errors.New("quota ttl cannot be less than 0"),
+		},
+		{
+			"Invalid overflow policy",
+			Config{DriverName: "foo", DataSource: "bar", Quota: &QuotaConfig{OnOverflow: "drop_newest"}},
+			errors.New(`invalid quota on_overflow policy "drop_newest", must be "reject" or "evict_oldest"`),
+		},
+		{
+			"valid quota",
+			Config{DriverName: "foo", DataSource: "bar", Quota: &QuotaConfig{MaxSizeMiB: 1024, TTL: time.Hour, OnOverflow: OverflowEvictOldest, ProtectedKeys: []string{"checkpoint"}}},
+			nil,
+		},
 	}
 
 	for _, test := range tests {
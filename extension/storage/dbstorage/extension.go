@@ -20,17 +20,26 @@ type databaseStorage struct {
 	datasourceName string
 	logger         *zap.Logger
 	db             *sql.DB
+	quota          *storageQuota
 }
 
 // Ensure this storage extension implements the appropriate interface
 var _ storage.Extension = (*databaseStorage)(nil)
 
-func newDBStorage(logger *zap.Logger, config *Config) (extension.Extension, error) {
-	return &databaseStorage{
+func newDBStorage(params extension.CreateSettings, config *Config) (extension.Extension, error) {
+	ds := &databaseStorage{
 		driverName:     config.DriverName,
 		datasourceName: config.DataSource,
-		logger:         logger,
-	}, nil
+		logger:         params.Logger,
+	}
+	if config.Quota != nil {
+		quota, err := newStorageQuota(config.Quota, params.ID, params.TelemetrySettings)
+		if err != nil {
+			return nil, err
+		}
+		ds.quota = quota
+	}
+	return ds, nil
 }
 
 // Start opens a connection to the database
@@ -64,7 +73,7 @@ func (ds *databaseStorage) GetClient(ctx context.Context, kind component.Kind, e
 		fullName = fmt.Sprintf("%s_%s_%s_%s", kindString(kind), ent.Type(), ent.Name(), name)
 	}
 	fullName = strings.ReplaceAll(fullName, " ", "")
-	return newClient(ctx, ds.db, fullName)
+	return newClient(ctx, ds.db, fullName, ds.quota)
 }
 
 func kindString(k component.Kind) string {
@@ -33,5 +33,5 @@ func createExtension(
 	params extension.CreateSettings,
 	cfg component.Config,
 ) (extension.Extension, error) {
-	return newDBStorage(params.Logger, cfg.(*Config))
+	return newDBStorage(params, cfg.(*Config))
 }
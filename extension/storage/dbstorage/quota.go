@@ -0,0 +1,360 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package dbstorage // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/dbstorage"
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/dbstorage/internal/metadata"
+)
+
+const (
+	oneMiB = 1048576
+
+	// expiryIntervalDivisor is the fraction of the ttl after which the expired entries of a
+	// client are looked up again, rather than on every operation.
+	expiryIntervalDivisor = 10
+
+	// the metadata table records when each key was last written and the size of its entry
+	createMetadataTable     = "create table if not exists %s_metadata (key text primary key, written_at bigint, size bigint)"
+	pruneMetadataQueryText  = "delete from %[1]s_metadata where key not in (select key from %[1]s)"
+	indexMetadataQueryText  = "insert into %[1]s_metadata(key, written_at, size) select key, ?, length(key)+length(value) from %[1]s where key not in (select key from %[1]s_metadata)"
+	usageQueryText          = "select coalesce(sum(size), 0) from %s_metadata"
+	sizeQueryText           = "select size from %s_metadata where key=?"
+	expiredQueryText        = "select key, size from %s_metadata where written_at<?"
+	oldestQueryText         = "select key, size from %s_metadata where key<>? order by written_at limit ?"
+	setMetadataQueryText    = "insert into %s_metadata(key, written_at, size) values(?,?,?) on conflict(key) do update set written_at=?, size=?"
+	deleteMetadataQueryText = "delete from %s_metadata where key=?"
+	txSetQueryText          = "insert into %s(key, value) values(?,?) on conflict(key) do update set value=?"
+	txDeleteQueryText       = "delete from %s where key=?"
+	txGetQueryText          = "select value from %s where key=?"
+)
+
+var (
+	errQuotaExceeded = errors.New("storage quota exceeded")
+
+	// queueMetadataKeys are the keys where the persistent queue of the exporters keeps its read
+	// and write indexes and the items being dispatched. They are protected by default, as the
+	// queue loses track of its items without them.
+	queueMetadataKeys = []string{"ri", "wi", "di"}
+)
+
+// storageQuota enforces the quota of an extension, shared by its clients, and reports
+// the usage of the clients.
+type storageQuota struct {
+	maxBytes       int64
+	maxClientBytes int64
+	ttl            time.Duration
+	evict          bool
+	// protected are the keys that are never expired nor evicted
+	protected map[string]bool
+
+	extensionAttr attribute.KeyValue
+	evictions     metric.Int64Counter
+	expirations   metric.Int64Counter
+	rejections    metric.Int64Counter
+
+	mu sync.Mutex
+	// usage is the size of the data stored by each client, by client name
+	usage map[string]*atomic.Int64
+}
+
+func newStorageQuota(cfg *QuotaConfig, id component.ID, set component.TelemetrySettings) (*storageQuota, error) {
+	q := &storageQuota{
+		maxBytes:       cfg.MaxSizeMiB * oneMiB,
+		maxClientBytes: cfg.MaxClientSizeMiB * oneMiB,
+		ttl:            cfg.TTL,
+		evict:          cfg.OnOverflow == OverflowEvictOldest,
+		extensionAttr:  attribute.String("extension", id.String()),
+		protected:      map[string]bool{},
+		usage:          map[string]*atomic.Int64{},
+	}
+	protected := cfg.ProtectedKeys
+	if protected == nil {
+		protected = queueMetadataKeys
+	}
+	for _, key := range protected {
+		q.protected[key] = true
+	}
+
+	meter := metadata.Meter(set)
+	var err, errs error
+	_, err = meter.Int64ObservableGauge(
+		"dbstorage_usage",
+		metric.WithDescription("Size of the keys and values stored by the client"),
+		metric.WithUnit("By"),
+		metric.WithInt64Callback(func(_ context.Context, observer metric.Int64Observer) error {
+			q.mu.Lock()
+			defer q.mu.Unlock()
+			for client, usage := range q.usage {
+				observer.Observe(usage.Load(), metric.WithAttributes(q.extensionAttr, attribute.String("client", client)))
+			}
+			return nil
+		}),
+	)
+	errs = errors.Join(errs, err)
+	q.evictions, err = meter.Int64Counter(
+		"dbstorage_evictions",
+		metric.WithDescription("Number of entries deleted to keep the stored data within the quota"),
+		metric.WithUnit("{entries}"),
+	)
+	errs = errors.Join(errs, err)
+	q.expirations, err = meter.Int64Counter(
+		"dbstorage_expirations",
+		metric.WithDescription("Number of entries deleted because they were not written within the ttl"),
+		metric.WithUnit("{entries}"),
+	)
+	errs = errors.Join(errs, err)
+	q.rejections, err = meter.Int64Counter(
+		"dbstorage_rejected_writes",
+		metric.WithDescription("Number of writes rejected because they exceed the quota"),
+		metric.WithUnit("{writes}"),
+	)
+	errs = errors.Join(errs, err)
+	return q, errs
+}
+
+// clientUsage returns the usage of the client, which is reset when the client is reopened.
+func (q *storageQuota) clientUsage(client string) *atomic.Int64 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	usage, ok := q.usage[client]
+	if !ok {
+		usage = &atomic.Int64{}
+		q.usage[client] = usage
+	}
+	return usage
+}
+
+// totalUsage returns the size of the data stored by all the clients.
+func (q *storageQuota) totalUsage() int64 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	var total int64
+	for _, usage := range q.usage {
+		total += usage.Load()
+	}
+	return total
+}
+
+// dueForExpiry returns whether the expired entries of a client are to be deleted by an operation
+// made at now, which happens at most once per tenth of the ttl. nextExpiry is the time of the next
+// expiry of the client.
+func (q *storageQuota) dueForExpiry(nextExpiry *atomic.Int64, now time.Time) bool {
+	if q.ttl <= 0 {
+		return false
+	}
+	next := nextExpiry.Load()
+	return now.UnixNano() >= next && nextExpiry.CompareAndSwap(next, now.Add(q.ttl/expiryIntervalDivisor).UnixNano())
+}
+
+// initQuota creates the metadata table of the client, records the write time of the entries
+// that were written while the quota was disabled, and sets the usage of the client.
+func initQuota(ctx context.Context, db *sql.DB, tableName string, quota *storageQuota) (*atomic.Int64, error) {
+	if _, err := db.ExecContext(ctx, fmt.Sprintf(createMetadataTable, tableName)); err != nil {
+		return nil, err
+	}
+	if _, err := db.ExecContext(ctx, fmt.Sprintf(pruneMetadataQueryText, tableName)); err != nil {
+		return nil, err
+	}
+	if _, err := db.ExecContext(ctx, fmt.Sprintf(indexMetadataQueryText, tableName), time.Now().UnixNano()); err != nil {
+		return nil, err
+	}
+
+	var size int64
+	if err := db.QueryRowContext(ctx, fmt.Sprintf(usageQueryText, tableName)).Scan(&size); err != nil {
+		return nil, err
+	}
+	usage := quota.clientUsage(tableName)
+	usage.Store(size)
+	return usage, nil
+}
+
+// quotaTx applies the quota of a client to the operations of a transaction.
+type quotaTx struct {
+	ctx       context.Context
+	tx        *sql.Tx
+	tableName string
+	quota     *storageQuota
+	usage     *atomic.Int64
+	now       time.Time
+
+	// delta is the change of the client usage made by the transaction
+	delta                           int64
+	evictions, expirations, rejects int64
+}
+
+func (q *quotaTx) get(key string) ([]byte, error) {
+	var value []byte
+	err := q.tx.QueryRowContext(q.ctx, fmt.Sprintf(txGetQueryText, q.tableName), key).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return value, err
+}
+
+// expire deletes the entries that were not written within the ttl.
+func (q *quotaTx) expire() error {
+	expired, err := q.entries(fmt.Sprintf(expiredQueryText, q.tableName), q.now.Add(-q.quota.ttl).UnixNano())
+	if err != nil {
+		return err
+	}
+	for _, entry := range expired {
+		if q.quota.protected[entry.key] {
+			continue
+		}
+		if err := q.delete(entry.key, entry.size); err != nil {
+			return err
+		}
+		q.expirations++
+	}
+	return nil
+}
+
+// put stores the value, evicting the oldest entries of the client or rejecting the write
+// if it would exceed the quota.
+func (q *quotaTx) put(key string, value []byte) error {
+	entrySize := int64(len(key) + len(value))
+	size := entrySize
+	if oldSize, err := q.size(key); err != nil {
+		return err
+	} else if oldSize >= 0 {
+		size -= oldSize
+	}
+
+	for q.exceeds(size) {
+		oldest, found, err := q.oldest(key)
+		if err != nil {
+			return err
+		}
+		if !q.quota.evict || !found {
+			q.rejects++
+			return errQuotaExceeded
+		}
+		if err := q.delete(oldest.key, oldest.size); err != nil {
+			return err
+		}
+		q.evictions++
+	}
+
+	if _, err := q.tx.ExecContext(q.ctx, fmt.Sprintf(txSetQueryText, q.tableName), key, value, value); err != nil {
+		return err
+	}
+	now := q.now.UnixNano()
+	if _, err := q.tx.ExecContext(q.ctx, fmt.Sprintf(setMetadataQueryText, q.tableName), key, now, entrySize, now, entrySize); err != nil {
+		return err
+	}
+	q.delta += size
+	return nil
+}
+
+// exceeds returns whether growing the data of the client by size exceeds the quota.
+func (q *quotaTx) exceeds(size int64) bool {
+	if size <= 0 {
+		return false
+	}
+	if q.quota.maxClientBytes > 0 && q.usage.Load()+q.delta+size > q.quota.maxClientBytes {
+		return true
+	}
+	return q.quota.maxBytes > 0 && q.quota.totalUsage()+q.delta+size > q.quota.maxBytes
+}
+
+// remove deletes the entry of the key, if any.
+func (q *quotaTx) remove(key string) error {
+	size, err := q.size(key)
+	if err != nil || size < 0 {
+		return err
+	}
+	return q.delete(key, size)
+}
+
+func (q *quotaTx) delete(key string, size int64) error {
+	if _, err := q.tx.ExecContext(q.ctx, fmt.Sprintf(txDeleteQueryText, q.tableName), key); err != nil {
+		return err
+	}
+	if _, err := q.tx.ExecContext(q.ctx, fmt.Sprintf(deleteMetadataQueryText, q.tableName), key); err != nil {
+		return err
+	}
+	q.delta -= size
+	return nil
+}
+
+// size returns the recorded size of the entry of the key, or -1 if there is none.
+func (q *quotaTx) size(key string) (int64, error) {
+	var size int64
+	err := q.tx.QueryRowContext(q.ctx, fmt.Sprintf(sizeQueryText, q.tableName), key).Scan(&size)
+	if errors.Is(err, sql.ErrNoRows) {
+		return -1, nil
+	}
+	return size, err
+}
+
+// oldest returns the least recently written entry other than the one of the given key and the
+// protected entries, if any.
+func (q *quotaTx) oldest(except string) (entry, bool, error) {
+	// the protected entries may be the oldest ones
+	oldest, err := q.entries(fmt.Sprintf(oldestQueryText, q.tableName), except, len(q.quota.protected)+1)
+	if err != nil {
+		return entry{}, false, err
+	}
+	for _, e := range oldest {
+		if !q.quota.protected[e.key] {
+			return e, true, nil
+		}
+	}
+	return entry{}, false, nil
+}
+
+// entry is the key and recorded size of a stored entry.
+type entry struct {
+	key  string
+	size int64
+}
+
+// entries returns the keys and sizes of the entries returned by the query, in order.
+func (q *quotaTx) entries(query string, args ...any) ([]entry, error) {
+	rows, err := q.tx.QueryContext(q.ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []entry
+	for rows.Next() {
+		var e entry
+		if err := rows.Scan(&e.key, &e.size); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// commit applies the changes of the transaction to the client usage and reports them,
+// only reporting the rejected writes if the transaction was rolled back.
+func (q *quotaTx) commit(committed bool) {
+	attrs := metric.WithAttributes(q.quota.extensionAttr)
+	if committed {
+		q.usage.Add(q.delta)
+		if q.evictions > 0 {
+			q.quota.evictions.Add(q.ctx, q.evictions, attrs)
+		}
+		if q.expirations > 0 {
+			q.quota.expirations.Add(q.ctx, q.expirations, attrs)
+		}
+	}
+	if q.rejects > 0 {
+		q.quota.rejections.Add(q.ctx, q.rejects, attrs)
+	}
+}
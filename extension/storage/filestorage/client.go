@@ -8,7 +8,9 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -35,6 +37,13 @@ type fileStorageClient struct {
 	openTimeout     time.Duration
 	cancel          context.CancelFunc
 	closed          bool
+	// cipher encrypts the stored values, or is nil when they are stored in plaintext
+	cipher *valueCipher
+	// quota limits the size and age of the stored data, or is nil when they are unbounded
+	quota *storageQuota
+	usage *atomic.Int64
+	// nextExpiry is the time from which the next operation deletes the expired entries
+	nextExpiry atomic.Int64
 }
 
 func bboltOptions(timeout time.Duration, noSync bool) *bbolt.Options {
@@ -46,7 +55,7 @@ func bboltOptions(timeout time.Duration, noSync bool) *bbolt.Options {
 	}
 }
 
-func newClient(logger *zap.Logger, filePath string, timeout time.Duration, compactionCfg *CompactionConfig, noSync bool) (*fileStorageClient, error) {
+func newClient(logger *zap.Logger, filePath string, timeout time.Duration, compactionCfg *CompactionConfig, noSync bool, cipher *valueCipher, quota *storageQuota) (*fileStorageClient, error) {
 	options := bboltOptions(timeout, noSync)
 	db, err := bbolt.Open(filePath, 0600, options)
 	if err != nil {
@@ -62,7 +71,13 @@ func newClient(logger *zap.Logger, filePath string, timeout time.Duration, compa
 		return nil, err
 	}
 
-	client := &fileStorageClient{logger: logger, db: db, compactionCfg: compactionCfg, openTimeout: timeout}
+	client := &fileStorageClient{logger: logger, db: db, compactionCfg: compactionCfg, openTimeout: timeout, cipher: cipher, quota: quota}
+	if quota != nil {
+		if err := client.initUsage(filepath.Base(filePath)); err != nil {
+			_ = db.Close()
+			return nil, err
+		}
+	}
 	if compactionCfg.OnRebound {
 		client.startCompactionLoop(context.Background())
 	}
@@ -93,6 +108,7 @@ func (c *fileStorageClient) Delete(ctx context.Context, key string) error {
 
 // Batch executes the specified operations in order. Get operation results are updated in place
 func (c *fileStorageClient) Batch(_ context.Context, ops ...storage.Operation) error {
+	var qtx *quotaTx
 	batch := func(tx *bbolt.Tx) error {
 		bucket := tx.Bucket(defaultBucket)
 		if bucket == nil {
@@ -100,22 +116,53 @@ func (c *fileStorageClient) Batch(_ context.Context, ops ...storage.Operation) e
 		}
 
 		var err error
+		if c.quota != nil {
+			if qtx, err = newQuotaTx(tx, c.quota, c.usage); err != nil {
+				return err
+			}
+			if c.quota.dueForExpiry(&c.nextExpiry, qtx.now) {
+				if err = qtx.expire(); err != nil {
+					return err
+				}
+			}
+		}
 		for _, op := range ops {
 			switch op.Type {
 			case storage.Get:
 				value := bucket.Get([]byte(op.Key))
-				if value != nil {
+				switch {
+				case value == nil:
+					op.Value = nil
+				case c.cipher != nil:
+					// decrypting copies the value, which is only valid within the transaction
+					op.Value, err = c.cipher.decrypt(op.Key, value)
+					if err != nil {
+						err = fmt.Errorf("failed to decrypt value of key %q: %w", op.Key, err)
+					}
+				default:
 					// the output of Bucket.Get is only valid within a transaction, so we need to make a copy
 					// to be able to return the value
 					op.Value = make([]byte, len(value))
 					copy(op.Value, value)
-				} else {
-					op.Value = nil
 				}
 			case storage.Set:
-				err = bucket.Put([]byte(op.Key), op.Value)
+				value := op.Value
+				if c.cipher != nil {
+					if value, err = c.cipher.encrypt(op.Key, op.Value); err != nil {
+						return err
+					}
+				}
+				if qtx != nil {
+					err = qtx.put([]byte(op.Key), value)
+				} else {
+					err = bucket.Put([]byte(op.Key), value)
+				}
 			case storage.Delete:
-				err = bucket.Delete([]byte(op.Key))
+				if qtx != nil {
+					err = qtx.delete([]byte(op.Key))
+				} else {
+					err = bucket.Delete([]byte(op.Key))
+				}
 			default:
 				return errors.New("wrong operation type")
 			}
@@ -130,7 +177,28 @@ func (c *fileStorageClient) Batch(_ context.Context, ops ...storage.Operation) e
 
 	c.compactionMutex.RLock()
 	defer c.compactionMutex.RUnlock()
-	return c.db.Update(batch)
+	err := c.db.Update(batch)
+	if qtx != nil {
+		qtx.commit(err == nil)
+	}
+	return err
+}
+
+// initUsage indexes the write times of the stored data and sets the usage of the client.
+func (c *fileStorageClient) initUsage(name string) error {
+	c.usage = c.quota.clientUsage(name)
+	return c.db.Update(func(tx *bbolt.Tx) error {
+		qtx, err := newQuotaTx(tx, c.quota, c.usage)
+		if err != nil {
+			return err
+		}
+		usage, err := qtx.index()
+		if err != nil {
+			return err
+		}
+		c.usage.Store(usage)
+		return nil
+	})
 }
 
 // Close will close the database
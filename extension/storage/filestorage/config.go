@@ -20,6 +20,57 @@ type Config struct {
 
 	// FSync specifies that fsync should be called after each database write
 	FSync bool `mapstructure:"fsync,omitempty"`
+
+	// Encryption specifies that stored values are encrypted with AES-GCM
+	Encryption *EncryptionConfig `mapstructure:"encryption,omitempty"`
+
+	// Quota bounds the size and age of the stored data
+	Quota *QuotaConfig `mapstructure:"quota,omitempty"`
+}
+
+// OverflowPolicy defines what happens to a write that would exceed a quota.
+type OverflowPolicy string
+
+const (
+	// OverflowReject rejects the writes exceeding a quota.
+	OverflowReject OverflowPolicy = "reject"
+	// OverflowEvictOldest deletes the least recently written entries of the client until the write fits.
+	OverflowEvictOldest OverflowPolicy = "evict_oldest"
+)
+
+// QuotaConfig defines configuration for optional bounds of the stored data.
+type QuotaConfig struct {
+	// MaxSizeMiB is the maximum total size of the keys and values stored by all the clients
+	// of the extension. Zero means no limit.
+	MaxSizeMiB int64 `mapstructure:"max_size_mib,omitempty"`
+	// MaxClientSizeMiB is the maximum total size of the keys and values stored by each client.
+	// Zero means no limit.
+	MaxClientSizeMiB int64 `mapstructure:"max_client_size_mib,omitempty"`
+	// TTL is the time after which entries that were not written again are deleted. Zero means no limit.
+	TTL time.Duration `mapstructure:"ttl,omitempty"`
+	// OnOverflow specifies what happens to writes that would exceed a size quota, rejecting them by default
+	OnOverflow OverflowPolicy `mapstructure:"on_overflow,omitempty"`
+	// ProtectedKeys are the keys that are never expired nor evicted. They default to the keys where the
+	// persistent queue of the exporters keeps its indexes.
+	ProtectedKeys []string `mapstructure:"protected_keys,omitempty"`
+}
+
+// EncryptionConfig defines configuration for optional encryption of the stored values.
+type EncryptionConfig struct {
+	// Key is the key used to encrypt the values that are written
+	Key KeyConfig `mapstructure:"key"`
+	// PreviousKeys are the keys the values written before a key rotation were encrypted with.
+	// They are only used to decrypt these values.
+	PreviousKeys []KeyConfig `mapstructure:"previous_keys,omitempty"`
+}
+
+// KeyConfig defines where a base64 encoded AES-128, AES-192 or AES-256 key is read from.
+// Exactly one of File and Env must be set.
+type KeyConfig struct {
+	// File is the path of the file containing the key
+	File string `mapstructure:"file,omitempty"`
+	// Env is the name of the environment variable containing the key
+	Env string `mapstructure:"env,omitempty"`
 }
 
 // CompactionConfig defines configuration for optional file storage compaction.
@@ -79,5 +130,44 @@ func (cfg *Config) Validate() error {
 		return errors.New("compaction check interval must be positive when rebound compaction is set")
 	}
 
+	if cfg.Quota != nil {
+		if err := cfg.Quota.validate(); err != nil {
+			return err
+		}
+	}
+
+	if cfg.Encryption != nil {
+		if err := cfg.Encryption.Key.validate(); err != nil {
+			return fmt.Errorf("invalid encryption key: %w", err)
+		}
+		for i, key := range cfg.Encryption.PreviousKeys {
+			if err := key.validate(); err != nil {
+				return fmt.Errorf("invalid previous encryption key %d: %w", i, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (q *QuotaConfig) validate() error {
+	if q.MaxSizeMiB < 0 || q.MaxClientSizeMiB < 0 {
+		return errors.New("quota sizes cannot be less than 0")
+	}
+	if q.TTL < 0 {
+		return errors.New("quota ttl cannot be less than 0")
+	}
+	switch q.OnOverflow {
+	case "", OverflowReject, OverflowEvictOldest:
+	default:
+		return fmt.Errorf("invalid quota on_overflow policy %q, must be %q or %q", q.OnOverflow, OverflowReject, OverflowEvictOldest)
+	}
+	return nil
+}
+
+func (k KeyConfig) validate() error {
+	if (k.File == "") == (k.Env == "") {
+		return errors.New("exactly one of file and env must be set")
+	}
 	return nil
 }
@@ -50,6 +50,33 @@ func TestLoadConfig(t *testing.T) {
 				FSync:   true,
 			},
 		},
+		{
+			id: component.NewIDWithName(metadata.Type, "encryption"),
+			expected: func() component.Config {
+				ret := NewFactory().CreateDefaultConfig().(*Config)
+				ret.Directory = "."
+				ret.Encryption = &EncryptionConfig{
+					Key:          KeyConfig{Env: "FILESTORAGE_KEY"},
+					PreviousKeys: []KeyConfig{{File: "/etc/otelcol/filestorage.key"}},
+				}
+				return ret
+			}(),
+		},
+		{
+			id: component.NewIDWithName(metadata.Type, "quota"),
+			expected: func() component.Config {
+				ret := NewFactory().CreateDefaultConfig().(*Config)
+				ret.Directory = "."
+				ret.Quota = &QuotaConfig{
+					MaxSizeMiB:       1024,
+					MaxClientSizeMiB: 256,
+					TTL:              24 * time.Hour,
+					OnOverflow:       OverflowEvictOldest,
+					ProtectedKeys:    []string{"ri", "wi", "di", "checkpoint"},
+				}
+				return ret
+			}(),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.id.String(), func(t *testing.T) {
@@ -94,3 +121,33 @@ func TestHandleProvidingFilePathAsDirWithAnError(t *testing.T) {
 	require.Error(t, err)
 	require.EqualError(t, err, file.Name()+" is not a directory")
 }
+
+func TestHandleInvalidEncryptionKeyWithAnError(t *testing.T) {
+	f := NewFactory()
+	cfg := f.CreateDefaultConfig().(*Config)
+	cfg.Directory = t.TempDir()
+
+	cfg.Encryption = &EncryptionConfig{}
+	require.EqualError(t, component.ValidateConfig(cfg), "invalid encryption key: exactly one of file and env must be set")
+
+	cfg.Encryption = &EncryptionConfig{
+		Key:          KeyConfig{Env: "FILESTORAGE_KEY"},
+		PreviousKeys: []KeyConfig{{File: "key", Env: "FILESTORAGE_PREVIOUS_KEY"}},
+	}
+	require.EqualError(t, component.ValidateConfig(cfg), "invalid previous encryption key 0: exactly one of file and env must be set")
+}
+
+func TestHandleInvalidQuotaWithAnError(t *testing.T) {
+	f := NewFactory()
+	cfg := f.CreateDefaultConfig().(*Config)
+	cfg.Directory = t.TempDir()
+
+	cfg.Quota = &QuotaConfig{MaxClientSizeMiB: -1}
+	require.EqualError(t, component.ValidateConfig(cfg), "quota sizes cannot be less than 0")
+
+	cfg.Quota = &QuotaConfig{TTL: -time.Second}
+	require.EqualError(t, component.ValidateConfig(cfg), "quota ttl cannot be less than 0")
+
+	cfg.Quota = &QuotaConfig{OnOverflow: "drop_newest"}
+	require.EqualError(t, component.ValidateConfig(cfg), `invalid quota on_overflow policy "drop_newest", must be "reject" or "evict_oldest"`)
+}
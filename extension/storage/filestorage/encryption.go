@@ -0,0 +1,122 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package filestorage // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/filestorage"
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+const (
+	// encryptedValueVersion is the first byte of the encrypted values, identifying their format:
+	// the version, the ID of the key, the nonce and the AES-GCM sealed value.
+	encryptedValueVersion byte = 1
+	// keyIDSize is the size of the key IDs, which are a prefix of the SHA-256 hash of the keys.
+	keyIDSize = 4
+)
+
+var (
+	errNotEncrypted = errors.New("value is not encrypted")
+	errUnknownKey   = errors.New("value is encrypted with an unknown key")
+)
+
+// valueCipher encrypts the stored values with the current key, and decrypts them with the
+// key they were encrypted with, as long as it is the current or one of the previous keys.
+// The storage key is authenticated along with the value, so that values can't be swapped.
+type valueCipher struct {
+	currentKeyID string
+	aeads        map[string]cipher.AEAD
+}
+
+func newValueCipher(cfg *EncryptionConfig) (*valueCipher, error) {
+	c := &valueCipher{aeads: make(map[string]cipher.AEAD, 1+len(cfg.PreviousKeys))}
+	for i, keyCfg := range append([]KeyConfig{cfg.Key}, cfg.PreviousKeys...) {
+		key, err := keyCfg.load()
+		if err != nil {
+			return nil, err
+		}
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+
+		hash := sha256.Sum256(key)
+		keyID := string(hash[:keyIDSize])
+		if i == 0 {
+			c.currentKeyID = keyID
+		}
+		c.aeads[keyID] = aead
+	}
+	return c, nil
+}
+
+// load reads and decodes the key.
+func (k KeyConfig) load() ([]byte, error) {
+	var encoded string
+	if k.File != "" {
+		b, err := os.ReadFile(k.File)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read encryption key: %w", err)
+		}
+		encoded = string(b)
+	} else {
+		var ok bool
+		if encoded, ok = os.LookupEnv(k.Env); !ok {
+			return nil, fmt.Errorf("environment variable %s of the encryption key is not set", k.Env)
+		}
+	}
+
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("encryption key is not base64 encoded: %w", err)
+	}
+	switch len(key) {
+	case 16, 24, 32:
+		return key, nil
+	default:
+		return nil, fmt.Errorf("encryption key must be 16, 24 or 32 bytes long, got %d bytes", len(key))
+	}
+}
+
+func (c *valueCipher) encrypt(key string, value []byte) ([]byte, error) {
+	aead := c.aeads[c.currentKeyID]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	encrypted := make([]byte, 0, 1+keyIDSize+len(nonce)+len(value)+aead.Overhead())
+	encrypted = append(encrypted, encryptedValueVersion)
+	encrypted = append(encrypted, c.currentKeyID...)
+	encrypted = append(encrypted, nonce...)
+	return aead.Seal(encrypted, nonce, value, []byte(key)), nil
+}
+
+func (c *valueCipher) decrypt(key string, value []byte) ([]byte, error) {
+	if len(value) < 1+keyIDSize || value[0] != encryptedValueVersion {
+		return nil, errNotEncrypted
+	}
+	aead, ok := c.aeads[string(value[1:1+keyIDSize])]
+	if !ok {
+		return nil, errUnknownKey
+	}
+
+	sealed := value[1+keyIDSize:]
+	if len(sealed) < aead.NonceSize() {
+		return nil, errNotEncrypted
+	}
+	// decrypt into a non-nil slice, so that empty values are not returned as missing ones
+	return aead.Open([]byte{}, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], []byte(key))
+}
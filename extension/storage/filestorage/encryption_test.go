@@ -0,0 +1,198 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package filestorage
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+	"go.opentelemetry.io/collector/extension/extensiontest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/storagetest"
+)
+
+// newKeyFile writes a new random base64 encoded AES-256 key to a file.
+func newKeyFile(t *testing.T) KeyConfig {
+	key := make([]byte, 32)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+	file := filepath.Join(t.TempDir(), "key")
+	require.NoError(t, os.WriteFile(file, []byte(base64.StdEncoding.EncodeToString(key)+"\n"), 0600))
+	return KeyConfig{File: file}
+}
+
+func newEncryptedExtension(t *testing.T, dir string, encryption *EncryptionConfig) storage.Extension {
+	f := NewFactory()
+	cfg := f.CreateDefaultConfig().(*Config)
+	cfg.Directory = dir
+	cfg.Encryption = encryption
+
+	ext, err := f.CreateExtension(context.Background(), extensiontest.NewNopCreateSettings(), cfg)
+	require.NoError(t, err)
+	require.NoError(t, ext.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		require.NoError(t, ext.Shutdown(context.Background()))
+	})
+	return ext.(storage.Extension)
+}
+
+func TestEncryptedStorage(t *testing.T) {
+	ext := newEncryptedExtension(t, t.TempDir(), &EncryptionConfig{Key: newKeyFile(t)})
+	storagetest.VerifyExtension(t, ext)
+}
+
+func TestEncryptedValuesAreNotStoredInPlaintext(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	ext := newEncryptedExtension(t, dir, &EncryptionConfig{Key: newKeyFile(t)})
+
+	client, err := ext.GetClient(ctx, component.KindExporter, newTestEntity("my_component"), "")
+	require.NoError(t, err)
+	secret := bytes.Repeat([]byte("user@example.com "), 100)
+	require.NoError(t, client.Set(ctx, "key", secret))
+	require.NoError(t, client.Close(ctx))
+
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	contents, err := os.ReadFile(filepath.Join(dir, files[0].Name()))
+	require.NoError(t, err)
+	assert.False(t, bytes.Contains(contents, []byte("user@example.com")))
+}
+
+func TestEncryptionKeyRotation(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	oldKey := newKeyFile(t)
+	newKey := KeyConfig{Env: "FILESTORAGE_TEST_ENCRYPTION_KEY"}
+	newKeyContent, err := os.ReadFile(newKeyFile(t).File)
+	require.NoError(t, err)
+	t.Setenv(newKey.Env, string(newKeyContent))
+
+	oldExt := newEncryptedExtension(t, dir, &EncryptionConfig{Key: oldKey})
+	client, err := oldExt.GetClient(ctx, component.KindReceiver, newTestEntity("my_component"), "")
+	require.NoError(t, err)
+	require.NoError(t, client.Set(ctx, "old", []byte("written with the old key")))
+	require.NoError(t, client.Close(ctx))
+
+	// Values written with the old key are read with the previous keys, new values are written with the new key
+	rotatedExt := newEncryptedExtension(t, dir, &EncryptionConfig{Key: newKey, PreviousKeys: []KeyConfig{oldKey}})
+	client, err = rotatedExt.GetClient(ctx, component.KindReceiver, newTestEntity("my_component"), "")
+	require.NoError(t, err)
+	value, err := client.Get(ctx, "old")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("written with the old key"), value)
+	require.NoError(t, client.Set(ctx, "new", []byte("written with the new key")))
+	require.NoError(t, client.Close(ctx))
+
+	// Once the old key is removed, only the values written with the new key can be read
+	newExt := newEncryptedExtension(t, dir, &EncryptionConfig{Key: newKey})
+	client, err = newExt.GetClient(ctx, component.KindReceiver, newTestEntity("my_component"), "")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, client.Close(ctx))
+	})
+	value, err = client.Get(ctx, "new")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("written with the new key"), value)
+	_, err = client.Get(ctx, "old")
+	assert.ErrorIs(t, err, errUnknownKey)
+}
+
+func TestEncryptedValuesCannotBeSwapped(t *testing.T) {
+	cipher, err := newValueCipher(&EncryptionConfig{Key: newKeyFile(t)})
+	require.NoError(t, err)
+
+	encrypted, err := cipher.encrypt("key", []byte("value"))
+	require.NoError(t, err)
+	value, err := cipher.decrypt("key", encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("value"), value)
+
+	_, err = cipher.decrypt("other_key", encrypted)
+	assert.Error(t, err)
+	_, err = cipher.decrypt("key", []byte("plaintext value"))
+	assert.ErrorIs(t, err, errNotEncrypted)
+}
+
+func TestEncryptedCompaction(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	ext := newEncryptedExtension(t, dir, &EncryptionConfig{Key: newKeyFile(t)})
+
+	client, err := ext.GetClient(ctx, component.KindReceiver, newTestEntity("my_component"), "")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, client.Close(ctx))
+	})
+
+	entry := make([]byte, 512)
+	for i := 0; i < 50; i++ {
+		require.NoError(t, client.Set(ctx, fmt.Sprintf("key_%d", i), entry))
+	}
+	for i := 0; i < 40; i++ {
+		require.NoError(t, client.Delete(ctx, fmt.Sprintf("key_%d", i)))
+	}
+
+	c, ok := client.(*fileStorageClient)
+	require.True(t, ok)
+	require.NoError(t, c.Compact(dir, c.openTimeout, 1))
+
+	for i := 40; i < 50; i++ {
+		value, err := client.Get(ctx, fmt.Sprintf("key_%d", i))
+		require.NoError(t, err)
+		assert.Equal(t, entry, value)
+	}
+}
+
+func TestNewValueCipherErrors(t *testing.T) {
+	shortKeyFile := filepath.Join(t.TempDir(), "short")
+	require.NoError(t, os.WriteFile(shortKeyFile, []byte(base64.StdEncoding.EncodeToString([]byte("short"))), 0600))
+	notEncodedKeyFile := filepath.Join(t.TempDir(), "not_encoded")
+	require.NoError(t, os.WriteFile(notEncodedKeyFile, []byte("not base64!"), 0600))
+
+	tests := []struct {
+		name          string
+		key           KeyConfig
+		expectedError string
+	}{
+		{
+			name:          "missing file",
+			key:           KeyConfig{File: filepath.Join(t.TempDir(), "missing")},
+			expectedError: "failed to read encryption key",
+		},
+		{
+			name:          "missing environment variable",
+			key:           KeyConfig{Env: "FILESTORAGE_TEST_MISSING_KEY"},
+			expectedError: "environment variable FILESTORAGE_TEST_MISSING_KEY of the encryption key is not set",
+		},
+		{
+			name:          "invalid key size",
+			key:           KeyConfig{File: shortKeyFile},
+			expectedError: "encryption key must be 16, 24 or 32 bytes long, got 5 bytes",
+		},
+		{
+			name:          "not base64 encoded",
+			key:           KeyConfig{File: notEncodedKeyFile},
+			expectedError: "encryption key is not base64 encoded",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newValueCipher(&EncryptionConfig{Key: tt.key})
+			assert.ErrorContains(t, err, tt.expectedError)
+		})
+	}
+}
@@ -28,16 +28,33 @@ var replaceUnsafeCharactersFeatureGate = featuregate.GlobalRegistry().MustRegist
 type localFileStorage struct {
 	cfg    *Config
 	logger *zap.Logger
+	cipher *valueCipher
+	quota  *storageQuota
 }
 
 // Ensure this storage extension implements the appropriate interface
 var _ storage.Extension = (*localFileStorage)(nil)
 
-func newLocalFileStorage(logger *zap.Logger, config *Config) (extension.Extension, error) {
-	return &localFileStorage{
+func newLocalFileStorage(params extension.CreateSettings, config *Config) (extension.Extension, error) {
+	lfs := &localFileStorage{
 		cfg:    config,
-		logger: logger,
-	}, nil
+		logger: params.Logger,
+	}
+	if config.Encryption != nil {
+		cipher, err := newValueCipher(config.Encryption)
+		if err != nil {
+			return nil, err
+		}
+		lfs.cipher = cipher
+	}
+	if config.Quota != nil {
+		quota, err := newStorageQuota(config.Quota, params.ID, params.TelemetrySettings)
+		if err != nil {
+			return nil, err
+		}
+		lfs.quota = quota
+	}
+	return lfs, nil
 }
 
 // Start does nothing
@@ -65,7 +82,7 @@ func (lfs *localFileStorage) GetClient(_ context.Context, kind component.Kind, e
 		rawName = sanitize(rawName)
 	}
 	absoluteName := filepath.Join(lfs.cfg.Directory, rawName)
-	client, err := newClient(lfs.logger, absoluteName, lfs.cfg.Timeout, lfs.cfg.Compaction, !lfs.cfg.FSync)
+	client, err := newClient(lfs.logger, absoluteName, lfs.cfg.Timeout, lfs.cfg.Compaction, !lfs.cfg.FSync, lfs.cipher, lfs.quota)
 
 	if err != nil {
 		return nil, err
@@ -0,0 +1,357 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package filestorage // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/filestorage"
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.etcd.io/bbolt"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/filestorage/internal/metadata"
+)
+
+var (
+	// writeTimesBucket maps the keys to the time they were last written at
+	writeTimesBucket = []byte(`write_times`)
+	// writeOrderBucket indexes the keys by the time they were last written at, to find the
+	// oldest entries. Its keys are the big endian write time followed by the key.
+	writeOrderBucket = []byte(`write_order`)
+
+	errQuotaExceeded = errors.New("storage quota exceeded")
+
+	// queueMetadataKeys are the keys where the persistent queue of the exporters keeps its read
+	// and write indexes and the items being dispatched. They are protected by default, as the
+	// queue loses track of its items without them.
+	queueMetadataKeys = []string{"ri", "wi", "di"}
+)
+
+const (
+	timestampSize = 8
+
+	// expiryIntervalDivisor is the fraction of the ttl after which the expired entries of a
+	// client are looked up again, rather than on every operation.
+	expiryIntervalDivisor = 10
+)
+
+// storageQuota enforces the quota of an extension, shared by its clients, and reports
+// the usage of the clients.
+type storageQuota struct {
+	maxBytes       int64
+	maxClientBytes int64
+	ttl            time.Duration
+	evict          bool
+	// protected are the keys that are never expired nor evicted
+	protected map[string]bool
+
+	extensionAttr attribute.KeyValue
+	evictions     metric.Int64Counter
+	expirations   metric.Int64Counter
+	rejections    metric.Int64Counter
+
+	mu sync.Mutex
+	// usage is the size of the data stored by each client, by client name
+	usage map[string]*atomic.Int64
+}
+
+func newStorageQuota(cfg *QuotaConfig, id component.ID, set component.TelemetrySettings) (*storageQuota, error) {
+	q := &storageQuota{
+		maxBytes:       cfg.MaxSizeMiB * oneMiB,
+		maxClientBytes: cfg.MaxClientSizeMiB * oneMiB,
+		ttl:            cfg.TTL,
+		evict:          cfg.OnOverflow == OverflowEvictOldest,
+		extensionAttr:  attribute.String("extension", id.String()),
+		protected:      map[string]bool{},
+		usage:          map[string]*atomic.Int64{},
+	}
+	protected := cfg.ProtectedKeys
+	if protected == nil {
+		protected = queueMetadataKeys
+	}
+	for _, key := range protected {
+		q.protected[key] = true
+	}
+
+	meter := metadata.Meter(set)
+	var err, errs error
+	_, err = meter.Int64ObservableGauge(
+		"filestorage_usage",
+		metric.WithDescription("Size of the keys and values stored by the client"),
+		metric.WithUnit("By"),
+		metric.WithInt64Callback(func(_ context.Context, observer metric.Int64Observer) error {
+			q.mu.Lock()
+			defer q.mu.Unlock()
+			for client, usage := range q.usage {
+				observer.Observe(usage.Load(), metric.WithAttributes(q.extensionAttr, attribute.String("client", client)))
+			}
+			return nil
+		}),
+	)
+	errs = errors.Join(errs, err)
+	q.evictions, err = meter.Int64Counter(
+		"filestorage_evictions",
+		metric.WithDescription("Number of entries deleted to keep the stored data within the quota"),
+		metric.WithUnit("{entries}"),
+	)
+	errs = errors.Join(errs, err)
+	q.expirations, err = meter.Int64Counter(
+		"filestorage_expirations",
+		metric.WithDescription("Number of entries deleted because they were not written within the ttl"),
+		metric.WithUnit("{entries}"),
+	)
+	errs = errors.Join(errs, err)
+	q.rejections, err = meter.Int64Counter(
+		"filestorage_rejected_writes",
+		metric.WithDescription("Number of writes rejected because they exceed the quota"),
+		metric.WithUnit("{writes}"),
+	)
+	errs = errors.Join(errs, err)
+	return q, errs
+}
+
+// clientUsage returns the usage of the client, which is reset when the client is reopened.
+func (q *storageQuota) clientUsage(client string) *atomic.Int64 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	usage, ok := q.usage[client]
+	if !ok {
+		usage = &atomic.Int64{}
+		q.usage[client] = usage
+	}
+	return usage
+}
+
+// totalUsage returns the size of the data stored by all the clients.
+func (q *storageQuota) totalUsage() int64 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	var total int64
+	for _, usage := range q.usage {
+		total += usage.Load()
+	}
+	return total
+}
+
+// dueForExpiry returns whether the expired entries of a client are to be deleted by an operation
+// made at now, which happens at most once per tenth of the ttl. nextExpiry is the time of the next
+// expiry of the client.
+func (q *storageQuota) dueForExpiry(nextExpiry *atomic.Int64, now time.Time) bool {
+	if q.ttl <= 0 {
+		return false
+	}
+	next := nextExpiry.Load()
+	return now.UnixNano() >= next && nextExpiry.CompareAndSwap(next, now.Add(q.ttl/expiryIntervalDivisor).UnixNano())
+}
+
+func entrySize(key, value []byte) int64 {
+	return int64(len(key) + len(value))
+}
+
+func encodeTimestamp(t time.Time) []byte {
+	b := make([]byte, timestampSize)
+	binary.BigEndian.PutUint64(b, uint64(t.UnixNano()))
+	return b
+}
+
+// quotaTx applies the quota of a client to the operations of a transaction.
+type quotaTx struct {
+	quota  *storageQuota
+	usage  *atomic.Int64
+	now    time.Time
+	bucket *bbolt.Bucket
+	times  *bbolt.Bucket
+	order  *bbolt.Bucket
+
+	// delta is the change of the client usage made by the transaction
+	delta                           int64
+	evictions, expirations, rejects int64
+}
+
+func newQuotaTx(tx *bbolt.Tx, quota *storageQuota, usage *atomic.Int64) (*quotaTx, error) {
+	times, err := tx.CreateBucketIfNotExists(writeTimesBucket)
+	if err != nil {
+		return nil, err
+	}
+	order, err := tx.CreateBucketIfNotExists(writeOrderBucket)
+	if err != nil {
+		return nil, err
+	}
+	return &quotaTx{
+		quota:  quota,
+		usage:  usage,
+		now:    time.Now(),
+		bucket: tx.Bucket(defaultBucket),
+		times:  times,
+		order:  order,
+	}, nil
+}
+
+// index records the write times of the stored keys that don't have one, which were written
+// while the quota was disabled, removes the write times of the deleted keys, and returns the
+// size of the stored data.
+func (q *quotaTx) index() (int64, error) {
+	var deleted [][]byte
+	if err := q.times.ForEach(func(k, _ []byte) error {
+		if q.bucket.Get(k) == nil {
+			deleted = append(deleted, k)
+		}
+		return nil
+	}); err != nil {
+		return 0, err
+	}
+	for _, key := range deleted {
+		if err := q.deleteWriteTime(key); err != nil {
+			return 0, err
+		}
+	}
+
+	var usage int64
+	var unindexed [][]byte
+	if err := q.bucket.ForEach(func(k, v []byte) error {
+		usage += entrySize(k, v)
+		if q.times.Get(k) == nil {
+			unindexed = append(unindexed, k)
+		}
+		return nil
+	}); err != nil {
+		return 0, err
+	}
+	for _, key := range unindexed {
+		if err := q.setWriteTime(key); err != nil {
+			return 0, err
+		}
+	}
+	return usage, nil
+}
+
+// expire deletes the entries that were not written within the ttl.
+func (q *quotaTx) expire() error {
+	cutoff := encodeTimestamp(q.now.Add(-q.quota.ttl))
+
+	var expired [][]byte
+	c := q.order.Cursor()
+	for k, _ := c.First(); k != nil && bytes.Compare(k[:timestampSize], cutoff) < 0; k, _ = c.Next() {
+		if key := k[timestampSize:]; !q.quota.protected[string(key)] {
+			expired = append(expired, key)
+		}
+	}
+	for _, key := range expired {
+		if err := q.delete(key); err != nil {
+			return err
+		}
+		q.expirations++
+	}
+	return nil
+}
+
+// put stores the value, evicting the oldest entries of the client or rejecting the write
+// if it would exceed the quota.
+func (q *quotaTx) put(key, value []byte) error {
+	size := entrySize(key, value)
+	if old := q.bucket.Get(key); old != nil {
+		size -= entrySize(key, old)
+	}
+
+	for q.exceeds(size) {
+		oldest := q.oldestKey(key)
+		if !q.quota.evict || oldest == nil {
+			q.rejects++
+			return errQuotaExceeded
+		}
+		if err := q.delete(oldest); err != nil {
+			return err
+		}
+		q.evictions++
+	}
+
+	if err := q.bucket.Put(key, value); err != nil {
+		return err
+	}
+	q.delta += size
+	return q.setWriteTime(key)
+}
+
+// exceeds returns whether growing the data of the client by size exceeds the quota.
+func (q *quotaTx) exceeds(size int64) bool {
+	if size <= 0 {
+		return false
+	}
+	if q.quota.maxClientBytes > 0 && q.usage.Load()+q.delta+size > q.quota.maxClientBytes {
+		return true
+	}
+	return q.quota.maxBytes > 0 && q.quota.totalUsage()+q.delta+size > q.quota.maxBytes
+}
+
+// oldestKey returns the least recently written key other than the given one and the
+// protected keys.
+func (q *quotaTx) oldestKey(except []byte) []byte {
+	c := q.order.Cursor()
+	for k, _ := c.First(); k != nil; k, _ = c.Next() {
+		if key := k[timestampSize:]; !bytes.Equal(key, except) && !q.quota.protected[string(key)] {
+			return append([]byte{}, key...)
+		}
+	}
+	return nil
+}
+
+func (q *quotaTx) delete(key []byte) error {
+	value := q.bucket.Get(key)
+	if value == nil {
+		return nil
+	}
+	q.delta -= entrySize(key, value)
+	if err := q.bucket.Delete(key); err != nil {
+		return err
+	}
+	return q.deleteWriteTime(key)
+}
+
+func (q *quotaTx) setWriteTime(key []byte) error {
+	if err := q.deleteWriteTime(key); err != nil {
+		return err
+	}
+	timestamp := encodeTimestamp(q.now)
+	if err := q.times.Put(key, timestamp); err != nil {
+		return err
+	}
+	return q.order.Put(append(timestamp, key...), []byte{})
+}
+
+func (q *quotaTx) deleteWriteTime(key []byte) error {
+	timestamp := q.times.Get(key)
+	if timestamp == nil {
+		return nil
+	}
+	if err := q.order.Delete(append(append([]byte{}, timestamp...), key...)); err != nil {
+		return err
+	}
+	return q.times.Delete(key)
+}
+
+// commit applies the changes of the transaction to the client usage and reports them,
+// only reporting the rejected writes if the transaction was rolled back.
+func (q *quotaTx) commit(committed bool) {
+	ctx := context.Background()
+	attrs := metric.WithAttributes(q.quota.extensionAttr)
+	if committed {
+		q.usage.Add(q.delta)
+		if q.evictions > 0 {
+			q.quota.evictions.Add(ctx, q.evictions, attrs)
+		}
+		if q.expirations > 0 {
+			q.quota.expirations.Add(ctx, q.expirations, attrs)
+		}
+	}
+	if q.rejects > 0 {
+		q.quota.rejections.Add(ctx, q.rejects, attrs)
+	}
+}
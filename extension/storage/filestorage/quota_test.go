@@ -0,0 +1,280 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package filestorage
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+	"go.opentelemetry.io/collector/extension/extensiontest"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/storagetest"
+)
+
+// quarterMiB is the size of the values written in the tests, so that three entries and their keys
+// fit in 1 MiB but four don't.
+const quarterMiB = oneMiB / 4
+
+func newQuotaExtension(t *testing.T, dir string, quota *QuotaConfig) (storage.Extension, *sdkmetric.ManualReader) {
+	f := NewFactory()
+	cfg := f.CreateDefaultConfig().(*Config)
+	cfg.Directory = dir
+	cfg.Quota = quota
+
+	reader := sdkmetric.NewManualReader()
+	set := extensiontest.NewNopCreateSettings()
+	set.MeterProvider = sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	ext, err := f.CreateExtension(context.Background(), set, cfg)
+	require.NoError(t, err)
+	require.NoError(t, ext.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		require.NoError(t, ext.Shutdown(context.Background()))
+	})
+	return ext.(storage.Extension), reader
+}
+
+func newQuotaClient(t *testing.T, ext storage.Extension, name string) storage.Client {
+	client, err := ext.GetClient(context.Background(), component.KindExporter, newTestEntity("my_component"), name)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, client.Close(context.Background()))
+	})
+	return client
+}
+
+// collectSums returns the values of the gauges and counters of the extension, by metric name and client.
+func collectSums(t *testing.T, reader *sdkmetric.ManualReader) map[string]map[string]int64 {
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+
+	sums := map[string]map[string]int64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			sums[m.Name] = map[string]int64{}
+			var points []metricdata.DataPoint[int64]
+			switch data := m.Data.(type) {
+			case metricdata.Gauge[int64]:
+				points = data.DataPoints
+			case metricdata.Sum[int64]:
+				points = data.DataPoints
+			}
+			for _, point := range points {
+				client, _ := point.Attributes.Value("client")
+				sums[m.Name][client.AsString()] += point.Value
+			}
+		}
+	}
+	return sums
+}
+
+func TestQuotaStorage(t *testing.T) {
+	ext, _ := newQuotaExtension(t, t.TempDir(), &QuotaConfig{MaxSizeMiB: 10, TTL: time.Hour})
+	storagetest.VerifyExtension(t, ext)
+}
+
+func TestQuotaRejectsWrites(t *testing.T) {
+	ctx := context.Background()
+	ext, reader := newQuotaExtension(t, t.TempDir(), &QuotaConfig{MaxClientSizeMiB: 1})
+	client := newQuotaClient(t, ext, "")
+
+	value := make([]byte, quarterMiB)
+	for i := 0; i < 3; i++ {
+		require.NoError(t, client.Set(ctx, fmt.Sprintf("key_%d", i), value))
+	}
+	assert.ErrorIs(t, client.Set(ctx, "key_3", value), errQuotaExceeded)
+	assert.ErrorIs(t, client.Set(ctx, "too_large", make([]byte, 2*oneMiB)), errQuotaExceeded)
+
+	// Overwriting and deleting values don't grow the stored data
+	require.NoError(t, client.Set(ctx, "key_0", value))
+	require.NoError(t, client.Delete(ctx, "key_0"))
+	require.NoError(t, client.Set(ctx, "key_3", value))
+
+	for i := 1; i < 4; i++ {
+		stored, err := client.Get(ctx, fmt.Sprintf("key_%d", i))
+		require.NoError(t, err)
+		assert.Equal(t, value, stored)
+	}
+
+	sums := collectSums(t, reader)
+	assert.Equal(t, int64(2), sums["filestorage_rejected_writes"][""])
+	assert.Equal(t, int64(3*(quarterMiB+len("key_0"))), sums["filestorage_usage"]["exporter_nop_my_component"])
+}
+
+func TestQuotaEvictsOldest(t *testing.T) {
+	ctx := context.Background()
+	ext, reader := newQuotaExtension(t, t.TempDir(), &QuotaConfig{MaxClientSizeMiB: 1, OnOverflow: OverflowEvictOldest})
+	client := newQuotaClient(t, ext, "")
+
+	value := make([]byte, quarterMiB)
+	for i := 0; i < 5; i++ {
+		require.NoError(t, client.Set(ctx, fmt.Sprintf("key_%d", i), value))
+	}
+	for i := 0; i < 5; i++ {
+		stored, err := client.Get(ctx, fmt.Sprintf("key_%d", i))
+		require.NoError(t, err)
+		if i < 2 {
+			assert.Nil(t, stored, "key_%d should be evicted", i)
+		} else {
+			assert.Equal(t, value, stored)
+		}
+	}
+
+	// Values larger than the quota are rejected without evicting anything
+	assert.ErrorIs(t, client.Set(ctx, "too_large", make([]byte, 2*oneMiB)), errQuotaExceeded)
+	stored, err := client.Get(ctx, "key_2")
+	require.NoError(t, err)
+	assert.Equal(t, value, stored)
+
+	sums := collectSums(t, reader)
+	assert.Equal(t, int64(2), sums["filestorage_evictions"][""])
+	assert.Equal(t, int64(1), sums["filestorage_rejected_writes"][""])
+}
+
+func TestQuotaIsSharedByClients(t *testing.T) {
+	ctx := context.Background()
+	ext, reader := newQuotaExtension(t, t.TempDir(), &QuotaConfig{MaxSizeMiB: 1})
+	first := newQuotaClient(t, ext, "first")
+	second := newQuotaClient(t, ext, "second")
+
+	value := make([]byte, quarterMiB)
+	require.NoError(t, first.Set(ctx, "key_0", value))
+	require.NoError(t, first.Set(ctx, "key_1", value))
+	require.NoError(t, second.Set(ctx, "key_0", value))
+	assert.ErrorIs(t, second.Set(ctx, "key_1", value), errQuotaExceeded)
+
+	require.NoError(t, first.Delete(ctx, "key_0"))
+	require.NoError(t, second.Set(ctx, "key_1", value))
+
+	sums := collectSums(t, reader)
+	entrySize := int64(quarterMiB + len("key_0"))
+	assert.Equal(t, entrySize, sums["filestorage_usage"]["exporter_nop_my_component_first"])
+	assert.Equal(t, 2*entrySize, sums["filestorage_usage"]["exporter_nop_my_component_second"])
+}
+
+func TestQuotaExpiresEntries(t *testing.T) {
+	ctx := context.Background()
+	ext, reader := newQuotaExtension(t, t.TempDir(), &QuotaConfig{TTL: 50 * time.Millisecond})
+	client := newQuotaClient(t, ext, "")
+
+	require.NoError(t, client.Set(ctx, "old", []byte("old value")))
+	time.Sleep(100 * time.Millisecond)
+	require.NoError(t, client.Set(ctx, "new", []byte("new value")))
+
+	value, err := client.Get(ctx, "old")
+	require.NoError(t, err)
+	assert.Nil(t, value)
+	value, err = client.Get(ctx, "new")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("new value"), value)
+
+	sums := collectSums(t, reader)
+	assert.Equal(t, int64(1), sums["filestorage_expirations"][""])
+	assert.Equal(t, int64(len("new")+len("new value")), sums["filestorage_usage"]["exporter_nop_my_component"])
+}
+
+func TestQuotaIndexesExistingData(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	// Data written without a quota counts towards it once it's enabled
+	unbounded, _ := newQuotaExtension(t, dir, nil)
+	client, err := unbounded.GetClient(ctx, component.KindExporter, newTestEntity("my_component"), "")
+	require.NoError(t, err)
+	value := make([]byte, quarterMiB)
+	for i := 0; i < 3; i++ {
+		require.NoError(t, client.Set(ctx, fmt.Sprintf("key_%d", i), value))
+	}
+	require.NoError(t, client.Close(ctx))
+
+	bounded, reader := newQuotaExtension(t, dir, &QuotaConfig{MaxClientSizeMiB: 1, OnOverflow: OverflowEvictOldest})
+	client = newQuotaClient(t, bounded, "")
+	sums := collectSums(t, reader)
+	assert.Equal(t, int64(3*(quarterMiB+len("key_0"))), sums["filestorage_usage"]["exporter_nop_my_component"])
+
+	require.NoError(t, client.Set(ctx, "key_3", value))
+	sums = collectSums(t, reader)
+	assert.Equal(t, int64(1), sums["filestorage_evictions"][""])
+	assert.Equal(t, int64(3*(quarterMiB+len("key_0"))), sums["filestorage_usage"]["exporter_nop_my_component"])
+}
+
+func TestQuotaKeepsQueueMetadata(t *testing.T) {
+	ctx := context.Background()
+	ext, reader := newQuotaExtension(t, t.TempDir(), &QuotaConfig{MaxClientSizeMiB: 1, OnOverflow: OverflowEvictOldest, TTL: 50 * time.Millisecond})
+	client := newQuotaClient(t, ext, "")
+
+	// The indexes of the persistent queue are neither expired nor evicted, even when they are the oldest entries
+	for _, key := range []string{"ri", "wi", "di"} {
+		require.NoError(t, client.Set(ctx, key, []byte{0}))
+	}
+	time.Sleep(100 * time.Millisecond)
+	value := make([]byte, quarterMiB)
+	for i := 0; i < 5; i++ {
+		require.NoError(t, client.Set(ctx, fmt.Sprintf("key_%d", i), value))
+	}
+
+	for _, key := range []string{"ri", "wi", "di"} {
+		stored, err := client.Get(ctx, key)
+		require.NoError(t, err)
+		assert.Equal(t, []byte{0}, stored, "%s should be kept", key)
+	}
+	stored, err := client.Get(ctx, "key_0")
+	require.NoError(t, err)
+	assert.Nil(t, stored)
+
+	sums := collectSums(t, reader)
+	assert.Equal(t, int64(2), sums["filestorage_evictions"][""])
+	assert.Zero(t, sums["filestorage_expirations"][""])
+}
+
+func TestQuotaKeepsProtectedKeys(t *testing.T) {
+	ctx := context.Background()
+	ext, reader := newQuotaExtension(t, t.TempDir(), &QuotaConfig{MaxClientSizeMiB: 1, OnOverflow: OverflowEvictOldest, ProtectedKeys: []string{"checkpoint"}})
+	client := newQuotaClient(t, ext, "")
+
+	// The configured keys replace the keys of the persistent queue
+	require.NoError(t, client.Set(ctx, "ri", []byte{0}))
+	require.NoError(t, client.Set(ctx, "checkpoint", []byte{0}))
+	value := make([]byte, quarterMiB)
+	for i := 0; i < 4; i++ {
+		require.NoError(t, client.Set(ctx, fmt.Sprintf("key_%d", i), value))
+	}
+
+	stored, err := client.Get(ctx, "checkpoint")
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0}, stored)
+	stored, err = client.Get(ctx, "ri")
+	require.NoError(t, err)
+	assert.Nil(t, stored)
+
+	sums := collectSums(t, reader)
+	assert.Equal(t, int64(2), sums["filestorage_evictions"][""])
+}
+
+func TestQuotaDueForExpiry(t *testing.T) {
+	quota, err := newStorageQuota(&QuotaConfig{TTL: time.Hour}, component.MustNewID("file_storage"), componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+
+	// The expired entries are looked up at most once per tenth of the ttl
+	var nextExpiry atomic.Int64
+	now := time.Now()
+	assert.True(t, quota.dueForExpiry(&nextExpiry, now))
+	assert.False(t, quota.dueForExpiry(&nextExpiry, now))
+	assert.False(t, quota.dueForExpiry(&nextExpiry, now.Add(5*time.Minute)))
+	assert.True(t, quota.dueForExpiry(&nextExpiry, now.Add(6*time.Minute)))
+
+	unbounded, err := newStorageQuota(&QuotaConfig{}, component.MustNewID("file_storage"), componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+	assert.False(t, unbounded.dueForExpiry(&nextExpiry, now.Add(time.Hour)))
+}
@@ -32,6 +32,13 @@ func TestFileBackedLifecycle(t *testing.T) {
 	runExtensionLifecycle(t, ext, true)
 }
 
+func TestVerifyFileBackedExtension(t *testing.T) {
+	ext := NewFileBackedStorageExtension("test", t.TempDir())
+	require.NoError(t, ext.Start(context.Background(), componenttest.NewNopHost()))
+	VerifyExtension(t, ext)
+	require.NoError(t, ext.Shutdown(context.Background()))
+}
+
 func runExtensionLifecycle(t *testing.T, ext *TestStorage, expectPersistence bool) {
 	ctx := context.Background()
 	require.NoError(t, ext.Start(ctx, componenttest.NewNopHost()))
@@ -0,0 +1,99 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package storagetest // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/storagetest"
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+)
+
+// VerifyExtension checks that the clients of a started storage extension support all the
+// storage operations, keep the data of each component and name separate, and persist
+// it across clients of the same component and name.
+func VerifyExtension(t *testing.T, ext storage.Extension) {
+	ctx := context.Background()
+	id := component.MustNewID("foo")
+
+	client, err := ext.GetClient(ctx, component.KindReceiver, id, "")
+	require.NoError(t, err)
+
+	// Missing values are nil
+	value, err := client.Get(ctx, "missing")
+	require.NoError(t, err)
+	require.Nil(t, value)
+
+	// Set, overwrite and delete values
+	require.NoError(t, client.Set(ctx, "key", []byte("value")))
+	value, err = client.Get(ctx, "key")
+	require.NoError(t, err)
+	require.Equal(t, []byte("value"), value)
+
+	require.NoError(t, client.Set(ctx, "key", []byte("new value")))
+	value, err = client.Get(ctx, "key")
+	require.NoError(t, err)
+	require.Equal(t, []byte("new value"), value)
+
+	require.NoError(t, client.Delete(ctx, "key"))
+	value, err = client.Get(ctx, "key")
+	require.NoError(t, err)
+	require.Nil(t, value)
+
+	// Empty and large values are kept as is
+	large := bytes.Repeat([]byte("0123456789abcdef"), 1<<14)
+	require.NoError(t, client.Set(ctx, "empty", []byte{}))
+	require.NoError(t, client.Set(ctx, "large", large))
+	value, err = client.Get(ctx, "empty")
+	require.NoError(t, err)
+	require.NotNil(t, value)
+	require.Empty(t, value)
+	value, err = client.Get(ctx, "large")
+	require.NoError(t, err)
+	require.Equal(t, large, value)
+
+	// Batch operations are applied in order
+	set := storage.SetOperation("batch", []byte("batch value"))
+	get := storage.GetOperation("batch")
+	deleteOp := storage.DeleteOperation("batch")
+	getDeleted := storage.GetOperation("batch")
+	require.NoError(t, client.Batch(ctx, set, get, deleteOp, getDeleted))
+	require.Equal(t, []byte("batch value"), get.Value)
+	require.Nil(t, getDeleted.Value)
+
+	require.NoError(t, client.Set(ctx, "persisted", []byte("persisted value")))
+	require.NoError(t, client.Close(ctx))
+
+	// Values are persisted across clients
+	client, err = ext.GetClient(ctx, component.KindReceiver, id, "")
+	require.NoError(t, err)
+	value, err = client.Get(ctx, "persisted")
+	require.NoError(t, err)
+	require.Equal(t, []byte("persisted value"), value)
+	value, err = client.Get(ctx, "large")
+	require.NoError(t, err)
+	require.Equal(t, large, value)
+	require.NoError(t, client.Close(ctx))
+
+	// Values are not shared with other components or names
+	for _, other := range []struct {
+		kind component.Kind
+		id   component.ID
+		name string
+	}{
+		{kind: component.KindExporter, id: id},
+		{kind: component.KindReceiver, id: component.MustNewIDWithName("foo", "other")},
+		{kind: component.KindReceiver, id: id, name: "other"},
+	} {
+		otherClient, err := ext.GetClient(ctx, other.kind, other.id, other.name)
+		require.NoError(t, err)
+		value, err = otherClient.Get(ctx, "persisted")
+		require.NoError(t, err)
+		require.Nil(t, value)
+		require.NoError(t, otherClient.Close(ctx))
+	}
+}
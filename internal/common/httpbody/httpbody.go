@@ -0,0 +1,60 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package httpbody reads HTTP response bodies and looks up values in their JSON documents.
+package httpbody // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/httpbody"
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// MaxSize is the maximum number of bytes of a response body read by Read.
+const MaxSize = 1 << 20
+
+// Read reads at most MaxSize bytes of the body.
+func Read(body io.Reader) ([]byte, error) {
+	return io.ReadAll(io.LimitReader(body, MaxSize))
+}
+
+// LookupJSONPath follows a dot separated path, such as "data.items.0.id",
+// through objects and arrays. A leading "$." is ignored.
+func LookupJSONPath(doc any, path string) (any, bool) {
+	path = strings.TrimPrefix(path, "$.")
+	current := doc
+	for _, key := range strings.Split(path, ".") {
+		switch node := current.(type) {
+		case map[string]any:
+			next, ok := node[key]
+			if !ok {
+				return nil, false
+			}
+			current = next
+		case []any:
+			idx, err := strconv.Atoi(key)
+			if err != nil || idx < 0 || idx >= len(node) {
+				return nil, false
+			}
+			current = node[idx]
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// JSONValueString returns strings as they are and every other value in its
+// JSON representation, e.g. 42, true or null.
+func JSONValueString(value any) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	b, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(b)
+}
@@ -0,0 +1,56 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package httpbody
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRead(t *testing.T) {
+	body, err := Read(strings.NewReader("ok"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("ok"), body)
+
+	body, err = Read(bytes.NewReader(make([]byte, MaxSize+1)))
+	require.NoError(t, err)
+	assert.Len(t, body, MaxSize)
+}
+
+func TestLookupJSONPath(t *testing.T) {
+	var doc any
+	decoder := json.NewDecoder(strings.NewReader(`{"data": {"items": [{"id": "a", "count": 42, "ready": true, "owner": null}]}}`))
+	decoder.UseNumber()
+	require.NoError(t, decoder.Decode(&doc))
+
+	tests := []struct {
+		path     string
+		expected string
+		found    bool
+	}{
+		{path: "data.items.0.id", expected: "a", found: true},
+		{path: "$.data.items.0.id", expected: "a", found: true},
+		{path: "data.items.0.count", expected: "42", found: true},
+		{path: "data.items.0.ready", expected: "true", found: true},
+		{path: "data.items.0.owner", expected: "null", found: true},
+		{path: "data.items.1.id"},
+		{path: "data.items.first.id"},
+		{path: "data.missing"},
+		{path: "data.items.0.id.name"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			value, ok := LookupJSONPath(doc, tt.path)
+			require.Equal(t, tt.found, ok)
+			if ok {
+				assert.Equal(t, tt.expected, JSONValueString(value))
+			}
+		})
+	}
+}
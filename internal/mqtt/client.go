@@ -0,0 +1,44 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package mqtt // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/mqtt"
+
+import (
+	"context"
+	"time"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"go.uber.org/zap"
+)
+
+// The interval between the first two attempts to connect to the broker,
+// which doubles up to maxReconnectInterval.
+const initialRetryInterval = time.Second
+
+// Connect connects the client to the broker, and keeps trying until it
+// succeeds or the context is canceled. Unlike the connect retries of the
+// client, it does not outlive the context.
+func Connect(ctx context.Context, client paho.Client, logger *zap.Logger) {
+	interval := initialRetryInterval
+	for {
+		token := client.Connect()
+		select {
+		case <-token.Done():
+		case <-ctx.Done():
+			return
+		}
+		err := token.Error()
+		if err == nil {
+			logger.Info("Connected to the broker")
+			return
+		}
+
+		logger.Warn("Failed to connect to the broker", zap.Duration("retry_interval", interval), zap.Error(err))
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			return
+		}
+		interval = min(2*interval, maxReconnectInterval)
+	}
+}
@@ -0,0 +1,45 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package mqtt
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestConnectRetries(t *testing.T) {
+	// Nothing listens on the endpoint.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	require.NoError(t, listener.Close())
+
+	cfg := NewDefaultClientConfig()
+	cfg.Endpoint = "tcp://" + listener.Addr().String()
+	opts, err := cfg.NewClientOptions(context.Background())
+	require.NoError(t, err)
+	client := paho.NewClient(opts)
+
+	core, logs := observer.New(zap.WarnLevel)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		Connect(ctx, client, zap.New(core))
+	}()
+
+	require.Eventually(t, func() bool {
+		return logs.FilterMessage("Failed to connect to the broker").Len() > 0
+	}, 5*time.Second, 10*time.Millisecond)
+	cancel()
+	<-done
+	assert.False(t, client.IsConnectionOpen())
+}
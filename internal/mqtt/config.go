@@ -0,0 +1,134 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package mqtt // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/mqtt"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"go.opentelemetry.io/collector/config/configopaque"
+	"go.opentelemetry.io/collector/config/configtls"
+	"go.uber.org/multierr"
+)
+
+const (
+	defaultEndpoint       = "tcp://localhost:1883"
+	defaultConnectTimeout = 30 * time.Second
+	defaultKeepAlive      = 30 * time.Second
+
+	// The largest interval between two attempts to connect to the broker.
+	maxReconnectInterval = time.Minute
+)
+
+var (
+	errMissingEndpoint    = errors.New("endpoint must be specified")
+	errInvalidTimeout     = errors.New("connect_timeout must be positive")
+	errInvalidKeepAlive   = errors.New("keep_alive must not be negative")
+	errPasswordNoUsername = errors.New("username must be specified with password")
+)
+
+// ClientConfig configures the connection of an MQTT client to a broker.
+type ClientConfig struct {
+	// Endpoint is the URL of the broker, with one of the tcp, mqtt, ssl, tls,
+	// mqtts, ws or wss schemes.
+	Endpoint string `mapstructure:"endpoint"`
+	// ClientID identifies the client to the broker. It is assigned by the
+	// broker when it is empty.
+	ClientID string `mapstructure:"client_id"`
+	// Username and Password authenticate the client to the broker.
+	Username string              `mapstructure:"username"`
+	Password configopaque.String `mapstructure:"password"`
+	// TLSSetting configures the TLS connections, which are used by the ssl,
+	// tls, mqtts and wss schemes.
+	TLSSetting *configtls.ClientConfig `mapstructure:"tls"`
+	// ConnectTimeout is the time given to a connection attempt.
+	ConnectTimeout time.Duration `mapstructure:"connect_timeout"`
+	// KeepAlive is the interval between the pings of the broker, which are
+	// disabled when it is 0.
+	KeepAlive time.Duration `mapstructure:"keep_alive"`
+}
+
+// NewDefaultClientConfig returns the default settings of the MQTT clients.
+func NewDefaultClientConfig() ClientConfig {
+	return ClientConfig{
+		Endpoint:       defaultEndpoint,
+		ConnectTimeout: defaultConnectTimeout,
+		KeepAlive:      defaultKeepAlive,
+	}
+}
+
+// Validate checks the client settings.
+func (cfg *ClientConfig) Validate() error {
+	var errs error
+	if cfg.Endpoint == "" {
+		errs = multierr.Append(errs, errMissingEndpoint)
+	} else if err := validateEndpoint(cfg.Endpoint); err != nil {
+		errs = multierr.Append(errs, err)
+	}
+	if cfg.ConnectTimeout <= 0 {
+		errs = multierr.Append(errs, errInvalidTimeout)
+	}
+	if cfg.KeepAlive < 0 {
+		errs = multierr.Append(errs, errInvalidKeepAlive)
+	}
+	if cfg.Password != "" && cfg.Username == "" {
+		errs = multierr.Append(errs, errPasswordNoUsername)
+	}
+	return errs
+}
+
+func validateEndpoint(endpoint string) error {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return fmt.Errorf("invalid endpoint %q: %w", endpoint, err)
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "tcp", "mqtt", "ssl", "tls", "mqtts", "ws", "wss":
+	default:
+		return fmt.Errorf("invalid endpoint %q: the scheme must be one of tcp, mqtt, ssl, tls, mqtts, ws or wss", endpoint)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("invalid endpoint %q: the host must be specified", endpoint)
+	}
+	return nil
+}
+
+// ValidateQoS checks that a QoS is one of the levels of MQTT: 0 (at most
+// once), 1 (at least once) or 2 (exactly once).
+func ValidateQoS(qos byte) error {
+	if qos > 2 {
+		return fmt.Errorf("invalid qos %d: must be 0, 1 or 2", qos)
+	}
+	return nil
+}
+
+// NewClientOptions returns the options of a client connecting to the broker.
+// The client reconnects when the connection is lost, once Connect connected
+// it.
+func (cfg *ClientConfig) NewClientOptions(ctx context.Context) (*paho.ClientOptions, error) {
+	opts := paho.NewClientOptions().
+		AddBroker(cfg.Endpoint).
+		SetClientID(cfg.ClientID).
+		SetConnectTimeout(cfg.ConnectTimeout).
+		SetKeepAlive(cfg.KeepAlive).
+		SetAutoReconnect(true).
+		SetMaxReconnectInterval(maxReconnectInterval)
+	if cfg.Username != "" {
+		opts.SetUsername(cfg.Username)
+		opts.SetPassword(string(cfg.Password))
+	}
+	if cfg.TLSSetting != nil {
+		tlsConfig, err := cfg.TLSSetting.LoadTLSConfig(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load TLS config: %w", err)
+		}
+		opts.SetTLSConfig(tlsConfig)
+	}
+	return opts, nil
+}
@@ -0,0 +1,113 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package mqtt
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/config/configtls"
+)
+
+func TestClientConfigValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		modify      func(*ClientConfig)
+		expectedErr string
+	}{
+		{
+			name:   "default",
+			modify: func(*ClientConfig) {},
+		},
+		{
+			name: "websocket with credentials",
+			modify: func(cfg *ClientConfig) {
+				cfg.Endpoint = "wss://broker.example.com:443/mqtt"
+				cfg.Username = "gateway"
+				cfg.Password = "secret"
+				cfg.KeepAlive = 0
+			},
+		},
+		{
+			name:        "missing endpoint",
+			modify:      func(cfg *ClientConfig) { cfg.Endpoint = "" },
+			expectedErr: "endpoint must be specified",
+		},
+		{
+			name:        "unsupported scheme",
+			modify:      func(cfg *ClientConfig) { cfg.Endpoint = "http://localhost:1883" },
+			expectedErr: `invalid endpoint "http://localhost:1883": the scheme must be one of tcp, mqtt, ssl, tls, mqtts, ws or wss`,
+		},
+		{
+			name:        "missing scheme",
+			modify:      func(cfg *ClientConfig) { cfg.Endpoint = "localhost:1883" },
+			expectedErr: `invalid endpoint "localhost:1883"`,
+		},
+		{
+			name:        "missing host",
+			modify:      func(cfg *ClientConfig) { cfg.Endpoint = "tcp://" },
+			expectedErr: `invalid endpoint "tcp://": the host must be specified`,
+		},
+		{
+			name: "invalid timeouts",
+			modify: func(cfg *ClientConfig) {
+				cfg.ConnectTimeout = 0
+				cfg.KeepAlive = -time.Second
+			},
+			expectedErr: "connect_timeout must be positive; keep_alive must not be negative",
+		},
+		{
+			name:        "password without username",
+			modify:      func(cfg *ClientConfig) { cfg.Password = "secret" },
+			expectedErr: "username must be specified with password",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewDefaultClientConfig()
+			tt.modify(&cfg)
+			err := cfg.Validate()
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.expectedErr)
+			}
+		})
+	}
+}
+
+func TestValidateQoS(t *testing.T) {
+	for _, qos := range []byte{0, 1, 2} {
+		assert.NoError(t, ValidateQoS(qos))
+	}
+	assert.EqualError(t, ValidateQoS(3), "invalid qos 3: must be 0, 1 or 2")
+}
+
+func TestNewClientOptions(t *testing.T) {
+	cfg := NewDefaultClientConfig()
+	cfg.ClientID = "otelcol"
+	cfg.Username = "gateway"
+	cfg.Password = "secret"
+	cfg.TLSSetting = &configtls.ClientConfig{ServerName: "broker.example.com"}
+
+	opts, err := cfg.NewClientOptions(context.Background())
+	require.NoError(t, err)
+	require.Len(t, opts.Servers, 1)
+	assert.Equal(t, "tcp://localhost:1883", opts.Servers[0].String())
+	assert.Equal(t, "otelcol", opts.ClientID)
+	assert.Equal(t, "gateway", opts.Username)
+	assert.Equal(t, "secret", opts.Password)
+	assert.Equal(t, defaultConnectTimeout, opts.ConnectTimeout)
+	assert.Equal(t, int64(defaultKeepAlive.Seconds()), opts.KeepAlive)
+	assert.True(t, opts.AutoReconnect)
+	require.NotNil(t, opts.TLSConfig)
+	assert.Equal(t, "broker.example.com", opts.TLSConfig.ServerName)
+
+	cfg.TLSSetting = &configtls.ClientConfig{Config: configtls.Config{CAFile: "missing.pem"}}
+	_, err = cfg.NewClientOptions(context.Background())
+	assert.ErrorContains(t, err, "failed to load TLS config")
+}
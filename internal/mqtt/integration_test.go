@@ -0,0 +1,35 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build integration
+
+package mqtt
+
+import (
+	"context"
+	"testing"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/mqtt/mqtttest"
+)
+
+func TestIntegrationConnect(t *testing.T) {
+	broker := mqtttest.NewBroker(t, mqtttest.WithCredentials("user", "password"))
+
+	cfg := NewDefaultClientConfig()
+	cfg.Endpoint = broker.Endpoint()
+	cfg.ClientID = "collector"
+	cfg.Username = "user"
+	cfg.Password = "password"
+	opts, err := cfg.NewClientOptions(context.Background())
+	require.NoError(t, err)
+	client := paho.NewClient(opts)
+
+	Connect(context.Background(), client, zap.NewNop())
+	defer client.Disconnect(0)
+	assert.True(t, client.IsConnectionOpen())
+}
@@ -0,0 +1,214 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package mqtttest runs an Eclipse Mosquitto broker in a container for the
+// integration tests of the MQTT components.
+package mqtttest // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/mqtt/mqtttest"
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/go-connections/nat"
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+const (
+	image = "docker.io/library/eclipse-mosquitto:2.0"
+
+	// The broker accepts plain connections on tcpPort, and TLS connections
+	// on tlsPort when it is configured with WithTLS.
+	tcpPort = nat.Port("1883/tcp")
+	tlsPort = nat.Port("8883/tcp")
+
+	configDir = "/mosquitto/config"
+	timeout   = 10 * time.Second
+)
+
+// Message is a message published to the broker.
+type Message struct {
+	Topic   string
+	QoS     byte
+	Retain  bool
+	Payload []byte
+}
+
+// Broker is an MQTT broker for the duration of a test.
+type Broker struct {
+	endpoint string
+	// tcpEndpoint is the plain endpoint of the client of the test.
+	tcpEndpoint string
+	caFile      string
+	username    string
+	password    string
+}
+
+// Option configures a Broker.
+type Option func(*brokerConfig)
+
+type brokerConfig struct {
+	username string
+	password string
+	tls      bool
+}
+
+// WithCredentials requires the clients to authenticate with the username and
+// password.
+func WithCredentials(username, password string) Option {
+	return func(cfg *brokerConfig) {
+		cfg.username = username
+		cfg.password = password
+	}
+}
+
+// WithTLS makes the endpoint of the broker accept TLS connections, with a
+// self-signed certificate for the loopback addresses.
+func WithTLS() Option {
+	return func(cfg *brokerConfig) {
+		cfg.tls = true
+	}
+}
+
+// NewBroker starts a broker, which is terminated at the end of the test.
+func NewBroker(t testing.TB, opts ...Option) *Broker {
+	var cfg brokerConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	conf := []string{"listener " + tcpPort.Port()}
+	ports := []string{string(tcpPort)}
+	var files []testcontainers.ContainerFile
+	var caFile string
+	if cfg.tls {
+		certPEM, keyPEM := generateCertificate(t)
+		caFile = filepath.Join(t.TempDir(), "ca.pem")
+		require.NoError(t, os.WriteFile(caFile, certPEM, 0o600))
+		conf = append(conf, "listener "+tlsPort.Port(), "certfile "+configDir+"/server.crt", "keyfile "+configDir+"/server.key")
+		ports = append(ports, string(tlsPort))
+		files = append(files,
+			testcontainers.ContainerFile{Reader: bytes.NewReader(certPEM), ContainerFilePath: configDir + "/server.crt", FileMode: 0o644},
+			testcontainers.ContainerFile{Reader: bytes.NewReader(keyPEM), ContainerFilePath: configDir + "/server.key", FileMode: 0o644})
+	}
+
+	// The password file is hashed by the broker before it starts.
+	cmd := "exec mosquitto -c " + configDir + "/mosquitto.conf"
+	if cfg.username != "" {
+		conf = append(conf, "allow_anonymous false", "password_file "+configDir+"/passwd")
+		cmd = fmt.Sprintf("mosquitto_passwd -b -c %[1]s/passwd '%[2]s' '%[3]s' && chown mosquitto %[1]s/passwd && chmod 0600 %[1]s/passwd && %[4]s",
+			configDir, cfg.username, cfg.password, cmd)
+	} else {
+		conf = append(conf, "allow_anonymous true")
+	}
+	files = append(files, testcontainers.ContainerFile{
+		Reader:            strings.NewReader(strings.Join(conf, "\n") + "\n"),
+		ContainerFilePath: configDir + "/mosquitto.conf",
+		FileMode:          0o644,
+	})
+
+	var strategies []wait.Strategy
+	for _, port := range ports {
+		strategies = append(strategies, wait.ForListeningPort(nat.Port(port)))
+	}
+	ctx := context.Background()
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image:        image,
+			ExposedPorts: ports,
+			Files:        files,
+			Cmd:          []string{"sh", "-c", cmd},
+			WaitingFor:   wait.ForAll(strategies...),
+		},
+		Started: true,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, container.Terminate(context.Background()))
+	})
+
+	host, err := container.Host(ctx)
+	require.NoError(t, err)
+	endpoint := func(scheme string, port nat.Port) string {
+		mapped, err := container.MappedPort(ctx, port)
+		require.NoError(t, err)
+		return scheme + "://" + net.JoinHostPort(host, mapped.Port())
+	}
+
+	b := &Broker{
+		tcpEndpoint: endpoint("tcp", tcpPort),
+		username:    cfg.username,
+		password:    cfg.password,
+	}
+	b.endpoint = b.tcpEndpoint
+	if cfg.tls {
+		b.endpoint = endpoint("ssl", tlsPort)
+		b.caFile = caFile
+	}
+	return b
+}
+
+// Endpoint returns the URL of the broker.
+func (b *Broker) Endpoint() string {
+	return b.endpoint
+}
+
+// CAFile returns the file of the certificate of the broker configured with
+// WithTLS, which the clients use as certificate authority.
+func (b *Broker) CAFile() string {
+	return b.caFile
+}
+
+// Subscribe returns the messages published to the topics matching the topic
+// filter, from now on, and the retained messages of these topics.
+func (b *Broker) Subscribe(t testing.TB, filter string) <-chan Message {
+	messages := make(chan Message, 1000)
+	client := b.connect(t, "")
+	token := client.Subscribe(filter, 2, func(_ paho.Client, msg paho.Message) {
+		messages <- Message{Topic: msg.Topic(), QoS: msg.Qos(), Retain: msg.Retained(), Payload: msg.Payload()}
+	})
+	require.True(t, token.WaitTimeout(timeout))
+	require.NoError(t, token.Error())
+	return messages
+}
+
+// Publish publishes a message to the broker.
+func (b *Broker) Publish(t testing.TB, msg Message) {
+	client := b.connect(t, "")
+	token := client.Publish(msg.Topic, msg.QoS, msg.Retain, msg.Payload)
+	require.True(t, token.WaitTimeout(timeout))
+	require.NoError(t, token.Error())
+}
+
+// DisconnectClient makes the broker close the connection of the client with
+// the identifier, by connecting another client with the same identifier and
+// discarding its session.
+func (b *Broker) DisconnectClient(t testing.TB, clientID string) {
+	b.connect(t, clientID).Disconnect(0)
+}
+
+func (b *Broker) connect(t testing.TB, clientID string) paho.Client {
+	opts := paho.NewClientOptions().
+		AddBroker(b.tcpEndpoint).
+		SetClientID(clientID).
+		SetUsername(b.username).
+		SetPassword(b.password).
+		SetAutoReconnect(false)
+	client := paho.NewClient(opts)
+	token := client.Connect()
+	require.True(t, token.WaitTimeout(timeout))
+	require.NoError(t, token.Error())
+	t.Cleanup(func() {
+		client.Disconnect(0)
+	})
+	return client
+}
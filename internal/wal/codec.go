@@ -0,0 +1,52 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package wal // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/wal"
+
+import (
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// LogsCodec persists plog.Logs using the OTLP protobuf encoding.
+type LogsCodec struct {
+	plog.ProtoMarshaler
+	plog.ProtoUnmarshaler
+}
+
+func (c LogsCodec) Marshal(ld plog.Logs) ([]byte, error) {
+	return c.MarshalLogs(ld)
+}
+
+func (c LogsCodec) Unmarshal(buf []byte) (plog.Logs, error) {
+	return c.UnmarshalLogs(buf)
+}
+
+// MetricsCodec persists pmetric.Metrics using the OTLP protobuf encoding.
+type MetricsCodec struct {
+	pmetric.ProtoMarshaler
+	pmetric.ProtoUnmarshaler
+}
+
+func (c MetricsCodec) Marshal(md pmetric.Metrics) ([]byte, error) {
+	return c.MarshalMetrics(md)
+}
+
+func (c MetricsCodec) Unmarshal(buf []byte) (pmetric.Metrics, error) {
+	return c.UnmarshalMetrics(buf)
+}
+
+// TracesCodec persists ptrace.Traces using the OTLP protobuf encoding.
+type TracesCodec struct {
+	ptrace.ProtoMarshaler
+	ptrace.ProtoUnmarshaler
+}
+
+func (c TracesCodec) Marshal(td ptrace.Traces) ([]byte, error) {
+	return c.MarshalTraces(td)
+}
+
+func (c TracesCodec) Unmarshal(buf []byte) (ptrace.Traces, error) {
+	return c.UnmarshalTraces(buf)
+}
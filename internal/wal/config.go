@@ -0,0 +1,90 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package wal // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/wal"
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/tidwall/wal"
+)
+
+const (
+	defaultBufferSize        = 300
+	defaultTruncateFrequency = 1 * time.Minute
+)
+
+// SyncPolicy controls when the write-ahead log is flushed to stable storage.
+type SyncPolicy string
+
+const (
+	// SyncAlways fsyncs the log after every persisted batch. This is the default.
+	SyncAlways SyncPolicy = "always"
+	// SyncOnTruncate only fsyncs the log before exported entries are truncated
+	// and when the log is closed. Writes are faster, but entries persisted since
+	// the last truncation can be lost if the host crashes.
+	SyncOnTruncate SyncPolicy = "on_truncate"
+)
+
+// Config defines the configuration of a write-ahead log.
+type Config struct {
+	// Directory is the directory the log and its checkpoint are stored in.
+	Directory string `mapstructure:"directory"`
+	// BufferSize is the maximum number of entries read from the log before
+	// they are exported and truncated. Defaults to 300.
+	BufferSize int `mapstructure:"buffer_size"`
+	// TruncateFrequency is the maximum time entries read from the log are
+	// held before they are exported and truncated. Defaults to 1m.
+	TruncateFrequency time.Duration `mapstructure:"truncate_frequency"`
+	// SyncPolicy is one of "always" or "on_truncate". Defaults to "always".
+	SyncPolicy SyncPolicy `mapstructure:"sync_policy"`
+}
+
+// Validate checks if the write-ahead log configuration is valid.
+func (c *Config) Validate() error {
+	switch c.SyncPolicy {
+	case "", SyncAlways, SyncOnTruncate:
+	default:
+		return fmt.Errorf("invalid sync_policy %q, must be one of %q or %q", c.SyncPolicy, SyncAlways, SyncOnTruncate)
+	}
+	if c.BufferSize < 0 {
+		return fmt.Errorf("buffer_size must not be negative")
+	}
+	if c.TruncateFrequency < 0 {
+		return fmt.Errorf("truncate_frequency must not be negative")
+	}
+	return nil
+}
+
+func (c *Config) bufferSize() int {
+	if c.BufferSize > 0 {
+		return c.BufferSize
+	}
+	return defaultBufferSize
+}
+
+func (c *Config) truncateFrequency() time.Duration {
+	if c.TruncateFrequency > 0 {
+		return c.TruncateFrequency
+	}
+	return defaultTruncateFrequency
+}
+
+func (c *Config) syncAlways() bool {
+	return c.SyncPolicy == "" || c.SyncPolicy == SyncAlways
+}
+
+func (c *Config) createWAL(name string) (*wal.Log, string, error) {
+	walPath := filepath.Join(c.Directory, name)
+	log, err := wal.Open(walPath, &wal.Options{
+		SegmentCacheSize: c.bufferSize(),
+		NoCopy:           true,
+		NoSync:           !c.syncAlways(),
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to open WAL: %w", err)
+	}
+	return log, walPath, nil
+}
@@ -0,0 +1,116 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package wal // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/wal"
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.uber.org/zap"
+)
+
+// Name returns the name of the write-ahead log of the component with id, which
+// is also the name of the directory it is stored in. The suffixes, such as the
+// signal, tell apart the logs of a component exporting several signals.
+func Name(id component.ID, suffixes ...string) string {
+	return strings.Join(append([]string{strings.ReplaceAll(id.String(), "/", "_")}, suffixes...), "_")
+}
+
+// Pusher wraps the push function of an exporter with a write-ahead log.
+// When the log is enabled, Push persists the data and returns, and the data
+// is handed to the wrapped push function from the log, in order, until it is
+// either exported or rejected with a permanent error. When the push function
+// returns a consumererror carrying the data that failed, only that data is
+// pushed again. When the log is not enabled, Push calls the wrapped push
+// function directly.
+type Pusher[T any] struct {
+	wal    *WAL[T]
+	push   func(context.Context, T) error
+	logger *zap.Logger
+}
+
+// NewPusher creates a Pusher for push. A nil cfg disables the write-ahead log.
+func NewPusher[T any](cfg *Config, name string, logger *zap.Logger, codec Codec[T], push func(context.Context, T) error) *Pusher[T] {
+	p := &Pusher[T]{
+		push:   push,
+		logger: logger,
+	}
+	p.wal = New(cfg, name, logger, codec, p.export)
+	return p
+}
+
+// Enabled returns whether data is persisted to a write-ahead log.
+func (p *Pusher[T]) Enabled() bool {
+	return p.wal != nil
+}
+
+// Start opens the write-ahead log and replays any data left over from a
+// previous run.
+func (p *Pusher[T]) Start(ctx context.Context) error {
+	if !p.Enabled() {
+		return nil
+	}
+	return p.wal.Start(ctx)
+}
+
+// Shutdown closes the write-ahead log.
+func (p *Pusher[T]) Shutdown(context.Context) error {
+	if !p.Enabled() {
+		return nil
+	}
+	return p.wal.Stop()
+}
+
+// Push persists data to the write-ahead log, or pushes it directly if the
+// log is not enabled.
+func (p *Pusher[T]) Push(ctx context.Context, data T) error {
+	if !p.Enabled() {
+		return p.push(ctx, data)
+	}
+	if err := p.wal.Persist([]T{data}); err != nil {
+		return consumererror.NewPermanent(err)
+	}
+	return nil
+}
+
+func (p *Pusher[T]) export(ctx context.Context, entries []T) error {
+	for i, data := range entries {
+		err := p.push(ctx, data)
+		if err == nil {
+			continue
+		}
+		if consumererror.IsPermanent(err) {
+			// Replaying the entry would fail again and block the log forever.
+			p.logger.Error("dropping data from write-ahead log after permanent error", zap.Error(err))
+			continue
+		}
+		if failed, ok := failedData[T](err); ok {
+			// Only the data which failed is replayed.
+			return &partialExportError{err: err, exported: i, failed: failed}
+		}
+		return NewPartialExportError(err, i)
+	}
+	return nil
+}
+
+// failedData returns the data carried by a consumererror, if any.
+func failedData[T any](err error) (T, bool) {
+	var data any
+	var logsErr consumererror.Logs
+	var metricsErr consumererror.Metrics
+	var tracesErr consumererror.Traces
+	switch {
+	case errors.As(err, &logsErr):
+		data = logsErr.Data()
+	case errors.As(err, &metricsErr):
+		data = metricsErr.Data()
+	case errors.As(err, &tracesErr):
+		data = tracesErr.Data()
+	}
+	failed, ok := data.(T)
+	return failed, ok
+}
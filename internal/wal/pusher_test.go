@@ -0,0 +1,133 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package wal
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.uber.org/zap"
+)
+
+func TestPusherDisabled(t *testing.T) {
+	var pushed []string
+	p := NewPusher[string](nil, "test", zap.NewNop(), stringCodec{}, func(_ context.Context, s string) error {
+		pushed = append(pushed, s)
+		return nil
+	})
+	assert.False(t, p.Enabled())
+	require.NoError(t, p.Start(context.Background()))
+	require.NoError(t, p.Push(context.Background(), "a"))
+	require.NoError(t, p.Shutdown(context.Background()))
+	assert.Equal(t, []string{"a"}, pushed)
+}
+
+func TestPusherRetriesUntilExported(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		pushed   []string
+		failures = 2
+	)
+	cfg := &Config{Directory: t.TempDir(), TruncateFrequency: 10 * time.Millisecond}
+	p := NewPusher(cfg, "test", zap.NewNop(), stringCodec{}, func(_ context.Context, s string) error {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case s == "permanent":
+			return consumererror.NewPermanent(errors.New("rejected"))
+		case s == "b" && failures > 0:
+			failures--
+			return errors.New("temporarily unavailable")
+		}
+		pushed = append(pushed, s)
+		return nil
+	})
+	require.True(t, p.Enabled())
+	p.wal.backOff.InitialInterval = time.Millisecond
+	require.NoError(t, p.Start(context.Background()))
+	t.Cleanup(func() { assert.NoError(t, p.Shutdown(context.Background())) })
+
+	for _, s := range []string{"a", "permanent", "b", "c"} {
+		require.NoError(t, p.Push(context.Background(), s))
+	}
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(pushed) == 3
+	}, 5*time.Second, 10*time.Millisecond)
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"a", "b", "c"}, pushed)
+}
+
+func TestPusherRetriesFailedData(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		pushed   []string
+		failures = 1
+	)
+	cfg := &Config{Directory: t.TempDir(), TruncateFrequency: 10 * time.Millisecond}
+	p := NewPusher(cfg, "test", zap.NewNop(), LogsCodec{}, func(_ context.Context, ld plog.Logs) error {
+		mu.Lock()
+		defer mu.Unlock()
+		records := ld.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords()
+		for i := 0; i < records.Len(); i++ {
+			body := records.At(i).Body().Str()
+			if body == "b" && failures > 0 {
+				failures--
+				return consumererror.NewLogs(errors.New("temporarily unavailable"), newTestLogs(body))
+			}
+			pushed = append(pushed, body)
+		}
+		return nil
+	})
+	p.wal.backOff.InitialInterval = time.Millisecond
+	require.NoError(t, p.Start(context.Background()))
+	t.Cleanup(func() { assert.NoError(t, p.Shutdown(context.Background())) })
+
+	require.NoError(t, p.Push(context.Background(), newTestLogs("a", "b")))
+	require.NoError(t, p.Push(context.Background(), newTestLogs("c")))
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(pushed) == 3
+	}, 5*time.Second, 10*time.Millisecond)
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"a", "b", "c"}, pushed)
+}
+
+func newTestLogs(bodies ...string) plog.Logs {
+	ld := plog.NewLogs()
+	records := ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords()
+	for _, body := range bodies {
+		records.AppendEmpty().Body().SetStr(body)
+	}
+	return ld
+}
+
+func TestName(t *testing.T) {
+	assert.Equal(t, "loki", Name(component.MustNewID("loki")))
+	assert.Equal(t, "splunk_hec_primary_logs", Name(component.MustNewIDWithName("splunk_hec", "primary"), "logs"))
+}
+
+func TestLogsCodec(t *testing.T) {
+	ld := plog.NewLogs()
+	ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStr("hello")
+
+	codec := LogsCodec{}
+	buf, err := codec.Marshal(ld)
+	require.NoError(t, err)
+	got, err := codec.Unmarshal(buf)
+	require.NoError(t, err)
+	assert.Equal(t, ld, got)
+}
@@ -0,0 +1,468 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package wal provides a disk backed write-ahead log that gives exporters
+// ordered, replayable delivery of the data they are handed. Entries are
+// persisted before the exporter acknowledges them, read back in order by a
+// background routine, exported in batches and then truncated. Entries that
+// were not exported before a shutdown or crash are replayed on the next start.
+package wal // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/wal"
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/tidwall/wal"
+	"go.uber.org/zap"
+)
+
+// Codec converts entries to and from their persisted representation.
+type Codec[T any] interface {
+	Marshal(T) ([]byte, error)
+	Unmarshal([]byte) (T, error)
+}
+
+// ExportFunc exports a batch of entries read from the log, in the order they
+// were persisted. If only the first entries of the batch were exported, it
+// should return an error created with NewPartialExportError so that those
+// entries are not exported again.
+type ExportFunc[T any] func(ctx context.Context, entries []T) error
+
+var (
+	errAlreadyClosed = errors.New("already closed")
+	errNilWAL        = errors.New("wal is nil")
+)
+
+type partialExportError struct {
+	err      error
+	exported int
+	// failed, when set, is the part of the first entry that was not exported,
+	// which replaces that entry when it is replayed.
+	failed any
+}
+
+// NewPartialExportError returns an error signalling that the first exported
+// entries of a batch were delivered before err occurred.
+func NewPartialExportError(err error, exported int) error {
+	return &partialExportError{err: err, exported: exported}
+}
+
+func (e *partialExportError) Error() string {
+	return fmt.Sprintf("exported %d entries before failing: %v", e.exported, e.err)
+}
+
+func (e *partialExportError) Unwrap() error {
+	return e.err
+}
+
+// WAL is a write-ahead log of entries of type T.
+type WAL[T any] struct {
+	mu      sync.Mutex // mu protects the fields below.
+	wal     *wal.Log
+	walPath string
+	rIndex  uint64 // rIndex is the index of the next entry to read.
+	wIndex  uint64 // wIndex is the index of the last entry written.
+	// retry replaces the entry at retryIndex when it is read again, after only
+	// part of it was exported.
+	retry      *T
+	retryIndex uint64
+
+	cfg        *Config
+	name       string
+	logger     *zap.Logger
+	codec      Codec[T]
+	exportSink ExportFunc[T]
+	backOff    *backoff.ExponentialBackOff
+
+	cancel   context.CancelFunc
+	stopOnce sync.Once
+	stopChan chan struct{}
+	doneChan chan struct{}
+	// writeChan is signaled every time entries are persisted, to wake up the
+	// routine reading from the log.
+	writeChan chan struct{}
+}
+
+// New creates a write-ahead log stored under name in the configured directory.
+// Entries read from the log are handed to exportSink. It returns nil if cfg is
+// nil, which callers can use to check whether the log is enabled.
+func New[T any](cfg *Config, name string, logger *zap.Logger, codec Codec[T], exportSink ExportFunc[T]) *WAL[T] {
+	if cfg == nil {
+		return nil
+	}
+
+	bo := backoff.NewExponentialBackOff()
+	bo.MaxElapsedTime = 0
+	return &WAL[T]{
+		cfg:        cfg,
+		name:       name,
+		logger:     logger,
+		codec:      codec,
+		exportSink: exportSink,
+		backOff:    bo,
+		stopChan:   make(chan struct{}),
+		doneChan:   make(chan struct{}),
+		writeChan:  make(chan struct{}, 1),
+	}
+}
+
+// Start opens the log and begins exporting its entries in the background,
+// starting with the entries left over from a previous run.
+func (w *WAL[T]) Start(_ context.Context) error {
+	if err := w.retrieveWALIndices(); err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	if pending := w.wIndex + 1 - w.rIndex; pending > 0 {
+		w.logger.Info("replaying entries from write-ahead log", zap.String("path", w.walPath), zap.Uint64("entries", pending))
+	}
+	w.mu.Unlock()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	w.cancel = cancel
+	go w.run(ctx)
+	return nil
+}
+
+// Stop stops exporting entries and closes the log. Entries which were not
+// exported yet remain in the log and are replayed on the next start.
+func (w *WAL[T]) Stop() error {
+	err := errAlreadyClosed
+	w.stopOnce.Do(func() {
+		close(w.stopChan)
+		if w.cancel != nil {
+			w.cancel()
+			<-w.doneChan
+		}
+
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		err = w.closeWAL()
+	})
+	return err
+}
+
+// Persist writes entries to the log. Once it returns without error, the entries
+// are guaranteed to be exported, possibly more than once.
+func (w *WAL[T]) Persist(entries []T) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.wal == nil {
+		return errNilWAL
+	}
+
+	// Write all the entries to the WAL in a batch.
+	batch := new(wal.Batch)
+	wIndex := w.wIndex
+	for _, entry := range entries {
+		blob, err := w.codec.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		wIndex++
+		batch.Write(wIndex, blob)
+	}
+	if err := w.wal.WriteBatch(batch); err != nil {
+		return err
+	}
+	w.wIndex = wIndex
+
+	select {
+	case w.writeChan <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// run exports entries from the log until the log is stopped.
+func (w *WAL[T]) run(ctx context.Context) {
+	defer close(w.doneChan)
+	for {
+		err := w.continuallyPopWALThenExport(ctx)
+		if err == nil || ctx.Err() != nil {
+			return
+		}
+		w.logger.Error("error processing WAL entries", zap.Error(err))
+
+		timer := time.NewTimer(w.backOff.NextBackOff())
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		// Restart from the first entry which was not acknowledged yet.
+		if errS := w.retrieveWALIndices(); errS != nil {
+			w.logger.Error("unable to re-start write-ahead log after error", zap.Error(errS))
+			return
+		}
+	}
+}
+
+// continuallyPopWALThenExport reads entries from the WAL until either the truncate
+// frequency expires or the buffer size is reached. When either of the two conditions
+// are matched, it exports the entries and truncates the head of the WAL to where it
+// last read from. It only returns on error, or with nil once the WAL is stopped.
+func (w *WAL[T]) continuallyPopWALThenExport(ctx context.Context) error {
+	var entries []T
+
+	freshTimer := func() *time.Timer {
+		return time.NewTimer(w.cfg.truncateFrequency())
+	}
+	timer := freshTimer()
+	defer func() {
+		// Added in a closure to ensure we capture the later
+		// updated value of timer when changed in the loop below.
+		timer.Stop()
+	}()
+
+	maxCountPerUpload := w.cfg.bufferSize()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-w.stopChan:
+			return nil
+		default:
+		}
+
+		entry, ok, err := w.readFromWAL()
+		if err != nil {
+			return err
+		}
+
+		var shouldExport bool
+		if ok {
+			entries = append(entries, entry)
+			select {
+			case <-timer.C:
+				shouldExport = true
+			default:
+				shouldExport = len(entries) >= maxCountPerUpload
+			}
+		} else {
+			// The log has been drained, so wait for new entries or the timer.
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-w.stopChan:
+				return nil
+			case <-w.writeChan:
+				continue
+			case <-timer.C:
+				shouldExport = len(entries) > 0
+				if !shouldExport {
+					timer = freshTimer()
+					continue
+				}
+			}
+		}
+
+		if !shouldExport {
+			continue
+		}
+
+		if err = w.exportThenFrontTruncateWAL(ctx, entries); err != nil {
+			return err
+		}
+		timer.Stop()
+		timer = freshTimer()
+		entries = nil
+	}
+}
+
+// readFromWAL returns the next entry of the WAL, or false if all entries were read.
+func (w *WAL[T]) readFromWAL() (entry T, ok bool, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.wal == nil {
+		return entry, false, errors.New("attempt to read from closed WAL")
+	}
+	if w.rIndex > w.wIndex {
+		return entry, false, nil
+	}
+
+	if w.retry != nil && w.retryIndex == w.rIndex {
+		entry = *w.retry
+		w.retry = nil
+		w.rIndex++
+		return entry, true, nil
+	}
+
+	blob, err := w.wal.Read(w.rIndex)
+	if err != nil {
+		return entry, false, err
+	}
+	if entry, err = w.codec.Unmarshal(blob); err != nil {
+		return entry, false, err
+	}
+	w.rIndex++
+	return entry, true, nil
+}
+
+func (w *WAL[T]) exportThenFrontTruncateWAL(ctx context.Context, entries []T) error {
+	w.mu.Lock()
+	first := w.rIndex - uint64(len(entries))
+	w.mu.Unlock()
+
+	err := w.exportSink(ctx, entries)
+	if err == nil {
+		w.backOff.Reset()
+		return w.acknowledge(first + uint64(len(entries)) - 1)
+	}
+
+	var pErr *partialExportError
+	if !errors.As(err, &pErr) {
+		return err
+	}
+	if failed, ok := pErr.failed.(T); ok {
+		w.mu.Lock()
+		w.retry = &failed
+		w.retryIndex = first + uint64(pErr.exported)
+		w.mu.Unlock()
+	}
+	if pErr.exported > 0 {
+		if ackErr := w.acknowledge(first + uint64(pErr.exported) - 1); ackErr != nil {
+			return errors.Join(err, ackErr)
+		}
+	}
+	return err
+}
+
+// acknowledge records that all entries up to and including index were exported,
+// and truncates them from the front of the WAL.
+func (w *WAL[T]) acknowledge(index uint64) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.wal == nil {
+		return errNilWAL
+	}
+
+	// Save all the entries that aren't yet committed, to the tail of the WAL.
+	if err := w.wal.Sync(); err != nil {
+		return err
+	}
+	if err := w.writeCheckpoint(index); err != nil {
+		return err
+	}
+	// The WAL can't be truncated to zero entries, so the last exported entry
+	// might be kept around. The checkpoint makes sure it is never replayed.
+	truncateIndex := index + 1
+	if truncateIndex > w.wIndex {
+		truncateIndex = w.wIndex
+	}
+	if err := w.wal.TruncateFront(truncateIndex); err != nil && !errors.Is(err, wal.ErrOutOfRange) {
+		return err
+	}
+	return nil
+}
+
+// retrieveWALIndices (re)opens the WAL and resets its read index to the first
+// entry which was not acknowledged yet.
+func (w *WAL[T]) retrieveWALIndices() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := w.closeWAL(); err != nil {
+		return err
+	}
+
+	log, walPath, err := w.cfg.createWAL(w.name)
+	if err != nil {
+		return err
+	}
+	w.wal = log
+	w.walPath = walPath
+
+	firstIndex, err := w.wal.FirstIndex()
+	if err != nil {
+		return fmt.Errorf("failed to retrieve the first WAL index: %w", err)
+	}
+	lastIndex, err := w.wal.LastIndex()
+	if err != nil {
+		return fmt.Errorf("failed to retrieve the last WAL index: %w", err)
+	}
+	acked, err := w.readCheckpoint()
+	if err != nil {
+		return err
+	}
+
+	w.wIndex = lastIndex
+	switch {
+	case lastIndex == 0:
+		// The WAL is empty, the first write will be at index 1 and
+		// any checkpoint left behind refers to a previous log.
+		w.rIndex = 1
+		w.retry = nil
+		if err = os.Remove(w.checkpointPath()); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to remove stale WAL checkpoint: %w", err)
+		}
+	case acked >= lastIndex:
+		w.rIndex = lastIndex + 1
+	case acked >= firstIndex:
+		w.rIndex = acked + 1
+	default:
+		w.rIndex = firstIndex
+	}
+	return nil
+}
+
+func (w *WAL[T]) closeWAL() error {
+	if w.wal != nil {
+		err := w.wal.Close()
+		w.wal = nil
+		return err
+	}
+	return nil
+}
+
+func (w *WAL[T]) checkpointPath() string {
+	return filepath.Join(w.cfg.Directory, w.name+".checkpoint")
+}
+
+func (w *WAL[T]) readCheckpoint() (uint64, error) {
+	b, err := os.ReadFile(w.checkpointPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read WAL checkpoint: %w", err)
+	}
+	if len(b) != 8 {
+		return 0, fmt.Errorf("invalid WAL checkpoint %q", w.checkpointPath())
+	}
+	return binary.BigEndian.Uint64(b), nil
+}
+
+// writeCheckpoint atomically replaces the checkpoint file with index.
+func (w *WAL[T]) writeCheckpoint(index uint64) error {
+	tmpPath := w.checkpointPath() + ".tmp"
+	f, err := os.Create(tmpPath)
+	if err != nil {
+		return err
+	}
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], index)
+	if _, err = f.Write(b[:]); err == nil && w.cfg.syncAlways() {
+		err = f.Sync()
+	}
+	if errC := f.Close(); err == nil {
+		err = errC
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, w.checkpointPath())
+}
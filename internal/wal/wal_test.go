@@ -0,0 +1,164 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package wal
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type stringCodec struct{}
+
+func (stringCodec) Marshal(s string) ([]byte, error) {
+	return []byte(s), nil
+}
+
+func (stringCodec) Unmarshal(b []byte) (string, error) {
+	return string(b), nil
+}
+
+// recordingSink records exported entries, and fails the export of the
+// entries listed in failOn until they were attempted once.
+type recordingSink struct {
+	mu       sync.Mutex
+	exported []string
+	failOn   map[string]bool
+}
+
+func (s *recordingSink) export(_ context.Context, entries []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, e := range entries {
+		if s.failOn[e] {
+			delete(s.failOn, e)
+			return NewPartialExportError(errors.New("export failed"), i)
+		}
+		s.exported = append(s.exported, e)
+	}
+	return nil
+}
+
+func (s *recordingSink) get() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.exported...)
+}
+
+func doNothingExportSink(context.Context, []string) error {
+	return nil
+}
+
+func TestWALCreation_nilConfig(t *testing.T) {
+	w := New[string](nil, "test", zap.NewNop(), stringCodec{}, doNothingExportSink)
+	require.Nil(t, w)
+}
+
+func TestWALStopManyTimes(t *testing.T) {
+	w := New(&Config{Directory: t.TempDir()}, "test", zap.NewNop(), stringCodec{}, doNothingExportSink)
+	require.NotNil(t, w)
+	require.NoError(t, w.Start(context.Background()))
+
+	// First close should NOT return an error.
+	require.NoError(t, w.Stop())
+	for i := 0; i < 4; i++ {
+		// Every invocation to Stop() should return an errAlreadyClosed.
+		require.ErrorIs(t, w.Stop(), errAlreadyClosed)
+	}
+}
+
+func TestWALPersistBeforeStart(t *testing.T) {
+	w := New(&Config{Directory: t.TempDir()}, "test", zap.NewNop(), stringCodec{}, doNothingExportSink)
+	require.ErrorIs(t, w.Persist([]string{"a"}), errNilWAL)
+}
+
+func TestWALExportsInOrder(t *testing.T) {
+	sink := &recordingSink{}
+	cfg := &Config{Directory: t.TempDir(), BufferSize: 2, TruncateFrequency: 10 * time.Millisecond}
+	w := New(cfg, "test", zap.NewNop(), stringCodec{}, sink.export)
+	require.NoError(t, w.Start(context.Background()))
+	t.Cleanup(func() { assert.NoError(t, w.Stop()) })
+
+	require.NoError(t, w.Persist([]string{"a", "b", "c"}))
+	require.NoError(t, w.Persist([]string{"d"}))
+	require.NoError(t, w.Persist([]string{"e"}))
+
+	assert.Eventually(t, func() bool {
+		return len(sink.get()) == 5
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"a", "b", "c", "d", "e"}, sink.get())
+}
+
+func TestWALReplayOnStart(t *testing.T) {
+	cfg := &Config{Directory: t.TempDir(), BufferSize: 1, TruncateFrequency: 10 * time.Millisecond}
+
+	// Persist entries, but stop before any of them could be exported.
+	blocked := make(chan struct{})
+	w := New(cfg, "test", zap.NewNop(), stringCodec{}, func(ctx context.Context, _ []string) error {
+		select {
+		case <-blocked:
+		case <-ctx.Done():
+		}
+		return errors.New("not exported")
+	})
+	require.NoError(t, w.Start(context.Background()))
+	require.NoError(t, w.Persist([]string{"a", "b", "c"}))
+	require.NoError(t, w.Stop())
+	close(blocked)
+
+	sink := &recordingSink{}
+	w = New(cfg, "test", zap.NewNop(), stringCodec{}, sink.export)
+	require.NoError(t, w.Start(context.Background()))
+	assert.Eventually(t, func() bool {
+		return len(sink.get()) == 3
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, w.Stop())
+	assert.Equal(t, []string{"a", "b", "c"}, sink.get())
+
+	// Exported entries must not be replayed again.
+	sink = &recordingSink{}
+	w = New(cfg, "test", zap.NewNop(), stringCodec{}, sink.export)
+	require.NoError(t, w.Start(context.Background()))
+	require.NoError(t, w.Persist([]string{"d"}))
+	assert.Eventually(t, func() bool {
+		return len(sink.get()) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, w.Stop())
+	assert.Equal(t, []string{"d"}, sink.get())
+}
+
+func TestWALResumesAfterPartialExport(t *testing.T) {
+	sink := &recordingSink{failOn: map[string]bool{"c": true}}
+	cfg := &Config{
+		Directory:         t.TempDir(),
+		BufferSize:        4,
+		TruncateFrequency: 10 * time.Millisecond,
+		SyncPolicy:        SyncOnTruncate,
+	}
+	w := New(cfg, "test", zap.NewNop(), stringCodec{}, sink.export)
+	w.backOff.InitialInterval = time.Millisecond
+	require.NoError(t, w.Start(context.Background()))
+	t.Cleanup(func() { assert.NoError(t, w.Stop()) })
+
+	require.NoError(t, w.Persist([]string{"a", "b", "c", "d"}))
+	assert.Eventually(t, func() bool {
+		return len(sink.get()) == 4
+	}, 5*time.Second, 10*time.Millisecond)
+	// "a" and "b" were exported before the failure and must not be sent twice.
+	assert.Equal(t, []string{"a", "b", "c", "d"}, sink.get())
+}
+
+func TestConfigValidate(t *testing.T) {
+	assert.NoError(t, (&Config{}).Validate())
+	assert.NoError(t, (&Config{SyncPolicy: SyncOnTruncate}).Validate())
+	assert.Error(t, (&Config{SyncPolicy: "sometimes"}).Validate())
+	assert.Error(t, (&Config{BufferSize: -1}).Validate())
+	assert.Error(t, (&Config{TruncateFrequency: -time.Second}).Validate())
+}
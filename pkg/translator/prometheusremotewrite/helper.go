@@ -225,7 +225,7 @@ func (c *prometheusConverter) addHistogramDataPoints(dataPoints pmetric.Histogra
 			}
 
 			sumlabels := createLabels(baseName+sumStr, baseLabels)
-			c.addSample(sum, sumlabels)
+			c.setCreatedTimestamp(c.addSample(sum, sumlabels), pt.StartTimestamp())
 
 		}
 
@@ -239,7 +239,7 @@ func (c *prometheusConverter) addHistogramDataPoints(dataPoints pmetric.Histogra
 		}
 
 		countlabels := createLabels(baseName+countStr, baseLabels)
-		c.addSample(count, countlabels)
+		c.setCreatedTimestamp(c.addSample(count, countlabels), pt.StartTimestamp())
 
 		// cumulative count for conversion to cumulative histogram
 		var cumulativeCount uint64
@@ -260,6 +260,7 @@ func (c *prometheusConverter) addHistogramDataPoints(dataPoints pmetric.Histogra
 			boundStr := strconv.FormatFloat(bound, 'f', -1, 64)
 			labels := createLabels(baseName+bucketStr, baseLabels, leStr, boundStr)
 			ts := c.addSample(bucket, labels)
+			c.setCreatedTimestamp(ts, pt.StartTimestamp())
 
 			bucketBounds = append(bucketBounds, bucketBoundsData{ts: ts, bound: bound})
 		}
@@ -274,6 +275,7 @@ func (c *prometheusConverter) addHistogramDataPoints(dataPoints pmetric.Histogra
 		}
 		infLabels := createLabels(baseName+bucketStr, baseLabels, leStr, pInfStr)
 		ts := c.addSample(infBucket, infLabels)
+		c.setCreatedTimestamp(ts, pt.StartTimestamp())
 
 		bucketBounds = append(bucketBounds, bucketBoundsData{ts: ts, bound: math.Inf(1)})
 		c.addExemplars(pt, bucketBounds)
@@ -405,7 +407,7 @@ func (c *prometheusConverter) addSummaryDataPoints(dataPoints pmetric.SummaryDat
 		}
 		// sum and count of the summary should append suffix to baseName
 		sumlabels := createLabels(baseName+sumStr, baseLabels)
-		c.addSample(sum, sumlabels)
+		c.setCreatedTimestamp(c.addSample(sum, sumlabels), pt.StartTimestamp())
 
 		// treat count as a sample in an individual TimeSeries
 		count := &prompb.Sample{
@@ -416,7 +418,7 @@ func (c *prometheusConverter) addSummaryDataPoints(dataPoints pmetric.SummaryDat
 			count.Value = math.Float64frombits(value.StaleNaN)
 		}
 		countlabels := createLabels(baseName+countStr, baseLabels)
-		c.addSample(count, countlabels)
+		c.setCreatedTimestamp(c.addSample(count, countlabels), pt.StartTimestamp())
 
 		// process each percentile/quantile
 		for i := 0; i < pt.QuantileValues().Len(); i++ {
@@ -430,7 +432,7 @@ func (c *prometheusConverter) addSummaryDataPoints(dataPoints pmetric.SummaryDat
 			}
 			percentileStr := strconv.FormatFloat(qt.Quantile(), 'f', -1, 64)
 			qtlabels := createLabels(baseName, baseLabels, quantileStr, percentileStr)
-			c.addSample(quantile, qtlabels)
+			c.setCreatedTimestamp(c.addSample(quantile, qtlabels), pt.StartTimestamp())
 		}
 
 		startTimestamp := pt.StartTimestamp()
@@ -483,6 +485,7 @@ func (c *prometheusConverter) getOrCreateTimeSeries(lbls []prompb.Label) (*promp
 			Labels: lbls,
 		}
 		c.conflicts[h] = append(c.conflicts[h], ts)
+		c.recordMetadata(ts)
 		return ts, true
 	}
 
@@ -491,6 +494,7 @@ func (c *prometheusConverter) getOrCreateTimeSeries(lbls []prompb.Label) (*promp
 		Labels: lbls,
 	}
 	c.unique[h] = ts
+	c.recordMetadata(ts)
 	return ts, true
 }
 
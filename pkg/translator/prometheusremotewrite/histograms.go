@@ -36,6 +36,7 @@ func (c *prometheusConverter) addExponentialHistogramDataPoints(dataPoints pmetr
 			return err
 		}
 		ts.Histograms = append(ts.Histograms, histogram)
+		c.setCreatedTimestamp(ts, pt.StartTimestamp())
 
 		exemplars := getPromExemplars[pmetric.ExponentialHistogramDataPoint](pt)
 		ts.Exemplars = append(ts.Exemplars, exemplars...)
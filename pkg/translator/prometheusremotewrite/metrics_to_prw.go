@@ -43,6 +43,10 @@ func FromMetrics(md pmetric.Metrics, settings Settings) (map[string]*prompb.Time
 type prometheusConverter struct {
 	unique    map[uint64]*prompb.TimeSeries
 	conflicts map[uint64][]*prompb.TimeSeries
+
+	// v2 tracks the additional per series information sent with Remote Write 2.0.
+	// It is nil when converting to Remote Write 1.0.
+	v2 *v2SeriesInfo
 }
 
 func newPrometheusConverter() *prometheusConverter {
@@ -76,6 +80,7 @@ func (c *prometheusConverter) fromMetrics(md pmetric.Metrics, settings Settings)
 				}
 
 				promName := prometheustranslator.BuildCompliantName(metric, settings.Namespace, settings.AddMetricSuffixes)
+				c.setMetricMetadata(metric)
 
 				// handle individual metrics based on type
 				//exhaustive:enforce
@@ -125,6 +130,7 @@ func (c *prometheusConverter) fromMetrics(md pmetric.Metrics, settings Settings)
 				}
 			}
 		}
+		c.setTargetInfoMetadata()
 		addResourceTargetInfo(resource, settings, mostRecentTimestamp, c)
 	}
 
@@ -0,0 +1,135 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusremotewrite // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheusremotewrite"
+
+import (
+	"github.com/prometheus/prometheus/prompb"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheusremotewrite/writev2"
+)
+
+// FromMetricsV2 converts pmetric.Metrics to Prometheus Remote Write 2.0 time series.
+// The strings of the returned time series are interned in the returned symbol table,
+// whose symbols must be sent in the same request.
+// Remote Write 2.0 carries metadata and created timestamps as part of each series,
+// so settings.SendMetadata and settings.ExportCreatedMetric are ignored.
+func FromMetricsV2(md pmetric.Metrics, settings Settings) ([]writev2.TimeSeries, writev2.SymbolsTable, error) {
+	c := newPrometheusConverter()
+	c.v2 = &v2SeriesInfo{
+		metadata: map[*prompb.TimeSeries]seriesMetadata{},
+		created:  map[*prompb.TimeSeries]int64{},
+	}
+	settings.ExportCreatedMetric = false
+	errs := c.fromMetrics(md, settings)
+
+	symbols := writev2.NewSymbolTable()
+	return c.timeSeriesV2(&symbols), symbols, errs
+}
+
+// seriesMetadata is the metadata of the metric a series was converted from.
+type seriesMetadata struct {
+	typ  writev2.Metadata_MetricType
+	help string
+	unit string
+}
+
+type v2SeriesInfo struct {
+	// current is the metadata of the metric that is being converted.
+	current  seriesMetadata
+	metadata map[*prompb.TimeSeries]seriesMetadata
+	created  map[*prompb.TimeSeries]int64
+}
+
+// setMetricMetadata sets the metadata recorded for the series that are created
+// from metric.
+func (c *prometheusConverter) setMetricMetadata(metric pmetric.Metric) {
+	if c.v2 == nil {
+		return
+	}
+	c.v2.current = seriesMetadata{
+		// Remote Write 1.0 and 2.0 metric types share their values.
+		typ:  writev2.Metadata_MetricType(otelMetricTypeToPromMetricType(metric)),
+		help: metric.Description(),
+		unit: metric.Unit(),
+	}
+}
+
+// setTargetInfoMetadata sets the metadata recorded for the target info series.
+func (c *prometheusConverter) setTargetInfoMetadata() {
+	if c.v2 == nil {
+		return
+	}
+	c.v2.current = seriesMetadata{
+		typ:  writev2.Metadata_METRIC_TYPE_GAUGE,
+		help: "Target metadata",
+	}
+}
+
+// recordMetadata associates the metadata of the metric being converted with ts.
+func (c *prometheusConverter) recordMetadata(ts *prompb.TimeSeries) {
+	if c.v2 == nil {
+		return
+	}
+	c.v2.metadata[ts] = c.v2.current
+}
+
+// setCreatedTimestamp records startTimestamp as the created timestamp of ts.
+func (c *prometheusConverter) setCreatedTimestamp(ts *prompb.TimeSeries, startTimestamp pcommon.Timestamp) {
+	if c.v2 == nil || ts == nil || startTimestamp == 0 {
+		return
+	}
+	c.v2.created[ts] = convertTimeStamp(startTimestamp)
+}
+
+// timeSeriesV2 returns the converted time series in Remote Write 2.0 format,
+// interning their strings in symbols.
+func (c *prometheusConverter) timeSeriesV2(symbols *writev2.SymbolsTable) []writev2.TimeSeries {
+	conflicts := 0
+	for _, ts := range c.conflicts {
+		conflicts += len(ts)
+	}
+	allTS := make([]writev2.TimeSeries, 0, len(c.unique)+conflicts)
+	for _, ts := range c.unique {
+		allTS = append(allTS, c.toTimeSeriesV2(ts, symbols))
+	}
+	for _, cTS := range c.conflicts {
+		for _, ts := range cTS {
+			allTS = append(allTS, c.toTimeSeriesV2(ts, symbols))
+		}
+	}
+	return allTS
+}
+
+func (c *prometheusConverter) toTimeSeriesV2(ts *prompb.TimeSeries, symbols *writev2.SymbolsTable) writev2.TimeSeries {
+	metadata := c.v2.metadata[ts]
+	out := writev2.TimeSeries{
+		LabelsRefs: symbols.SymbolizeLabels(ts.Labels, nil),
+		Histograms: ts.Histograms,
+		Metadata: writev2.Metadata{
+			Type:    metadata.typ,
+			HelpRef: symbols.Symbolize(metadata.help),
+			UnitRef: symbols.Symbolize(metadata.unit),
+		},
+		CreatedTimestamp: c.v2.created[ts],
+	}
+	if len(ts.Samples) > 0 {
+		out.Samples = make([]writev2.Sample, len(ts.Samples))
+		for i, s := range ts.Samples {
+			out.Samples[i] = writev2.Sample{Value: s.Value, Timestamp: s.Timestamp}
+		}
+	}
+	if len(ts.Exemplars) > 0 {
+		out.Exemplars = make([]writev2.Exemplar, len(ts.Exemplars))
+		for i, e := range ts.Exemplars {
+			out.Exemplars[i] = writev2.Exemplar{
+				LabelsRefs: symbols.SymbolizeLabels(e.Labels, nil),
+				Value:      e.Value,
+				Timestamp:  e.Timestamp,
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,139 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusremotewrite
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheusremotewrite/writev2"
+)
+
+func TestFromMetricsV2(t *testing.T) {
+	start := pcommon.NewTimestampFromTime(time.UnixMilli(1000))
+	ts := pcommon.NewTimestampFromTime(time.UnixMilli(2000))
+
+	md := pmetric.NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().PutStr("service.name", "api")
+	rm.Resource().Attributes().PutStr("service.instance.id", "host:80")
+	rm.Resource().Attributes().PutStr("host.arch", "amd64")
+	metrics := rm.ScopeMetrics().AppendEmpty().Metrics()
+
+	counter := metrics.AppendEmpty()
+	counter.SetName("requests")
+	counter.SetDescription("Number of requests")
+	counter.SetUnit("1")
+	sum := counter.SetEmptySum()
+	sum.SetIsMonotonic(true)
+	sum.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	dp := sum.DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetIntValue(5)
+	dp.Attributes().PutStr("method", "GET")
+
+	gauge := metrics.AppendEmpty()
+	gauge.SetName("temperature")
+	gauge.SetUnit("Cel")
+	gdp := gauge.SetEmptyGauge().DataPoints().AppendEmpty()
+	gdp.SetStartTimestamp(start)
+	gdp.SetTimestamp(ts)
+	gdp.SetDoubleValue(21.5)
+
+	series, symbols, err := FromMetricsV2(md, Settings{ExportCreatedMetric: true})
+	require.NoError(t, err)
+	require.Len(t, series, 3)
+
+	byName := map[string]writev2.TimeSeries{}
+	for _, s := range series {
+		lbls, err := writev2.DesymbolizeLabels(s.LabelsRefs, symbols.Symbols())
+		require.NoError(t, err)
+		for _, l := range lbls {
+			if l.Name == "__name__" {
+				byName[l.Value] = s
+			}
+		}
+	}
+	require.Contains(t, byName, "requests")
+	require.Contains(t, byName, "temperature")
+	require.Contains(t, byName, "target_info")
+	assert.NotContains(t, byName, "requests_created", "created timestamps are sent inline")
+
+	requests := byName["requests"]
+	lbls, err := writev2.DesymbolizeLabels(requests.LabelsRefs, symbols.Symbols())
+	require.NoError(t, err)
+	assert.Equal(t, []prompb.Label{
+		{Name: "__name__", Value: "requests"},
+		{Name: "instance", Value: "host:80"},
+		{Name: "job", Value: "api"},
+		{Name: "method", Value: "GET"},
+	}, lbls)
+	assert.Equal(t, []writev2.Sample{{Value: 5, Timestamp: 2000}}, requests.Samples)
+	assert.Equal(t, int64(1000), requests.CreatedTimestamp)
+	assert.Equal(t, writev2.Metadata_METRIC_TYPE_COUNTER, requests.Metadata.Type)
+	assert.Equal(t, "Number of requests", symbols.Symbols()[requests.Metadata.HelpRef])
+	assert.Equal(t, "1", symbols.Symbols()[requests.Metadata.UnitRef])
+
+	temperature := byName["temperature"]
+	assert.Equal(t, writev2.Metadata_METRIC_TYPE_GAUGE, temperature.Metadata.Type)
+	assert.Equal(t, "Cel", symbols.Symbols()[temperature.Metadata.UnitRef])
+	assert.Zero(t, temperature.Metadata.HelpRef)
+	assert.Zero(t, temperature.CreatedTimestamp, "gauges have no created timestamp")
+
+	assert.Equal(t, writev2.Metadata_METRIC_TYPE_GAUGE, byName["target_info"].Metadata.Type)
+}
+
+func TestFromMetricsV2Histograms(t *testing.T) {
+	start := pcommon.NewTimestampFromTime(time.UnixMilli(1000))
+	ts := pcommon.NewTimestampFromTime(time.UnixMilli(2000))
+
+	md := pmetric.NewMetrics()
+	metrics := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics()
+
+	histogram := metrics.AppendEmpty()
+	histogram.SetName("latency")
+	h := histogram.SetEmptyHistogram()
+	h.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	hdp := h.DataPoints().AppendEmpty()
+	hdp.SetStartTimestamp(start)
+	hdp.SetTimestamp(ts)
+	hdp.SetCount(3)
+	hdp.SetSum(6)
+	hdp.ExplicitBounds().FromRaw([]float64{1})
+	hdp.BucketCounts().FromRaw([]uint64{1, 2})
+
+	exponential := metrics.AppendEmpty()
+	exponential.SetName("size")
+	eh := exponential.SetEmptyExponentialHistogram()
+	eh.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	edp := eh.DataPoints().AppendEmpty()
+	edp.SetStartTimestamp(start)
+	edp.SetTimestamp(ts)
+	edp.SetCount(2)
+	edp.SetSum(3)
+	edp.Positive().BucketCounts().FromRaw([]uint64{1, 1})
+
+	series, symbols, err := FromMetricsV2(md, Settings{})
+	require.NoError(t, err)
+	// _sum, _count, two buckets and the native histogram.
+	require.Len(t, series, 5)
+	for _, s := range series {
+		assert.Equal(t, int64(1000), s.CreatedTimestamp)
+		lbls, err := writev2.DesymbolizeLabels(s.LabelsRefs, symbols.Symbols())
+		require.NoError(t, err)
+		for _, l := range lbls {
+			if l.Name == "__name__" && l.Value == "size" {
+				assert.Len(t, s.Histograms, 1)
+			}
+		}
+		assert.Equal(t, writev2.Metadata_METRIC_TYPE_HISTOGRAM, s.Metadata.Type)
+	}
+}
@@ -70,6 +70,9 @@ func (c *prometheusConverter) addSumNumberDataPoints(dataPoints pmetric.NumberDa
 			sample.Value = math.Float64frombits(value.StaleNaN)
 		}
 		ts := c.addSample(sample, lbls)
+		if metric.Sum().IsMonotonic() {
+			c.setCreatedTimestamp(ts, pt.StartTimestamp())
+		}
 		if ts != nil {
 			exemplars := getPromExemplars[pmetric.NumberDataPoint](pt)
 			ts.Exemplars = append(ts.Exemplars, exemplars...)
@@ -0,0 +1,698 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusremotewrite // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheusremotewrite"
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/value"
+	"github.com/prometheus/prometheus/prompb"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	conventions "go.opentelemetry.io/collector/semconv/v1.6.1"
+	"go.uber.org/multierr"
+
+	prometheustranslator "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheus"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheusremotewrite/writev2"
+)
+
+const totalSuffix = "_total"
+
+var errMissingMetricName = errors.New("series has no metric name")
+
+// ToMetrics converts a Prometheus Remote Write 1.0 request to pmetric.Metrics.
+//
+// Metric types are taken from the metadata of the request when available and
+// are otherwise inferred from the series names and labels: series with an "le"
+// label and a "_bucket" suffix form histograms, series with a "quantile" label
+// form summaries, series with a "_total" suffix are monotonic sums and all
+// other series are gauges. The "job" and "instance" labels become the
+// service.name and service.instance.id resource attributes, and the labels of
+// "target_info" series become attributes of the matching resource.
+// Series that cannot be converted are skipped and reported in the returned error.
+func ToMetrics(req *prompb.WriteRequest) (pmetric.Metrics, error) {
+	metadata := make(map[string]seriesMetadata, len(req.Metadata))
+	for _, m := range req.Metadata {
+		metadata[m.MetricFamilyName] = seriesMetadata{
+			// Remote Write 1.0 and 2.0 metric types share their values.
+			typ:  writev2.Metadata_MetricType(m.Type),
+			help: m.Help,
+			unit: m.Unit,
+		}
+	}
+
+	c := newRemoteWriteConverter()
+	var errs error
+	for i := range req.Timeseries {
+		ts := &req.Timeseries[i]
+		s := remoteSeries{
+			labels:     ts.Labels,
+			samples:    ts.Samples,
+			histograms: ts.Histograms,
+			exemplars:  ts.Exemplars,
+		}
+		if err := s.init(); err != nil {
+			errs = multierr.Append(errs, err)
+			continue
+		}
+		s.metadata = lookupMetadata(metadata, s.name)
+		c.series = append(c.series, s)
+	}
+	return c.convert(), errs
+}
+
+// WriteStats counts the samples, histograms and exemplars of the series that
+// were converted from a Remote Write request.
+type WriteStats struct {
+	Samples    int
+	Histograms int
+	Exemplars  int
+}
+
+// ToMetricsV2 converts a Prometheus Remote Write 2.0 request to pmetric.Metrics.
+// Conversion follows ToMetrics, with the metadata and created timestamps of each
+// series taken from the series itself. The returned stats only count the series
+// that were converted.
+func ToMetricsV2(req *writev2.Request) (pmetric.Metrics, WriteStats, error) {
+	c := newRemoteWriteConverter()
+	var stats WriteStats
+	var errs error
+	for i := range req.Timeseries {
+		s, err := fromTimeSeriesV2(&req.Timeseries[i], req.Symbols)
+		if err != nil {
+			errs = multierr.Append(errs, err)
+			continue
+		}
+		stats.Samples += len(s.samples)
+		stats.Histograms += len(s.histograms)
+		stats.Exemplars += len(s.exemplars)
+		c.series = append(c.series, s)
+	}
+	return c.convert(), stats, errs
+}
+
+func fromTimeSeriesV2(ts *writev2.TimeSeries, symbols []string) (remoteSeries, error) {
+	lbls, err := writev2.DesymbolizeLabels(ts.LabelsRefs, symbols)
+	if err != nil {
+		return remoteSeries{}, err
+	}
+	help, err := writev2.Symbol(ts.Metadata.HelpRef, symbols)
+	if err != nil {
+		return remoteSeries{}, err
+	}
+	unit, err := writev2.Symbol(ts.Metadata.UnitRef, symbols)
+	if err != nil {
+		return remoteSeries{}, err
+	}
+	s := remoteSeries{
+		labels:     lbls,
+		histograms: ts.Histograms,
+		metadata:   seriesMetadata{typ: ts.Metadata.Type, help: help, unit: unit},
+		created:    ts.CreatedTimestamp,
+	}
+	if len(ts.Samples) > 0 {
+		s.samples = make([]prompb.Sample, len(ts.Samples))
+		for i, sample := range ts.Samples {
+			s.samples[i] = prompb.Sample{Value: sample.Value, Timestamp: sample.Timestamp}
+		}
+	}
+	for _, e := range ts.Exemplars {
+		exemplarLabels, err := writev2.DesymbolizeLabels(e.LabelsRefs, symbols)
+		if err != nil {
+			return remoteSeries{}, err
+		}
+		s.exemplars = append(s.exemplars, prompb.Exemplar{Labels: exemplarLabels, Value: e.Value, Timestamp: e.Timestamp})
+	}
+	return s, s.init()
+}
+
+// lookupMetadata returns the metadata of the metric family of the series called name.
+func lookupMetadata(metadata map[string]seriesMetadata, name string) seriesMetadata {
+	if m, ok := metadata[name]; ok {
+		return m
+	}
+	for _, suffix := range []string{bucketStr, sumStr, countStr, totalSuffix, createdSuffix} {
+		if base, ok := strings.CutSuffix(name, suffix); ok {
+			if m, ok := metadata[base]; ok {
+				return m
+			}
+		}
+	}
+	return seriesMetadata{}
+}
+
+// remoteSeries is a series of a Remote Write request of either version.
+type remoteSeries struct {
+	labels     []prompb.Label
+	samples    []prompb.Sample
+	histograms []prompb.Histogram
+	exemplars  []prompb.Exemplar
+	metadata   seriesMetadata
+	// created is the created timestamp in milliseconds.
+	created int64
+
+	// name, job and instance are the values of the corresponding labels.
+	name     string
+	job      string
+	instance string
+}
+
+func (s *remoteSeries) init() error {
+	for _, l := range s.labels {
+		switch l.Name {
+		case model.MetricNameLabel:
+			s.name = l.Value
+		case model.JobLabel:
+			s.job = l.Value
+		case model.InstanceLabel:
+			s.instance = l.Value
+		}
+	}
+	if s.name == "" {
+		return errMissingMetricName
+	}
+	for i := range s.histograms {
+		// Exponential histograms only support the exponential schemas, native
+		// histograms with custom buckets cannot be converted.
+		if schema := s.histograms[i].Schema; schema < -4 || schema > 8 {
+			return fmt.Errorf("series %q: unsupported native histogram schema %d", s.name, schema)
+		}
+	}
+	return nil
+}
+
+// label returns the value of the label called name.
+func (s *remoteSeries) label(name string) (string, bool) {
+	for _, l := range s.labels {
+		if l.Name == name {
+			return l.Value, true
+		}
+	}
+	return "", false
+}
+
+// attributes returns the labels of the series that become data point
+// attributes, sorted by name, and a key identifying them.
+func (s *remoteSeries) attributes(excluded string) ([]prompb.Label, string) {
+	attrs := make([]prompb.Label, 0, len(s.labels))
+	for _, l := range s.labels {
+		switch l.Name {
+		case model.MetricNameLabel, model.JobLabel, model.InstanceLabel, excluded:
+			continue
+		}
+		attrs = append(attrs, l)
+	}
+	sort.Sort(ByLabelName(attrs))
+	var key strings.Builder
+	for _, l := range attrs {
+		key.WriteString(l.Name)
+		key.WriteByte(0xff)
+		key.WriteString(l.Value)
+		key.WriteByte(0xff)
+	}
+	return attrs, key.String()
+}
+
+// remoteWriteConverter converts the series of a Remote Write request to OTel format.
+type remoteWriteConverter struct {
+	series []remoteSeries
+
+	metrics   pmetric.Metrics
+	resources map[string]pmetric.ResourceMetrics
+	// families holds the converted metrics by resource and metric family.
+	families map[string]*metricFamily
+}
+
+func newRemoteWriteConverter() *remoteWriteConverter {
+	return &remoteWriteConverter{
+		metrics:   pmetric.NewMetrics(),
+		resources: map[string]pmetric.ResourceMetrics{},
+		families:  map[string]*metricFamily{},
+	}
+}
+
+type familyKind int
+
+const (
+	kindGauge familyKind = iota
+	kindSum
+	kindHistogram
+	kindSummary
+	kindExponentialHistogram
+)
+
+type metricFamily struct {
+	metric pmetric.Metric
+	// histograms and summaries hold the classic histogram and summary data
+	// points by attributes and timestamp.
+	histograms map[string]*classicHistogram
+	summaries  map[string]*summaryPoint
+}
+
+type classicHistogram struct {
+	point   pmetric.HistogramDataPoint
+	buckets map[float64]float64
+	count   float64
+	// hasCount is true if the count was received in a _count series.
+	hasCount bool
+}
+
+type summaryPoint struct {
+	point     pmetric.SummaryDataPoint
+	quantiles map[float64]float64
+}
+
+func (c *remoteWriteConverter) convert() pmetric.Metrics {
+	histogramFamilies := map[string]bool{}
+	summaryFamilies := map[string]bool{}
+	for i := range c.series {
+		s := &c.series[i]
+		if s.metadata.typ != writev2.Metadata_METRIC_TYPE_UNSPECIFIED {
+			continue
+		}
+		if base, ok := strings.CutSuffix(s.name, bucketStr); ok {
+			if _, ok := s.label(leStr); ok {
+				histogramFamilies[base] = true
+			}
+		}
+		if _, ok := s.label(quantileStr); ok {
+			summaryFamilies[s.name] = true
+		}
+	}
+
+	for i := range c.series {
+		s := &c.series[i]
+		if s.name == targetMetricName {
+			c.addTargetInfo(s)
+		}
+	}
+
+	for i := range c.series {
+		s := &c.series[i]
+		if s.name == targetMetricName {
+			continue
+		}
+		kind, family := classify(s, histogramFamilies, summaryFamilies)
+		f := c.family(s, kind, family)
+		switch kind {
+		case kindGauge:
+			addNumberDataPoints(f.metric.Gauge().DataPoints(), s)
+		case kindSum:
+			addNumberDataPoints(f.metric.Sum().DataPoints(), s)
+		case kindHistogram:
+			f.addClassicHistogramSeries(s, family)
+		case kindSummary:
+			f.addSummarySeries(s, family)
+		case kindExponentialHistogram:
+			addExponentialHistogramDataPoints(f.metric.ExponentialHistogram().DataPoints(), s)
+		}
+	}
+
+	for _, f := range c.families {
+		f.finish()
+	}
+	return c.metrics
+}
+
+// classify returns the kind and name of the metric family s belongs to.
+func classify(s *remoteSeries, histogramFamilies, summaryFamilies map[string]bool) (familyKind, string) {
+	if len(s.histograms) > 0 {
+		return kindExponentialHistogram, s.name
+	}
+	switch s.metadata.typ {
+	case writev2.Metadata_METRIC_TYPE_COUNTER:
+		return kindSum, s.name
+	case writev2.Metadata_METRIC_TYPE_HISTOGRAM, writev2.Metadata_METRIC_TYPE_GAUGEHISTOGRAM:
+		for _, suffix := range []string{bucketStr, sumStr, countStr} {
+			if base, ok := strings.CutSuffix(s.name, suffix); ok {
+				return kindHistogram, base
+			}
+		}
+		return kindGauge, s.name
+	case writev2.Metadata_METRIC_TYPE_SUMMARY:
+		if _, ok := s.label(quantileStr); ok {
+			return kindSummary, s.name
+		}
+		for _, suffix := range []string{sumStr, countStr} {
+			if base, ok := strings.CutSuffix(s.name, suffix); ok {
+				return kindSummary, base
+			}
+		}
+		return kindGauge, s.name
+	case writev2.Metadata_METRIC_TYPE_UNSPECIFIED:
+		for _, suffix := range []string{bucketStr, sumStr, countStr} {
+			if base, ok := strings.CutSuffix(s.name, suffix); ok && histogramFamilies[base] {
+				return kindHistogram, base
+			}
+		}
+		if summaryFamilies[s.name] {
+			return kindSummary, s.name
+		}
+		for _, suffix := range []string{sumStr, countStr} {
+			if base, ok := strings.CutSuffix(s.name, suffix); ok && summaryFamilies[base] {
+				return kindSummary, base
+			}
+		}
+		if strings.HasSuffix(s.name, totalSuffix) {
+			return kindSum, s.name
+		}
+	}
+	return kindGauge, s.name
+}
+
+func resourceKey(job, instance string) string {
+	return job + "\xff" + instance
+}
+
+// resource returns the resource metrics for the job and instance of s.
+func (c *remoteWriteConverter) resource(s *remoteSeries) pmetric.ResourceMetrics {
+	key := resourceKey(s.job, s.instance)
+	rm, ok := c.resources[key]
+	if !ok {
+		rm = c.metrics.ResourceMetrics().AppendEmpty()
+		attrs := rm.Resource().Attributes()
+		if s.job != "" {
+			attrs.PutStr(conventions.AttributeServiceName, s.job)
+		}
+		if s.instance != "" {
+			attrs.PutStr(conventions.AttributeServiceInstanceID, s.instance)
+		}
+		rm.ScopeMetrics().AppendEmpty()
+		c.resources[key] = rm
+	}
+	return rm
+}
+
+func (c *remoteWriteConverter) addTargetInfo(s *remoteSeries) {
+	attrs := c.resource(s).Resource().Attributes()
+	for _, l := range s.labels {
+		switch l.Name {
+		case model.MetricNameLabel, model.JobLabel, model.InstanceLabel:
+			continue
+		}
+		attrs.PutStr(l.Name, l.Value)
+	}
+}
+
+// family returns the metric the series of family belong to, creating it if needed.
+func (c *remoteWriteConverter) family(s *remoteSeries, kind familyKind, name string) *metricFamily {
+	key := resourceKey(s.job, s.instance) + "\xff" + strconv.Itoa(int(kind)) + "\xff" + name
+	if f, ok := c.families[key]; ok {
+		return f
+	}
+	metric := c.resource(s).ScopeMetrics().At(0).Metrics().AppendEmpty()
+	metric.SetName(name)
+	metric.SetDescription(s.metadata.help)
+	if s.metadata.unit != "" {
+		metric.SetUnit(prometheustranslator.UnitWordToUCUM(s.metadata.unit))
+	}
+	f := &metricFamily{metric: metric}
+	switch kind {
+	case kindGauge:
+		metric.SetEmptyGauge()
+	case kindSum:
+		sum := metric.SetEmptySum()
+		sum.SetIsMonotonic(true)
+		sum.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	case kindHistogram:
+		metric.SetEmptyHistogram().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+		f.histograms = map[string]*classicHistogram{}
+	case kindSummary:
+		metric.SetEmptySummary()
+		f.summaries = map[string]*summaryPoint{}
+	case kindExponentialHistogram:
+		metric.SetEmptyExponentialHistogram().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	}
+	c.families[key] = f
+	return f
+}
+
+func putAttributes(dest pcommon.Map, attrs []prompb.Label) {
+	dest.EnsureCapacity(len(attrs))
+	for _, l := range attrs {
+		dest.PutStr(l.Name, l.Value)
+	}
+}
+
+func fromMillis(ms int64) pcommon.Timestamp {
+	return pcommon.Timestamp(ms * 1e6)
+}
+
+func addNumberDataPoints(dest pmetric.NumberDataPointSlice, s *remoteSeries) {
+	attrs, _ := s.attributes("")
+	var last pmetric.NumberDataPoint
+	for _, sample := range s.samples {
+		dp := dest.AppendEmpty()
+		putAttributes(dp.Attributes(), attrs)
+		dp.SetTimestamp(fromMillis(sample.Timestamp))
+		if s.created != 0 {
+			dp.SetStartTimestamp(fromMillis(s.created))
+		}
+		if value.IsStaleNaN(sample.Value) {
+			dp.SetFlags(pmetric.DefaultDataPointFlags.WithNoRecordedValue(true))
+		} else {
+			dp.SetDoubleValue(sample.Value)
+		}
+		last = dp
+	}
+	if len(s.samples) > 0 {
+		addExemplars(last.Exemplars(), s.exemplars)
+	}
+}
+
+func (f *metricFamily) addClassicHistogramSeries(s *remoteSeries, family string) {
+	attrs, key := s.attributes(leStr)
+	var bound float64
+	isBucket := s.name == family+bucketStr
+	if isBucket {
+		le, _ := s.label(leStr)
+		var err error
+		if bound, err = strconv.ParseFloat(le, 64); err != nil {
+			return
+		}
+	}
+	var last *classicHistogram
+	for _, sample := range s.samples {
+		pointKey := key + strconv.FormatInt(sample.Timestamp, 10)
+		h, ok := f.histograms[pointKey]
+		if !ok {
+			dp := f.metric.Histogram().DataPoints().AppendEmpty()
+			putAttributes(dp.Attributes(), attrs)
+			dp.SetTimestamp(fromMillis(sample.Timestamp))
+			h = &classicHistogram{point: dp, buckets: map[float64]float64{}}
+			f.histograms[pointKey] = h
+		}
+		if s.created != 0 {
+			h.point.SetStartTimestamp(fromMillis(s.created))
+		}
+		if value.IsStaleNaN(sample.Value) {
+			h.point.SetFlags(pmetric.DefaultDataPointFlags.WithNoRecordedValue(true))
+			continue
+		}
+		switch {
+		case isBucket:
+			h.buckets[bound] = sample.Value
+		case s.name == family+sumStr:
+			h.point.SetSum(sample.Value)
+		case s.name == family+countStr:
+			h.count = sample.Value
+			h.hasCount = true
+		}
+		last = h
+	}
+	if last != nil {
+		addExemplars(last.point.Exemplars(), s.exemplars)
+	}
+}
+
+func (f *metricFamily) addSummarySeries(s *remoteSeries, family string) {
+	attrs, key := s.attributes(quantileStr)
+	var quantile float64
+	isQuantile := s.name == family
+	if isQuantile {
+		q, _ := s.label(quantileStr)
+		var err error
+		if quantile, err = strconv.ParseFloat(q, 64); err != nil {
+			return
+		}
+	}
+	for _, sample := range s.samples {
+		pointKey := key + strconv.FormatInt(sample.Timestamp, 10)
+		p, ok := f.summaries[pointKey]
+		if !ok {
+			dp := f.metric.Summary().DataPoints().AppendEmpty()
+			putAttributes(dp.Attributes(), attrs)
+			dp.SetTimestamp(fromMillis(sample.Timestamp))
+			p = &summaryPoint{point: dp, quantiles: map[float64]float64{}}
+			f.summaries[pointKey] = p
+		}
+		if s.created != 0 {
+			p.point.SetStartTimestamp(fromMillis(s.created))
+		}
+		if value.IsStaleNaN(sample.Value) {
+			p.point.SetFlags(pmetric.DefaultDataPointFlags.WithNoRecordedValue(true))
+			continue
+		}
+		switch {
+		case isQuantile:
+			p.quantiles[quantile] = sample.Value
+		case s.name == family+sumStr:
+			p.point.SetSum(sample.Value)
+		case s.name == family+countStr:
+			p.point.SetCount(uint64(sample.Value))
+		}
+	}
+}
+
+// finish sets the buckets and quantiles collected for classic histograms and summaries.
+func (f *metricFamily) finish() {
+	for _, h := range f.histograms {
+		bounds := make([]float64, 0, len(h.buckets))
+		for bound := range h.buckets {
+			if !math.IsInf(bound, 1) {
+				bounds = append(bounds, bound)
+			}
+		}
+		sort.Float64s(bounds)
+
+		total := h.count
+		if inf, ok := h.buckets[math.Inf(1)]; ok && !h.hasCount {
+			total = inf
+		}
+		h.point.SetCount(uint64(total))
+		h.point.ExplicitBounds().FromRaw(bounds)
+		counts := h.point.BucketCounts()
+		counts.EnsureCapacity(len(bounds) + 1)
+		var previous float64
+		for _, bound := range bounds {
+			cumulative := h.buckets[bound]
+			counts.Append(uint64(math.Max(cumulative-previous, 0)))
+			previous = cumulative
+		}
+		counts.Append(uint64(math.Max(total-previous, 0)))
+	}
+	for _, p := range f.summaries {
+		quantiles := make([]float64, 0, len(p.quantiles))
+		for q := range p.quantiles {
+			quantiles = append(quantiles, q)
+		}
+		sort.Float64s(quantiles)
+		values := p.point.QuantileValues()
+		values.EnsureCapacity(len(quantiles))
+		for _, q := range quantiles {
+			v := values.AppendEmpty()
+			v.SetQuantile(q)
+			v.SetValue(p.quantiles[q])
+		}
+	}
+}
+
+func addExponentialHistogramDataPoints(dest pmetric.ExponentialHistogramDataPointSlice, s *remoteSeries) {
+	attrs, _ := s.attributes("")
+	var last pmetric.ExponentialHistogramDataPoint
+	for i := range s.histograms {
+		h := &s.histograms[i]
+		dp := dest.AppendEmpty()
+		putAttributes(dp.Attributes(), attrs)
+		dp.SetTimestamp(fromMillis(h.Timestamp))
+		if s.created != 0 {
+			dp.SetStartTimestamp(fromMillis(s.created))
+		}
+		if value.IsStaleNaN(h.Sum) {
+			dp.SetFlags(pmetric.DefaultDataPointFlags.WithNoRecordedValue(true))
+		} else {
+			dp.SetSum(h.Sum)
+		}
+		dp.SetScale(h.Schema)
+		dp.SetZeroThreshold(h.ZeroThreshold)
+		if h.IsFloatHistogram() {
+			dp.SetCount(uint64(math.Round(h.GetCountFloat())))
+			dp.SetZeroCount(uint64(math.Round(h.GetZeroCountFloat())))
+			floatBucketsToExponential(h.PositiveSpans, h.PositiveCounts, dp.Positive())
+			floatBucketsToExponential(h.NegativeSpans, h.NegativeCounts, dp.Negative())
+		} else {
+			dp.SetCount(h.GetCountInt())
+			dp.SetZeroCount(h.GetZeroCountInt())
+			deltaBucketsToExponential(h.PositiveSpans, h.PositiveDeltas, dp.Positive())
+			deltaBucketsToExponential(h.NegativeSpans, h.NegativeDeltas, dp.Negative())
+		}
+		last = dp
+	}
+	if len(s.histograms) > 0 {
+		addExemplars(last.Exemplars(), s.exemplars)
+	}
+}
+
+// deltaBucketsToExponential converts the delta encoded buckets of an integer
+// native histogram into dense exponential histogram buckets.
+func deltaBucketsToExponential(spans []prompb.BucketSpan, deltas []int64, buckets pmetric.ExponentialHistogramDataPointBuckets) {
+	counts := make([]float64, len(deltas))
+	var count int64
+	for i, delta := range deltas {
+		count += delta
+		counts[i] = float64(count)
+	}
+	floatBucketsToExponential(spans, counts, buckets)
+}
+
+// floatBucketsToExponential converts the sparse buckets of a native histogram
+// into dense exponential histogram buckets. Native histogram bucket i covers
+// the same range as exponential histogram bucket i-1.
+func floatBucketsToExponential(spans []prompb.BucketSpan, counts []float64, buckets pmetric.ExponentialHistogramDataPointBuckets) {
+	if len(spans) == 0 || len(counts) == 0 {
+		return
+	}
+	bucketCounts := buckets.BucketCounts()
+	var idx int32
+	next := 0
+	for i, span := range spans {
+		idx += span.Offset
+		if i == 0 {
+			buckets.SetOffset(idx - 1)
+		} else {
+			for j := int32(0); j < span.Offset; j++ {
+				bucketCounts.Append(0)
+			}
+		}
+		for j := uint32(0); j < span.Length && next < len(counts); j++ {
+			bucketCounts.Append(uint64(math.Round(math.Max(counts[next], 0))))
+			next++
+		}
+		idx += int32(span.Length)
+	}
+}
+
+func addExemplars(dest pmetric.ExemplarSlice, exemplars []prompb.Exemplar) {
+	for _, e := range exemplars {
+		exemplar := dest.AppendEmpty()
+		exemplar.SetDoubleValue(e.Value)
+		exemplar.SetTimestamp(fromMillis(e.Timestamp))
+		for _, l := range e.Labels {
+			switch l.Name {
+			case traceIDKey:
+				var traceID pcommon.TraceID
+				if b, err := hex.DecodeString(l.Value); err == nil && len(b) == len(traceID) {
+					copy(traceID[:], b)
+					exemplar.SetTraceID(traceID)
+					continue
+				}
+			case spanIDKey:
+				var spanID pcommon.SpanID
+				if b, err := hex.DecodeString(l.Value); err == nil && len(b) == len(spanID) {
+					copy(spanID[:], b)
+					exemplar.SetSpanID(spanID)
+					continue
+				}
+			}
+			exemplar.FilteredAttributes().PutStr(l.Name, l.Value)
+		}
+	}
+}
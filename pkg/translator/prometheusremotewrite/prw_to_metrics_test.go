@@ -0,0 +1,258 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusremotewrite
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/prometheus/prometheus/model/value"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheusremotewrite/writev2"
+)
+
+func metricsByName(t *testing.T, md pmetric.Metrics) map[string]pmetric.Metric {
+	out := map[string]pmetric.Metric{}
+	for i := 0; i < md.ResourceMetrics().Len(); i++ {
+		sms := md.ResourceMetrics().At(i).ScopeMetrics()
+		for j := 0; j < sms.Len(); j++ {
+			ms := sms.At(j).Metrics()
+			for k := 0; k < ms.Len(); k++ {
+				_, dup := out[ms.At(k).Name()]
+				require.False(t, dup, "duplicate metric %s", ms.At(k).Name())
+				out[ms.At(k).Name()] = ms.At(k)
+			}
+		}
+	}
+	return out
+}
+
+func TestToMetricsInfersTypes(t *testing.T) {
+	req := &prompb.WriteRequest{
+		Timeseries: []prompb.TimeSeries{
+			*getTimeSeries(getPromLabels("__name__", "temperature", "job", "api", "instance", "host:80", "room", "a"), getSample(21.5, 2000)),
+			*getTimeSeries(getPromLabels("__name__", "requests_total", "job", "api", "instance", "host:80"), getSample(5, 2000), getSample(7, 3000)),
+			*getTimeSeries(getPromLabels("__name__", "latency_bucket", "job", "api", "instance", "host:80", "le", "1"), getSample(1, 2000)),
+			*getTimeSeries(getPromLabels("__name__", "latency_bucket", "job", "api", "instance", "host:80", "le", "+Inf"), getSample(3, 2000)),
+			*getTimeSeries(getPromLabels("__name__", "latency_sum", "job", "api", "instance", "host:80"), getSample(6, 2000)),
+			*getTimeSeries(getPromLabels("__name__", "latency_count", "job", "api", "instance", "host:80"), getSample(3, 2000)),
+			*getTimeSeries(getPromLabels("__name__", "rpc", "job", "api", "instance", "host:80", "quantile", "0.99"), getSample(0.3, 2000)),
+			*getTimeSeries(getPromLabels("__name__", "rpc", "job", "api", "instance", "host:80", "quantile", "0.5"), getSample(0.1, 2000)),
+			*getTimeSeries(getPromLabels("__name__", "rpc_count", "job", "api", "instance", "host:80"), getSample(10, 2000)),
+			*getTimeSeries(getPromLabels("__name__", "target_info", "job", "api", "instance", "host:80", "host_arch", "amd64"), getSample(1, 2000)),
+			*getTimeSeries(getPromLabels("__name__", "up", "job", "other"), getSample(math.Float64frombits(value.StaleNaN), 2000)),
+		},
+	}
+
+	md, err := ToMetrics(req)
+	require.NoError(t, err)
+	require.Equal(t, 2, md.ResourceMetrics().Len())
+
+	resource := md.ResourceMetrics().At(0).Resource().Attributes()
+	assert.Equal(t, map[string]any{
+		"service.name":        "api",
+		"service.instance.id": "host:80",
+		"host_arch":           "amd64",
+	}, resource.AsRaw())
+
+	metrics := metricsByName(t, md)
+	require.Len(t, metrics, 5)
+
+	temperature := metrics["temperature"]
+	require.Equal(t, pmetric.MetricTypeGauge, temperature.Type())
+	gdp := temperature.Gauge().DataPoints().At(0)
+	assert.Equal(t, 21.5, gdp.DoubleValue())
+	assert.Equal(t, pcommon.NewTimestampFromTime(time.UnixMilli(2000)), gdp.Timestamp())
+	assert.Equal(t, map[string]any{"room": "a"}, gdp.Attributes().AsRaw())
+
+	requests := metrics["requests_total"]
+	require.Equal(t, pmetric.MetricTypeSum, requests.Type())
+	assert.True(t, requests.Sum().IsMonotonic())
+	assert.Equal(t, pmetric.AggregationTemporalityCumulative, requests.Sum().AggregationTemporality())
+	assert.Equal(t, 2, requests.Sum().DataPoints().Len())
+
+	latency := metrics["latency"]
+	require.Equal(t, pmetric.MetricTypeHistogram, latency.Type())
+	hdp := latency.Histogram().DataPoints().At(0)
+	assert.Equal(t, uint64(3), hdp.Count())
+	assert.Equal(t, 6.0, hdp.Sum())
+	assert.Equal(t, []float64{1}, hdp.ExplicitBounds().AsRaw())
+	assert.Equal(t, []uint64{1, 2}, hdp.BucketCounts().AsRaw())
+
+	rpc := metrics["rpc"]
+	require.Equal(t, pmetric.MetricTypeSummary, rpc.Type())
+	sdp := rpc.Summary().DataPoints().At(0)
+	assert.Equal(t, uint64(10), sdp.Count())
+	require.Equal(t, 2, sdp.QuantileValues().Len())
+	assert.Equal(t, 0.5, sdp.QuantileValues().At(0).Quantile())
+	assert.Equal(t, 0.1, sdp.QuantileValues().At(0).Value())
+	assert.Equal(t, 0.99, sdp.QuantileValues().At(1).Quantile())
+
+	up := metrics["up"]
+	assert.True(t, up.Gauge().DataPoints().At(0).Flags().NoRecordedValue())
+}
+
+func TestToMetricsUsesMetadata(t *testing.T) {
+	req := &prompb.WriteRequest{
+		Timeseries: []prompb.TimeSeries{
+			*getTimeSeries(getPromLabels("__name__", "queue_size", "job", "api"), getSample(5, 2000)),
+			*getTimeSeries(getPromLabels("__name__", "bytes_sent", "job", "api"), getSample(1024, 2000)),
+		},
+		Metadata: []prompb.MetricMetadata{
+			{MetricFamilyName: "bytes_sent", Type: prompb.MetricMetadata_COUNTER, Help: "Bytes sent", Unit: "bytes"},
+		},
+	}
+	md, err := ToMetrics(req)
+	require.NoError(t, err)
+	metrics := metricsByName(t, md)
+	assert.Equal(t, pmetric.MetricTypeGauge, metrics["queue_size"].Type())
+	bytesSent := metrics["bytes_sent"]
+	require.Equal(t, pmetric.MetricTypeSum, bytesSent.Type())
+	assert.Equal(t, "Bytes sent", bytesSent.Description())
+	assert.Equal(t, "By", bytesSent.Unit())
+}
+
+func TestToMetricsSkipsInvalidSeries(t *testing.T) {
+	req := &prompb.WriteRequest{
+		Timeseries: []prompb.TimeSeries{
+			*getTimeSeries(getPromLabels("job", "api"), getSample(5, 2000)),
+			{
+				Labels:     getPromLabels("__name__", "custom_buckets"),
+				Histograms: []prompb.Histogram{{Schema: -53, Timestamp: 2000}},
+			},
+			*getTimeSeries(getPromLabels("__name__", "up", "job", "api"), getSample(1, 2000)),
+		},
+	}
+	md, err := ToMetrics(req)
+	assert.ErrorIs(t, err, errMissingMetricName)
+	assert.Equal(t, 1, md.MetricCount())
+}
+
+func TestToMetricsV2RoundTrip(t *testing.T) {
+	start := pcommon.NewTimestampFromTime(time.UnixMilli(1000))
+	ts := pcommon.NewTimestampFromTime(time.UnixMilli(2000))
+	traceID := pcommon.TraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16})
+
+	md := pmetric.NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().PutStr("service.name", "api")
+	metrics := rm.ScopeMetrics().AppendEmpty().Metrics()
+
+	counter := metrics.AppendEmpty()
+	counter.SetName("requests")
+	counter.SetDescription("Number of requests")
+	sum := counter.SetEmptySum()
+	sum.SetIsMonotonic(true)
+	sum.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	dp := sum.DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetIntValue(5)
+	exemplar := dp.Exemplars().AppendEmpty()
+	exemplar.SetTraceID(traceID)
+	exemplar.SetDoubleValue(1)
+	exemplar.SetTimestamp(ts)
+
+	histogram := metrics.AppendEmpty()
+	histogram.SetName("latency")
+	histogram.SetUnit("s")
+	h := histogram.SetEmptyHistogram()
+	h.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	hdp := h.DataPoints().AppendEmpty()
+	hdp.SetStartTimestamp(start)
+	hdp.SetTimestamp(ts)
+	hdp.SetCount(3)
+	hdp.SetSum(6)
+	hdp.ExplicitBounds().FromRaw([]float64{1, 2})
+	hdp.BucketCounts().FromRaw([]uint64{1, 0, 2})
+
+	exponential := metrics.AppendEmpty()
+	exponential.SetName("size")
+	eh := exponential.SetEmptyExponentialHistogram()
+	eh.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	edp := eh.DataPoints().AppendEmpty()
+	edp.SetStartTimestamp(start)
+	edp.SetTimestamp(ts)
+	edp.SetScale(2)
+	edp.SetCount(6)
+	edp.SetZeroCount(1)
+	edp.SetSum(12)
+	edp.Positive().SetOffset(3)
+	edp.Positive().BucketCounts().FromRaw([]uint64{1, 0, 0, 0, 2})
+	edp.Negative().SetOffset(-1)
+	edp.Negative().BucketCounts().FromRaw([]uint64{2})
+
+	series, symbols, err := FromMetricsV2(md, Settings{DisableTargetInfo: true})
+	require.NoError(t, err)
+	req := &writev2.Request{Symbols: symbols.Symbols(), Timeseries: series}
+
+	// Go through the wire format to cover the encoding as well.
+	b, err := req.Marshal()
+	require.NoError(t, err)
+	var decoded writev2.Request
+	require.NoError(t, decoded.Unmarshal(b))
+
+	got, stats, err := ToMetricsV2(&decoded)
+	require.NoError(t, err)
+	// The counter and the five classic histogram series each have one sample.
+	assert.Equal(t, WriteStats{Samples: 6, Histograms: 1, Exemplars: 1}, stats)
+	require.Equal(t, 1, got.ResourceMetrics().Len())
+	assert.Equal(t, map[string]any{"service.name": "api"}, got.ResourceMetrics().At(0).Resource().Attributes().AsRaw())
+
+	gotMetrics := metricsByName(t, got)
+	require.Len(t, gotMetrics, 3)
+
+	requests := gotMetrics["requests"]
+	require.Equal(t, pmetric.MetricTypeSum, requests.Type())
+	assert.Equal(t, "Number of requests", requests.Description())
+	rdp := requests.Sum().DataPoints().At(0)
+	assert.Equal(t, 5.0, rdp.DoubleValue())
+	assert.Equal(t, start, rdp.StartTimestamp())
+	assert.Equal(t, ts, rdp.Timestamp())
+	require.Equal(t, 1, rdp.Exemplars().Len())
+	assert.Equal(t, traceID, rdp.Exemplars().At(0).TraceID())
+
+	latency := gotMetrics["latency"]
+	require.Equal(t, pmetric.MetricTypeHistogram, latency.Type())
+	assert.Equal(t, "s", latency.Unit())
+	ldp := latency.Histogram().DataPoints().At(0)
+	assert.Equal(t, start, ldp.StartTimestamp())
+	assert.Equal(t, uint64(3), ldp.Count())
+	assert.Equal(t, 6.0, ldp.Sum())
+	assert.Equal(t, []float64{1, 2}, ldp.ExplicitBounds().AsRaw())
+	assert.Equal(t, []uint64{1, 0, 2}, ldp.BucketCounts().AsRaw())
+
+	size := gotMetrics["size"]
+	require.Equal(t, pmetric.MetricTypeExponentialHistogram, size.Type())
+	sdp := size.ExponentialHistogram().DataPoints().At(0)
+	assert.Equal(t, start, sdp.StartTimestamp())
+	assert.Equal(t, int32(2), sdp.Scale())
+	assert.Equal(t, uint64(6), sdp.Count())
+	assert.Equal(t, uint64(1), sdp.ZeroCount())
+	assert.Equal(t, 12.0, sdp.Sum())
+	assert.Equal(t, int32(3), sdp.Positive().Offset())
+	assert.Equal(t, []uint64{1, 0, 0, 0, 2}, sdp.Positive().BucketCounts().AsRaw())
+	assert.Equal(t, int32(-1), sdp.Negative().Offset())
+	assert.Equal(t, []uint64{2}, sdp.Negative().BucketCounts().AsRaw())
+}
+
+func TestToMetricsV2InvalidReferences(t *testing.T) {
+	req := &writev2.Request{
+		Symbols: []string{"", "__name__", "up"},
+		Timeseries: []writev2.TimeSeries{
+			{LabelsRefs: []uint32{1, 7}, Samples: []writev2.Sample{{Value: 1, Timestamp: 1}}},
+			{LabelsRefs: []uint32{1, 2}, Samples: []writev2.Sample{{Value: 1, Timestamp: 1}}},
+		},
+	}
+	md, stats, err := ToMetricsV2(req)
+	assert.Error(t, err)
+	assert.Equal(t, 1, md.MetricCount())
+	assert.Equal(t, WriteStats{Samples: 1}, stats)
+}
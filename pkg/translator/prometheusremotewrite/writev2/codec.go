@@ -0,0 +1,363 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package writev2 // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheusremotewrite/writev2"
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Field numbers of io.prometheus.write.v2 messages.
+const (
+	requestSymbolsField    = 4
+	requestTimeseriesField = 5
+
+	timeSeriesLabelsRefsField       = 1
+	timeSeriesSamplesField          = 2
+	timeSeriesHistogramsField       = 3
+	timeSeriesExemplarsField        = 4
+	timeSeriesMetadataField         = 5
+	timeSeriesCreatedTimestampField = 6
+
+	sampleValueField     = 1
+	sampleTimestampField = 2
+
+	exemplarLabelsRefsField = 1
+	exemplarValueField      = 2
+	exemplarTimestampField  = 3
+
+	metadataTypeField    = 1
+	metadataHelpRefField = 3
+	metadataUnitRefField = 4
+)
+
+// wireTypeError is returned by the consume functions in place of a protowire
+// error code when a field has an unexpected wire type.
+const wireTypeError = math.MinInt32
+
+var errInvalidWireType = errors.New("invalid wire type")
+
+// Marshal returns the protobuf encoding of the request.
+func (m *Request) Marshal() ([]byte, error) {
+	var b []byte
+	for _, s := range m.Symbols {
+		b = protowire.AppendTag(b, requestSymbolsField, protowire.BytesType)
+		b = protowire.AppendString(b, s)
+	}
+	var buf []byte
+	for i := range m.Timeseries {
+		var err error
+		buf, err = m.Timeseries[i].appendTo(buf[:0])
+		if err != nil {
+			return nil, err
+		}
+		b = protowire.AppendTag(b, requestTimeseriesField, protowire.BytesType)
+		b = protowire.AppendBytes(b, buf)
+	}
+	return b, nil
+}
+
+// Unmarshal decodes the protobuf encoding of a request into m.
+func (m *Request) Unmarshal(b []byte) error {
+	*m = Request{}
+	return consumeFields(b, func(num protowire.Number, typ protowire.Type, b []byte) (int, error) {
+		switch num {
+		case requestSymbolsField:
+			v, n := consumeBytes(typ, b)
+			if n >= 0 {
+				m.Symbols = append(m.Symbols, string(v))
+			}
+			return n, nil
+		case requestTimeseriesField:
+			v, n := consumeBytes(typ, b)
+			if n < 0 {
+				return n, nil
+			}
+			var ts TimeSeries
+			if err := ts.unmarshal(v); err != nil {
+				return 0, fmt.Errorf("invalid timeseries: %w", err)
+			}
+			m.Timeseries = append(m.Timeseries, ts)
+			return n, nil
+		}
+		return protowire.ConsumeFieldValue(num, typ, b), nil
+	})
+}
+
+// Size returns the size of the protobuf encoding of the series.
+func (m *TimeSeries) Size() int {
+	b, _ := m.appendTo(nil)
+	return len(b)
+}
+
+func (m *TimeSeries) appendTo(b []byte) ([]byte, error) {
+	b = appendPackedRefs(b, timeSeriesLabelsRefsField, m.LabelsRefs)
+	for _, s := range m.Samples {
+		b = protowire.AppendTag(b, timeSeriesSamplesField, protowire.BytesType)
+		b = protowire.AppendBytes(b, s.appendTo(nil))
+	}
+	for i := range m.Histograms {
+		h, err := m.Histograms[i].Marshal()
+		if err != nil {
+			return nil, err
+		}
+		b = protowire.AppendTag(b, timeSeriesHistogramsField, protowire.BytesType)
+		b = protowire.AppendBytes(b, h)
+	}
+	for i := range m.Exemplars {
+		b = protowire.AppendTag(b, timeSeriesExemplarsField, protowire.BytesType)
+		b = protowire.AppendBytes(b, m.Exemplars[i].appendTo(nil))
+	}
+	b = protowire.AppendTag(b, timeSeriesMetadataField, protowire.BytesType)
+	b = protowire.AppendBytes(b, m.Metadata.appendTo(nil))
+	if m.CreatedTimestamp != 0 {
+		b = protowire.AppendTag(b, timeSeriesCreatedTimestampField, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(m.CreatedTimestamp))
+	}
+	return b, nil
+}
+
+func (m *TimeSeries) unmarshal(b []byte) error {
+	return consumeFields(b, func(num protowire.Number, typ protowire.Type, b []byte) (int, error) {
+		switch num {
+		case timeSeriesLabelsRefsField:
+			var n int
+			m.LabelsRefs, n = consumeRefs(m.LabelsRefs, typ, b)
+			return n, nil
+		case timeSeriesSamplesField:
+			v, n := consumeBytes(typ, b)
+			if n < 0 {
+				return n, nil
+			}
+			var s Sample
+			if err := s.unmarshal(v); err != nil {
+				return 0, fmt.Errorf("invalid sample: %w", err)
+			}
+			m.Samples = append(m.Samples, s)
+			return n, nil
+		case timeSeriesHistogramsField:
+			v, n := consumeBytes(typ, b)
+			if n < 0 {
+				return n, nil
+			}
+			var h Histogram
+			if err := h.Unmarshal(v); err != nil {
+				return 0, fmt.Errorf("invalid histogram: %w", err)
+			}
+			m.Histograms = append(m.Histograms, h)
+			return n, nil
+		case timeSeriesExemplarsField:
+			v, n := consumeBytes(typ, b)
+			if n < 0 {
+				return n, nil
+			}
+			var e Exemplar
+			if err := e.unmarshal(v); err != nil {
+				return 0, fmt.Errorf("invalid exemplar: %w", err)
+			}
+			m.Exemplars = append(m.Exemplars, e)
+			return n, nil
+		case timeSeriesMetadataField:
+			v, n := consumeBytes(typ, b)
+			if n < 0 {
+				return n, nil
+			}
+			if err := m.Metadata.unmarshal(v); err != nil {
+				return 0, fmt.Errorf("invalid metadata: %w", err)
+			}
+			return n, nil
+		case timeSeriesCreatedTimestampField:
+			v, n := consumeVarint(typ, b)
+			m.CreatedTimestamp = int64(v)
+			return n, nil
+		}
+		return protowire.ConsumeFieldValue(num, typ, b), nil
+	})
+}
+
+func (m Sample) appendTo(b []byte) []byte {
+	if m.Value != 0 || math.Signbit(m.Value) {
+		b = protowire.AppendTag(b, sampleValueField, protowire.Fixed64Type)
+		b = protowire.AppendFixed64(b, math.Float64bits(m.Value))
+	}
+	if m.Timestamp != 0 {
+		b = protowire.AppendTag(b, sampleTimestampField, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(m.Timestamp))
+	}
+	return b
+}
+
+func (m *Sample) unmarshal(b []byte) error {
+	return consumeFields(b, func(num protowire.Number, typ protowire.Type, b []byte) (int, error) {
+		switch num {
+		case sampleValueField:
+			v, n := consumeFixed64(typ, b)
+			m.Value = math.Float64frombits(v)
+			return n, nil
+		case sampleTimestampField:
+			v, n := consumeVarint(typ, b)
+			m.Timestamp = int64(v)
+			return n, nil
+		}
+		return protowire.ConsumeFieldValue(num, typ, b), nil
+	})
+}
+
+func (m *Exemplar) appendTo(b []byte) []byte {
+	b = appendPackedRefs(b, exemplarLabelsRefsField, m.LabelsRefs)
+	if m.Value != 0 || math.Signbit(m.Value) {
+		b = protowire.AppendTag(b, exemplarValueField, protowire.Fixed64Type)
+		b = protowire.AppendFixed64(b, math.Float64bits(m.Value))
+	}
+	if m.Timestamp != 0 {
+		b = protowire.AppendTag(b, exemplarTimestampField, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(m.Timestamp))
+	}
+	return b
+}
+
+func (m *Exemplar) unmarshal(b []byte) error {
+	return consumeFields(b, func(num protowire.Number, typ protowire.Type, b []byte) (int, error) {
+		switch num {
+		case exemplarLabelsRefsField:
+			var n int
+			m.LabelsRefs, n = consumeRefs(m.LabelsRefs, typ, b)
+			return n, nil
+		case exemplarValueField:
+			v, n := consumeFixed64(typ, b)
+			m.Value = math.Float64frombits(v)
+			return n, nil
+		case exemplarTimestampField:
+			v, n := consumeVarint(typ, b)
+			m.Timestamp = int64(v)
+			return n, nil
+		}
+		return protowire.ConsumeFieldValue(num, typ, b), nil
+	})
+}
+
+func (m Metadata) appendTo(b []byte) []byte {
+	if m.Type != Metadata_METRIC_TYPE_UNSPECIFIED {
+		b = protowire.AppendTag(b, metadataTypeField, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(m.Type))
+	}
+	if m.HelpRef != 0 {
+		b = protowire.AppendTag(b, metadataHelpRefField, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(m.HelpRef))
+	}
+	if m.UnitRef != 0 {
+		b = protowire.AppendTag(b, metadataUnitRefField, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(m.UnitRef))
+	}
+	return b
+}
+
+func (m *Metadata) unmarshal(b []byte) error {
+	return consumeFields(b, func(num protowire.Number, typ protowire.Type, b []byte) (int, error) {
+		switch num {
+		case metadataTypeField:
+			v, n := consumeVarint(typ, b)
+			m.Type = Metadata_MetricType(v)
+			return n, nil
+		case metadataHelpRefField:
+			v, n := consumeVarint(typ, b)
+			m.HelpRef = uint32(v)
+			return n, nil
+		case metadataUnitRefField:
+			v, n := consumeVarint(typ, b)
+			m.UnitRef = uint32(v)
+			return n, nil
+		}
+		return protowire.ConsumeFieldValue(num, typ, b), nil
+	})
+}
+
+func appendPackedRefs(b []byte, num protowire.Number, refs []uint32) []byte {
+	if len(refs) == 0 {
+		return b
+	}
+	size := 0
+	for _, r := range refs {
+		size += protowire.SizeVarint(uint64(r))
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	b = protowire.AppendVarint(b, uint64(size))
+	for _, r := range refs {
+		b = protowire.AppendVarint(b, uint64(r))
+	}
+	return b
+}
+
+// consumeFields calls fn for every field in b. fn returns the number of bytes
+// of the field value it consumed, or a negative protowire error code.
+func consumeFields(b []byte, fn func(protowire.Number, protowire.Type, []byte) (int, error)) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+		n, err := fn(num, typ, b)
+		if err != nil {
+			return err
+		}
+		if n == wireTypeError {
+			return fmt.Errorf("field %d: %w", num, errInvalidWireType)
+		}
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+	}
+	return nil
+}
+
+// consumeRefs consumes a repeated uint32 field in either packed or unpacked
+// encoding and appends the values to refs.
+func consumeRefs(refs []uint32, typ protowire.Type, b []byte) ([]uint32, int) {
+	if typ == protowire.VarintType {
+		v, n := protowire.ConsumeVarint(b)
+		if n >= 0 {
+			refs = append(refs, uint32(v))
+		}
+		return refs, n
+	}
+	packed, n := consumeBytes(typ, b)
+	if n < 0 {
+		return refs, n
+	}
+	for len(packed) > 0 {
+		v, m := protowire.ConsumeVarint(packed)
+		if m < 0 {
+			return refs, m
+		}
+		refs = append(refs, uint32(v))
+		packed = packed[m:]
+	}
+	return refs, n
+}
+
+func consumeBytes(typ protowire.Type, b []byte) ([]byte, int) {
+	if typ != protowire.BytesType {
+		return nil, wireTypeError
+	}
+	return protowire.ConsumeBytes(b)
+}
+
+func consumeVarint(typ protowire.Type, b []byte) (uint64, int) {
+	if typ != protowire.VarintType {
+		return 0, wireTypeError
+	}
+	return protowire.ConsumeVarint(b)
+}
+
+func consumeFixed64(typ protowire.Type, b []byte) (uint64, int) {
+	if typ != protowire.Fixed64Type {
+		return 0, wireTypeError
+	}
+	return protowire.ConsumeFixed64(b)
+}
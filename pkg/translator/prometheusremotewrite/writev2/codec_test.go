@@ -0,0 +1,129 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package writev2
+
+import (
+	"math"
+	"testing"
+
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func TestRequestRoundTrip(t *testing.T) {
+	symbols := NewSymbolTable()
+	lbls := symbols.SymbolizeLabels([]prompb.Label{
+		{Name: "__name__", Value: "http_requests_total"},
+		{Name: "job", Value: "api"},
+	}, nil)
+	help := symbols.Symbolize("Number of requests")
+	unit := symbols.Symbolize("requests")
+	exemplarLabels := symbols.SymbolizeLabels([]prompb.Label{{Name: "trace_id", Value: "0102"}}, nil)
+
+	req := &Request{
+		Symbols: symbols.Symbols(),
+		Timeseries: []TimeSeries{
+			{
+				LabelsRefs: lbls,
+				Samples: []Sample{
+					{Value: 1, Timestamp: 1000},
+					{Value: 0, Timestamp: 2000},
+					{Value: math.Inf(1), Timestamp: -1},
+				},
+				Exemplars: []Exemplar{
+					{LabelsRefs: exemplarLabels, Value: 0.5, Timestamp: 1500},
+				},
+				Metadata: Metadata{
+					Type:    Metadata_METRIC_TYPE_COUNTER,
+					HelpRef: help,
+					UnitRef: unit,
+				},
+				CreatedTimestamp: 500,
+			},
+			{
+				LabelsRefs: lbls,
+				Histograms: []Histogram{{
+					Count:          &prompb.Histogram_CountInt{CountInt: 3},
+					Sum:            4.5,
+					Schema:         1,
+					ZeroThreshold:  1e-128,
+					ZeroCount:      &prompb.Histogram_ZeroCountInt{ZeroCountInt: 1},
+					PositiveSpans:  []prompb.BucketSpan{{Offset: 0, Length: 2}},
+					PositiveDeltas: []int64{1, 0},
+					Timestamp:      3000,
+				}},
+				Metadata: Metadata{Type: Metadata_METRIC_TYPE_HISTOGRAM},
+			},
+		},
+	}
+
+	b, err := req.Marshal()
+	require.NoError(t, err)
+
+	var got Request
+	require.NoError(t, got.Unmarshal(b))
+	assert.Equal(t, req.Symbols, got.Symbols)
+	require.Len(t, got.Timeseries, 2)
+	assert.Equal(t, req.Timeseries[0], got.Timeseries[0])
+	assert.Equal(t, req.Timeseries[1].Metadata, got.Timeseries[1].Metadata)
+	require.Len(t, got.Timeseries[1].Histograms, 1)
+	assert.Equal(t, req.Timeseries[1].Histograms[0].String(), got.Timeseries[1].Histograms[0].String())
+
+	gotLabels, err := DesymbolizeLabels(got.Timeseries[0].LabelsRefs, got.Symbols)
+	require.NoError(t, err)
+	assert.Equal(t, []prompb.Label{
+		{Name: "__name__", Value: "http_requests_total"},
+		{Name: "job", Value: "api"},
+	}, gotLabels)
+}
+
+func TestUnmarshalUnpackedRefsAndUnknownFields(t *testing.T) {
+	var ts []byte
+	ts = protowire.AppendTag(ts, timeSeriesLabelsRefsField, protowire.VarintType)
+	ts = protowire.AppendVarint(ts, 1)
+	ts = protowire.AppendTag(ts, timeSeriesLabelsRefsField, protowire.VarintType)
+	ts = protowire.AppendVarint(ts, 2)
+	ts = protowire.AppendTag(ts, 99, protowire.BytesType)
+	ts = protowire.AppendString(ts, "ignored")
+
+	var b []byte
+	b = protowire.AppendTag(b, requestSymbolsField, protowire.BytesType)
+	b = protowire.AppendString(b, "")
+	b = protowire.AppendTag(b, requestTimeseriesField, protowire.BytesType)
+	b = protowire.AppendBytes(b, ts)
+
+	var got Request
+	require.NoError(t, got.Unmarshal(b))
+	require.Len(t, got.Timeseries, 1)
+	assert.Equal(t, []uint32{1, 2}, got.Timeseries[0].LabelsRefs)
+}
+
+func TestUnmarshalInvalid(t *testing.T) {
+	var got Request
+	assert.Error(t, got.Unmarshal([]byte{0x2a, 0x05, 0x01}))
+
+	b := protowire.AppendTag(nil, requestTimeseriesField, protowire.VarintType)
+	b = protowire.AppendVarint(b, 1)
+	assert.ErrorIs(t, got.Unmarshal(b), errInvalidWireType)
+}
+
+func TestSymbolsTable(t *testing.T) {
+	symbols := NewSymbolTable()
+	assert.Equal(t, uint32(0), symbols.Symbolize(""))
+	assert.Equal(t, uint32(1), symbols.Symbolize("a"))
+	assert.Equal(t, uint32(2), symbols.Symbolize("b"))
+	assert.Equal(t, uint32(1), symbols.Symbolize("a"))
+	assert.Equal(t, []string{"", "a", "b"}, symbols.Symbols())
+
+	symbols.Reset()
+	assert.Equal(t, []string{""}, symbols.Symbols())
+	assert.Equal(t, uint32(1), symbols.Symbolize("b"))
+
+	_, err := DesymbolizeLabels([]uint32{1}, symbols.Symbols())
+	assert.Error(t, err)
+	_, err = DesymbolizeLabels([]uint32{1, 5}, symbols.Symbols())
+	assert.Error(t, err)
+}
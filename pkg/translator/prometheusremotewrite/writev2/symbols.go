@@ -0,0 +1,84 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package writev2 // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheusremotewrite/writev2"
+
+import (
+	"fmt"
+
+	"github.com/prometheus/prometheus/prompb"
+)
+
+// SymbolsTable interns the strings of a request.
+type SymbolsTable struct {
+	strings    []string
+	symbolsMap map[string]uint32
+}
+
+// NewSymbolTable returns a symbol table that only contains the empty string.
+func NewSymbolTable() SymbolsTable {
+	return SymbolsTable{
+		strings:    []string{""},
+		symbolsMap: map[string]uint32{"": 0},
+	}
+}
+
+// Symbolize adds str to the table if needed and returns its reference.
+func (t *SymbolsTable) Symbolize(str string) uint32 {
+	if ref, ok := t.symbolsMap[str]; ok {
+		return ref
+	}
+	ref := uint32(len(t.strings))
+	t.strings = append(t.strings, str)
+	t.symbolsMap[str] = ref
+	return ref
+}
+
+// SymbolizeLabels symbolizes the names and values of lbls and appends the
+// references to buf.
+func (t *SymbolsTable) SymbolizeLabels(lbls []prompb.Label, buf []uint32) []uint32 {
+	for _, l := range lbls {
+		buf = append(buf, t.Symbolize(l.Name), t.Symbolize(l.Value))
+	}
+	return buf
+}
+
+// Symbols returns the interned strings, to be used as Request.Symbols.
+func (t *SymbolsTable) Symbols() []string {
+	return t.strings
+}
+
+// Reset clears the table, keeping only the empty string.
+func (t *SymbolsTable) Reset() {
+	clear(t.symbolsMap)
+	t.strings = t.strings[:1]
+	t.symbolsMap[""] = 0
+}
+
+// DesymbolizeLabels resolves pairs of label references against symbols.
+func DesymbolizeLabels(labelRefs []uint32, symbols []string) ([]prompb.Label, error) {
+	if len(labelRefs)%2 != 0 {
+		return nil, fmt.Errorf("odd number of label references: %d", len(labelRefs))
+	}
+	lbls := make([]prompb.Label, 0, len(labelRefs)/2)
+	for i := 0; i < len(labelRefs); i += 2 {
+		name, err := Symbol(labelRefs[i], symbols)
+		if err != nil {
+			return nil, err
+		}
+		value, err := Symbol(labelRefs[i+1], symbols)
+		if err != nil {
+			return nil, err
+		}
+		lbls = append(lbls, prompb.Label{Name: name, Value: value})
+	}
+	return lbls, nil
+}
+
+// Symbol resolves a single reference against symbols.
+func Symbol(ref uint32, symbols []string) (string, error) {
+	if int(ref) >= len(symbols) {
+		return "", fmt.Errorf("symbol reference %d out of range, the request has %d symbols", ref, len(symbols))
+	}
+	return symbols[ref], nil
+}
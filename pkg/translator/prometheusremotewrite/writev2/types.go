@@ -0,0 +1,93 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package writev2 contains the Prometheus Remote Write 2.0 message types
+// (io.prometheus.write.v2.Request) and their protobuf encoding.
+//
+// The types follow the names used by the upstream Prometheus writev2 package
+// so they can be replaced by it once the Prometheus dependency provides them.
+package writev2 // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheusremotewrite/writev2"
+
+import "github.com/prometheus/prometheus/prompb"
+
+const (
+	// ContentType is the Content-Type header value of a Remote Write 2.0 request.
+	ContentType = "application/x-protobuf;proto=io.prometheus.write.v2.Request"
+	// Version is the X-Prometheus-Remote-Write-Version header value of a Remote Write 2.0 request.
+	Version = "2.0.0"
+
+	// SamplesWrittenHeader, HistogramsWrittenHeader and ExemplarsWrittenHeader are
+	// set by Remote Write 2.0 receivers on responses to report what was written.
+	SamplesWrittenHeader    = "X-Prometheus-Remote-Write-Samples-Written"
+	HistogramsWrittenHeader = "X-Prometheus-Remote-Write-Histograms-Written"
+	ExemplarsWrittenHeader  = "X-Prometheus-Remote-Write-Exemplars-Written"
+)
+
+// Request is a Remote Write 2.0 request. All strings of the request, such as
+// label names and values, are interned in Symbols and referenced by index.
+type Request struct {
+	// Symbols is the symbol table of the request. The first symbol is always
+	// the empty string.
+	Symbols    []string
+	Timeseries []TimeSeries
+}
+
+// TimeSeries is a single series of a Remote Write 2.0 request.
+type TimeSeries struct {
+	// LabelsRefs holds pairs of references into Request.Symbols, one pair of
+	// name and value reference per label.
+	LabelsRefs []uint32
+	Samples    []Sample
+	Histograms []Histogram
+	Exemplars  []Exemplar
+	Metadata   Metadata
+	// CreatedTimestamp is the time in milliseconds the series was created, or
+	// zero if unknown.
+	CreatedTimestamp int64
+}
+
+// Sample is a single float sample.
+type Sample struct {
+	Value float64
+	// Timestamp in milliseconds.
+	Timestamp int64
+}
+
+// Histogram is a native histogram sample. Fields 1 to 15 of the Remote Write
+// 2.0 histogram are wire compatible with the 1.0 histogram; custom bucket
+// values (field 16) are not supported and are kept as unrecognized bytes.
+type Histogram = prompb.Histogram
+
+// Exemplar is an exemplar attached to a series.
+type Exemplar struct {
+	// LabelsRefs holds pairs of references into Request.Symbols.
+	LabelsRefs []uint32
+	Value      float64
+	// Timestamp in milliseconds.
+	Timestamp int64
+}
+
+// Metadata_MetricType is the type of the metric a series belongs to.
+//
+//nolint:revive // Names match the generated upstream protobuf types.
+type Metadata_MetricType int32
+
+//nolint:revive // Names match the generated upstream protobuf types.
+const (
+	Metadata_METRIC_TYPE_UNSPECIFIED    Metadata_MetricType = 0
+	Metadata_METRIC_TYPE_COUNTER        Metadata_MetricType = 1
+	Metadata_METRIC_TYPE_GAUGE          Metadata_MetricType = 2
+	Metadata_METRIC_TYPE_HISTOGRAM      Metadata_MetricType = 3
+	Metadata_METRIC_TYPE_GAUGEHISTOGRAM Metadata_MetricType = 4
+	Metadata_METRIC_TYPE_SUMMARY        Metadata_MetricType = 5
+	Metadata_METRIC_TYPE_INFO           Metadata_MetricType = 6
+	Metadata_METRIC_TYPE_STATESET       Metadata_MetricType = 7
+)
+
+// Metadata describes the metric a series belongs to.
+type Metadata struct {
+	Type Metadata_MetricType
+	// HelpRef and UnitRef are references into Request.Symbols.
+	HelpRef uint32
+	UnitRef uint32
+}
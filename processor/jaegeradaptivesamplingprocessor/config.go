@@ -0,0 +1,17 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package jaegeradaptivesamplingprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/jaegeradaptivesamplingprocessor"
+
+import (
+	"go.opentelemetry.io/collector/component"
+)
+
+// Config defines the configuration for the Jaeger adaptive sampling processor.
+type Config struct {
+	// Extension is the jaegerremotesampling extension, configured with the
+	// `adaptive` source, that records the throughput of the traces.
+	Extension component.ID `mapstructure:"extension"`
+}
+
+var _ component.Config = (*Config)(nil)
@@ -0,0 +1,9 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:generate mdatagen metadata.yaml
+
+// Package jaegeradaptivesamplingprocessor records the throughput of the traces
+// sampled by Jaeger remote samplers, so that the jaegerremotesampling extension
+// can compute the adaptive sampling strategies.
+package jaegeradaptivesamplingprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/jaegeradaptivesamplingprocessor"
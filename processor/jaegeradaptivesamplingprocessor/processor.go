@@ -0,0 +1,42 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package jaegeradaptivesamplingprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/jaegeradaptivesamplingprocessor"
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/jaegerremotesampling"
+)
+
+type throughputProcessor struct {
+	config   *Config
+	recorder jaegerremotesampling.ThroughputRecorder
+}
+
+func newThroughputProcessor(cfg *Config) *throughputProcessor {
+	return &throughputProcessor{config: cfg}
+}
+
+func (p *throughputProcessor) start(_ context.Context, host component.Host) error {
+	ext, ok := host.GetExtensions()[p.config.Extension]
+	if !ok {
+		return fmt.Errorf("jaegerremotesampling extension '%s' not found", p.config.Extension)
+	}
+	recorder, ok := ext.(jaegerremotesampling.ThroughputRecorder)
+	if !ok {
+		return fmt.Errorf("non-jaegerremotesampling extension '%s' found", p.config.Extension)
+	}
+	p.recorder = recorder
+	return nil
+}
+
+// processTraces records the throughput of the traces and passes them on unchanged.
+func (p *throughputProcessor) processTraces(_ context.Context, td ptrace.Traces) (ptrace.Traces, error) {
+	p.recorder.RecordTraces(td)
+	return td, nil
+}
@@ -0,0 +1,139 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package jaegeradaptivesamplingprocessor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/extension"
+	"go.opentelemetry.io/collector/extension/extensiontest"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/processor/processortest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/jaegerremotesampling"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/testutil"
+)
+
+type extensionsHost struct {
+	component.Host
+	extensions map[component.ID]component.Component
+}
+
+func (h *extensionsHost) GetExtensions() map[component.ID]component.Component {
+	return h.extensions
+}
+
+func newExtensionsHost(id component.ID, ext extension.Extension) component.Host {
+	return &extensionsHost{
+		Host:       componenttest.NewNopHost(),
+		extensions: map[component.ID]component.Component{id: ext},
+	}
+}
+
+type strategyResponse struct {
+	OperationSampling struct {
+		DefaultSamplingProbability float64 `json:"defaultSamplingProbability"`
+		PerOperationStrategies     []struct {
+			Operation             string `json:"operation"`
+			ProbabilisticSampling struct {
+				SamplingRate float64 `json:"samplingRate"`
+			} `json:"probabilisticSampling"`
+		} `json:"perOperationStrategies"`
+	} `json:"operationSampling"`
+}
+
+func TestAdaptiveSampling(t *testing.T) {
+	endpoint := testutil.GetAvailableLocalAddress(t)
+	extFactory := jaegerremotesampling.NewFactory()
+	extCfg := extFactory.CreateDefaultConfig().(*jaegerremotesampling.Config)
+	extCfg.GRPCServerConfig = nil
+	extCfg.HTTPServerConfig.Endpoint = endpoint
+	extCfg.Source.Adaptive = &jaegerremotesampling.AdaptiveConfig{
+		TargetSamplesPerSecond:     1,
+		DeltaTolerance:             0.3,
+		CalculationInterval:        100 * time.Millisecond,
+		AggregationBuckets:         10,
+		BucketsForCalculation:      1,
+		InitialSamplingProbability: 0.001,
+		MinSamplingProbability:     0.00001,
+	}
+	ext, err := extFactory.CreateExtension(context.Background(), extensiontest.NewNopCreateSettings(), extCfg)
+	require.NoError(t, err)
+	extID := component.NewID(extFactory.Type())
+	host := newExtensionsHost(extID, ext)
+	require.NoError(t, ext.Start(context.Background(), host))
+	t.Cleanup(func() {
+		assert.NoError(t, ext.Shutdown(context.Background()))
+	})
+
+	sink := new(consumertest.TracesSink)
+	cfg := createDefaultConfig().(*Config)
+	p, err := NewFactory().CreateTracesProcessor(context.Background(), processortest.NewNopCreateSettings(), cfg, sink)
+	require.NoError(t, err)
+	require.NoError(t, p.Start(context.Background(), host))
+	t.Cleanup(func() {
+		assert.NoError(t, p.Shutdown(context.Background()))
+	})
+
+	td := ptrace.NewTraces()
+	rs := td.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().PutStr("service.name", "foo")
+	spans := rs.ScopeSpans().AppendEmpty().Spans()
+	for i := 0; i < 100; i++ {
+		span := spans.AppendEmpty()
+		span.SetName("op1")
+		span.Attributes().PutStr("sampler.type", "probabilistic")
+		span.Attributes().PutDouble("sampler.param", 0.001)
+	}
+
+	// The operation is sampled far above the target rate, so its probability is lowered
+	// once the recorded throughput is used.
+	assert.Eventually(t, func() bool {
+		require.NoError(t, p.ConsumeTraces(context.Background(), td))
+
+		resp, err := http.Get(fmt.Sprintf("http://%s/sampling?service=foo", endpoint))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var strategy strategyResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&strategy))
+		for _, op := range strategy.OperationSampling.PerOperationStrategies {
+			if op.Operation == "op1" {
+				return op.ProbabilisticSampling.SamplingRate < 0.001
+			}
+		}
+		return false
+	}, 10*time.Second, 50*time.Millisecond)
+
+	assert.NotZero(t, sink.SpanCount())
+	assert.Equal(t, spans.Len(), sink.AllTraces()[0].SpanCount())
+}
+
+func TestStartMissingExtension(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	p, err := NewFactory().CreateTracesProcessor(context.Background(), processortest.NewNopCreateSettings(), cfg, consumertest.NewNop())
+	require.NoError(t, err)
+	assert.EqualError(t, p.Start(context.Background(), componenttest.NewNopHost()), "jaegerremotesampling extension 'jaegerremotesampling' not found")
+}
+
+func TestStartNonJaegerExtension(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	p, err := NewFactory().CreateTracesProcessor(context.Background(), processortest.NewNopCreateSettings(), cfg, consumertest.NewNop())
+	require.NoError(t, err)
+	factory := extensiontest.NewNopFactory()
+	ext, err := factory.CreateExtension(context.Background(), extensiontest.NewNopCreateSettings(), factory.CreateDefaultConfig())
+	require.NoError(t, err)
+	host := newExtensionsHost(cfg.Extension, ext)
+	assert.EqualError(t, p.Start(context.Background(), host), "non-jaegerremotesampling extension 'jaegerremotesampling' found")
+}
@@ -0,0 +1,99 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ratelimitprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/ratelimitprocessor"
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+)
+
+// ThrottleMode determines what happens to data that exceeds the rate limit.
+type ThrottleMode string
+
+const (
+	// ThrottleModeDrop drops the items that exceed the rate limit and passes the rest on.
+	ThrottleModeDrop ThrottleMode = "drop"
+	// ThrottleModeError rejects the whole request with a retryable error so
+	// that the back-pressure reaches the client.
+	ThrottleModeError ThrottleMode = "error"
+)
+
+var (
+	errInvalidRate         = errors.New("rate must be greater than 0")
+	errInvalidBurst        = errors.New("burst cannot be less than 0")
+	errInvalidCardinality  = errors.New("key_cardinality_limit must be greater than 0")
+	errInvalidSyncInterval = errors.New("sync_interval must be greater than 0")
+)
+
+// Config defines the configuration for the rate limit processor.
+type Config struct {
+	// Rate is the number of items per second allowed for each key. Items are
+	// spans, log records or metric data points.
+	Rate float64 `mapstructure:"rate"`
+
+	// Burst is the number of items that can be accepted at once for each key.
+	// It defaults to the rate, rounded up.
+	Burst int `mapstructure:"burst"`
+
+	// Mode is either "drop" (default) or "error".
+	Mode ThrottleMode `mapstructure:"mode"`
+
+	// ResourceAttributes are the resource attributes that form the key.
+	ResourceAttributes []string `mapstructure:"resource_attributes"`
+
+	// Attributes are the span, log record or data point attributes that form the key.
+	Attributes []string `mapstructure:"attributes"`
+
+	// MetadataKeys are the client.Info metadata keys that form the key.
+	// Entries are case-insensitive.
+	MetadataKeys []string `mapstructure:"metadata_keys"`
+
+	// KeyCardinalityLimit is the maximum number of keys tracked at once. Keys
+	// seen once the limit is reached share a single bucket.
+	KeyCardinalityLimit int `mapstructure:"key_cardinality_limit"`
+
+	// StorageID is the storage extension used to discover the other replicas
+	// sharing the limit. When set, the rate and burst are divided evenly
+	// between the live replicas.
+	StorageID *component.ID `mapstructure:"storage"`
+
+	// SyncInterval is how often replicas announce themselves in the storage.
+	SyncInterval time.Duration `mapstructure:"sync_interval"`
+}
+
+var _ component.Config = (*Config)(nil)
+
+// Validate checks if the processor configuration is valid.
+func (cfg *Config) Validate() error {
+	if cfg.Rate <= 0 {
+		return errInvalidRate
+	}
+	if cfg.Burst < 0 {
+		return errInvalidBurst
+	}
+	switch cfg.Mode {
+	case ThrottleModeDrop, ThrottleModeError:
+	default:
+		return fmt.Errorf("unknown mode %q, must be %q or %q", cfg.Mode, ThrottleModeDrop, ThrottleModeError)
+	}
+	if cfg.KeyCardinalityLimit <= 0 {
+		return errInvalidCardinality
+	}
+	if cfg.StorageID != nil && cfg.SyncInterval <= 0 {
+		return errInvalidSyncInterval
+	}
+	uniq := map[string]bool{}
+	for _, k := range cfg.MetadataKeys {
+		l := strings.ToLower(k)
+		if uniq[l] {
+			return fmt.Errorf("duplicate entry in metadata_keys: %q (case-insensitive)", l)
+		}
+		uniq[l] = true
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ratelimitprocessor
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap/confmaptest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/ratelimitprocessor/internal/metadata"
+)
+
+func TestLoadConfig(t *testing.T) {
+	storageID := component.MustNewID("file_storage")
+
+	tests := []struct {
+		id          component.ID
+		expected    component.Config
+		expectedErr string
+	}{
+		{
+			id:       component.NewID(metadata.Type),
+			expected: createDefaultConfig(),
+		},
+		{
+			id: component.NewIDWithName(metadata.Type, "tenant"),
+			expected: &Config{
+				Rate:                500,
+				Burst:               1000,
+				Mode:                ThrottleModeError,
+				ResourceAttributes:  []string{"service.name"},
+				Attributes:          []string{"http.route"},
+				MetadataKeys:        []string{"x-tenant"},
+				KeyCardinalityLimit: 100,
+				StorageID:           &storageID,
+				SyncInterval:        5 * time.Second,
+			},
+		},
+		{
+			id:          component.NewIDWithName(metadata.Type, "invalidrate"),
+			expectedErr: errInvalidRate.Error(),
+		},
+		{
+			id:          component.NewIDWithName(metadata.Type, "invalidmode"),
+			expectedErr: `unknown mode "queue", must be "drop" or "error"`,
+		},
+		{
+			id:          component.NewIDWithName(metadata.Type, "duplicatemetadata"),
+			expectedErr: `duplicate entry in metadata_keys: "x-tenant" (case-insensitive)`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id.String(), func(t *testing.T) {
+			cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config.yaml"))
+			require.NoError(t, err)
+
+			factory := NewFactory()
+			cfg := factory.CreateDefaultConfig()
+			sub, err := cm.Sub(tt.id.String())
+			require.NoError(t, err)
+			require.NoError(t, component.UnmarshalConfig(sub, cfg))
+
+			if tt.expectedErr != "" {
+				assert.EqualError(t, component.ValidateConfig(cfg), tt.expectedErr)
+				return
+			}
+			assert.NoError(t, component.ValidateConfig(cfg))
+			assert.Equal(t, tt.expected, cfg)
+		})
+	}
+}
+
+func TestValidateConfig(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Burst = -1
+	assert.ErrorIs(t, cfg.Validate(), errInvalidBurst)
+
+	cfg = createDefaultConfig().(*Config)
+	cfg.KeyCardinalityLimit = 0
+	assert.ErrorIs(t, cfg.Validate(), errInvalidCardinality)
+
+	storageID := component.MustNewID("file_storage")
+	cfg = createDefaultConfig().(*Config)
+	cfg.StorageID = &storageID
+	cfg.SyncInterval = 0
+	assert.ErrorIs(t, cfg.Validate(), errInvalidSyncInterval)
+}
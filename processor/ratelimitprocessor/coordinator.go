@@ -0,0 +1,137 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ratelimitprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/ratelimitprocessor"
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+	"go.uber.org/zap"
+)
+
+const (
+	replicasStorageKey = "replicas"
+
+	// replicaExpiry is the number of sync intervals after which a replica
+	// that stopped announcing itself is no longer counted.
+	replicaExpiry = 3
+)
+
+// coordinator announces this replica in a shared storage and reports the
+// number of live replicas, so that each one enforces its share of the limit.
+//
+// The replica list is updated with a read-modify-write, so concurrent updates
+// can lose an announcement. A lost announcement is repaired on the next sync,
+// which makes the replica count eventually consistent.
+type coordinator struct {
+	client     storage.Client
+	id         string
+	interval   time.Duration
+	logger     *zap.Logger
+	onReplicas func(int)
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func newCoordinator(client storage.Client, interval time.Duration, logger *zap.Logger, onReplicas func(int)) *coordinator {
+	return &coordinator{
+		client:     client,
+		id:         uuid.NewString(),
+		interval:   interval,
+		logger:     logger,
+		onReplicas: onReplicas,
+	}
+}
+
+func (c *coordinator) start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
+
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		c.syncAndLog(ctx)
+
+		ticker := time.NewTicker(c.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				c.syncAndLog(ctx)
+			}
+		}
+	}()
+}
+
+func (c *coordinator) shutdown(ctx context.Context) error {
+	if c.cancel != nil {
+		c.cancel()
+		c.wg.Wait()
+	}
+
+	replicas, err := c.load(ctx)
+	if err == nil {
+		delete(replicas, c.id)
+		err = c.store(ctx, replicas)
+	}
+	if err != nil {
+		c.logger.Warn("Failed to remove the replica from the storage", zap.Error(err))
+	}
+	return c.client.Close(ctx)
+}
+
+func (c *coordinator) syncAndLog(ctx context.Context) {
+	if err := c.sync(ctx, time.Now()); err != nil {
+		c.logger.Warn("Failed to synchronize the rate limit with the other replicas", zap.Error(err))
+	}
+}
+
+func (c *coordinator) sync(ctx context.Context, now time.Time) error {
+	replicas, err := c.load(ctx)
+	if err != nil {
+		return err
+	}
+
+	replicas[c.id] = now
+	expiry := now.Add(-replicaExpiry * c.interval)
+	for id, lastSeen := range replicas {
+		if lastSeen.Before(expiry) {
+			delete(replicas, id)
+		}
+	}
+
+	if err := c.store(ctx, replicas); err != nil {
+		return err
+	}
+	c.onReplicas(len(replicas))
+	return nil
+}
+
+func (c *coordinator) load(ctx context.Context) (map[string]time.Time, error) {
+	replicas := make(map[string]time.Time)
+	data, err := c.client.Get(ctx, replicasStorageKey)
+	if err != nil || data == nil {
+		return replicas, err
+	}
+	if err := json.Unmarshal(data, &replicas); err != nil {
+		c.logger.Warn("Discarding the invalid replica list found in the storage", zap.Error(err))
+		return make(map[string]time.Time), nil
+	}
+	return replicas, nil
+}
+
+func (c *coordinator) store(ctx context.Context, replicas map[string]time.Time) error {
+	data, err := json.Marshal(replicas)
+	if err != nil {
+		return err
+	}
+	return c.client.Set(ctx, replicasStorageKey, data)
+}
@@ -0,0 +1,88 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ratelimitprocessor
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/storagetest"
+)
+
+func TestCoordinatorSync(t *testing.T) {
+	ctx := context.Background()
+	client := storagetest.NewInMemoryClient(component.KindProcessor, component.MustNewID("ratelimit"), "traces")
+
+	var replicas []int
+	onReplicas := func(n int) { replicas = append(replicas, n) }
+	first := newCoordinator(client, time.Second, zap.NewNop(), onReplicas)
+	second := newCoordinator(client, time.Second, zap.NewNop(), onReplicas)
+
+	now := time.Now()
+	require.NoError(t, first.sync(ctx, now))
+	require.NoError(t, second.sync(ctx, now))
+	require.NoError(t, first.sync(ctx, now.Add(time.Second)))
+	assert.Equal(t, []int{1, 2, 2}, replicas)
+
+	// The second replica stopped announcing itself and expires.
+	require.NoError(t, first.sync(ctx, now.Add(4*time.Second)))
+	assert.Equal(t, 1, replicas[len(replicas)-1])
+
+	data, err := client.Get(ctx, replicasStorageKey)
+	require.NoError(t, err)
+	var stored map[string]time.Time
+	require.NoError(t, json.Unmarshal(data, &stored))
+	assert.Contains(t, stored, first.id)
+	assert.NotContains(t, stored, second.id)
+}
+
+func TestCoordinatorInvalidData(t *testing.T) {
+	ctx := context.Background()
+	client := storagetest.NewInMemoryClient(component.KindProcessor, component.MustNewID("ratelimit"), "traces")
+	require.NoError(t, client.Set(ctx, replicasStorageKey, []byte("not json")))
+
+	var replicas int
+	c := newCoordinator(client, time.Second, zap.NewNop(), func(n int) { replicas = n })
+	require.NoError(t, c.sync(ctx, time.Now()))
+	assert.Equal(t, 1, replicas)
+}
+
+func TestCoordinatorShutdownRemovesReplica(t *testing.T) {
+	ctx := context.Background()
+	client := storagetest.NewInMemoryClient(component.KindProcessor, component.MustNewID("ratelimit"), "traces")
+	other := newCoordinator(client, time.Hour, zap.NewNop(), func(int) {})
+	require.NoError(t, other.sync(ctx, time.Now()))
+
+	replicas := make(chan int, 1)
+	c := newCoordinator(&unclosableClient{Client: client}, time.Hour, zap.NewNop(), func(n int) { replicas <- n })
+	c.start()
+	assert.Equal(t, 2, <-replicas)
+	require.NoError(t, c.shutdown(ctx))
+	assert.True(t, c.client.(*unclosableClient).closed)
+
+	stored, err := other.load(ctx)
+	require.NoError(t, err)
+	assert.Len(t, stored, 1)
+	assert.Contains(t, stored, other.id)
+}
+
+// unclosableClient lets a test inspect a client shared with a coordinator
+// that has been shut down.
+type unclosableClient struct {
+	storage.Client
+	closed bool
+}
+
+func (c *unclosableClient) Close(context.Context) error {
+	c.closed = true
+	return nil
+}
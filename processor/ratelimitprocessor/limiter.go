@@ -0,0 +1,157 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ratelimitprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/ratelimitprocessor"
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// overflowKey identifies the bucket shared by the keys seen once the
+// cardinality limit is reached.
+const overflowKey = "overflow"
+
+// bucketSet keeps one token bucket per key.
+type bucketSet struct {
+	mu       sync.Mutex
+	rate     float64
+	burst    int
+	replicas int
+	limit    int
+	buckets  map[string]*rate.Limiter
+	overflow *rate.Limiter
+}
+
+func newBucketSet(ratePerSecond float64, burst, cardinalityLimit int) *bucketSet {
+	if burst == 0 {
+		burst = int(math.Ceil(ratePerSecond))
+	}
+	s := &bucketSet{
+		rate:     ratePerSecond,
+		burst:    burst,
+		replicas: 1,
+		limit:    cardinalityLimit,
+		buckets:  make(map[string]*rate.Limiter),
+	}
+	s.overflow = s.newLimiter()
+	return s
+}
+
+func (s *bucketSet) newLimiter() *rate.Limiter {
+	return rate.NewLimiter(rate.Limit(s.rate/float64(s.replicas)), s.replicaBurst())
+}
+
+func (s *bucketSet) replicaBurst() int {
+	return max(1, int(math.Ceil(float64(s.burst)/float64(s.replicas))))
+}
+
+// get returns the bucket of key along with the key it is tracked under,
+// which is overflowKey when the cardinality limit has been reached.
+func (s *bucketSet) get(key string) (*rate.Limiter, string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if lim, ok := s.buckets[key]; ok {
+		return lim, key
+	}
+	if len(s.buckets) >= s.limit {
+		s.evictFull()
+	}
+	if len(s.buckets) >= s.limit {
+		return s.overflow, overflowKey
+	}
+	lim := s.newLimiter()
+	s.buckets[key] = lim
+	return lim, key
+}
+
+// evictFull removes the buckets that have refilled completely. They behave
+// exactly like new buckets, so removing them does not change any outcome.
+func (s *bucketSet) evictFull() {
+	now := time.Now()
+	for key, lim := range s.buckets {
+		if lim.TokensAt(now) >= float64(lim.Burst()) {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+// allow takes one token from the bucket of key.
+func (s *bucketSet) allow(key string) (string, bool) {
+	lim, key := s.get(key)
+	return key, lim.Allow()
+}
+
+// reserve takes the requested number of tokens from every bucket, or none at
+// all. It returns the number of items of the keys that could not be
+// satisfied, by the key they are tracked under. A key asking for more than the
+// burst is only satisfied when its bucket is full, and the tokens above the
+// burst are borrowed from the next refills, which the key has to wait for
+// before it is satisfied again.
+func (s *bucketSet) reserve(counts map[string]int) map[string]int {
+	now := time.Now()
+	reservations := make([]*rate.Reservation, 0, len(counts))
+	throttled := make(map[string]int)
+	for key, n := range counts {
+		lim, trackedKey := s.get(key)
+		if n > lim.Burst() && lim.TokensAt(now) < float64(lim.Burst()) {
+			throttled[trackedKey] += n
+			continue
+		}
+		var keyReservations []*rate.Reservation
+		for remaining := n; remaining > 0; remaining -= lim.Burst() {
+			keyReservations = append(keyReservations, lim.ReserveN(now, min(remaining, lim.Burst())))
+		}
+		if len(keyReservations) > 0 && keyReservations[0].DelayFrom(now) > 0 {
+			cancelReservations(now, keyReservations)
+			throttled[trackedKey] += n
+			continue
+		}
+		reservations = append(reservations, keyReservations...)
+	}
+	if len(throttled) > 0 {
+		cancelReservations(now, reservations)
+	}
+	return throttled
+}
+
+// cancelReservations cancels the reservations in the reverse order they were
+// made, so that the tokens of every reservation taken from the same bucket are
+// restored.
+func cancelReservations(now time.Time, reservations []*rate.Reservation) {
+	for i := len(reservations) - 1; i >= 0; i-- {
+		reservations[i].CancelAt(now)
+	}
+}
+
+// setReplicas divides the rate and burst of every bucket between the given
+// number of replicas.
+func (s *bucketSet) setReplicas(replicas int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	replicas = max(1, replicas)
+	if replicas == s.replicas {
+		return
+	}
+	s.replicas = replicas
+
+	now := time.Now()
+	limit, burst := rate.Limit(s.rate/float64(replicas)), s.replicaBurst()
+	for _, lim := range append(mapValues(s.buckets), s.overflow) {
+		lim.SetLimitAt(now, limit)
+		lim.SetBurstAt(now, burst)
+	}
+}
+
+func mapValues(m map[string]*rate.Limiter) []*rate.Limiter {
+	values := make([]*rate.Limiter, 0, len(m)+1)
+	for _, v := range m {
+		values = append(values, v)
+	}
+	return values
+}
@@ -0,0 +1,115 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ratelimitprocessor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBucketSetAllow(t *testing.T) {
+	s := newBucketSet(0.001, 2, 10)
+
+	for i := 0; i < 2; i++ {
+		key, ok := s.allow("tenant=acme")
+		assert.Equal(t, "tenant=acme", key)
+		assert.True(t, ok)
+	}
+	_, ok := s.allow("tenant=acme")
+	assert.False(t, ok)
+
+	_, ok = s.allow("tenant=globex")
+	assert.True(t, ok, "keys have independent buckets")
+}
+
+func TestBucketSetDefaultBurst(t *testing.T) {
+	s := newBucketSet(2.5, 0, 10)
+	assert.Equal(t, 3, s.burst)
+}
+
+func TestBucketSetCardinalityLimit(t *testing.T) {
+	s := newBucketSet(0.001, 1, 1)
+
+	key, ok := s.allow("tenant=acme")
+	assert.Equal(t, "tenant=acme", key)
+	assert.True(t, ok)
+
+	key, ok = s.allow("tenant=globex")
+	assert.Equal(t, overflowKey, key)
+	assert.True(t, ok)
+
+	key, ok = s.allow("tenant=initech")
+	assert.Equal(t, overflowKey, key)
+	assert.False(t, ok, "keys beyond the limit share the overflow bucket")
+}
+
+func TestBucketSetEvictsFullBuckets(t *testing.T) {
+	s := newBucketSet(0.001, 1, 1)
+
+	lim, key := s.get("tenant=acme")
+	assert.Equal(t, "tenant=acme", key)
+	assert.Equal(t, float64(1), lim.Tokens())
+
+	// The bucket of acme is still full, so it is evicted to make room for globex.
+	_, key = s.get("tenant=globex")
+	assert.Equal(t, "tenant=globex", key)
+	assert.NotContains(t, s.buckets, "tenant=acme")
+}
+
+func TestBucketSetReserve(t *testing.T) {
+	s := newBucketSet(0.001, 5, 10)
+
+	throttled := s.reserve(map[string]int{"tenant=acme": 3, "tenant=globex": 5})
+	assert.Empty(t, throttled)
+
+	// globex has no tokens left, so acme must not be charged for the rejected request.
+	throttled = s.reserve(map[string]int{"tenant=acme": 2, "tenant=globex": 1})
+	assert.Equal(t, map[string]int{"tenant=globex": 1}, throttled)
+
+	throttled = s.reserve(map[string]int{"tenant=acme": 2})
+	assert.Empty(t, throttled)
+
+	// Keys beyond the cardinality limit are throttled under the overflow key.
+	s = newBucketSet(0.001, 5, 1)
+	throttled = s.reserve(map[string]int{"tenant=acme": 5})
+	assert.Empty(t, throttled)
+	throttled = s.reserve(map[string]int{"tenant=globex": 3, "tenant=initech": 3})
+	assert.Equal(t, map[string]int{overflowKey: 3}, throttled)
+}
+
+func TestBucketSetReserveLargerThanBurst(t *testing.T) {
+	s := newBucketSet(0.001, 5, 10)
+
+	// A request larger than the burst is accepted when the bucket is full.
+	assert.Empty(t, s.reserve(map[string]int{"tenant=acme": 12}))
+	lim, _ := s.get("tenant=acme")
+	assert.InDelta(t, -7, lim.Tokens(), 0.1)
+
+	// The tokens above the burst are borrowed, so the key is throttled until they are paid back.
+	assert.Equal(t, map[string]int{"tenant=acme": 1}, s.reserve(map[string]int{"tenant=acme": 1}))
+
+	// A request larger than the burst is rejected while the bucket is not full, and
+	// the other keys of a rejected request are not charged.
+	assert.Empty(t, s.reserve(map[string]int{"tenant=globex": 1}))
+	assert.Equal(t, map[string]int{"tenant=globex": 6}, s.reserve(map[string]int{"tenant=globex": 6, "tenant=initech": 12}))
+	lim, _ = s.get("tenant=initech")
+	assert.InDelta(t, 5, lim.Tokens(), 0.1)
+}
+
+func TestBucketSetReplicas(t *testing.T) {
+	s := newBucketSet(100, 10, 10)
+	lim, _ := s.get("tenant=acme")
+
+	s.setReplicas(4)
+	assert.Equal(t, float64(25), float64(lim.Limit()))
+	assert.Equal(t, 3, lim.Burst())
+
+	newLim, _ := s.get("tenant=globex")
+	assert.Equal(t, float64(25), float64(newLim.Limit()))
+
+	s.setReplicas(0)
+	assert.Equal(t, float64(100), float64(lim.Limit()))
+	assert.Equal(t, 10, lim.Burst())
+}
@@ -0,0 +1,284 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ratelimitprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/ratelimitprocessor"
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.opentelemetry.io/collector/client"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/processor"
+	"go.opentelemetry.io/collector/processor/processorhelper"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/ratelimitprocessor/internal/metadata"
+)
+
+// keepFunc decides whether an item with the given key is kept.
+type keepFunc func(key string) bool
+
+type rateLimiter struct {
+	id          component.ID
+	config      *Config
+	signal      component.DataType
+	logger      *zap.Logger
+	buckets     *bucketSet
+	coordinator *coordinator
+	throttled   metric.Int64Counter
+}
+
+func newRateLimiter(set processor.CreateSettings, cfg *Config, signal component.DataType) (*rateLimiter, error) {
+	throttled, err := metadata.Meter(set.TelemetrySettings).Int64Counter(
+		"ratelimit_throttled_items",
+		metric.WithDescription("Number of items that exceeded the rate limit of their key"),
+		metric.WithUnit("1"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rateLimiter{
+		id:        set.ID,
+		config:    cfg,
+		signal:    signal,
+		logger:    set.Logger,
+		buckets:   newBucketSet(cfg.Rate, cfg.Burst, cfg.KeyCardinalityLimit),
+		throttled: throttled,
+	}, nil
+}
+
+func (r *rateLimiter) start(ctx context.Context, host component.Host) error {
+	if r.config.StorageID == nil {
+		return nil
+	}
+
+	ext, ok := host.GetExtensions()[*r.config.StorageID]
+	if !ok {
+		return fmt.Errorf("storage extension '%s' not found", r.config.StorageID)
+	}
+	storageExt, ok := ext.(storage.Extension)
+	if !ok {
+		return fmt.Errorf("non-storage extension '%s' found", r.config.StorageID)
+	}
+	client, err := storageExt.GetClient(ctx, component.KindProcessor, r.id, r.signal.String())
+	if err != nil {
+		return err
+	}
+
+	r.coordinator = newCoordinator(client, r.config.SyncInterval, r.logger, r.buckets.setReplicas)
+	r.coordinator.start()
+	return nil
+}
+
+func (r *rateLimiter) shutdown(ctx context.Context) error {
+	if r.coordinator == nil {
+		return nil
+	}
+	return r.coordinator.shutdown(ctx)
+}
+
+// limit returns the keepFunc for a request along with a function to call once
+// every item has been visited. In drop mode every item takes a token and is
+// dropped when none is left. In error mode the items are only counted, and
+// the request is rejected unless every key can take all of its items.
+func (r *rateLimiter) limit(ctx context.Context) (keepFunc, func() error) {
+	if r.config.Mode == ThrottleModeError {
+		counts := make(map[string]int)
+		keep := func(key string) bool {
+			counts[key]++
+			return true
+		}
+		return keep, func() error { return r.reserve(ctx, counts) }
+	}
+
+	throttled := make(map[string]int64)
+	keep := func(key string) bool {
+		trackedKey, ok := r.buckets.allow(key)
+		if !ok {
+			throttled[trackedKey]++
+		}
+		return ok
+	}
+	return keep, func() error {
+		for key, n := range throttled {
+			r.recordThrottled(ctx, key, n)
+		}
+		return nil
+	}
+}
+
+func (r *rateLimiter) reserve(ctx context.Context, counts map[string]int) error {
+	throttled := r.buckets.reserve(counts)
+	if len(throttled) == 0 {
+		return nil
+	}
+
+	throttledKeys := make([]string, 0, len(throttled))
+	for key, n := range throttled {
+		r.recordThrottled(ctx, key, int64(n))
+		throttledKeys = append(throttledKeys, key)
+	}
+	sort.Strings(throttledKeys)
+	return fmt.Errorf("rate limit exceeded for %s", strings.Join(throttledKeys, "; "))
+}
+
+func (r *rateLimiter) recordThrottled(ctx context.Context, key string, n int64) {
+	r.throttled.Add(ctx, n, metric.WithAttributes(
+		attribute.String("processor", r.id.String()),
+		attribute.String("signal", r.signal.String()),
+		attribute.String("key", key),
+	))
+}
+
+// keyBuilder builds the bucket key of an item from the client metadata, the
+// resource attributes and the item attributes, in that order.
+type keyBuilder struct {
+	config *Config
+	prefix string
+}
+
+func (r *rateLimiter) newKeyBuilder(ctx context.Context) keyBuilder {
+	var parts []string
+	if len(r.config.MetadataKeys) > 0 {
+		info := client.FromContext(ctx)
+		for _, k := range r.config.MetadataKeys {
+			parts = append(parts, k+"="+strings.Join(info.Metadata.Get(k), ";"))
+		}
+	}
+	return keyBuilder{config: r.config, prefix: strings.Join(parts, ",")}
+}
+
+func (b keyBuilder) withResource(attrs pcommon.Map) keyBuilder {
+	return keyBuilder{config: b.config, prefix: appendAttributes(b.prefix, b.config.ResourceAttributes, attrs)}
+}
+
+func (b keyBuilder) key(attrs pcommon.Map) string {
+	return appendAttributes(b.prefix, b.config.Attributes, attrs)
+}
+
+func appendAttributes(prefix string, names []string, attrs pcommon.Map) string {
+	if len(names) == 0 {
+		return prefix
+	}
+	var sb strings.Builder
+	sb.WriteString(prefix)
+	for _, name := range names {
+		if sb.Len() > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(name)
+		sb.WriteByte('=')
+		if v, ok := attrs.Get(name); ok {
+			sb.WriteString(v.AsString())
+		}
+	}
+	return sb.String()
+}
+
+func (r *rateLimiter) processTraces(ctx context.Context, td ptrace.Traces) (ptrace.Traces, error) {
+	keep, done := r.limit(ctx)
+	kb := r.newKeyBuilder(ctx)
+	td.ResourceSpans().RemoveIf(func(rs ptrace.ResourceSpans) bool {
+		rkb := kb.withResource(rs.Resource().Attributes())
+		rs.ScopeSpans().RemoveIf(func(ss ptrace.ScopeSpans) bool {
+			ss.Spans().RemoveIf(func(span ptrace.Span) bool {
+				return !keep(rkb.key(span.Attributes()))
+			})
+			return ss.Spans().Len() == 0
+		})
+		return rs.ScopeSpans().Len() == 0
+	})
+	if err := done(); err != nil {
+		return td, err
+	}
+	if td.ResourceSpans().Len() == 0 {
+		return td, processorhelper.ErrSkipProcessingData
+	}
+	return td, nil
+}
+
+func (r *rateLimiter) processLogs(ctx context.Context, ld plog.Logs) (plog.Logs, error) {
+	keep, done := r.limit(ctx)
+	kb := r.newKeyBuilder(ctx)
+	ld.ResourceLogs().RemoveIf(func(rl plog.ResourceLogs) bool {
+		rkb := kb.withResource(rl.Resource().Attributes())
+		rl.ScopeLogs().RemoveIf(func(sl plog.ScopeLogs) bool {
+			sl.LogRecords().RemoveIf(func(lr plog.LogRecord) bool {
+				return !keep(rkb.key(lr.Attributes()))
+			})
+			return sl.LogRecords().Len() == 0
+		})
+		return rl.ScopeLogs().Len() == 0
+	})
+	if err := done(); err != nil {
+		return ld, err
+	}
+	if ld.ResourceLogs().Len() == 0 {
+		return ld, processorhelper.ErrSkipProcessingData
+	}
+	return ld, nil
+}
+
+func (r *rateLimiter) processMetrics(ctx context.Context, md pmetric.Metrics) (pmetric.Metrics, error) {
+	keep, done := r.limit(ctx)
+	kb := r.newKeyBuilder(ctx)
+	md.ResourceMetrics().RemoveIf(func(rm pmetric.ResourceMetrics) bool {
+		rkb := kb.withResource(rm.Resource().Attributes())
+		rm.ScopeMetrics().RemoveIf(func(sm pmetric.ScopeMetrics) bool {
+			sm.Metrics().RemoveIf(func(m pmetric.Metric) bool {
+				return removeDataPoints(m, func(attrs pcommon.Map) bool {
+					return !keep(rkb.key(attrs))
+				})
+			})
+			return sm.Metrics().Len() == 0
+		})
+		return rm.ScopeMetrics().Len() == 0
+	})
+	if err := done(); err != nil {
+		return md, err
+	}
+	if md.ResourceMetrics().Len() == 0 {
+		return md, processorhelper.ErrSkipProcessingData
+	}
+	return md, nil
+}
+
+// removeDataPoints removes the data points for which remove returns true and
+// reports whether the metric is left without data points.
+func removeDataPoints(m pmetric.Metric, remove func(pcommon.Map) bool) bool {
+	switch m.Type() {
+	case pmetric.MetricTypeGauge:
+		dps := m.Gauge().DataPoints()
+		dps.RemoveIf(func(dp pmetric.NumberDataPoint) bool { return remove(dp.Attributes()) })
+		return dps.Len() == 0
+	case pmetric.MetricTypeSum:
+		dps := m.Sum().DataPoints()
+		dps.RemoveIf(func(dp pmetric.NumberDataPoint) bool { return remove(dp.Attributes()) })
+		return dps.Len() == 0
+	case pmetric.MetricTypeHistogram:
+		dps := m.Histogram().DataPoints()
+		dps.RemoveIf(func(dp pmetric.HistogramDataPoint) bool { return remove(dp.Attributes()) })
+		return dps.Len() == 0
+	case pmetric.MetricTypeExponentialHistogram:
+		dps := m.ExponentialHistogram().DataPoints()
+		dps.RemoveIf(func(dp pmetric.ExponentialHistogramDataPoint) bool { return remove(dp.Attributes()) })
+		return dps.Len() == 0
+	case pmetric.MetricTypeSummary:
+		dps := m.Summary().DataPoints()
+		dps.RemoveIf(func(dp pmetric.SummaryDataPoint) bool { return remove(dp.Attributes()) })
+		return dps.Len() == 0
+	default:
+		return false
+	}
+}
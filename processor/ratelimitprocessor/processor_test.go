@@ -0,0 +1,272 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ratelimitprocessor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/client"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/processor"
+	"go.opentelemetry.io/collector/processor/processortest"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/storagetest"
+)
+
+func newTestSettings() (processor.CreateSettings, *sdkmetric.ManualReader) {
+	reader := sdkmetric.NewManualReader()
+	set := processortest.NewNopCreateSettings()
+	set.MeterProvider = sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	return set, reader
+}
+
+// collectThrottled returns the number of throttled items reported by the processor, by key.
+func collectThrottled(t *testing.T, reader *sdkmetric.ManualReader) map[string]int64 {
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+
+	throttled := map[string]int64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			require.Equal(t, "ratelimit_throttled_items", m.Name)
+			for _, point := range m.Data.(metricdata.Sum[int64]).DataPoints {
+				key, _ := point.Attributes.Value("key")
+				throttled[key.AsString()] += point.Value
+			}
+		}
+	}
+	return throttled
+}
+
+// newTestConfig returns a config whose buckets effectively never refill
+// during a test.
+func newTestConfig(burst int) *Config {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Rate = 0.001
+	cfg.Burst = burst
+	return cfg
+}
+
+func generateTraces(spansPerService map[string]int) ptrace.Traces {
+	td := ptrace.NewTraces()
+	for service, n := range spansPerService {
+		rs := td.ResourceSpans().AppendEmpty()
+		rs.Resource().Attributes().PutStr("service.name", service)
+		spans := rs.ScopeSpans().AppendEmpty().Spans()
+		for i := 0; i < n; i++ {
+			spans.AppendEmpty().SetName("span")
+		}
+	}
+	return td
+}
+
+func TestDropTraces(t *testing.T) {
+	cfg := newTestConfig(2)
+	cfg.ResourceAttributes = []string{"service.name"}
+	set, reader := newTestSettings()
+	sink := new(consumertest.TracesSink)
+
+	tp, err := NewFactory().CreateTracesProcessor(context.Background(), set, cfg, sink)
+	require.NoError(t, err)
+	require.NoError(t, tp.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { require.NoError(t, tp.Shutdown(context.Background())) }()
+
+	require.NoError(t, tp.ConsumeTraces(context.Background(), generateTraces(map[string]int{"checkout": 3, "cart": 1})))
+	require.Len(t, sink.AllTraces(), 1)
+	assert.Equal(t, 3, sink.SpanCount())
+
+	// Only cart has a token left, so everything else is dropped.
+	require.NoError(t, tp.ConsumeTraces(context.Background(), generateTraces(map[string]int{"checkout": 2, "cart": 2})))
+	require.Len(t, sink.AllTraces(), 2)
+	rss := sink.AllTraces()[1].ResourceSpans()
+	require.Equal(t, 1, rss.Len())
+	service, _ := rss.At(0).Resource().Attributes().Get("service.name")
+	assert.Equal(t, "cart", service.Str())
+	assert.Equal(t, 1, rss.At(0).ScopeSpans().At(0).Spans().Len())
+
+	// Nothing is forwarded once every item is dropped.
+	require.NoError(t, tp.ConsumeTraces(context.Background(), generateTraces(map[string]int{"checkout": 1})))
+	assert.Len(t, sink.AllTraces(), 2)
+
+	assert.Equal(t, map[string]int64{
+		"service.name=checkout": 4,
+		"service.name=cart":     1,
+	}, collectThrottled(t, reader))
+}
+
+func generateLogs(n int) plog.Logs {
+	ld := plog.NewLogs()
+	records := ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords()
+	for i := 0; i < n; i++ {
+		records.AppendEmpty().Body().SetStr("log")
+	}
+	return ld
+}
+
+func TestErrorModeLogs(t *testing.T) {
+	cfg := newTestConfig(5)
+	cfg.Mode = ThrottleModeError
+	cfg.MetadataKeys = []string{"x-tenant"}
+	set, reader := newTestSettings()
+	sink := new(consumertest.LogsSink)
+
+	lp, err := NewFactory().CreateLogsProcessor(context.Background(), set, cfg, sink)
+	require.NoError(t, err)
+	require.NoError(t, lp.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { require.NoError(t, lp.Shutdown(context.Background())) }()
+
+	acme := client.NewContext(context.Background(), client.Info{
+		Metadata: client.NewMetadata(map[string][]string{"x-tenant": {"acme"}}),
+	})
+	globex := client.NewContext(context.Background(), client.Info{
+		Metadata: client.NewMetadata(map[string][]string{"x-tenant": {"globex"}}),
+	})
+
+	require.NoError(t, lp.ConsumeLogs(acme, generateLogs(4)))
+
+	// The whole request is rejected with a retryable error instead of being partially dropped.
+	err = lp.ConsumeLogs(acme, generateLogs(2))
+	require.EqualError(t, err, "rate limit exceeded for x-tenant=acme")
+	assert.False(t, consumererror.IsPermanent(err))
+
+	require.NoError(t, lp.ConsumeLogs(acme, generateLogs(1)))
+	require.NoError(t, lp.ConsumeLogs(globex, generateLogs(5)))
+
+	// A request larger than the burst is only accepted when the bucket is full, and the
+	// items above the burst are paid back before the key accepts more items.
+	err = lp.ConsumeLogs(globex, generateLogs(6))
+	require.EqualError(t, err, "rate limit exceeded for x-tenant=globex")
+	assert.False(t, consumererror.IsPermanent(err))
+	initech := client.NewContext(context.Background(), client.Info{
+		Metadata: client.NewMetadata(map[string][]string{"x-tenant": {"initech"}}),
+	})
+	require.NoError(t, lp.ConsumeLogs(initech, generateLogs(6)))
+	require.EqualError(t, lp.ConsumeLogs(initech, generateLogs(1)), "rate limit exceeded for x-tenant=initech")
+
+	assert.Equal(t, 16, sink.LogRecordCount())
+	assert.Equal(t, map[string]int64{
+		"x-tenant=acme":    2,
+		"x-tenant=globex":  6,
+		"x-tenant=initech": 1,
+	}, collectThrottled(t, reader))
+}
+
+func TestErrorModeRecordsThrottledKeys(t *testing.T) {
+	cfg := newTestConfig(2)
+	cfg.Mode = ThrottleModeError
+	cfg.ResourceAttributes = []string{"service.name"}
+	cfg.KeyCardinalityLimit = 2
+	set, reader := newTestSettings()
+	sink := new(consumertest.TracesSink)
+
+	tp, err := NewFactory().CreateTracesProcessor(context.Background(), set, cfg, sink)
+	require.NoError(t, err)
+	require.NoError(t, tp.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { require.NoError(t, tp.Shutdown(context.Background())) }()
+
+	// Only the items of checkout are throttled, even though the whole request is rejected.
+	require.NoError(t, tp.ConsumeTraces(context.Background(), generateTraces(map[string]int{"checkout": 2})))
+	err = tp.ConsumeTraces(context.Background(), generateTraces(map[string]int{"checkout": 1, "cart": 1}))
+	require.Error(t, err)
+	assert.Equal(t, 2, sink.SpanCount())
+	assert.Equal(t, map[string]int64{"service.name=checkout": 1}, collectThrottled(t, reader))
+
+	// Keys beyond the cardinality limit are recorded under the overflow key.
+	require.NoError(t, tp.ConsumeTraces(context.Background(), generateTraces(map[string]int{"cart": 2})))
+	require.NoError(t, tp.ConsumeTraces(context.Background(), generateTraces(map[string]int{"shipping": 1})))
+	err = tp.ConsumeTraces(context.Background(), generateTraces(map[string]int{"payment": 2}))
+	require.EqualError(t, err, "rate limit exceeded for overflow")
+	assert.Equal(t, map[string]int64{
+		"service.name=checkout": 1,
+		overflowKey:             2,
+	}, collectThrottled(t, reader))
+}
+
+func TestDropMetricDataPoints(t *testing.T) {
+	cfg := newTestConfig(1)
+	cfg.Attributes = []string{"tenant"}
+	set, reader := newTestSettings()
+	sink := new(consumertest.MetricsSink)
+
+	mp, err := NewFactory().CreateMetricsProcessor(context.Background(), set, cfg, sink)
+	require.NoError(t, err)
+	require.NoError(t, mp.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { require.NoError(t, mp.Shutdown(context.Background())) }()
+
+	md := pmetric.NewMetrics()
+	metrics := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics()
+	gauge := metrics.AppendEmpty().SetEmptyGauge()
+	gauge.DataPoints().AppendEmpty().Attributes().PutStr("tenant", "acme")
+	gauge.DataPoints().AppendEmpty().Attributes().PutStr("tenant", "globex")
+	histogram := metrics.AppendEmpty().SetEmptyHistogram()
+	histogram.DataPoints().AppendEmpty().Attributes().PutStr("tenant", "acme")
+	summary := metrics.AppendEmpty().SetEmptySummary()
+	summary.DataPoints().AppendEmpty().Attributes().PutStr("tenant", "initech")
+
+	require.NoError(t, mp.ConsumeMetrics(context.Background(), md))
+	require.Len(t, sink.AllMetrics(), 1)
+	assert.Equal(t, 3, sink.DataPointCount())
+
+	// The histogram lost its only data point and was removed.
+	assert.Equal(t, 2, sink.AllMetrics()[0].MetricCount())
+	assert.Equal(t, map[string]int64{"tenant=acme": 1}, collectThrottled(t, reader))
+}
+
+func TestStorageCoordination(t *testing.T) {
+	storageID := storagetest.NewStorageID("storage")
+	cfg := createDefaultConfig().(*Config)
+	cfg.StorageID = &storageID
+	set, _ := newTestSettings()
+
+	tp, err := NewFactory().CreateTracesProcessor(context.Background(), set, cfg, consumertest.NewNop())
+	require.NoError(t, err)
+
+	host := storagetest.NewStorageHost().WithInMemoryStorageExtension("storage")
+	require.NoError(t, tp.Start(context.Background(), host))
+	require.NoError(t, tp.Shutdown(context.Background()))
+}
+
+func TestStorageErrors(t *testing.T) {
+	for _, tt := range []struct {
+		name        string
+		storageID   component.ID
+		host        component.Host
+		expectedErr string
+	}{
+		{
+			name:        "missing",
+			storageID:   storagetest.NewStorageID("storage"),
+			host:        storagetest.NewStorageHost(),
+			expectedErr: "storage extension 'test_storage/storage' not found",
+		},
+		{
+			name:        "not a storage",
+			storageID:   storagetest.NewNonStorageID("storage"),
+			host:        storagetest.NewStorageHost().WithNonStorageExtension("storage"),
+			expectedErr: "non-storage extension 'non_storage/storage' found",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.StorageID = &tt.storageID
+			set, _ := newTestSettings()
+
+			tp, err := NewFactory().CreateTracesProcessor(context.Background(), set, cfg, consumertest.NewNop())
+			require.NoError(t, err)
+			assert.EqualError(t, tp.Start(context.Background(), tt.host), tt.expectedErr)
+			require.NoError(t, tp.Shutdown(context.Background()))
+		})
+	}
+}
@@ -5,47 +5,48 @@ package remotetapprocessor // import "github.com/open-telemetry/opentelemetry-co
 
 import "sync"
 
-// channelSet is a collection of byte channels where adding, removing, and writing to
-// the channels is synchronized.
+// channelSet is a collection of subscribers where adding, removing, and iterating over
+// the subscribers is synchronized.
 type channelSet struct {
 	i       int
 	mu      sync.RWMutex
-	chanmap map[int]chan []byte
+	chanmap map[int]*subscriber
 }
 
 func newChannelSet() *channelSet {
 	return &channelSet{
-		chanmap: map[int]chan []byte{},
+		chanmap: map[int]*subscriber{},
 	}
 }
 
-// add adds the channel to the channelSet and returns a key (just an int) used to
-// remove the channel later.
-func (c *channelSet) add(ch chan []byte) int {
+// add adds the subscriber to the channelSet and returns a key (just an int) used to
+// remove the subscriber later.
+func (c *channelSet) add(s *subscriber) int {
 	c.mu.Lock()
 	idx := c.i
-	c.chanmap[idx] = ch
+	c.chanmap[idx] = s
 	c.i++
 	c.mu.Unlock()
 	return idx
 }
 
-// writeBytes writes the passed in bytes to all of the channels in the
-// channelSet.
-func (c *channelSet) writeBytes(bytes []byte) {
+// forEach calls fn with each of the subscribers in the channelSet.
+func (c *channelSet) forEach(fn func(s *subscriber)) {
 	c.mu.RLock()
-	for _, ch := range c.chanmap {
-		ch <- bytes
+	for _, s := range c.chanmap {
+		fn(s)
 	}
 	c.mu.RUnlock()
 }
 
-// closeAndRemove closes then removes the channel associated with the passed in
-// key. Panics if an invalid key is passed in.
+// closeAndRemove closes the channel of the subscriber associated with the passed in
+// key, then removes it. Removing a subscriber that was already removed is a no-op.
 func (c *channelSet) closeAndRemove(key int) {
 	c.mu.Lock()
-	close(c.chanmap[key])
-	delete(c.chanmap, key)
+	if s, ok := c.chanmap[key]; ok {
+		close(s.ch)
+		delete(c.chanmap, key)
+	}
 	c.mu.Unlock()
 }
 
@@ -53,17 +54,8 @@ func (c *channelSet) shutdown() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	// Get all keys before deletion, so the map is not
-	// being modified while iterating.
-	keys := make([]int, len(c.chanmap))
-	i := 0
-	for key := range c.chanmap {
-		keys[i] = key
-		i++
-	}
-
-	for key := range keys {
-		close(c.chanmap[key])
+	for key, s := range c.chanmap {
+		close(s.ch)
 		delete(c.chanmap, key)
 	}
 }
@@ -5,20 +5,40 @@ package remotetapprocessor
 
 import (
 	"testing"
-	"time"
 
 	"github.com/stretchr/testify/assert"
 )
 
 func TestChannelset(t *testing.T) {
 	cs := newChannelSet()
-	ch := make(chan []byte)
-	key := cs.add(ch)
-	go func() {
-		cs.writeBytes([]byte("hello"))
-	}()
-	assert.Eventually(t, func() bool {
-		return assert.Equal(t, []byte("hello"), <-ch)
-	}, time.Second, time.Millisecond*10)
+	s := &subscriber{ch: make(chan []byte, 1)}
+	key := cs.add(s)
+	cs.forEach(func(s *subscriber) {
+		s.send([]byte("hello"))
+	})
+	assert.Equal(t, []byte("hello"), <-s.ch)
 	cs.closeAndRemove(key)
+	_, ok := <-s.ch
+	assert.False(t, ok)
+
+	// removing a subscriber twice is a no-op
+	cs.closeAndRemove(key)
+}
+
+func TestChannelsetShutdown(t *testing.T) {
+	cs := newChannelSet()
+	first := &subscriber{ch: make(chan []byte, 1)}
+	second := &subscriber{ch: make(chan []byte, 1)}
+	cs.add(first)
+	cs.add(second)
+
+	cs.shutdown()
+
+	_, ok := <-first.ch
+	assert.False(t, ok)
+	_, ok = <-second.ch
+	assert.False(t, ok)
+	cs.forEach(func(*subscriber) {
+		assert.Fail(t, "no subscriber expected after shutdown")
+	})
 }
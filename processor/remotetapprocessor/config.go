@@ -4,6 +4,8 @@
 package remotetapprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/remotetapprocessor"
 
 import (
+	"errors"
+
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/confighttp"
 	"golang.org/x/time/rate"
@@ -17,7 +19,8 @@ type Config struct {
 	confighttp.ServerConfig `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct
 
 	// Limit is a float that indicates the maximum number of messages repeated
-	// through the websocket by this processor in messages per second. Defaults to 1.
+	// through the websocket to each client in messages per second. Clients can
+	// lower their own limit when connecting. Defaults to 1.
 	Limit rate.Limit `mapstructure:"limit"`
 }
 
@@ -29,3 +32,10 @@ func createDefaultConfig() component.Config {
 		Limit: 1,
 	}
 }
+
+func (cfg *Config) Validate() error {
+	if cfg.Limit <= 0 {
+		return errors.New("limit must be greater than 0")
+	}
+	return nil
+}
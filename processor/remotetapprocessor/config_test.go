@@ -14,3 +14,10 @@ func TestCreateDefaultConfig(t *testing.T) {
 	assert.Equal(t, "0.0.0.0:12001", cfg.Endpoint)
 	assert.EqualValues(t, 1, cfg.Limit)
 }
+
+func TestValidateConfig(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	assert.NoError(t, cfg.Validate())
+	cfg.Limit = 0
+	assert.EqualError(t, cfg.Validate(), "limit must be greater than 0")
+}
@@ -48,7 +48,7 @@ func (w *wsprocessor) Start(ctx context.Context, host component.Host) error {
 	if err != nil {
 		return fmt.Errorf("failed to bind to address %s: %w", w.config.Endpoint, err)
 	}
-	w.server, err = w.config.ServerConfig.ToServer(ctx, host, w.telemetrySettings, websocket.Handler(w.handleConn))
+	w.server, err = w.config.ServerConfig.ToServer(ctx, host, w.telemetrySettings, http.HandlerFunc(w.handleSubscribe))
 	if err != nil {
 		return err
 	}
@@ -62,15 +62,27 @@ func (w *wsprocessor) Start(ctx context.Context, host component.Host) error {
 	return nil
 }
 
-func (w *wsprocessor) handleConn(conn *websocket.Conn) {
+// handleSubscribe upgrades the request to a websocket connection, subscribing the client
+// with the filter and rate limit of the query parameters.
+func (w *wsprocessor) handleSubscribe(rw http.ResponseWriter, r *http.Request) {
+	s, err := newSubscriber(r.URL.Query(), w.config, w.telemetrySettings)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	websocket.Handler(func(conn *websocket.Conn) {
+		w.handleConn(conn, s)
+	}).ServeHTTP(rw, r)
+}
+
+func (w *wsprocessor) handleConn(conn *websocket.Conn, s *subscriber) {
 	err := conn.SetDeadline(time.Time{})
 	if err != nil {
 		w.telemetrySettings.Logger.Debug("Error setting deadline", zap.Error(err))
 		return
 	}
-	ch := make(chan []byte)
-	idx := w.cs.add(ch)
-	for bytes := range ch {
+	idx := w.cs.add(s)
+	for bytes := range s.ch {
 		_, err := conn.Write(bytes)
 		if err != nil {
 			w.telemetrySettings.Logger.Debug("websocket write error: %w", zap.Error(err))
@@ -97,32 +109,56 @@ func (w *wsprocessor) Shutdown(ctx context.Context) error {
 	return err
 }
 
-func (w *wsprocessor) ConsumeMetrics(_ context.Context, md pmetric.Metrics) (pmetric.Metrics, error) {
-	b, err := metricMarshaler.MarshalMetrics(md)
-	if err != nil {
-		w.telemetrySettings.Logger.Debug("Error serializing to JSON", zap.Error(err))
-	} else {
-		w.cs.writeBytes(b)
-	}
+func (w *wsprocessor) ConsumeMetrics(ctx context.Context, md pmetric.Metrics) (pmetric.Metrics, error) {
+	publish(w, component.DataTypeMetrics, func(s *subscriber) (pmetric.Metrics, bool, bool) {
+		filtered, ok := s.filterMetrics(ctx, md)
+		return filtered, ok, s.metricCondition == nil
+	}, metricMarshaler.MarshalMetrics)
 	return md, nil
 }
 
-func (w *wsprocessor) ConsumeLogs(_ context.Context, ld plog.Logs) (plog.Logs, error) {
-	b, err := logMarshaler.MarshalLogs(ld)
-	if err != nil {
-		w.telemetrySettings.Logger.Debug("Error serializing to JSON", zap.Error(err))
-	} else {
-		w.cs.writeBytes(b)
-	}
+func (w *wsprocessor) ConsumeLogs(ctx context.Context, ld plog.Logs) (plog.Logs, error) {
+	publish(w, component.DataTypeLogs, func(s *subscriber) (plog.Logs, bool, bool) {
+		filtered, ok := s.filterLogs(ctx, ld)
+		return filtered, ok, s.logCondition == nil
+	}, logMarshaler.MarshalLogs)
 	return ld, nil
 }
 
-func (w *wsprocessor) ConsumeTraces(_ context.Context, td ptrace.Traces) (ptrace.Traces, error) {
-	b, err := traceMarshaler.MarshalTraces(td)
-	if err != nil {
-		w.telemetrySettings.Logger.Debug("Error serializing to JSON", zap.Error(err))
-	} else {
-		w.cs.writeBytes(b)
-	}
+func (w *wsprocessor) ConsumeTraces(ctx context.Context, td ptrace.Traces) (ptrace.Traces, error) {
+	publish(w, component.DataTypeTraces, func(s *subscriber) (ptrace.Traces, bool, bool) {
+		filtered, ok := s.filterTraces(ctx, td)
+		return filtered, ok, s.spanCondition == nil
+	}, traceMarshaler.MarshalTraces)
 	return td, nil
 }
+
+// publish sends the data of the signal to the subscribers, as returned by filter with whether
+// there is any data to send and whether it is the unfiltered data, which is only serialized once.
+func publish[T any](w *wsprocessor, signal component.DataType, filter func(s *subscriber) (T, bool, bool), marshal func(T) ([]byte, error)) {
+	var unfiltered []byte
+	w.cs.forEach(func(s *subscriber) {
+		if !s.subscribed(signal) {
+			return
+		}
+		data, ok, isUnfiltered := filter(s)
+		if !ok || !s.limiter.Allow() {
+			return
+		}
+
+		b := unfiltered
+		if !isUnfiltered || b == nil {
+			var err error
+			if b, err = marshal(data); err != nil {
+				w.telemetrySettings.Logger.Debug("Error serializing to JSON", zap.Error(err))
+				return
+			}
+			if isUnfiltered {
+				unfiltered = b
+			}
+		}
+		if !s.send(b) {
+			w.telemetrySettings.Logger.Debug("Dropping data for a websocket client not keeping up", zap.String("signal", signal.String()))
+		}
+	})
+}
@@ -5,10 +5,14 @@ package remotetapprocessor
 
 import (
 	"context"
+	"io"
 	"net"
+	"net/http"
+	"net/url"
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config/confighttp"
@@ -25,6 +29,7 @@ func TestSocketConnectionLogs(t *testing.T) {
 		ServerConfig: confighttp.ServerConfig{
 			Endpoint: "localhost:12001",
 		},
+		Limit: 1,
 	}
 	logSink := &consumertest.LogsSink{}
 	processor, err := NewFactory().CreateLogsProcessor(context.Background(), processortest.NewNopCreateSettings(), cfg,
@@ -62,6 +67,7 @@ func TestSocketConnectionMetrics(t *testing.T) {
 		ServerConfig: confighttp.ServerConfig{
 			Endpoint: "localhost:12002",
 		},
+		Limit: 1,
 	}
 	metricsSink := &consumertest.MetricsSink{}
 	processor, err := NewFactory().CreateMetricsProcessor(context.Background(), processortest.NewNopCreateSettings(), cfg,
@@ -97,6 +103,7 @@ func TestSocketConnectionTraces(t *testing.T) {
 		ServerConfig: confighttp.ServerConfig{
 			Endpoint: "localhost:12003",
 		},
+		Limit: 1,
 	}
 	tracesSink := &consumertest.TracesSink{}
 	processor, err := NewFactory().CreateTracesProcessor(context.Background(), processortest.NewNopCreateSettings(), cfg,
@@ -126,3 +133,84 @@ func TestSocketConnectionTraces(t *testing.T) {
 	err = rawConn.Close()
 	require.NoError(t, err)
 }
+
+func TestSocketConnectionFiltered(t *testing.T) {
+	cfg := &Config{
+		ServerConfig: confighttp.ServerConfig{
+			Endpoint: "localhost:12004",
+		},
+		Limit: 100,
+	}
+	processor := newProcessor(processortest.NewNopCreateSettings(), cfg)
+	require.NoError(t, processor.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() {
+		require.NoError(t, processor.Shutdown(context.Background()))
+	}()
+
+	query := url.Values{signalParam: {"traces"}, conditionParam: {`name == "bar"`, `attributes["http.status_code"] == 500`}}
+	wsConn, err := websocket.Dial("ws://localhost:12004/?"+query.Encode(), "", "http://localhost:12004")
+	require.NoError(t, err)
+	defer wsConn.Close()
+
+	trace := ptrace.NewTraces()
+	spans := trace.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans()
+	spans.AppendEmpty().SetName("foo")
+	spans.AppendEmpty().SetName("bar")
+	failed := spans.AppendEmpty()
+	failed.SetName("baz")
+	failed.Attributes().PutInt("http.status_code", 500)
+	log := plog.NewLogs()
+	log.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStr("foo")
+	unmatched := ptrace.NewTraces()
+	unmatched.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty().SetName("foo")
+
+	var received ptrace.Traces
+	require.Eventually(t, func() bool {
+		// only the matching spans of the subscribed signal are sent
+		_, err = processor.ConsumeLogs(context.Background(), log)
+		require.NoError(t, err)
+		_, err = processor.ConsumeTraces(context.Background(), unmatched)
+		require.NoError(t, err)
+		_, err = processor.ConsumeTraces(context.Background(), trace)
+		require.NoError(t, err)
+
+		var msg []byte
+		require.NoError(t, wsConn.SetReadDeadline(time.Now().Add(100*time.Millisecond)))
+		if websocket.Message.Receive(wsConn, &msg) != nil {
+			return false
+		}
+		received, err = (&ptrace.JSONUnmarshaler{}).UnmarshalTraces(msg)
+		require.NoError(t, err)
+		return true
+	}, 5*time.Second, 10*time.Millisecond)
+
+	receivedSpans := received.ResourceSpans().At(0).ScopeSpans().At(0).Spans()
+	require.Equal(t, 2, receivedSpans.Len())
+	assert.Equal(t, "bar", receivedSpans.At(0).Name())
+	assert.Equal(t, "baz", receivedSpans.At(1).Name())
+	// the data consumed by the pipeline is not modified
+	assert.Equal(t, 3, spans.Len())
+}
+
+func TestSocketConnectionInvalidQuery(t *testing.T) {
+	cfg := &Config{
+		ServerConfig: confighttp.ServerConfig{
+			Endpoint: "localhost:12005",
+		},
+		Limit: 1,
+	}
+	processor := newProcessor(processortest.NewNopCreateSettings(), cfg)
+	require.NoError(t, processor.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() {
+		require.NoError(t, processor.Shutdown(context.Background()))
+	}()
+
+	query := url.Values{signalParam: {"traces"}, conditionParam: {`name ==`}}
+	resp, err := http.Get("http://localhost:12005/?" + query.Encode())
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Contains(t, string(body), "invalid condition")
+}
@@ -0,0 +1,180 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package remotetapprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/remotetapprocessor"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"golang.org/x/time/rate"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/filter/expr"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/filter/filterottl"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottllog"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlmetric"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlspan"
+)
+
+const (
+	// signalParam is the query parameter selecting the signal sent to the client.
+	signalParam = "signal"
+	// conditionParam is the query parameter holding an OTTL condition selecting the data sent
+	// to the client. It can be repeated, the data matching any of the conditions is sent.
+	conditionParam = "condition"
+	// limitParam is the query parameter lowering the rate limit of the client.
+	limitParam = "limit"
+
+	// subscriberBufferSize is the number of messages buffered for a client, the messages
+	// are dropped when a client doesn't read them fast enough.
+	subscriberBufferSize = 16
+)
+
+var errConditionWithoutSignal = errors.New("a signal is required to filter with a condition")
+
+// subscriber is a websocket client, receiving the data of its signal that matches its conditions
+// within its rate limit.
+type subscriber struct {
+	signal  component.DataType
+	limiter *rate.Limiter
+	ch      chan []byte
+
+	spanCondition   expr.BoolExpr[ottlspan.TransformContext]
+	metricCondition expr.BoolExpr[ottlmetric.TransformContext]
+	logCondition    expr.BoolExpr[ottllog.TransformContext]
+}
+
+// newSubscriber returns a subscriber for the query parameters of the connect URL.
+func newSubscriber(query url.Values, config *Config, set component.TelemetrySettings) (*subscriber, error) {
+	s := &subscriber{
+		limiter: rate.NewLimiter(config.Limit, 1),
+		ch:      make(chan []byte, subscriberBufferSize),
+	}
+
+	if limit := query.Get(limitParam); limit != "" {
+		l, err := strconv.ParseFloat(limit, 64)
+		if err != nil || l <= 0 {
+			return nil, fmt.Errorf("invalid %s %q, must be a number greater than 0", limitParam, limit)
+		}
+		if rate.Limit(l) < config.Limit {
+			s.limiter.SetLimit(rate.Limit(l))
+		}
+	}
+
+	switch signal := query.Get(signalParam); signal {
+	case "":
+	case component.DataTypeTraces.String():
+		s.signal = component.DataTypeTraces
+	case component.DataTypeMetrics.String():
+		s.signal = component.DataTypeMetrics
+	case component.DataTypeLogs.String():
+		s.signal = component.DataTypeLogs
+	default:
+		return nil, fmt.Errorf("invalid %s %q, must be %q, %q or %q", signalParam, signal,
+			component.DataTypeTraces, component.DataTypeMetrics, component.DataTypeLogs)
+	}
+
+	conditions := query[conditionParam]
+	if len(conditions) == 0 {
+		return s, nil
+	}
+	var err error
+	switch s.signal {
+	case component.DataTypeTraces:
+		s.spanCondition, err = filterottl.NewBoolExprForSpan(conditions, filterottl.StandardSpanFuncs(), ottl.IgnoreError, set)
+	case component.DataTypeMetrics:
+		s.metricCondition, err = filterottl.NewBoolExprForMetric(conditions, filterottl.StandardMetricFuncs(), ottl.IgnoreError, set)
+	case component.DataTypeLogs:
+		s.logCondition, err = filterottl.NewBoolExprForLog(conditions, filterottl.StandardLogFuncs(), ottl.IgnoreError, set)
+	default:
+		return nil, errConditionWithoutSignal
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", conditionParam, err)
+	}
+	return s, nil
+}
+
+// subscribed returns whether the client receives the data of the signal.
+func (s *subscriber) subscribed(signal component.DataType) bool {
+	return s.signal == (component.DataType{}) || s.signal == signal
+}
+
+// send sends the message to the client unless the client doesn't keep up with the previous
+// messages, returning whether the message was sent.
+func (s *subscriber) send(message []byte) bool {
+	select {
+	case s.ch <- message:
+		return true
+	default:
+		return false
+	}
+}
+
+// filterTraces returns the spans matching the condition of the client, and whether there are any.
+func (s *subscriber) filterTraces(ctx context.Context, td ptrace.Traces) (ptrace.Traces, bool) {
+	if s.spanCondition == nil {
+		return td, td.SpanCount() > 0
+	}
+	filtered := ptrace.NewTraces()
+	td.CopyTo(filtered)
+	filtered.ResourceSpans().RemoveIf(func(rs ptrace.ResourceSpans) bool {
+		rs.ScopeSpans().RemoveIf(func(ss ptrace.ScopeSpans) bool {
+			ss.Spans().RemoveIf(func(span ptrace.Span) bool {
+				matched, err := s.spanCondition.Eval(ctx, ottlspan.NewTransformContext(span, ss.Scope(), rs.Resource()))
+				return err != nil || !matched
+			})
+			return ss.Spans().Len() == 0
+		})
+		return rs.ScopeSpans().Len() == 0
+	})
+	return filtered, filtered.ResourceSpans().Len() > 0
+}
+
+// filterMetrics returns the metrics matching the condition of the client, and whether there are any.
+func (s *subscriber) filterMetrics(ctx context.Context, md pmetric.Metrics) (pmetric.Metrics, bool) {
+	if s.metricCondition == nil {
+		return md, md.MetricCount() > 0
+	}
+	filtered := pmetric.NewMetrics()
+	md.CopyTo(filtered)
+	filtered.ResourceMetrics().RemoveIf(func(rm pmetric.ResourceMetrics) bool {
+		rm.ScopeMetrics().RemoveIf(func(sm pmetric.ScopeMetrics) bool {
+			sm.Metrics().RemoveIf(func(metric pmetric.Metric) bool {
+				matched, err := s.metricCondition.Eval(ctx, ottlmetric.NewTransformContext(metric, sm.Metrics(), sm.Scope(), rm.Resource()))
+				return err != nil || !matched
+			})
+			return sm.Metrics().Len() == 0
+		})
+		return rm.ScopeMetrics().Len() == 0
+	})
+	return filtered, filtered.ResourceMetrics().Len() > 0
+}
+
+// filterLogs returns the log records matching the condition of the client, and whether there are any.
+func (s *subscriber) filterLogs(ctx context.Context, ld plog.Logs) (plog.Logs, bool) {
+	if s.logCondition == nil {
+		return ld, ld.LogRecordCount() > 0
+	}
+	filtered := plog.NewLogs()
+	ld.CopyTo(filtered)
+	filtered.ResourceLogs().RemoveIf(func(rl plog.ResourceLogs) bool {
+		rl.ScopeLogs().RemoveIf(func(sl plog.ScopeLogs) bool {
+			sl.LogRecords().RemoveIf(func(lr plog.LogRecord) bool {
+				matched, err := s.logCondition.Eval(ctx, ottllog.NewTransformContext(lr, sl.Scope(), rl.Resource()))
+				return err != nil || !matched
+			})
+			return sl.LogRecords().Len() == 0
+		})
+		return rl.ScopeLogs().Len() == 0
+	})
+	return filtered, filtered.ResourceLogs().Len() > 0
+}
@@ -0,0 +1,141 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package remotetapprocessor
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"golang.org/x/time/rate"
+)
+
+func TestNewSubscriber(t *testing.T) {
+	cfg := &Config{Limit: 10}
+
+	for _, tt := range []struct {
+		casename      string
+		query         url.Values
+		expectedLimit rate.Limit
+		expectedErr   string
+	}{
+		{
+			casename:      "default",
+			query:         url.Values{},
+			expectedLimit: 10,
+		},
+		{
+			casename:      "lowerLimit",
+			query:         url.Values{signalParam: {"logs"}, conditionParam: {`severity_number >= SEVERITY_NUMBER_ERROR`}, limitParam: {"0.5"}},
+			expectedLimit: 0.5,
+		},
+		{
+			casename:      "higherLimitIsCapped",
+			query:         url.Values{limitParam: {"100"}},
+			expectedLimit: 10,
+		},
+		{
+			casename:    "invalidLimit",
+			query:       url.Values{limitParam: {"0"}},
+			expectedErr: `invalid limit "0", must be a number greater than 0`,
+		},
+		{
+			casename:    "invalidSignal",
+			query:       url.Values{signalParam: {"profiles"}},
+			expectedErr: `invalid signal "profiles", must be "traces", "metrics" or "logs"`,
+		},
+		{
+			casename:    "conditionWithoutSignal",
+			query:       url.Values{conditionParam: {`name == "foo"`}},
+			expectedErr: errConditionWithoutSignal.Error(),
+		},
+		{
+			casename:    "invalidCondition",
+			query:       url.Values{signalParam: {"metrics"}, conditionParam: {`unknown == "foo"`}},
+			expectedErr: "invalid condition",
+		},
+	} {
+		t.Run(tt.casename, func(t *testing.T) {
+			s, err := newSubscriber(tt.query, cfg, componenttest.NewNopTelemetrySettings())
+			if tt.expectedErr != "" {
+				assert.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedLimit, s.limiter.Limit())
+		})
+	}
+}
+
+func TestSubscriberSignal(t *testing.T) {
+	all, err := newSubscriber(url.Values{}, &Config{Limit: 1}, componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+	assert.True(t, all.subscribed(component.DataTypeTraces))
+	assert.True(t, all.subscribed(component.DataTypeLogs))
+
+	metrics, err := newSubscriber(url.Values{signalParam: {"metrics"}}, &Config{Limit: 1}, componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+	assert.True(t, metrics.subscribed(component.DataTypeMetrics))
+	assert.False(t, metrics.subscribed(component.DataTypeTraces))
+}
+
+func TestSubscriberFilterMetrics(t *testing.T) {
+	s, err := newSubscriber(url.Values{signalParam: {"metrics"}, conditionParam: {`IsMatch(name, "^http\\.")`}}, &Config{Limit: 1}, componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+
+	md := pmetric.NewMetrics()
+	metrics := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics()
+	metrics.AppendEmpty().SetName("http.server.duration")
+	metrics.AppendEmpty().SetName("process.cpu.time")
+	other := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics()
+	other.AppendEmpty().SetName("system.memory.usage")
+
+	filtered, ok := s.filterMetrics(context.Background(), md)
+	require.True(t, ok)
+	require.Equal(t, 1, filtered.ResourceMetrics().Len())
+	assert.Equal(t, 1, filtered.MetricCount())
+	assert.Equal(t, "http.server.duration", filtered.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Name())
+	assert.Equal(t, 3, md.MetricCount())
+
+	unmatched := pmetric.NewMetrics()
+	unmatched.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty().SetName("db.client.duration")
+	_, ok = s.filterMetrics(context.Background(), unmatched)
+	assert.False(t, ok)
+}
+
+func TestSubscriberFilterLogs(t *testing.T) {
+	s, err := newSubscriber(url.Values{signalParam: {"logs"}, conditionParam: {`severity_number >= SEVERITY_NUMBER_ERROR`}}, &Config{Limit: 1}, componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+
+	ld := plog.NewLogs()
+	records := ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords()
+	records.AppendEmpty().SetSeverityNumber(plog.SeverityNumberInfo)
+	records.AppendEmpty().SetSeverityNumber(plog.SeverityNumberError)
+
+	filtered, ok := s.filterLogs(context.Background(), ld)
+	require.True(t, ok)
+	require.Equal(t, 1, filtered.LogRecordCount())
+	assert.Equal(t, plog.SeverityNumberError, filtered.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0).SeverityNumber())
+}
+
+func TestSubscriberRateLimit(t *testing.T) {
+	s, err := newSubscriber(url.Values{}, &Config{Limit: 1}, componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+	w := &wsprocessor{config: &Config{Limit: 1}, telemetrySettings: componenttest.NewNopTelemetrySettings(), cs: newChannelSet()}
+	w.cs.add(s)
+
+	ld := plog.NewLogs()
+	ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStr("foo")
+	for i := 0; i < 10; i++ {
+		_, err = w.ConsumeLogs(context.Background(), ld)
+		require.NoError(t, err)
+	}
+	assert.Len(t, s.ch, 1)
+}
@@ -34,7 +34,16 @@ func (cfg *Config) Validate() error {
 	if len(cfg.Scrapers) == 0 {
 		return errors.New("must specify at least one scraper")
 	}
-	return nil
+
+	// The scraper configs are stored as interfaces, which are not validated
+	// through the receiver config.
+	var errs error
+	for key, scraperCfg := range cfg.Scrapers {
+		if err := component.ValidateConfig(scraperCfg); err != nil {
+			errs = errors.Join(errs, fmt.Errorf("invalid %q scraper config: %w", key, err))
+		}
+	}
+	return errs
 }
 
 // Unmarshal a config.Parser into the config struct.
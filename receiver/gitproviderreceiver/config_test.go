@@ -18,6 +18,7 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/gitproviderreceiver/internal"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/gitproviderreceiver/internal/metadata"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/gitproviderreceiver/internal/scraper/githubscraper"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/gitproviderreceiver/internal/scraper/gitlabscraper"
 )
 
 func TestLoadConfig(t *testing.T) {
@@ -42,6 +43,12 @@ func TestLoadConfig(t *testing.T) {
 	assert.Equal(t, defaultConfigGitHubScraper, r0)
 
 	r1 := cfg.Receivers[component.NewIDWithName(metadata.Type, "customname")].(*Config)
+	gitlabConfig := (&gitlabscraper.Factory{}).CreateDefaultConfig().(*gitlabscraper.Config)
+	gitlabConfig.GitLabOrg = "liatrio"
+	gitlabConfig.Deployments = internal.DeploymentsConfig{
+		Environments: []string{"production", "staging"},
+		Lookback:     30 * 24 * time.Hour,
+	}
 	expectedConfig := &Config{
 		ControllerConfig: scraperhelper.ControllerConfig{
 			CollectionInterval: 30 * time.Second,
@@ -49,6 +56,7 @@ func TestLoadConfig(t *testing.T) {
 		},
 		Scrapers: map[string]internal.Config{
 			githubscraper.TypeStr: (&githubscraper.Factory{}).CreateDefaultConfig(),
+			gitlabscraper.TypeStr: gitlabConfig,
 		},
 	}
 
@@ -77,6 +85,18 @@ func TestLoadInvalidConfig_InvalidScraperKey(t *testing.T) {
 	require.Contains(t, err.Error(), "error reading configuration for \"gitprovider\": invalid scraper key: \"invalidscraperkey\"")
 }
 
+func TestLoadInvalidConfig_GitLab(t *testing.T) {
+	factories, err := otelcoltest.NopFactories()
+	require.NoError(t, err)
+
+	factory := NewFactory()
+	factories.Receivers[metadata.Type] = factory
+	_, err = otelcoltest.LoadConfigAndValidate(filepath.Join("testdata", "config-invalidgitlab.yaml"), factories)
+
+	require.ErrorContains(t, err, "gitlab_org must be specified")
+	require.ErrorContains(t, err, "deployments: lookback must be positive")
+}
+
 func TestConfig_Unmarshal(t *testing.T) {
 	type fields struct {
 		ControllerConfig     scraperhelper.ControllerConfig
@@ -0,0 +1,180 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package internal // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/gitproviderreceiver/internal"
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/gitproviderreceiver/internal/metadata"
+)
+
+const defaultDeploymentLookback = 7 * 24 * time.Hour
+
+// DeploymentsConfig defines which deployments the scrapers use to compute the
+// DORA metrics of a repository.
+type DeploymentsConfig struct {
+	// Environments are the names of the environments to compute the metrics for.
+	Environments []string `mapstructure:"environments"`
+	// Lookback is how far back in time deployments and merges are considered.
+	Lookback time.Duration `mapstructure:"lookback"`
+}
+
+// NewDefaultDeploymentsConfig returns the deployments configuration used when
+// none is set, which looks at the last week of production deployments.
+func NewDefaultDeploymentsConfig() DeploymentsConfig {
+	return DeploymentsConfig{
+		Environments: []string{"production"},
+		Lookback:     defaultDeploymentLookback,
+	}
+}
+
+// Validate checks the deployments configuration is valid.
+func (cfg *DeploymentsConfig) Validate() error {
+	var errs error
+	if len(cfg.Environments) == 0 {
+		errs = errors.Join(errs, errors.New("deployments: at least one environment must be specified"))
+	}
+	if cfg.Lookback <= 0 {
+		errs = errors.Join(errs, errors.New("deployments: lookback must be positive"))
+	}
+	return errs
+}
+
+// DeploymentMetricsEnabled returns whether any of the deployment metrics are
+// enabled, so that scrapers can skip the API calls needed for them otherwise.
+func DeploymentMetricsEnabled(cfg metadata.MetricsBuilderConfig) bool {
+	return cfg.Metrics.GitRepositoryDeploymentCount.Enabled ||
+		cfg.Metrics.GitRepositoryDeploymentLeadTime.Enabled ||
+		cfg.Metrics.GitRepositoryDeploymentChangeFailureRate.Enabled ||
+		cfg.Metrics.GitRepositoryDeploymentTimeToRestore.Enabled
+}
+
+// Deployment is a finished deployment of a repository to an environment.
+type Deployment struct {
+	Environment string
+	FinishedAt  time.Time
+	Failed      bool
+}
+
+// DeploymentMetrics are the DORA metrics of a repository for one environment.
+type DeploymentMetrics struct {
+	Environment string
+	// Successes and Failures are the number of successful and failed deployments.
+	Successes int64
+	Failures  int64
+	// LeadTime is the median time from a merge to the first successful
+	// deployment finishing after it. It is only set if HasLeadTime is true.
+	LeadTime    time.Duration
+	HasLeadTime bool
+	// TimeToRestore is the median time from the first of a run of failed
+	// deployments to the next successful one. It is only set if
+	// HasTimeToRestore is true.
+	TimeToRestore    time.Duration
+	HasTimeToRestore bool
+}
+
+// ComputeDeploymentMetrics returns the metrics of each environment for the
+// deployments finished and the changes merged since the given time.
+func ComputeDeploymentMetrics(
+	environments []string,
+	deployments []Deployment,
+	merges []time.Time,
+	since time.Time,
+) []DeploymentMetrics {
+	byEnvironment := map[string][]Deployment{}
+	for _, d := range deployments {
+		if d.FinishedAt.Before(since) {
+			continue
+		}
+		byEnvironment[d.Environment] = append(byEnvironment[d.Environment], d)
+	}
+
+	var recent []time.Time
+	for _, m := range merges {
+		if !m.Before(since) {
+			recent = append(recent, m)
+		}
+	}
+
+	metrics := make([]DeploymentMetrics, 0, len(environments))
+	for _, env := range environments {
+		envDeployments := byEnvironment[env]
+		sort.SliceStable(envDeployments, func(i, j int) bool {
+			return envDeployments[i].FinishedAt.Before(envDeployments[j].FinishedAt)
+		})
+
+		m := DeploymentMetrics{Environment: env}
+		var successes []time.Time
+		var restores []time.Duration
+		var failedAt *time.Time
+		for i, d := range envDeployments {
+			if d.Failed {
+				m.Failures++
+				if failedAt == nil {
+					failedAt = &envDeployments[i].FinishedAt
+				}
+				continue
+			}
+			m.Successes++
+			successes = append(successes, d.FinishedAt)
+			if failedAt != nil {
+				restores = append(restores, d.FinishedAt.Sub(*failedAt))
+				failedAt = nil
+			}
+		}
+
+		var leadTimes []time.Duration
+		for _, merged := range recent {
+			i := sort.Search(len(successes), func(i int) bool {
+				return !successes[i].Before(merged)
+			})
+			if i < len(successes) {
+				leadTimes = append(leadTimes, successes[i].Sub(merged))
+			}
+		}
+
+		m.LeadTime, m.HasLeadTime = median(leadTimes)
+		m.TimeToRestore, m.HasTimeToRestore = median(restores)
+		metrics = append(metrics, m)
+	}
+	return metrics
+}
+
+// RecordDeploymentMetrics records the deployment metrics of a repository.
+// The change failure rate is only recorded for environments with deployments.
+func RecordDeploymentMetrics(
+	mb *metadata.MetricsBuilder,
+	now pcommon.Timestamp,
+	repoName string,
+	metrics []DeploymentMetrics,
+) {
+	for _, m := range metrics {
+		mb.RecordGitRepositoryDeploymentCountDataPoint(now, m.Successes, repoName, m.Environment)
+		if total := m.Successes + m.Failures; total > 0 {
+			mb.RecordGitRepositoryDeploymentChangeFailureRateDataPoint(now, float64(m.Failures)/float64(total), repoName, m.Environment)
+		}
+		if m.HasLeadTime {
+			mb.RecordGitRepositoryDeploymentLeadTimeDataPoint(now, int64(m.LeadTime.Seconds()), repoName, m.Environment)
+		}
+		if m.HasTimeToRestore {
+			mb.RecordGitRepositoryDeploymentTimeToRestoreDataPoint(now, int64(m.TimeToRestore.Seconds()), repoName, m.Environment)
+		}
+	}
+}
+
+func median(durations []time.Duration) (time.Duration, bool) {
+	if len(durations) == 0 {
+		return 0, false
+	}
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	mid := len(durations) / 2
+	if len(durations)%2 == 0 {
+		return (durations[mid-1] + durations[mid]) / 2, true
+	}
+	return durations[mid], true
+}
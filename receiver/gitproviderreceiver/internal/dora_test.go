@@ -0,0 +1,138 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/gitproviderreceiver/internal/metadata"
+)
+
+func TestDeploymentsConfigValidate(t *testing.T) {
+	cfg := NewDefaultDeploymentsConfig()
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Environments = nil
+	cfg.Lookback = 0
+	assert.EqualError(t, cfg.Validate(), "deployments: at least one environment must be specified\ndeployments: lookback must be positive")
+}
+
+func TestComputeDeploymentMetrics(t *testing.T) {
+	since := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	at := func(hours int) time.Time { return since.Add(time.Duration(hours) * time.Hour) }
+
+	deployments := []Deployment{
+		// before the lookback window
+		{Environment: "production", FinishedAt: at(-2), Failed: true},
+		{Environment: "production", FinishedAt: at(2)},
+		{Environment: "production", FinishedAt: at(10), Failed: true},
+		{Environment: "production", FinishedAt: at(6), Failed: true},
+		{Environment: "production", FinishedAt: at(12)},
+		{Environment: "production", FinishedAt: at(20), Failed: true},
+		{Environment: "production", FinishedAt: at(30)},
+		{Environment: "staging", FinishedAt: at(1)},
+		{Environment: "qa", FinishedAt: at(1)},
+	}
+	merges := []time.Time{
+		// before the lookback window
+		at(-10),
+		at(0),
+		at(5),
+		at(11),
+		// not deployed yet
+		at(40),
+	}
+
+	metrics := ComputeDeploymentMetrics([]string{"production", "staging", "development"}, deployments, merges, since)
+	assert.Equal(t, []DeploymentMetrics{
+		{
+			Environment: "production",
+			Successes:   3,
+			Failures:    3,
+			// merged at 0, 5 and 11 and deployed at 2, 12 and 12
+			LeadTime:    2 * time.Hour,
+			HasLeadTime: true,
+			// failed at 6 and 20 and restored at 12 and 30
+			TimeToRestore:    8 * time.Hour,
+			HasTimeToRestore: true,
+		},
+		{
+			Environment: "staging",
+			Successes:   1,
+			// merged at 0 and deployed at 1
+			LeadTime:    time.Hour,
+			HasLeadTime: true,
+		},
+		{
+			Environment: "development",
+		},
+	}, metrics)
+}
+
+func TestMedian(t *testing.T) {
+	_, ok := median(nil)
+	assert.False(t, ok)
+
+	m, ok := median([]time.Duration{3, 1, 2})
+	assert.True(t, ok)
+	assert.Equal(t, time.Duration(2), m)
+
+	m, ok = median([]time.Duration{4, 1, 3, 2})
+	assert.True(t, ok)
+	assert.Equal(t, time.Duration(2), m)
+}
+
+func TestRecordDeploymentMetrics(t *testing.T) {
+	mbc := metadata.DefaultMetricsBuilderConfig()
+	assert.False(t, DeploymentMetricsEnabled(mbc))
+	mbc.Metrics.GitRepositoryDeploymentCount.Enabled = true
+	mbc.Metrics.GitRepositoryDeploymentLeadTime.Enabled = true
+	mbc.Metrics.GitRepositoryDeploymentChangeFailureRate.Enabled = true
+	mbc.Metrics.GitRepositoryDeploymentTimeToRestore.Enabled = true
+	assert.True(t, DeploymentMetricsEnabled(mbc))
+
+	mb := metadata.NewMetricsBuilder(mbc, receivertest.NewNopCreateSettings())
+	RecordDeploymentMetrics(mb, pcommon.NewTimestampFromTime(time.Now()), "repo1", []DeploymentMetrics{
+		{
+			Environment:      "production",
+			Successes:        3,
+			Failures:         1,
+			LeadTime:         90 * time.Minute,
+			HasLeadTime:      true,
+			TimeToRestore:    time.Hour,
+			HasTimeToRestore: true,
+		},
+		{Environment: "staging"},
+	})
+
+	md := mb.Emit()
+	metrics := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	values := map[string]any{}
+	for i := 0; i < metrics.Len(); i++ {
+		m := metrics.At(i)
+		dps := m.Gauge().DataPoints()
+		for j := 0; j < dps.Len(); j++ {
+			env, _ := dps.At(j).Attributes().Get("deployment.environment")
+			key := m.Name() + "/" + env.Str()
+			if m.Name() == "git.repository.deployment.change_failure_rate" {
+				values[key] = dps.At(j).DoubleValue()
+			} else {
+				values[key] = dps.At(j).IntValue()
+			}
+		}
+	}
+	require.Equal(t, map[string]any{
+		"git.repository.deployment.count/production":               int64(3),
+		"git.repository.deployment.count/staging":                  int64(0),
+		"git.repository.deployment.change_failure_rate/production": 0.25,
+		"git.repository.deployment.lead_time/production":           int64(5400),
+		"git.repository.deployment.time_to_restore/production":     int64(3600),
+	}, values)
+}
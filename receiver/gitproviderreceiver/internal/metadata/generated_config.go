@@ -28,13 +28,17 @@ func (ms *MetricConfig) Unmarshal(parser *confmap.Conf) error {
 
 // MetricsConfig provides config for gitprovider metrics.
 type MetricsConfig struct {
-	GitRepositoryBranchCount               MetricConfig `mapstructure:"git.repository.branch.count"`
-	GitRepositoryContributorCount          MetricConfig `mapstructure:"git.repository.contributor.count"`
-	GitRepositoryCount                     MetricConfig `mapstructure:"git.repository.count"`
-	GitRepositoryPullRequestCount          MetricConfig `mapstructure:"git.repository.pull_request.count"`
-	GitRepositoryPullRequestTimeOpen       MetricConfig `mapstructure:"git.repository.pull_request.time_open"`
-	GitRepositoryPullRequestTimeToApproval MetricConfig `mapstructure:"git.repository.pull_request.time_to_approval"`
-	GitRepositoryPullRequestTimeToMerge    MetricConfig `mapstructure:"git.repository.pull_request.time_to_merge"`
+	GitRepositoryBranchCount                 MetricConfig `mapstructure:"git.repository.branch.count"`
+	GitRepositoryContributorCount            MetricConfig `mapstructure:"git.repository.contributor.count"`
+	GitRepositoryCount                       MetricConfig `mapstructure:"git.repository.count"`
+	GitRepositoryDeploymentChangeFailureRate MetricConfig `mapstructure:"git.repository.deployment.change_failure_rate"`
+	GitRepositoryDeploymentCount             MetricConfig `mapstructure:"git.repository.deployment.count"`
+	GitRepositoryDeploymentLeadTime          MetricConfig `mapstructure:"git.repository.deployment.lead_time"`
+	GitRepositoryDeploymentTimeToRestore     MetricConfig `mapstructure:"git.repository.deployment.time_to_restore"`
+	GitRepositoryPullRequestCount            MetricConfig `mapstructure:"git.repository.pull_request.count"`
+	GitRepositoryPullRequestTimeOpen         MetricConfig `mapstructure:"git.repository.pull_request.time_open"`
+	GitRepositoryPullRequestTimeToApproval   MetricConfig `mapstructure:"git.repository.pull_request.time_to_approval"`
+	GitRepositoryPullRequestTimeToMerge      MetricConfig `mapstructure:"git.repository.pull_request.time_to_merge"`
 }
 
 func DefaultMetricsConfig() MetricsConfig {
@@ -48,6 +52,18 @@ func DefaultMetricsConfig() MetricsConfig {
 		GitRepositoryCount: MetricConfig{
 			Enabled: true,
 		},
+		GitRepositoryDeploymentChangeFailureRate: MetricConfig{
+			Enabled: false,
+		},
+		GitRepositoryDeploymentCount: MetricConfig{
+			Enabled: false,
+		},
+		GitRepositoryDeploymentLeadTime: MetricConfig{
+			Enabled: false,
+		},
+		GitRepositoryDeploymentTimeToRestore: MetricConfig{
+			Enabled: false,
+		},
 		GitRepositoryPullRequestCount: MetricConfig{
 			Enabled: true,
 		},
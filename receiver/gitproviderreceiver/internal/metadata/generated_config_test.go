@@ -26,13 +26,17 @@ func TestMetricsBuilderConfig(t *testing.T) {
 			name: "all_set",
 			want: MetricsBuilderConfig{
 				Metrics: MetricsConfig{
-					GitRepositoryBranchCount:               MetricConfig{Enabled: true},
-					GitRepositoryContributorCount:          MetricConfig{Enabled: true},
-					GitRepositoryCount:                     MetricConfig{Enabled: true},
-					GitRepositoryPullRequestCount:          MetricConfig{Enabled: true},
-					GitRepositoryPullRequestTimeOpen:       MetricConfig{Enabled: true},
-					GitRepositoryPullRequestTimeToApproval: MetricConfig{Enabled: true},
-					GitRepositoryPullRequestTimeToMerge:    MetricConfig{Enabled: true},
+					GitRepositoryBranchCount:                 MetricConfig{Enabled: true},
+					GitRepositoryContributorCount:            MetricConfig{Enabled: true},
+					GitRepositoryCount:                       MetricConfig{Enabled: true},
+					GitRepositoryDeploymentChangeFailureRate: MetricConfig{Enabled: true},
+					GitRepositoryDeploymentCount:             MetricConfig{Enabled: true},
+					GitRepositoryDeploymentLeadTime:          MetricConfig{Enabled: true},
+					GitRepositoryDeploymentTimeToRestore:     MetricConfig{Enabled: true},
+					GitRepositoryPullRequestCount:            MetricConfig{Enabled: true},
+					GitRepositoryPullRequestTimeOpen:         MetricConfig{Enabled: true},
+					GitRepositoryPullRequestTimeToApproval:   MetricConfig{Enabled: true},
+					GitRepositoryPullRequestTimeToMerge:      MetricConfig{Enabled: true},
 				},
 				ResourceAttributes: ResourceAttributesConfig{
 					GitVendorName:    ResourceAttributeConfig{Enabled: true},
@@ -44,13 +48,17 @@ func TestMetricsBuilderConfig(t *testing.T) {
 			name: "none_set",
 			want: MetricsBuilderConfig{
 				Metrics: MetricsConfig{
-					GitRepositoryBranchCount:               MetricConfig{Enabled: false},
-					GitRepositoryContributorCount:          MetricConfig{Enabled: false},
-					GitRepositoryCount:                     MetricConfig{Enabled: false},
-					GitRepositoryPullRequestCount:          MetricConfig{Enabled: false},
-					GitRepositoryPullRequestTimeOpen:       MetricConfig{Enabled: false},
-					GitRepositoryPullRequestTimeToApproval: MetricConfig{Enabled: false},
-					GitRepositoryPullRequestTimeToMerge:    MetricConfig{Enabled: false},
+					GitRepositoryBranchCount:                 MetricConfig{Enabled: false},
+					GitRepositoryContributorCount:            MetricConfig{Enabled: false},
+					GitRepositoryCount:                       MetricConfig{Enabled: false},
+					GitRepositoryDeploymentChangeFailureRate: MetricConfig{Enabled: false},
+					GitRepositoryDeploymentCount:             MetricConfig{Enabled: false},
+					GitRepositoryDeploymentLeadTime:          MetricConfig{Enabled: false},
+					GitRepositoryDeploymentTimeToRestore:     MetricConfig{Enabled: false},
+					GitRepositoryPullRequestCount:            MetricConfig{Enabled: false},
+					GitRepositoryPullRequestTimeOpen:         MetricConfig{Enabled: false},
+					GitRepositoryPullRequestTimeToApproval:   MetricConfig{Enabled: false},
+					GitRepositoryPullRequestTimeToMerge:      MetricConfig{Enabled: false},
 				},
 				ResourceAttributes: ResourceAttributesConfig{
 					GitVendorName:    ResourceAttributeConfig{Enabled: false},
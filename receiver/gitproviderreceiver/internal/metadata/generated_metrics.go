@@ -190,6 +190,214 @@ func newMetricGitRepositoryCount(cfg MetricConfig) metricGitRepositoryCount {
 	return m
 }
 
+type metricGitRepositoryDeploymentChangeFailureRate struct {
+	data     pmetric.Metric // data buffer for generated metric.
+	config   MetricConfig   // metric config provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills git.repository.deployment.change_failure_rate metric with initial data.
+func (m *metricGitRepositoryDeploymentChangeFailureRate) init() {
+	m.data.SetName("git.repository.deployment.change_failure_rate")
+	m.data.SetDescription("The ratio of failed deployments to all finished deployments to an environment within the lookback window")
+	m.data.SetUnit("1")
+	m.data.SetEmptyGauge()
+	m.data.Gauge().DataPoints().EnsureCapacity(m.capacity)
+}
+
+func (m *metricGitRepositoryDeploymentChangeFailureRate) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val float64, repositoryNameAttributeValue string, deploymentEnvironmentAttributeValue string) {
+	if !m.config.Enabled {
+		return
+	}
+	dp := m.data.Gauge().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetDoubleValue(val)
+	dp.Attributes().PutStr("repository.name", repositoryNameAttributeValue)
+	dp.Attributes().PutStr("deployment.environment", deploymentEnvironmentAttributeValue)
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricGitRepositoryDeploymentChangeFailureRate) updateCapacity() {
+	if m.data.Gauge().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Gauge().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricGitRepositoryDeploymentChangeFailureRate) emit(metrics pmetric.MetricSlice) {
+	if m.config.Enabled && m.data.Gauge().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricGitRepositoryDeploymentChangeFailureRate(cfg MetricConfig) metricGitRepositoryDeploymentChangeFailureRate {
+	m := metricGitRepositoryDeploymentChangeFailureRate{config: cfg}
+	if cfg.Enabled {
+		m.data = pmetric.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+type metricGitRepositoryDeploymentCount struct {
+	data     pmetric.Metric // data buffer for generated metric.
+	config   MetricConfig   // metric config provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills git.repository.deployment.count metric with initial data.
+func (m *metricGitRepositoryDeploymentCount) init() {
+	m.data.SetName("git.repository.deployment.count")
+	m.data.SetDescription("The number of successful deployments to an environment within the lookback window")
+	m.data.SetUnit("{deployment}")
+	m.data.SetEmptyGauge()
+	m.data.Gauge().DataPoints().EnsureCapacity(m.capacity)
+}
+
+func (m *metricGitRepositoryDeploymentCount) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val int64, repositoryNameAttributeValue string, deploymentEnvironmentAttributeValue string) {
+	if !m.config.Enabled {
+		return
+	}
+	dp := m.data.Gauge().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetIntValue(val)
+	dp.Attributes().PutStr("repository.name", repositoryNameAttributeValue)
+	dp.Attributes().PutStr("deployment.environment", deploymentEnvironmentAttributeValue)
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricGitRepositoryDeploymentCount) updateCapacity() {
+	if m.data.Gauge().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Gauge().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricGitRepositoryDeploymentCount) emit(metrics pmetric.MetricSlice) {
+	if m.config.Enabled && m.data.Gauge().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricGitRepositoryDeploymentCount(cfg MetricConfig) metricGitRepositoryDeploymentCount {
+	m := metricGitRepositoryDeploymentCount{config: cfg}
+	if cfg.Enabled {
+		m.data = pmetric.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+type metricGitRepositoryDeploymentLeadTime struct {
+	data     pmetric.Metric // data buffer for generated metric.
+	config   MetricConfig   // metric config provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills git.repository.deployment.lead_time metric with initial data.
+func (m *metricGitRepositoryDeploymentLeadTime) init() {
+	m.data.SetName("git.repository.deployment.lead_time")
+	m.data.SetDescription("The median time from the merge of a change to the first successful deployment to an environment finishing after it, for the changes merged within the lookback window")
+	m.data.SetUnit("s")
+	m.data.SetEmptyGauge()
+	m.data.Gauge().DataPoints().EnsureCapacity(m.capacity)
+}
+
+func (m *metricGitRepositoryDeploymentLeadTime) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val int64, repositoryNameAttributeValue string, deploymentEnvironmentAttributeValue string) {
+	if !m.config.Enabled {
+		return
+	}
+	dp := m.data.Gauge().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetIntValue(val)
+	dp.Attributes().PutStr("repository.name", repositoryNameAttributeValue)
+	dp.Attributes().PutStr("deployment.environment", deploymentEnvironmentAttributeValue)
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricGitRepositoryDeploymentLeadTime) updateCapacity() {
+	if m.data.Gauge().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Gauge().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricGitRepositoryDeploymentLeadTime) emit(metrics pmetric.MetricSlice) {
+	if m.config.Enabled && m.data.Gauge().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricGitRepositoryDeploymentLeadTime(cfg MetricConfig) metricGitRepositoryDeploymentLeadTime {
+	m := metricGitRepositoryDeploymentLeadTime{config: cfg}
+	if cfg.Enabled {
+		m.data = pmetric.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+type metricGitRepositoryDeploymentTimeToRestore struct {
+	data     pmetric.Metric // data buffer for generated metric.
+	config   MetricConfig   // metric config provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills git.repository.deployment.time_to_restore metric with initial data.
+func (m *metricGitRepositoryDeploymentTimeToRestore) init() {
+	m.data.SetName("git.repository.deployment.time_to_restore")
+	m.data.SetDescription("The median time from a failed deployment to the next successful deployment to the same environment, for the failures within the lookback window")
+	m.data.SetUnit("s")
+	m.data.SetEmptyGauge()
+	m.data.Gauge().DataPoints().EnsureCapacity(m.capacity)
+}
+
+func (m *metricGitRepositoryDeploymentTimeToRestore) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val int64, repositoryNameAttributeValue string, deploymentEnvironmentAttributeValue string) {
+	if !m.config.Enabled {
+		return
+	}
+	dp := m.data.Gauge().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetIntValue(val)
+	dp.Attributes().PutStr("repository.name", repositoryNameAttributeValue)
+	dp.Attributes().PutStr("deployment.environment", deploymentEnvironmentAttributeValue)
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricGitRepositoryDeploymentTimeToRestore) updateCapacity() {
+	if m.data.Gauge().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Gauge().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricGitRepositoryDeploymentTimeToRestore) emit(metrics pmetric.MetricSlice) {
+	if m.config.Enabled && m.data.Gauge().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricGitRepositoryDeploymentTimeToRestore(cfg MetricConfig) metricGitRepositoryDeploymentTimeToRestore {
+	m := metricGitRepositoryDeploymentTimeToRestore{config: cfg}
+	if cfg.Enabled {
+		m.data = pmetric.NewMetric()
+		m.init()
+	}
+	return m
+}
+
 type metricGitRepositoryPullRequestCount struct {
 	data     pmetric.Metric // data buffer for generated metric.
 	config   MetricConfig   // metric config provided by user.
@@ -401,20 +609,24 @@ func newMetricGitRepositoryPullRequestTimeToMerge(cfg MetricConfig) metricGitRep
 // MetricsBuilder provides an interface for scrapers to report metrics while taking care of all the transformations
 // required to produce metric representation defined in metadata and user config.
 type MetricsBuilder struct {
-	config                                       MetricsBuilderConfig // config of the metrics builder.
-	startTime                                    pcommon.Timestamp    // start time that will be applied to all recorded data points.
-	metricsCapacity                              int                  // maximum observed number of metrics per resource.
-	metricsBuffer                                pmetric.Metrics      // accumulates metrics data before emitting.
-	buildInfo                                    component.BuildInfo  // contains version information.
-	resourceAttributeIncludeFilter               map[string]filter.Filter
-	resourceAttributeExcludeFilter               map[string]filter.Filter
-	metricGitRepositoryBranchCount               metricGitRepositoryBranchCount
-	metricGitRepositoryContributorCount          metricGitRepositoryContributorCount
-	metricGitRepositoryCount                     metricGitRepositoryCount
-	metricGitRepositoryPullRequestCount          metricGitRepositoryPullRequestCount
-	metricGitRepositoryPullRequestTimeOpen       metricGitRepositoryPullRequestTimeOpen
-	metricGitRepositoryPullRequestTimeToApproval metricGitRepositoryPullRequestTimeToApproval
-	metricGitRepositoryPullRequestTimeToMerge    metricGitRepositoryPullRequestTimeToMerge
+	config                                         MetricsBuilderConfig // config of the metrics builder.
+	startTime                                      pcommon.Timestamp    // start time that will be applied to all recorded data points.
+	metricsCapacity                                int                  // maximum observed number of metrics per resource.
+	metricsBuffer                                  pmetric.Metrics      // accumulates metrics data before emitting.
+	buildInfo                                      component.BuildInfo  // contains version information.
+	resourceAttributeIncludeFilter                 map[string]filter.Filter
+	resourceAttributeExcludeFilter                 map[string]filter.Filter
+	metricGitRepositoryBranchCount                 metricGitRepositoryBranchCount
+	metricGitRepositoryContributorCount            metricGitRepositoryContributorCount
+	metricGitRepositoryCount                       metricGitRepositoryCount
+	metricGitRepositoryDeploymentChangeFailureRate metricGitRepositoryDeploymentChangeFailureRate
+	metricGitRepositoryDeploymentCount             metricGitRepositoryDeploymentCount
+	metricGitRepositoryDeploymentLeadTime          metricGitRepositoryDeploymentLeadTime
+	metricGitRepositoryDeploymentTimeToRestore     metricGitRepositoryDeploymentTimeToRestore
+	metricGitRepositoryPullRequestCount            metricGitRepositoryPullRequestCount
+	metricGitRepositoryPullRequestTimeOpen         metricGitRepositoryPullRequestTimeOpen
+	metricGitRepositoryPullRequestTimeToApproval   metricGitRepositoryPullRequestTimeToApproval
+	metricGitRepositoryPullRequestTimeToMerge      metricGitRepositoryPullRequestTimeToMerge
 }
 
 // metricBuilderOption applies changes to default metrics builder.
@@ -429,19 +641,23 @@ func WithStartTime(startTime pcommon.Timestamp) metricBuilderOption {
 
 func NewMetricsBuilder(mbc MetricsBuilderConfig, settings receiver.CreateSettings, options ...metricBuilderOption) *MetricsBuilder {
 	mb := &MetricsBuilder{
-		config:                                       mbc,
-		startTime:                                    pcommon.NewTimestampFromTime(time.Now()),
-		metricsBuffer:                                pmetric.NewMetrics(),
-		buildInfo:                                    settings.BuildInfo,
-		metricGitRepositoryBranchCount:               newMetricGitRepositoryBranchCount(mbc.Metrics.GitRepositoryBranchCount),
-		metricGitRepositoryContributorCount:          newMetricGitRepositoryContributorCount(mbc.Metrics.GitRepositoryContributorCount),
-		metricGitRepositoryCount:                     newMetricGitRepositoryCount(mbc.Metrics.GitRepositoryCount),
-		metricGitRepositoryPullRequestCount:          newMetricGitRepositoryPullRequestCount(mbc.Metrics.GitRepositoryPullRequestCount),
-		metricGitRepositoryPullRequestTimeOpen:       newMetricGitRepositoryPullRequestTimeOpen(mbc.Metrics.GitRepositoryPullRequestTimeOpen),
-		metricGitRepositoryPullRequestTimeToApproval: newMetricGitRepositoryPullRequestTimeToApproval(mbc.Metrics.GitRepositoryPullRequestTimeToApproval),
-		metricGitRepositoryPullRequestTimeToMerge:    newMetricGitRepositoryPullRequestTimeToMerge(mbc.Metrics.GitRepositoryPullRequestTimeToMerge),
-		resourceAttributeIncludeFilter:               make(map[string]filter.Filter),
-		resourceAttributeExcludeFilter:               make(map[string]filter.Filter),
+		config:                              mbc,
+		startTime:                           pcommon.NewTimestampFromTime(time.Now()),
+		metricsBuffer:                       pmetric.NewMetrics(),
+		buildInfo:                           settings.BuildInfo,
+		metricGitRepositoryBranchCount:      newMetricGitRepositoryBranchCount(mbc.Metrics.GitRepositoryBranchCount),
+		metricGitRepositoryContributorCount: newMetricGitRepositoryContributorCount(mbc.Metrics.GitRepositoryContributorCount),
+		metricGitRepositoryCount:            newMetricGitRepositoryCount(mbc.Metrics.GitRepositoryCount),
+		metricGitRepositoryDeploymentChangeFailureRate: newMetricGitRepositoryDeploymentChangeFailureRate(mbc.Metrics.GitRepositoryDeploymentChangeFailureRate),
+		metricGitRepositoryDeploymentCount:             newMetricGitRepositoryDeploymentCount(mbc.Metrics.GitRepositoryDeploymentCount),
+		metricGitRepositoryDeploymentLeadTime:          newMetricGitRepositoryDeploymentLeadTime(mbc.Metrics.GitRepositoryDeploymentLeadTime),
+		metricGitRepositoryDeploymentTimeToRestore:     newMetricGitRepositoryDeploymentTimeToRestore(mbc.Metrics.GitRepositoryDeploymentTimeToRestore),
+		metricGitRepositoryPullRequestCount:            newMetricGitRepositoryPullRequestCount(mbc.Metrics.GitRepositoryPullRequestCount),
+		metricGitRepositoryPullRequestTimeOpen:         newMetricGitRepositoryPullRequestTimeOpen(mbc.Metrics.GitRepositoryPullRequestTimeOpen),
+		metricGitRepositoryPullRequestTimeToApproval:   newMetricGitRepositoryPullRequestTimeToApproval(mbc.Metrics.GitRepositoryPullRequestTimeToApproval),
+		metricGitRepositoryPullRequestTimeToMerge:      newMetricGitRepositoryPullRequestTimeToMerge(mbc.Metrics.GitRepositoryPullRequestTimeToMerge),
+		resourceAttributeIncludeFilter:                 make(map[string]filter.Filter),
+		resourceAttributeExcludeFilter:                 make(map[string]filter.Filter),
 	}
 	if mbc.ResourceAttributes.GitVendorName.MetricsInclude != nil {
 		mb.resourceAttributeIncludeFilter["git.vendor.name"] = filter.CreateFilter(mbc.ResourceAttributes.GitVendorName.MetricsInclude)
@@ -520,6 +736,10 @@ func (mb *MetricsBuilder) EmitForResource(rmo ...ResourceMetricsOption) {
 	mb.metricGitRepositoryBranchCount.emit(ils.Metrics())
 	mb.metricGitRepositoryContributorCount.emit(ils.Metrics())
 	mb.metricGitRepositoryCount.emit(ils.Metrics())
+	mb.metricGitRepositoryDeploymentChangeFailureRate.emit(ils.Metrics())
+	mb.metricGitRepositoryDeploymentCount.emit(ils.Metrics())
+	mb.metricGitRepositoryDeploymentLeadTime.emit(ils.Metrics())
+	mb.metricGitRepositoryDeploymentTimeToRestore.emit(ils.Metrics())
 	mb.metricGitRepositoryPullRequestCount.emit(ils.Metrics())
 	mb.metricGitRepositoryPullRequestTimeOpen.emit(ils.Metrics())
 	mb.metricGitRepositoryPullRequestTimeToApproval.emit(ils.Metrics())
@@ -570,6 +790,26 @@ func (mb *MetricsBuilder) RecordGitRepositoryCountDataPoint(ts pcommon.Timestamp
 	mb.metricGitRepositoryCount.recordDataPoint(mb.startTime, ts, val)
 }
 
+// RecordGitRepositoryDeploymentChangeFailureRateDataPoint adds a data point to git.repository.deployment.change_failure_rate metric.
+func (mb *MetricsBuilder) RecordGitRepositoryDeploymentChangeFailureRateDataPoint(ts pcommon.Timestamp, val float64, repositoryNameAttributeValue string, deploymentEnvironmentAttributeValue string) {
+	mb.metricGitRepositoryDeploymentChangeFailureRate.recordDataPoint(mb.startTime, ts, val, repositoryNameAttributeValue, deploymentEnvironmentAttributeValue)
+}
+
+// RecordGitRepositoryDeploymentCountDataPoint adds a data point to git.repository.deployment.count metric.
+func (mb *MetricsBuilder) RecordGitRepositoryDeploymentCountDataPoint(ts pcommon.Timestamp, val int64, repositoryNameAttributeValue string, deploymentEnvironmentAttributeValue string) {
+	mb.metricGitRepositoryDeploymentCount.recordDataPoint(mb.startTime, ts, val, repositoryNameAttributeValue, deploymentEnvironmentAttributeValue)
+}
+
+// RecordGitRepositoryDeploymentLeadTimeDataPoint adds a data point to git.repository.deployment.lead_time metric.
+func (mb *MetricsBuilder) RecordGitRepositoryDeploymentLeadTimeDataPoint(ts pcommon.Timestamp, val int64, repositoryNameAttributeValue string, deploymentEnvironmentAttributeValue string) {
+	mb.metricGitRepositoryDeploymentLeadTime.recordDataPoint(mb.startTime, ts, val, repositoryNameAttributeValue, deploymentEnvironmentAttributeValue)
+}
+
+// RecordGitRepositoryDeploymentTimeToRestoreDataPoint adds a data point to git.repository.deployment.time_to_restore metric.
+func (mb *MetricsBuilder) RecordGitRepositoryDeploymentTimeToRestoreDataPoint(ts pcommon.Timestamp, val int64, repositoryNameAttributeValue string, deploymentEnvironmentAttributeValue string) {
+	mb.metricGitRepositoryDeploymentTimeToRestore.recordDataPoint(mb.startTime, ts, val, repositoryNameAttributeValue, deploymentEnvironmentAttributeValue)
+}
+
 // RecordGitRepositoryPullRequestCountDataPoint adds a data point to git.repository.pull_request.count metric.
 func (mb *MetricsBuilder) RecordGitRepositoryPullRequestCountDataPoint(ts pcommon.Timestamp, val int64, pullRequestStateAttributeValue AttributePullRequestState, repositoryNameAttributeValue string) {
 	mb.metricGitRepositoryPullRequestCount.recordDataPoint(mb.startTime, ts, val, pullRequestStateAttributeValue.String(), repositoryNameAttributeValue)
@@ -79,6 +79,18 @@ func TestMetricsBuilder(t *testing.T) {
 			allMetricsCount++
 			mb.RecordGitRepositoryCountDataPoint(ts, 1)
 
+			allMetricsCount++
+			mb.RecordGitRepositoryDeploymentChangeFailureRateDataPoint(ts, 1, "repository.name-val", "deployment.environment-val")
+
+			allMetricsCount++
+			mb.RecordGitRepositoryDeploymentCountDataPoint(ts, 1, "repository.name-val", "deployment.environment-val")
+
+			allMetricsCount++
+			mb.RecordGitRepositoryDeploymentLeadTimeDataPoint(ts, 1, "repository.name-val", "deployment.environment-val")
+
+			allMetricsCount++
+			mb.RecordGitRepositoryDeploymentTimeToRestoreDataPoint(ts, 1, "repository.name-val", "deployment.environment-val")
+
 			defaultMetricsCount++
 			allMetricsCount++
 			mb.RecordGitRepositoryPullRequestCountDataPoint(ts, 1, AttributePullRequestStateOpen, "repository.name-val")
@@ -162,6 +174,78 @@ func TestMetricsBuilder(t *testing.T) {
 					assert.Equal(t, ts, dp.Timestamp())
 					assert.Equal(t, pmetric.NumberDataPointValueTypeInt, dp.ValueType())
 					assert.Equal(t, int64(1), dp.IntValue())
+				case "git.repository.deployment.change_failure_rate":
+					assert.False(t, validatedMetrics["git.repository.deployment.change_failure_rate"], "Found a duplicate in the metrics slice: git.repository.deployment.change_failure_rate")
+					validatedMetrics["git.repository.deployment.change_failure_rate"] = true
+					assert.Equal(t, pmetric.MetricTypeGauge, ms.At(i).Type())
+					assert.Equal(t, 1, ms.At(i).Gauge().DataPoints().Len())
+					assert.Equal(t, "The ratio of failed deployments to all finished deployments to an environment within the lookback window", ms.At(i).Description())
+					assert.Equal(t, "1", ms.At(i).Unit())
+					dp := ms.At(i).Gauge().DataPoints().At(0)
+					assert.Equal(t, start, dp.StartTimestamp())
+					assert.Equal(t, ts, dp.Timestamp())
+					assert.Equal(t, pmetric.NumberDataPointValueTypeDouble, dp.ValueType())
+					assert.Equal(t, float64(1), dp.DoubleValue())
+					attrVal, ok := dp.Attributes().Get("repository.name")
+					assert.True(t, ok)
+					assert.EqualValues(t, "repository.name-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("deployment.environment")
+					assert.True(t, ok)
+					assert.EqualValues(t, "deployment.environment-val", attrVal.Str())
+				case "git.repository.deployment.count":
+					assert.False(t, validatedMetrics["git.repository.deployment.count"], "Found a duplicate in the metrics slice: git.repository.deployment.count")
+					validatedMetrics["git.repository.deployment.count"] = true
+					assert.Equal(t, pmetric.MetricTypeGauge, ms.At(i).Type())
+					assert.Equal(t, 1, ms.At(i).Gauge().DataPoints().Len())
+					assert.Equal(t, "The number of successful deployments to an environment within the lookback window", ms.At(i).Description())
+					assert.Equal(t, "{deployment}", ms.At(i).Unit())
+					dp := ms.At(i).Gauge().DataPoints().At(0)
+					assert.Equal(t, start, dp.StartTimestamp())
+					assert.Equal(t, ts, dp.Timestamp())
+					assert.Equal(t, pmetric.NumberDataPointValueTypeInt, dp.ValueType())
+					assert.Equal(t, int64(1), dp.IntValue())
+					attrVal, ok := dp.Attributes().Get("repository.name")
+					assert.True(t, ok)
+					assert.EqualValues(t, "repository.name-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("deployment.environment")
+					assert.True(t, ok)
+					assert.EqualValues(t, "deployment.environment-val", attrVal.Str())
+				case "git.repository.deployment.lead_time":
+					assert.False(t, validatedMetrics["git.repository.deployment.lead_time"], "Found a duplicate in the metrics slice: git.repository.deployment.lead_time")
+					validatedMetrics["git.repository.deployment.lead_time"] = true
+					assert.Equal(t, pmetric.MetricTypeGauge, ms.At(i).Type())
+					assert.Equal(t, 1, ms.At(i).Gauge().DataPoints().Len())
+					assert.Equal(t, "The median time from the merge of a change to the first successful deployment to an environment finishing after it, for the changes merged within the lookback window", ms.At(i).Description())
+					assert.Equal(t, "s", ms.At(i).Unit())
+					dp := ms.At(i).Gauge().DataPoints().At(0)
+					assert.Equal(t, start, dp.StartTimestamp())
+					assert.Equal(t, ts, dp.Timestamp())
+					assert.Equal(t, pmetric.NumberDataPointValueTypeInt, dp.ValueType())
+					assert.Equal(t, int64(1), dp.IntValue())
+					attrVal, ok := dp.Attributes().Get("repository.name")
+					assert.True(t, ok)
+					assert.EqualValues(t, "repository.name-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("deployment.environment")
+					assert.True(t, ok)
+					assert.EqualValues(t, "deployment.environment-val", attrVal.Str())
+				case "git.repository.deployment.time_to_restore":
+					assert.False(t, validatedMetrics["git.repository.deployment.time_to_restore"], "Found a duplicate in the metrics slice: git.repository.deployment.time_to_restore")
+					validatedMetrics["git.repository.deployment.time_to_restore"] = true
+					assert.Equal(t, pmetric.MetricTypeGauge, ms.At(i).Type())
+					assert.Equal(t, 1, ms.At(i).Gauge().DataPoints().Len())
+					assert.Equal(t, "The median time from a failed deployment to the next successful deployment to the same environment, for the failures within the lookback window", ms.At(i).Description())
+					assert.Equal(t, "s", ms.At(i).Unit())
+					dp := ms.At(i).Gauge().DataPoints().At(0)
+					assert.Equal(t, start, dp.StartTimestamp())
+					assert.Equal(t, ts, dp.Timestamp())
+					assert.Equal(t, pmetric.NumberDataPointValueTypeInt, dp.ValueType())
+					assert.Equal(t, int64(1), dp.IntValue())
+					attrVal, ok := dp.Attributes().Get("repository.name")
+					assert.True(t, ok)
+					assert.EqualValues(t, "repository.name-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("deployment.environment")
+					assert.True(t, ok)
+					assert.EqualValues(t, "deployment.environment-val", attrVal.Str())
 				case "git.repository.pull_request.count":
 					assert.False(t, validatedMetrics["git.repository.pull_request.count"], "Found a duplicate in the metrics slice: git.repository.pull_request.count")
 					validatedMetrics["git.repository.pull_request.count"] = true
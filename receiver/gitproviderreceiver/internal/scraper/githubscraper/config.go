@@ -19,4 +19,6 @@ type Config struct {
 	GitHubOrg string `mapstructure:"github_org"`
 	// SearchQuery is the query to use when defining a custom search for repository data
 	SearchQuery string `mapstructure:"search_query"`
+	// Deployments defines the deployments used to compute the deployment metrics
+	Deployments internal.DeploymentsConfig `mapstructure:"deployments"`
 }
@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"go.opentelemetry.io/collector/config/confighttp"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/gitproviderreceiver/internal"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/gitproviderreceiver/internal/metadata"
 )
 
@@ -24,6 +25,10 @@ func TestConfig(t *testing.T) {
 		ClientConfig: confighttp.ClientConfig{
 			Timeout: 15 * time.Second,
 		},
+		Deployments: internal.DeploymentsConfig{
+			Environments: []string{"production"},
+			Lookback:     7 * 24 * time.Hour,
+		},
 	}
 
 	assert.Equal(t, expectedConfig, defaultConfig)
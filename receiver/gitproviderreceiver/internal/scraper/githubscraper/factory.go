@@ -31,6 +31,7 @@ func (f *Factory) CreateDefaultConfig() internal.Config {
 		ClientConfig: confighttp.ClientConfig{
 			Timeout: defaultHTTPTimeout,
 		},
+		Deployments: internal.NewDefaultDeploymentsConfig(),
 	}
 }
 
@@ -81,6 +81,87 @@ type BranchNodeRepositoryDefaultBranchRef struct {
 // GetName returns BranchNodeRepositoryDefaultBranchRef.Name, and is useful for accessing the field via an interface.
 func (v *BranchNodeRepositoryDefaultBranchRef) GetName() string { return v.Name }
 
+// DeploymentNode includes the requested fields of the GraphQL type Deployment.
+// The GraphQL type's documentation follows.
+//
+// Represents triggered deployment instance.
+type DeploymentNode struct {
+	// Identifies the date and time when the object was created.
+	CreatedAt time.Time `json:"createdAt"`
+	// The latest environment to which this deployment was made.
+	Environment string `json:"environment"`
+	// A list of statuses associated with the deployment.
+	Statuses DeploymentNodeStatusesDeploymentStatusConnection `json:"statuses"`
+}
+
+// GetCreatedAt returns DeploymentNode.CreatedAt, and is useful for accessing the field via an interface.
+func (v *DeploymentNode) GetCreatedAt() time.Time { return v.CreatedAt }
+
+// GetEnvironment returns DeploymentNode.Environment, and is useful for accessing the field via an interface.
+func (v *DeploymentNode) GetEnvironment() string { return v.Environment }
+
+// GetStatuses returns DeploymentNode.Statuses, and is useful for accessing the field via an interface.
+func (v *DeploymentNode) GetStatuses() DeploymentNodeStatusesDeploymentStatusConnection {
+	return v.Statuses
+}
+
+// DeploymentNodeStatusesDeploymentStatusConnection includes the requested fields of the GraphQL type DeploymentStatusConnection.
+// The GraphQL type's documentation follows.
+//
+// The connection type for DeploymentStatus.
+type DeploymentNodeStatusesDeploymentStatusConnection struct {
+	// A list of nodes.
+	Nodes []DeploymentNodeStatusesDeploymentStatusConnectionNodesDeploymentStatus `json:"nodes"`
+}
+
+// GetNodes returns DeploymentNodeStatusesDeploymentStatusConnection.Nodes, and is useful for accessing the field via an interface.
+func (v *DeploymentNodeStatusesDeploymentStatusConnection) GetNodes() []DeploymentNodeStatusesDeploymentStatusConnectionNodesDeploymentStatus {
+	return v.Nodes
+}
+
+// DeploymentNodeStatusesDeploymentStatusConnectionNodesDeploymentStatus includes the requested fields of the GraphQL type DeploymentStatus.
+// The GraphQL type's documentation follows.
+//
+// Describes the status of a given deployment attempt.
+type DeploymentNodeStatusesDeploymentStatusConnectionNodesDeploymentStatus struct {
+	// Identifies the current state of the deployment.
+	State DeploymentStatusState `json:"state"`
+	// Identifies the date and time when the object was created.
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// GetState returns DeploymentNodeStatusesDeploymentStatusConnectionNodesDeploymentStatus.State, and is useful for accessing the field via an interface.
+func (v *DeploymentNodeStatusesDeploymentStatusConnectionNodesDeploymentStatus) GetState() DeploymentStatusState {
+	return v.State
+}
+
+// GetCreatedAt returns DeploymentNodeStatusesDeploymentStatusConnectionNodesDeploymentStatus.CreatedAt, and is useful for accessing the field via an interface.
+func (v *DeploymentNodeStatusesDeploymentStatusConnectionNodesDeploymentStatus) GetCreatedAt() time.Time {
+	return v.CreatedAt
+}
+
+// The possible states for a deployment status.
+type DeploymentStatusState string
+
+const (
+	// The deployment is pending.
+	DeploymentStatusStatePending DeploymentStatusState = "PENDING"
+	// The deployment was successful.
+	DeploymentStatusStateSuccess DeploymentStatusState = "SUCCESS"
+	// The deployment has failed.
+	DeploymentStatusStateFailure DeploymentStatusState = "FAILURE"
+	// The deployment is inactive.
+	DeploymentStatusStateInactive DeploymentStatusState = "INACTIVE"
+	// The deployment experienced an error.
+	DeploymentStatusStateError DeploymentStatusState = "ERROR"
+	// The deployment is queued
+	DeploymentStatusStateQueued DeploymentStatusState = "QUEUED"
+	// The deployment is in progress.
+	DeploymentStatusStateInProgress DeploymentStatusState = "IN_PROGRESS"
+	// The deployment is waiting.
+	DeploymentStatusStateWaiting DeploymentStatusState = "WAITING"
+)
+
 // PullRequestNode includes the requested fields of the GraphQL type PullRequest.
 // The GraphQL type's documentation follows.
 //
@@ -514,6 +595,30 @@ func (v *__getBranchDataInput) GetTargetBranch() string { return v.TargetBranch
 // GetBranchCursor returns __getBranchDataInput.BranchCursor, and is useful for accessing the field via an interface.
 func (v *__getBranchDataInput) GetBranchCursor() *string { return v.BranchCursor }
 
+// __getDeploymentDataInput is used internally by genqlient
+type __getDeploymentDataInput struct {
+	Name             string   `json:"name"`
+	Owner            string   `json:"owner"`
+	DeploymentFirst  int      `json:"deploymentFirst"`
+	Environments     []string `json:"environments"`
+	DeploymentCursor *string  `json:"deploymentCursor"`
+}
+
+// GetName returns __getDeploymentDataInput.Name, and is useful for accessing the field via an interface.
+func (v *__getDeploymentDataInput) GetName() string { return v.Name }
+
+// GetOwner returns __getDeploymentDataInput.Owner, and is useful for accessing the field via an interface.
+func (v *__getDeploymentDataInput) GetOwner() string { return v.Owner }
+
+// GetDeploymentFirst returns __getDeploymentDataInput.DeploymentFirst, and is useful for accessing the field via an interface.
+func (v *__getDeploymentDataInput) GetDeploymentFirst() int { return v.DeploymentFirst }
+
+// GetEnvironments returns __getDeploymentDataInput.Environments, and is useful for accessing the field via an interface.
+func (v *__getDeploymentDataInput) GetEnvironments() []string { return v.Environments }
+
+// GetDeploymentCursor returns __getDeploymentDataInput.DeploymentCursor, and is useful for accessing the field via an interface.
+func (v *__getDeploymentDataInput) GetDeploymentCursor() *string { return v.DeploymentCursor }
+
 // __getPullRequestDataInput is used internally by genqlient
 type __getPullRequestDataInput struct {
 	Name     string             `json:"name"`
@@ -652,6 +757,73 @@ type getBranchDataResponse struct {
 // GetRepository returns getBranchDataResponse.Repository, and is useful for accessing the field via an interface.
 func (v *getBranchDataResponse) GetRepository() getBranchDataRepository { return v.Repository }
 
+// getDeploymentDataRepository includes the requested fields of the GraphQL type Repository.
+// The GraphQL type's documentation follows.
+//
+// A repository contains the content for a project.
+type getDeploymentDataRepository struct {
+	// Deployments associated with the repository
+	Deployments getDeploymentDataRepositoryDeploymentsDeploymentConnection `json:"deployments"`
+}
+
+// GetDeployments returns getDeploymentDataRepository.Deployments, and is useful for accessing the field via an interface.
+func (v *getDeploymentDataRepository) GetDeployments() getDeploymentDataRepositoryDeploymentsDeploymentConnection {
+	return v.Deployments
+}
+
+// getDeploymentDataRepositoryDeploymentsDeploymentConnection includes the requested fields of the GraphQL type DeploymentConnection.
+// The GraphQL type's documentation follows.
+//
+// The connection type for Deployment.
+type getDeploymentDataRepositoryDeploymentsDeploymentConnection struct {
+	// A list of nodes.
+	Nodes []DeploymentNode `json:"nodes"`
+	// Information to aid in pagination.
+	PageInfo getDeploymentDataRepositoryDeploymentsDeploymentConnectionPageInfo `json:"pageInfo"`
+}
+
+// GetNodes returns getDeploymentDataRepositoryDeploymentsDeploymentConnection.Nodes, and is useful for accessing the field via an interface.
+func (v *getDeploymentDataRepositoryDeploymentsDeploymentConnection) GetNodes() []DeploymentNode {
+	return v.Nodes
+}
+
+// GetPageInfo returns getDeploymentDataRepositoryDeploymentsDeploymentConnection.PageInfo, and is useful for accessing the field via an interface.
+func (v *getDeploymentDataRepositoryDeploymentsDeploymentConnection) GetPageInfo() getDeploymentDataRepositoryDeploymentsDeploymentConnectionPageInfo {
+	return v.PageInfo
+}
+
+// getDeploymentDataRepositoryDeploymentsDeploymentConnectionPageInfo includes the requested fields of the GraphQL type PageInfo.
+// The GraphQL type's documentation follows.
+//
+// Information about pagination in a connection.
+type getDeploymentDataRepositoryDeploymentsDeploymentConnectionPageInfo struct {
+	// When paginating forwards, are there more items?
+	HasNextPage bool `json:"hasNextPage"`
+	// When paginating forwards, the cursor to continue.
+	EndCursor string `json:"endCursor"`
+}
+
+// GetHasNextPage returns getDeploymentDataRepositoryDeploymentsDeploymentConnectionPageInfo.HasNextPage, and is useful for accessing the field via an interface.
+func (v *getDeploymentDataRepositoryDeploymentsDeploymentConnectionPageInfo) GetHasNextPage() bool {
+	return v.HasNextPage
+}
+
+// GetEndCursor returns getDeploymentDataRepositoryDeploymentsDeploymentConnectionPageInfo.EndCursor, and is useful for accessing the field via an interface.
+func (v *getDeploymentDataRepositoryDeploymentsDeploymentConnectionPageInfo) GetEndCursor() string {
+	return v.EndCursor
+}
+
+// getDeploymentDataResponse is returned by getDeploymentData on success.
+type getDeploymentDataResponse struct {
+	// Lookup a given repository by the owner and repository name.
+	Repository getDeploymentDataRepository `json:"repository"`
+}
+
+// GetRepository returns getDeploymentDataResponse.Repository, and is useful for accessing the field via an interface.
+func (v *getDeploymentDataResponse) GetRepository() getDeploymentDataRepository {
+	return v.Repository
+}
+
 // getPullRequestDataRepository includes the requested fields of the GraphQL type Repository.
 // The GraphQL type's documentation follows.
 //
@@ -958,6 +1130,64 @@ func getBranchData(
 	return &data_, err_
 }
 
+// The query or mutation executed by getDeploymentData.
+const getDeploymentData_Operation = `
+query getDeploymentData ($name: String!, $owner: String!, $deploymentFirst: Int!, $environments: [String!], $deploymentCursor: String) {
+	repository(name: $name, owner: $owner) {
+		deployments(environments: $environments, first: $deploymentFirst, after: $deploymentCursor, orderBy: {field:CREATED_AT,direction:DESC}) {
+			nodes {
+				createdAt
+				environment
+				statuses(first: 10) {
+					nodes {
+						state
+						createdAt
+					}
+				}
+			}
+			pageInfo {
+				hasNextPage
+				endCursor
+			}
+		}
+	}
+}
+`
+
+func getDeploymentData(
+	ctx_ context.Context,
+	client_ graphql.Client,
+	name string,
+	owner string,
+	deploymentFirst int,
+	environments []string,
+	deploymentCursor *string,
+) (*getDeploymentDataResponse, error) {
+	req_ := &graphql.Request{
+		OpName: "getDeploymentData",
+		Query:  getDeploymentData_Operation,
+		Variables: &__getDeploymentDataInput{
+			Name:             name,
+			Owner:            owner,
+			DeploymentFirst:  deploymentFirst,
+			Environments:     environments,
+			DeploymentCursor: deploymentCursor,
+		},
+	}
+	var err_ error
+
+	var data_ getDeploymentDataResponse
+	resp_ := &graphql.Response{Data: &data_}
+
+	err_ = client_.MakeRequest(
+		ctx_,
+		req_,
+		resp_,
+	)
+
+	return &data_, err_
+}
+
 // The query or mutation executed by getPullRequestData.
 const getPullRequestData_Operation = `
 query getPullRequestData ($name: String!, $owner: String!, $prFirst: Int!, $prCursor: String, $prStates: [PullRequestState!]) {
@@ -12,12 +12,15 @@ import (
 	"sync"
 	"time"
 
+	"github.com/Khan/genqlient/graphql"
+	"github.com/google/go-github/v61/github"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/receiver"
 	"go.uber.org/zap"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/gitproviderreceiver/internal"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/gitproviderreceiver/internal/metadata"
 )
 
@@ -52,6 +55,66 @@ func newGitHubScraper(
 	}
 }
 
+// repoData holds the data of a repository the metrics are recorded from.
+type repoData struct {
+	name         string
+	branches     int
+	contributors int
+	prs          []PullRequestNode
+	deployments  []internal.DeploymentMetrics
+}
+
+// getRepoData gets the data of a repository. Failures to get some of the data
+// are logged and the remaining data is still returned.
+func (ghs *githubScraper) getRepoData(
+	ctx context.Context,
+	genClient graphql.Client,
+	restClient *github.Client,
+	repo SearchNodeRepository,
+	now time.Time,
+) repoData {
+	name := repo.Name
+	data := repoData{name: name}
+
+	var err error
+	data.branches, err = ghs.getBranches(ctx, genClient, name, repo.DefaultBranchRef.Name)
+	if err != nil {
+		ghs.logger.Sugar().Errorf("error getting branch count for repo %s", zap.Error(err), name)
+	}
+
+	// Get the contributor count for each of the repositories
+	data.contributors, err = ghs.getContributorCount(ctx, restClient, name)
+	if err != nil {
+		ghs.logger.Sugar().Errorf("error getting contributor count for repo %s", zap.Error(err), name)
+	}
+
+	// Get Pull Request data
+	data.prs, err = ghs.getPullRequests(ctx, genClient, name)
+	if err != nil {
+		ghs.logger.Sugar().Errorf("error getting pull requests for repo %s", zap.Error(err), name)
+	}
+
+	// Get the deployment data only if any of the deployment metrics is enabled
+	if !internal.DeploymentMetricsEnabled(ghs.cfg.MetricsBuilderConfig) {
+		return data
+	}
+	since := now.Add(-ghs.cfg.Deployments.Lookback)
+	deployments, err := ghs.getDeployments(ctx, genClient, name, since)
+	if err != nil {
+		ghs.logger.Sugar().Errorf("error getting deployments for repo %s", zap.Error(err), name)
+		return data
+	}
+
+	var merges []time.Time
+	for _, pr := range data.prs {
+		if pr.Merged {
+			merges = append(merges, pr.MergedAt)
+		}
+	}
+	data.deployments = internal.ComputeDeploymentMetrics(ghs.cfg.Deployments.Environments, deployments, merges, since)
+	return data
+}
+
 // scrape and return github metrics
 func (ghs *githubScraper) scrape(ctx context.Context) (pmetric.Metrics, error) {
 	if ghs.client == nil {
@@ -96,69 +159,60 @@ func (ghs *githubScraper) scrape(ctx context.Context) (pmetric.Metrics, error) {
 
 	ghs.mb.RecordGitRepositoryCountDataPoint(now, int64(count))
 
-	// Get the branch count (future branch data) for each repo and record the given metrics
+	// Get the data of each repository concurrently. The metrics are recorded
+	// afterwards as the metrics builder is not safe for concurrent use.
+	results := make([]repoData, len(repos))
 	var wg sync.WaitGroup
 
-	for _, repo := range repos {
-		repo := repo
-		name := repo.Name
-		trunk := repo.DefaultBranchRef.Name
+	for i := range repos {
+		i := i
 
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
+			results[i] = ghs.getRepoData(ctx, genClient, restClient, repos[i], now.AsTime())
+		}()
+	}
 
-			count, err := ghs.getBranches(ctx, genClient, name, trunk)
-			if err != nil {
-				ghs.logger.Sugar().Errorf("error getting branch count for repo %s", zap.Error(err), repo.Name)
-			}
-			ghs.mb.RecordGitRepositoryBranchCountDataPoint(now, int64(count), name)
+	wg.Wait()
 
-			// Get the contributor count for each of the repositories
-			contribs, err := ghs.getContributorCount(ctx, restClient, name)
-			if err != nil {
-				ghs.logger.Sugar().Errorf("error getting contributor count for repo %s", zap.Error(err), repo.Name)
-			}
-			ghs.mb.RecordGitRepositoryContributorCountDataPoint(now, int64(contribs), name)
+	for _, data := range results {
+		name := data.name
 
-			// Get Pull Request data
-			prs, err := ghs.getPullRequests(ctx, genClient, name)
-			if err != nil {
-				ghs.logger.Sugar().Errorf("error getting pull requests for repo %s", zap.Error(err), repo.Name)
-			}
+		ghs.mb.RecordGitRepositoryBranchCountDataPoint(now, int64(data.branches), name)
+		ghs.mb.RecordGitRepositoryContributorCountDataPoint(now, int64(data.contributors), name)
 
-			var merged int
-			var open int
+		var merged int
+		var open int
 
-			for _, pr := range prs {
-				if pr.Merged {
-					merged++
+		for _, pr := range data.prs {
+			if pr.Merged {
+				merged++
 
-					age := getAge(pr.CreatedAt, pr.MergedAt)
+				age := getAge(pr.CreatedAt, pr.MergedAt)
 
-					ghs.mb.RecordGitRepositoryPullRequestTimeToMergeDataPoint(now, age, name, pr.HeadRefName)
+				ghs.mb.RecordGitRepositoryPullRequestTimeToMergeDataPoint(now, age, name, pr.HeadRefName)
 
-				} else {
-					open++
+			} else {
+				open++
 
-					age := getAge(pr.CreatedAt, now.AsTime())
+				age := getAge(pr.CreatedAt, now.AsTime())
 
-					ghs.mb.RecordGitRepositoryPullRequestTimeOpenDataPoint(now, age, name, pr.HeadRefName)
+				ghs.mb.RecordGitRepositoryPullRequestTimeOpenDataPoint(now, age, name, pr.HeadRefName)
 
-					if pr.Reviews.TotalCount > 0 {
-						age := getAge(pr.CreatedAt, pr.Reviews.Nodes[0].CreatedAt)
+				if pr.Reviews.TotalCount > 0 {
+					age := getAge(pr.CreatedAt, pr.Reviews.Nodes[0].CreatedAt)
 
-						ghs.mb.RecordGitRepositoryPullRequestTimeToApprovalDataPoint(now, age, name, pr.HeadRefName)
-					}
+					ghs.mb.RecordGitRepositoryPullRequestTimeToApprovalDataPoint(now, age, name, pr.HeadRefName)
 				}
 			}
+		}
 
-			ghs.mb.RecordGitRepositoryPullRequestCountDataPoint(now, int64(open), metadata.AttributePullRequestStateOpen, name)
-			ghs.mb.RecordGitRepositoryPullRequestCountDataPoint(now, int64(merged), metadata.AttributePullRequestStateMerged, name)
-		}()
-	}
+		ghs.mb.RecordGitRepositoryPullRequestCountDataPoint(now, int64(open), metadata.AttributePullRequestStateOpen, name)
+		ghs.mb.RecordGitRepositoryPullRequestCountDataPoint(now, int64(merged), metadata.AttributePullRequestStateMerged, name)
 
-	wg.Wait()
+		internal.RecordDeploymentMetrics(ghs.mb, now, name, data.deployments)
+	}
 
 	// Set the resource attributes and emit metrics with those resources
 	ghs.rb.SetGitVendorName("github")
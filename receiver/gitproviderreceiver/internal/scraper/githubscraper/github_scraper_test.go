@@ -2,3 +2,129 @@
 // SPDX-License-Identifier: Apache-2.0
 
 package githubscraper // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/gitproviderreceiver"
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Khan/genqlient/graphql"
+	"github.com/google/go-github/v61/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+)
+
+const scrapeRepoCount = 10
+
+// newScrapeServer serves the same responses to every request, so that the
+// data of several repositories can be requested concurrently. The branch and
+// contributor counts of repoN are N.
+func newScrapeServer(t *testing.T) *httptest.Server {
+	mergedAt := time.Now().Add(-time.Hour)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/graphql", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			OpName    string         `json:"operationName"`
+			Variables map[string]any `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		var data any
+		switch req.OpName {
+		case "checkLogin":
+			data = &checkLoginResponse{Organization: checkLoginOrganization{Login: "liatrio"}}
+		case "getRepoDataBySearch":
+			search := getRepoDataBySearchSearchSearchResultItemConnection{RepositoryCount: scrapeRepoCount}
+			for i := 1; i <= scrapeRepoCount; i++ {
+				search.Nodes = append(search.Nodes, &SearchNodeRepository{
+					Typename:         "Repository",
+					Name:             fmt.Sprintf("repo%d", i),
+					DefaultBranchRef: SearchNodeDefaultBranchRef{Name: "main"},
+				})
+			}
+			data = &getRepoDataBySearchResponse{Search: search}
+		case "getBranchData":
+			count, _ := strconv.Atoi(strings.TrimPrefix(req.Variables["name"].(string), "repo"))
+			data = &getBranchDataResponse{Repository: getBranchDataRepository{
+				Refs: getBranchDataRepositoryRefsRefConnection{TotalCount: count},
+			}}
+		case "getPullRequestData":
+			data = &getPullRequestDataResponse{Repository: getPullRequestDataRepository{
+				PullRequests: getPullRequestDataRepositoryPullRequestsPullRequestConnection{
+					Nodes: []PullRequestNode{
+						{CreatedAt: mergedAt.Add(-time.Hour), Merged: true, MergedAt: mergedAt, HeadRefName: "merged"},
+						{CreatedAt: mergedAt, HeadRefName: "open"},
+					},
+				},
+			}}
+		case "getDeploymentData":
+			data = &getDeploymentDataResponse{}
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(graphql.Response{Data: data})
+	})
+	mux.HandleFunc("/api/v3/repos/liatrio/", func(w http.ResponseWriter, r *http.Request) {
+		name := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/v3/repos/liatrio/"), "/contributors")
+		count, _ := strconv.Atoi(strings.TrimPrefix(name, "repo"))
+		_ = json.NewEncoder(w).Encode(make([]*github.Contributor, count))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestScrapeRepositories(t *testing.T) {
+	server := newScrapeServer(t)
+	cfg := (&Factory{}).CreateDefaultConfig().(*Config)
+	cfg.GitHubOrg = "liatrio"
+	cfg.Endpoint = server.URL
+	cfg.Metrics.GitRepositoryContributorCount.Enabled = true
+	cfg.Metrics.GitRepositoryDeploymentCount.Enabled = true
+
+	ghs := newGitHubScraper(context.Background(), receivertest.NewNopCreateSettings(), cfg)
+	require.NoError(t, ghs.start(context.Background(), componenttest.NewNopHost()))
+
+	md, err := ghs.scrape(context.Background())
+	require.NoError(t, err)
+
+	branches := map[string]int64{}
+	contributors := map[string]int64{}
+	prCounts := map[string]int{}
+	metrics := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	for i := 0; i < metrics.Len(); i++ {
+		m := metrics.At(i)
+		dps := m.Gauge().DataPoints()
+		for j := 0; j < dps.Len(); j++ {
+			name, _ := dps.At(j).Attributes().Get("repository.name")
+			switch m.Name() {
+			case "git.repository.branch.count":
+				branches[name.Str()] = dps.At(j).IntValue()
+			case "git.repository.contributor.count":
+				contributors[name.Str()] = dps.At(j).IntValue()
+			case "git.repository.pull_request.count":
+				prCounts[name.Str()]++
+			}
+		}
+	}
+
+	require.Len(t, branches, scrapeRepoCount)
+	for i := 1; i <= scrapeRepoCount; i++ {
+		name := fmt.Sprintf("repo%d", i)
+		assert.Equal(t, int64(i), branches[name], name)
+		assert.Equal(t, int64(i), contributors[name], name)
+		assert.Equal(t, 2, prCounts[name], name)
+	}
+}
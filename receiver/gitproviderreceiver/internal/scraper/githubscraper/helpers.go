@@ -12,6 +12,8 @@ import (
 	"github.com/Khan/genqlient/graphql"
 	"github.com/google/go-github/v61/github"
 	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/gitproviderreceiver/internal"
 )
 
 const (
@@ -206,6 +208,71 @@ func (ghs *githubScraper) getPullRequests(
 	return pullRequests, nil
 }
 
+// Get the finished deployments of the configured environments from the GraphQL
+// API. Deployments are returned newest first, so the pagination stops at the
+// first page holding a deployment created before the given time.
+func (ghs *githubScraper) getDeployments(
+	ctx context.Context,
+	client graphql.Client,
+	repoName string,
+	since time.Time,
+) ([]internal.Deployment, error) {
+	var cursor *string
+	var deployments []internal.Deployment
+
+	for next := true; next; {
+		r, err := getDeploymentData(
+			ctx,
+			client,
+			repoName,
+			ghs.cfg.GitHubOrg,
+			100,
+			ghs.cfg.Deployments.Environments,
+			cursor,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		nodes := r.Repository.Deployments.Nodes
+		for _, node := range nodes {
+			if d, ok := finishedDeployment(node); ok {
+				deployments = append(deployments, d)
+			}
+		}
+
+		cursor = &r.Repository.Deployments.PageInfo.EndCursor
+		next = r.Repository.Deployments.PageInfo.HasNextPage &&
+			len(nodes) > 0 && !nodes[len(nodes)-1].CreatedAt.Before(since)
+	}
+
+	return deployments, nil
+}
+
+// finishedDeployment returns the outcome of a deployment from its first
+// successful, failed or errored status. Later statuses, such as a successful
+// deployment becoming inactive when superseded, are ignored. Deployments that
+// have not finished yet are not returned.
+func finishedDeployment(node DeploymentNode) (internal.Deployment, bool) {
+	var finished *DeploymentNodeStatusesDeploymentStatusConnectionNodesDeploymentStatus
+	for i, status := range node.Statuses.Nodes {
+		switch status.State {
+		case DeploymentStatusStateSuccess, DeploymentStatusStateFailure, DeploymentStatusStateError:
+			if finished == nil || status.CreatedAt.Before(finished.CreatedAt) {
+				finished = &node.Statuses.Nodes[i]
+			}
+		}
+	}
+	if finished == nil {
+		return internal.Deployment{}, false
+	}
+	return internal.Deployment{
+		Environment: node.Environment,
+		FinishedAt:  finished.CreatedAt,
+		Failed:      finished.State != DeploymentStatusStateSuccess,
+	}, true
+}
+
 // Get the age/duration between two times in seconds.
 func getAge(start time.Time, end time.Time) int64 {
 	return int64(end.Sub(start).Seconds())
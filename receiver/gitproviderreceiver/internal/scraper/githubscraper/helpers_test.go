@@ -18,6 +18,8 @@ import (
 	"github.com/google/go-github/v61/github"
 	"github.com/stretchr/testify/assert"
 	"go.opentelemetry.io/collector/receiver/receivertest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/gitproviderreceiver/internal"
 )
 
 type responses struct {
@@ -26,6 +28,7 @@ type responses struct {
 	branchResponse     branchResponse
 	checkLoginResponse loginResponse
 	contribResponse    contribResponse
+	deploymentResponse deploymentResponse
 	scrape             bool
 }
 
@@ -47,6 +50,12 @@ type branchResponse struct {
 	page         int
 }
 
+type deploymentResponse struct {
+	deployments  []getDeploymentDataRepositoryDeploymentsDeploymentConnection
+	responseCode int
+	page         int
+}
+
 type loginResponse struct {
 	checkLogin   checkLoginResponse
 	responseCode int
@@ -126,6 +135,21 @@ func MockServer(responses *responses) *http.ServeMux {
 				}
 				prResp.page++
 			}
+		case reqBody.OpName == "getDeploymentData":
+			deploymentResp := &responses.deploymentResponse
+			w.WriteHeader(deploymentResp.responseCode)
+			if deploymentResp.responseCode == http.StatusOK {
+				deployments := getDeploymentDataResponse{
+					Repository: getDeploymentDataRepository{
+						Deployments: deploymentResp.deployments[deploymentResp.page],
+					},
+				}
+				graphqlResponse := graphql.Response{Data: &deployments}
+				if err := json.NewEncoder(w).Encode(graphqlResponse); err != nil {
+					return
+				}
+				deploymentResp.page++
+			}
 		}
 	})
 	mux.HandleFunc(restEndpoint, func(w http.ResponseWriter, _ *http.Request) {
@@ -403,6 +427,127 @@ func TestGetPullRequests(t *testing.T) {
 	}
 }
 
+func TestGetDeployments(t *testing.T) {
+	since := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	deployment := func(createdAt time.Time, states ...DeploymentStatusState) DeploymentNode {
+		node := DeploymentNode{CreatedAt: createdAt, Environment: "production"}
+		// statuses are returned newest first
+		for i := len(states) - 1; i >= 0; i-- {
+			node.Statuses.Nodes = append(node.Statuses.Nodes, DeploymentNodeStatusesDeploymentStatusConnectionNodesDeploymentStatus{
+				State:     states[i],
+				CreatedAt: createdAt.Add(time.Duration(i+1) * time.Minute),
+			})
+		}
+		return node
+	}
+
+	testCases := []struct {
+		desc                string
+		server              *http.ServeMux
+		expectedErr         error
+		expectedDeployments []internal.Deployment
+	}{
+		{
+			desc: "TestSinglePageResponse",
+			server: MockServer(&responses{
+				scrape: false,
+				deploymentResponse: deploymentResponse{
+					deployments: []getDeploymentDataRepositoryDeploymentsDeploymentConnection{
+						{
+							PageInfo: getDeploymentDataRepositoryDeploymentsDeploymentConnectionPageInfo{
+								HasNextPage: false,
+							},
+							Nodes: []DeploymentNode{
+								deployment(since.Add(3*time.Hour), DeploymentStatusStateInProgress),
+								deployment(since.Add(2*time.Hour), DeploymentStatusStateInProgress, DeploymentStatusStateError),
+								deployment(since.Add(time.Hour), DeploymentStatusStateQueued, DeploymentStatusStateSuccess, DeploymentStatusStateInactive),
+							},
+						},
+					},
+					responseCode: http.StatusOK,
+				},
+			}),
+			expectedErr: nil,
+			expectedDeployments: []internal.Deployment{
+				{Environment: "production", FinishedAt: since.Add(2*time.Hour + 2*time.Minute), Failed: true},
+				{Environment: "production", FinishedAt: since.Add(time.Hour + 2*time.Minute)},
+			},
+		},
+		{
+			desc: "TestMultiPageResponseStopsBeforeLookback",
+			server: MockServer(&responses{
+				scrape: false,
+				deploymentResponse: deploymentResponse{
+					deployments: []getDeploymentDataRepositoryDeploymentsDeploymentConnection{
+						{
+							PageInfo: getDeploymentDataRepositoryDeploymentsDeploymentConnectionPageInfo{
+								HasNextPage: true,
+							},
+							Nodes: []DeploymentNode{
+								deployment(since.Add(time.Hour), DeploymentStatusStateSuccess),
+							},
+						},
+						{
+							PageInfo: getDeploymentDataRepositoryDeploymentsDeploymentConnectionPageInfo{
+								HasNextPage: true,
+							},
+							Nodes: []DeploymentNode{
+								deployment(since.Add(-time.Hour), DeploymentStatusStateFailure),
+							},
+						},
+						{
+							PageInfo: getDeploymentDataRepositoryDeploymentsDeploymentConnectionPageInfo{
+								HasNextPage: false,
+							},
+							Nodes: []DeploymentNode{
+								deployment(since.Add(-2*time.Hour), DeploymentStatusStateSuccess),
+							},
+						},
+					},
+					responseCode: http.StatusOK,
+				},
+			}),
+			expectedErr: nil,
+			expectedDeployments: []internal.Deployment{
+				{Environment: "production", FinishedAt: since.Add(time.Hour + time.Minute)},
+				{Environment: "production", FinishedAt: since.Add(-time.Hour + time.Minute), Failed: true},
+			},
+		},
+		{
+			desc: "Test404Response",
+			server: MockServer(&responses{
+				scrape: false,
+				deploymentResponse: deploymentResponse{
+					responseCode: http.StatusNotFound,
+				},
+			}),
+			expectedErr: errors.New("returned error 404 Not Found: "),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			factory := Factory{}
+			defaultConfig := factory.CreateDefaultConfig()
+			settings := receivertest.NewNopCreateSettings()
+			ghs := newGitHubScraper(context.Background(), settings, defaultConfig.(*Config))
+			server := httptest.NewServer(tc.server)
+			defer server.Close()
+			client := graphql.NewClient(server.URL, ghs.client)
+
+			deployments, err := ghs.getDeployments(context.Background(), client, "repo name", since)
+
+			assert.Equal(t, tc.expectedDeployments, deployments)
+			if tc.expectedErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expectedErr.Error())
+			}
+		})
+	}
+}
+
 func TestGetRepos(t *testing.T) {
 	testCases := []struct {
 		desc        string
@@ -0,0 +1,32 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package gitlabscraper // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/gitproviderreceiver/internal/scraper/gitlabscraper"
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/collector/config/confighttp"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/gitproviderreceiver/internal"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/gitproviderreceiver/internal/metadata"
+)
+
+// Config relating to GitLab Metric Scraper.
+type Config struct {
+	metadata.MetricsBuilderConfig `mapstructure:",squash"`
+	confighttp.ClientConfig       `mapstructure:",squash"`
+	internal.ScraperConfig
+	// GitLabOrg is the full path of the GitLab group to scrape, including its subgroups
+	GitLabOrg string `mapstructure:"gitlab_org"`
+	// Deployments defines the deployments used to compute the deployment metrics
+	Deployments internal.DeploymentsConfig `mapstructure:"deployments"`
+}
+
+// Validate checks the GitLab group to scrape is set.
+func (cfg *Config) Validate() error {
+	if cfg.GitLabOrg == "" {
+		return errors.New("gitlab_org must be specified")
+	}
+	return nil
+}
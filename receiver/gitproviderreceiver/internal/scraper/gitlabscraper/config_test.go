@@ -0,0 +1,44 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package gitlabscraper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/config/confighttp"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/gitproviderreceiver/internal"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/gitproviderreceiver/internal/metadata"
+)
+
+// TestConfig ensures a config created with the factory is the same as one created manually with
+// the exported Config struct.
+func TestConfig(t *testing.T) {
+	factory := Factory{}
+	defaultConfig := factory.CreateDefaultConfig()
+
+	expectedConfig := &Config{
+		MetricsBuilderConfig: metadata.DefaultMetricsBuilderConfig(),
+		ClientConfig: confighttp.ClientConfig{
+			Endpoint: "https://gitlab.com",
+			Timeout:  15 * time.Second,
+		},
+		Deployments: internal.DeploymentsConfig{
+			Environments: []string{"production"},
+			Lookback:     7 * 24 * time.Hour,
+		},
+	}
+
+	assert.Equal(t, expectedConfig, defaultConfig)
+}
+
+func TestConfigValidate(t *testing.T) {
+	cfg := (&Factory{}).CreateDefaultConfig().(*Config)
+	assert.EqualError(t, cfg.Validate(), "gitlab_org must be specified")
+
+	cfg.GitLabOrg = "liatrio"
+	assert.NoError(t, cfg.Validate())
+}
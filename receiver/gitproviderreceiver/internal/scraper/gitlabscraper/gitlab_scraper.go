@@ -0,0 +1,190 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package gitlabscraper // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/gitproviderreceiver/internal/scraper/gitlabscraper"
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/receiver"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/gitproviderreceiver/internal"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/gitproviderreceiver/internal/metadata"
+)
+
+// The maximum number of projects whose data is requested at the same time,
+// to stay within the rate limits of the GitLab API.
+const maxConcurrentProjects = 8
+
+var errClientNotInitErr = errors.New("http client not initialized")
+
+type gitlabScraper struct {
+	client   *http.Client
+	cfg      *Config
+	settings component.TelemetrySettings
+	logger   *zap.Logger
+	mb       *metadata.MetricsBuilder
+	rb       *metadata.ResourceBuilder
+}
+
+func (gls *gitlabScraper) start(ctx context.Context, host component.Host) (err error) {
+	gls.logger.Sugar().Info("starting the GitLab scraper")
+	gls.client, err = gls.cfg.ToClient(ctx, host, gls.settings)
+	return
+}
+
+func newGitLabScraper(
+	_ context.Context,
+	settings receiver.CreateSettings,
+	cfg *Config,
+) *gitlabScraper {
+	return &gitlabScraper{
+		cfg:      cfg,
+		settings: settings.TelemetrySettings,
+		logger:   settings.Logger,
+		mb:       metadata.NewMetricsBuilder(cfg.MetricsBuilderConfig, settings),
+		rb:       metadata.NewResourceBuilder(cfg.ResourceAttributes),
+	}
+}
+
+// projectData holds the data of a project the metrics are recorded from.
+type projectData struct {
+	project      project
+	branches     int
+	contributors int
+	opened       []mergeRequest
+	merged       []mergeRequest
+	deployments  []internal.DeploymentMetrics
+}
+
+// getProjectData gets the data of a project. Failures to get some of the data
+// are logged and the remaining data is still returned. The merge requests
+// merged and the deployments finished before the lookback window are ignored.
+func (gls *gitlabScraper) getProjectData(ctx context.Context, p project, now time.Time) projectData {
+	data := projectData{project: p}
+	name := p.PathWithNamespace
+	since := now.Add(-gls.cfg.Deployments.Lookback)
+
+	var err error
+	data.branches, err = gls.getBranchCount(ctx, p.ID)
+	if err != nil {
+		gls.logger.Sugar().Errorf("error getting branch count for project %s", zap.Error(err), name)
+	}
+
+	data.contributors, err = gls.getContributorCount(ctx, p.ID)
+	if err != nil {
+		gls.logger.Sugar().Errorf("error getting contributor count for project %s", zap.Error(err), name)
+	}
+
+	// Get Merge Request data
+	data.opened, err = gls.getOpenedMergeRequests(ctx, p.ID)
+	if err != nil {
+		gls.logger.Sugar().Errorf("error getting open merge requests for project %s", zap.Error(err), name)
+	}
+
+	data.merged, err = gls.getMergedMergeRequests(ctx, p.ID, since)
+	if err != nil {
+		gls.logger.Sugar().Errorf("error getting merged merge requests for project %s", zap.Error(err), name)
+	}
+
+	// Get the deployment data only if any of the deployment metrics is enabled
+	if !internal.DeploymentMetricsEnabled(gls.cfg.MetricsBuilderConfig) {
+		return data
+	}
+
+	environments, err := gls.getEnvironments(ctx, p.ID)
+	if err != nil {
+		gls.logger.Sugar().Errorf("error getting environments for project %s", zap.Error(err), name)
+		return data
+	}
+
+	deployments, err := gls.getDeployments(ctx, p.ID, environments, since)
+	if err != nil {
+		gls.logger.Sugar().Errorf("error getting deployments for project %s", zap.Error(err), name)
+		return data
+	}
+
+	var merges []time.Time
+	for _, mr := range data.merged {
+		merges = append(merges, *mr.MergedAt)
+	}
+	data.deployments = internal.ComputeDeploymentMetrics(environments, deployments, merges, since)
+	return data
+}
+
+// scrape and return gitlab metrics
+func (gls *gitlabScraper) scrape(ctx context.Context) (pmetric.Metrics, error) {
+	if gls.client == nil {
+		return pmetric.NewMetrics(), errClientNotInitErr
+	}
+
+	now := pcommon.NewTimestampFromTime(time.Now())
+
+	// Get the projects of the group and its subgroups and record the total count
+	// of repositories
+	projects, err := gls.getProjects(ctx)
+	if err != nil {
+		gls.logger.Sugar().Errorf("error getting projects", zap.Error(err))
+		return gls.mb.Emit(), err
+	}
+
+	gls.mb.RecordGitRepositoryCountDataPoint(now, int64(len(projects)))
+
+	// Get the data of the projects concurrently, up to maxConcurrentProjects
+	// at a time. The metrics are recorded afterwards as the metrics builder is
+	// not safe for concurrent use.
+	results := make([]projectData, len(projects))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxConcurrentProjects)
+
+	for i := range projects {
+		i := i
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			results[i] = gls.getProjectData(ctx, projects[i], now.AsTime())
+		}()
+	}
+
+	wg.Wait()
+
+	for _, data := range results {
+		name := data.project.PathWithNamespace
+
+		gls.mb.RecordGitRepositoryBranchCountDataPoint(now, int64(data.branches), name)
+		gls.mb.RecordGitRepositoryContributorCountDataPoint(now, int64(data.contributors), name)
+
+		for _, mr := range data.opened {
+			age := getAge(mr.CreatedAt, now.AsTime())
+			gls.mb.RecordGitRepositoryPullRequestTimeOpenDataPoint(now, age, name, mr.SourceBranch)
+		}
+		for _, mr := range data.merged {
+			age := getAge(mr.CreatedAt, *mr.MergedAt)
+			gls.mb.RecordGitRepositoryPullRequestTimeToMergeDataPoint(now, age, name, mr.SourceBranch)
+		}
+		gls.mb.RecordGitRepositoryPullRequestCountDataPoint(now, int64(len(data.opened)), metadata.AttributePullRequestStateOpen, name)
+		gls.mb.RecordGitRepositoryPullRequestCountDataPoint(now, int64(len(data.merged)), metadata.AttributePullRequestStateMerged, name)
+
+		internal.RecordDeploymentMetrics(gls.mb, now, name, data.deployments)
+	}
+
+	// Set the resource attributes and emit metrics with those resources
+	gls.rb.SetGitVendorName("gitlab")
+	gls.rb.SetOrganizationName(gls.cfg.GitLabOrg)
+
+	res := gls.rb.Emit()
+	return gls.mb.Emit(metadata.WithResource(res)), nil
+}
@@ -0,0 +1,108 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package gitlabscraper
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/gitproviderreceiver/internal/metadata"
+)
+
+func TestScrape(t *testing.T) {
+	server := newMockServer(t)
+	gls := newTestScraper(t, server.URL, "liatrio")
+	gls.cfg.Metrics.GitRepositoryContributorCount.Enabled = true
+	gls.cfg.Metrics.GitRepositoryDeploymentCount.Enabled = true
+	gls.cfg.Metrics.GitRepositoryDeploymentLeadTime.Enabled = true
+	gls.cfg.Metrics.GitRepositoryDeploymentChangeFailureRate.Enabled = true
+	gls.cfg.Metrics.GitRepositoryDeploymentTimeToRestore.Enabled = true
+	// include all the recorded deployments in the lookback window
+	gls.cfg.Deployments.Lookback = time.Since(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC))
+	gls.mb = metadata.NewMetricsBuilder(gls.cfg.MetricsBuilderConfig, receivertest.NewNopCreateSettings())
+
+	md, err := gls.scrape(context.Background())
+	require.NoError(t, err)
+
+	require.Equal(t, 1, md.ResourceMetrics().Len())
+	attrs := md.ResourceMetrics().At(0).Resource().Attributes().AsRaw()
+	assert.Equal(t, map[string]any{"git.vendor.name": "gitlab", "organization.name": "liatrio"}, attrs)
+
+	values := map[string]any{}
+	metrics := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	for i := 0; i < metrics.Len(); i++ {
+		m := metrics.At(i)
+		dps := m.Gauge().DataPoints()
+		for j := 0; j < dps.Len(); j++ {
+			dp := dps.At(j)
+			key := m.Name()
+			for _, attr := range []string{"repository.name", "deployment.environment", "branch.name", "pull_request.state"} {
+				if v, ok := dp.Attributes().Get(attr); ok {
+					key += "/" + v.Str()
+				}
+			}
+			if m.Name() == "git.repository.pull_request.time_open" {
+				// depends on the current time
+				values[key] = dp.IntValue() > 0
+				continue
+			}
+			if dp.ValueType() == pmetric.NumberDataPointValueTypeDouble {
+				values[key] = dp.DoubleValue()
+			} else {
+				values[key] = dp.IntValue()
+			}
+		}
+	}
+
+	assert.Equal(t, map[string]any{
+		"git.repository.count":                                                        int64(2),
+		"git.repository.branch.count/liatrio/api":                                     int64(3),
+		"git.repository.branch.count/liatrio/frontend/web":                            int64(1),
+		"git.repository.contributor.count/liatrio/api":                                int64(2),
+		"git.repository.contributor.count/liatrio/frontend/web":                       int64(1),
+		"git.repository.pull_request.count/liatrio/api/open":                          int64(1),
+		"git.repository.pull_request.count/liatrio/api/merged":                        int64(3),
+		"git.repository.pull_request.count/liatrio/frontend/web/open":                 int64(0),
+		"git.repository.pull_request.count/liatrio/frontend/web/merged":               int64(0),
+		"git.repository.pull_request.time_open/liatrio/api/feature/checkout":          true,
+		"git.repository.pull_request.time_to_merge/liatrio/api/feature/payment-retry": int64(9000),
+		"git.repository.pull_request.time_to_merge/liatrio/api/fix/cart-total":        int64(58740),
+		"git.repository.pull_request.time_to_merge/liatrio/api/feature/cart":          int64(86400),
+		// 3 successful and 2 failed deployments to production, one of them
+		// because of its pipeline
+		"git.repository.deployment.count/liatrio/api/production":               int64(3),
+		"git.repository.deployment.change_failure_rate/liatrio/api/production": 0.4,
+		// merged at 04-01 09:00, 04-02 09:00 and 04-03 10:30 and deployed at
+		// 04-01 10:00, 04-02 12:00 and 04-03 11:00
+		"git.repository.deployment.lead_time/liatrio/api/production": int64(3600),
+		// failed at 04-02 10:00 and 04-03 10:00 and restored 2 and 1 hours later
+		"git.repository.deployment.time_to_restore/liatrio/api/production":  int64(5400),
+		"git.repository.deployment.count/liatrio/api/staging":               int64(1),
+		"git.repository.deployment.change_failure_rate/liatrio/api/staging": 0.0,
+		// merged at 04-01 09:00 and deployed at 04-01 09:30
+		"git.repository.deployment.lead_time/liatrio/api/staging": int64(1800),
+	}, values)
+}
+
+func TestScrapeNoClient(t *testing.T) {
+	gls := newGitLabScraper(context.Background(), receivertest.NewNopCreateSettings(), (&Factory{}).CreateDefaultConfig().(*Config))
+
+	_, err := gls.scrape(context.Background())
+	assert.ErrorIs(t, err, errClientNotInitErr)
+}
+
+func TestScrapeError(t *testing.T) {
+	server := newMockServer(t)
+	gls := newTestScraper(t, server.URL, "unknown")
+
+	md, err := gls.scrape(context.Background())
+	assert.EqualError(t, err, "GET /api/v4/groups/unknown/projects returned 404 Not Found")
+	assert.Equal(t, 0, md.DataPointCount())
+}
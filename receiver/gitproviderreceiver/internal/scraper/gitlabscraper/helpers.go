@@ -0,0 +1,251 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package gitlabscraper // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/gitproviderreceiver/internal/scraper/gitlabscraper"
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/gitproviderreceiver/internal"
+)
+
+const (
+	// The path of the GitLab REST API relative to the endpoint
+	apiPath = "api/v4"
+	// The maximum page size allowed by the GitLab REST API
+	perPage = 100
+
+	mergeRequestStateOpened = "opened"
+	mergeRequestStateMerged = "merged"
+
+	deploymentStatusSuccess = "success"
+	deploymentStatusFailed  = "failed"
+	pipelineStatusFailed    = "failed"
+)
+
+type project struct {
+	ID                int    `json:"id"`
+	Name              string `json:"name"`
+	PathWithNamespace string `json:"path_with_namespace"`
+}
+
+type branch struct {
+	Name string `json:"name"`
+}
+
+type contributor struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+type mergeRequest struct {
+	CreatedAt    time.Time  `json:"created_at"`
+	MergedAt     *time.Time `json:"merged_at"`
+	SourceBranch string     `json:"source_branch"`
+}
+
+type environment struct {
+	Name string `json:"name"`
+}
+
+type deployment struct {
+	Status      string      `json:"status"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+	Environment environment `json:"environment"`
+	Deployable  *deployable `json:"deployable"`
+}
+
+type deployable struct {
+	Status     string     `json:"status"`
+	FinishedAt *time.Time `json:"finished_at"`
+	Pipeline   struct {
+		Status string `json:"status"`
+	} `json:"pipeline"`
+}
+
+// Get a single page of a GitLab REST API resource, decoding it into out and
+// returning the next page, which is empty on the last page.
+// https://docs.gitlab.com/ee/api/rest/#pagination
+func (gls *gitlabScraper) getPage(
+	ctx context.Context,
+	path string,
+	query url.Values,
+	out any,
+) (string, error) {
+	u, err := url.Parse(strings.TrimSuffix(gls.cfg.Endpoint, "/") + "/" + apiPath + "/" + path)
+	if err != nil {
+		return "", err
+	}
+	u.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := gls.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("GET %s returned %s", u.Path, resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return "", fmt.Errorf("failed to decode the response of GET %s: %w", u.Path, err)
+	}
+	return resp.Header.Get("X-Next-Page"), nil
+}
+
+// Get all the pages of a GitLab REST API resource.
+func getAll[T any](
+	ctx context.Context,
+	gls *gitlabScraper,
+	path string,
+	query url.Values,
+) ([]T, error) {
+	var all []T
+
+	if query == nil {
+		query = url.Values{}
+	}
+	query.Set("per_page", strconv.Itoa(perPage))
+
+	for page := "1"; page != ""; {
+		query.Set("page", page)
+
+		var items []T
+		next, err := gls.getPage(ctx, path, query, &items)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, items...)
+		page = next
+	}
+
+	return all, nil
+}
+
+// Get the non archived projects of the group and of its subgroups.
+func (gls *gitlabScraper) getProjects(ctx context.Context) ([]project, error) {
+	return getAll[project](ctx, gls, "groups/"+url.PathEscape(gls.cfg.GitLabOrg)+"/projects", url.Values{
+		"include_subgroups": []string{"true"},
+		"archived":          []string{"false"},
+	})
+}
+
+func (gls *gitlabScraper) getBranchCount(ctx context.Context, projectID int) (int, error) {
+	branches, err := getAll[branch](ctx, gls, fmt.Sprintf("projects/%d/repository/branches", projectID), nil)
+	return len(branches), err
+}
+
+func (gls *gitlabScraper) getContributorCount(ctx context.Context, projectID int) (int, error) {
+	contribs, err := getAll[contributor](ctx, gls, fmt.Sprintf("projects/%d/repository/contributors", projectID), nil)
+	return len(contribs), err
+}
+
+func (gls *gitlabScraper) getOpenedMergeRequests(ctx context.Context, projectID int) ([]mergeRequest, error) {
+	return getAll[mergeRequest](ctx, gls, fmt.Sprintf("projects/%d/merge_requests", projectID), url.Values{
+		"state": []string{mergeRequestStateOpened},
+	})
+}
+
+// Get the merge requests merged since the given time. A merge request is
+// updated when it is merged, so only the ones updated since then are listed
+// instead of the whole history of the project.
+func (gls *gitlabScraper) getMergedMergeRequests(ctx context.Context, projectID int, since time.Time) ([]mergeRequest, error) {
+	mrs, err := getAll[mergeRequest](ctx, gls, fmt.Sprintf("projects/%d/merge_requests", projectID), url.Values{
+		"state":         []string{mergeRequestStateMerged},
+		"updated_after": []string{since.UTC().Format(time.RFC3339)},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	merged := mrs[:0]
+	for _, mr := range mrs {
+		if mr.MergedAt != nil && !mr.MergedAt.Before(since) {
+			merged = append(merged, mr)
+		}
+	}
+	return merged, nil
+}
+
+// Get the names of the configured environments that exist in the project, so
+// that projects without deployments don't report deployment metrics.
+func (gls *gitlabScraper) getEnvironments(ctx context.Context, projectID int) ([]string, error) {
+	environments, err := getAll[environment](ctx, gls, fmt.Sprintf("projects/%d/environments", projectID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, env := range gls.cfg.Deployments.Environments {
+		for _, e := range environments {
+			if e.Name == env {
+				names = append(names, env)
+				break
+			}
+		}
+	}
+	return names, nil
+}
+
+// Get the finished deployments to the given environments updated since the
+// given time. A deployment is failed if either the deployment or the pipeline
+// it is part of failed, so that failures of post deployment jobs count too.
+func (gls *gitlabScraper) getDeployments(
+	ctx context.Context,
+	projectID int,
+	environments []string,
+	since time.Time,
+) ([]internal.Deployment, error) {
+	var deployments []internal.Deployment
+
+	for _, env := range environments {
+		ds, err := getAll[deployment](ctx, gls, fmt.Sprintf("projects/%d/deployments", projectID), url.Values{
+			"environment":   []string{env},
+			"updated_after": []string{since.UTC().Format(time.RFC3339)},
+			"order_by":      []string{"updated_at"},
+			"sort":          []string{"asc"},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, d := range ds {
+			if d.Status != deploymentStatusSuccess && d.Status != deploymentStatusFailed {
+				continue
+			}
+
+			finishedAt := d.UpdatedAt
+			failed := d.Status == deploymentStatusFailed
+			if d.Deployable != nil {
+				if d.Deployable.FinishedAt != nil {
+					finishedAt = *d.Deployable.FinishedAt
+				}
+				failed = failed || d.Deployable.Pipeline.Status == pipelineStatusFailed
+			}
+
+			deployments = append(deployments, internal.Deployment{
+				Environment: env,
+				FinishedAt:  finishedAt,
+				Failed:      failed,
+			})
+		}
+	}
+
+	return deployments, nil
+}
+
+// Get the age/duration between two times in seconds.
+func getAge(start time.Time, end time.Time) int64 {
+	return int64(end.Sub(start).Seconds())
+}
@@ -0,0 +1,187 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package gitlabscraper
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/gitproviderreceiver/internal"
+)
+
+// fixtures are the pages of the recorded GitLab API responses in testdata,
+// keyed by the request path and its state or environment parameter. The
+// merged merge requests must be requested with the updated_after parameter.
+var fixtures = map[string][]string{
+	"/api/v4/groups/liatrio/projects":                       {"projects_page1.json", "projects_page2.json"},
+	"/api/v4/projects/1/repository/branches":                {"api_branches.json"},
+	"/api/v4/projects/2/repository/branches":                {"web_branches.json"},
+	"/api/v4/projects/1/repository/contributors":            {"api_contributors.json"},
+	"/api/v4/projects/2/repository/contributors":            {"web_contributors.json"},
+	"/api/v4/projects/1/merge_requests?state=opened":        {"api_merge_requests_opened.json"},
+	"/api/v4/projects/1/merge_requests?state=merged":        {"api_merge_requests_merged.json"},
+	"/api/v4/projects/2/merge_requests?state=opened":        {"empty.json"},
+	"/api/v4/projects/2/merge_requests?state=merged":        {"empty.json"},
+	"/api/v4/projects/1/environments":                       {"api_environments.json"},
+	"/api/v4/projects/2/environments":                       {"web_environments.json"},
+	"/api/v4/projects/1/deployments?environment=production": {"api_deployments_production.json"},
+	"/api/v4/projects/1/deployments?environment=staging":    {"api_deployments_staging.json"},
+}
+
+// newMockServer serves the recorded GitLab API responses, paginated as the
+// GitLab API does with the page parameter and the X-Next-Page header.
+func newMockServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		key := r.URL.Path
+		for _, param := range []string{"state", "environment"} {
+			if value := query.Get(param); value != "" {
+				key += "?" + param + "=" + value
+			}
+		}
+
+		pages, ok := fixtures[key]
+		if query.Get("state") == mergeRequestStateMerged && query.Get("updated_after") == "" {
+			ok = false
+		}
+		page, err := strconv.Atoi(query.Get("page"))
+		if !ok || err != nil || page < 1 || page > len(pages) || query.Get("per_page") != "100" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		body, err := os.ReadFile(filepath.Join("testdata", pages[page-1]))
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if page < len(pages) {
+			w.Header().Set("X-Next-Page", strconv.Itoa(page+1))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(body)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newTestScraper(t *testing.T, endpoint string, group string) *gitlabScraper {
+	cfg := (&Factory{}).CreateDefaultConfig().(*Config)
+	cfg.Endpoint = endpoint
+	cfg.GitLabOrg = group
+	cfg.Deployments.Environments = []string{"production", "staging"}
+
+	gls := newGitLabScraper(context.Background(), receivertest.NewNopCreateSettings(), cfg)
+	require.NoError(t, gls.start(context.Background(), componenttest.NewNopHost()))
+	return gls
+}
+
+func TestGetProjects(t *testing.T) {
+	server := newMockServer(t)
+
+	testCases := []struct {
+		desc             string
+		group            string
+		expectedErr      string
+		expectedProjects []string
+	}{
+		{
+			desc:             "TestMultiPageResponse",
+			group:            "liatrio",
+			expectedProjects: []string{"liatrio/api", "liatrio/frontend/web"},
+		},
+		{
+			desc:        "Test404Response",
+			group:       "unknown",
+			expectedErr: "GET /api/v4/groups/unknown/projects returned 404 Not Found",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			gls := newTestScraper(t, server.URL, tc.group)
+
+			projects, err := gls.getProjects(context.Background())
+
+			var names []string
+			for _, p := range projects {
+				names = append(names, p.PathWithNamespace)
+			}
+			assert.Equal(t, tc.expectedProjects, names)
+			if tc.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expectedErr)
+			}
+		})
+	}
+}
+
+func TestGetEnvironments(t *testing.T) {
+	server := newMockServer(t)
+	gls := newTestScraper(t, server.URL, "liatrio")
+
+	environments, err := gls.getEnvironments(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"production", "staging"}, environments)
+
+	environments, err = gls.getEnvironments(context.Background(), 2)
+	require.NoError(t, err)
+	assert.Empty(t, environments)
+
+	_, err = gls.getEnvironments(context.Background(), 3)
+	assert.EqualError(t, err, "GET /api/v4/projects/3/environments returned 404 Not Found")
+}
+
+func TestGetDeployments(t *testing.T) {
+	server := newMockServer(t)
+	gls := newTestScraper(t, server.URL, "liatrio")
+	at := func(day, hour, minute int) time.Time { return time.Date(2024, 4, day, hour, minute, 0, 0, time.UTC) }
+
+	deployments, err := gls.getDeployments(context.Background(), 1, []string{"production", "staging"}, at(1, 0, 0))
+	require.NoError(t, err)
+	assert.Equal(t, []internal.Deployment{
+		{Environment: "production", FinishedAt: at(1, 10, 0)},
+		{Environment: "production", FinishedAt: at(2, 10, 0), Failed: true},
+		{Environment: "production", FinishedAt: at(2, 12, 0)},
+		// the deployment succeeded but its pipeline failed
+		{Environment: "production", FinishedAt: at(3, 10, 0), Failed: true},
+		{Environment: "production", FinishedAt: at(3, 11, 0)},
+		{Environment: "staging", FinishedAt: at(1, 9, 30)},
+	}, deployments)
+
+	_, err = gls.getDeployments(context.Background(), 2, []string{"production"}, at(1, 0, 0))
+	assert.EqualError(t, err, "GET /api/v4/projects/2/deployments returned 404 Not Found")
+}
+
+func TestGetMergedMergeRequests(t *testing.T) {
+	server := newMockServer(t)
+	gls := newTestScraper(t, server.URL, "liatrio")
+
+	// The merge requests merged before the given time are ignored.
+	mrs, err := gls.getMergedMergeRequests(context.Background(), 1, time.Date(2024, 4, 2, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Len(t, mrs, 2)
+	assert.Equal(t, time.Date(2024, 4, 3, 10, 30, 0, 0, time.UTC), mrs[0].MergedAt.UTC())
+	assert.Equal(t, time.Date(2024, 4, 2, 9, 0, 0, 0, time.UTC), mrs[1].MergedAt.UTC())
+
+	_, err = gls.getMergedMergeRequests(context.Background(), 3, time.Date(2024, 4, 2, 0, 0, 0, 0, time.UTC))
+	assert.EqualError(t, err, "GET /api/v4/projects/3/merge_requests returned 404 Not Found")
+}
+
+func TestGetAge(t *testing.T) {
+	start := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, int64(90*60), getAge(start, start.Add(90*time.Minute)))
+}
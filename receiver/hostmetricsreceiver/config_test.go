@@ -25,6 +25,7 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/hostmetricsreceiver/internal/scraper/memoryscraper"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/hostmetricsreceiver/internal/scraper/networkscraper"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/hostmetricsreceiver/internal/scraper/pagingscraper"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/hostmetricsreceiver/internal/scraper/pressurescraper"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/hostmetricsreceiver/internal/scraper/processesscraper"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/hostmetricsreceiver/internal/scraper/processscraper"
 )
@@ -106,6 +107,16 @@ func TestLoadConfig(t *testing.T) {
 				cfg.SetEnvMap(common.EnvMap{})
 				return cfg
 			}(),
+			pressurescraper.TypeStr: (func() internal.Config {
+				cfg := (&pressurescraper.Factory{}).CreateDefaultConfig()
+				cfg.(*pressurescraper.Config).CgroupMaxDepth = 2
+				cfg.(*pressurescraper.Config).Exclude = pressurescraper.MatchConfig{
+					Cgroups: []string{"/system.slice"},
+					Config:  filterset.Config{MatchType: "strict"},
+				}
+				cfg.SetEnvMap(common.EnvMap{})
+				return cfg
+			})(),
 			processscraper.TypeStr: (func() internal.Config {
 				cfg := (&processscraper.Factory{}).CreateDefaultConfig()
 				cfg.(*processscraper.Config).Include = processscraper.MatchConfig{
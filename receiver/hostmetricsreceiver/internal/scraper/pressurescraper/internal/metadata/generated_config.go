@@ -0,0 +1,82 @@
+// Code generated by mdatagen. DO NOT EDIT.
+
+package metadata
+
+import (
+	"go.opentelemetry.io/collector/confmap"
+)
+
+// MetricConfig provides common config for a particular metric.
+type MetricConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+
+	enabledSetByUser bool
+}
+
+func (ms *MetricConfig) Unmarshal(parser *confmap.Conf) error {
+	if parser == nil {
+		return nil
+	}
+	err := parser.Unmarshal(ms)
+	if err != nil {
+		return err
+	}
+	ms.enabledSetByUser = parser.IsSet("enabled")
+	return nil
+}
+
+// MetricsConfig provides config for hostmetricsreceiver/pressure metrics.
+type MetricsConfig struct {
+	SystemCgroupCPUThrottledTime  MetricConfig `mapstructure:"system.cgroup.cpu.throttled.time"`
+	SystemCgroupCPUTime           MetricConfig `mapstructure:"system.cgroup.cpu.time"`
+	SystemCgroupIoBytes           MetricConfig `mapstructure:"system.cgroup.io.bytes"`
+	SystemCgroupIoOperations      MetricConfig `mapstructure:"system.cgroup.io.operations"`
+	SystemCgroupMemoryLimit       MetricConfig `mapstructure:"system.cgroup.memory.limit"`
+	SystemCgroupMemoryUsage       MetricConfig `mapstructure:"system.cgroup.memory.usage"`
+	SystemCgroupPressureStallTime MetricConfig `mapstructure:"system.cgroup.pressure.stall.time"`
+	SystemPressureStallAverage    MetricConfig `mapstructure:"system.pressure.stall.average"`
+	SystemPressureStallTime       MetricConfig `mapstructure:"system.pressure.stall.time"`
+}
+
+func DefaultMetricsConfig() MetricsConfig {
+	return MetricsConfig{
+		SystemCgroupCPUThrottledTime: MetricConfig{
+			Enabled: true,
+		},
+		SystemCgroupCPUTime: MetricConfig{
+			Enabled: true,
+		},
+		SystemCgroupIoBytes: MetricConfig{
+			Enabled: true,
+		},
+		SystemCgroupIoOperations: MetricConfig{
+			Enabled: true,
+		},
+		SystemCgroupMemoryLimit: MetricConfig{
+			Enabled: false,
+		},
+		SystemCgroupMemoryUsage: MetricConfig{
+			Enabled: true,
+		},
+		SystemCgroupPressureStallTime: MetricConfig{
+			Enabled: true,
+		},
+		SystemPressureStallAverage: MetricConfig{
+			Enabled: true,
+		},
+		SystemPressureStallTime: MetricConfig{
+			Enabled: true,
+		},
+	}
+}
+
+// MetricsBuilderConfig is a configuration for hostmetricsreceiver/pressure metrics builder.
+type MetricsBuilderConfig struct {
+	Metrics MetricsConfig `mapstructure:"metrics"`
+}
+
+func DefaultMetricsBuilderConfig() MetricsBuilderConfig {
+	return MetricsBuilderConfig{
+		Metrics: DefaultMetricsConfig(),
+	}
+}
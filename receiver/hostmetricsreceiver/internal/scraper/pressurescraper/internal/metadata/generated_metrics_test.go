@@ -0,0 +1,304 @@
+// Code generated by mdatagen. DO NOT EDIT.
+
+package metadata
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+type testDataSet int
+
+const (
+	testDataSetDefault testDataSet = iota
+	testDataSetAll
+	testDataSetNone
+)
+
+func TestMetricsBuilder(t *testing.T) {
+	tests := []struct {
+		name        string
+		metricsSet  testDataSet
+		resAttrsSet testDataSet
+		expectEmpty bool
+	}{
+		{
+			name: "default",
+		},
+		{
+			name:        "all_set",
+			metricsSet:  testDataSetAll,
+			resAttrsSet: testDataSetAll,
+		},
+		{
+			name:        "none_set",
+			metricsSet:  testDataSetNone,
+			resAttrsSet: testDataSetNone,
+			expectEmpty: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			start := pcommon.Timestamp(1_000_000_000)
+			ts := pcommon.Timestamp(1_000_001_000)
+			observedZapCore, observedLogs := observer.New(zap.WarnLevel)
+			settings := receivertest.NewNopCreateSettings()
+			settings.Logger = zap.New(observedZapCore)
+			mb := NewMetricsBuilder(loadMetricsBuilderConfig(t, test.name), settings, WithStartTime(start))
+
+			expectedWarnings := 0
+
+			assert.Equal(t, expectedWarnings, observedLogs.Len())
+
+			defaultMetricsCount := 0
+			allMetricsCount := 0
+
+			defaultMetricsCount++
+			allMetricsCount++
+			mb.RecordSystemCgroupCPUThrottledTimeDataPoint(ts, 1, "cgroup-val")
+
+			defaultMetricsCount++
+			allMetricsCount++
+			mb.RecordSystemCgroupCPUTimeDataPoint(ts, 1, "cgroup-val", AttributeStateUser)
+
+			defaultMetricsCount++
+			allMetricsCount++
+			mb.RecordSystemCgroupIoBytesDataPoint(ts, 1, "cgroup-val", "device-val", AttributeDirectionRead)
+
+			defaultMetricsCount++
+			allMetricsCount++
+			mb.RecordSystemCgroupIoOperationsDataPoint(ts, 1, "cgroup-val", "device-val", AttributeDirectionRead)
+
+			allMetricsCount++
+			mb.RecordSystemCgroupMemoryLimitDataPoint(ts, 1, "cgroup-val")
+
+			defaultMetricsCount++
+			allMetricsCount++
+			mb.RecordSystemCgroupMemoryUsageDataPoint(ts, 1, "cgroup-val")
+
+			defaultMetricsCount++
+			allMetricsCount++
+			mb.RecordSystemCgroupPressureStallTimeDataPoint(ts, 1, "cgroup-val", AttributeResourceCpu, AttributeStallSome)
+
+			defaultMetricsCount++
+			allMetricsCount++
+			mb.RecordSystemPressureStallAverageDataPoint(ts, 1, AttributeResourceCpu, AttributeStallSome, AttributeWindow10s)
+
+			defaultMetricsCount++
+			allMetricsCount++
+			mb.RecordSystemPressureStallTimeDataPoint(ts, 1, AttributeResourceCpu, AttributeStallSome)
+
+			res := pcommon.NewResource()
+			metrics := mb.Emit(WithResource(res))
+
+			if test.expectEmpty {
+				assert.Equal(t, 0, metrics.ResourceMetrics().Len())
+				return
+			}
+
+			assert.Equal(t, 1, metrics.ResourceMetrics().Len())
+			rm := metrics.ResourceMetrics().At(0)
+			assert.Equal(t, res, rm.Resource())
+			assert.Equal(t, 1, rm.ScopeMetrics().Len())
+			ms := rm.ScopeMetrics().At(0).Metrics()
+			if test.metricsSet == testDataSetDefault {
+				assert.Equal(t, defaultMetricsCount, ms.Len())
+			}
+			if test.metricsSet == testDataSetAll {
+				assert.Equal(t, allMetricsCount, ms.Len())
+			}
+			validatedMetrics := make(map[string]bool)
+			for i := 0; i < ms.Len(); i++ {
+				switch ms.At(i).Name() {
+				case "system.cgroup.cpu.throttled.time":
+					assert.False(t, validatedMetrics["system.cgroup.cpu.throttled.time"], "Found a duplicate in the metrics slice: system.cgroup.cpu.throttled.time")
+					validatedMetrics["system.cgroup.cpu.throttled.time"] = true
+					assert.Equal(t, pmetric.MetricTypeSum, ms.At(i).Type())
+					assert.Equal(t, 1, ms.At(i).Sum().DataPoints().Len())
+					assert.Equal(t, "Time during which a cgroup was throttled by its CPU bandwidth limit.", ms.At(i).Description())
+					assert.Equal(t, "s", ms.At(i).Unit())
+					assert.Equal(t, true, ms.At(i).Sum().IsMonotonic())
+					assert.Equal(t, pmetric.AggregationTemporalityCumulative, ms.At(i).Sum().AggregationTemporality())
+					dp := ms.At(i).Sum().DataPoints().At(0)
+					assert.Equal(t, start, dp.StartTimestamp())
+					assert.Equal(t, ts, dp.Timestamp())
+					assert.Equal(t, pmetric.NumberDataPointValueTypeDouble, dp.ValueType())
+					assert.Equal(t, float64(1), dp.DoubleValue())
+					attrVal, ok := dp.Attributes().Get("cgroup")
+					assert.True(t, ok)
+					assert.EqualValues(t, "cgroup-val", attrVal.Str())
+				case "system.cgroup.cpu.time":
+					assert.False(t, validatedMetrics["system.cgroup.cpu.time"], "Found a duplicate in the metrics slice: system.cgroup.cpu.time")
+					validatedMetrics["system.cgroup.cpu.time"] = true
+					assert.Equal(t, pmetric.MetricTypeSum, ms.At(i).Type())
+					assert.Equal(t, 1, ms.At(i).Sum().DataPoints().Len())
+					assert.Equal(t, "CPU time consumed by a cgroup.", ms.At(i).Description())
+					assert.Equal(t, "s", ms.At(i).Unit())
+					assert.Equal(t, true, ms.At(i).Sum().IsMonotonic())
+					assert.Equal(t, pmetric.AggregationTemporalityCumulative, ms.At(i).Sum().AggregationTemporality())
+					dp := ms.At(i).Sum().DataPoints().At(0)
+					assert.Equal(t, start, dp.StartTimestamp())
+					assert.Equal(t, ts, dp.Timestamp())
+					assert.Equal(t, pmetric.NumberDataPointValueTypeDouble, dp.ValueType())
+					assert.Equal(t, float64(1), dp.DoubleValue())
+					attrVal, ok := dp.Attributes().Get("cgroup")
+					assert.True(t, ok)
+					assert.EqualValues(t, "cgroup-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("state")
+					assert.True(t, ok)
+					assert.EqualValues(t, "user", attrVal.Str())
+				case "system.cgroup.io.bytes":
+					assert.False(t, validatedMetrics["system.cgroup.io.bytes"], "Found a duplicate in the metrics slice: system.cgroup.io.bytes")
+					validatedMetrics["system.cgroup.io.bytes"] = true
+					assert.Equal(t, pmetric.MetricTypeSum, ms.At(i).Type())
+					assert.Equal(t, 1, ms.At(i).Sum().DataPoints().Len())
+					assert.Equal(t, "Bytes transferred by a cgroup per device.", ms.At(i).Description())
+					assert.Equal(t, "By", ms.At(i).Unit())
+					assert.Equal(t, true, ms.At(i).Sum().IsMonotonic())
+					assert.Equal(t, pmetric.AggregationTemporalityCumulative, ms.At(i).Sum().AggregationTemporality())
+					dp := ms.At(i).Sum().DataPoints().At(0)
+					assert.Equal(t, start, dp.StartTimestamp())
+					assert.Equal(t, ts, dp.Timestamp())
+					assert.Equal(t, pmetric.NumberDataPointValueTypeInt, dp.ValueType())
+					assert.Equal(t, int64(1), dp.IntValue())
+					attrVal, ok := dp.Attributes().Get("cgroup")
+					assert.True(t, ok)
+					assert.EqualValues(t, "cgroup-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("device")
+					assert.True(t, ok)
+					assert.EqualValues(t, "device-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("direction")
+					assert.True(t, ok)
+					assert.EqualValues(t, "read", attrVal.Str())
+				case "system.cgroup.io.operations":
+					assert.False(t, validatedMetrics["system.cgroup.io.operations"], "Found a duplicate in the metrics slice: system.cgroup.io.operations")
+					validatedMetrics["system.cgroup.io.operations"] = true
+					assert.Equal(t, pmetric.MetricTypeSum, ms.At(i).Type())
+					assert.Equal(t, 1, ms.At(i).Sum().DataPoints().Len())
+					assert.Equal(t, "I/O operations performed by a cgroup per device.", ms.At(i).Description())
+					assert.Equal(t, "{operation}", ms.At(i).Unit())
+					assert.Equal(t, true, ms.At(i).Sum().IsMonotonic())
+					assert.Equal(t, pmetric.AggregationTemporalityCumulative, ms.At(i).Sum().AggregationTemporality())
+					dp := ms.At(i).Sum().DataPoints().At(0)
+					assert.Equal(t, start, dp.StartTimestamp())
+					assert.Equal(t, ts, dp.Timestamp())
+					assert.Equal(t, pmetric.NumberDataPointValueTypeInt, dp.ValueType())
+					assert.Equal(t, int64(1), dp.IntValue())
+					attrVal, ok := dp.Attributes().Get("cgroup")
+					assert.True(t, ok)
+					assert.EqualValues(t, "cgroup-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("device")
+					assert.True(t, ok)
+					assert.EqualValues(t, "device-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("direction")
+					assert.True(t, ok)
+					assert.EqualValues(t, "read", attrVal.Str())
+				case "system.cgroup.memory.limit":
+					assert.False(t, validatedMetrics["system.cgroup.memory.limit"], "Found a duplicate in the metrics slice: system.cgroup.memory.limit")
+					validatedMetrics["system.cgroup.memory.limit"] = true
+					assert.Equal(t, pmetric.MetricTypeSum, ms.At(i).Type())
+					assert.Equal(t, 1, ms.At(i).Sum().DataPoints().Len())
+					assert.Equal(t, "Memory usage hard limit of a cgroup. Not reported for cgroups without a limit.", ms.At(i).Description())
+					assert.Equal(t, "By", ms.At(i).Unit())
+					assert.Equal(t, false, ms.At(i).Sum().IsMonotonic())
+					assert.Equal(t, pmetric.AggregationTemporalityCumulative, ms.At(i).Sum().AggregationTemporality())
+					dp := ms.At(i).Sum().DataPoints().At(0)
+					assert.Equal(t, start, dp.StartTimestamp())
+					assert.Equal(t, ts, dp.Timestamp())
+					assert.Equal(t, pmetric.NumberDataPointValueTypeInt, dp.ValueType())
+					assert.Equal(t, int64(1), dp.IntValue())
+					attrVal, ok := dp.Attributes().Get("cgroup")
+					assert.True(t, ok)
+					assert.EqualValues(t, "cgroup-val", attrVal.Str())
+				case "system.cgroup.memory.usage":
+					assert.False(t, validatedMetrics["system.cgroup.memory.usage"], "Found a duplicate in the metrics slice: system.cgroup.memory.usage")
+					validatedMetrics["system.cgroup.memory.usage"] = true
+					assert.Equal(t, pmetric.MetricTypeSum, ms.At(i).Type())
+					assert.Equal(t, 1, ms.At(i).Sum().DataPoints().Len())
+					assert.Equal(t, "Memory currently used by a cgroup and its descendants.", ms.At(i).Description())
+					assert.Equal(t, "By", ms.At(i).Unit())
+					assert.Equal(t, false, ms.At(i).Sum().IsMonotonic())
+					assert.Equal(t, pmetric.AggregationTemporalityCumulative, ms.At(i).Sum().AggregationTemporality())
+					dp := ms.At(i).Sum().DataPoints().At(0)
+					assert.Equal(t, start, dp.StartTimestamp())
+					assert.Equal(t, ts, dp.Timestamp())
+					assert.Equal(t, pmetric.NumberDataPointValueTypeInt, dp.ValueType())
+					assert.Equal(t, int64(1), dp.IntValue())
+					attrVal, ok := dp.Attributes().Get("cgroup")
+					assert.True(t, ok)
+					assert.EqualValues(t, "cgroup-val", attrVal.Str())
+				case "system.cgroup.pressure.stall.time":
+					assert.False(t, validatedMetrics["system.cgroup.pressure.stall.time"], "Found a duplicate in the metrics slice: system.cgroup.pressure.stall.time")
+					validatedMetrics["system.cgroup.pressure.stall.time"] = true
+					assert.Equal(t, pmetric.MetricTypeSum, ms.At(i).Type())
+					assert.Equal(t, 1, ms.At(i).Sum().DataPoints().Len())
+					assert.Equal(t, "Total time in which tasks of a cgroup were stalled on a resource.", ms.At(i).Description())
+					assert.Equal(t, "s", ms.At(i).Unit())
+					assert.Equal(t, true, ms.At(i).Sum().IsMonotonic())
+					assert.Equal(t, pmetric.AggregationTemporalityCumulative, ms.At(i).Sum().AggregationTemporality())
+					dp := ms.At(i).Sum().DataPoints().At(0)
+					assert.Equal(t, start, dp.StartTimestamp())
+					assert.Equal(t, ts, dp.Timestamp())
+					assert.Equal(t, pmetric.NumberDataPointValueTypeDouble, dp.ValueType())
+					assert.Equal(t, float64(1), dp.DoubleValue())
+					attrVal, ok := dp.Attributes().Get("cgroup")
+					assert.True(t, ok)
+					assert.EqualValues(t, "cgroup-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("resource")
+					assert.True(t, ok)
+					assert.EqualValues(t, "cpu", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("stall")
+					assert.True(t, ok)
+					assert.EqualValues(t, "some", attrVal.Str())
+				case "system.pressure.stall.average":
+					assert.False(t, validatedMetrics["system.pressure.stall.average"], "Found a duplicate in the metrics slice: system.pressure.stall.average")
+					validatedMetrics["system.pressure.stall.average"] = true
+					assert.Equal(t, pmetric.MetricTypeGauge, ms.At(i).Type())
+					assert.Equal(t, 1, ms.At(i).Gauge().DataPoints().Len())
+					assert.Equal(t, "Share of wall time in which tasks were stalled on a resource, averaged over a window.", ms.At(i).Description())
+					assert.Equal(t, "%", ms.At(i).Unit())
+					dp := ms.At(i).Gauge().DataPoints().At(0)
+					assert.Equal(t, start, dp.StartTimestamp())
+					assert.Equal(t, ts, dp.Timestamp())
+					assert.Equal(t, pmetric.NumberDataPointValueTypeDouble, dp.ValueType())
+					assert.Equal(t, float64(1), dp.DoubleValue())
+					attrVal, ok := dp.Attributes().Get("resource")
+					assert.True(t, ok)
+					assert.EqualValues(t, "cpu", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("stall")
+					assert.True(t, ok)
+					assert.EqualValues(t, "some", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("window")
+					assert.True(t, ok)
+					assert.EqualValues(t, "10s", attrVal.Str())
+				case "system.pressure.stall.time":
+					assert.False(t, validatedMetrics["system.pressure.stall.time"], "Found a duplicate in the metrics slice: system.pressure.stall.time")
+					validatedMetrics["system.pressure.stall.time"] = true
+					assert.Equal(t, pmetric.MetricTypeSum, ms.At(i).Type())
+					assert.Equal(t, 1, ms.At(i).Sum().DataPoints().Len())
+					assert.Equal(t, "Total time in which tasks were stalled on a resource.", ms.At(i).Description())
+					assert.Equal(t, "s", ms.At(i).Unit())
+					assert.Equal(t, true, ms.At(i).Sum().IsMonotonic())
+					assert.Equal(t, pmetric.AggregationTemporalityCumulative, ms.At(i).Sum().AggregationTemporality())
+					dp := ms.At(i).Sum().DataPoints().At(0)
+					assert.Equal(t, start, dp.StartTimestamp())
+					assert.Equal(t, ts, dp.Timestamp())
+					assert.Equal(t, pmetric.NumberDataPointValueTypeDouble, dp.ValueType())
+					assert.Equal(t, float64(1), dp.DoubleValue())
+					attrVal, ok := dp.Attributes().Get("resource")
+					assert.True(t, ok)
+					assert.EqualValues(t, "cpu", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("stall")
+					assert.True(t, ok)
+					assert.EqualValues(t, "some", attrVal.Str())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,268 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package pressurescraper // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/hostmetricsreceiver/internal/scraper/pressurescraper"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/common"
+	"github.com/shirou/gopsutil/v3/host"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/receiver"
+	"go.opentelemetry.io/collector/receiver/scrapererror"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/filter/filterset"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/hostmetricsreceiver/internal/scraper/pressurescraper/internal/metadata"
+)
+
+const (
+	pressureMetricsLen       = 2
+	cgroupCPUMetricsLen      = 2
+	cgroupMemoryMetricsLen   = 2
+	cgroupIOMetricsLen       = 2
+	cgroupPressureMetricsLen = 1
+	cgroupMetricsLen         = cgroupCPUMetricsLen + cgroupMemoryMetricsLen + cgroupIOMetricsLen + cgroupPressureMetricsLen
+)
+
+var pressureResources = []metadata.AttributeResource{
+	metadata.AttributeResourceCpu,
+	metadata.AttributeResourceMemory,
+	metadata.AttributeResourceIo,
+	metadata.AttributeResourceIrq,
+}
+
+// scraper for Pressure Metrics
+type scraper struct {
+	settings  receiver.CreateSettings
+	config    *Config
+	mb        *metadata.MetricsBuilder
+	includeFS filterset.FilterSet
+	excludeFS filterset.FilterSet
+
+	// pressureDir is the directory holding the system-wide PSI files, empty
+	// when PSI is not available.
+	pressureDir string
+	// cgroupRoot is the root of the cgroup v2 hierarchy, empty when the
+	// host does not use cgroup v2.
+	cgroupRoot string
+
+	// for mocking
+	bootTime func(context.Context) (uint64, error)
+}
+
+// newPressureScraper creates a Pressure Scraper
+func newPressureScraper(_ context.Context, settings receiver.CreateSettings, cfg *Config) (*scraper, error) {
+	if cfg.CgroupMaxDepth < 0 {
+		return nil, fmt.Errorf("cgroup_max_depth cannot be negative: %d", cfg.CgroupMaxDepth)
+	}
+
+	scraper := &scraper{settings: settings, config: cfg, bootTime: host.BootTimeWithContext}
+
+	var err error
+
+	if len(cfg.Include.Cgroups) > 0 {
+		scraper.includeFS, err = filterset.CreateFilterSet(cfg.Include.Cgroups, &cfg.Include.Config)
+		if err != nil {
+			return nil, fmt.Errorf("error creating cgroup include filters: %w", err)
+		}
+	}
+
+	if len(cfg.Exclude.Cgroups) > 0 {
+		scraper.excludeFS, err = filterset.CreateFilterSet(cfg.Exclude.Cgroups, &cfg.Exclude.Config)
+		if err != nil {
+			return nil, fmt.Errorf("error creating cgroup exclude filters: %w", err)
+		}
+	}
+
+	return scraper, nil
+}
+
+func (s *scraper) start(ctx context.Context, _ component.Host) error {
+	ctx = context.WithValue(ctx, common.EnvKey, s.config.EnvMap)
+	bootTime, err := s.bootTime(ctx)
+	if err != nil {
+		return err
+	}
+	s.mb = metadata.NewMetricsBuilder(s.config.MetricsBuilderConfig, s.settings, metadata.WithStartTime(pcommon.Timestamp(bootTime*1e9)))
+
+	pressureDir := hostPath(s.config.EnvMap, common.HostProcEnvKey, "/proc", "pressure")
+	if _, err := os.Stat(pressureDir); err != nil {
+		s.settings.Logger.Warn("Pressure stall information is not available, system pressure metrics will not be scraped", zap.Error(err))
+	} else {
+		s.pressureDir = pressureDir
+	}
+
+	cgroupRoot := hostPath(s.config.EnvMap, common.HostSysEnvKey, "/sys", "fs", "cgroup")
+	if _, err := os.Stat(filepath.Join(cgroupRoot, "cgroup.controllers")); err != nil {
+		s.settings.Logger.Warn("No cgroup v2 hierarchy found, cgroup metrics will not be scraped", zap.String("path", cgroupRoot), zap.Error(err))
+	} else {
+		s.cgroupRoot = cgroupRoot
+	}
+
+	return nil
+}
+
+func (s *scraper) scrape(_ context.Context) (pmetric.Metrics, error) {
+	now := pcommon.NewTimestampFromTime(time.Now())
+
+	var errs scrapererror.ScrapeErrors
+	if s.pressureDir != "" {
+		s.scrapeSystemPressure(now, &errs)
+	}
+	if s.cgroupRoot != "" {
+		s.scrapeCgroups(now, &errs)
+	}
+
+	return s.mb.Emit(), errs.Combine()
+}
+
+func (s *scraper) scrapeSystemPressure(now pcommon.Timestamp, errs *scrapererror.ScrapeErrors) {
+	for _, resource := range pressureResources {
+		stats, err := readPressure(filepath.Join(s.pressureDir, resource.String()))
+		if err != nil {
+			addPartial(errs, pressureMetricsLen, err)
+			continue
+		}
+		for stallName, ps := range stats {
+			stall, ok := metadata.MapAttributeStall[stallName]
+			if !ok {
+				continue
+			}
+			s.mb.RecordSystemPressureStallAverageDataPoint(now, ps.avg10, resource, stall, metadata.AttributeWindow10s)
+			s.mb.RecordSystemPressureStallAverageDataPoint(now, ps.avg60, resource, stall, metadata.AttributeWindow60s)
+			s.mb.RecordSystemPressureStallAverageDataPoint(now, ps.avg300, resource, stall, metadata.AttributeWindow300s)
+			s.mb.RecordSystemPressureStallTimeDataPoint(now, float64(ps.total)/1e6, resource, stall)
+		}
+	}
+}
+
+// scrapeCgroups walks the cgroup v2 hierarchy down to the configured depth.
+// Cgroups can be removed at any time, so files that vanish are skipped
+// rather than reported as errors.
+func (s *scraper) scrapeCgroups(now pcommon.Timestamp, errs *scrapererror.ScrapeErrors) {
+	err := filepath.WalkDir(s.cgroupRoot, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == s.cgroupRoot {
+				return err
+			}
+			addPartial(errs, cgroupMetricsLen, err)
+			return nil
+		}
+		if !d.IsDir() {
+			return nil
+		}
+
+		cgroup, depth := s.cgroupName(path)
+		if s.includeCgroup(cgroup) {
+			s.recordCgroup(now, path, cgroup, errs)
+		}
+		if depth >= s.config.CgroupMaxDepth {
+			return fs.SkipDir
+		}
+		return nil
+	})
+	if err != nil {
+		errs.AddPartial(cgroupMetricsLen, err)
+	}
+}
+
+// cgroupName returns the path of the cgroup in dir relative to the root of
+// the hierarchy, along with its depth.
+func (s *scraper) cgroupName(dir string) (string, int) {
+	rel, err := filepath.Rel(s.cgroupRoot, dir)
+	if err != nil || rel == "." {
+		return "/", 0
+	}
+	rel = filepath.ToSlash(rel)
+	return "/" + rel, strings.Count(rel, "/") + 1
+}
+
+func (s *scraper) includeCgroup(cgroup string) bool {
+	return (s.includeFS == nil || s.includeFS.Matches(cgroup)) &&
+		(s.excludeFS == nil || !s.excludeFS.Matches(cgroup))
+}
+
+func (s *scraper) recordCgroup(now pcommon.Timestamp, dir string, cgroup string, errs *scrapererror.ScrapeErrors) {
+	if stats, err := readFlatKeyed(filepath.Join(dir, "cpu.stat")); err != nil {
+		addPartial(errs, cgroupCPUMetricsLen, err)
+	} else {
+		s.mb.RecordSystemCgroupCPUTimeDataPoint(now, float64(stats["user_usec"])/1e6, cgroup, metadata.AttributeStateUser)
+		s.mb.RecordSystemCgroupCPUTimeDataPoint(now, float64(stats["system_usec"])/1e6, cgroup, metadata.AttributeStateSystem)
+		// The throttling counters are only present when the cpu controller is enabled.
+		if throttled, ok := stats["throttled_usec"]; ok {
+			s.mb.RecordSystemCgroupCPUThrottledTimeDataPoint(now, float64(throttled)/1e6, cgroup)
+		}
+	}
+
+	if usage, _, err := readSingleValue(filepath.Join(dir, "memory.current")); err != nil {
+		addPartial(errs, 1, err)
+	} else {
+		s.mb.RecordSystemCgroupMemoryUsageDataPoint(now, int64(usage), cgroup)
+	}
+	if s.config.Metrics.SystemCgroupMemoryLimit.Enabled {
+		if limit, ok, err := readSingleValue(filepath.Join(dir, "memory.max")); err != nil {
+			addPartial(errs, 1, err)
+		} else if ok {
+			s.mb.RecordSystemCgroupMemoryLimitDataPoint(now, int64(limit), cgroup)
+		}
+	}
+
+	if stats, err := readIOStat(filepath.Join(dir, "io.stat")); err != nil {
+		addPartial(errs, cgroupIOMetricsLen, err)
+	} else {
+		for _, dev := range stats {
+			s.mb.RecordSystemCgroupIoBytesDataPoint(now, int64(dev.rbytes), cgroup, dev.device, metadata.AttributeDirectionRead)
+			s.mb.RecordSystemCgroupIoBytesDataPoint(now, int64(dev.wbytes), cgroup, dev.device, metadata.AttributeDirectionWrite)
+			s.mb.RecordSystemCgroupIoOperationsDataPoint(now, int64(dev.rios), cgroup, dev.device, metadata.AttributeDirectionRead)
+			s.mb.RecordSystemCgroupIoOperationsDataPoint(now, int64(dev.wios), cgroup, dev.device, metadata.AttributeDirectionWrite)
+		}
+	}
+
+	for _, resource := range pressureResources {
+		stats, err := readPressure(filepath.Join(dir, resource.String()+".pressure"))
+		if err != nil {
+			addPartial(errs, cgroupPressureMetricsLen, err)
+			continue
+		}
+		for stallName, ps := range stats {
+			if stall, ok := metadata.MapAttributeStall[stallName]; ok {
+				s.mb.RecordSystemCgroupPressureStallTimeDataPoint(now, float64(ps.total)/1e6, cgroup, resource, stall)
+			}
+		}
+	}
+}
+
+// addPartial records a partial scrape error, unless the file does not exist.
+// Files are missing when a controller is not enabled for a cgroup, when the
+// kernel does not support a pressure resource, or when a cgroup is removed
+// while being scraped.
+func addPartial(errs *scrapererror.ScrapeErrors, failed int, err error) {
+	if errors.Is(err, fs.ErrNotExist) {
+		return
+	}
+	errs.AddPartial(failed, err)
+}
+
+// hostPath resolves a path on the host the same way gopsutil does, so that
+// the root_path setting is honoured.
+func hostPath(env common.EnvMap, key common.EnvKeyType, dfault string, combineWith ...string) string {
+	value := env[key]
+	if value == "" {
+		value = os.Getenv(string(key))
+	}
+	if value == "" {
+		value = dfault
+	}
+	return filepath.Join(append([]string{value}, combineWith...)...)
+}
@@ -0,0 +1,219 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package pressurescraper
+
+import (
+	"context"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/shirou/gopsutil/v3/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+	"go.opentelemetry.io/collector/receiver/scrapererror"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/filter/filterset"
+)
+
+const bootTime = 100
+
+// newTestScraper starts a scraper reading the fixture filesystem in
+// testdata/<root>, the same way the receiver sets it up for root_path.
+func newTestScraper(t *testing.T, root string, configure func(*Config)) *scraper {
+	cfg := (&Factory{}).CreateDefaultConfig().(*Config)
+	cfg.SetEnvMap(common.EnvMap{
+		common.HostProcEnvKey: filepath.Join("testdata", root, "proc"),
+		common.HostSysEnvKey:  filepath.Join("testdata", root, "sys"),
+	})
+	if configure != nil {
+		configure(cfg)
+	}
+
+	s, err := newPressureScraper(context.Background(), receivertest.NewNopCreateSettings(), cfg)
+	require.NoError(t, err)
+	s.bootTime = func(context.Context) (uint64, error) { return bootTime, nil }
+	require.NoError(t, s.start(context.Background(), componenttest.NewNopHost()))
+	return s
+}
+
+// dataPoints returns the values of the named metric keyed by their sorted
+// attributes, e.g. "resource=cpu,stall=some".
+func dataPoints(t *testing.T, md pmetric.Metrics, name string) map[string]float64 {
+	values := map[string]float64{}
+	metrics := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	for i := 0; i < metrics.Len(); i++ {
+		m := metrics.At(i)
+		if m.Name() != name {
+			continue
+		}
+		var dps pmetric.NumberDataPointSlice
+		switch m.Type() {
+		case pmetric.MetricTypeGauge:
+			dps = m.Gauge().DataPoints()
+		case pmetric.MetricTypeSum:
+			assert.Equal(t, pcommon.Timestamp(bootTime*1e9), m.Sum().DataPoints().At(0).StartTimestamp())
+			dps = m.Sum().DataPoints()
+		}
+		for j := 0; j < dps.Len(); j++ {
+			dp := dps.At(j)
+			var attrs []string
+			dp.Attributes().Range(func(k string, v pcommon.Value) bool {
+				attrs = append(attrs, k+"="+v.AsString())
+				return true
+			})
+			sort.Strings(attrs)
+			if dp.ValueType() == pmetric.NumberDataPointValueTypeInt {
+				values[strings.Join(attrs, ",")] = float64(dp.IntValue())
+			} else {
+				values[strings.Join(attrs, ",")] = dp.DoubleValue()
+			}
+		}
+	}
+	return values
+}
+
+func TestScrapeSystemPressure(t *testing.T) {
+	s := newTestScraper(t, "host", nil)
+
+	md, err := s.scrape(context.Background())
+	require.NoError(t, err)
+
+	averages := dataPoints(t, md, "system.pressure.stall.average")
+	assert.Len(t, averages, 18)
+	assert.Equal(t, 1.5, averages["resource=cpu,stall=some,window=10s"])
+	assert.Equal(t, 0.75, averages["resource=cpu,stall=some,window=60s"])
+	assert.Equal(t, 0.5, averages["resource=io,stall=full,window=300s"])
+
+	// irq is not exposed by the fixture, as on kernels older than 6.1.
+	assert.Equal(t, map[string]float64{
+		"resource=cpu,stall=some":    12,
+		"resource=cpu,stall=full":    0,
+		"resource=memory,stall=some": 3.5,
+		"resource=memory,stall=full": 1.5,
+		"resource=io,stall=some":     40,
+		"resource=io,stall=full":     20,
+	}, dataPoints(t, md, "system.pressure.stall.time"))
+}
+
+func TestScrapeCgroups(t *testing.T) {
+	s := newTestScraper(t, "host", func(cfg *Config) {
+		cfg.Metrics.SystemCgroupMemoryLimit.Enabled = true
+	})
+
+	md, err := s.scrape(context.Background())
+	require.NoError(t, err)
+
+	// The nested cgroup is deeper than the default cgroup_max_depth.
+	assert.Equal(t, map[string]float64{
+		"cgroup=/,state=user":                                                              20,
+		"cgroup=/,state=system":                                                            10,
+		"cgroup=/kubepods.slice,state=user":                                                15,
+		"cgroup=/kubepods.slice,state=system":                                              5,
+		"cgroup=/kubepods.slice/kubepods-pod1.slice,state=user":                            10,
+		"cgroup=/kubepods.slice/kubepods-pod1.slice,state=system":                          2,
+		"cgroup=/kubepods.slice/kubepods-pod1.slice/cri-containerd-abc.scope,state=user":   9,
+		"cgroup=/kubepods.slice/kubepods-pod1.slice/cri-containerd-abc.scope,state=system": 1,
+		"cgroup=/system.slice,state=user":                                                  2,
+		"cgroup=/system.slice,state=system":                                                3,
+	}, dataPoints(t, md, "system.cgroup.cpu.time"))
+
+	assert.Equal(t, map[string]float64{
+		"cgroup=/kubepods.slice":                                              1,
+		"cgroup=/kubepods.slice/kubepods-pod1.slice":                          0.5,
+		"cgroup=/kubepods.slice/kubepods-pod1.slice/cri-containerd-abc.scope": 0.5,
+		"cgroup=/system.slice":                                                0,
+	}, dataPoints(t, md, "system.cgroup.cpu.throttled.time"))
+
+	// The root cgroup has no memory.current.
+	assert.Equal(t, map[string]float64{
+		"cgroup=/kubepods.slice":                                              1073741824,
+		"cgroup=/kubepods.slice/kubepods-pod1.slice":                          536870912,
+		"cgroup=/kubepods.slice/kubepods-pod1.slice/cri-containerd-abc.scope": 268435456,
+		"cgroup=/system.slice":                                                104857600,
+	}, dataPoints(t, md, "system.cgroup.memory.usage"))
+
+	// Cgroups without a limit are not reported.
+	assert.Equal(t, map[string]float64{
+		"cgroup=/kubepods.slice/kubepods-pod1.slice":                          1073741824,
+		"cgroup=/kubepods.slice/kubepods-pod1.slice/cri-containerd-abc.scope": 536870912,
+	}, dataPoints(t, md, "system.cgroup.memory.limit"))
+
+	ioBytes := dataPoints(t, md, "system.cgroup.io.bytes")
+	assert.Len(t, ioBytes, 10)
+	assert.Equal(t, 4096.0, ioBytes["cgroup=/,device=8:0,direction=read"])
+	assert.Equal(t, 2048.0, ioBytes["cgroup=/kubepods.slice/kubepods-pod1.slice,device=8:0,direction=write"])
+
+	ioOperations := dataPoints(t, md, "system.cgroup.io.operations")
+	assert.Len(t, ioOperations, 10)
+	assert.Equal(t, 2.0, ioOperations["cgroup=/,device=8:0,direction=write"])
+
+	stallTime := dataPoints(t, md, "system.cgroup.pressure.stall.time")
+	assert.Len(t, stallTime, 14)
+	assert.Equal(t, 5.0, stallTime["cgroup=/kubepods.slice,resource=cpu,stall=some"])
+	assert.Equal(t, 20.0, stallTime["cgroup=/,resource=io,stall=full"])
+}
+
+func TestScrapeCgroupsFiltered(t *testing.T) {
+	s := newTestScraper(t, "host", func(cfg *Config) {
+		cfg.CgroupMaxDepth = 1
+		cfg.Exclude = MatchConfig{
+			Config:  filterset.Config{MatchType: filterset.Strict},
+			Cgroups: []string{"/system.slice"},
+		}
+	})
+
+	md, err := s.scrape(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]float64{
+		"cgroup=/kubepods.slice": 1073741824,
+	}, dataPoints(t, md, "system.cgroup.memory.usage"))
+}
+
+func TestScrapeCgroupsIncluded(t *testing.T) {
+	s := newTestScraper(t, "host", func(cfg *Config) {
+		cfg.CgroupMaxDepth = 10
+		cfg.Include = MatchConfig{
+			Config:  filterset.Config{MatchType: filterset.Regexp},
+			Cgroups: []string{"/nested$"},
+		}
+	})
+
+	md, err := s.scrape(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]float64{
+		"cgroup=/kubepods.slice/kubepods-pod1.slice/cri-containerd-abc.scope/nested": 1024,
+	}, dataPoints(t, md, "system.cgroup.memory.usage"))
+}
+
+func TestScrapeUnavailable(t *testing.T) {
+	s := newTestScraper(t, "missing", nil)
+	assert.Empty(t, s.pressureDir)
+	assert.Empty(t, s.cgroupRoot)
+
+	md, err := s.scrape(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, md.MetricCount())
+}
+
+func TestScrapeInvalid(t *testing.T) {
+	s := newTestScraper(t, "invalid", nil)
+
+	md, err := s.scrape(context.Background())
+	require.Error(t, err)
+
+	var partialErr scrapererror.PartialScrapeError
+	require.ErrorAs(t, err, &partialErr)
+	assert.Equal(t, pressureMetricsLen+cgroupCPUMetricsLen, partialErr.Failed)
+	assert.ErrorContains(t, err, `failed to parse testdata/invalid/proc/pressure/cpu: invalid field "avg10=abc"`)
+	assert.ErrorContains(t, err, `failed to parse testdata/invalid/sys/fs/cgroup/broken.slice/cpu.stat: invalid line "user_usec"`)
+	assert.Equal(t, 0, md.MetricCount())
+}
@@ -0,0 +1,154 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package pressurescraper // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/hostmetricsreceiver/internal/scraper/pressurescraper"
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// pressureStats holds one line of a PSI file, e.g.
+// "some avg10=0.12 avg60=0.05 avg300=0.01 total=123456".
+type pressureStats struct {
+	avg10  float64
+	avg60  float64
+	avg300 float64
+	// total is the accumulated stall time in microseconds.
+	total uint64
+}
+
+// ioStats holds the counters of one device in a cgroup io.stat file.
+type ioStats struct {
+	device string
+	rbytes uint64
+	wbytes uint64
+	rios   uint64
+	wios   uint64
+}
+
+// readPressure parses a PSI file, such as /proc/pressure/cpu or
+// <cgroup>/cpu.pressure, into its "some" and "full" lines.
+func readPressure(path string) (map[string]pressureStats, error) {
+	stats := make(map[string]pressureStats)
+	err := scanLines(path, func(fields []string) error {
+		var ps pressureStats
+		for _, field := range fields[1:] {
+			key, value, ok := strings.Cut(field, "=")
+			if !ok {
+				return fmt.Errorf("invalid field %q", field)
+			}
+			var err error
+			switch key {
+			case "avg10":
+				ps.avg10, err = strconv.ParseFloat(value, 64)
+			case "avg60":
+				ps.avg60, err = strconv.ParseFloat(value, 64)
+			case "avg300":
+				ps.avg300, err = strconv.ParseFloat(value, 64)
+			case "total":
+				ps.total, err = strconv.ParseUint(value, 10, 64)
+			}
+			if err != nil {
+				return fmt.Errorf("invalid field %q: %w", field, err)
+			}
+		}
+		stats[fields[0]] = ps
+		return nil
+	})
+	return stats, err
+}
+
+// readFlatKeyed parses a file made of "key value" lines, such as cpu.stat.
+func readFlatKeyed(path string) (map[string]uint64, error) {
+	stats := make(map[string]uint64)
+	err := scanLines(path, func(fields []string) error {
+		if len(fields) != 2 {
+			return fmt.Errorf("invalid line %q", strings.Join(fields, " "))
+		}
+		value, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid value for %q: %w", fields[0], err)
+		}
+		stats[fields[0]] = value
+		return nil
+	})
+	return stats, err
+}
+
+// readIOStat parses a cgroup io.stat file, made of lines such as
+// "8:0 rbytes=1 wbytes=2 rios=3 wios=4 dbytes=0 dios=0".
+func readIOStat(path string) ([]ioStats, error) {
+	var stats []ioStats
+	err := scanLines(path, func(fields []string) error {
+		s := ioStats{device: fields[0]}
+		for _, field := range fields[1:] {
+			key, value, ok := strings.Cut(field, "=")
+			if !ok {
+				return fmt.Errorf("invalid field %q", field)
+			}
+			var dst *uint64
+			switch key {
+			case "rbytes":
+				dst = &s.rbytes
+			case "wbytes":
+				dst = &s.wbytes
+			case "rios":
+				dst = &s.rios
+			case "wios":
+				dst = &s.wios
+			default:
+				continue
+			}
+			v, err := strconv.ParseUint(value, 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid field %q: %w", field, err)
+			}
+			*dst = v
+		}
+		stats = append(stats, s)
+		return nil
+	})
+	return stats, err
+}
+
+// readSingleValue parses a file holding a single number, such as
+// memory.current. ok is false when the file holds "max" instead.
+func readSingleValue(path string) (value uint64, ok bool, err error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, false, err
+	}
+	s := strings.TrimSpace(string(data))
+	if s == "max" {
+		return 0, false, nil
+	}
+	value, err = strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return value, true, nil
+}
+
+func scanLines(path string, parse func(fields []string) error) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if err := parse(fields); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+	}
+	return scanner.Err()
+}
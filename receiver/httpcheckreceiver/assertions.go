@@ -0,0 +1,177 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package httpcheckreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/httpcheckreceiver"
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/httpbody"
+)
+
+// The reasons why an assertion fails, reported as the assertion.reason
+// attribute. They are a fixed set to keep the cardinality of the metric low,
+// the details of the failure are only logged.
+const (
+	reasonMissing       = "missing"
+	reasonMismatch      = "mismatch"
+	reasonInvalidJSON   = "invalid_json"
+	reasonRequestFailed = "request_failed"
+)
+
+// assertionResult is the outcome of one assertion. reason and detail are
+// empty when the assertion passed.
+type assertionResult struct {
+	name   string
+	reason string
+	detail string
+}
+
+type headerAssertion struct {
+	name  string
+	value *regexp.Regexp
+}
+
+// assertions holds the compiled assertions of a target.
+type assertions struct {
+	bodyRegex *regexp.Regexp
+	json      []jsonAssertion
+	headers   []headerAssertion
+}
+
+func newAssertions(cfg assertionsConfig) (*assertions, error) {
+	a := &assertions{json: cfg.JSON}
+
+	if cfg.BodyRegex != "" {
+		re, err := regexp.Compile(cfg.BodyRegex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid body_regex: %w", err)
+		}
+		a.bodyRegex = re
+	}
+
+	for _, ja := range cfg.JSON {
+		if ja.Path == "" {
+			return nil, errMissingJSONPath
+		}
+	}
+
+	for name, value := range cfg.Headers {
+		ha := headerAssertion{name: http.CanonicalHeaderKey(name)}
+		if value != "" {
+			re, err := regexp.Compile(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid regular expression for header %q: %w", name, err)
+			}
+			ha.value = re
+		}
+		a.headers = append(a.headers, ha)
+	}
+	sort.Slice(a.headers, func(i, j int) bool { return a.headers[i].name < a.headers[j].name })
+
+	return a, nil
+}
+
+// empty reports whether the target has no assertion.
+func (a *assertions) empty() bool {
+	return a.bodyRegex == nil && len(a.json) == 0 && len(a.headers) == 0
+}
+
+// needsBody reports whether the response body must be read.
+func (a *assertions) needsBody() bool {
+	return a.bodyRegex != nil || len(a.json) > 0
+}
+
+// fail returns a request_failed result for every assertion, for when no
+// response could be obtained.
+func (a *assertions) fail(detail string) []assertionResult {
+	var results []assertionResult
+	if a.bodyRegex != nil {
+		results = append(results, assertionResult{name: "body_regex", reason: reasonRequestFailed, detail: detail})
+	}
+	for _, ja := range a.json {
+		results = append(results, assertionResult{name: "json:" + ja.Path, reason: reasonRequestFailed, detail: detail})
+	}
+	for _, ha := range a.headers {
+		results = append(results, assertionResult{name: "header:" + ha.name, reason: reasonRequestFailed, detail: detail})
+	}
+	return results
+}
+
+// evaluate checks every assertion against the response.
+func (a *assertions) evaluate(header http.Header, body []byte) []assertionResult {
+	var results []assertionResult
+
+	if a.bodyRegex != nil {
+		result := assertionResult{name: "body_regex"}
+		if !a.bodyRegex.Match(body) {
+			result.reason = reasonMismatch
+			result.detail = fmt.Sprintf("body does not match %q", a.bodyRegex.String())
+		}
+		results = append(results, result)
+	}
+
+	if len(a.json) > 0 {
+		var doc any
+		decoder := json.NewDecoder(bytes.NewReader(body))
+		decoder.UseNumber()
+		docErr := decoder.Decode(&doc)
+		for _, ja := range a.json {
+			result := assertionResult{name: "json:" + ja.Path}
+			if docErr != nil {
+				result.reason = reasonInvalidJSON
+				result.detail = fmt.Sprintf("body is not valid JSON: %v", docErr)
+			} else {
+				result.reason, result.detail = ja.check(doc)
+			}
+			results = append(results, result)
+		}
+	}
+
+	for _, ha := range a.headers {
+		result := assertionResult{name: "header:" + ha.name}
+		values, ok := header[ha.name]
+		switch {
+		case !ok:
+			result.reason = reasonMissing
+			result.detail = fmt.Sprintf("header %s is missing", ha.name)
+		case ha.value != nil && !matchesAny(ha.value, values):
+			result.reason = reasonMismatch
+			result.detail = fmt.Sprintf("header %s does not match %q", ha.name, ha.value.String())
+		}
+		results = append(results, result)
+	}
+
+	return results
+}
+
+func matchesAny(re *regexp.Regexp, values []string) bool {
+	for _, v := range values {
+		if re.MatchString(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// check looks up the path in the decoded document and compares the value
+// found there. It returns the reason and the detail of the failure, or empty
+// strings.
+func (ja jsonAssertion) check(doc any) (reason, detail string) {
+	value, ok := httpbody.LookupJSONPath(doc, ja.Path)
+	if !ok {
+		return reasonMissing, fmt.Sprintf("path %s not found", ja.Path)
+	}
+	if ja.Value == "" {
+		return "", ""
+	}
+	if actual := httpbody.JSONValueString(value); actual != ja.Value {
+		return reasonMismatch, fmt.Sprintf("expected %q, got %q", ja.Value, actual)
+	}
+	return "", ""
+}
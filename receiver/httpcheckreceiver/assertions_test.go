@@ -0,0 +1,68 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package httpcheckreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/httpcheckreceiver"
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONAssertions(t *testing.T) {
+	a, err := newAssertions(assertionsConfig{
+		JSON: []jsonAssertion{
+			{Path: "$.data.ready", Value: "true"},
+			{Path: "data.count", Value: "9007199254740993"},
+			{Path: "data.items.1.name", Value: "b"},
+			{Path: "data.items.2.name"},
+			{Path: "data.items.name"},
+			{Path: "data.missing", Value: "null"},
+			{Path: "data.none", Value: "null"},
+		},
+	})
+	require.NoError(t, err)
+
+	body := []byte(`{"data":{"ready":true,"count":9007199254740993,"none":null,"items":[{"name":"a"},{"name":"b"}]}}`)
+	assert.Equal(t, []assertionResult{
+		{name: "json:$.data.ready"},
+		{name: "json:data.count"},
+		{name: "json:data.items.1.name"},
+		{name: "json:data.items.2.name", reason: reasonMissing, detail: "path data.items.2.name not found"},
+		{name: "json:data.items.name", reason: reasonMissing, detail: "path data.items.name not found"},
+		{name: "json:data.missing", reason: reasonMissing, detail: "path data.missing not found"},
+		{name: "json:data.none"},
+	}, a.evaluate(http.Header{}, body))
+
+	results := (&assertions{json: a.json[:1]}).evaluate(http.Header{}, []byte("<html>"))
+	require.Len(t, results, 1)
+	assert.Equal(t, reasonInvalidJSON, results[0].reason)
+	assert.Contains(t, results[0].detail, "body is not valid JSON")
+}
+
+func TestHeaderAssertions(t *testing.T) {
+	a, err := newAssertions(assertionsConfig{
+		Headers: map[string]string{
+			"cache-control": "max-age=[0-9]+",
+			"x-version":     "",
+		},
+	})
+	require.NoError(t, err)
+
+	header := http.Header{}
+	header.Add("Cache-Control", "no-store")
+	header.Add("Cache-Control", "max-age=60")
+	assert.Equal(t, []assertionResult{
+		{name: "header:Cache-Control"},
+		{name: "header:X-Version", reason: reasonMissing, detail: "header X-Version is missing"},
+	}, a.evaluate(header, nil))
+
+	header.Set("Cache-Control", "no-store")
+	header.Set("X-Version", "1.2.3")
+	assert.Equal(t, []assertionResult{
+		{name: "header:Cache-Control", reason: reasonMismatch, detail: `header Cache-Control does not match "max-age=[0-9]+"`},
+		{name: "header:X-Version"},
+	}, a.evaluate(header, nil))
+}
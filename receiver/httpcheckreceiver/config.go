@@ -19,6 +19,7 @@ import (
 var (
 	errMissingEndpoint = errors.New(`"endpoint" must be specified`)
 	errInvalidEndpoint = errors.New(`"endpoint" must be in the form of <scheme>://<hostname>[:<port>]`)
+	errMissingJSONPath = errors.New(`"path" must be specified for json assertions`)
 )
 
 // Config defines the configuration for the various elements of the receiver agent.
@@ -30,7 +31,29 @@ type Config struct {
 
 type targetConfig struct {
 	confighttp.ClientConfig `mapstructure:",squash"`
-	Method                  string `mapstructure:"method"`
+	Method                  string           `mapstructure:"method"`
+	Body                    string           `mapstructure:"body"`
+	Assertions              assertionsConfig `mapstructure:"assertions"`
+}
+
+// assertionsConfig defines the checks made on the response of a target.
+type assertionsConfig struct {
+	// BodyRegex is a regular expression the response body must match.
+	BodyRegex string `mapstructure:"body_regex"`
+	// JSON lists values the JSON response body must contain.
+	JSON []jsonAssertion `mapstructure:"json"`
+	// Headers maps the names of the headers the response must have to a
+	// regular expression their value must match. An empty expression only
+	// requires the header to be present.
+	Headers map[string]string `mapstructure:"headers"`
+}
+
+type jsonAssertion struct {
+	// Path is a dot separated path, e.g. "data.items.0.status".
+	Path string `mapstructure:"path"`
+	// Value is the expected value. Values that are not strings are compared
+	// using their JSON representation. When empty, the path only has to exist.
+	Value string `mapstructure:"value"`
 }
 
 // Validate validates the configuration by checking for missing or invalid fields
@@ -46,6 +69,10 @@ func (cfg *targetConfig) Validate() error {
 		}
 	}
 
+	if _, assertionsErr := newAssertions(cfg.Assertions); assertionsErr != nil {
+		err = multierr.Append(err, assertionsErr)
+	}
+
 	return err
 }
 
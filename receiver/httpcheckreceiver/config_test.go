@@ -4,6 +4,7 @@
 package httpcheckreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/httpcheckreceiver"
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 
@@ -86,6 +87,34 @@ func TestValidate(t *testing.T) {
 				fmt.Errorf("%w: %s", errInvalidEndpoint, `parse "www.opentelemetry.io/docs": invalid URI for request`),
 			),
 		},
+		{
+			desc: "invalid assertions",
+			cfg: &Config{
+				Targets: []*targetConfig{
+					{
+						ClientConfig: confighttp.ClientConfig{
+							Endpoint: "https://opentelemetry.io",
+						},
+						Assertions: assertionsConfig{
+							BodyRegex: "(",
+						},
+					},
+					{
+						ClientConfig: confighttp.ClientConfig{
+							Endpoint: "https://opentelemetry.io",
+						},
+						Assertions: assertionsConfig{
+							JSON: []jsonAssertion{{Value: "up"}},
+						},
+					},
+				},
+				ControllerConfig: scraperhelper.NewDefaultControllerConfig(),
+			},
+			expectedErr: multierr.Combine(
+				errors.New("invalid body_regex: error parsing regexp: missing closing ): `(`"),
+				errMissingJSONPath,
+			),
+		},
 		{
 			desc: "valid config",
 			cfg: &Config{
@@ -94,6 +123,13 @@ func TestValidate(t *testing.T) {
 						ClientConfig: confighttp.ClientConfig{
 							Endpoint: "https://opentelemetry.io",
 						},
+						Method: "POST",
+						Body:   `{"query":"status"}`,
+						Assertions: assertionsConfig{
+							BodyRegex: "ok",
+							JSON:      []jsonAssertion{{Path: "status", Value: "up"}},
+							Headers:   map[string]string{"Content-Type": "^application/json"},
+						},
 					},
 					{
 						ClientConfig: confighttp.ClientConfig{
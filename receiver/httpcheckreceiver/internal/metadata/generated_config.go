@@ -27,13 +27,18 @@ func (ms *MetricConfig) Unmarshal(parser *confmap.Conf) error {
 
 // MetricsConfig provides config for httpcheck metrics.
 type MetricsConfig struct {
-	HttpcheckDuration MetricConfig `mapstructure:"httpcheck.duration"`
-	HttpcheckError    MetricConfig `mapstructure:"httpcheck.error"`
-	HttpcheckStatus   MetricConfig `mapstructure:"httpcheck.status"`
+	HttpcheckAssertionStatus MetricConfig `mapstructure:"httpcheck.assertion.status"`
+	HttpcheckDuration        MetricConfig `mapstructure:"httpcheck.duration"`
+	HttpcheckError           MetricConfig `mapstructure:"httpcheck.error"`
+	HttpcheckStatus          MetricConfig `mapstructure:"httpcheck.status"`
+	HttpcheckTLSNotAfter     MetricConfig `mapstructure:"httpcheck.tls.not_after"`
 }
 
 func DefaultMetricsConfig() MetricsConfig {
 	return MetricsConfig{
+		HttpcheckAssertionStatus: MetricConfig{
+			Enabled: true,
+		},
 		HttpcheckDuration: MetricConfig{
 			Enabled: true,
 		},
@@ -43,6 +48,9 @@ func DefaultMetricsConfig() MetricsConfig {
 		HttpcheckStatus: MetricConfig{
 			Enabled: true,
 		},
+		HttpcheckTLSNotAfter: MetricConfig{
+			Enabled: true,
+		},
 	}
 }
 
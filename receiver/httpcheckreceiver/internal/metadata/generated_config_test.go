@@ -26,9 +26,11 @@ func TestMetricsBuilderConfig(t *testing.T) {
 			name: "all_set",
 			want: MetricsBuilderConfig{
 				Metrics: MetricsConfig{
-					HttpcheckDuration: MetricConfig{Enabled: true},
-					HttpcheckError:    MetricConfig{Enabled: true},
-					HttpcheckStatus:   MetricConfig{Enabled: true},
+					HttpcheckAssertionStatus: MetricConfig{Enabled: true},
+					HttpcheckDuration:        MetricConfig{Enabled: true},
+					HttpcheckError:           MetricConfig{Enabled: true},
+					HttpcheckStatus:          MetricConfig{Enabled: true},
+					HttpcheckTLSNotAfter:     MetricConfig{Enabled: true},
 				},
 			},
 		},
@@ -36,9 +38,11 @@ func TestMetricsBuilderConfig(t *testing.T) {
 			name: "none_set",
 			want: MetricsBuilderConfig{
 				Metrics: MetricsConfig{
-					HttpcheckDuration: MetricConfig{Enabled: false},
-					HttpcheckError:    MetricConfig{Enabled: false},
-					HttpcheckStatus:   MetricConfig{Enabled: false},
+					HttpcheckAssertionStatus: MetricConfig{Enabled: false},
+					HttpcheckDuration:        MetricConfig{Enabled: false},
+					HttpcheckError:           MetricConfig{Enabled: false},
+					HttpcheckStatus:          MetricConfig{Enabled: false},
+					HttpcheckTLSNotAfter:     MetricConfig{Enabled: false},
 				},
 			},
 		},
@@ -59,6 +59,10 @@ func TestMetricsBuilder(t *testing.T) {
 			defaultMetricsCount := 0
 			allMetricsCount := 0
 
+			defaultMetricsCount++
+			allMetricsCount++
+			mb.RecordHttpcheckAssertionStatusDataPoint(ts, 1, "http.url-val", "assertion.name-val", "assertion.reason-val")
+
 			defaultMetricsCount++
 			allMetricsCount++
 			mb.RecordHttpcheckDurationDataPoint(ts, 1, "http.url-val")
@@ -71,6 +75,10 @@ func TestMetricsBuilder(t *testing.T) {
 			allMetricsCount++
 			mb.RecordHttpcheckStatusDataPoint(ts, 1, "http.url-val", 16, "http.method-val", "http.status_class-val")
 
+			defaultMetricsCount++
+			allMetricsCount++
+			mb.RecordHttpcheckTLSNotAfterDataPoint(ts, 1, "http.url-val", "http.tls.issuer-val", []any{"http.tls.san-item1", "http.tls.san-item2"})
+
 			res := pcommon.NewResource()
 			metrics := mb.Emit(WithResource(res))
 
@@ -93,6 +101,27 @@ func TestMetricsBuilder(t *testing.T) {
 			validatedMetrics := make(map[string]bool)
 			for i := 0; i < ms.Len(); i++ {
 				switch ms.At(i).Name() {
+				case "httpcheck.assertion.status":
+					assert.False(t, validatedMetrics["httpcheck.assertion.status"], "Found a duplicate in the metrics slice: httpcheck.assertion.status")
+					validatedMetrics["httpcheck.assertion.status"] = true
+					assert.Equal(t, pmetric.MetricTypeGauge, ms.At(i).Type())
+					assert.Equal(t, 1, ms.At(i).Gauge().DataPoints().Len())
+					assert.Equal(t, "1 if the assertion passed, otherwise 0.", ms.At(i).Description())
+					assert.Equal(t, "1", ms.At(i).Unit())
+					dp := ms.At(i).Gauge().DataPoints().At(0)
+					assert.Equal(t, start, dp.StartTimestamp())
+					assert.Equal(t, ts, dp.Timestamp())
+					assert.Equal(t, pmetric.NumberDataPointValueTypeInt, dp.ValueType())
+					assert.Equal(t, int64(1), dp.IntValue())
+					attrVal, ok := dp.Attributes().Get("http.url")
+					assert.True(t, ok)
+					assert.EqualValues(t, "http.url-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("assertion.name")
+					assert.True(t, ok)
+					assert.EqualValues(t, "assertion.name-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("assertion.reason")
+					assert.True(t, ok)
+					assert.EqualValues(t, "assertion.reason-val", attrVal.Str())
 				case "httpcheck.duration":
 					assert.False(t, validatedMetrics["httpcheck.duration"], "Found a duplicate in the metrics slice: httpcheck.duration")
 					validatedMetrics["httpcheck.duration"] = true
@@ -154,6 +183,27 @@ func TestMetricsBuilder(t *testing.T) {
 					attrVal, ok = dp.Attributes().Get("http.status_class")
 					assert.True(t, ok)
 					assert.EqualValues(t, "http.status_class-val", attrVal.Str())
+				case "httpcheck.tls.not_after":
+					assert.False(t, validatedMetrics["httpcheck.tls.not_after"], "Found a duplicate in the metrics slice: httpcheck.tls.not_after")
+					validatedMetrics["httpcheck.tls.not_after"] = true
+					assert.Equal(t, pmetric.MetricTypeGauge, ms.At(i).Type())
+					assert.Equal(t, 1, ms.At(i).Gauge().DataPoints().Len())
+					assert.Equal(t, "Time after which the leaf certificate presented by the target is no longer valid, in seconds since the Unix epoch.", ms.At(i).Description())
+					assert.Equal(t, "s", ms.At(i).Unit())
+					dp := ms.At(i).Gauge().DataPoints().At(0)
+					assert.Equal(t, start, dp.StartTimestamp())
+					assert.Equal(t, ts, dp.Timestamp())
+					assert.Equal(t, pmetric.NumberDataPointValueTypeInt, dp.ValueType())
+					assert.Equal(t, int64(1), dp.IntValue())
+					attrVal, ok := dp.Attributes().Get("http.url")
+					assert.True(t, ok)
+					assert.EqualValues(t, "http.url-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("http.tls.issuer")
+					assert.True(t, ok)
+					assert.EqualValues(t, "http.tls.issuer-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("http.tls.san")
+					assert.True(t, ok)
+					assert.EqualValues(t, []any{"http.tls.san-item1", "http.tls.san-item2"}, attrVal.Slice().AsRaw())
 				}
 			}
 		})
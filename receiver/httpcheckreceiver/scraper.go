@@ -5,8 +5,12 @@ package httpcheckreceiver // import "github.com/open-telemetry/opentelemetry-col
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"strings"
 	"sync"
 	"time"
 
@@ -17,6 +21,7 @@ import (
 	"go.uber.org/multierr"
 	"go.uber.org/zap"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/httpbody"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/httpcheckreceiver/internal/metadata"
 )
 
@@ -26,10 +31,11 @@ var (
 )
 
 type httpcheckScraper struct {
-	clients  []*http.Client
-	cfg      *Config
-	settings component.TelemetrySettings
-	mb       *metadata.MetricsBuilder
+	clients    []*http.Client
+	assertions []*assertions
+	cfg        *Config
+	settings   component.TelemetrySettings
+	mb         *metadata.MetricsBuilder
 }
 
 // start starts the scraper by creating a new HTTP Client on the scraper
@@ -40,6 +46,12 @@ func (h *httpcheckScraper) start(ctx context.Context, host component.Host) (err
 			err = multierr.Append(err, clentErr)
 		}
 		h.clients = append(h.clients, client)
+
+		targetAssertions, assertionsErr := newAssertions(target.Assertions)
+		if assertionsErr != nil {
+			err = multierr.Append(err, assertionsErr)
+		}
+		h.assertions = append(h.assertions, targetAssertions)
 	}
 	return
 }
@@ -59,8 +71,14 @@ func (h *httpcheckScraper) scrape(ctx context.Context) (pmetric.Metrics, error)
 			defer wg.Done()
 
 			now := pcommon.NewTimestampFromTime(time.Now())
+			target := h.cfg.Targets[targetIndex]
+			targetAssertions := h.assertions[targetIndex]
 
-			req, err := http.NewRequestWithContext(ctx, h.cfg.Targets[targetIndex].Method, h.cfg.Targets[targetIndex].Endpoint, http.NoBody)
+			var body io.Reader = http.NoBody
+			if target.Body != "" {
+				body = strings.NewReader(target.Body)
+			}
+			req, err := http.NewRequestWithContext(ctx, target.Method, target.Endpoint, body)
 			if err != nil {
 				h.settings.Logger.Error("failed to create request", zap.Error(err))
 				return
@@ -68,23 +86,45 @@ func (h *httpcheckScraper) scrape(ctx context.Context) (pmetric.Metrics, error)
 
 			start := time.Now()
 			resp, err := targetClient.Do(req)
+			var assertionResults []assertionResult
+			if err == nil {
+				assertionResults = h.checkResponse(resp, targetAssertions)
+			} else if targetAssertions != nil {
+				assertionResults = targetAssertions.fail(err.Error())
+			}
+
 			mux.Lock()
-			h.mb.RecordHttpcheckDurationDataPoint(now, time.Since(start).Milliseconds(), h.cfg.Targets[targetIndex].Endpoint)
+			h.mb.RecordHttpcheckDurationDataPoint(now, time.Since(start).Milliseconds(), target.Endpoint)
 
 			statusCode := 0
 			if err != nil {
-				h.mb.RecordHttpcheckErrorDataPoint(now, int64(1), h.cfg.Targets[targetIndex].Endpoint, err.Error())
+				h.mb.RecordHttpcheckErrorDataPoint(now, int64(1), target.Endpoint, err.Error())
 			} else {
 				statusCode = resp.StatusCode
+				h.recordCertificate(now, target.Endpoint, resp.TLS)
 			}
 
 			for class, intVal := range httpResponseClasses {
 				if statusCode/100 == intVal {
-					h.mb.RecordHttpcheckStatusDataPoint(now, int64(1), h.cfg.Targets[targetIndex].Endpoint, int64(statusCode), req.Method, class)
+					h.mb.RecordHttpcheckStatusDataPoint(now, int64(1), target.Endpoint, int64(statusCode), req.Method, class)
 				} else {
-					h.mb.RecordHttpcheckStatusDataPoint(now, int64(0), h.cfg.Targets[targetIndex].Endpoint, int64(statusCode), req.Method, class)
+					h.mb.RecordHttpcheckStatusDataPoint(now, int64(0), target.Endpoint, int64(statusCode), req.Method, class)
 				}
 			}
+
+			for _, result := range assertionResults {
+				passed := int64(0)
+				if result.reason == "" {
+					passed = 1
+				} else {
+					h.settings.Logger.Debug("assertion failed",
+						zap.String("endpoint", target.Endpoint),
+						zap.String("assertion", result.name),
+						zap.String("reason", result.reason),
+						zap.String("detail", result.detail))
+				}
+				h.mb.RecordHttpcheckAssertionStatusDataPoint(now, passed, target.Endpoint, result.name, result.reason)
+			}
 			mux.Unlock()
 		}(client, idx)
 	}
@@ -94,6 +134,51 @@ func (h *httpcheckScraper) scrape(ctx context.Context) (pmetric.Metrics, error)
 	return h.mb.Emit(), nil
 }
 
+// checkResponse evaluates the assertions of the target, if any, and closes
+// the response body.
+func (h *httpcheckScraper) checkResponse(resp *http.Response, targetAssertions *assertions) []assertionResult {
+	defer resp.Body.Close()
+
+	if targetAssertions == nil || targetAssertions.empty() {
+		return nil
+	}
+
+	var body []byte
+	if targetAssertions.needsBody() {
+		var err error
+		body, err = httpbody.Read(resp.Body)
+		if err != nil {
+			return targetAssertions.fail(fmt.Sprintf("failed to read body: %v", err))
+		}
+	}
+	return targetAssertions.evaluate(resp.Header, body)
+}
+
+// recordCertificate records the expiry of the leaf certificate presented by
+// the target, when the connection used TLS.
+func (h *httpcheckScraper) recordCertificate(now pcommon.Timestamp, endpoint string, state *tls.ConnectionState) {
+	if state == nil || len(state.PeerCertificates) == 0 {
+		return
+	}
+	leaf := state.PeerCertificates[0]
+
+	sans := make([]any, 0, len(leaf.DNSNames)+len(leaf.IPAddresses)+len(leaf.EmailAddresses)+len(leaf.URIs))
+	for _, name := range leaf.DNSNames {
+		sans = append(sans, name)
+	}
+	for _, ip := range leaf.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+	for _, email := range leaf.EmailAddresses {
+		sans = append(sans, email)
+	}
+	for _, uri := range leaf.URIs {
+		sans = append(sans, uri.String())
+	}
+
+	h.mb.RecordHttpcheckTLSNotAfterDataPoint(now, leaf.NotAfter.Unix(), endpoint, leaf.Issuer.String(), sans)
+}
+
 func newScraper(conf *Config, settings receiver.CreateSettings) *httpcheckScraper {
 	return &httpcheckScraper{
 		cfg:      conf,
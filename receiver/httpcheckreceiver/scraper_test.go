@@ -5,14 +5,17 @@ package httpcheckreceiver // import "github.com/open-telemetry/opentelemetry-col
 
 import (
 	"context"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"path/filepath"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/config/configopaque"
 	"go.opentelemetry.io/collector/config/configtls"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/receiver/receivertest"
@@ -233,3 +236,125 @@ func TestScraperMultipleTargets(t *testing.T) {
 		pmetrictest.IgnoreTimestamp(),
 	))
 }
+
+func TestScraperTLSCertificate(t *testing.T) {
+	ms := httptest.NewTLSServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer ms.Close()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Targets = []*targetConfig{{
+		ClientConfig: confighttp.ClientConfig{
+			Endpoint: ms.URL,
+			TLSSetting: configtls.ClientConfig{
+				InsecureSkipVerify: true,
+			},
+		}},
+	}
+	scraper := newScraper(cfg, receivertest.NewNopCreateSettings())
+	require.NoError(t, scraper.start(context.Background(), componenttest.NewNopHost()))
+
+	actualMetrics, err := scraper.scrape(context.Background())
+	require.NoError(t, err)
+
+	goldenPath := filepath.Join("testdata", "expected_metrics", "tls.yaml")
+	expectedMetrics, err := golden.ReadMetrics(goldenPath)
+	require.NoError(t, err)
+
+	require.NoError(t, pmetrictest.CompareMetrics(expectedMetrics, actualMetrics,
+		pmetrictest.IgnoreMetricAttributeValue("http.url"),
+		pmetrictest.IgnoreMetricValues("httpcheck.duration"),
+		pmetrictest.IgnoreMetricDataPointsOrder(),
+		pmetrictest.IgnoreStartTimestamp(),
+		pmetrictest.IgnoreTimestamp(),
+	))
+}
+
+func TestScraperAssertions(t *testing.T) {
+	ms := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		body, err := io.ReadAll(req.Body)
+		assert.NoError(t, err)
+		if req.Method != http.MethodPost || req.Header.Get("X-Check") != "synthetic" || string(body) != `{"query":"status"}` {
+			rw.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		rw.Header().Set("Content-Type", "application/json")
+		rw.WriteHeader(http.StatusOK)
+		_, err = rw.Write([]byte(`{"status":"degraded","checks":[{"name":"db","latency":12}]}`))
+		assert.NoError(t, err)
+	}))
+	defer ms.Close()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Targets = []*targetConfig{{
+		ClientConfig: confighttp.ClientConfig{
+			Endpoint: ms.URL,
+			Headers: map[string]configopaque.String{
+				"X-Check": "synthetic",
+			},
+		},
+		Method: http.MethodPost,
+		Body:   `{"query":"status"}`,
+		Assertions: assertionsConfig{
+			BodyRegex: `"name":"db"`,
+			JSON: []jsonAssertion{
+				{Path: "status", Value: "up"},
+				{Path: "checks.0.latency", Value: "12"},
+				{Path: "checks.1"},
+			},
+			Headers: map[string]string{
+				"content-type": "^application/json$",
+				"X-Request-Id": "",
+			},
+		},
+	}}
+	scraper := newScraper(cfg, receivertest.NewNopCreateSettings())
+	require.NoError(t, scraper.start(context.Background(), componenttest.NewNopHost()))
+
+	actualMetrics, err := scraper.scrape(context.Background())
+	require.NoError(t, err)
+
+	goldenPath := filepath.Join("testdata", "expected_metrics", "assertions.yaml")
+	expectedMetrics, err := golden.ReadMetrics(goldenPath)
+	require.NoError(t, err)
+
+	require.NoError(t, pmetrictest.CompareMetrics(expectedMetrics, actualMetrics,
+		pmetrictest.IgnoreMetricAttributeValue("http.url"),
+		pmetrictest.IgnoreMetricValues("httpcheck.duration"),
+		pmetrictest.IgnoreMetricDataPointsOrder(),
+		pmetrictest.IgnoreStartTimestamp(),
+		pmetrictest.IgnoreTimestamp(),
+	))
+}
+
+func TestScraperAssertionsRequestFailed(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Targets = []*targetConfig{{
+		ClientConfig: confighttp.ClientConfig{
+			Endpoint: "http://invalid-endpoint",
+		},
+		Assertions: assertionsConfig{
+			BodyRegex: "ok",
+		},
+	}}
+	scraper := newScraper(cfg, receivertest.NewNopCreateSettings())
+	require.NoError(t, scraper.start(context.Background(), componenttest.NewNopHost()))
+
+	actualMetrics, err := scraper.scrape(context.Background())
+	require.NoError(t, err)
+
+	var found bool
+	metrics := actualMetrics.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	for i := 0; i < metrics.Len(); i++ {
+		if metrics.At(i).Name() != "httpcheck.assertion.status" {
+			continue
+		}
+		found = true
+		dp := metrics.At(i).Gauge().DataPoints().At(0)
+		assert.Equal(t, int64(0), dp.IntValue())
+		reason, _ := dp.Attributes().Get("assertion.reason")
+		assert.Equal(t, "request_failed", reason.Str())
+	}
+	assert.True(t, found)
+}
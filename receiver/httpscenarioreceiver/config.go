@@ -0,0 +1,178 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package httpscenarioreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/httpscenarioreceiver"
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/receiver/scraperhelper"
+	"go.uber.org/multierr"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/httpscenarioreceiver/internal/metadata"
+)
+
+// Predefined error responses for configuration validation failures
+var (
+	errNoScenarios         = errors.New("no scenarios configured")
+	errMissingScenarioName = errors.New(`"name" must be specified for every scenario`)
+	errMissingStepName     = errors.New(`"name" must be specified for every step`)
+	errMissingURL          = errors.New(`"url" must be specified for every step`)
+	errMissingVariable     = errors.New(`"variable" must be specified for every extraction`)
+)
+
+// Config defines the configuration for the HTTP scenario receiver.
+type Config struct {
+	scraperhelper.ControllerConfig `mapstructure:",squash"`
+	metadata.MetricsBuilderConfig  `mapstructure:",squash"`
+	Scenarios                      []*ScenarioConfig `mapstructure:"scenarios"`
+}
+
+// ScenarioConfig defines an ordered list of requests sharing one HTTP client,
+// one cookie jar and one set of variables.
+type ScenarioConfig struct {
+	// ClientConfig configures the HTTP client of the scenario. Its endpoint,
+	// when set, is the base URL that relative step URLs are resolved against.
+	confighttp.ClientConfig `mapstructure:",squash"`
+
+	// Name identifies the scenario in the metrics and traces.
+	Name string `mapstructure:"name"`
+
+	// PropagateTraceContext adds a W3C traceparent header to every request so
+	// that the spans of the target join the trace of the scenario.
+	PropagateTraceContext bool `mapstructure:"propagate_trace_context"`
+
+	// Steps are run in order until one of them fails.
+	Steps []StepConfig `mapstructure:"steps"`
+}
+
+// StepConfig defines one request of a scenario. The URL, header values and
+// body can refer to the variables extracted by the previous steps with
+// {{ variable }}.
+type StepConfig struct {
+	Name    string            `mapstructure:"name"`
+	Method  string            `mapstructure:"method"`
+	URL     string            `mapstructure:"url"`
+	Headers map[string]string `mapstructure:"headers"`
+	Body    string            `mapstructure:"body"`
+
+	// ExpectedStatus lists the status codes for which the step succeeds. By
+	// default, every status code below 400 is accepted.
+	ExpectedStatus []int `mapstructure:"expected_status"`
+
+	// Extract lists the values taken from the response for the next steps.
+	Extract []ExtractConfig `mapstructure:"extract"`
+}
+
+// ExtractConfig defines a value taken from a response. Exactly one of JSON,
+// Header and Cookie must be set.
+type ExtractConfig struct {
+	// Variable is the name the value is referred to by the next steps.
+	Variable string `mapstructure:"variable"`
+	// JSON is a dot separated path in the JSON response body, e.g. "data.token".
+	JSON string `mapstructure:"json"`
+	// Header is the name of a response header.
+	Header string `mapstructure:"header"`
+	// Cookie is the name of a cookie set by the response.
+	Cookie string `mapstructure:"cookie"`
+}
+
+// Validate validates the configuration by checking for missing or invalid fields
+func (cfg *Config) Validate() error {
+	var err error
+
+	if len(cfg.Scenarios) == 0 {
+		err = multierr.Append(err, errNoScenarios)
+	}
+
+	names := make(map[string]bool)
+	for _, scenario := range cfg.Scenarios {
+		if names[scenario.Name] {
+			err = multierr.Append(err, fmt.Errorf("duplicate scenario name %q", scenario.Name))
+		}
+		names[scenario.Name] = true
+		err = multierr.Append(err, scenario.validate())
+	}
+
+	return err
+}
+
+// validate checks the scenario for missing or invalid fields, and that
+// variables are only used after the step extracting them.
+func (cfg *ScenarioConfig) validate() error {
+	var err error
+
+	if cfg.Name == "" {
+		err = multierr.Append(err, errMissingScenarioName)
+	}
+	if cfg.Endpoint != "" {
+		if _, parseErr := url.ParseRequestURI(cfg.Endpoint); parseErr != nil {
+			err = multierr.Append(err, fmt.Errorf("scenario %q: invalid endpoint: %w", cfg.Name, parseErr))
+		}
+	}
+	if len(cfg.Steps) == 0 {
+		err = multierr.Append(err, fmt.Errorf("scenario %q: no steps configured", cfg.Name))
+	}
+
+	defined := make(map[string]bool)
+	steps := make(map[string]bool)
+	for _, step := range cfg.Steps {
+		if step.Name == "" {
+			err = multierr.Append(err, fmt.Errorf("scenario %q: %w", cfg.Name, errMissingStepName))
+		} else if steps[step.Name] {
+			err = multierr.Append(err, fmt.Errorf("scenario %q: duplicate step name %q", cfg.Name, step.Name))
+		}
+		steps[step.Name] = true
+
+		if step.URL == "" {
+			err = multierr.Append(err, fmt.Errorf("scenario %q, step %q: %w", cfg.Name, step.Name, errMissingURL))
+		}
+
+		templates := []string{step.URL, step.Body}
+		headerNames := make([]string, 0, len(step.Headers))
+		for name := range step.Headers {
+			headerNames = append(headerNames, name)
+		}
+		sort.Strings(headerNames)
+		for _, name := range headerNames {
+			templates = append(templates, step.Headers[name])
+		}
+		for _, tmpl := range templates {
+			for _, variable := range templateVariables(tmpl) {
+				if !defined[variable] {
+					err = multierr.Append(err, fmt.Errorf("scenario %q, step %q: variable %q is not extracted by a previous step", cfg.Name, step.Name, variable))
+				}
+			}
+		}
+
+		for _, extract := range step.Extract {
+			if extractErr := extract.validate(); extractErr != nil {
+				err = multierr.Append(err, fmt.Errorf("scenario %q, step %q: %w", cfg.Name, step.Name, extractErr))
+			}
+			defined[extract.Variable] = true
+		}
+	}
+
+	return err
+}
+
+// validate checks that the extraction has a variable and exactly one source.
+func (cfg *ExtractConfig) validate() error {
+	if cfg.Variable == "" {
+		return errMissingVariable
+	}
+	sources := 0
+	for _, source := range []string{cfg.JSON, cfg.Header, cfg.Cookie} {
+		if source != "" {
+			sources++
+		}
+	}
+	if sources != 1 {
+		return fmt.Errorf("exactly one of \"json\", \"header\" or \"cookie\" must be specified to extract %q", cfg.Variable)
+	}
+	return nil
+}
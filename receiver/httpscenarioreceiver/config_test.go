@@ -0,0 +1,177 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package httpscenarioreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/httpscenarioreceiver"
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/confmap/confmaptest"
+	"go.uber.org/multierr"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/httpscenarioreceiver/internal/metadata"
+)
+
+func TestLoadConfig(t *testing.T) {
+	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config.yaml"))
+	require.NoError(t, err)
+
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig()
+	sub, err := cm.Sub(component.NewID(metadata.Type).String())
+	require.NoError(t, err)
+	require.NoError(t, sub.Unmarshal(cfg))
+	require.NoError(t, component.ValidateConfig(cfg))
+
+	expected := factory.CreateDefaultConfig().(*Config)
+	expected.CollectionInterval = 30 * time.Second
+	expected.Scenarios = []*ScenarioConfig{
+		{
+			ClientConfig:          confighttp.ClientConfig{Endpoint: "https://shop.example.com"},
+			Name:                  "checkout",
+			PropagateTraceContext: true,
+			Steps: []StepConfig{
+				{
+					Name:           "login",
+					Method:         "POST",
+					URL:            "/api/login",
+					Headers:        map[string]string{"Content-Type": "application/json"},
+					Body:           `{"user": "synthetic", "password": "secret"}`,
+					ExpectedStatus: []int{200},
+					Extract: []ExtractConfig{
+						{Variable: "token", JSON: "data.token"},
+						{Variable: "session", Cookie: "session_id"},
+					},
+				},
+				{
+					Name:    "cart",
+					URL:     "/api/cart",
+					Headers: map[string]string{"Authorization": "Bearer {{ token }}"},
+					Extract: []ExtractConfig{
+						{Variable: "item", JSON: "items.0.id"},
+					},
+				},
+				{
+					Name:           "checkout",
+					Method:         "POST",
+					URL:            "/api/checkout/{{ item }}",
+					ExpectedStatus: []int{201, 202},
+				},
+			},
+		},
+	}
+	assert.Equal(t, expected, cfg)
+}
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		scenarios   []*ScenarioConfig
+		expectedErr error
+	}{
+		{
+			desc:        "no scenarios",
+			expectedErr: errNoScenarios,
+		},
+		{
+			desc: "missing names and url",
+			scenarios: []*ScenarioConfig{
+				{
+					Steps: []StepConfig{{}},
+				},
+			},
+			expectedErr: multierr.Combine(
+				errMissingScenarioName,
+				fmt.Errorf("scenario %q: %w", "", errMissingStepName),
+				fmt.Errorf("scenario %q, step %q: %w", "", "", errMissingURL),
+			),
+		},
+		{
+			desc: "duplicate names and no steps",
+			scenarios: []*ScenarioConfig{
+				{
+					Name:  "a",
+					Steps: []StepConfig{{Name: "s", URL: "http://localhost"}, {Name: "s", URL: "http://localhost"}},
+				},
+				{
+					Name: "a",
+				},
+			},
+			expectedErr: multierr.Combine(
+				errors.New(`scenario "a": duplicate step name "s"`),
+				errors.New(`duplicate scenario name "a"`),
+				errors.New(`scenario "a": no steps configured`),
+			),
+		},
+		{
+			desc: "variable used before it is extracted",
+			scenarios: []*ScenarioConfig{
+				{
+					Name: "a",
+					Steps: []StepConfig{
+						{Name: "first", URL: "http://localhost/{{ id }}"},
+						{Name: "second", URL: "http://localhost", Extract: []ExtractConfig{{Variable: "id", JSON: "id"}}},
+						{Name: "third", URL: "http://localhost/{{ id }}", Headers: map[string]string{"X-Token": "{{ token }}"}},
+					},
+				},
+			},
+			expectedErr: multierr.Combine(
+				errors.New(`scenario "a", step "first": variable "id" is not extracted by a previous step`),
+				errors.New(`scenario "a", step "third": variable "token" is not extracted by a previous step`),
+			),
+		},
+		{
+			desc: "invalid extractions",
+			scenarios: []*ScenarioConfig{
+				{
+					Name: "a",
+					Steps: []StepConfig{
+						{
+							Name: "first",
+							URL:  "http://localhost",
+							Extract: []ExtractConfig{
+								{JSON: "id"},
+								{Variable: "id"},
+								{Variable: "token", JSON: "token", Header: "X-Token"},
+							},
+						},
+					},
+				},
+			},
+			expectedErr: multierr.Combine(
+				fmt.Errorf("scenario %q, step %q: %w", "a", "first", errMissingVariable),
+				errors.New(`scenario "a", step "first": exactly one of "json", "header" or "cookie" must be specified to extract "id"`),
+				errors.New(`scenario "a", step "first": exactly one of "json", "header" or "cookie" must be specified to extract "token"`),
+			),
+		},
+		{
+			desc: "invalid endpoint",
+			scenarios: []*ScenarioConfig{
+				{
+					ClientConfig: confighttp.ClientConfig{Endpoint: "not a url"},
+					Name:         "a",
+					Steps:        []StepConfig{{Name: "first", URL: "/"}},
+				},
+			},
+			expectedErr: errors.New(`scenario "a": invalid endpoint: parse "not a url": invalid URI for request`),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.Scenarios = tc.scenarios
+			err := cfg.Validate()
+			require.Error(t, err)
+			require.EqualError(t, err, tc.expectedErr.Error())
+		})
+	}
+}
@@ -0,0 +1,8 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:generate mdatagen metadata.yaml
+
+// Package httpscenarioreceiver runs multi-step HTTP scenarios and reports
+// them as traces and metrics.
+package httpscenarioreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/httpscenarioreceiver"
@@ -0,0 +1,70 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package httpscenarioreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/httpscenarioreceiver"
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/receiver"
+	"go.opentelemetry.io/collector/receiver/scraperhelper"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/sharedcomponent"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/httpscenarioreceiver/internal/metadata"
+)
+
+var errConfigNotHTTPScenario = errors.New("config was not a HTTP scenario receiver config")
+
+// receivers holds the receivers by configuration, so that the metrics and
+// traces pipelines share the same scenario runs.
+var receivers = sharedcomponent.NewSharedComponents()
+
+// NewFactory creates a new receiver factory
+func NewFactory() receiver.Factory {
+	return receiver.NewFactory(
+		metadata.Type,
+		createDefaultConfig,
+		receiver.WithMetrics(createMetricsReceiver, metadata.MetricsStability),
+		receiver.WithTraces(createTracesReceiver, metadata.TracesStability))
+}
+
+func createDefaultConfig() component.Config {
+	cfg := scraperhelper.NewDefaultControllerConfig()
+	cfg.CollectionInterval = 60 * time.Second
+
+	return &Config{
+		ControllerConfig:     cfg,
+		MetricsBuilderConfig: metadata.DefaultMetricsBuilderConfig(),
+		Scenarios:            []*ScenarioConfig{},
+	}
+}
+
+func createMetricsReceiver(_ context.Context, params receiver.CreateSettings, rConf component.Config, nextConsumer consumer.Metrics) (receiver.Metrics, error) {
+	cfg, ok := rConf.(*Config)
+	if !ok {
+		return nil, errConfigNotHTTPScenario
+	}
+
+	r := receivers.GetOrAdd(cfg, func() component.Component {
+		return newReceiver(cfg, params)
+	})
+	r.Unwrap().(*scenarioReceiver).nextMetrics = nextConsumer
+	return r, nil
+}
+
+func createTracesReceiver(_ context.Context, params receiver.CreateSettings, rConf component.Config, nextConsumer consumer.Traces) (receiver.Traces, error) {
+	cfg, ok := rConf.(*Config)
+	if !ok {
+		return nil, errConfigNotHTTPScenario
+	}
+
+	r := receivers.GetOrAdd(cfg, func() component.Component {
+		return newReceiver(cfg, params)
+	})
+	r.Unwrap().(*scenarioReceiver).nextTraces = nextConsumer
+	return r, nil
+}
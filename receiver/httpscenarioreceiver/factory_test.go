@@ -0,0 +1,51 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package httpscenarioreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/httpscenarioreceiver"
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+	"go.opentelemetry.io/collector/receiver/scraperhelper"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/httpscenarioreceiver/internal/metadata"
+)
+
+func TestNewFactory(t *testing.T) {
+	factory := NewFactory()
+	require.EqualValues(t, metadata.Type, factory.Type())
+
+	var expectedCfg component.Config = &Config{
+		ControllerConfig: scraperhelper.ControllerConfig{
+			CollectionInterval: 60 * time.Second,
+			InitialDelay:       time.Second,
+		},
+		MetricsBuilderConfig: metadata.DefaultMetricsBuilderConfig(),
+		Scenarios:            []*ScenarioConfig{},
+	}
+	require.Equal(t, expectedCfg, factory.CreateDefaultConfig())
+}
+
+func TestCreateReceivers(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig()
+
+	metricsReceiver, err := factory.CreateMetricsReceiver(context.Background(), receivertest.NewNopCreateSettings(), cfg, consumertest.NewNop())
+	require.NoError(t, err)
+	tracesReceiver, err := factory.CreateTracesReceiver(context.Background(), receivertest.NewNopCreateSettings(), cfg, consumertest.NewNop())
+	require.NoError(t, err)
+
+	// Both pipelines share the scenario runs of the same configuration.
+	require.Same(t, metricsReceiver, tracesReceiver)
+
+	_, err = factory.CreateMetricsReceiver(context.Background(), receivertest.NewNopCreateSettings(), nil, consumertest.NewNop())
+	require.ErrorIs(t, err, errConfigNotHTTPScenario)
+	_, err = factory.CreateTracesReceiver(context.Background(), receivertest.NewNopCreateSettings(), nil, consumertest.NewNop())
+	require.ErrorIs(t, err, errConfigNotHTTPScenario)
+}
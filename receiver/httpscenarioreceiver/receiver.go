@@ -0,0 +1,217 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package httpscenarioreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/httpscenarioreceiver"
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/receiver"
+	"go.opentelemetry.io/collector/receiver/receiverhelper"
+	"go.opentelemetry.io/collector/receiver/scraperhelper"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/httpscenarioreceiver/internal/metadata"
+)
+
+const (
+	attributeScenarioName   = "scenario.name"
+	attributeStepName       = "step.name"
+	attributeHTTPMethod     = "http.request.method"
+	attributeHTTPStatusCode = "http.response.status_code"
+	attributeURLFull        = "url.full"
+)
+
+// scenarioReceiver runs the scenarios on the schedule of a scraper
+// controller. It is shared between the metrics and traces pipelines, and
+// reports the runs as metrics and traces to whichever are configured.
+type scenarioReceiver struct {
+	cfg         *Config
+	settings    receiver.CreateSettings
+	nextMetrics consumer.Metrics
+	nextTraces  consumer.Traces
+	controller  receiver.Metrics
+}
+
+func newReceiver(cfg *Config, settings receiver.CreateSettings) *scenarioReceiver {
+	return &scenarioReceiver{cfg: cfg, settings: settings}
+}
+
+func (r *scenarioReceiver) Start(ctx context.Context, host component.Host) error {
+	nextMetrics := r.nextMetrics
+	if nextMetrics == nil {
+		// The scraper controller needs a metrics consumer even when the
+		// receiver is only used in traces pipelines.
+		nextMetrics, _ = consumer.NewMetrics(func(context.Context, pmetric.Metrics) error { return nil })
+	}
+
+	s, err := newScraper(r.cfg, r.settings, r.nextTraces)
+	if err != nil {
+		return err
+	}
+	scraper, err := scraperhelper.NewScraper(metadata.Type.String(), s.scrape, scraperhelper.WithStart(s.start))
+	if err != nil {
+		return err
+	}
+	r.controller, err = scraperhelper.NewScraperControllerReceiver(&r.cfg.ControllerConfig, r.settings, nextMetrics, scraperhelper.AddScraper(scraper))
+	if err != nil {
+		return err
+	}
+	return r.controller.Start(ctx, host)
+}
+
+func (r *scenarioReceiver) Shutdown(ctx context.Context) error {
+	if r.controller == nil {
+		return nil
+	}
+	return r.controller.Shutdown(ctx)
+}
+
+type scenarioScraper struct {
+	cfg        *Config
+	settings   receiver.CreateSettings
+	mb         *metadata.MetricsBuilder
+	runners    []*scenarioRunner
+	nextTraces consumer.Traces
+	obsrecv    *receiverhelper.ObsReport
+}
+
+func newScraper(cfg *Config, settings receiver.CreateSettings, nextTraces consumer.Traces) (*scenarioScraper, error) {
+	obsrecv, err := receiverhelper.NewObsReport(receiverhelper.ObsReportSettings{
+		ReceiverID:             settings.ID,
+		Transport:              "http",
+		ReceiverCreateSettings: settings,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &scenarioScraper{
+		cfg:        cfg,
+		settings:   settings,
+		mb:         metadata.NewMetricsBuilder(cfg.MetricsBuilderConfig, settings),
+		nextTraces: nextTraces,
+		obsrecv:    obsrecv,
+	}, nil
+}
+
+// start creates the HTTP client of every scenario.
+func (s *scenarioScraper) start(ctx context.Context, host component.Host) error {
+	var err error
+	for _, scenario := range s.cfg.Scenarios {
+		client, clientErr := scenario.ToClient(ctx, host, s.settings.TelemetrySettings)
+		if clientErr != nil {
+			err = multierr.Append(err, clientErr)
+			continue
+		}
+		runner, runnerErr := newScenarioRunner(scenario, client)
+		if runnerErr != nil {
+			err = multierr.Append(err, runnerErr)
+			continue
+		}
+		s.runners = append(s.runners, runner)
+	}
+	return err
+}
+
+// scrape runs every scenario concurrently, sends the traces and returns the
+// metrics.
+func (s *scenarioScraper) scrape(ctx context.Context) (pmetric.Metrics, error) {
+	results := make([]scenarioResult, len(s.runners))
+	var wg sync.WaitGroup
+	wg.Add(len(s.runners))
+	for i, runner := range s.runners {
+		go func(i int, runner *scenarioRunner) {
+			defer wg.Done()
+			results[i] = runner.run(ctx)
+		}(i, runner)
+	}
+	wg.Wait()
+
+	now := pcommon.NewTimestampFromTime(time.Now())
+	for _, result := range results {
+		s.recordMetrics(now, result)
+	}
+
+	if s.nextTraces != nil {
+		td := buildTraces(results)
+		obsCtx := s.obsrecv.StartTracesOp(ctx)
+		err := s.nextTraces.ConsumeTraces(obsCtx, td)
+		s.obsrecv.EndTracesOp(obsCtx, metadata.Type.String(), td.SpanCount(), err)
+		if err != nil {
+			s.settings.Logger.Error("Failed to send the traces of the scenarios", zap.Error(err))
+		}
+	}
+
+	return s.mb.Emit(), nil
+}
+
+func (s *scenarioScraper) recordMetrics(now pcommon.Timestamp, result scenarioResult) {
+	status := int64(1)
+	if result.failedStep != "" {
+		status = 0
+	}
+	s.mb.RecordHttpscenarioStatusDataPoint(now, status, result.name, result.failedStep)
+	s.mb.RecordHttpscenarioDurationDataPoint(now, result.end.Sub(result.start).Milliseconds(), result.name)
+	for _, step := range result.steps {
+		s.mb.RecordHttpscenarioStepDurationDataPoint(now, step.end.Sub(step.start).Milliseconds(), result.name, step.name, int64(step.statusCode))
+	}
+}
+
+// buildTraces returns one trace per scenario run, with a root span for the
+// scenario and a child span for every step that was run.
+func buildTraces(results []scenarioResult) ptrace.Traces {
+	td := ptrace.NewTraces()
+	if len(results) == 0 {
+		return td
+	}
+
+	ss := td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty()
+	ss.Scope().SetName("otelcol/httpscenarioreceiver")
+	for _, result := range results {
+		root := ss.Spans().AppendEmpty()
+		root.SetTraceID(result.traceID)
+		root.SetSpanID(result.spanID)
+		root.SetName(result.name)
+		root.SetKind(ptrace.SpanKindInternal)
+		root.SetStartTimestamp(pcommon.NewTimestampFromTime(result.start))
+		root.SetEndTimestamp(pcommon.NewTimestampFromTime(result.end))
+		root.Attributes().PutStr(attributeScenarioName, result.name)
+		if result.failedStep != "" {
+			root.Status().SetCode(ptrace.StatusCodeError)
+			root.Status().SetMessage("step " + result.failedStep + " failed")
+		}
+
+		for _, step := range result.steps {
+			span := ss.Spans().AppendEmpty()
+			span.SetTraceID(result.traceID)
+			span.SetSpanID(step.spanID)
+			span.SetParentSpanID(result.spanID)
+			span.SetName(step.name)
+			span.SetKind(ptrace.SpanKindClient)
+			span.SetStartTimestamp(pcommon.NewTimestampFromTime(step.start))
+			span.SetEndTimestamp(pcommon.NewTimestampFromTime(step.end))
+			span.Attributes().PutStr(attributeScenarioName, result.name)
+			span.Attributes().PutStr(attributeStepName, step.name)
+			span.Attributes().PutStr(attributeHTTPMethod, step.method)
+			if step.url != "" {
+				span.Attributes().PutStr(attributeURLFull, step.url)
+			}
+			if step.statusCode != 0 {
+				span.Attributes().PutInt(attributeHTTPStatusCode, int64(step.statusCode))
+			}
+			if step.err != nil {
+				span.Status().SetCode(ptrace.StatusCodeError)
+				span.Status().SetMessage(step.err.Error())
+			}
+		}
+	}
+	return td
+}
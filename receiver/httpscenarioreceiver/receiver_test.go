@@ -0,0 +1,149 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package httpscenarioreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/httpscenarioreceiver"
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+)
+
+func TestScrape(t *testing.T) {
+	server := newShopServer(t, nil)
+
+	failing := shopScenario(server.URL)
+	failing.Name = "unauthorized"
+	failing.Steps[0].Body = "{}"
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Scenarios = []*ScenarioConfig{shopScenario(server.URL), failing}
+
+	traces := new(consumertest.TracesSink)
+	s, err := newScraper(cfg, receivertest.NewNopCreateSettings(), traces)
+	require.NoError(t, err)
+	require.NoError(t, s.start(context.Background(), componenttest.NewNopHost()))
+
+	md, err := s.scrape(context.Background())
+	require.NoError(t, err)
+
+	metrics := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	require.Equal(t, 3, metrics.Len())
+	statuses := map[string]int64{}
+	steps := map[string]int64{}
+	for i := 0; i < metrics.Len(); i++ {
+		m := metrics.At(i)
+		dps := m.Gauge().DataPoints()
+		for j := 0; j < dps.Len(); j++ {
+			dp := dps.At(j)
+			scenario, _ := dp.Attributes().Get("scenario.name")
+			switch m.Name() {
+			case "httpscenario.status":
+				failedStep, _ := dp.Attributes().Get("scenario.failed_step")
+				statuses[scenario.Str()+"/"+failedStep.Str()] = dp.IntValue()
+			case "httpscenario.step.duration":
+				step, _ := dp.Attributes().Get("step.name")
+				statusCode, _ := dp.Attributes().Get("http.status_code")
+				steps[scenario.Str()+"/"+step.Str()] = statusCode.Int()
+			}
+		}
+	}
+	assert.Equal(t, map[string]int64{"checkout/": 1, "unauthorized/login": 0}, statuses)
+	assert.Equal(t, map[string]int64{
+		"checkout/login":     http.StatusOK,
+		"checkout/cart":      http.StatusOK,
+		"checkout/checkout":  http.StatusCreated,
+		"unauthorized/login": http.StatusUnauthorized,
+	}, steps)
+
+	require.Len(t, traces.AllTraces(), 1)
+	td := traces.AllTraces()[0]
+	assert.Equal(t, 6, td.SpanCount())
+	spans := td.ResourceSpans().At(0).ScopeSpans().At(0).Spans()
+
+	root := spans.At(0)
+	assert.Equal(t, "checkout", root.Name())
+	assert.Equal(t, ptrace.SpanKindInternal, root.Kind())
+	assert.Equal(t, ptrace.StatusCodeUnset, root.Status().Code())
+	for i := 1; i <= 3; i++ {
+		span := spans.At(i)
+		assert.Equal(t, root.TraceID(), span.TraceID())
+		assert.Equal(t, root.SpanID(), span.ParentSpanID())
+		assert.Equal(t, ptrace.SpanKindClient, span.Kind())
+	}
+	assert.Equal(t, map[string]any{
+		"scenario.name":             "checkout",
+		"step.name":                 "checkout",
+		"http.request.method":       http.MethodPost,
+		"url.full":                  server.URL + "/api/checkout/42",
+		"http.response.status_code": int64(http.StatusCreated),
+	}, spans.At(3).Attributes().AsRaw())
+
+	failedRoot := spans.At(4)
+	assert.Equal(t, "unauthorized", failedRoot.Name())
+	assert.NotEqual(t, root.TraceID(), failedRoot.TraceID())
+	assert.Equal(t, ptrace.StatusCodeError, failedRoot.Status().Code())
+	assert.Equal(t, "step login failed", failedRoot.Status().Message())
+	assert.Equal(t, ptrace.StatusCodeError, spans.At(5).Status().Code())
+	assert.Equal(t, "unexpected status code 401", spans.At(5).Status().Message())
+}
+
+func TestReceiverTracesOnly(t *testing.T) {
+	server := newShopServer(t, nil)
+
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.CollectionInterval = 10 * time.Millisecond
+	cfg.InitialDelay = 0
+	cfg.Scenarios = []*ScenarioConfig{shopScenario(server.URL)}
+
+	traces := new(consumertest.TracesSink)
+	r, err := factory.CreateTracesReceiver(context.Background(), receivertest.NewNopCreateSettings(), cfg, traces)
+	require.NoError(t, err)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { require.NoError(t, r.Shutdown(context.Background())) }()
+
+	require.Eventually(t, func() bool { return traces.SpanCount() >= 4 }, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestBuildTracesEmpty(t *testing.T) {
+	assert.Equal(t, 0, buildTraces(nil).SpanCount())
+}
+
+func TestRecordMetricsWithoutResponse(t *testing.T) {
+	s, err := newScraper(createDefaultConfig().(*Config), receivertest.NewNopCreateSettings(), nil)
+	require.NoError(t, err)
+
+	start := time.Now()
+	s.recordMetrics(pcommon.NewTimestampFromTime(start), scenarioResult{
+		name:       "down",
+		start:      start,
+		end:        start.Add(1500 * time.Millisecond),
+		failedStep: "login",
+		steps:      []stepResult{{name: "login", start: start, end: start.Add(1500 * time.Millisecond)}},
+	})
+	md := s.mb.Emit()
+
+	metrics := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	values := map[string]int64{}
+	for i := 0; i < metrics.Len(); i++ {
+		m := metrics.At(i)
+		require.Equal(t, pmetric.MetricTypeGauge, m.Type())
+		values[m.Name()] = m.Gauge().DataPoints().At(0).IntValue()
+	}
+	assert.Equal(t, map[string]int64{
+		"httpscenario.status":        0,
+		"httpscenario.duration":      1500,
+		"httpscenario.step.duration": 1500,
+	}, values)
+}
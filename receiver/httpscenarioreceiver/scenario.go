@@ -0,0 +1,273 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package httpscenarioreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/httpscenarioreceiver"
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/httpbody"
+)
+
+var templatePattern = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_.-]+)\s*\}\}`)
+
+// templateVariables returns the variables a template refers to.
+func templateVariables(tmpl string) []string {
+	var variables []string
+	for _, match := range templatePattern.FindAllStringSubmatch(tmpl, -1) {
+		variables = append(variables, match[1])
+	}
+	return variables
+}
+
+// expand replaces the references to variables in a template by their value.
+func expand(tmpl string, variables map[string]string) string {
+	return templatePattern.ReplaceAllStringFunc(tmpl, func(match string) string {
+		return variables[templatePattern.FindStringSubmatch(match)[1]]
+	})
+}
+
+// stepResult is the outcome of one step of a scenario run.
+type stepResult struct {
+	name       string
+	method     string
+	url        string
+	statusCode int
+	start      time.Time
+	end        time.Time
+	spanID     pcommon.SpanID
+	err        error
+}
+
+// scenarioResult is the outcome of one scenario run. Steps following the one
+// that failed are not run and have no result.
+type scenarioResult struct {
+	name       string
+	start      time.Time
+	end        time.Time
+	traceID    pcommon.TraceID
+	spanID     pcommon.SpanID
+	steps      []stepResult
+	failedStep string
+}
+
+// scenarioRunner runs a scenario with a fresh cookie jar and set of variables
+// every time.
+type scenarioRunner struct {
+	cfg    *ScenarioConfig
+	client *http.Client
+	base   *url.URL
+}
+
+func newScenarioRunner(cfg *ScenarioConfig, client *http.Client) (*scenarioRunner, error) {
+	r := &scenarioRunner{cfg: cfg, client: client}
+	if cfg.Endpoint != "" {
+		base, err := url.Parse(cfg.Endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("scenario %q: invalid endpoint: %w", cfg.Name, err)
+		}
+		r.base = base
+	}
+	return r, nil
+}
+
+func (r *scenarioRunner) run(ctx context.Context) scenarioResult {
+	result := scenarioResult{
+		name:    r.cfg.Name,
+		start:   time.Now(),
+		traceID: newTraceID(),
+		spanID:  newSpanID(),
+	}
+
+	jar, _ := cookiejar.New(nil)
+	client := *r.client
+	client.Jar = jar
+
+	variables := make(map[string]string)
+	for _, step := range r.cfg.Steps {
+		sr := r.runStep(ctx, &client, step, variables, result.traceID)
+		result.steps = append(result.steps, sr)
+		if sr.err != nil {
+			result.failedStep = step.Name
+			break
+		}
+	}
+
+	result.end = time.Now()
+	return result
+}
+
+func (r *scenarioRunner) runStep(ctx context.Context, client *http.Client, step StepConfig, variables map[string]string, traceID pcommon.TraceID) (result stepResult) {
+	result = stepResult{
+		name:   step.Name,
+		method: step.Method,
+		start:  time.Now(),
+		spanID: newSpanID(),
+	}
+	if result.method == "" {
+		result.method = http.MethodGet
+	}
+	defer func() { result.end = time.Now() }()
+
+	target, err := r.resolve(expand(step.URL, variables))
+	if err != nil {
+		result.err = err
+		return result
+	}
+	result.url = target
+
+	var body io.Reader = http.NoBody
+	if step.Body != "" {
+		body = strings.NewReader(expand(step.Body, variables))
+	}
+	req, err := http.NewRequestWithContext(ctx, result.method, target, body)
+	if err != nil {
+		result.err = err
+		return result
+	}
+	for name, value := range step.Headers {
+		req.Header.Set(name, expand(value, variables))
+	}
+	if r.cfg.PropagateTraceContext {
+		req.Header.Set("traceparent", fmt.Sprintf("00-%s-%s-01", traceID, result.spanID))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		result.err = err
+		return result
+	}
+	defer resp.Body.Close()
+	result.statusCode = resp.StatusCode
+
+	if !statusExpected(step.ExpectedStatus, resp.StatusCode) {
+		result.err = fmt.Errorf("unexpected status code %d", resp.StatusCode)
+		return result
+	}
+
+	result.err = extract(client, resp, step.Extract, variables)
+	return result
+}
+
+// resolve returns the absolute URL of a step.
+func (r *scenarioRunner) resolve(rawURL string) (string, error) {
+	var u *url.URL
+	var err error
+	if r.base != nil {
+		u, err = r.base.Parse(rawURL)
+	} else {
+		u, err = url.Parse(rawURL)
+	}
+	if err != nil {
+		return "", err
+	}
+	if !u.IsAbs() {
+		return "", fmt.Errorf("url %q is not absolute and the scenario has no endpoint", rawURL)
+	}
+	return u.String(), nil
+}
+
+func statusExpected(expected []int, statusCode int) bool {
+	if len(expected) == 0 {
+		return statusCode < http.StatusBadRequest
+	}
+	for _, code := range expected {
+		if code == statusCode {
+			return true
+		}
+	}
+	return false
+}
+
+// extract sets the variables taken from the response.
+func extract(client *http.Client, resp *http.Response, extractions []ExtractConfig, variables map[string]string) error {
+	var doc any
+	var docErr error
+	var decoded bool
+
+	for _, ex := range extractions {
+		switch {
+		case ex.JSON != "":
+			if !decoded {
+				doc, docErr = decodeJSON(resp.Body)
+				decoded = true
+			}
+			if docErr != nil {
+				return fmt.Errorf("failed to extract %q: %w", ex.Variable, docErr)
+			}
+			value, ok := httpbody.LookupJSONPath(doc, ex.JSON)
+			if !ok {
+				return fmt.Errorf("failed to extract %q: path %s not found", ex.Variable, ex.JSON)
+			}
+			variables[ex.Variable] = httpbody.JSONValueString(value)
+		case ex.Header != "":
+			values, ok := resp.Header[http.CanonicalHeaderKey(ex.Header)]
+			if !ok {
+				return fmt.Errorf("failed to extract %q: header %s is missing", ex.Variable, ex.Header)
+			}
+			variables[ex.Variable] = values[0]
+		case ex.Cookie != "":
+			value, ok := findCookie(client, resp, ex.Cookie)
+			if !ok {
+				return fmt.Errorf("failed to extract %q: cookie %s is missing", ex.Variable, ex.Cookie)
+			}
+			variables[ex.Variable] = value
+		}
+	}
+	return nil
+}
+
+// findCookie looks for a cookie set by the response, then in the cookie jar
+// to find the cookies set by the responses of redirects.
+func findCookie(client *http.Client, resp *http.Response, name string) (string, bool) {
+	cookies := resp.Cookies()
+	if client.Jar != nil && resp.Request != nil {
+		cookies = append(cookies, client.Jar.Cookies(resp.Request.URL)...)
+	}
+	for _, cookie := range cookies {
+		if cookie.Name == name {
+			return cookie.Value, true
+		}
+	}
+	return "", false
+}
+
+func decodeJSON(body io.Reader) (any, error) {
+	data, err := httpbody.Read(body)
+	if err != nil {
+		return nil, err
+	}
+	var doc any
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, errors.New("body is not valid JSON")
+	}
+	return doc, nil
+}
+
+func newTraceID() pcommon.TraceID {
+	var id [16]byte
+	_, _ = rand.Read(id[:])
+	return id
+}
+
+func newSpanID() pcommon.SpanID {
+	var id [8]byte
+	_, _ = rand.Read(id[:])
+	return id
+}
@@ -0,0 +1,252 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package httpscenarioreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/httpscenarioreceiver"
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newShopServer serves a login, cart and checkout flow. The cart requires the
+// token returned by the login and the checkout requires the session cookie.
+func newShopServer(t *testing.T, traceparents chan<- string) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/login", func(w http.ResponseWriter, r *http.Request) {
+		if traceparents != nil {
+			traceparents <- r.Header.Get("traceparent")
+		}
+		body, _ := io.ReadAll(r.Body)
+		if r.Method != http.MethodPost || string(body) != `{"user":"synthetic"}` {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		http.SetCookie(w, &http.Cookie{Name: "session_id", Value: "s3cr3t", Path: "/"})
+		w.Header().Set("X-Request-Id", "req-1")
+		_, _ = w.Write([]byte(`{"data":{"token":"abc","ttl":3600}}`))
+	})
+	mux.HandleFunc("/api/cart", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer abc" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		_, _ = w.Write([]byte(`{"items":[{"id":42}]}`))
+	})
+	mux.HandleFunc("/api/checkout/42", func(w http.ResponseWriter, r *http.Request) {
+		cookie, err := r.Cookie("session_id")
+		if err != nil || cookie.Value != "s3cr3t" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func shopScenario(endpoint string) *ScenarioConfig {
+	scenario := &ScenarioConfig{
+		Name: "checkout",
+		Steps: []StepConfig{
+			{
+				Name:   "login",
+				Method: http.MethodPost,
+				URL:    "/api/login",
+				Body:   `{"user":"synthetic"}`,
+				Extract: []ExtractConfig{
+					{Variable: "token", JSON: "data.token"},
+					{Variable: "ttl", JSON: "$.data.ttl"},
+					{Variable: "request", Header: "x-request-id"},
+					{Variable: "session", Cookie: "session_id"},
+				},
+			},
+			{
+				Name:    "cart",
+				URL:     "/api/cart",
+				Headers: map[string]string{"Authorization": "Bearer {{ token }}"},
+				Extract: []ExtractConfig{{Variable: "item", JSON: "items.0.id"}},
+			},
+			{
+				Name:           "checkout",
+				Method:         http.MethodPost,
+				URL:            "/api/checkout/{{item}}",
+				ExpectedStatus: []int{http.StatusCreated},
+			},
+		},
+	}
+	scenario.Endpoint = endpoint
+	return scenario
+}
+
+func runScenario(t *testing.T, cfg *ScenarioConfig) scenarioResult {
+	runner, err := newScenarioRunner(cfg, http.DefaultClient)
+	require.NoError(t, err)
+	return runner.run(context.Background())
+}
+
+func TestScenarioRun(t *testing.T) {
+	server := newShopServer(t, nil)
+
+	result := runScenario(t, shopScenario(server.URL))
+	assert.Empty(t, result.failedStep)
+	assert.Equal(t, "checkout", result.name)
+	assert.False(t, result.traceID.IsEmpty())
+	assert.False(t, result.spanID.IsEmpty())
+	assert.False(t, result.end.Before(result.start))
+
+	require.Len(t, result.steps, 3)
+	for i, step := range result.steps {
+		assert.NoError(t, step.err, step.name)
+		assert.False(t, step.spanID.IsEmpty())
+		assert.False(t, step.end.Before(step.start))
+		if i > 0 {
+			assert.NotEqual(t, result.steps[i-1].spanID, step.spanID)
+		}
+	}
+	assert.Equal(t, http.MethodPost, result.steps[0].method)
+	assert.Equal(t, server.URL+"/api/login", result.steps[0].url)
+	assert.Equal(t, http.StatusOK, result.steps[0].statusCode)
+	assert.Equal(t, http.MethodGet, result.steps[1].method)
+	assert.Equal(t, server.URL+"/api/checkout/42", result.steps[2].url)
+	assert.Equal(t, http.StatusCreated, result.steps[2].statusCode)
+}
+
+func TestScenarioRunFreshSession(t *testing.T) {
+	server := newShopServer(t, nil)
+	cfg := shopScenario(server.URL)
+	// Without the login, the checkout has no session cookie even though a
+	// previous run of the scenario received one.
+	runner, err := newScenarioRunner(cfg, http.DefaultClient)
+	require.NoError(t, err)
+	require.Empty(t, runner.run(context.Background()).failedStep)
+
+	cfg.Steps = cfg.Steps[2:]
+	cfg.Steps[0].URL = "/api/checkout/42"
+	result := runner.run(context.Background())
+	assert.Equal(t, "checkout", result.failedStep)
+	require.Len(t, result.steps, 1)
+	assert.EqualError(t, result.steps[0].err, "unexpected status code 403")
+}
+
+func TestScenarioRunFailures(t *testing.T) {
+	server := newShopServer(t, nil)
+
+	testCases := []struct {
+		desc          string
+		modify        func(*ScenarioConfig)
+		failedStep    string
+		steps         int
+		expectedError string
+	}{
+		{
+			desc: "unexpected status code",
+			modify: func(cfg *ScenarioConfig) {
+				cfg.Steps[0].Body = `{"user":"unknown"}`
+			},
+			failedStep:    "login",
+			steps:         1,
+			expectedError: "unexpected status code 401",
+		},
+		{
+			desc: "missing json path",
+			modify: func(cfg *ScenarioConfig) {
+				cfg.Steps[1].Extract[0].JSON = "items.1.id"
+			},
+			failedStep:    "cart",
+			steps:         2,
+			expectedError: `failed to extract "item": path items.1.id not found`,
+		},
+		{
+			desc: "missing header",
+			modify: func(cfg *ScenarioConfig) {
+				cfg.Steps[0].Extract[2].Header = "X-Missing"
+			},
+			failedStep:    "login",
+			steps:         1,
+			expectedError: `failed to extract "request": header X-Missing is missing`,
+		},
+		{
+			desc: "missing cookie",
+			modify: func(cfg *ScenarioConfig) {
+				cfg.Steps[0].Extract[3].Cookie = "missing"
+			},
+			failedStep:    "login",
+			steps:         1,
+			expectedError: `failed to extract "session": cookie missing is missing`,
+		},
+		{
+			desc: "body is not json",
+			modify: func(cfg *ScenarioConfig) {
+				cfg.Steps[2].ExpectedStatus = nil
+				cfg.Steps[2].Extract = []ExtractConfig{{Variable: "order", JSON: "id"}}
+			},
+			failedStep:    "checkout",
+			steps:         3,
+			expectedError: `failed to extract "order": body is not valid JSON`,
+		},
+		{
+			desc: "relative url without endpoint",
+			modify: func(cfg *ScenarioConfig) {
+				cfg.Endpoint = ""
+			},
+			failedStep:    "login",
+			steps:         1,
+			expectedError: `url "/api/login" is not absolute and the scenario has no endpoint`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			cfg := shopScenario(server.URL)
+			tc.modify(cfg)
+
+			result := runScenario(t, cfg)
+			assert.Equal(t, tc.failedStep, result.failedStep)
+			require.Len(t, result.steps, tc.steps)
+			assert.EqualError(t, result.steps[tc.steps-1].err, tc.expectedError)
+		})
+	}
+}
+
+func TestScenarioRunConnectionError(t *testing.T) {
+	server := newShopServer(t, nil)
+	server.Close()
+
+	result := runScenario(t, shopScenario(server.URL))
+	assert.Equal(t, "login", result.failedStep)
+	require.Len(t, result.steps, 1)
+	assert.Error(t, result.steps[0].err)
+	assert.Zero(t, result.steps[0].statusCode)
+}
+
+func TestScenarioRunPropagateTraceContext(t *testing.T) {
+	traceparents := make(chan string, 1)
+	server := newShopServer(t, traceparents)
+
+	cfg := shopScenario(server.URL)
+	cfg.Steps = cfg.Steps[:1]
+	cfg.PropagateTraceContext = true
+
+	result := runScenario(t, cfg)
+	require.Empty(t, result.failedStep)
+	assert.Equal(t, fmt.Sprintf("00-%s-%s-01", result.traceID, result.steps[0].spanID), <-traceparents)
+
+	cfg.PropagateTraceContext = false
+	runScenario(t, cfg)
+	assert.Empty(t, <-traceparents)
+}
+
+func TestExpand(t *testing.T) {
+	variables := map[string]string{"token": "abc", "item.id": "42"}
+	assert.Equal(t, "/items/42?token=abc&missing=", expand("/items/{{item.id}}?token={{ token }}&missing={{ other }}", variables))
+	assert.Equal(t, []string{"item.id", "token"}, templateVariables("/items/{{item.id}}?token={{ token }}"))
+	assert.Empty(t, templateVariables("${env:TOKEN}"))
+}
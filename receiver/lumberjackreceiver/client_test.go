@@ -0,0 +1,97 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package lumberjackreceiver
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+)
+
+// testClient is an in-process Lumberjack v2 client, sending events like the
+// Logstash output of the Beats does.
+type testClient struct {
+	conn     net.Conn
+	compress bool
+}
+
+// encodeWindow encodes a window frame and the events it announces, within a
+// compressed frame if the client compresses its events.
+func (c *testClient) encodeWindow(events []map[string]any) ([]byte, error) {
+	var frames bytes.Buffer
+	for i, e := range events {
+		payload, err := json.Marshal(e)
+		if err != nil {
+			return nil, err
+		}
+		frames.Write([]byte{protocolVersion, frameTypeJSON})
+		_ = binary.Write(&frames, binary.BigEndian, uint32(i+1))
+		_ = binary.Write(&frames, binary.BigEndian, uint32(len(payload)))
+		frames.Write(payload)
+	}
+
+	var buf bytes.Buffer
+	buf.Write([]byte{protocolVersion, frameTypeWindow})
+	_ = binary.Write(&buf, binary.BigEndian, uint32(len(events)))
+
+	if !c.compress || len(events) == 0 {
+		buf.Write(frames.Bytes())
+		return buf.Bytes(), nil
+	}
+
+	var compressed bytes.Buffer
+	zw := zlib.NewWriter(&compressed)
+	if _, err := zw.Write(frames.Bytes()); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	buf.Write([]byte{protocolVersion, frameTypeCompressed})
+	_ = binary.Write(&buf, binary.BigEndian, uint32(compressed.Len()))
+	buf.Write(compressed.Bytes())
+	return buf.Bytes(), nil
+}
+
+// send sends the events and waits for them to be acknowledged, returning the
+// number of keep alive ACKs received meanwhile.
+func (c *testClient) send(events []map[string]any) (int, error) {
+	window, err := c.encodeWindow(events)
+	if err != nil {
+		return 0, err
+	}
+	if _, err = c.conn.Write(window); err != nil {
+		return 0, err
+	}
+
+	keepAlives := 0
+	for {
+		seq, err := c.readACK()
+		if err != nil {
+			return keepAlives, err
+		}
+		if seq == uint32(len(events)) {
+			return keepAlives, nil
+		}
+		if seq != 0 {
+			return keepAlives, fmt.Errorf("unexpected ACK %d", seq)
+		}
+		keepAlives++
+	}
+}
+
+func (c *testClient) readACK() (uint32, error) {
+	var frame [6]byte
+	if _, err := io.ReadFull(c.conn, frame[:]); err != nil {
+		return 0, err
+	}
+	if frame[0] != protocolVersion || frame[1] != frameTypeACK {
+		return 0, fmt.Errorf("unexpected frame %q", frame[:2])
+	}
+	return binary.BigEndian.Uint32(frame[2:]), nil
+}
@@ -0,0 +1,49 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package lumberjackreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/lumberjackreceiver"
+
+import (
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/collector/config/confignet"
+	"go.opentelemetry.io/collector/config/configtls"
+	"go.uber.org/multierr"
+)
+
+var (
+	errMissingEndpoint          = errors.New("endpoint must be specified")
+	errInvalidInactivityTimeout = errors.New("inactivity_timeout must be positive")
+	errInvalidMaxWindowSize     = errors.New("max_window_size must be positive")
+)
+
+// Config defines configuration for the Lumberjack receiver.
+type Config struct {
+	// The address to listen on for Lumberjack clients, such as Filebeat or
+	// Winlogbeat configured with a Logstash output.
+	confignet.TCPAddrConfig `mapstructure:",squash"`
+	// TLSSetting configures the TLS server, clients connect in plain text if
+	// it is not set.
+	TLSSetting *configtls.ServerConfig `mapstructure:"tls"`
+	// InactivityTimeout is how long a client connection can stay idle before
+	// it is closed, like the client_inactivity_timeout of Logstash.
+	InactivityTimeout time.Duration `mapstructure:"inactivity_timeout"`
+	// MaxWindowSize is the maximum number of events a client can announce
+	// in a window frame. Connections announcing larger windows are closed.
+	MaxWindowSize int `mapstructure:"max_window_size"`
+}
+
+func (cfg *Config) Validate() error {
+	var errs error
+	if cfg.Endpoint == "" {
+		errs = multierr.Append(errs, errMissingEndpoint)
+	}
+	if cfg.InactivityTimeout <= 0 {
+		errs = multierr.Append(errs, errInvalidInactivityTimeout)
+	}
+	if cfg.MaxWindowSize <= 0 {
+		errs = multierr.Append(errs, errInvalidMaxWindowSize)
+	}
+	return errs
+}
@@ -0,0 +1,72 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package lumberjackreceiver
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/confignet"
+	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/confmap/confmaptest"
+	"go.uber.org/multierr"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/lumberjackreceiver/internal/metadata"
+)
+
+func TestLoadConfig(t *testing.T) {
+	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config.yaml"))
+	require.NoError(t, err)
+
+	tests := []struct {
+		id          component.ID
+		expected    component.Config
+		expectedErr error
+	}{
+		{
+			id:       component.NewID(metadata.Type),
+			expected: createDefaultConfig(),
+		},
+		{
+			id: component.NewIDWithName(metadata.Type, "tls"),
+			expected: &Config{
+				TCPAddrConfig: confignet.TCPAddrConfig{
+					Endpoint: "0.0.0.0:5045",
+				},
+				TLSSetting: &configtls.ServerConfig{
+					Config: configtls.Config{
+						CertFile: "/etc/otelcol/lumberjack.crt",
+						KeyFile:  "/etc/otelcol/lumberjack.key",
+					},
+				},
+				InactivityTimeout: 2 * time.Minute,
+				MaxWindowSize:     4096,
+			},
+		},
+		{
+			id:          component.NewIDWithName(metadata.Type, "invalid"),
+			expectedErr: multierr.Combine(errMissingEndpoint, errInvalidInactivityTimeout, errInvalidMaxWindowSize),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id.String(), func(t *testing.T) {
+			cfg := createDefaultConfig()
+			sub, err := cm.Sub(tt.id.String())
+			require.NoError(t, err)
+			require.NoError(t, sub.Unmarshal(cfg))
+
+			if tt.expectedErr != nil {
+				assert.Equal(t, tt.expectedErr, component.ValidateConfig(cfg))
+				return
+			}
+			assert.NoError(t, component.ValidateConfig(cfg))
+			assert.Equal(t, tt.expected, cfg)
+		})
+	}
+}
@@ -0,0 +1,212 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package lumberjackreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/lumberjackreceiver"
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+const (
+	fieldTimestamp = "@timestamp"
+	fieldMetadata  = "@metadata"
+	fieldMessage   = "message"
+	fieldLogLevel  = "log.level"
+	fieldHostName  = "host.name"
+	fieldHostname  = "host.hostname"
+)
+
+// resourceFieldPrefixes are the prefixes of the ECS fields describing the
+// source of the events rather than the events, which are set as resource
+// attributes.
+var resourceFieldPrefixes = []string{"host.", "agent.", "cloud.", "container.", "service.", "orchestrator."}
+
+// resourceFieldRenames are the ECS fields set as resource attributes under
+// the name of the equivalent semantic conventions attribute.
+var resourceFieldRenames = map[string]string{
+	"host.os.type":    "os.type",
+	"host.os.name":    "os.name",
+	"host.os.version": "os.version",
+	"host.os.kernel":  "os.description",
+}
+
+// severities maps the log levels of ECS to log severities.
+var severities = map[string]plog.SeverityNumber{
+	"trace":     plog.SeverityNumberTrace,
+	"debug":     plog.SeverityNumberDebug,
+	"info":      plog.SeverityNumberInfo,
+	"notice":    plog.SeverityNumberInfo2,
+	"warn":      plog.SeverityNumberWarn,
+	"warning":   plog.SeverityNumberWarn,
+	"error":     plog.SeverityNumberError,
+	"err":       plog.SeverityNumberError,
+	"critical":  plog.SeverityNumberFatal,
+	"crit":      plog.SeverityNumberFatal,
+	"alert":     plog.SeverityNumberFatal2,
+	"emergency": plog.SeverityNumberFatal3,
+	"fatal":     plog.SeverityNumberFatal,
+}
+
+// logsBuilder converts Beats events to logs, grouping the log records of the
+// events with the same resource.
+type logsBuilder struct {
+	logs      plog.Logs
+	resources map[string]plog.LogRecordSlice
+	now       pcommon.Timestamp
+}
+
+func newLogsBuilder(now time.Time) *logsBuilder {
+	return &logsBuilder{
+		logs:      plog.NewLogs(),
+		resources: map[string]plog.LogRecordSlice{},
+		now:       pcommon.NewTimestampFromTime(now),
+	}
+}
+
+// appendEvent converts a Beats event to a log record. The fields of the event
+// are flattened to attributes with dot separated keys, so that ECS fields are
+// named like their semantic conventions equivalent.
+func (lb *logsBuilder) appendEvent(payload []byte) error {
+	var fields map[string]any
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.UseNumber()
+	if err := decoder.Decode(&fields); err != nil {
+		return fmt.Errorf("failed to decode event: %w", err)
+	}
+
+	flattened := map[string]any{}
+	for k, v := range fields {
+		// The metadata of the event is not part of the event, Logstash doesn't
+		// index it either.
+		if k == fieldMetadata {
+			continue
+		}
+		flatten(k, v, flattened)
+	}
+
+	record := plog.NewLogRecord()
+	record.SetObservedTimestamp(lb.now)
+	resource := map[string]any{}
+
+	for k, v := range flattened {
+		switch {
+		case k == fieldTimestamp:
+			s, _ := v.(string)
+			timestamp, err := time.Parse(time.RFC3339Nano, s)
+			if err != nil {
+				return fmt.Errorf("invalid %s: %v", fieldTimestamp, v)
+			}
+			record.SetTimestamp(pcommon.NewTimestampFromTime(timestamp))
+		case k == fieldMessage:
+			if err := record.Body().FromRaw(v); err != nil {
+				return err
+			}
+		case k == fieldLogLevel:
+			level := fmt.Sprint(v)
+			record.SetSeverityText(level)
+			record.SetSeverityNumber(severities[strings.ToLower(level)])
+			if err := record.Attributes().PutEmpty(k).FromRaw(v); err != nil {
+				return err
+			}
+		case resourceFieldRenames[k] != "":
+			resource[resourceFieldRenames[k]] = v
+		case isResourceField(k):
+			resource[k] = v
+		default:
+			if err := record.Attributes().PutEmpty(k).FromRaw(v); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Beats set host.name to the name of the host by default, but it can be
+	// dropped in favor of the hostname reported by the operating system.
+	if _, ok := resource[fieldHostName]; !ok {
+		if hostname, ok := resource[fieldHostname]; ok {
+			resource[fieldHostName] = hostname
+		}
+	}
+
+	records, err := lb.recordsOf(resource)
+	if err != nil {
+		return err
+	}
+	record.MoveTo(records.AppendEmpty())
+	return nil
+}
+
+// recordsOf returns the log records of the resource with the given attributes.
+func (lb *logsBuilder) recordsOf(resource map[string]any) (plog.LogRecordSlice, error) {
+	// The keys of maps are sorted when encoded, making the encoding a key.
+	key, err := json.Marshal(resource)
+	if err != nil {
+		return plog.LogRecordSlice{}, err
+	}
+	if records, ok := lb.resources[string(key)]; ok {
+		return records, nil
+	}
+
+	rl := lb.logs.ResourceLogs().AppendEmpty()
+	if err := rl.Resource().Attributes().FromRaw(resource); err != nil {
+		return plog.LogRecordSlice{}, err
+	}
+	sl := rl.ScopeLogs().AppendEmpty()
+	sl.Scope().SetName(scopeName)
+	records := sl.LogRecords()
+	lb.resources[string(key)] = records
+	return records, nil
+}
+
+func isResourceField(k string) bool {
+	for _, prefix := range resourceFieldPrefixes {
+		if strings.HasPrefix(k, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// flatten adds the value to the fields, flattening objects to dot separated
+// keys and converting JSON numbers to integers when possible.
+func flatten(key string, value any, fields map[string]any) {
+	switch v := value.(type) {
+	case map[string]any:
+		for k, inner := range v {
+			flatten(key+"."+k, inner, fields)
+		}
+	default:
+		fields[key] = normalize(value)
+	}
+}
+
+// normalize converts the JSON numbers of a value to integers when possible,
+// and to floats otherwise.
+func normalize(value any) any {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case []any:
+		for i := range v {
+			v[i] = normalize(v[i])
+		}
+		return v
+	case map[string]any:
+		for k := range v {
+			v[k] = normalize(v[k])
+		}
+		return v
+	default:
+		return value
+	}
+}
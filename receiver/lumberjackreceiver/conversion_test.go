@@ -0,0 +1,107 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package lumberjackreceiver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+const filebeatEvent = `{
+  "@timestamp": "2024-04-22T10:15:30.123Z",
+  "@metadata": {"beat": "filebeat", "type": "_doc", "version": "8.13.2"},
+  "message": "GET /index.html 200",
+  "log": {"file": {"path": "/var/log/nginx/access.log"}, "offset": 1234, "level": "WARN"},
+  "input": {"type": "filestream"},
+  "fields": {"team": "web", "ratio": 0.5},
+  "tags": ["nginx", "access"],
+  "ecs": {"version": "8.0.0"},
+  "agent": {"type": "filebeat", "version": "8.13.2", "name": "web-1", "id": "abc"},
+  "host": {"name": "web-1", "architecture": "x86_64", "os": {"type": "linux", "name": "Ubuntu", "version": "22.04", "kernel": "5.15.0"}}
+}`
+
+const winlogbeatEvent = `{
+  "@timestamp": "2024-04-22T10:15:31Z",
+  "message": "An account was successfully logged on.",
+  "event": {"code": "4624", "provider": "Microsoft-Windows-Security-Auditing"},
+  "winlog": {"channel": "Security", "event_id": "4624", "record_id": 98765},
+  "agent": {"type": "winlogbeat", "version": "8.13.2"},
+  "host": {"hostname": "DC-1"}
+}`
+
+func TestLogsBuilder(t *testing.T) {
+	now := time.Date(2024, 4, 22, 10, 16, 0, 0, time.UTC)
+	lb := newLogsBuilder(now)
+	require.NoError(t, lb.appendEvent([]byte(filebeatEvent)))
+	require.NoError(t, lb.appendEvent([]byte(winlogbeatEvent)))
+	// Events of the same resource are grouped.
+	require.NoError(t, lb.appendEvent([]byte(filebeatEvent)))
+
+	require.Equal(t, 2, lb.logs.ResourceLogs().Len())
+	require.Equal(t, 3, lb.logs.LogRecordCount())
+
+	filebeat := lb.logs.ResourceLogs().At(0)
+	assert.Equal(t, map[string]any{
+		"host.name":         "web-1",
+		"host.architecture": "x86_64",
+		"os.type":           "linux",
+		"os.name":           "Ubuntu",
+		"os.version":        "22.04",
+		"os.description":    "5.15.0",
+		"agent.type":        "filebeat",
+		"agent.version":     "8.13.2",
+		"agent.name":        "web-1",
+		"agent.id":          "abc",
+	}, filebeat.Resource().Attributes().AsRaw())
+	require.Equal(t, 1, filebeat.ScopeLogs().Len())
+	assert.Equal(t, scopeName, filebeat.ScopeLogs().At(0).Scope().Name())
+	require.Equal(t, 2, filebeat.ScopeLogs().At(0).LogRecords().Len())
+
+	record := filebeat.ScopeLogs().At(0).LogRecords().At(0)
+	assert.Equal(t, time.Date(2024, 4, 22, 10, 15, 30, 123000000, time.UTC), record.Timestamp().AsTime())
+	assert.Equal(t, now, record.ObservedTimestamp().AsTime())
+	assert.Equal(t, "GET /index.html 200", record.Body().Str())
+	assert.Equal(t, plog.SeverityNumberWarn, record.SeverityNumber())
+	assert.Equal(t, "WARN", record.SeverityText())
+	assert.Equal(t, map[string]any{
+		"log.file.path": "/var/log/nginx/access.log",
+		"log.offset":    int64(1234),
+		"log.level":     "WARN",
+		"input.type":    "filestream",
+		"fields.team":   "web",
+		"fields.ratio":  0.5,
+		"tags":          []any{"nginx", "access"},
+		"ecs.version":   "8.0.0",
+	}, record.Attributes().AsRaw())
+
+	winlogbeat := lb.logs.ResourceLogs().At(1)
+	assert.Equal(t, map[string]any{
+		"host.name":     "DC-1",
+		"host.hostname": "DC-1",
+		"agent.type":    "winlogbeat",
+		"agent.version": "8.13.2",
+	}, winlogbeat.Resource().Attributes().AsRaw())
+
+	record = winlogbeat.ScopeLogs().At(0).LogRecords().At(0)
+	assert.Equal(t, "An account was successfully logged on.", record.Body().Str())
+	assert.Equal(t, plog.SeverityNumberUnspecified, record.SeverityNumber())
+	assert.Equal(t, map[string]any{
+		"event.code":       "4624",
+		"event.provider":   "Microsoft-Windows-Security-Auditing",
+		"winlog.channel":   "Security",
+		"winlog.event_id":  "4624",
+		"winlog.record_id": int64(98765),
+	}, record.Attributes().AsRaw())
+}
+
+func TestLogsBuilderInvalidEvents(t *testing.T) {
+	lb := newLogsBuilder(time.Now())
+	assert.EqualError(t, lb.appendEvent([]byte(`not json`)), "failed to decode event: invalid character 'o' in literal null (expecting 'u')")
+	assert.EqualError(t, lb.appendEvent([]byte(`{"@timestamp": "yesterday"}`)), "invalid @timestamp: yesterday")
+	assert.Equal(t, 0, lb.logs.LogRecordCount())
+}
@@ -0,0 +1,8 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:generate mdatagen metadata.yaml
+
+// Package lumberjackreceiver receives the events of Beats and other Logstash
+// clients over the Lumberjack v2 protocol.
+package lumberjackreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/lumberjackreceiver"
@@ -0,0 +1,52 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package lumberjackreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/lumberjackreceiver"
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/confignet"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/receiver"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/lumberjackreceiver/internal/metadata"
+)
+
+const (
+	// The default port of the Beats input of Logstash.
+	defaultEndpoint          = "localhost:5044"
+	defaultInactivityTimeout = 60 * time.Second
+	// Well above the default bulk_max_size of the Logstash output of the
+	// Beats, 2048 events.
+	defaultMaxWindowSize = 16384
+)
+
+// NewFactory creates a factory for the Lumberjack receiver.
+func NewFactory() receiver.Factory {
+	return receiver.NewFactory(
+		metadata.Type,
+		createDefaultConfig,
+		receiver.WithLogs(createLogsReceiver, metadata.LogsStability))
+}
+
+func createDefaultConfig() component.Config {
+	return &Config{
+		TCPAddrConfig: confignet.TCPAddrConfig{
+			Endpoint: defaultEndpoint,
+		},
+		InactivityTimeout: defaultInactivityTimeout,
+		MaxWindowSize:     defaultMaxWindowSize,
+	}
+}
+
+func createLogsReceiver(
+	_ context.Context,
+	params receiver.CreateSettings,
+	cfg component.Config,
+	consumer consumer.Logs,
+) (receiver.Logs, error) {
+	return newLumberjackReceiver(params, cfg.(*Config), consumer)
+}
@@ -0,0 +1,186 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package lumberjackreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/lumberjackreceiver"
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// The Lumberjack v2 protocol, as implemented by the Beats and Logstash:
+// https://github.com/logstash-plugins/logstash-input-beats/blob/main/PROTOCOL.md
+//
+// Every frame starts with the protocol version and the frame type. A client
+// announces the number of events it is going to send with a window frame,
+// then sends the events as JSON frames, possibly within compressed frames.
+// The server acknowledges the events with the sequence number of the last
+// one once they are processed.
+const (
+	protocolVersion byte = '2'
+
+	frameTypeWindow     byte = 'W'
+	frameTypeJSON       byte = 'J'
+	frameTypeCompressed byte = 'C'
+	frameTypeACK        byte = 'A'
+
+	// maxPayloadSize is the maximum size of the payload of a frame, compressed
+	// or not, protecting the receiver from allocating unbounded buffers.
+	maxPayloadSize = 64 << 20
+)
+
+var (
+	errPayloadTooLarge   = fmt.Errorf("frame payload exceeds %d bytes", maxPayloadSize)
+	errNestedCompression = errors.New("compressed frame within a compressed frame")
+	errTooManyEvents     = errors.New("more events than announced by the window frame")
+)
+
+// event is a JSON event received from a client, with its sequence number.
+type event struct {
+	seq     uint32
+	payload []byte
+}
+
+// batchReader reads the batches of events sent by a client.
+type batchReader struct {
+	r             *bufio.Reader
+	maxWindowSize uint32
+}
+
+func newBatchReader(r io.Reader, maxWindowSize int) *batchReader {
+	return &batchReader{r: bufio.NewReader(r), maxWindowSize: uint32(maxWindowSize)}
+}
+
+// readBatch reads a window frame and the events it announces.
+func (br *batchReader) readBatch() ([]event, error) {
+	frameType, err := readHeader(br.r)
+	if err != nil {
+		return nil, err
+	}
+	if frameType != frameTypeWindow {
+		return nil, fmt.Errorf("expected a window frame, got frame type %q", frameType)
+	}
+	var size uint32
+	if err = binary.Read(br.r, binary.BigEndian, &size); err != nil {
+		return nil, err
+	}
+	if size > br.maxWindowSize {
+		return nil, fmt.Errorf("window of %d events exceeds the maximum of %d", size, br.maxWindowSize)
+	}
+
+	// The window size is announced by the client, so don't trust it to size
+	// the buffer of events.
+	events := make([]event, 0, min(size, 1024))
+	for uint32(len(events)) < size {
+		if events, err = readEvents(br.r, events, false); err != nil {
+			return nil, err
+		}
+	}
+	// A compressed frame may hold more events than the window announced.
+	if uint32(len(events)) > size {
+		return nil, errTooManyEvents
+	}
+	return events, nil
+}
+
+// readEvents reads a JSON or compressed frame, appending its events.
+// Compressed frames may only hold JSON frames, so that a small payload can't
+// expand recursively.
+func readEvents(r *bufio.Reader, events []event, decompressed bool) ([]event, error) {
+	frameType, err := readHeader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	switch frameType {
+	case frameTypeJSON:
+		var seq uint32
+		if err = binary.Read(r, binary.BigEndian, &seq); err != nil {
+			return nil, err
+		}
+		payload, err := readPayload(r)
+		if err != nil {
+			return nil, err
+		}
+		return append(events, event{seq: seq, payload: payload}), nil
+
+	case frameTypeCompressed:
+		if decompressed {
+			return nil, errNestedCompression
+		}
+		payload, err := readPayload(r)
+		if err != nil {
+			return nil, err
+		}
+		zr, err := zlib.NewReader(bytes.NewReader(payload))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress frame: %w", err)
+		}
+		defer zr.Close()
+
+		// Read one more byte than allowed to detect oversized payloads.
+		content, err := io.ReadAll(io.LimitReader(zr, maxPayloadSize+1))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress frame: %w", err)
+		}
+		if len(content) > maxPayloadSize {
+			return nil, errPayloadTooLarge
+		}
+
+		inner := bufio.NewReader(bytes.NewReader(content))
+		for {
+			if _, err = inner.Peek(1); errors.Is(err, io.EOF) {
+				return events, nil
+			}
+			if events, err = readEvents(inner, events, true); err != nil {
+				return nil, err
+			}
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported frame type %q", frameType)
+	}
+}
+
+// readHeader reads the version and type of a frame.
+func readHeader(r io.Reader) (byte, error) {
+	var header [2]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return 0, err
+	}
+	if header[0] != protocolVersion {
+		return 0, fmt.Errorf("unsupported protocol version %q", header[0])
+	}
+	return header[1], nil
+}
+
+// readPayload reads a payload prefixed by its size.
+func readPayload(r io.Reader) ([]byte, error) {
+	var size uint32
+	if err := binary.Read(r, binary.BigEndian, &size); err != nil {
+		return nil, err
+	}
+	if size > maxPayloadSize {
+		return nil, errPayloadTooLarge
+	}
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+// writeACK acknowledges the events up to the given sequence number.
+func writeACK(w io.Writer, seq uint32) error {
+	var frame [6]byte
+	frame[0] = protocolVersion
+	frame[1] = frameTypeACK
+	binary.BigEndian.PutUint32(frame[2:], seq)
+	_, err := w.Write(frame[:])
+	return err
+}
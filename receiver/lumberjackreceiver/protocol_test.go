@@ -0,0 +1,133 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package lumberjackreceiver
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadBatch(t *testing.T) {
+	events := []map[string]any{
+		{"message": "first"},
+		{"message": "second"},
+	}
+	expected := []event{
+		{seq: 1, payload: []byte(`{"message":"first"}`)},
+		{seq: 2, payload: []byte(`{"message":"second"}`)},
+	}
+
+	for _, compress := range []bool{false, true} {
+		c := &testClient{compress: compress}
+		window, err := c.encodeWindow(events)
+		require.NoError(t, err)
+		empty, err := c.encodeWindow(nil)
+		require.NoError(t, err)
+
+		// Several windows are sent on the same connection.
+		br := newBatchReader(io.MultiReader(bytes.NewReader(window), bytes.NewReader(empty), bytes.NewReader(window)), defaultMaxWindowSize)
+		for _, want := range [][]event{expected, {}, expected} {
+			got, err := br.readBatch()
+			require.NoError(t, err)
+			assert.Equal(t, want, got)
+		}
+		_, err = br.readBatch()
+		assert.ErrorIs(t, err, io.EOF)
+	}
+}
+
+func TestReadBatchErrors(t *testing.T) {
+	frame := func(parts ...any) []byte {
+		var buf bytes.Buffer
+		for _, p := range parts {
+			_ = binary.Write(&buf, binary.BigEndian, p)
+		}
+		return buf.Bytes()
+	}
+	window := func(frames ...[]byte) []byte {
+		return append(frame([]byte("2W"), uint32(1)), bytes.Join(frames, nil)...)
+	}
+	compressed := func(frames ...[]byte) []byte {
+		var buf bytes.Buffer
+		zw := zlib.NewWriter(&buf)
+		_, _ = zw.Write(bytes.Join(frames, nil))
+		_ = zw.Close()
+		return frame([]byte("2C"), uint32(buf.Len()), buf.Bytes())
+	}
+	jsonFrame := func(seq uint32) []byte {
+		return frame([]byte("2J"), seq, uint32(2), []byte("{}"))
+	}
+
+	tests := []struct {
+		name  string
+		input []byte
+		err   error
+	}{
+		{
+			name:  "protocol v1",
+			input: frame([]byte("1W"), uint32(1)),
+			err:   errors.New(`unsupported protocol version '1'`),
+		},
+		{
+			name:  "missing window",
+			input: frame([]byte("2J"), uint32(1), uint32(2), []byte("{}")),
+			err:   errors.New(`expected a window frame, got frame type 'J'`),
+		},
+		{
+			name:  "unsupported frame type",
+			input: window(frame([]byte("2D"), uint32(1), uint32(0))),
+			err:   errors.New(`unsupported frame type 'D'`),
+		},
+		{
+			name:  "payload too large",
+			input: window(frame([]byte("2J"), uint32(1), uint32(maxPayloadSize+1))),
+			err:   errPayloadTooLarge,
+		},
+		{
+			name:  "invalid compressed payload",
+			input: window(frame([]byte("2C"), uint32(3), []byte("abc"))),
+			err:   errors.New("failed to decompress frame: zlib: invalid header"),
+		},
+		{
+			name:  "window too large",
+			input: frame([]byte("2W"), uint32(3)),
+			err:   errors.New("window of 3 events exceeds the maximum of 2"),
+		},
+		{
+			name:  "more events than the window",
+			input: window(compressed(jsonFrame(1), jsonFrame(2))),
+			err:   errTooManyEvents,
+		},
+		{
+			name:  "nested compressed frames",
+			input: window(compressed(compressed(jsonFrame(1)))),
+			err:   errNestedCompression,
+		},
+		{
+			name:  "truncated payload",
+			input: window(frame([]byte("2J"), uint32(1), uint32(10), []byte("{}"))),
+			err:   io.ErrUnexpectedEOF,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newBatchReader(bytes.NewReader(tt.input), 2).readBatch()
+			assert.EqualError(t, err, tt.err.Error())
+		})
+	}
+}
+
+func TestWriteACK(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, writeACK(&buf, 258))
+	assert.Equal(t, []byte{'2', 'A', 0, 0, 1, 2}, buf.Bytes())
+}
@@ -0,0 +1,223 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package lumberjackreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/lumberjackreceiver"
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/client"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/receiver"
+	"go.opentelemetry.io/collector/receiver/receiverhelper"
+	"go.uber.org/zap"
+)
+
+const (
+	scopeName = "otelcol/lumberjackreceiver"
+
+	// The format of the received data reported by the receiver observability.
+	dataFormat = "lumberjack"
+)
+
+// keepAliveInterval is the interval at which empty ACKs are sent to clients
+// while their events are consumed, so that they don't time out waiting for
+// slow pipelines.
+var keepAliveInterval = 5 * time.Second
+
+type lumberjackReceiver struct {
+	cfg          *Config
+	logger       *zap.Logger
+	nextConsumer consumer.Logs
+	obsrecv      *receiverhelper.ObsReport
+
+	listener net.Listener
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+
+	mu    sync.Mutex
+	conns map[net.Conn]struct{}
+}
+
+func newLumberjackReceiver(set receiver.CreateSettings, cfg *Config, nextConsumer consumer.Logs) (receiver.Logs, error) {
+	obsrecv, err := receiverhelper.NewObsReport(receiverhelper.ObsReportSettings{
+		ReceiverID:             set.ID,
+		Transport:              "tcp",
+		ReceiverCreateSettings: set,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &lumberjackReceiver{
+		cfg:          cfg,
+		logger:       set.Logger,
+		nextConsumer: nextConsumer,
+		obsrecv:      obsrecv,
+		conns:        map[net.Conn]struct{}{},
+	}, nil
+}
+
+func (r *lumberjackReceiver) Start(ctx context.Context, _ component.Host) error {
+	listener, err := r.cfg.TCPAddrConfig.Listen(ctx)
+	if err != nil {
+		return err
+	}
+
+	if r.cfg.TLSSetting != nil {
+		tlsCfg, err := r.cfg.TLSSetting.LoadTLSConfig(ctx)
+		if err != nil {
+			listener.Close()
+			return err
+		}
+		listener = tls.NewListener(listener, tlsCfg)
+	}
+	r.listener = listener
+
+	// The context of Start must not be used beyond Start.
+	ctx, r.cancel = context.WithCancel(context.Background())
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		r.acceptConnections(ctx)
+	}()
+	return nil
+}
+
+func (r *lumberjackReceiver) Shutdown(context.Context) error {
+	if r.listener == nil {
+		return nil
+	}
+
+	r.cancel()
+	err := r.listener.Close()
+
+	r.mu.Lock()
+	for conn := range r.conns {
+		conn.Close()
+	}
+	r.mu.Unlock()
+
+	r.wg.Wait()
+	return err
+}
+
+func (r *lumberjackReceiver) acceptConnections(ctx context.Context) {
+	for {
+		conn, err := r.listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, net.ErrClosed) {
+				return
+			}
+			// Keep accepting connections if at all possible, without hot
+			// looping if the error persists.
+			r.logger.Error("Failed to accept connection", zap.Error(err))
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(time.Second):
+				continue
+			}
+		}
+
+		r.mu.Lock()
+		r.conns[conn] = struct{}{}
+		r.mu.Unlock()
+
+		r.wg.Add(1)
+		go func() {
+			defer r.wg.Done()
+			defer func() {
+				r.mu.Lock()
+				delete(r.conns, conn)
+				r.mu.Unlock()
+				conn.Close()
+			}()
+
+			if err := r.handleConn(ctx, conn); err != nil && ctx.Err() == nil {
+				r.logger.Debug("Closing connection", zap.String("remoteAddr", conn.RemoteAddr().String()), zap.Error(err))
+			}
+		}()
+	}
+}
+
+// handleConn reads the batches of events sent on the connection, consumes
+// them and acknowledges them once consumed. The events are not acknowledged
+// if they can't be consumed, so that the client sends them again.
+func (r *lumberjackReceiver) handleConn(ctx context.Context, conn net.Conn) error {
+	ctx = client.NewContext(ctx, client.Info{Addr: conn.RemoteAddr()})
+	reader := newBatchReader(conn, r.cfg.MaxWindowSize)
+
+	for {
+		if err := conn.SetReadDeadline(time.Now().Add(r.cfg.InactivityTimeout)); err != nil {
+			return err
+		}
+		events, err := reader.readBatch()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		var seq uint32
+		if len(events) > 0 {
+			seq = events[len(events)-1].seq
+			if err = r.consumeEvents(ctx, conn, events); err != nil {
+				return err
+			}
+		}
+		if err = writeACK(conn, seq); err != nil {
+			return err
+		}
+	}
+}
+
+// consumeEvents converts the events to logs and passes them to the next
+// consumer, keeping the client waiting for the ACK alive meanwhile.
+func (r *lumberjackReceiver) consumeEvents(ctx context.Context, conn net.Conn, events []event) error {
+	lb := newLogsBuilder(time.Now())
+	for _, e := range events {
+		// Sending an invalid event again won't make it valid, so it is dropped
+		// rather than failing the whole batch.
+		if err := lb.appendEvent(e.payload); err != nil {
+			r.logger.Debug("Dropping invalid event", zap.Uint32("seq", e.seq), zap.Error(err))
+		}
+	}
+
+	done := make(chan struct{})
+	var keepAlive sync.WaitGroup
+	keepAlive.Add(1)
+	go func() {
+		defer keepAlive.Done()
+		ticker := time.NewTicker(keepAliveInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := writeACK(conn, 0); err != nil {
+					return
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	obsCtx := r.obsrecv.StartLogsOp(ctx)
+	count := lb.logs.LogRecordCount()
+	err := r.nextConsumer.ConsumeLogs(obsCtx, lb.logs)
+	r.obsrecv.EndLogsOp(obsCtx, dataFormat, count, err)
+
+	close(done)
+	keepAlive.Wait()
+	return err
+}
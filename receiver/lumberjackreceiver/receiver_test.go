@@ -0,0 +1,228 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package lumberjackreceiver
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+)
+
+func startReceiver(t *testing.T, cfg *Config, next consumer.Logs) *lumberjackReceiver {
+	r, err := newLumberjackReceiver(receivertest.NewNopCreateSettings(), cfg, next)
+	require.NoError(t, err)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, r.Shutdown(context.Background()))
+	})
+	return r.(*lumberjackReceiver)
+}
+
+func testConfig() *Config {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = "localhost:0"
+	return cfg
+}
+
+func TestReceiveEvents(t *testing.T) {
+	for _, compress := range []bool{false, true} {
+		sink := new(consumertest.LogsSink)
+		r := startReceiver(t, testConfig(), sink)
+
+		conn, err := net.Dial("tcp", r.listener.Addr().String())
+		require.NoError(t, err)
+		defer conn.Close()
+		c := &testClient{conn: conn, compress: compress}
+
+		_, err = c.send([]map[string]any{
+			{"@timestamp": "2024-04-22T10:15:30Z", "message": "first", "host": map[string]any{"name": "web-1"}},
+			{"@timestamp": "2024-04-22T10:15:31Z", "message": "second", "host": map[string]any{"name": "web-1"}},
+			{"@timestamp": "invalid", "message": "dropped"},
+		})
+		require.NoError(t, err)
+		_, err = c.send([]map[string]any{{"message": "third"}})
+		require.NoError(t, err)
+
+		// Empty windows are acknowledged without consuming anything.
+		_, err = c.send(nil)
+		require.NoError(t, err)
+
+		require.Len(t, sink.AllLogs(), 2)
+		assert.Equal(t, 3, sink.LogRecordCount())
+		records := sink.AllLogs()[0].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords()
+		require.Equal(t, 2, records.Len())
+		assert.Equal(t, "first", records.At(0).Body().Str())
+		assert.Equal(t, "second", records.At(1).Body().Str())
+	}
+}
+
+func TestReceiveEventsTLS(t *testing.T) {
+	certFile, keyFile, pool := generateCertificate(t)
+	cfg := testConfig()
+	cfg.TLSSetting = &configtls.ServerConfig{
+		Config: configtls.Config{
+			CertFile: certFile,
+			KeyFile:  keyFile,
+		},
+	}
+	sink := new(consumertest.LogsSink)
+	r := startReceiver(t, cfg, sink)
+
+	conn, err := tls.Dial("tcp", r.listener.Addr().String(), &tls.Config{
+		RootCAs:    pool,
+		ServerName: "localhost",
+		MinVersion: tls.VersionTLS12,
+	})
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = (&testClient{conn: conn, compress: true}).send([]map[string]any{{"message": "secured"}})
+	require.NoError(t, err)
+	require.Equal(t, 1, sink.LogRecordCount())
+}
+
+func TestConsumerError(t *testing.T) {
+	r := startReceiver(t, testConfig(), consumertest.NewErr(errors.New("pipeline failure")))
+
+	conn, err := net.Dial("tcp", r.listener.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// The events are not acknowledged, the connection is closed instead so
+	// that the client sends them again.
+	_, err = (&testClient{conn: conn}).send([]map[string]any{{"message": "lost"}})
+	require.Error(t, err)
+}
+
+func TestKeepAlive(t *testing.T) {
+	defer func(interval time.Duration) { keepAliveInterval = interval }(keepAliveInterval)
+	keepAliveInterval = 10 * time.Millisecond
+
+	release := make(chan struct{})
+	next, err := consumer.NewLogs(func(context.Context, plog.Logs) error {
+		<-release
+		return nil
+	})
+	require.NoError(t, err)
+	r := startReceiver(t, testConfig(), next)
+
+	conn, err := net.Dial("tcp", r.listener.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+	c := &testClient{conn: conn}
+
+	go func() {
+		// The consumer is released once the client received a keep alive.
+		seq, err := c.readACK()
+		assert.NoError(t, err)
+		assert.Equal(t, uint32(0), seq)
+		close(release)
+	}()
+
+	window, err := c.encodeWindow([]map[string]any{{"message": "slow"}})
+	require.NoError(t, err)
+	_, err = conn.Write(window)
+	require.NoError(t, err)
+
+	<-release
+	for {
+		seq, err := c.readACK()
+		require.NoError(t, err)
+		if seq == 1 {
+			break
+		}
+		assert.Equal(t, uint32(0), seq)
+	}
+}
+
+func TestInactivityTimeout(t *testing.T) {
+	cfg := testConfig()
+	cfg.InactivityTimeout = 50 * time.Millisecond
+	r := startReceiver(t, cfg, consumertest.NewNop())
+
+	conn, err := net.Dial("tcp", r.listener.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	_, err = (&testClient{conn: conn}).readACK()
+	var netErr net.Error
+	assert.False(t, errors.As(err, &netErr) && netErr.Timeout(), "the connection should have been closed by the receiver")
+	assert.Error(t, err)
+}
+
+func TestShutdownWithActiveConnections(t *testing.T) {
+	r, err := newLumberjackReceiver(receivertest.NewNopCreateSettings(), testConfig(), consumertest.NewNop())
+	require.NoError(t, err)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+
+	conn, err := net.Dial("tcp", r.(*lumberjackReceiver).listener.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = (&testClient{conn: conn}).send([]map[string]any{{"message": "hello"}})
+	require.NoError(t, err)
+
+	require.NoError(t, r.Shutdown(context.Background()))
+}
+
+func TestShutdownWithoutStart(t *testing.T) {
+	r, err := newLumberjackReceiver(receivertest.NewNopCreateSettings(), testConfig(), consumertest.NewNop())
+	require.NoError(t, err)
+	require.NoError(t, r.Shutdown(context.Background()))
+}
+
+// generateCertificate writes a self-signed certificate for localhost and its
+// key to temporary files, returning the pool of certificates trusting it.
+func generateCertificate(t *testing.T) (string, string, *x509.CertPool) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600))
+
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+	return certFile, keyFile, pool
+}
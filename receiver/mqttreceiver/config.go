@@ -0,0 +1,92 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package mqttreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/mqttreceiver"
+
+import (
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/collector/component"
+	"go.uber.org/multierr"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/mqtt"
+)
+
+var (
+	errMissingTopics     = errors.New("at least one topic must be configured under logs, metrics or traces")
+	errSessionNoClientID = errors.New("client_id must be specified when clean_session is false")
+)
+
+// Config defines configuration for the MQTT receiver.
+type Config struct {
+	mqtt.ClientConfig `mapstructure:",squash"`
+	// CleanSession discards the session of the client when it connects. The
+	// broker keeps the subscriptions and the messages published while the
+	// client is disconnected when it is false.
+	CleanSession bool `mapstructure:"clean_session"`
+
+	// Logs, Metrics and Traces configure the topics of the signals.
+	Logs    SignalConfig `mapstructure:"logs"`
+	Metrics SignalConfig `mapstructure:"metrics"`
+	Traces  SignalConfig `mapstructure:"traces"`
+}
+
+// SignalConfig configures the topics to which the telemetry of a signal is
+// published.
+type SignalConfig struct {
+	// Topics are the topic filters subscribed to.
+	Topics []TopicConfig `mapstructure:"topics"`
+	// EncodingExtensionID is the encoding extension which unmarshals the
+	// payloads, which are OTLP protobuf messages by default.
+	EncodingExtensionID *component.ID `mapstructure:"encoding_extension"`
+}
+
+// TopicConfig configures a subscription.
+type TopicConfig struct {
+	// Filter is the topic filter, which may contain the + and # wildcards,
+	// and {name} levels setting the name resource attribute to the level of
+	// the topic of the messages.
+	Filter string `mapstructure:"filter"`
+	// QoS is the maximum QoS of the messages received.
+	QoS byte `mapstructure:"qos"`
+}
+
+var _ component.Config = (*Config)(nil)
+
+func (cfg *Config) Validate() error {
+	var errs error
+	if len(cfg.Logs.Topics) == 0 && len(cfg.Metrics.Topics) == 0 && len(cfg.Traces.Topics) == 0 {
+		errs = multierr.Append(errs, errMissingTopics)
+	}
+	if !cfg.CleanSession && cfg.ClientID == "" {
+		errs = multierr.Append(errs, errSessionNoClientID)
+	}
+
+	// The messages are routed to the signals by topic filter.
+	seen := map[string]bool{}
+	for _, signal := range []struct {
+		name   string
+		config SignalConfig
+	}{
+		{name: "logs", config: cfg.Logs},
+		{name: "metrics", config: cfg.Metrics},
+		{name: "traces", config: cfg.Traces},
+	} {
+		for _, topic := range signal.config.Topics {
+			if err := mqtt.ValidateQoS(topic.QoS); err != nil {
+				errs = multierr.Append(errs, fmt.Errorf("%s: %w", signal.name, err))
+			}
+			f, err := parseTopicFilter(topic.Filter)
+			if err != nil {
+				errs = multierr.Append(errs, fmt.Errorf("%s: %w", signal.name, err))
+				continue
+			}
+			if seen[f.filter] {
+				errs = multierr.Append(errs, fmt.Errorf("%s: duplicate topic filter %q", signal.name, f.filter))
+			}
+			seen[f.filter] = true
+		}
+	}
+	return errs
+}
@@ -0,0 +1,96 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package mqttreceiver
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/confmap/confmaptest"
+	"go.uber.org/multierr"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/mqtt"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/mqttreceiver/internal/metadata"
+)
+
+func TestLoadConfig(t *testing.T) {
+	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config.yaml"))
+	require.NoError(t, err)
+
+	encodingID := component.MustNewID("text_encoding")
+	tests := []struct {
+		id          component.ID
+		expected    component.Config
+		expectedErr error
+	}{
+		{
+			id: component.NewIDWithName(metadata.Type, "custom"),
+			expected: &Config{
+				ClientConfig: mqtt.ClientConfig{
+					Endpoint: "ssl://broker.example.com:8883",
+					ClientID: "collector",
+					Username: "collector",
+					Password: "secret",
+					TLSSetting: &configtls.ClientConfig{
+						Config: configtls.Config{CAFile: "ca.pem"},
+					},
+					ConnectTimeout: 10 * time.Second,
+					KeepAlive:      time.Minute,
+				},
+				CleanSession: false,
+				Logs: SignalConfig{
+					Topics:              []TopicConfig{{Filter: "gateways/{gateway}/logs/#", QoS: 1}},
+					EncodingExtensionID: &encodingID,
+				},
+				Metrics: SignalConfig{
+					Topics: []TopicConfig{
+						{Filter: "gateways/{gateway}/sensors/{sensor}", QoS: 2},
+						{Filter: "gateways/+/metrics"},
+					},
+				},
+				Traces: SignalConfig{
+					Topics: []TopicConfig{{Filter: "gateways/{gateway}/traces"}},
+				},
+			},
+		},
+		{
+			id:          component.NewIDWithName(metadata.Type, "no_topics"),
+			expectedErr: errMissingTopics,
+		},
+		{
+			id: component.NewIDWithName(metadata.Type, "invalid"),
+			expectedErr: multierr.Combine(
+				errSessionNoClientID,
+				errors.New("logs: invalid qos 3: must be 0, 1 or 2"),
+				errors.New(`logs: invalid topic filter "gateways/#/logs": # must be the last level`),
+				errors.New(`metrics: duplicate topic filter "gateways/+/logs"`),
+				errors.New(`metrics: invalid topic filter "gateways/{}/metrics": invalid attribute name ""`),
+				errors.New("traces: topic filter must not be empty"),
+				errors.New(`invalid endpoint "http://localhost:1883": the scheme must be one of tcp, mqtt, ssl, tls, mqtts, ws or wss`),
+			),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id.String(), func(t *testing.T) {
+			cfg := createDefaultConfig()
+			sub, err := cm.Sub(tt.id.String())
+			require.NoError(t, err)
+			require.NoError(t, sub.Unmarshal(cfg))
+
+			if tt.expectedErr != nil {
+				assert.EqualError(t, component.ValidateConfig(cfg), tt.expectedErr.Error())
+				return
+			}
+			assert.NoError(t, component.ValidateConfig(cfg))
+			assert.Equal(t, tt.expected, cfg)
+		})
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package mqttreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/mqttreceiver"
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/receiver"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/mqtt"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/sharedcomponent"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/mqttreceiver/internal/metadata"
+)
+
+// receivers holds the receivers by configuration, so that the pipelines of
+// the signals share the same connection to the broker.
+var receivers = sharedcomponent.NewSharedComponents()
+
+// NewFactory creates a factory for the MQTT receiver.
+func NewFactory() receiver.Factory {
+	return receiver.NewFactory(
+		metadata.Type,
+		createDefaultConfig,
+		receiver.WithLogs(createLogsReceiver, metadata.LogsStability),
+		receiver.WithMetrics(createMetricsReceiver, metadata.MetricsStability),
+		receiver.WithTraces(createTracesReceiver, metadata.TracesStability),
+	)
+}
+
+func createDefaultConfig() component.Config {
+	return &Config{
+		ClientConfig: mqtt.NewDefaultClientConfig(),
+		CleanSession: true,
+	}
+}
+
+func createLogsReceiver(
+	_ context.Context,
+	params receiver.CreateSettings,
+	cfg component.Config,
+	consumer consumer.Logs,
+) (receiver.Logs, error) {
+	c := cfg.(*Config)
+	if len(c.Logs.Topics) == 0 {
+		return nil, fmt.Errorf("no topics configured for %s", component.DataTypeLogs)
+	}
+	r, err := getOrAddReceiver(params, c)
+	if err != nil {
+		return nil, err
+	}
+	r.Unwrap().(*mqttReceiver).nextLogs = consumer
+	return r, nil
+}
+
+func createMetricsReceiver(
+	_ context.Context,
+	params receiver.CreateSettings,
+	cfg component.Config,
+	consumer consumer.Metrics,
+) (receiver.Metrics, error) {
+	c := cfg.(*Config)
+	if len(c.Metrics.Topics) == 0 {
+		return nil, fmt.Errorf("no topics configured for %s", component.DataTypeMetrics)
+	}
+	r, err := getOrAddReceiver(params, c)
+	if err != nil {
+		return nil, err
+	}
+	r.Unwrap().(*mqttReceiver).nextMetrics = consumer
+	return r, nil
+}
+
+func createTracesReceiver(
+	_ context.Context,
+	params receiver.CreateSettings,
+	cfg component.Config,
+	consumer consumer.Traces,
+) (receiver.Traces, error) {
+	c := cfg.(*Config)
+	if len(c.Traces.Topics) == 0 {
+		return nil, fmt.Errorf("no topics configured for %s", component.DataTypeTraces)
+	}
+	r, err := getOrAddReceiver(params, c)
+	if err != nil {
+		return nil, err
+	}
+	r.Unwrap().(*mqttReceiver).nextTraces = consumer
+	return r, nil
+}
+
+func getOrAddReceiver(params receiver.CreateSettings, cfg *Config) (*sharedcomponent.SharedComponent, error) {
+	var err error
+	r := receivers.GetOrAdd(cfg, func() component.Component {
+		var rcv *mqttReceiver
+		rcv, err = newMQTTReceiver(params, cfg)
+		return rcv
+	})
+	return r, err
+}
@@ -0,0 +1,53 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package mqttreceiver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/mqtt"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/mqttreceiver/internal/metadata"
+)
+
+func TestCreateDefaultConfig(t *testing.T) {
+	factory := NewFactory()
+	assert.Equal(t, metadata.Type, factory.Type())
+
+	cfg := factory.CreateDefaultConfig()
+	assert.Equal(t, &Config{
+		ClientConfig: mqtt.ClientConfig{
+			Endpoint:       "tcp://localhost:1883",
+			ConnectTimeout: 30 * time.Second,
+			KeepAlive:      30 * time.Second,
+		},
+		CleanSession: true,
+	}, cfg)
+	assert.NoError(t, componenttest.CheckConfigStruct(cfg))
+}
+
+func TestCreateReceivers(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.Logs.Topics = []TopicConfig{{Filter: "logs"}}
+	cfg.Metrics.Topics = []TopicConfig{{Filter: "metrics"}}
+	set := receivertest.NewNopCreateSettings()
+
+	logs, err := factory.CreateLogsReceiver(context.Background(), set, cfg, consumertest.NewNop())
+	require.NoError(t, err)
+	metrics, err := factory.CreateMetricsReceiver(context.Background(), set, cfg, consumertest.NewNop())
+	require.NoError(t, err)
+	// The pipelines share the same receiver, and thus the same connection.
+	assert.Same(t, logs, metrics)
+
+	_, err = factory.CreateTracesReceiver(context.Background(), set, cfg, consumertest.NewNop())
+	assert.EqualError(t, err, "no topics configured for traces")
+}
@@ -0,0 +1,124 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build integration
+
+package mqttreceiver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/mqtt/mqtttest"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/mqttreceiver/internal/metadata"
+)
+
+// The messages are retained by the broker, which delivers them to the receiver
+// whenever it subscribes to their topics.
+
+func TestIntegration(t *testing.T) {
+	broker := mqtttest.NewBroker(t)
+	broker.Publish(t, mqtttest.Message{Topic: "gateways/gw-1/logs", QoS: 1, Retain: true, Payload: marshalLogs(t)})
+	broker.Publish(t, mqtttest.Message{Topic: "gateways/gw-2/sensors/temperature", QoS: 2, Retain: true, Payload: marshalMetrics(t)})
+	broker.Publish(t, mqtttest.Message{Topic: "traces/gw-3", Retain: true, Payload: marshalTraces(t)})
+
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.Endpoint = broker.Endpoint()
+	cfg.Logs.Topics = []TopicConfig{{Filter: "gateways/{gateway}/logs", QoS: 1}}
+	cfg.Metrics.Topics = []TopicConfig{{Filter: "gateways/{gateway}/sensors/{sensor}", QoS: 2}}
+	cfg.Traces.Topics = []TopicConfig{{Filter: "traces/#"}}
+	set := receivertest.NewNopCreateSettings()
+
+	logsSink := new(consumertest.LogsSink)
+	metricsSink := new(consumertest.MetricsSink)
+	tracesSink := new(consumertest.TracesSink)
+	logs, err := factory.CreateLogsReceiver(context.Background(), set, cfg, logsSink)
+	require.NoError(t, err)
+	_, err = factory.CreateMetricsReceiver(context.Background(), set, cfg, metricsSink)
+	require.NoError(t, err)
+	_, err = factory.CreateTracesReceiver(context.Background(), set, cfg, tracesSink)
+	require.NoError(t, err)
+
+	require.NoError(t, logs.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() {
+		assert.NoError(t, logs.Shutdown(context.Background()))
+	}()
+
+	require.Eventually(t, func() bool {
+		return logsSink.LogRecordCount() == 1 && metricsSink.DataPointCount() == 1 && tracesSink.SpanCount() == 1
+	}, 30*time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, map[string]any{"service.name": "sensor", "gateway": "gw-1"},
+		logsSink.AllLogs()[0].ResourceLogs().At(0).Resource().Attributes().AsRaw())
+	assert.Equal(t, map[string]any{"service.name": "sensor", "gateway": "gw-2", "sensor": "temperature"},
+		metricsSink.AllMetrics()[0].ResourceMetrics().At(0).Resource().Attributes().AsRaw())
+	assert.Equal(t, map[string]any{"service.name": "sensor"},
+		tracesSink.AllTraces()[0].ResourceSpans().At(0).Resource().Attributes().AsRaw())
+}
+
+func TestIntegrationTLSAndCredentials(t *testing.T) {
+	broker := mqtttest.NewBroker(t, mqtttest.WithTLS(), mqtttest.WithCredentials("collector", "secret"))
+	broker.Publish(t, mqtttest.Message{Topic: "logs", QoS: 1, Retain: true, Payload: marshalLogs(t)})
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = broker.Endpoint()
+	cfg.ClientID = "collector"
+	cfg.CleanSession = false
+	cfg.Username = "collector"
+	cfg.Password = "secret"
+	cfg.TLSSetting = &configtls.ClientConfig{Config: configtls.Config{CAFile: broker.CAFile()}}
+	cfg.Logs.Topics = []TopicConfig{{Filter: "logs", QoS: 1}}
+
+	sink := new(consumertest.LogsSink)
+	r, err := newMQTTReceiver(receivertest.NewNopCreateSettings(), cfg)
+	require.NoError(t, err)
+	r.nextLogs = sink
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() {
+		assert.NoError(t, r.Shutdown(context.Background()))
+	}()
+
+	assert.Eventually(t, func() bool {
+		return sink.LogRecordCount() == 1
+	}, 30*time.Second, 10*time.Millisecond)
+}
+
+func TestIntegrationReconnects(t *testing.T) {
+	broker := mqtttest.NewBroker(t)
+	broker.Publish(t, mqtttest.Message{Topic: "logs", Retain: true, Payload: marshalLogs(t)})
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = broker.Endpoint()
+	cfg.ClientID = "collector"
+	cfg.Logs.Topics = []TopicConfig{{Filter: "logs"}}
+
+	sink := new(consumertest.LogsSink)
+	set := receivertest.NewNopCreateSettings()
+	set.ID = component.NewIDWithName(metadata.Type, "reconnects")
+	r, err := NewFactory().CreateLogsReceiver(context.Background(), set, cfg, sink)
+	require.NoError(t, err)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() {
+		assert.NoError(t, r.Shutdown(context.Background()))
+	}()
+	require.Eventually(t, func() bool {
+		return sink.LogRecordCount() == 1
+	}, 30*time.Second, 10*time.Millisecond)
+
+	// The subscriptions are renewed once the connection is lost, and the
+	// retained message is delivered again.
+	broker.DisconnectClient(t, "collector")
+	require.Eventually(t, func() bool {
+		return sink.LogRecordCount() == 2
+	}, 30*time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,343 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package mqttreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/mqttreceiver"
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/receiver"
+	"go.opentelemetry.io/collector/receiver/receiverhelper"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/encoding"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/mqtt"
+)
+
+const (
+	// The format of the payloads when no encoding extension is configured.
+	defaultFormat = "otlp_proto"
+
+	// The time given to the subscriptions and to the disconnection.
+	subscribeTimeout  = 10 * time.Second
+	disconnectQuiesce = 250 * time.Millisecond
+
+	// The return code of the subscriptions refused by the broker.
+	subscriptionFailure = 0x80
+
+	// The interval between the first two attempts to consume a message
+	// failing with a retryable error, which doubles up to maxRetryInterval.
+	initialRetryInterval = 100 * time.Millisecond
+	maxRetryInterval     = 30 * time.Second
+)
+
+var (
+	_ receiver.Logs    = (*mqttReceiver)(nil)
+	_ receiver.Metrics = (*mqttReceiver)(nil)
+	_ receiver.Traces  = (*mqttReceiver)(nil)
+)
+
+// mqttReceiver subscribes to the topics of the signals, and passes the
+// messages published to them to the consumers. It is shared by the pipelines
+// of the signals.
+type mqttReceiver struct {
+	cfg      *Config
+	settings receiver.CreateSettings
+	obsrecv  *receiverhelper.ObsReport
+
+	nextLogs    consumer.Logs
+	nextMetrics consumer.Metrics
+	nextTraces  consumer.Traces
+
+	client paho.Client
+	// filters are the QoS of the topic filters subscribed to.
+	filters map[string]byte
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// newMQTTReceiver creates the MQTT receiver. The consumers are set by the
+// factory, as the receiver is shared by the pipelines of the signals.
+func newMQTTReceiver(set receiver.CreateSettings, cfg *Config) (*mqttReceiver, error) {
+	obsrecv, err := receiverhelper.NewObsReport(receiverhelper.ObsReportSettings{
+		ReceiverID:             set.ID,
+		Transport:              "mqtt",
+		ReceiverCreateSettings: set,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &mqttReceiver{
+		cfg:      cfg,
+		settings: set,
+		obsrecv:  obsrecv,
+		filters:  map[string]byte{},
+	}, nil
+}
+
+func (r *mqttReceiver) Start(ctx context.Context, host component.Host) error {
+	opts, err := r.cfg.NewClientOptions(ctx)
+	if err != nil {
+		return err
+	}
+	// The messages are consumed concurrently, and acknowledged by the
+	// handlers once consumed.
+	opts.SetCleanSession(r.cfg.CleanSession).
+		SetOrderMatters(false).
+		SetAutoAckDisabled(true).
+		SetOnConnectHandler(r.subscribe).
+		SetConnectionLostHandler(func(_ paho.Client, err error) {
+			r.settings.Logger.Warn("Lost the connection to the broker", zap.Error(err))
+		})
+	client := paho.NewClient(opts)
+
+	if r.nextLogs != nil {
+		unmarshaler, format, err := logsUnmarshaler(r.cfg.Logs.EncodingExtensionID, host)
+		if err != nil {
+			return err
+		}
+		r.addRoutes(client, r.cfg.Logs, func(f topicFilter) paho.MessageHandler {
+			return r.handleLogs(f, unmarshaler, format)
+		})
+	}
+	if r.nextMetrics != nil {
+		unmarshaler, format, err := metricsUnmarshaler(r.cfg.Metrics.EncodingExtensionID, host)
+		if err != nil {
+			return err
+		}
+		r.addRoutes(client, r.cfg.Metrics, func(f topicFilter) paho.MessageHandler {
+			return r.handleMetrics(f, unmarshaler, format)
+		})
+	}
+	if r.nextTraces != nil {
+		unmarshaler, format, err := tracesUnmarshaler(r.cfg.Traces.EncodingExtensionID, host)
+		if err != nil {
+			return err
+		}
+		r.addRoutes(client, r.cfg.Traces, func(f topicFilter) paho.MessageHandler {
+			return r.handleTraces(f, unmarshaler, format)
+		})
+	}
+
+	if r.cfg.CleanSession && r.acknowledges() {
+		r.settings.Logger.Warn("clean_session is true: the QoS 1 and 2 messages not consumed yet when the receiver " +
+			"disconnects from the broker are lost, as the broker discards them along with the session")
+	}
+
+	// The context of Start must not be used beyond Start.
+	r.ctx, r.cancel = context.WithCancel(context.Background())
+
+	// The client subscribes to the topics whenever it connects.
+	r.client = client
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		mqtt.Connect(r.ctx, client, r.settings.Logger)
+	}()
+	return nil
+}
+
+func (r *mqttReceiver) Shutdown(context.Context) error {
+	if r.client == nil {
+		return nil
+	}
+	r.cancel()
+	r.wg.Wait()
+	r.client.Disconnect(uint(disconnectQuiesce / time.Millisecond))
+	return nil
+}
+
+// addRoutes routes the messages published to the topics of a signal to its
+// handler.
+func (r *mqttReceiver) addRoutes(client paho.Client, cfg SignalConfig, handler func(topicFilter) paho.MessageHandler) {
+	for _, topic := range cfg.Topics {
+		// The filters were validated with the configuration.
+		f, _ := parseTopicFilter(topic.Filter)
+		r.filters[f.filter] = topic.QoS
+		client.AddRoute(f.filter, handler(f))
+	}
+}
+
+// acknowledges returns whether QoS 1 or 2 messages are subscribed to.
+func (r *mqttReceiver) acknowledges() bool {
+	for _, qos := range r.filters {
+		if qos > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// subscribe subscribes to the topics once the client is connected.
+func (r *mqttReceiver) subscribe(client paho.Client) {
+	token := client.SubscribeMultiple(r.filters, nil)
+	if !token.WaitTimeout(subscribeTimeout) {
+		r.settings.Logger.Error("Timed out subscribing to the topics")
+		return
+	}
+	if err := token.Error(); err != nil {
+		r.settings.Logger.Error("Failed to subscribe to the topics", zap.Error(err))
+		return
+	}
+	for filter, qos := range token.(*paho.SubscribeToken).Result() {
+		if qos == subscriptionFailure {
+			r.settings.Logger.Error("The broker refused the subscription", zap.String("filter", filter))
+		}
+	}
+}
+
+func (r *mqttReceiver) handleLogs(f topicFilter, unmarshaler plog.Unmarshaler, format string) paho.MessageHandler {
+	return func(_ paho.Client, msg paho.Message) {
+		logs, err := unmarshaler.UnmarshalLogs(msg.Payload())
+		if err != nil {
+			r.settings.Logger.Error("Failed to unmarshal logs", zap.String("topic", msg.Topic()), zap.Error(err))
+			r.obsrecv.EndLogsOp(r.obsrecv.StartLogsOp(r.ctx), format, 0, err)
+			msg.Ack()
+			return
+		}
+		for i := 0; i < logs.ResourceLogs().Len(); i++ {
+			f.putAttributes(msg.Topic(), logs.ResourceLogs().At(i).Resource().Attributes())
+		}
+		count := logs.LogRecordCount()
+		r.consume(msg, func() error {
+			ctx := r.obsrecv.StartLogsOp(r.ctx)
+			err := r.nextLogs.ConsumeLogs(ctx, logs)
+			r.obsrecv.EndLogsOp(ctx, format, count, err)
+			return err
+		})
+	}
+}
+
+func (r *mqttReceiver) handleMetrics(f topicFilter, unmarshaler pmetric.Unmarshaler, format string) paho.MessageHandler {
+	return func(_ paho.Client, msg paho.Message) {
+		metrics, err := unmarshaler.UnmarshalMetrics(msg.Payload())
+		if err != nil {
+			r.settings.Logger.Error("Failed to unmarshal metrics", zap.String("topic", msg.Topic()), zap.Error(err))
+			r.obsrecv.EndMetricsOp(r.obsrecv.StartMetricsOp(r.ctx), format, 0, err)
+			msg.Ack()
+			return
+		}
+		for i := 0; i < metrics.ResourceMetrics().Len(); i++ {
+			f.putAttributes(msg.Topic(), metrics.ResourceMetrics().At(i).Resource().Attributes())
+		}
+		count := metrics.DataPointCount()
+		r.consume(msg, func() error {
+			ctx := r.obsrecv.StartMetricsOp(r.ctx)
+			err := r.nextMetrics.ConsumeMetrics(ctx, metrics)
+			r.obsrecv.EndMetricsOp(ctx, format, count, err)
+			return err
+		})
+	}
+}
+
+func (r *mqttReceiver) handleTraces(f topicFilter, unmarshaler ptrace.Unmarshaler, format string) paho.MessageHandler {
+	return func(_ paho.Client, msg paho.Message) {
+		traces, err := unmarshaler.UnmarshalTraces(msg.Payload())
+		if err != nil {
+			r.settings.Logger.Error("Failed to unmarshal traces", zap.String("topic", msg.Topic()), zap.Error(err))
+			r.obsrecv.EndTracesOp(r.obsrecv.StartTracesOp(r.ctx), format, 0, err)
+			msg.Ack()
+			return
+		}
+		for i := 0; i < traces.ResourceSpans().Len(); i++ {
+			f.putAttributes(msg.Topic(), traces.ResourceSpans().At(i).Resource().Attributes())
+		}
+		count := traces.SpanCount()
+		r.consume(msg, func() error {
+			ctx := r.obsrecv.StartTracesOp(r.ctx)
+			err := r.nextTraces.ConsumeTraces(ctx, traces)
+			r.obsrecv.EndTracesOp(ctx, format, count, err)
+			return err
+		})
+	}
+}
+
+// consume consumes the data of a message, and acknowledges the message once
+// consumed or refused with a permanent error. The data is consumed again while
+// it fails with a retryable error, with an increasing interval. The message is
+// left unacknowledged if the receiver shuts down in the meantime, for the
+// broker to deliver it again when the client resumes its session.
+func (r *mqttReceiver) consume(msg paho.Message, consume func() error) {
+	interval := initialRetryInterval
+	for {
+		err := consume()
+		if err == nil || consumererror.IsPermanent(err) {
+			msg.Ack()
+			return
+		}
+
+		r.settings.Logger.Warn("Failed to consume a message", zap.String("topic", msg.Topic()),
+			zap.Duration("retry_interval", interval), zap.Error(err))
+		select {
+		case <-time.After(interval):
+		case <-r.ctx.Done():
+			return
+		}
+		interval = min(2*interval, maxRetryInterval)
+	}
+}
+
+func logsUnmarshaler(id *component.ID, host component.Host) (plog.Unmarshaler, string, error) {
+	if id == nil {
+		return &plog.ProtoUnmarshaler{}, defaultFormat, nil
+	}
+	ext, err := encodingExtension(id, host)
+	if err != nil {
+		return nil, "", err
+	}
+	unmarshaler, ok := ext.(encoding.LogsUnmarshalerExtension)
+	if !ok {
+		return nil, "", fmt.Errorf("extension %q is not a logs unmarshaler", id)
+	}
+	return unmarshaler, id.String(), nil
+}
+
+func metricsUnmarshaler(id *component.ID, host component.Host) (pmetric.Unmarshaler, string, error) {
+	if id == nil {
+		return &pmetric.ProtoUnmarshaler{}, defaultFormat, nil
+	}
+	ext, err := encodingExtension(id, host)
+	if err != nil {
+		return nil, "", err
+	}
+	unmarshaler, ok := ext.(encoding.MetricsUnmarshalerExtension)
+	if !ok {
+		return nil, "", fmt.Errorf("extension %q is not a metrics unmarshaler", id)
+	}
+	return unmarshaler, id.String(), nil
+}
+
+func tracesUnmarshaler(id *component.ID, host component.Host) (ptrace.Unmarshaler, string, error) {
+	if id == nil {
+		return &ptrace.ProtoUnmarshaler{}, defaultFormat, nil
+	}
+	ext, err := encodingExtension(id, host)
+	if err != nil {
+		return nil, "", err
+	}
+	unmarshaler, ok := ext.(encoding.TracesUnmarshalerExtension)
+	if !ok {
+		return nil, "", fmt.Errorf("extension %q is not a traces unmarshaler", id)
+	}
+	return unmarshaler, id.String(), nil
+}
+
+func encodingExtension(id *component.ID, host component.Host) (component.Component, error) {
+	ext, ok := host.GetExtensions()[*id]
+	if !ok {
+		return nil, fmt.Errorf("unknown encoding %q", id)
+	}
+	return ext, nil
+}
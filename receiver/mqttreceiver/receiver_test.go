@@ -0,0 +1,355 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package mqttreceiver
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestReceiverEncodingExtension(t *testing.T) {
+	encodingID := component.MustNewID("text_encoding")
+	cfg := createDefaultConfig().(*Config)
+	cfg.Logs.Topics = []TopicConfig{{Filter: "gateways/{gateway}/syslog"}}
+	cfg.Logs.EncodingExtensionID = &encodingID
+	host := &testHost{Host: componenttest.NewNopHost(), extensions: map[component.ID]component.Component{
+		encodingID: &textEncoding{},
+	}}
+
+	sink := new(consumertest.LogsSink)
+	r := newTestReceiver(t, cfg)
+	r.nextLogs = sink
+	unmarshaler, format, err := logsUnmarshaler(cfg.Logs.EncodingExtensionID, host)
+	require.NoError(t, err)
+	assert.Equal(t, "text_encoding", format)
+	f, err := parseTopicFilter("gateways/{gateway}/syslog")
+	require.NoError(t, err)
+
+	msg := &testMessage{topic: "gateways/gw-1/syslog", payload: []byte("booted\nlink up")}
+	r.handleLogs(f, unmarshaler, format)(nil, msg)
+	assert.True(t, msg.isAcked())
+	require.Equal(t, 2, sink.LogRecordCount())
+	rl := sink.AllLogs()[0].ResourceLogs().At(0)
+	assert.Equal(t, map[string]any{"gateway": "gw-1"}, rl.Resource().Attributes().AsRaw())
+	assert.Equal(t, "booted", rl.ScopeLogs().At(0).LogRecords().At(0).Body().Str())
+	assert.Equal(t, "link up", rl.ScopeLogs().At(0).LogRecords().At(1).Body().Str())
+}
+
+func TestReceiverInvalidEncodingExtension(t *testing.T) {
+	encodingID := component.MustNewID("text_encoding")
+	unknownID := component.MustNewID("unknown")
+	host := &testHost{Host: componenttest.NewNopHost(), extensions: map[component.ID]component.Component{
+		encodingID: &textEncoding{},
+	}}
+
+	tests := []struct {
+		name        string
+		configure   func(*Config, *mqttReceiver)
+		expectedErr string
+	}{
+		{
+			name: "unknown",
+			configure: func(cfg *Config, r *mqttReceiver) {
+				cfg.Logs.Topics = []TopicConfig{{Filter: "logs"}}
+				cfg.Logs.EncodingExtensionID = &unknownID
+				r.nextLogs = consumertest.NewNop()
+			},
+			expectedErr: `unknown encoding "unknown"`,
+		},
+		{
+			name: "not_an_unmarshaler",
+			configure: func(cfg *Config, r *mqttReceiver) {
+				cfg.Metrics.Topics = []TopicConfig{{Filter: "metrics"}}
+				cfg.Metrics.EncodingExtensionID = &encodingID
+				r.nextMetrics = consumertest.NewNop()
+			},
+			expectedErr: `extension "text_encoding" is not a metrics unmarshaler`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			r, err := newMQTTReceiver(receivertest.NewNopCreateSettings(), cfg)
+			require.NoError(t, err)
+			tt.configure(cfg, r)
+			assert.EqualError(t, r.Start(context.Background(), host), tt.expectedErr)
+			assert.NoError(t, r.Shutdown(context.Background()))
+		})
+	}
+}
+
+func TestReceiverInvalidPayload(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Traces.Topics = []TopicConfig{{Filter: "traces"}}
+	f, err := parseTopicFilter("traces")
+	require.NoError(t, err)
+
+	sink := new(consumertest.TracesSink)
+	r := newTestReceiver(t, cfg)
+	r.nextTraces = sink
+	handler := r.handleTraces(f, &ptrace.ProtoUnmarshaler{}, defaultFormat)
+
+	// The invalid payload is acknowledged and dropped, without affecting the
+	// next messages.
+	invalid := &testMessage{topic: "traces", payload: []byte("not a protobuf message")}
+	handler(nil, invalid)
+	assert.True(t, invalid.isAcked())
+	assert.Equal(t, 0, sink.SpanCount())
+
+	handler(nil, &testMessage{topic: "traces", payload: marshalTraces(t)})
+	assert.Equal(t, 1, sink.SpanCount())
+}
+
+func TestReceiverAcknowledgesConsumedMessages(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Logs.Topics = []TopicConfig{{Filter: "logs", QoS: 1}}
+	f, err := parseTopicFilter("logs")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		err   error
+		calls int
+	}{
+		{name: "consumed", calls: 1},
+		// Messages refused with a permanent error are acknowledged and dropped.
+		{name: "permanent_error", err: consumererror.NewPermanent(errors.New("invalid")), calls: 1},
+		// Messages failing with a retryable error are consumed again until
+		// they are consumed.
+		{name: "retryable_error", err: errors.New("unavailable"), calls: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTestReceiver(t, cfg)
+			consumer := &flakyLogs{err: tt.err, failures: tt.calls - 1}
+			r.nextLogs = consumer
+
+			msg := &testMessage{topic: "logs", payload: marshalLogs(t)}
+			r.handleLogs(f, &plog.ProtoUnmarshaler{}, defaultFormat)(nil, msg)
+			assert.True(t, msg.isAcked())
+			assert.Equal(t, tt.calls, consumer.calls())
+		})
+	}
+}
+
+func TestReceiverLeavesUnconsumedMessagesOnShutdown(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Logs.Topics = []TopicConfig{{Filter: "logs", QoS: 1}}
+	f, err := parseTopicFilter("logs")
+	require.NoError(t, err)
+
+	r := newTestReceiver(t, cfg)
+	consumer := &flakyLogs{err: errors.New("unavailable"), failures: -1}
+	r.nextLogs = consumer
+
+	// The message is left for the broker to deliver it again.
+	msg := &testMessage{topic: "logs", payload: marshalLogs(t)}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		r.handleLogs(f, &plog.ProtoUnmarshaler{}, defaultFormat)(nil, msg)
+	}()
+	require.Eventually(t, func() bool {
+		return consumer.calls() == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	r.cancel()
+	<-done
+	assert.False(t, msg.isAcked())
+}
+
+func TestReceiverWarnsOfCleanSession(t *testing.T) {
+	tests := []struct {
+		name         string
+		cleanSession bool
+		qos          byte
+		expectedWarn bool
+	}{
+		{name: "clean_session", cleanSession: true, qos: 1, expectedWarn: true},
+		{name: "clean_session_qos_0", cleanSession: true, qos: 0},
+		{name: "persistent_session", qos: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.Endpoint = "tcp://127.0.0.1:1"
+			cfg.ClientID = "collector"
+			cfg.CleanSession = tt.cleanSession
+			cfg.Logs.Topics = []TopicConfig{{Filter: "logs", QoS: tt.qos}}
+
+			core, logs := observer.New(zap.WarnLevel)
+			set := receivertest.NewNopCreateSettings()
+			set.Logger = zap.New(core)
+			r, err := newMQTTReceiver(set, cfg)
+			require.NoError(t, err)
+			r.nextLogs = consumertest.NewNop()
+			require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+			assert.NoError(t, r.Shutdown(context.Background()))
+
+			warned := logs.FilterMessageSnippet("clean_session is true").Len() > 0
+			assert.Equal(t, tt.expectedWarn, warned)
+		})
+	}
+}
+
+// flakyLogs fails to consume the logs with an error the given number of
+// times, or always when it is negative.
+type flakyLogs struct {
+	consumertest.LogsSink
+	err      error
+	failures int
+
+	mu    sync.Mutex
+	count int
+}
+
+func (c *flakyLogs) calls() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.count
+}
+
+func (c *flakyLogs) ConsumeLogs(ctx context.Context, ld plog.Logs) error {
+	c.mu.Lock()
+	c.count++
+	fail := c.failures < 0 || c.count <= c.failures
+	c.mu.Unlock()
+	if fail {
+		return c.err
+	}
+	return c.LogsSink.ConsumeLogs(ctx, ld)
+}
+
+// testMessage is a message recording whether it is acknowledged.
+type testMessage struct {
+	topic   string
+	payload []byte
+	acked   atomic.Bool
+}
+
+func (*testMessage) Duplicate() bool {
+	return false
+}
+
+func (*testMessage) Qos() byte {
+	return 1
+}
+
+func (*testMessage) Retained() bool {
+	return false
+}
+
+func (m *testMessage) Topic() string {
+	return m.topic
+}
+
+func (*testMessage) MessageID() uint16 {
+	return 1
+}
+
+func (m *testMessage) Payload() []byte {
+	return m.payload
+}
+
+func (m *testMessage) Ack() {
+	m.acked.Store(true)
+}
+
+func (m *testMessage) isAcked() bool {
+	return m.acked.Load()
+}
+
+// newTestReceiver creates a receiver whose handlers can be called without
+// starting it.
+func newTestReceiver(t *testing.T, cfg *Config) *mqttReceiver {
+	r, err := newMQTTReceiver(receivertest.NewNopCreateSettings(), cfg)
+	require.NoError(t, err)
+	r.ctx, r.cancel = context.WithCancel(context.Background())
+	t.Cleanup(r.cancel)
+	return r
+}
+
+func TestShutdownWithoutStart(t *testing.T) {
+	r, err := newMQTTReceiver(receivertest.NewNopCreateSettings(), createDefaultConfig().(*Config))
+	require.NoError(t, err)
+	require.NoError(t, r.Shutdown(context.Background()))
+}
+
+func marshalLogs(t *testing.T) []byte {
+	logs := plog.NewLogs()
+	rl := logs.ResourceLogs().AppendEmpty()
+	rl.Resource().Attributes().PutStr("service.name", "sensor")
+	rl.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStr("calibrated")
+	payload, err := (&plog.ProtoMarshaler{}).MarshalLogs(logs)
+	require.NoError(t, err)
+	return payload
+}
+
+func marshalMetrics(t *testing.T) []byte {
+	metrics := pmetric.NewMetrics()
+	rm := metrics.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().PutStr("service.name", "sensor")
+	m := rm.ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	m.SetName("temperature")
+	m.SetEmptyGauge().DataPoints().AppendEmpty().SetDoubleValue(21.5)
+	payload, err := (&pmetric.ProtoMarshaler{}).MarshalMetrics(metrics)
+	require.NoError(t, err)
+	return payload
+}
+
+func marshalTraces(t *testing.T) []byte {
+	traces := ptrace.NewTraces()
+	rs := traces.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().PutStr("service.name", "sensor")
+	rs.ScopeSpans().AppendEmpty().Spans().AppendEmpty().SetName("calibrate")
+	payload, err := (&ptrace.ProtoMarshaler{}).MarshalTraces(traces)
+	require.NoError(t, err)
+	return payload
+}
+
+type testHost struct {
+	component.Host
+	extensions map[component.ID]component.Component
+}
+
+func (h *testHost) GetExtensions() map[component.ID]component.Component {
+	return h.extensions
+}
+
+// textEncoding is an encoding extension unmarshaling a log record per line.
+type textEncoding struct{}
+
+func (*textEncoding) Start(context.Context, component.Host) error {
+	return nil
+}
+
+func (*textEncoding) Shutdown(context.Context) error {
+	return nil
+}
+
+func (*textEncoding) UnmarshalLogs(buf []byte) (plog.Logs, error) {
+	logs := plog.NewLogs()
+	records := logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords()
+	for _, line := range bytes.Split(buf, []byte("\n")) {
+		records.AppendEmpty().Body().SetStr(string(line))
+	}
+	return logs, nil
+}
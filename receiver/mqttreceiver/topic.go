@@ -0,0 +1,72 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package mqttreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/mqttreceiver"
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+var errEmptyTopicFilter = errors.New("topic filter must not be empty")
+
+// topicFilter is a topic filter of the configuration, whose {name} levels
+// are subscribed to with the single-level wildcard.
+type topicFilter struct {
+	// filter is the topic filter subscribed to.
+	filter string
+	// attributes are the names of the attributes set to the levels of the
+	// topics, which are empty for the levels not captured.
+	attributes []string
+}
+
+// parseTopicFilter parses a topic filter, which may contain the single-level
+// (+) and multi-level (#) wildcards, and {name} levels matching any level and
+// capturing it as the name attribute.
+func parseTopicFilter(filter string) (topicFilter, error) {
+	if filter == "" {
+		return topicFilter{}, errEmptyTopicFilter
+	}
+
+	levels := strings.Split(filter, "/")
+	f := topicFilter{attributes: make([]string, len(levels))}
+	seen := map[string]bool{}
+	for i, level := range levels {
+		switch {
+		case level == "#":
+			if i != len(levels)-1 {
+				return topicFilter{}, fmt.Errorf("invalid topic filter %q: # must be the last level", filter)
+			}
+		case level == "+":
+		case strings.HasPrefix(level, "{") && strings.HasSuffix(level, "}"):
+			name := level[1 : len(level)-1]
+			if name == "" || strings.ContainsAny(name, "{}+#") {
+				return topicFilter{}, fmt.Errorf("invalid topic filter %q: invalid attribute name %q", filter, name)
+			}
+			if seen[name] {
+				return topicFilter{}, fmt.Errorf("invalid topic filter %q: duplicate attribute %q", filter, name)
+			}
+			seen[name] = true
+			f.attributes[i] = name
+			levels[i] = "+"
+		case strings.ContainsAny(level, "{}+#"):
+			return topicFilter{}, fmt.Errorf("invalid topic filter %q: wildcards and attributes must be whole levels", filter)
+		}
+	}
+	f.filter = strings.Join(levels, "/")
+	return f, nil
+}
+
+// putAttributes sets the attributes captured from the levels of a topic
+// matching the filter.
+func (f topicFilter) putAttributes(topic string, attrs pcommon.Map) {
+	levels := strings.Split(topic, "/")
+	for i, name := range f.attributes {
+		if name != "" && i < len(levels) {
+			attrs.PutStr(name, levels[i])
+		}
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package mqttreceiver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+func TestParseTopicFilter(t *testing.T) {
+	tests := []struct {
+		filter      string
+		expected    topicFilter
+		expectedErr string
+	}{
+		{
+			filter:   "telemetry",
+			expected: topicFilter{filter: "telemetry", attributes: []string{""}},
+		},
+		{
+			filter:   "gateways/+/sensors/#",
+			expected: topicFilter{filter: "gateways/+/sensors/#", attributes: []string{"", "", "", ""}},
+		},
+		{
+			filter:   "gateways/{gateway}/sensors/{sensor}",
+			expected: topicFilter{filter: "gateways/+/sensors/+", attributes: []string{"", "gateway", "", "sensor"}},
+		},
+		{
+			filter:   "{site}/{gateway}/#",
+			expected: topicFilter{filter: "+/+/#", attributes: []string{"site", "gateway", ""}},
+		},
+		{
+			filter:      "",
+			expectedErr: "topic filter must not be empty",
+		},
+		{
+			filter:      "gateways/#/logs",
+			expectedErr: `invalid topic filter "gateways/#/logs": # must be the last level`,
+		},
+		{
+			filter:      "gateways/gw+/logs",
+			expectedErr: `invalid topic filter "gateways/gw+/logs": wildcards and attributes must be whole levels`,
+		},
+		{
+			filter:      "gateways/gw-{id}",
+			expectedErr: `invalid topic filter "gateways/gw-{id}": wildcards and attributes must be whole levels`,
+		},
+		{
+			filter:      "gateways/{}",
+			expectedErr: `invalid topic filter "gateways/{}": invalid attribute name ""`,
+		},
+		{
+			filter:      "{id}/{id}",
+			expectedErr: `invalid topic filter "{id}/{id}": duplicate attribute "id"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			f, err := parseTopicFilter(tt.filter)
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, f)
+		})
+	}
+}
+
+func TestPutAttributes(t *testing.T) {
+	f, err := parseTopicFilter("gateways/{gateway}/sensors/{sensor}/#")
+	require.NoError(t, err)
+
+	attrs := pcommon.NewMap()
+	attrs.PutStr("gateway", "overwritten")
+	f.putAttributes("gateways/gw-1/sensors/temperature/celsius/raw", attrs)
+	assert.Equal(t, map[string]any{"gateway": "gw-1", "sensor": "temperature"}, attrs.AsRaw())
+}
@@ -0,0 +1,72 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package netflowreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/netflowreceiver"
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/multierr"
+)
+
+var (
+	errMissingEndpoint            = errors.New("endpoint must be specified")
+	errInvalidAggregationInterval = errors.New("aggregation.interval must be positive")
+	errInvalidMaxTemplates        = errors.New("max_templates must be positive")
+	errInvalidTemplateTTL         = errors.New("template_ttl must be positive")
+)
+
+// Config defines configuration for the NetFlow receiver.
+type Config struct {
+	// Endpoint is the UDP address on which NetFlow and IPFIX packets are
+	// received.
+	Endpoint string `mapstructure:"endpoint"`
+	// MaxTemplates is the maximum number of NetFlow v9 and IPFIX templates
+	// cached, across all exporters. The least recently received templates
+	// are evicted first.
+	MaxTemplates int `mapstructure:"max_templates"`
+	// TemplateTTL is how long a template is cached after it was last
+	// received.
+	TemplateTTL time.Duration `mapstructure:"template_ttl"`
+	// Aggregation configures the metrics aggregated from the flows, which are
+	// only reported when the receiver is part of a metrics pipeline.
+	Aggregation AggregationConfig `mapstructure:"aggregation"`
+}
+
+// AggregationConfig configures the aggregation of the flows into metrics.
+type AggregationConfig struct {
+	// Interval is the interval at which the aggregated metrics are reported.
+	Interval time.Duration `mapstructure:"interval"`
+	// Attributes are the attributes of the flows by which they are
+	// aggregated, in addition to their exporter.
+	Attributes []string `mapstructure:"attributes"`
+}
+
+func (cfg *Config) Validate() error {
+	var errs error
+	if cfg.Endpoint == "" {
+		errs = multierr.Append(errs, errMissingEndpoint)
+	}
+	if cfg.MaxTemplates <= 0 {
+		errs = multierr.Append(errs, errInvalidMaxTemplates)
+	}
+	if cfg.TemplateTTL <= 0 {
+		errs = multierr.Append(errs, errInvalidTemplateTTL)
+	}
+	if cfg.Aggregation.Interval <= 0 {
+		errs = multierr.Append(errs, errInvalidAggregationInterval)
+	}
+	seen := map[string]bool{}
+	for _, attribute := range cfg.Aggregation.Attributes {
+		switch {
+		case attribute == "":
+			errs = multierr.Append(errs, errors.New("aggregation.attributes must not contain empty attributes"))
+		case seen[attribute]:
+			errs = multierr.Append(errs, fmt.Errorf("duplicate aggregation attribute %q", attribute))
+		}
+		seen[attribute] = true
+	}
+	return errs
+}
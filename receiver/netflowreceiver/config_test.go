@@ -0,0 +1,74 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package netflowreceiver
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap/confmaptest"
+	"go.uber.org/multierr"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/netflowreceiver/internal/metadata"
+)
+
+func TestLoadConfig(t *testing.T) {
+	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config.yaml"))
+	require.NoError(t, err)
+
+	tests := []struct {
+		id          component.ID
+		expected    component.Config
+		expectedErr error
+	}{
+		{
+			id:       component.NewID(metadata.Type),
+			expected: createDefaultConfig(),
+		},
+		{
+			id: component.NewIDWithName(metadata.Type, "custom"),
+			expected: &Config{
+				Endpoint:     "0.0.0.0:4739",
+				MaxTemplates: 500,
+				TemplateTTL:  10 * time.Minute,
+				Aggregation: AggregationConfig{
+					Interval:   10 * time.Second,
+					Attributes: []string{"destination.address", "destination.port", "network.transport"},
+				},
+			},
+		},
+		{
+			id: component.NewIDWithName(metadata.Type, "invalid"),
+			expectedErr: multierr.Combine(
+				errMissingEndpoint,
+				errInvalidMaxTemplates,
+				errInvalidTemplateTTL,
+				errInvalidAggregationInterval,
+				errors.New(`duplicate aggregation attribute "source.address"`),
+				errors.New("aggregation.attributes must not contain empty attributes"),
+			),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id.String(), func(t *testing.T) {
+			cfg := createDefaultConfig()
+			sub, err := cm.Sub(tt.id.String())
+			require.NoError(t, err)
+			require.NoError(t, sub.Unmarshal(cfg))
+
+			if tt.expectedErr != nil {
+				assert.EqualError(t, component.ValidateConfig(cfg), tt.expectedErr.Error())
+				return
+			}
+			assert.NoError(t, component.ValidateConfig(cfg))
+			assert.Equal(t, tt.expected, cfg)
+		})
+	}
+}
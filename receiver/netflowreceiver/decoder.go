@@ -0,0 +1,467 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package netflowreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/netflowreceiver"
+
+import (
+	"container/list"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	versionNetFlowV5 = 5
+	versionNetFlowV9 = 9
+	versionIPFIX     = 10
+
+	netFlowV5HeaderLength = 24
+	netFlowV5RecordLength = 48
+	netFlowV9HeaderLength = 20
+	ipfixHeaderLength     = 16
+	setHeaderLength       = 4
+
+	// The IDs of the sets (flowsets in NetFlow v9) describing templates,
+	// the sets with a greater ID contain the data records of a template.
+	netFlowV9TemplateSetID        = 0
+	netFlowV9OptionsTemplateSetID = 1
+	ipfixTemplateSetID            = 2
+	ipfixOptionsTemplateSetID     = 3
+	minDataSetID                  = 256
+
+	// The length of IPFIX fields whose length is given in the data records.
+	variableLength = 0xffff
+	// The bit of IPFIX field IDs set for enterprise-specific fields.
+	enterpriseBit = 0x8000
+)
+
+var errTruncated = errors.New("truncated packet")
+
+// field is a field of a flow record.
+type field struct {
+	enterprise uint32
+	id         uint16
+	value      []byte
+}
+
+// flowRecord is a data record of a flow packet.
+type flowRecord []field
+
+// packet is a decoded NetFlow or IPFIX packet.
+type packet struct {
+	version    uint16
+	exportTime time.Time
+	// sysUptime is the uptime of the exporter in milliseconds when the
+	// packet was exported, the times of the NetFlow records are relative to
+	// it. It is zero for IPFIX packets.
+	sysUptime uint32
+	// domainID is the source ID of NetFlow v9 packets and the observation
+	// domain ID of IPFIX packets.
+	domainID uint32
+	// samplingInterval is the sampling interval of NetFlow v5 packets.
+	samplingInterval uint16
+	records          []flowRecord
+	// missingTemplates are the IDs of the templates of the data sets which
+	// couldn't be decoded, as their template hasn't been received yet.
+	missingTemplates []uint16
+}
+
+// templateField is a field of a template.
+type templateField struct {
+	enterprise uint32
+	id         uint16
+	length     uint16
+}
+
+type template struct {
+	fields []templateField
+	// options templates describe records about the exporter or the
+	// metering process rather than flows, which are skipped.
+	options bool
+}
+
+// templateKey identifies a template: template IDs are only unique within an
+// observation domain of an exporter.
+type templateKey struct {
+	exporter string
+	version  uint16
+	domainID uint32
+	id       uint16
+}
+
+// cachedTemplate is a template in the cache of the decoder.
+type cachedTemplate struct {
+	key      templateKey
+	template *template
+	received time.Time
+}
+
+// decoder decodes NetFlow v5, NetFlow v9 and IPFIX packets, caching the
+// templates of NetFlow v9 and IPFIX exporters to decode their data records.
+// At most maxTemplates templates are cached, the least recently received
+// ones being evicted first, and templates not received again within the
+// template TTL expire. It is not safe for concurrent use.
+type decoder struct {
+	templates map[templateKey]*list.Element
+	// received holds the cached templates by the time they were last
+	// received, oldest first.
+	received     *list.List
+	maxTemplates int
+	templateTTL  time.Duration
+	now          func() time.Time
+}
+
+func newDecoder(maxTemplates int, templateTTL time.Duration) *decoder {
+	return &decoder{
+		templates:    map[templateKey]*list.Element{},
+		received:     list.New(),
+		maxTemplates: maxTemplates,
+		templateTTL:  templateTTL,
+		now:          time.Now,
+	}
+}
+
+// putTemplate caches a received template, after dropping the expired
+// templates and, if the cache is full, the least recently received one.
+func (d *decoder) putTemplate(key templateKey, tmpl *template) {
+	now := d.now()
+	if e, ok := d.templates[key]; ok {
+		cached := e.Value.(*cachedTemplate)
+		cached.template = tmpl
+		cached.received = now
+		d.received.MoveToBack(e)
+		return
+	}
+
+	for e := d.received.Front(); e != nil; e = d.received.Front() {
+		if d.received.Len() < d.maxTemplates && now.Sub(e.Value.(*cachedTemplate).received) <= d.templateTTL {
+			break
+		}
+		d.removeTemplate(e)
+	}
+	d.templates[key] = d.received.PushBack(&cachedTemplate{key: key, template: tmpl, received: now})
+}
+
+// getTemplate returns the cached template with the key, unless it expired.
+func (d *decoder) getTemplate(key templateKey) (*template, bool) {
+	e, ok := d.templates[key]
+	if !ok {
+		return nil, false
+	}
+	cached := e.Value.(*cachedTemplate)
+	if d.now().Sub(cached.received) > d.templateTTL {
+		d.removeTemplate(e)
+		return nil, false
+	}
+	return cached.template, true
+}
+
+func (d *decoder) deleteTemplate(key templateKey) {
+	if e, ok := d.templates[key]; ok {
+		d.removeTemplate(e)
+	}
+}
+
+func (d *decoder) removeTemplate(e *list.Element) {
+	delete(d.templates, e.Value.(*cachedTemplate).key)
+	d.received.Remove(e)
+}
+
+// decode decodes a packet sent by the exporter. The fields of the decoded
+// records refer to data, which must not be modified while they are used.
+func (d *decoder) decode(exporter string, data []byte) (*packet, error) {
+	if len(data) < 2 {
+		return nil, errTruncated
+	}
+	switch version := binary.BigEndian.Uint16(data); version {
+	case versionNetFlowV5:
+		return decodeNetFlowV5(data)
+	case versionNetFlowV9:
+		return d.decodeNetFlowV9(exporter, data)
+	case versionIPFIX:
+		return d.decodeIPFIX(exporter, data)
+	default:
+		return nil, fmt.Errorf("unsupported version %d", version)
+	}
+}
+
+// netFlowV5Fields describes the fixed records of NetFlow v5 with the
+// equivalent IPFIX information elements, so that all versions are converted
+// alike. The padding fields are paddingOctets.
+var netFlowV5Fields = []templateField{
+	{id: 8, length: 4},   // srcaddr
+	{id: 12, length: 4},  // dstaddr
+	{id: 15, length: 4},  // nexthop
+	{id: 10, length: 2},  // input
+	{id: 14, length: 2},  // output
+	{id: 2, length: 4},   // dPkts
+	{id: 1, length: 4},   // dOctets
+	{id: 22, length: 4},  // first
+	{id: 21, length: 4},  // last
+	{id: 7, length: 2},   // srcport
+	{id: 11, length: 2},  // dstport
+	{id: 210, length: 1}, // pad1
+	{id: 6, length: 1},   // tcp_flags
+	{id: 4, length: 1},   // prot
+	{id: 5, length: 1},   // tos
+	{id: 16, length: 2},  // src_as
+	{id: 17, length: 2},  // dst_as
+	{id: 9, length: 1},   // src_mask
+	{id: 13, length: 1},  // dst_mask
+	{id: 210, length: 2}, // pad2
+}
+
+func decodeNetFlowV5(data []byte) (*packet, error) {
+	if len(data) < netFlowV5HeaderLength {
+		return nil, errTruncated
+	}
+	count := int(binary.BigEndian.Uint16(data[2:]))
+	if len(data) < netFlowV5HeaderLength+count*netFlowV5RecordLength {
+		return nil, errTruncated
+	}
+
+	p := &packet{
+		version:    versionNetFlowV5,
+		sysUptime:  binary.BigEndian.Uint32(data[4:]),
+		exportTime: time.Unix(int64(binary.BigEndian.Uint32(data[8:])), int64(binary.BigEndian.Uint32(data[12:]))).UTC(),
+		// The first two bits are the sampling mode.
+		samplingInterval: binary.BigEndian.Uint16(data[22:]) & 0x3fff,
+	}
+	tmpl := &template{fields: netFlowV5Fields}
+	for i := 0; i < count; i++ {
+		offset := netFlowV5HeaderLength + i*netFlowV5RecordLength
+		record, _, err := tmpl.decodeRecord(data[offset : offset+netFlowV5RecordLength])
+		if err != nil {
+			return nil, err
+		}
+		p.records = append(p.records, record)
+	}
+	return p, nil
+}
+
+func (d *decoder) decodeNetFlowV9(exporter string, data []byte) (*packet, error) {
+	if len(data) < netFlowV9HeaderLength {
+		return nil, errTruncated
+	}
+	p := &packet{
+		version:    versionNetFlowV9,
+		sysUptime:  binary.BigEndian.Uint32(data[4:]),
+		exportTime: time.Unix(int64(binary.BigEndian.Uint32(data[8:])), 0).UTC(),
+		domainID:   binary.BigEndian.Uint32(data[16:]),
+	}
+	err := d.decodeSets(p, exporter, data[netFlowV9HeaderLength:])
+	return p, err
+}
+
+func (d *decoder) decodeIPFIX(exporter string, data []byte) (*packet, error) {
+	if len(data) < ipfixHeaderLength {
+		return nil, errTruncated
+	}
+	length := int(binary.BigEndian.Uint16(data[2:]))
+	if length < ipfixHeaderLength || len(data) < length {
+		return nil, errTruncated
+	}
+	p := &packet{
+		version:    versionIPFIX,
+		exportTime: time.Unix(int64(binary.BigEndian.Uint32(data[4:])), 0).UTC(),
+		domainID:   binary.BigEndian.Uint32(data[12:]),
+	}
+	err := d.decodeSets(p, exporter, data[ipfixHeaderLength:length])
+	return p, err
+}
+
+// decodeSets decodes the sets of a NetFlow v9 or IPFIX packet, which share
+// the same layout.
+func (d *decoder) decodeSets(p *packet, exporter string, data []byte) error {
+	for len(data) > 0 {
+		// NetFlow v9 packets may be padded after their last flowset.
+		if len(data) < setHeaderLength {
+			return nil
+		}
+		id := binary.BigEndian.Uint16(data)
+		length := int(binary.BigEndian.Uint16(data[2:]))
+		if length < setHeaderLength || len(data) < length {
+			return errTruncated
+		}
+		body := data[setHeaderLength:length]
+		data = data[length:]
+
+		key := templateKey{exporter: exporter, version: p.version, domainID: p.domainID}
+		var err error
+		switch {
+		case p.version == versionNetFlowV9 && id == netFlowV9TemplateSetID:
+			err = d.decodeTemplates(key, body, false)
+		case p.version == versionNetFlowV9 && id == netFlowV9OptionsTemplateSetID:
+			err = d.decodeNetFlowV9OptionsTemplates(key, body)
+		case p.version == versionIPFIX && id == ipfixTemplateSetID:
+			err = d.decodeTemplates(key, body, false)
+		case p.version == versionIPFIX && id == ipfixOptionsTemplateSetID:
+			err = d.decodeTemplates(key, body, true)
+		case id >= minDataSetID:
+			key.id = id
+			err = d.decodeDataSet(p, key, body)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decodeTemplates decodes the template records of a template set, or of an
+// IPFIX options template set.
+func (d *decoder) decodeTemplates(key templateKey, data []byte, options bool) error {
+	headerLength := 4
+	if options {
+		// IPFIX options templates also have the count of their scope fields,
+		// which are not told apart from the other fields.
+		headerLength = 6
+	}
+	for len(data) >= headerLength {
+		key.id = binary.BigEndian.Uint16(data)
+		count := int(binary.BigEndian.Uint16(data[2:]))
+		data = data[headerLength:]
+
+		// IPFIX exporters withdraw templates with a template record without
+		// fields.
+		if count == 0 {
+			if key.version == versionIPFIX {
+				d.deleteTemplate(key)
+				continue
+			}
+			// NetFlow v9 template sets may be padded.
+			return nil
+		}
+
+		tmpl := &template{options: options}
+		for i := 0; i < count; i++ {
+			if len(data) < 4 {
+				return errTruncated
+			}
+			f := templateField{
+				id:     binary.BigEndian.Uint16(data),
+				length: binary.BigEndian.Uint16(data[2:]),
+			}
+			data = data[4:]
+			if key.version == versionIPFIX && f.id&enterpriseBit != 0 {
+				if len(data) < 4 {
+					return errTruncated
+				}
+				f.id &^= enterpriseBit
+				f.enterprise = binary.BigEndian.Uint32(data)
+				data = data[4:]
+			}
+			tmpl.fields = append(tmpl.fields, f)
+		}
+		if key.id < minDataSetID {
+			return fmt.Errorf("invalid template ID %d", key.id)
+		}
+		d.putTemplate(key, tmpl)
+	}
+	return nil
+}
+
+// decodeNetFlowV9OptionsTemplates decodes the templates of a NetFlow v9
+// options template flowset, whose layout differs from the IPFIX one.
+func (d *decoder) decodeNetFlowV9OptionsTemplates(key templateKey, data []byte) error {
+	for len(data) >= 6 {
+		key.id = binary.BigEndian.Uint16(data)
+		// The lengths of the scope and option fields are in bytes.
+		length := int(binary.BigEndian.Uint16(data[2:])) + int(binary.BigEndian.Uint16(data[4:]))
+		data = data[6:]
+		if length == 0 {
+			// Padding.
+			return nil
+		}
+		if length%4 != 0 || len(data) < length {
+			return errTruncated
+		}
+
+		tmpl := &template{options: true}
+		for i := 0; i < length; i += 4 {
+			tmpl.fields = append(tmpl.fields, templateField{
+				id:     binary.BigEndian.Uint16(data[i:]),
+				length: binary.BigEndian.Uint16(data[i+2:]),
+			})
+		}
+		data = data[length:]
+		if key.id < minDataSetID {
+			return fmt.Errorf("invalid template ID %d", key.id)
+		}
+		d.putTemplate(key, tmpl)
+	}
+	return nil
+}
+
+func (d *decoder) decodeDataSet(p *packet, key templateKey, data []byte) error {
+	tmpl, ok := d.getTemplate(key)
+	if !ok {
+		p.missingTemplates = append(p.missingTemplates, key.id)
+		return nil
+	}
+	if tmpl.options {
+		return nil
+	}
+
+	minLength := tmpl.minRecordLength()
+	if minLength == 0 {
+		return nil
+	}
+	// The set ends with padding when less than a record remains.
+	for len(data) >= minLength {
+		record, n, err := tmpl.decodeRecord(data)
+		if err != nil {
+			return err
+		}
+		p.records = append(p.records, record)
+		data = data[n:]
+	}
+	return nil
+}
+
+// minRecordLength returns the length of the shortest record of the template,
+// whose variable length fields are empty.
+func (t *template) minRecordLength() int {
+	length := 0
+	for _, f := range t.fields {
+		if f.length == variableLength {
+			length++
+		} else {
+			length += int(f.length)
+		}
+	}
+	return length
+}
+
+// decodeRecord decodes a data record of the template, returning the number of
+// bytes it spans.
+func (t *template) decodeRecord(data []byte) (flowRecord, int, error) {
+	record := make(flowRecord, 0, len(t.fields))
+	offset := 0
+	for _, f := range t.fields {
+		length := int(f.length)
+		if f.length == variableLength {
+			// The length of variable length fields is encoded on one byte,
+			// or on the two bytes following 255 for longer fields.
+			if offset >= len(data) {
+				return nil, 0, errTruncated
+			}
+			length = int(data[offset])
+			offset++
+			if length == 255 {
+				if offset+2 > len(data) {
+					return nil, 0, errTruncated
+				}
+				length = int(binary.BigEndian.Uint16(data[offset:]))
+				offset += 2
+			}
+		}
+		if offset+length > len(data) {
+			return nil, 0, errTruncated
+		}
+		record = append(record, field{enterprise: f.enterprise, id: f.id, value: data[offset : offset+length]})
+		offset += length
+	}
+	return record, offset, nil
+}
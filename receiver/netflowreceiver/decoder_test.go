@@ -0,0 +1,224 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package netflowreceiver
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readPacket(t *testing.T, name string) []byte {
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	require.NoError(t, err)
+	return data
+}
+
+func TestDecodeNetFlowV5(t *testing.T) {
+	p, err := newDecoder(defaultMaxTemplates, defaultTemplateTTL).decode("10.0.0.254", readPacket(t, "netflow_v5.bin"))
+	require.NoError(t, err)
+
+	assert.Equal(t, uint16(versionNetFlowV5), p.version)
+	assert.Equal(t, time.Date(2024, 4, 22, 10, 0, 0, 500000000, time.UTC), p.exportTime)
+	assert.Equal(t, uint32(3600000), p.sysUptime)
+	assert.Equal(t, uint16(100), p.samplingInterval)
+	require.Len(t, p.records, 2)
+	require.Len(t, p.records[0], len(netFlowV5Fields))
+	assert.Equal(t, field{id: 8, value: []byte{10, 0, 0, 1}}, p.records[0][0])
+	assert.Equal(t, field{id: 11, value: []byte{0, 53}}, p.records[1][10])
+}
+
+func TestDecodeNetFlowV9(t *testing.T) {
+	d := newDecoder(defaultMaxTemplates, defaultTemplateTTL)
+
+	// Data sets are dropped until their template is received.
+	p, err := d.decode("192.0.2.1", readPacket(t, "netflow_v9_data.bin"))
+	require.NoError(t, err)
+	assert.Empty(t, p.records)
+	assert.Equal(t, []uint16{256, 257, 258, 300}, p.missingTemplates)
+
+	p, err = d.decode("192.0.2.1", readPacket(t, "netflow_v9_templates.bin"))
+	require.NoError(t, err)
+	assert.Empty(t, p.records)
+	assert.Empty(t, p.missingTemplates)
+	assert.Len(t, d.templates, 3)
+
+	p, err = d.decode("192.0.2.1", readPacket(t, "netflow_v9_data.bin"))
+	require.NoError(t, err)
+	assert.Equal(t, uint16(versionNetFlowV9), p.version)
+	assert.Equal(t, uint32(1), p.domainID)
+	assert.Equal(t, uint32(7200000), p.sysUptime)
+	// The options data set is skipped, and the padding of the sets ignored.
+	require.Len(t, p.records, 3)
+	assert.Len(t, p.records[0], 13)
+	assert.Len(t, p.records[1], 13)
+	assert.Len(t, p.records[2], 9)
+	assert.Equal(t, []uint16{300}, p.missingTemplates)
+
+	// Templates are cached by exporter.
+	p, err = d.decode("192.0.2.2", readPacket(t, "netflow_v9_data.bin"))
+	require.NoError(t, err)
+	assert.Empty(t, p.records)
+	assert.Equal(t, []uint16{256, 257, 258, 300}, p.missingTemplates)
+}
+
+func TestDecodeIPFIX(t *testing.T) {
+	d := newDecoder(defaultMaxTemplates, defaultTemplateTTL)
+	p, err := d.decode("192.0.2.1", readPacket(t, "ipfix.bin"))
+	require.NoError(t, err)
+
+	assert.Equal(t, uint16(versionIPFIX), p.version)
+	assert.Equal(t, uint32(7), p.domainID)
+	assert.Equal(t, time.Date(2024, 4, 22, 10, 0, 0, 0, time.UTC), p.exportTime)
+	require.Len(t, p.records, 2)
+	require.Len(t, p.records[0], 12)
+	assert.Equal(t, field{enterprise: 29305, id: 1, value: []byte{0, 0, 0, 7}}, p.records[0][10])
+	// Variable length fields, with a length on one or three bytes.
+	assert.Equal(t, field{id: 82, value: []byte("eth0")}, p.records[0][11])
+	assert.Equal(t, field{id: 82, value: []byte("eth1")}, p.records[1][11])
+
+	// Templates are withdrawn with template records without fields.
+	withdrawal := ipfixMessage(setOf(ipfixTemplateSetID, []byte{0x01, 0x90, 0, 0}))
+	_, err = d.decode("192.0.2.1", withdrawal)
+	require.NoError(t, err)
+	assert.NotContains(t, d.templates, templateKey{exporter: "192.0.2.1", version: versionIPFIX, domainID: 7, id: 400})
+	assert.Contains(t, d.templates, templateKey{exporter: "192.0.2.1", version: versionIPFIX, domainID: 7, id: 401})
+}
+
+func TestTemplateCache(t *testing.T) {
+	now := time.Date(2024, 4, 22, 10, 0, 0, 0, time.UTC)
+	d := newDecoder(2, time.Minute)
+	d.now = func() time.Time { return now }
+
+	template := func(id uint16) []byte {
+		// A template with a single 4 bytes field.
+		return ipfixMessage(setOf(ipfixTemplateSetID, []byte{byte(id >> 8), byte(id), 0, 1, 0, 8, 0, 4}))
+	}
+	data := func(id uint16) []byte {
+		return ipfixMessage(setOf(id, []byte{192, 0, 2, 1}))
+	}
+	key := func(id uint16) templateKey {
+		return templateKey{exporter: "192.0.2.1", version: versionIPFIX, domainID: 7, id: id}
+	}
+	decode := func(b []byte) *packet {
+		p, err := d.decode("192.0.2.1", b)
+		require.NoError(t, err)
+		return p
+	}
+
+	decode(template(256))
+	now = now.Add(time.Second)
+	decode(template(257))
+	now = now.Add(time.Second)
+	// Receiving a template again makes it the most recently received.
+	decode(template(256))
+	now = now.Add(time.Second)
+
+	// The least recently received template is evicted when the cache is full.
+	decode(template(258))
+	assert.Len(t, d.templates, 2)
+	assert.NotContains(t, d.templates, key(257))
+	assert.Len(t, decode(data(256)).records, 1)
+	assert.Equal(t, []uint16{257}, decode(data(257)).missingTemplates)
+
+	// Templates expire when they are not received again within the TTL.
+	now = now.Add(time.Minute)
+	assert.Equal(t, []uint16{256}, decode(data(256)).missingTemplates)
+	assert.NotContains(t, d.templates, key(256))
+	assert.Len(t, decode(data(258)).records, 1)
+
+	// The expired templates are dropped before the least recently received.
+	now = now.Add(2 * time.Second)
+	decode(template(259))
+	decode(template(260))
+	assert.Len(t, d.templates, 2)
+	assert.Contains(t, d.templates, key(259))
+	assert.Contains(t, d.templates, key(260))
+	assert.Equal(t, 2, d.received.Len())
+}
+
+func TestDecodeErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		err  string
+	}{
+		{
+			name: "empty",
+			data: nil,
+			err:  "truncated packet",
+		},
+		{
+			name: "unsupported version",
+			data: []byte{0, 1, 0, 0},
+			err:  "unsupported version 1",
+		},
+		{
+			name: "truncated NetFlow v5 records",
+			data: readPacket(t, "netflow_v5.bin")[:100],
+			err:  "truncated packet",
+		},
+		{
+			name: "truncated NetFlow v9 header",
+			data: readPacket(t, "netflow_v9_data.bin")[:10],
+			err:  "truncated packet",
+		},
+		{
+			name: "truncated set",
+			data: ipfixMessage(setOf(ipfixTemplateSetID, []byte{0x01, 0x90, 0, 1})[:6]),
+			err:  "truncated packet",
+		},
+		{
+			name: "truncated template",
+			data: ipfixMessage(setOf(ipfixTemplateSetID, []byte{0x01, 0x90, 0, 2, 0, 8, 0, 4})),
+			err:  "truncated packet",
+		},
+		{
+			name: "invalid template ID",
+			data: ipfixMessage(setOf(ipfixTemplateSetID, []byte{0, 10, 0, 1, 0, 8, 0, 4})),
+			err:  "invalid template ID 10",
+		},
+		{
+			name: "truncated variable length field",
+			data: ipfixMessage(
+				setOf(ipfixTemplateSetID, []byte{0x01, 0x90, 0, 1, 0, 82, 0xff, 0xff}),
+				setOf(400, []byte{255, 0}),
+			),
+			err: "truncated packet",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newDecoder(defaultMaxTemplates, defaultTemplateTTL).decode("192.0.2.1", tt.data)
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
+
+func setOf(id uint16, body []byte) []byte {
+	var buf bytes.Buffer
+	_ = binary.Write(&buf, binary.BigEndian, id)
+	_ = binary.Write(&buf, binary.BigEndian, uint16(setHeaderLength+len(body)))
+	buf.Write(body)
+	return buf.Bytes()
+}
+
+func ipfixMessage(sets ...[]byte) []byte {
+	body := bytes.Join(sets, nil)
+	var buf bytes.Buffer
+	_ = binary.Write(&buf, binary.BigEndian, uint16(versionIPFIX))
+	_ = binary.Write(&buf, binary.BigEndian, uint16(ipfixHeaderLength+len(body)))
+	_ = binary.Write(&buf, binary.BigEndian, uint32(1713780000))
+	_ = binary.Write(&buf, binary.BigEndian, uint32(1))
+	_ = binary.Write(&buf, binary.BigEndian, uint32(7))
+	buf.Write(body)
+	return buf.Bytes()
+}
@@ -0,0 +1,92 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package netflowreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/netflowreceiver"
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/receiver"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/sharedcomponent"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/netflowreceiver/internal/metadata"
+)
+
+const (
+	// The port commonly used by NetFlow exporters.
+	defaultEndpoint            = "localhost:2055"
+	defaultMaxTemplates        = 10000
+	defaultTemplateTTL         = 30 * time.Minute
+	defaultAggregationInterval = 60 * time.Second
+)
+
+var defaultAggregationAttributes = []string{"source.address", "destination.address", "network.transport"}
+
+// receivers holds the receivers by configuration, so that the logs and
+// metrics pipelines share the same listener.
+var receivers = sharedcomponent.NewSharedComponents()
+
+// NewFactory creates a factory for the NetFlow receiver.
+func NewFactory() receiver.Factory {
+	return receiver.NewFactory(
+		metadata.Type,
+		createDefaultConfig,
+		receiver.WithLogs(createLogsReceiver, metadata.LogsStability),
+		receiver.WithMetrics(createMetricsReceiver, metadata.MetricsStability),
+	)
+}
+
+func createDefaultConfig() component.Config {
+	return &Config{
+		Endpoint:     defaultEndpoint,
+		MaxTemplates: defaultMaxTemplates,
+		TemplateTTL:  defaultTemplateTTL,
+		Aggregation: AggregationConfig{
+			Interval:   defaultAggregationInterval,
+			Attributes: defaultAggregationAttributes,
+		},
+	}
+}
+
+func createLogsReceiver(
+	_ context.Context,
+	params receiver.CreateSettings,
+	cfg component.Config,
+	consumer consumer.Logs,
+) (receiver.Logs, error) {
+	c := cfg.(*Config)
+	var err error
+	r := receivers.GetOrAdd(c, func() component.Component {
+		var rcv *netflowReceiver
+		rcv, err = newNetflowReceiver(params, c)
+		return rcv
+	})
+	if err != nil {
+		return nil, err
+	}
+	r.Unwrap().(*netflowReceiver).nextLogs = consumer
+	return r, nil
+}
+
+func createMetricsReceiver(
+	_ context.Context,
+	params receiver.CreateSettings,
+	cfg component.Config,
+	consumer consumer.Metrics,
+) (receiver.Metrics, error) {
+	c := cfg.(*Config)
+	var err error
+	r := receivers.GetOrAdd(c, func() component.Component {
+		var rcv *netflowReceiver
+		rcv, err = newNetflowReceiver(params, c)
+		return rcv
+	})
+	if err != nil {
+		return nil, err
+	}
+	r.Unwrap().(*netflowReceiver).nextMetrics = consumer
+	return r, nil
+}
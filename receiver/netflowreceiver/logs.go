@@ -0,0 +1,262 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package netflowreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/netflowreceiver"
+
+import (
+	"encoding/binary"
+	"net"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+const (
+	attributeExporterAddress  = "netflow.exporter.address"
+	attributeVersion          = "netflow.version"
+	attributeDomainID         = "netflow.observation_domain_id"
+	attributeSamplingInterval = "netflow.sampling_interval"
+
+	attributeSourceAddress      = "source.address"
+	attributeDestinationAddress = "destination.address"
+	attributeNetworkTransport   = "network.transport"
+	attributeNetworkType        = "network.type"
+	attributeBytes              = "netflow.bytes"
+	attributePackets            = "netflow.packets"
+	attributeStartTime          = "netflow.start_time"
+)
+
+// The IPFIX information elements which need more than a conversion of their
+// value to an attribute.
+const (
+	ieProtocolIdentifier         = 4
+	ieSourceIPv4Address          = 8
+	ieDestinationIPv4Address     = 12
+	ieFlowEndSysUpTime           = 21
+	ieFlowStartSysUpTime         = 22
+	ieSourceIPv6Address          = 27
+	ieDestinationIPv6Address     = 28
+	ieIPVersion                  = 60
+	ieFlowStartSeconds           = 150
+	ieFlowEndSeconds             = 151
+	ieFlowStartMilliseconds      = 152
+	ieFlowEndMilliseconds        = 153
+	ieSystemInitTimeMilliseconds = 160
+	iePaddingOctets              = 210
+)
+
+type fieldKind int
+
+const (
+	kindUnsigned fieldKind = iota
+	kindAddress
+	kindMAC
+	kindString
+)
+
+type fieldSpec struct {
+	attribute string
+	kind      fieldKind
+}
+
+// fieldSpecs are the attributes of the IPFIX information elements, which
+// are the NetFlow v9 field types as well. Semantic conventions attributes are
+// used when they exist.
+var fieldSpecs = map[uint16]fieldSpec{
+	1:                        {attribute: attributeBytes},
+	2:                        {attribute: attributePackets},
+	5:                        {attribute: "netflow.tos"},
+	6:                        {attribute: "netflow.tcp_flags"},
+	7:                        {attribute: "source.port"},
+	ieSourceIPv4Address:      {attribute: attributeSourceAddress, kind: kindAddress},
+	9:                        {attribute: "netflow.source_mask"},
+	10:                       {attribute: "netflow.input_interface"},
+	11:                       {attribute: "destination.port"},
+	ieDestinationIPv4Address: {attribute: attributeDestinationAddress, kind: kindAddress},
+	13:                       {attribute: "netflow.destination_mask"},
+	14:                       {attribute: "netflow.output_interface"},
+	15:                       {attribute: "netflow.next_hop", kind: kindAddress},
+	16:                       {attribute: "netflow.source_as"},
+	17:                       {attribute: "netflow.destination_as"},
+	ieSourceIPv6Address:      {attribute: attributeSourceAddress, kind: kindAddress},
+	ieDestinationIPv6Address: {attribute: attributeDestinationAddress, kind: kindAddress},
+	29:                       {attribute: "netflow.source_mask"},
+	30:                       {attribute: "netflow.destination_mask"},
+	32:                       {attribute: "netflow.icmp_type_code"},
+	56:                       {attribute: "netflow.source_mac", kind: kindMAC},
+	58:                       {attribute: "netflow.vlan_id"},
+	61:                       {attribute: "netflow.direction"},
+	62:                       {attribute: "netflow.next_hop", kind: kindAddress},
+	80:                       {attribute: "netflow.destination_mac", kind: kindMAC},
+	82:                       {attribute: "netflow.interface_name", kind: kindString},
+	83:                       {attribute: "netflow.interface_description", kind: kindString},
+	85:                       {attribute: "netflow.total_bytes"},
+	86:                       {attribute: "netflow.total_packets"},
+	96:                       {attribute: "netflow.application_name", kind: kindString},
+	136:                      {attribute: "netflow.end_reason"},
+	139:                      {attribute: "netflow.icmp_type_code"},
+}
+
+// transports are the names of the IP protocols, as registered by IANA.
+var transports = map[uint64]string{
+	1:   "icmp",
+	2:   "igmp",
+	6:   "tcp",
+	17:  "udp",
+	47:  "gre",
+	50:  "esp",
+	51:  "ah",
+	58:  "ipv6-icmp",
+	132: "sctp",
+}
+
+// newLogs converts the records of a packet to log records, under a resource
+// describing the exporter.
+func newLogs(p *packet, exporter string, now time.Time) plog.Logs {
+	logs := plog.NewLogs()
+	rl := logs.ResourceLogs().AppendEmpty()
+	resource := rl.Resource().Attributes()
+	resource.PutStr(attributeExporterAddress, exporter)
+	resource.PutInt(attributeVersion, int64(p.version))
+	if p.version != versionNetFlowV5 {
+		resource.PutInt(attributeDomainID, int64(p.domainID))
+	}
+	if p.samplingInterval != 0 {
+		resource.PutInt(attributeSamplingInterval, int64(p.samplingInterval))
+	}
+
+	sl := rl.ScopeLogs().AppendEmpty()
+	sl.Scope().SetName(scopeName)
+	records := sl.LogRecords()
+	records.EnsureCapacity(len(p.records))
+	observed := pcommon.NewTimestampFromTime(now)
+	for _, r := range p.records {
+		record := records.AppendEmpty()
+		record.SetObservedTimestamp(observed)
+		convertRecord(p, r, record)
+	}
+	return logs
+}
+
+func convertRecord(p *packet, r flowRecord, record plog.LogRecord) {
+	attrs := record.Attributes()
+	attrs.EnsureCapacity(len(r))
+
+	var start, end time.Time
+	var startUptime, endUptime, initTime uint64
+	var hasStartUptime, hasEndUptime, hasInitTime bool
+	for _, f := range r {
+		if f.enterprise != 0 {
+			attrs.PutEmptyBytes("netflow.field." + strconv.FormatUint(uint64(f.enterprise), 10) + "." + strconv.Itoa(int(f.id))).FromRaw(f.value)
+			continue
+		}
+
+		switch f.id {
+		case iePaddingOctets:
+		case ieProtocolIdentifier:
+			protocol := unsigned(f.value)
+			if name, ok := transports[protocol]; ok {
+				attrs.PutStr(attributeNetworkTransport, name)
+			} else {
+				attrs.PutStr(attributeNetworkTransport, strconv.FormatUint(protocol, 10))
+			}
+		case ieIPVersion:
+			attrs.PutStr(attributeNetworkType, "ipv"+strconv.FormatUint(unsigned(f.value), 10))
+		case ieFlowStartSysUpTime:
+			startUptime, hasStartUptime = unsigned(f.value), true
+		case ieFlowEndSysUpTime:
+			endUptime, hasEndUptime = unsigned(f.value), true
+		case ieSystemInitTimeMilliseconds:
+			initTime, hasInitTime = unsigned(f.value), true
+		case ieFlowStartSeconds:
+			start = time.Unix(int64(unsigned(f.value)), 0)
+		case ieFlowEndSeconds:
+			end = time.Unix(int64(unsigned(f.value)), 0)
+		case ieFlowStartMilliseconds:
+			start = time.UnixMilli(int64(unsigned(f.value)))
+		case ieFlowEndMilliseconds:
+			end = time.UnixMilli(int64(unsigned(f.value)))
+		default:
+			putField(attrs, f)
+		}
+	}
+
+	// The sysUpTime fields are relative to the uptime of NetFlow exporters,
+	// and to the initialization time of IPFIX exporters.
+	uptimeToTime := func(uptime uint64) time.Time {
+		if hasInitTime {
+			return time.UnixMilli(int64(initTime + uptime))
+		}
+		return p.exportTime.Add(-time.Duration(int64(p.sysUptime)-int64(uptime)) * time.Millisecond)
+	}
+	if hasStartUptime && start.IsZero() && (p.version != versionIPFIX || hasInitTime) {
+		start = uptimeToTime(startUptime)
+	}
+	if hasEndUptime && end.IsZero() && (p.version != versionIPFIX || hasInitTime) {
+		end = uptimeToTime(endUptime)
+	}
+
+	if !start.IsZero() {
+		attrs.PutStr(attributeStartTime, start.UTC().Format(time.RFC3339Nano))
+	}
+	// Flows are reported once they end, or regularly while they are active.
+	if end.IsZero() {
+		end = p.exportTime
+	}
+	record.SetTimestamp(pcommon.NewTimestampFromTime(end))
+
+	if _, ok := attrs.Get(attributeNetworkType); !ok {
+		if addr, ok := attrs.Get(attributeSourceAddress); ok {
+			if ip := net.ParseIP(addr.Str()); ip != nil {
+				if ip.To4() != nil {
+					attrs.PutStr(attributeNetworkType, "ipv4")
+				} else {
+					attrs.PutStr(attributeNetworkType, "ipv6")
+				}
+			}
+		}
+	}
+}
+
+// putField adds the field to the attributes, under the attribute of its
+// information element if it is known.
+func putField(attrs pcommon.Map, f field) {
+	spec, ok := fieldSpecs[f.id]
+	if !ok {
+		// Unknown fields are kept as integers when they have the length of
+		// one.
+		name := "netflow.field." + strconv.Itoa(int(f.id))
+		if l := len(f.value); l == 1 || l == 2 || l == 4 || l == 8 {
+			attrs.PutInt(name, int64(unsigned(f.value)))
+		} else {
+			attrs.PutEmptyBytes(name).FromRaw(f.value)
+		}
+		return
+	}
+
+	switch {
+	case spec.kind == kindAddress && (len(f.value) == net.IPv4len || len(f.value) == net.IPv6len):
+		attrs.PutStr(spec.attribute, net.IP(f.value).String())
+	case spec.kind == kindMAC && len(f.value) == 6:
+		attrs.PutStr(spec.attribute, net.HardwareAddr(f.value).String())
+	case spec.kind == kindString:
+		attrs.PutStr(spec.attribute, string(f.value))
+	case spec.kind == kindUnsigned && len(f.value) <= 8:
+		attrs.PutInt(spec.attribute, int64(unsigned(f.value)))
+	default:
+		attrs.PutEmptyBytes(spec.attribute).FromRaw(f.value)
+	}
+}
+
+// unsigned decodes an unsigned integer, which IPFIX exporters may encode on
+// less bytes than its type.
+func unsigned(value []byte) uint64 {
+	if len(value) > 8 {
+		value = value[len(value)-8:]
+	}
+	var buf [8]byte
+	copy(buf[8-len(value):], value)
+	return binary.BigEndian.Uint64(buf[:])
+}
@@ -0,0 +1,101 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package netflowreceiver
+
+import (
+	"encoding/binary"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/plog"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/golden"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/pdatatest/plogtest"
+)
+
+func TestNewLogs(t *testing.T) {
+	tests := []struct {
+		name     string
+		packets  []string
+		expected string
+	}{
+		{
+			name:     "NetFlow v5",
+			packets:  []string{"netflow_v5.bin"},
+			expected: "netflow_v5_logs.yaml",
+		},
+		{
+			name:     "NetFlow v9",
+			packets:  []string{"netflow_v9_templates.bin", "netflow_v9_data.bin"},
+			expected: "netflow_v9_logs.yaml",
+		},
+		{
+			name:     "IPFIX",
+			packets:  []string{"ipfix.bin"},
+			expected: "ipfix_logs.yaml",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newDecoder(defaultMaxTemplates, defaultTemplateTTL)
+			var p *packet
+			for _, name := range tt.packets {
+				var err error
+				p, err = d.decode("192.0.2.1", readPacket(t, name))
+				require.NoError(t, err)
+			}
+
+			logs := newLogs(p, "192.0.2.1", time.Date(2024, 4, 22, 10, 0, 5, 0, time.UTC))
+			expectedFile := filepath.Join("testdata", tt.expected)
+			expected, err := golden.ReadLogs(expectedFile)
+			require.NoError(t, err)
+			require.NoError(t, plogtest.CompareLogs(expected, logs))
+		})
+	}
+}
+
+func TestConvertRecord(t *testing.T) {
+	p := &packet{
+		version:    versionIPFIX,
+		exportTime: time.Date(2024, 4, 22, 10, 0, 0, 0, time.UTC),
+	}
+	initTime := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	record := plog.NewLogRecord()
+	convertRecord(p, flowRecord{
+		{id: ieSystemInitTimeMilliseconds, value: binary.BigEndian.AppendUint64(nil, uint64(initTime.UnixMilli()))},
+		{id: ieFlowStartSysUpTime, value: binary.BigEndian.AppendUint32(nil, 1000)},
+		{id: ieFlowEndSysUpTime, value: binary.BigEndian.AppendUint32(nil, 3000)},
+		{id: ieProtocolIdentifier, value: []byte{103}},
+		// Reduced-size encoding of octetDeltaCount.
+		{id: 1, value: []byte{1, 0}},
+		// Addresses of unexpected length are kept as is.
+		{id: ieSourceIPv4Address, value: []byte{1, 2, 3}},
+		{id: 2000, value: []byte{1, 2, 3}},
+		{id: 2001, value: []byte{0, 0, 0, 42}},
+	}, record)
+
+	assert.Equal(t, initTime.Add(3*time.Second), record.Timestamp().AsTime())
+	assert.Equal(t, map[string]any{
+		"netflow.start_time": "2024-04-01T00:00:01Z",
+		"network.transport":  "103",
+		"netflow.bytes":      int64(256),
+		"source.address":     []byte{1, 2, 3},
+		"netflow.field.2000": []byte{1, 2, 3},
+		"netflow.field.2001": int64(42),
+	}, record.Attributes().AsRaw())
+
+	// The sysUpTime fields of IPFIX records without the initialization time
+	// of the exporter are ignored.
+	record = plog.NewLogRecord()
+	convertRecord(p, flowRecord{
+		{id: ieFlowEndSysUpTime, value: binary.BigEndian.AppendUint32(nil, 3000)},
+	}, record)
+	assert.Equal(t, p.exportTime, record.Timestamp().AsTime())
+	assert.Equal(t, 0, record.Attributes().Len())
+}
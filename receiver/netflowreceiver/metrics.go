@@ -0,0 +1,152 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package netflowreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/netflowreceiver"
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+const (
+	metricFlows   = "netflow.flows"
+	metricBytes   = "netflow.bytes"
+	metricPackets = "netflow.packets"
+)
+
+// flowCounts are the totals of the flows with the same aggregation
+// attributes.
+type flowCounts struct {
+	attributes pcommon.Map
+	flows      int64
+	bytes      int64
+	packets    int64
+}
+
+// aggregator aggregates the flows of each exporter by the values of the
+// configured attributes, into delta sums reported at every flush.
+type aggregator struct {
+	attributes []string
+
+	mu    sync.Mutex
+	start time.Time
+	// exporters holds the counts of each exporter by the key of their
+	// attributes.
+	exporters map[string]map[string]*flowCounts
+}
+
+func newAggregator(attributes []string, now time.Time) *aggregator {
+	return &aggregator{
+		attributes: attributes,
+		start:      now,
+		exporters:  map[string]map[string]*flowCounts{},
+	}
+}
+
+// add adds the flows converted to logs by newLogs to the counts.
+func (a *aggregator) add(logs plog.Logs) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for i := 0; i < logs.ResourceLogs().Len(); i++ {
+		rl := logs.ResourceLogs().At(i)
+		exporter, _ := rl.Resource().Attributes().Get(attributeExporterAddress)
+		counts, ok := a.exporters[exporter.Str()]
+		if !ok {
+			counts = map[string]*flowCounts{}
+			a.exporters[exporter.Str()] = counts
+		}
+
+		for j := 0; j < rl.ScopeLogs().Len(); j++ {
+			records := rl.ScopeLogs().At(j).LogRecords()
+			for k := 0; k < records.Len(); k++ {
+				a.addRecord(counts, records.At(k).Attributes())
+			}
+		}
+	}
+}
+
+func (a *aggregator) addRecord(counts map[string]*flowCounts, attrs pcommon.Map) {
+	var key strings.Builder
+	for _, name := range a.attributes {
+		// Missing attributes are told apart from empty ones.
+		if v, ok := attrs.Get(name); ok {
+			key.WriteByte('=')
+			key.WriteString(v.AsString())
+		}
+		key.WriteByte(0)
+	}
+
+	c, ok := counts[key.String()]
+	if !ok {
+		c = &flowCounts{attributes: pcommon.NewMap()}
+		for _, name := range a.attributes {
+			if v, ok := attrs.Get(name); ok {
+				v.CopyTo(c.attributes.PutEmpty(name))
+			}
+		}
+		counts[key.String()] = c
+	}
+
+	c.flows++
+	if v, ok := attrs.Get(attributeBytes); ok {
+		c.bytes += v.Int()
+	}
+	if v, ok := attrs.Get(attributePackets); ok {
+		c.packets += v.Int()
+	}
+}
+
+// flush returns the metrics of the flows added since the previous flush, and
+// resets the counts.
+func (a *aggregator) flush(now time.Time) pmetric.Metrics {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	metrics := pmetric.NewMetrics()
+	start := pcommon.NewTimestampFromTime(a.start)
+	end := pcommon.NewTimestampFromTime(now)
+	for exporter, counts := range a.exporters {
+		rm := metrics.ResourceMetrics().AppendEmpty()
+		rm.Resource().Attributes().PutStr(attributeExporterAddress, exporter)
+		sm := rm.ScopeMetrics().AppendEmpty()
+		sm.Scope().SetName(scopeName)
+
+		flows := newDeltaSum(sm.Metrics(), metricFlows, "The number of flows reported by the exporter.", "{flows}")
+		bytes := newDeltaSum(sm.Metrics(), metricBytes, "The number of bytes of the flows reported by the exporter.", "By")
+		packets := newDeltaSum(sm.Metrics(), metricPackets, "The number of packets of the flows reported by the exporter.", "{packets}")
+		for _, c := range counts {
+			addDataPoint(flows, c.attributes, start, end, c.flows)
+			addDataPoint(bytes, c.attributes, start, end, c.bytes)
+			addDataPoint(packets, c.attributes, start, end, c.packets)
+		}
+	}
+
+	a.start = now
+	a.exporters = map[string]map[string]*flowCounts{}
+	return metrics
+}
+
+func newDeltaSum(metrics pmetric.MetricSlice, name, description, unit string) pmetric.NumberDataPointSlice {
+	m := metrics.AppendEmpty()
+	m.SetName(name)
+	m.SetDescription(description)
+	m.SetUnit(unit)
+	sum := m.SetEmptySum()
+	sum.SetIsMonotonic(true)
+	sum.SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+	return sum.DataPoints()
+}
+
+func addDataPoint(dps pmetric.NumberDataPointSlice, attributes pcommon.Map, start, end pcommon.Timestamp, value int64) {
+	dp := dps.AppendEmpty()
+	attributes.CopyTo(dp.Attributes())
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(end)
+	dp.SetIntValue(value)
+}
@@ -0,0 +1,42 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package netflowreceiver
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/golden"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/pdatatest/pmetrictest"
+)
+
+func TestAggregator(t *testing.T) {
+	start := time.Date(2024, 4, 22, 10, 0, 0, 0, time.UTC)
+	a := newAggregator([]string{"network.transport", "destination.port"}, start)
+
+	d := newDecoder(defaultMaxTemplates, defaultTemplateTTL)
+	for _, exporter := range []string{"192.0.2.1", "192.0.2.2"} {
+		for _, name := range []string{"netflow_v5.bin", "netflow_v5.bin", "ipfix.bin"} {
+			p, err := d.decode(exporter, readPacket(t, name))
+			require.NoError(t, err)
+			a.add(newLogs(p, exporter, start))
+		}
+	}
+
+	metrics := a.flush(start.Add(time.Minute))
+	expectedFile := filepath.Join("testdata", "metrics.yaml")
+	expected, err := golden.ReadMetrics(expectedFile)
+	require.NoError(t, err)
+	require.NoError(t, pmetrictest.CompareMetrics(expected, metrics,
+		pmetrictest.IgnoreResourceMetricsOrder(),
+		pmetrictest.IgnoreMetricDataPointsOrder(),
+	))
+
+	// The counts are reset at every flush.
+	metrics = a.flush(start.Add(2 * time.Minute))
+	require.Equal(t, 0, metrics.ResourceMetrics().Len())
+}
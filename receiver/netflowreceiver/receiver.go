@@ -0,0 +1,183 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package netflowreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/netflowreceiver"
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/client"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/receiver"
+	"go.opentelemetry.io/collector/receiver/receiverhelper"
+	"go.uber.org/zap"
+)
+
+const (
+	scopeName = "otelcol/netflowreceiver"
+
+	// The format of the received data reported by the receiver observability.
+	dataFormat = "netflow"
+
+	// The largest UDP payload.
+	maxPacketSize = 65535
+)
+
+var (
+	_ receiver.Logs    = (*netflowReceiver)(nil)
+	_ receiver.Metrics = (*netflowReceiver)(nil)
+)
+
+// netflowReceiver receives NetFlow and IPFIX packets, converting their flows
+// to logs and aggregating them into metrics. It is shared by the logs and
+// metrics pipelines.
+type netflowReceiver struct {
+	cfg      *Config
+	settings receiver.CreateSettings
+	obsrecv  *receiverhelper.ObsReport
+
+	nextLogs    consumer.Logs
+	nextMetrics consumer.Metrics
+
+	conn       net.PacketConn
+	decoder    *decoder
+	aggregator *aggregator
+	cancel     context.CancelFunc
+	wg         sync.WaitGroup
+}
+
+// newNetflowReceiver creates the NetFlow receiver. The consumers are set by
+// the factory, as the receiver is shared by the logs and metrics pipelines.
+func newNetflowReceiver(set receiver.CreateSettings, cfg *Config) (*netflowReceiver, error) {
+	obsrecv, err := receiverhelper.NewObsReport(receiverhelper.ObsReportSettings{
+		ReceiverID:             set.ID,
+		Transport:              "udp",
+		ReceiverCreateSettings: set,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &netflowReceiver{
+		cfg:      cfg,
+		settings: set,
+		obsrecv:  obsrecv,
+		decoder:  newDecoder(cfg.MaxTemplates, cfg.TemplateTTL),
+	}, nil
+}
+
+func (r *netflowReceiver) Start(ctx context.Context, _ component.Host) error {
+	var lc net.ListenConfig
+	conn, err := lc.ListenPacket(ctx, "udp", r.cfg.Endpoint)
+	if err != nil {
+		return err
+	}
+	r.conn = conn
+
+	// The context of Start must not be used beyond Start.
+	ctx, r.cancel = context.WithCancel(context.Background())
+
+	if r.nextMetrics != nil {
+		r.aggregator = newAggregator(r.cfg.Aggregation.Attributes, time.Now())
+		r.wg.Add(1)
+		go func() {
+			defer r.wg.Done()
+			r.flushMetrics(ctx)
+		}()
+	}
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		r.readPackets(ctx)
+	}()
+	return nil
+}
+
+func (r *netflowReceiver) Shutdown(context.Context) error {
+	if r.conn == nil {
+		return nil
+	}
+	r.cancel()
+	err := r.conn.Close()
+	r.wg.Wait()
+	return err
+}
+
+func (r *netflowReceiver) readPackets(ctx context.Context) {
+	buf := make([]byte, maxPacketSize)
+	for {
+		n, addr, err := r.conn.ReadFrom(buf)
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			r.settings.Logger.Warn("Failed to read packet", zap.Error(err))
+			continue
+		}
+		r.handlePacket(ctx, addr, buf[:n])
+	}
+}
+
+// handlePacket decodes a packet and passes its flows to the consumers. The
+// packet is not used once it returns.
+func (r *netflowReceiver) handlePacket(ctx context.Context, addr net.Addr, data []byte) {
+	// Templates are cached by exporter, which may send packets from several
+	// ports.
+	exporter := addr.String()
+	if udpAddr, ok := addr.(*net.UDPAddr); ok {
+		exporter = udpAddr.IP.String()
+	}
+
+	p, err := r.decoder.decode(exporter, data)
+	if err != nil {
+		r.settings.Logger.Debug("Failed to decode packet", zap.String("exporter", exporter), zap.Error(err))
+		return
+	}
+	if len(p.missingTemplates) > 0 {
+		// Exporters send their templates regularly, the data sets received
+		// before them are lost.
+		r.settings.Logger.Debug("Dropping data sets without template",
+			zap.String("exporter", exporter), zap.Uint16s("templates", p.missingTemplates))
+	}
+	if len(p.records) == 0 {
+		return
+	}
+
+	logs := newLogs(p, exporter, time.Now())
+	if r.aggregator != nil {
+		r.aggregator.add(logs)
+	}
+	if r.nextLogs != nil {
+		ctx = client.NewContext(ctx, client.Info{Addr: addr})
+		obsCtx := r.obsrecv.StartLogsOp(ctx)
+		count := logs.LogRecordCount()
+		err = r.nextLogs.ConsumeLogs(obsCtx, logs)
+		r.obsrecv.EndLogsOp(obsCtx, dataFormat, count, err)
+	}
+}
+
+func (r *netflowReceiver) flushMetrics(ctx context.Context) {
+	ticker := time.NewTicker(r.cfg.Aggregation.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			metrics := r.aggregator.flush(time.Now())
+			if metrics.ResourceMetrics().Len() == 0 {
+				continue
+			}
+			obsCtx := r.obsrecv.StartMetricsOp(ctx)
+			count := metrics.DataPointCount()
+			err := r.nextMetrics.ConsumeMetrics(obsCtx, metrics)
+			r.obsrecv.EndMetricsOp(obsCtx, dataFormat, count, err)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package netflowreceiver
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/sharedcomponent"
+)
+
+func TestReceiver(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.Endpoint = "localhost:0"
+	cfg.Aggregation.Interval = 10 * time.Millisecond
+	set := receivertest.NewNopCreateSettings()
+
+	logsSink := new(consumertest.LogsSink)
+	metricsSink := new(consumertest.MetricsSink)
+	logs, err := factory.CreateLogsReceiver(context.Background(), set, cfg, logsSink)
+	require.NoError(t, err)
+	_, err = factory.CreateMetricsReceiver(context.Background(), set, cfg, metricsSink)
+	require.NoError(t, err)
+
+	require.NoError(t, logs.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() {
+		assert.NoError(t, logs.Shutdown(context.Background()))
+	}()
+
+	r := logs.(*sharedcomponent.SharedComponent).Unwrap().(*netflowReceiver)
+	conn, err := net.Dial("udp", r.conn.LocalAddr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	for _, name := range []string{"netflow_v9_templates.bin", "netflow_v9_data.bin", "netflow_v5.bin", "ipfix.bin"} {
+		_, err = conn.Write(readPacket(t, name))
+		require.NoError(t, err)
+	}
+
+	assert.Eventually(t, func() bool {
+		return logsSink.LogRecordCount() == 7
+	}, 5*time.Second, 10*time.Millisecond)
+	// The templates packet has no flows.
+	assert.Len(t, logsSink.AllLogs(), 3)
+	for _, l := range logsSink.AllLogs() {
+		exporter, _ := l.ResourceLogs().At(0).Resource().Attributes().Get(attributeExporterAddress)
+		assert.Equal(t, "127.0.0.1", exporter.Str())
+	}
+
+	assert.Eventually(t, func() bool {
+		flows := int64(0)
+		for _, m := range metricsSink.AllMetrics() {
+			dps := m.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Sum().DataPoints()
+			for i := 0; i < dps.Len(); i++ {
+				flows += dps.At(i).IntValue()
+			}
+		}
+		return flows == 7
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestReceiverInvalidPackets(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = "localhost:0"
+	r, err := newNetflowReceiver(receivertest.NewNopCreateSettings(), cfg)
+	require.NoError(t, err)
+	sink := new(consumertest.LogsSink)
+	r.nextLogs = sink
+
+	addr := &net.UDPAddr{IP: net.IPv4(192, 0, 2, 1), Port: 2055}
+	r.handlePacket(context.Background(), addr, []byte{0, 1})
+	r.handlePacket(context.Background(), addr, readPacket(t, "netflow_v9_data.bin"))
+	assert.Equal(t, 0, sink.LogRecordCount())
+
+	r.handlePacket(context.Background(), addr, readPacket(t, "netflow_v5.bin"))
+	assert.Equal(t, 2, sink.LogRecordCount())
+}
+
+func TestShutdownWithoutStart(t *testing.T) {
+	r, err := newNetflowReceiver(receivertest.NewNopCreateSettings(), createDefaultConfig().(*Config))
+	require.NoError(t, err)
+	require.NoError(t, r.Shutdown(context.Background()))
+}
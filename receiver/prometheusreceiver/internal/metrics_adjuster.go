@@ -54,9 +54,10 @@ import (
 type timeseriesInfo struct {
 	mark bool
 
-	number    numberInfo
-	histogram histogramInfo
-	summary   summaryInfo
+	number               numberInfo
+	histogram            histogramInfo
+	exponentialHistogram exponentialHistogramInfo
+	summary              summaryInfo
 }
 
 type numberInfo struct {
@@ -70,6 +71,75 @@ type histogramInfo struct {
 	previousSum   float64
 }
 
+type exponentialHistogramInfo struct {
+	startTime             pcommon.Timestamp
+	previousCount         uint64
+	previousZeroCount     uint64
+	previousZeroThreshold float64
+	previousScale         int32
+	previousPositive      exponentialBuckets
+	previousNegative      exponentialBuckets
+}
+
+type exponentialBuckets struct {
+	offset int32
+	counts []uint64
+}
+
+func (info *exponentialHistogramInfo) update(dp pmetric.ExponentialHistogramDataPoint) {
+	info.previousCount = dp.Count()
+	info.previousZeroCount = dp.ZeroCount()
+	info.previousZeroThreshold = dp.ZeroThreshold()
+	info.previousScale = dp.Scale()
+	info.previousPositive = exponentialBuckets{offset: dp.Positive().Offset(), counts: dp.Positive().BucketCounts().AsRaw()}
+	info.previousNegative = exponentialBuckets{offset: dp.Negative().Offset(), counts: dp.Negative().BucketCounts().AsRaw()}
+}
+
+// isReset reports whether dp follows a counter reset. Like the reset detection
+// of Prometheus native histograms, it checks the count, the zero bucket and
+// every bucket, and treats a higher scale or a narrower zero bucket as a reset.
+// The sum is not checked as it decreases with negative observations.
+func (info *exponentialHistogramInfo) isReset(dp pmetric.ExponentialHistogramDataPoint) bool {
+	if dp.Count() < info.previousCount ||
+		dp.Scale() > info.previousScale ||
+		dp.ZeroThreshold() < info.previousZeroThreshold ||
+		dp.ZeroCount() < info.previousZeroCount {
+		return true
+	}
+	if dp.ZeroThreshold() != info.previousZeroThreshold {
+		// A wider zero bucket absorbs an unknown part of the previous buckets.
+		return false
+	}
+	scaleDiff := info.previousScale - dp.Scale()
+	return info.previousPositive.decreased(dp.Positive(), scaleDiff) ||
+		info.previousNegative.decreased(dp.Negative(), scaleDiff)
+}
+
+// decreased reports whether any bucket of current has a lower count than the
+// same bucket of b, once b is downscaled by scaleDiff to the scale of current.
+func (b exponentialBuckets) decreased(current pmetric.ExponentialHistogramDataPointBuckets, scaleDiff int32) bool {
+	index := b.offset >> scaleDiff
+	var merged uint64
+	for i, count := range b.counts {
+		if next := (b.offset + int32(i)) >> scaleDiff; next != index {
+			if merged > bucketCount(current, index) {
+				return true
+			}
+			index, merged = next, 0
+		}
+		merged += count
+	}
+	return merged > bucketCount(current, index)
+}
+
+func bucketCount(buckets pmetric.ExponentialHistogramDataPointBuckets, index int32) uint64 {
+	i := int(index - buckets.Offset())
+	if i < 0 || i >= buckets.BucketCounts().Len() {
+		return 0
+	}
+	return buckets.BucketCounts().At(i)
+}
+
 type summaryInfo struct {
 	startTime     pcommon.Timestamp
 	previousCount uint64
@@ -376,30 +446,27 @@ func (a *initialPointAdjuster) adjustMetricExponentialHistogram(tsm *timeseriesM
 		tsi, found := tsm.get(current, currentDist.Attributes())
 		if !found {
 			// initialize everything.
-			tsi.histogram.startTime = currentDist.StartTimestamp()
-			tsi.histogram.previousCount = currentDist.Count()
-			tsi.histogram.previousSum = currentDist.Sum()
+			tsi.exponentialHistogram.startTime = currentDist.StartTimestamp()
+			tsi.exponentialHistogram.update(currentDist)
 			continue
 		}
 
 		if currentDist.Flags().NoRecordedValue() {
 			// TODO: Investigate why this does not reset.
-			currentDist.SetStartTimestamp(tsi.histogram.startTime)
+			currentDist.SetStartTimestamp(tsi.exponentialHistogram.startTime)
 			continue
 		}
 
-		if currentDist.Count() < tsi.histogram.previousCount || currentDist.Sum() < tsi.histogram.previousSum {
+		if tsi.exponentialHistogram.isReset(currentDist) {
 			// reset re-initialize everything.
-			tsi.histogram.startTime = currentDist.StartTimestamp()
-			tsi.histogram.previousCount = currentDist.Count()
-			tsi.histogram.previousSum = currentDist.Sum()
+			tsi.exponentialHistogram.startTime = currentDist.StartTimestamp()
+			tsi.exponentialHistogram.update(currentDist)
 			continue
 		}
 
 		// Update only previous values.
-		tsi.histogram.previousCount = currentDist.Count()
-		tsi.histogram.previousSum = currentDist.Sum()
-		currentDist.SetStartTimestamp(tsi.histogram.startTime)
+		tsi.exponentialHistogram.update(currentDist)
+		currentDist.SetStartTimestamp(tsi.exponentialHistogram.startTime)
 	}
 }
 
@@ -247,10 +247,6 @@ func TestHistogramFlagNoRecordedValueFirstObservation(t *testing.T) {
 	runScript(t, NewInitialPointAdjuster(zap.NewNop(), time.Minute, true), "job", "0", script)
 }
 
-// In TestExponentHistogram we exclude negative buckets on purpose as they are
-// not considered the main use case - response times that are most commonly
-// observed are never negative. Negative buckets would make the Sum() non
-// monotonic and cause unexpected resets.
 func TestExponentialHistogram(t *testing.T) {
 	script := []*metricsAdjusterTest{
 		{
@@ -274,6 +270,33 @@ func TestExponentialHistogram(t *testing.T) {
 	runScript(t, NewInitialPointAdjuster(zap.NewNop(), time.Minute, true), "job", "0", script)
 }
 
+func TestExponentialHistogramResets(t *testing.T) {
+	script := []*metricsAdjusterTest{
+		{
+			description: "Exponential Histogram: round 1 - initial instance, start time is established",
+			metrics:     metrics(exponentialHistogramMetric(exponentialHistogram1, exponentialHistogramPoint(k1v1k2v2, t1, t1, 1, 1, 0, []uint64{1, 1}, 0, []uint64{2, 2}))),
+			adjusted:    metrics(exponentialHistogramMetric(exponentialHistogram1, exponentialHistogramPoint(k1v1k2v2, t1, t1, 1, 1, 0, []uint64{1, 1}, 0, []uint64{2, 2}))),
+		}, {
+			description: "Exponential Histogram: round 2 - negative observations decrease the sum, start time is kept",
+			metrics:     metrics(exponentialHistogramMetric(exponentialHistogram1, exponentialHistogramPoint(k1v1k2v2, t2, t2, 1, 1, 0, []uint64{1, 3}, 0, []uint64{2, 2}))),
+			adjusted:    metrics(exponentialHistogramMetric(exponentialHistogram1, exponentialHistogramPoint(k1v1k2v2, t1, t2, 1, 1, 0, []uint64{1, 3}, 0, []uint64{2, 2}))),
+		}, {
+			description: "Exponential Histogram: round 3 - scale is reduced and buckets are merged, start time is kept",
+			metrics:     metrics(exponentialHistogramMetric(exponentialHistogram1, exponentialHistogramPoint(k1v1k2v2, t3, t3, 0, 1, 0, []uint64{4}, 0, []uint64{5}))),
+			adjusted:    metrics(exponentialHistogramMetric(exponentialHistogram1, exponentialHistogramPoint(k1v1k2v2, t1, t3, 0, 1, 0, []uint64{4}, 0, []uint64{5}))),
+		}, {
+			description: "Exponential Histogram: round 4 - scale is increased, start time is reset",
+			metrics:     metrics(exponentialHistogramMetric(exponentialHistogram1, exponentialHistogramPoint(k1v1k2v2, t4, t4, 1, 1, 0, []uint64{2, 2}, 0, []uint64{3, 3}))),
+			adjusted:    metrics(exponentialHistogramMetric(exponentialHistogram1, exponentialHistogramPoint(k1v1k2v2, t4, t4, 1, 1, 0, []uint64{2, 2}, 0, []uint64{3, 3}))),
+		}, {
+			description: "Exponential Histogram: round 5 - a bucket decreases while the count does not, start time is reset",
+			metrics:     metrics(exponentialHistogramMetric(exponentialHistogram1, exponentialHistogramPoint(k1v1k2v2, t5, t5, 1, 1, 0, []uint64{2, 2}, 0, []uint64{4, 2}))),
+			adjusted:    metrics(exponentialHistogramMetric(exponentialHistogram1, exponentialHistogramPoint(k1v1k2v2, t5, t5, 1, 1, 0, []uint64{2, 2}, 0, []uint64{4, 2}))),
+		},
+	}
+	runScript(t, NewInitialPointAdjuster(zap.NewNop(), time.Minute, true), "job", "0", script)
+}
+
 func TestExponentialHistogramFlagNoRecordedValue(t *testing.T) {
 	script := []*metricsAdjusterTest{
 		{
@@ -1639,6 +1639,7 @@ func TestMetricBuilderNativeHistogram(t *testing.T) {
 			ZeroCount:     0,
 		}
 		h0 := tsdbutil.GenerateTestHistogram(0)
+		gaugeH := tsdbutil.GenerateTestGaugeHistogram(0)
 
 		tests := []buildTestData{
 			{
@@ -1716,6 +1717,19 @@ func TestMetricBuilderNativeHistogram(t *testing.T) {
 					return []pmetric.Metrics{md0}
 				},
 			},
+			{
+				name: "gauge histogram is dropped",
+				inputs: []*testScrapedPage{
+					{
+						pts: []*testDataPoint{
+							createHistogramDataPoint("hist_test", gaugeH, nil, nil, "foo", "bar"),
+						},
+					},
+				},
+				wants: func() []pmetric.Metrics {
+					return []pmetric.Metrics{pmetric.NewMetrics()}
+				},
+			},
 		}
 
 		for _, tt := range tests {
@@ -611,3 +611,103 @@ func TestFloatCounterHistogram(t *testing.T) {
 		c.PrometheusConfig.GlobalConfig.ScrapeProtocols = []config.ScrapeProtocol{config.PrometheusProto}
 	})
 }
+
+func TestNativeHistogramStartTimeAdjustment(t *testing.T) {
+	nativeHistogram := func(count uint64, sum float64, negativeDelta []int64) *dto.MetricFamily {
+		return &dto.MetricFamily{
+			Name: "test_native_histogram",
+			Type: dto.MetricType_HISTOGRAM,
+			Metric: []dto.Metric{
+				{
+					Histogram: &dto.Histogram{
+						SampleCount:   count,
+						SampleSum:     sum,
+						Schema:        3,
+						ZeroThreshold: 0.001,
+						ZeroCount:     2,
+						NegativeSpan: []dto.BucketSpan{
+							{Offset: 0, Length: 2},
+						},
+						NegativeDelta: negativeDelta,
+						PositiveSpan: []dto.BucketSpan{
+							{Offset: 0, Length: 2},
+						},
+						PositiveDelta: []int64{2, 0},
+					},
+				},
+			},
+		}
+	}
+	gaugeHistogram := &dto.MetricFamily{
+		Name: "test_gauge_histogram",
+		Type: dto.MetricType_GAUGE_HISTOGRAM,
+		Metric: []dto.Metric{
+			{
+				Histogram: &dto.Histogram{
+					SampleCount:   3,
+					SampleSum:     5,
+					Schema:        0,
+					ZeroThreshold: 0.001,
+					ZeroCount:     1,
+					PositiveSpan: []dto.BucketSpan{
+						{Offset: 0, Length: 1},
+					},
+					PositiveDelta: []int64{2},
+				},
+			},
+		},
+	}
+
+	// The second scrape only adds negative observations, so the sum decreases
+	// without a counter reset.
+	buffer1 := prometheusMetricFamilyToProtoBuf(t, nil, nativeHistogram(8, 10, []int64{1, 0}))
+	prometheusMetricFamilyToProtoBuf(t, buffer1, gaugeHistogram)
+	buffer2 := prometheusMetricFamilyToProtoBuf(t, nil, nativeHistogram(12, 2, []int64{3, 0}))
+	prometheusMetricFamilyToProtoBuf(t, buffer2, gaugeHistogram)
+
+	exponentialHistogramPoint := func(t *testing.T, rm pmetric.ResourceMetrics) pmetric.ExponentialHistogramDataPoint {
+		for _, m := range getMetrics(rm) {
+			if m.Name() == "test_native_histogram" {
+				require.Equal(t, pmetric.MetricTypeExponentialHistogram, m.Type())
+				require.Equal(t, 1, m.ExponentialHistogram().DataPoints().Len())
+				return m.ExponentialHistogram().DataPoints().At(0)
+			}
+		}
+		require.Fail(t, "test_native_histogram not found")
+		return pmetric.ExponentialHistogramDataPoint{}
+	}
+
+	targets := []*testData{
+		{
+			name: "target1",
+			pages: []mockPrometheusResponse{
+				{code: 200, useProtoBuf: true, buf: buffer1.Bytes()},
+				{code: 200, useProtoBuf: true, buf: buffer2.Bytes()},
+			},
+			validateFunc: func(t *testing.T, td *testData, result []pmetric.ResourceMetrics) {
+				verifyNumValidScrapeResults(t, td, result)
+				expectations := []testExpectation{
+					// Gauge histograms can't be represented in OTLP.
+					assertMetricAbsent("test_gauge_histogram"),
+				}
+				doCompare(t, "scrape1", td.attributes, result[0], expectations)
+				doCompare(t, "scrape2", td.attributes, result[1], expectations)
+
+				first := exponentialHistogramPoint(t, result[0])
+				second := exponentialHistogramPoint(t, result[1])
+				require.Equal(t, first.Timestamp(), first.StartTimestamp())
+				require.Equal(t, first.StartTimestamp(), second.StartTimestamp())
+				require.Less(t, second.StartTimestamp(), second.Timestamp())
+				require.Equal(t, []uint64{3, 3}, second.Negative().BucketCounts().AsRaw())
+			},
+		},
+	}
+	err := featuregate.GlobalRegistry().Set("receiver.prometheusreceiver.EnableNativeHistograms", true)
+	require.NoError(t, err)
+	defer func() {
+		_ = featuregate.GlobalRegistry().Set("receiver.prometheusreceiver.EnableNativeHistograms", false)
+	}()
+	testComponent(t, targets, func(c *Config) {
+		c.PrometheusConfig.GlobalConfig.ScrapeProtocols = []config.ScrapeProtocol{config.PrometheusProto}
+	})
+}
@@ -0,0 +1,32 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusremotewritereceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusremotewritereceiver"
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/confighttp"
+)
+
+var errNonPositiveMaxDecompressedBodySize = errors.New("max_decompressed_body_size must be positive")
+
+// Config defines configuration for the Prometheus Remote Write receiver.
+type Config struct {
+	confighttp.ServerConfig `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct.
+
+	// MaxDecompressedBodySize is the maximum size in bytes of a request body
+	// once decompressed. Larger requests are rejected before being decoded.
+	MaxDecompressedBodySize int64 `mapstructure:"max_decompressed_body_size"`
+}
+
+var _ component.Config = (*Config)(nil)
+
+// Validate checks the receiver configuration is valid.
+func (cfg *Config) Validate() error {
+	if cfg.MaxDecompressedBodySize <= 0 {
+		return errNonPositiveMaxDecompressedBodySize
+	}
+	return nil
+}
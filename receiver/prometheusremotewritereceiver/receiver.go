@@ -0,0 +1,238 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusremotewritereceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusremotewritereceiver"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/golang/snappy"
+	"github.com/prometheus/prometheus/prompb"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/receiver"
+	"go.opentelemetry.io/collector/receiver/receiverhelper"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheusremotewrite"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheusremotewrite/writev2"
+)
+
+const (
+	writePath = "/api/v1/write"
+	scopeName = "otelcol/prometheusremotewritereceiver"
+
+	protoMsgV1 = "prometheus.WriteRequest"
+	protoMsgV2 = "io.prometheus.write.v2.Request"
+)
+
+var errUnsupportedContentType = errors.New("unsupported content type")
+
+type remoteWriteReceiver struct {
+	settings     receiver.CreateSettings
+	config       *Config
+	nextConsumer consumer.Metrics
+	obsrecv      *receiverhelper.ObsReport
+
+	server     *http.Server
+	shutdownWG sync.WaitGroup
+}
+
+func newRemoteWriteReceiver(settings receiver.CreateSettings, cfg *Config, nextConsumer consumer.Metrics) (receiver.Metrics, error) {
+	obsrecv, err := receiverhelper.NewObsReport(receiverhelper.ObsReportSettings{
+		ReceiverID:             settings.ID,
+		Transport:              "http",
+		ReceiverCreateSettings: settings,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &remoteWriteReceiver{
+		settings:     settings,
+		config:       cfg,
+		nextConsumer: nextConsumer,
+		obsrecv:      obsrecv,
+	}, nil
+}
+
+func (rw *remoteWriteReceiver) Start(ctx context.Context, host component.Host) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc(writePath, rw.handlePRW)
+
+	var err error
+	// Remote Write bodies use the snappy block format and are decoded by the
+	// handler, while confighttp would decode the snappy stream format.
+	rw.server, err = rw.config.ToServer(ctx, host, rw.settings.TelemetrySettings, mux,
+		confighttp.WithDecoder("snappy", func(body io.ReadCloser) (io.ReadCloser, error) { return body, nil }))
+	if err != nil {
+		return fmt.Errorf("failed to create server: %w", err)
+	}
+
+	listener, err := rw.config.ToListener(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create listener: %w", err)
+	}
+	rw.settings.Logger.Info("Starting Prometheus Remote Write receiver", zap.String("endpoint", rw.config.Endpoint))
+
+	rw.shutdownWG.Add(1)
+	go func() {
+		defer rw.shutdownWG.Done()
+		if errHTTP := rw.server.Serve(listener); errHTTP != nil && !errors.Is(errHTTP, http.ErrServerClosed) {
+			rw.settings.ReportStatus(component.NewFatalErrorEvent(errHTTP))
+		}
+	}()
+	return nil
+}
+
+func (rw *remoteWriteReceiver) Shutdown(ctx context.Context) error {
+	if rw.server == nil {
+		return nil
+	}
+	err := rw.server.Shutdown(ctx)
+	rw.shutdownWG.Wait()
+	return err
+}
+
+// handlePRW handles Remote Write requests. The protocol version is negotiated
+// with the proto parameter of the Content-Type header, as described in
+// https://prometheus.io/docs/specs/remote_write_spec_2_0/#protocol
+func (rw *remoteWriteReceiver) handlePRW(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, fmt.Sprintf("%s method not allowed, supported: [POST]", req.Method), http.StatusMethodNotAllowed)
+		return
+	}
+
+	protoMsg, err := parseProtoMsg(req.Header.Get("Content-Type"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnsupportedMediaType)
+		return
+	}
+	if enc := req.Header.Get("Content-Encoding"); enc != "" && enc != "snappy" {
+		http.Error(w, fmt.Sprintf("unsupported content encoding %q, supported: [snappy]", enc), http.StatusUnsupportedMediaType)
+		return
+	}
+
+	// A body can't be larger than the encoding of the largest allowed
+	// decompressed body, read one more byte to detect larger ones.
+	maxSize := rw.config.MaxDecompressedBodySize
+	body, err := io.ReadAll(io.LimitReader(req.Body, int64(snappy.MaxEncodedLen(int(maxSize)))+1))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	decodedLen, err := snappy.DecodedLen(body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to decode snappy body: %v", err), http.StatusBadRequest)
+		return
+	}
+	if int64(decodedLen) > maxSize {
+		http.Error(w, fmt.Sprintf("decompressed body exceeds %d bytes", maxSize), http.StatusRequestEntityTooLarge)
+		return
+	}
+	data, err := snappy.Decode(nil, body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to decode snappy body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	var md pmetric.Metrics
+	var stats prometheusremotewrite.WriteStats
+	var translateErr error
+	switch protoMsg {
+	case protoMsgV1:
+		var writeReq prompb.WriteRequest
+		if err = writeReq.Unmarshal(data); err != nil {
+			http.Error(w, fmt.Sprintf("failed to unmarshal %s: %v", protoMsg, err), http.StatusBadRequest)
+			return
+		}
+		md, translateErr = prometheusremotewrite.ToMetrics(&writeReq)
+	case protoMsgV2:
+		var writeReq writev2.Request
+		if err = writeReq.Unmarshal(data); err != nil {
+			http.Error(w, fmt.Sprintf("failed to unmarshal %s: %v", protoMsg, err), http.StatusBadRequest)
+			return
+		}
+		md, stats, translateErr = prometheusremotewrite.ToMetricsV2(&writeReq)
+	}
+	if translateErr != nil {
+		rw.settings.Logger.Debug("failed to translate some series", zap.Error(translateErr))
+	}
+
+	if md.DataPointCount() > 0 {
+		rw.setScope(md)
+		ctx := rw.obsrecv.StartMetricsOp(req.Context())
+		err = rw.nextConsumer.ConsumeMetrics(ctx, md)
+		rw.obsrecv.EndMetricsOp(ctx, "protobuf", md.DataPointCount(), err)
+		if err != nil {
+			if protoMsg == protoMsgV2 {
+				setWrittenHeaders(w, prometheusremotewrite.WriteStats{})
+			}
+			status := http.StatusInternalServerError
+			if consumererror.IsPermanent(err) {
+				status = http.StatusBadRequest
+			}
+			http.Error(w, err.Error(), status)
+			return
+		}
+	}
+
+	if protoMsg == protoMsgV2 {
+		setWrittenHeaders(w, stats)
+	}
+	if translateErr != nil {
+		// Sending the invalid series again would fail again.
+		http.Error(w, translateErr.Error(), http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// parseProtoMsg returns the protobuf message of a request with the given
+// Content-Type. Requests without a proto parameter are Remote Write 1.0 requests.
+func parseProtoMsg(contentType string) (string, error) {
+	if contentType == "" {
+		return protoMsgV1, nil
+	}
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "", fmt.Errorf("%w %q: %w", errUnsupportedContentType, contentType, err)
+	}
+	if mediaType != "application/x-protobuf" {
+		return "", fmt.Errorf("%w %q, supported: [application/x-protobuf]", errUnsupportedContentType, contentType)
+	}
+	switch params["proto"] {
+	case "", protoMsgV1:
+		return protoMsgV1, nil
+	case protoMsgV2:
+		return protoMsgV2, nil
+	}
+	return "", fmt.Errorf("%w %q, supported proto messages: [%s, %s]", errUnsupportedContentType, contentType, protoMsgV1, protoMsgV2)
+}
+
+func setWrittenHeaders(w http.ResponseWriter, stats prometheusremotewrite.WriteStats) {
+	w.Header().Set(writev2.SamplesWrittenHeader, strconv.Itoa(stats.Samples))
+	w.Header().Set(writev2.HistogramsWrittenHeader, strconv.Itoa(stats.Histograms))
+	w.Header().Set(writev2.ExemplarsWrittenHeader, strconv.Itoa(stats.Exemplars))
+}
+
+func (rw *remoteWriteReceiver) setScope(md pmetric.Metrics) {
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		sms := rms.At(i).ScopeMetrics()
+		for j := 0; j < sms.Len(); j++ {
+			sms.At(j).Scope().SetName(scopeName)
+			sms.At(j).Scope().SetVersion(rw.settings.BuildInfo.Version)
+		}
+	}
+}
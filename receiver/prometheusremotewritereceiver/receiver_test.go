@@ -0,0 +1,223 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusremotewritereceiver
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/golang/snappy"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/testutil"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheusremotewrite/writev2"
+)
+
+func startReceiver(t *testing.T, next consumer.Metrics) string {
+	return startReceiverWithConfig(t, createDefaultConfig().(*Config), next)
+}
+
+func startReceiverWithConfig(t *testing.T, cfg *Config, next consumer.Metrics) string {
+	cfg.Endpoint = testutil.GetAvailableLocalAddress(t)
+
+	rcvr, err := newRemoteWriteReceiver(receivertest.NewNopCreateSettings(), cfg, next)
+	require.NoError(t, err)
+	require.NoError(t, rcvr.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		require.NoError(t, rcvr.Shutdown(context.Background()))
+	})
+	return "http://" + cfg.Endpoint + writePath
+}
+
+func post(t *testing.T, url string, contentType string, body []byte) *http.Response {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(snappy.Encode(nil, body)))
+	require.NoError(t, err)
+	req.Header.Set("Content-Encoding", "snappy")
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	_, _ = io.Copy(io.Discard, resp.Body)
+	require.NoError(t, resp.Body.Close())
+	return resp
+}
+
+func v1Request(t *testing.T) []byte {
+	req := &prompb.WriteRequest{
+		Timeseries: []prompb.TimeSeries{{
+			Labels: []prompb.Label{
+				{Name: "__name__", Value: "requests_total"},
+				{Name: "job", Value: "api"},
+			},
+			Samples: []prompb.Sample{{Value: 5, Timestamp: 1000}},
+		}},
+	}
+	b, err := req.Marshal()
+	require.NoError(t, err)
+	return b
+}
+
+func v2Request(t *testing.T, series ...writev2.TimeSeries) []byte {
+	req := &writev2.Request{
+		Symbols:    []string{"", "__name__", "requests_total", "job", "api", "Number of requests"},
+		Timeseries: series,
+	}
+	b, err := req.Marshal()
+	require.NoError(t, err)
+	return b
+}
+
+var v2Series = writev2.TimeSeries{
+	LabelsRefs: []uint32{1, 2, 3, 4},
+	Samples:    []writev2.Sample{{Value: 5, Timestamp: 1000}, {Value: 6, Timestamp: 2000}},
+	Exemplars:  []writev2.Exemplar{{Value: 1, Timestamp: 1000}},
+	Metadata: writev2.Metadata{
+		Type:    writev2.Metadata_METRIC_TYPE_COUNTER,
+		HelpRef: 5,
+	},
+	CreatedTimestamp: 500,
+}
+
+func TestReceiveRemoteWriteV1(t *testing.T) {
+	for _, contentType := range []string{"", "application/x-protobuf", "application/x-protobuf;proto=prometheus.WriteRequest"} {
+		t.Run(contentType, func(t *testing.T) {
+			sink := new(consumertest.MetricsSink)
+			url := startReceiver(t, sink)
+
+			resp := post(t, url, contentType, v1Request(t))
+			assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+			assert.Empty(t, resp.Header.Get(writev2.SamplesWrittenHeader))
+
+			require.Len(t, sink.AllMetrics(), 1)
+			md := sink.AllMetrics()[0]
+			rm := md.ResourceMetrics().At(0)
+			serviceName, _ := rm.Resource().Attributes().Get("service.name")
+			assert.Equal(t, "api", serviceName.Str())
+			assert.Equal(t, scopeName, rm.ScopeMetrics().At(0).Scope().Name())
+			metric := rm.ScopeMetrics().At(0).Metrics().At(0)
+			assert.Equal(t, "requests_total", metric.Name())
+			assert.Equal(t, pmetric.MetricTypeSum, metric.Type())
+		})
+	}
+}
+
+func TestReceiveRemoteWriteV2(t *testing.T) {
+	sink := new(consumertest.MetricsSink)
+	url := startReceiver(t, sink)
+
+	resp := post(t, url, writev2.ContentType, v2Request(t, v2Series))
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	assert.Equal(t, "2", resp.Header.Get(writev2.SamplesWrittenHeader))
+	assert.Equal(t, "0", resp.Header.Get(writev2.HistogramsWrittenHeader))
+	assert.Equal(t, "1", resp.Header.Get(writev2.ExemplarsWrittenHeader))
+
+	require.Len(t, sink.AllMetrics(), 1)
+	metric := sink.AllMetrics()[0].ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0)
+	assert.Equal(t, "requests_total", metric.Name())
+	assert.Equal(t, "Number of requests", metric.Description())
+	require.Equal(t, pmetric.MetricTypeSum, metric.Type())
+	require.Equal(t, 2, metric.Sum().DataPoints().Len())
+	assert.NotZero(t, metric.Sum().DataPoints().At(0).StartTimestamp())
+}
+
+func TestReceiveRemoteWriteV2PartialWrite(t *testing.T) {
+	sink := new(consumertest.MetricsSink)
+	url := startReceiver(t, sink)
+
+	invalid := writev2.TimeSeries{
+		LabelsRefs: []uint32{1, 42},
+		Samples:    []writev2.Sample{{Value: 1, Timestamp: 1000}},
+	}
+	resp := post(t, url, writev2.ContentType, v2Request(t, v2Series, invalid))
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, "2", resp.Header.Get(writev2.SamplesWrittenHeader))
+	assert.Equal(t, 2, sink.DataPointCount())
+}
+
+func TestReceiveErrors(t *testing.T) {
+	tests := []struct {
+		name           string
+		contentType    string
+		body           []byte
+		consumer       consumer.Metrics
+		expectedStatus int
+	}{
+		{
+			name:           "unsupported media type",
+			contentType:    "application/json",
+			body:           []byte("{}"),
+			consumer:       consumertest.NewNop(),
+			expectedStatus: http.StatusUnsupportedMediaType,
+		},
+		{
+			name:           "unsupported proto message",
+			contentType:    "application/x-protobuf;proto=io.prometheus.write.v3.Request",
+			body:           v1Request(t),
+			consumer:       consumertest.NewNop(),
+			expectedStatus: http.StatusUnsupportedMediaType,
+		},
+		{
+			name:           "invalid body",
+			contentType:    writev2.ContentType,
+			body:           []byte{0x2a, 0x05, 0x01},
+			consumer:       consumertest.NewNop(),
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "retryable consumer error",
+			body:           v1Request(t),
+			consumer:       consumertest.NewErr(errors.New("temporary")),
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:           "permanent consumer error",
+			body:           v1Request(t),
+			consumer:       consumertest.NewErr(consumererror.NewPermanent(errors.New("permanent"))),
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url := startReceiver(t, tt.consumer)
+			resp := post(t, url, tt.contentType, tt.body)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+		})
+	}
+}
+
+func TestReceiveRejectsLargeBody(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.MaxDecompressedBodySize = 1024
+	sink := new(consumertest.MetricsSink)
+	url := startReceiverWithConfig(t, cfg, sink)
+
+	// The body compresses well below the limit but decompresses above it.
+	resp := post(t, url, "", bytes.Repeat([]byte{0}, 2048))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+
+	resp = post(t, url, "", v1Request(t))
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	assert.Len(t, sink.AllMetrics(), 1)
+}
+
+func TestReceiveRejectsGet(t *testing.T) {
+	url := startReceiver(t, consumertest.NewNop())
+	resp, err := http.Get(url)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+}
@@ -0,0 +1,119 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package receivercreator // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/receivercreator"
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"sort"
+
+	"go.opentelemetry.io/collector/confmap"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// metricsAnnotationPrefix is the prefix of the pod annotations used to opt in to metrics
+	// discovery. Annotations apply to all ports of the pod, or to a single port when the prefix
+	// is followed by the port number (e.g. io.opentelemetry.discovery.metrics.6379/scraper).
+	metricsAnnotationPrefix = "io.opentelemetry.discovery.metrics"
+	// logsAnnotationPrefix is the prefix of the pod annotations and container labels used to
+	// opt in to log collection. Pod annotations apply to all containers of the pod, or to a
+	// single container when the prefix is followed by the container name
+	// (e.g. io.opentelemetry.discovery.logs.nginx/receiver).
+	logsAnnotationPrefix = "io.opentelemetry.discovery.logs"
+	// scraperAnnotation selects the metrics receiver template by its name in the receivers section.
+	scraperAnnotation = "scraper"
+	// receiverAnnotation selects the logs receiver template by its name in the receivers section.
+	receiverAnnotation = "receiver"
+	// configAnnotation holds YAML config overriding the config of the receiver template.
+	configAnnotation = "config"
+)
+
+// DiscoveryConfig configures the receivers started from pod annotations and container labels.
+type DiscoveryConfig struct {
+	// Enabled starts the receiver templates selected by the annotations of the pods
+	// owning discovered port and container endpoints, and by the labels of discovered
+	// docker containers.
+	Enabled bool `mapstructure:"enabled"`
+	// AllowedConfigKeys are the top-level keys of the receiver config that annotations and
+	// labels can override. An endpoint override must target the discovered endpoint.
+	AllowedConfigKeys []string `mapstructure:"allowed_config_keys"`
+}
+
+// annotationHints are the receiver template and config overrides selected by the
+// annotations of a pod for one of its ports or containers.
+type annotationHints struct {
+	template string
+	config   userConfigMap
+}
+
+// hintsFromAnnotations returns the hints of the given annotations under the prefix, and
+// false if they don't select a receiver template with the templateKey. Annotations of the
+// scope (a port or container name) take precedence over the unscoped ones. An empty scope
+// only looks up the unscoped annotations.
+func hintsFromAnnotations(annotations map[string]string, prefix, templateKey, scope string) (annotationHints, bool, error) {
+	keyPrefixes := []string{prefix + "/"}
+	if scope != "" {
+		keyPrefixes = append(keyPrefixes, fmt.Sprintf("%s.%s/", prefix, scope))
+	}
+
+	var hints annotationHints
+	for _, keyPrefix := range keyPrefixes {
+		if template, ok := annotations[keyPrefix+templateKey]; ok {
+			hints.template = template
+		}
+	}
+	if hints.template == "" {
+		return hints, false, nil
+	}
+
+	merged := confmap.New()
+	for _, keyPrefix := range keyPrefixes {
+		key := keyPrefix + configAnnotation
+		raw, ok := annotations[key]
+		if !ok {
+			continue
+		}
+		var cfg map[string]any
+		if err := yaml.Unmarshal([]byte(raw), &cfg); err != nil {
+			return hints, true, fmt.Errorf("invalid %q annotation: %w", key, err)
+		}
+		if err := merged.Merge(confmap.NewFromStringMap(cfg)); err != nil {
+			return hints, true, fmt.Errorf("invalid %q annotation: %w", key, err)
+		}
+	}
+	hints.config = merged.ToStringMap()
+	return hints, true, nil
+}
+
+// validateOverrides checks that the config overrides of the hints only set allowed keys, and
+// that an endpoint override targets the discovered endpoint, so that annotations and labels
+// can't make the collector connect to other hosts.
+func validateOverrides(overrides userConfigMap, allowedKeys []string, target string) error {
+	keys := make([]string, 0, len(overrides))
+	for key := range overrides {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if !slices.Contains(allowedKeys, key) {
+			return fmt.Errorf("config key %q is not allowed by discovery::allowed_config_keys", key)
+		}
+	}
+
+	endpoint, ok := overrides[endpointConfigKey]
+	if !ok {
+		return nil
+	}
+	if value, isString := endpoint.(string); isString {
+		if value == target {
+			return nil
+		}
+		if u, err := url.Parse(value); err == nil && u.Host == target {
+			return nil
+		}
+	}
+	return fmt.Errorf("endpoint %v must be the discovered endpoint %q, optionally with a scheme and a path", endpoint, target)
+}
@@ -40,7 +40,8 @@ type receiverTemplate struct {
 	receiverConfig
 
 	// Rule is the discovery rule that when matched will create a receiver instance
-	// based on receiverTemplate.
+	// based on receiverTemplate. It is optional when discovery from pod annotations
+	// is enabled.
 	Rule string `mapstructure:"rule"`
 	// ResourceAttributes is a map of resource attributes to add to just this receiver's resource metrics.
 	// It can contain expr expressions for endpoint env value expansion
@@ -78,6 +79,8 @@ type Config struct {
 	// ResourceAttributes is a map of default resource attributes to add to each resource
 	// object received by this receiver from dynamically created receivers.
 	ResourceAttributes resourceAttributes `mapstructure:"resource_attributes"`
+	// Discovery configures starting receivers from pod annotations.
+	Discovery DiscoveryConfig `mapstructure:"discovery"`
 }
 
 func (cfg *Config) Unmarshal(componentParser *confmap.Conf) error {
@@ -92,7 +95,7 @@ func (cfg *Config) Unmarshal(componentParser *confmap.Conf) error {
 
 	for endpointType := range cfg.ResourceAttributes {
 		switch endpointType {
-		case observer.ContainerType, observer.K8sServiceType, observer.HostPortType, observer.K8sNodeType, observer.PodType, observer.PortType:
+		case observer.ContainerType, observer.K8sServiceType, observer.HostPortType, observer.K8sNodeType, observer.PodType, observer.PortType, observer.PodContainerType:
 		default:
 			return fmt.Errorf("resource attributes for unsupported endpoint type %q", endpointType)
 		}
@@ -119,9 +122,12 @@ func (cfg *Config) Unmarshal(componentParser *confmap.Conf) error {
 			return fmt.Errorf("failed to deserialize sub-receiver %q: %w", subreceiverKey, err)
 		}
 
-		subreceiver.rule, err = newRule(subreceiver.Rule)
-		if err != nil {
-			return fmt.Errorf("subreceiver %q rule is invalid: %w", subreceiverKey, err)
+		// Without a rule, a template can only be started from pod annotations.
+		if subreceiver.Rule != "" || !cfg.Discovery.Enabled {
+			subreceiver.rule, err = newRule(subreceiver.Rule)
+			if err != nil {
+				return fmt.Errorf("subreceiver %q rule is invalid: %w", subreceiverKey, err)
+			}
 		}
 
 		for k, v := range subreceiver.ResourceAttributes {
@@ -109,15 +109,38 @@ func TestLoadConfig(t *testing.T) {
 					component.MustNewIDWithName("mock_observer", "with_name"),
 				},
 				ResourceAttributes: map[observer.EndpointType]map[string]string{
-					observer.ContainerType:  {"container.key": "container.value"},
-					observer.PodType:        {"pod.key": "pod.value"},
-					observer.PortType:       {"port.key": "port.value"},
-					observer.HostPortType:   {"hostport.key": "hostport.value"},
-					observer.K8sServiceType: {"k8s.service.key": "k8s.service.value"},
-					observer.K8sNodeType:    {"k8s.node.key": "k8s.node.value"},
+					observer.ContainerType:    {"container.key": "container.value"},
+					observer.PodType:          {"pod.key": "pod.value"},
+					observer.PortType:         {"port.key": "port.value"},
+					observer.PodContainerType: {"pod.container.key": "pod.container.value"},
+					observer.HostPortType:     {"hostport.key": "hostport.value"},
+					observer.K8sServiceType:   {"k8s.service.key": "k8s.service.value"},
+					observer.K8sNodeType:      {"k8s.node.key": "k8s.node.value"},
 				},
+				Discovery: createDefaultConfig().(*Config).Discovery,
 			},
 		},
+		{
+			id: component.NewIDWithName(metadata.Type, "discovery"),
+			expected: func() component.Config {
+				cfg := createDefaultConfig().(*Config)
+				cfg.WatchObservers = []component.ID{component.MustNewID("mock_observer")}
+				cfg.Discovery.Enabled = true
+				cfg.Discovery.AllowedConfigKeys = []string{"collection_interval"}
+				cfg.receiverTemplates = map[string]receiverTemplate{
+					"nop/1": {
+						receiverConfig: receiverConfig{
+							id: component.MustNewIDWithName("nop", "1"),
+							config: userConfigMap{
+								endpointConfigKey: "`endpoint`",
+							},
+							endpointID: "endpoint.id",
+						},
+					},
+				}
+				return cfg
+			}(),
+		},
 	}
 
 	for _, tt := range tests {
@@ -30,6 +30,8 @@ func TestNewEnhancingConsumer(t *testing.T) {
 	require.NoError(t, err)
 	cntrEnv, err := containerEndpoint.Env()
 	require.NoError(t, err)
+	podCntrEnv, err := podContainerEndpoint.Env()
+	require.NoError(t, err)
 
 	cfg := createDefaultConfig().(*Config)
 	type args struct {
@@ -89,6 +91,28 @@ func TestNewEnhancingConsumer(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "pod container endpoint",
+			args: args{
+				resources:   cfg.ResourceAttributes,
+				env:         podCntrEnv,
+				endpoint:    podContainerEndpoint,
+				nextLogs:    &consumertest.LogsSink{},
+				nextMetrics: nil,
+				nextTraces:  nil,
+			},
+			want: &enhancingConsumer{
+				logs:    &consumertest.LogsSink{},
+				metrics: nil,
+				traces:  nil,
+				attrs: map[string]string{
+					"k8s.pod.uid":        "uid-1",
+					"k8s.pod.name":       "pod-1",
+					"k8s.namespace.name": "default",
+					"k8s.container.name": "redis",
+				},
+			},
+		},
 		{
 			name: "container endpoint",
 			args: args{
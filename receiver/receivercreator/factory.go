@@ -47,6 +47,12 @@ func createDefaultConfig() component.Config {
 				conventions.AttributeK8SPodUID:        "`pod.uid`",
 				conventions.AttributeK8SNamespaceName: "`pod.namespace`",
 			},
+			observer.PodContainerType: map[string]string{
+				conventions.AttributeK8SPodName:       "`pod.name`",
+				conventions.AttributeK8SPodUID:        "`pod.uid`",
+				conventions.AttributeK8SNamespaceName: "`pod.namespace`",
+				conventions.AttributeK8SContainerName: "`container_name`",
+			},
 			observer.ContainerType: map[string]string{
 				conventions.AttributeContainerName:      "`name`",
 				conventions.AttributeContainerImageName: "`image`",
@@ -56,6 +62,10 @@ func createDefaultConfig() component.Config {
 				conventions.AttributeK8SNodeUID:  "`uid`",
 			},
 		},
+		Discovery: DiscoveryConfig{
+			// settings of the scrapers, which don't reach other hosts than the discovered ones
+			AllowedConfigKeys: []string{"collection_interval", "endpoint", "initial_delay", "metrics_path", "timeout", "username"},
+		},
 		receiverTemplates: map[string]receiverTemplate{},
 	}
 }
@@ -56,6 +56,18 @@ var portEndpoint = observer.Endpoint{
 	},
 }
 
+var podContainerEndpoint = observer.Endpoint{
+	ID:     "container-1",
+	Target: "localhost",
+	Details: &observer.PodContainer{
+		Name:        "redis",
+		Image:       "redis:7",
+		ContainerID: "abc123",
+		LogPath:     "/var/log/pods/default_pod-1_uid-1/redis/*.log",
+		Pod:         pod,
+	},
+}
+
 var hostportEndpoint = observer.Endpoint{
 	ID:     "port-1",
 	Target: "localhost:1234",
@@ -5,9 +5,12 @@ package receivercreator // import "github.com/open-telemetry/opentelemetry-colle
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
 	"sync"
 
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/receiver"
 	"go.uber.org/multierr"
@@ -42,6 +45,19 @@ type observerHandler struct {
 	nextTracesConsumer consumer.Traces
 	// runner starts and stops receiver instances.
 	runner runner
+	// containerLogEndpoints maps the IDs of docker containers whose logs are collected from
+	// their labels to the endpoints of the container.
+	containerLogEndpoints map[string]*containerLogState
+}
+
+// containerLogState tracks the endpoints of a docker container whose logs are collected
+// from its labels. The docker observer reports an endpoint for each port of a container,
+// but its logs must only be collected once, by the owner.
+type containerLogState struct {
+	// owner is the ID of the endpoint that started the logs receiver.
+	owner observer.EndpointID
+	// endpoints are the current endpoints of the container, by ID.
+	endpoints map[observer.EndpointID]observer.Endpoint
 }
 
 // shutdown all receivers started at runtime.
@@ -85,7 +101,17 @@ func (obs *observerHandler) OnAdd(added []observer.Endpoint) {
 
 		obs.params.TelemetrySettings.Logger.Debug("handling added endpoint", zap.Any("env", env))
 
-		for _, template := range obs.config.receiverTemplates {
+		var annotatedTemplate string
+		if obs.config.Discovery.Enabled {
+			annotatedTemplate = obs.startAnnotatedReceiver(e, env)
+		}
+
+		for name, template := range obs.config.receiverTemplates {
+			if template.Rule == "" || name == annotatedTemplate {
+				// Templates without rules are only started from pod annotations, and
+				// annotations take precedence over the rule of the selected template.
+				continue
+			}
 			if matches, e := template.rule.eval(env); e != nil {
 				obs.params.TelemetrySettings.Logger.Error("failed matching rule", zap.String("rule", template.Rule), zap.Error(e))
 				continue
@@ -93,76 +119,174 @@ func (obs *observerHandler) OnAdd(added []observer.Endpoint) {
 				continue
 			}
 
-			obs.params.TelemetrySettings.Logger.Info("starting receiver",
-				zap.String("name", template.id.String()),
-				zap.String("endpoint", e.Target),
-				zap.String("endpoint_id", string(e.ID)))
-
-			resolvedConfig, err := expandConfig(template.config, env)
-			if err != nil {
-				obs.params.TelemetrySettings.Logger.Error("unable to resolve template config", zap.String("receiver", template.id.String()), zap.Error(err))
-				continue
+			if err = obs.startReceiver(template, nil, env, e); err != nil {
+				obs.params.TelemetrySettings.Logger.Error("failed to start receiver", zap.String("receiver", template.id.String()), zap.Error(err))
 			}
+		}
+	}
+}
 
-			discoveredCfg := userConfigMap{}
-			// If user didn't set endpoint set to default value as well as
-			// flag indicating we've done this for later validation.
-			if _, ok := resolvedConfig[endpointConfigKey]; !ok {
-				discoveredCfg[endpointConfigKey] = e.Target
-				discoveredCfg[tmpSetEndpointConfigKey] = struct{}{}
+// startAnnotatedReceiver starts the receiver template selected by the annotations of the
+// pod owning a port or container endpoint, or by the labels of a docker container, and
+// returns the name of the selected template. Invalid hints are reported as recoverable errors,
+// and no template is returned so that the templates with a matching rule are still started.
+func (obs *observerHandler) startAnnotatedReceiver(e observer.Endpoint, env observer.EndpointEnv) string {
+	var hints annotationHints
+	var ok bool
+	var err error
+	var source string
+	switch details := e.Details.(type) {
+	case *observer.Port:
+		hints, ok, err = hintsFromAnnotations(details.Pod.Annotations, metricsAnnotationPrefix, scraperAnnotation, strconv.Itoa(int(details.Port)))
+		source = fmt.Sprintf("annotations of pod %s/%s", details.Pod.Namespace, details.Pod.Name)
+	case *observer.PodContainer:
+		hints, ok, err = hintsFromAnnotations(details.Pod.Annotations, logsAnnotationPrefix, receiverAnnotation, details.Name)
+		source = fmt.Sprintf("annotations of pod %s/%s", details.Pod.Namespace, details.Pod.Name)
+	case *observer.Container:
+		hints, ok, err = hintsFromAnnotations(details.Labels, logsAnnotationPrefix, receiverAnnotation, "")
+		source = fmt.Sprintf("labels of container %s", details.Name)
+		if ok {
+			state, found := obs.containerLogEndpoints[details.ContainerID]
+			if !found {
+				state = &containerLogState{endpoints: map[observer.EndpointID]observer.Endpoint{}}
+				obs.containerLogEndpoints[details.ContainerID] = state
 			}
-
-			// Though not necessary with contrib provided observers, nothing is stopping custom
-			// ones from using expr in their Target values.
-			discoveredConfig, err := expandConfig(discoveredCfg, env)
-			if err != nil {
-				obs.params.TelemetrySettings.Logger.Error("unable to resolve discovered config", zap.String("receiver", template.id.String()), zap.Error(err))
-				continue
+			state.endpoints[e.ID] = e
+			if state.owner != "" && state.owner != e.ID {
+				return hints.template
 			}
+		}
+	}
+	if !ok {
+		return ""
+	}
+	if err == nil {
+		template, found := obs.config.receiverTemplates[hints.template]
+		if !found {
+			err = fmt.Errorf("receiver template %q is not configured", hints.template)
+		} else if err = validateOverrides(hints.config, obs.config.Discovery.AllowedConfigKeys, e.Target); err == nil {
+			err = obs.startReceiver(template, hints.config, env, e)
+		}
+	}
+	if err != nil {
+		err = fmt.Errorf("failed to start receiver from %s: %w", source, err)
+		obs.params.TelemetrySettings.Logger.Error("invalid discovery hints", zap.String("endpoint_id", string(e.ID)), zap.Error(err))
+		obs.params.TelemetrySettings.ReportStatus(component.NewRecoverableErrorEvent(err))
+		return ""
+	}
+	if container, isContainer := e.Details.(*observer.Container); isContainer {
+		obs.containerLogEndpoints[container.ContainerID].owner = e.ID
+	}
+	return hints.template
+}
 
-			resAttrs := map[string]string{}
-			for k, v := range template.ResourceAttributes {
-				strVal, ok := v.(string)
-				if !ok {
-					obs.params.TelemetrySettings.Logger.Info(fmt.Sprintf("ignoring unsupported `resource_attributes` %q value %v", k, v))
-					continue
-				}
-				resAttrs[k] = strVal
-			}
+// removeContainerLogEndpoint forgets a removed endpoint of a docker container. When the
+// endpoint started the logs receiver of the container, the logs receiver is started again
+// from one of the remaining endpoints, so that the logs are still collected.
+func (obs *observerHandler) removeContainerLogEndpoint(containerID string, id observer.EndpointID) {
+	state, ok := obs.containerLogEndpoints[containerID]
+	if !ok {
+		return
+	}
+	delete(state.endpoints, id)
+	if len(state.endpoints) == 0 {
+		delete(obs.containerLogEndpoints, containerID)
+		return
+	}
+	if state.owner != id {
+		return
+	}
+	state.owner = ""
 
-			// Adds default and/or configured resource attributes (e.g. k8s.pod.uid) to resources
-			// as telemetry is emitted.
-			var consumer *enhancingConsumer
-			if consumer, err = newEnhancingConsumer(
-				obs.config.ResourceAttributes,
-				resAttrs,
-				env,
-				e,
-				obs.nextLogsConsumer,
-				obs.nextMetricsConsumer,
-				obs.nextTracesConsumer,
-			); err != nil {
-				obs.params.TelemetrySettings.Logger.Error("failed creating resource enhancer", zap.String("receiver", template.id.String()), zap.Error(err))
-				continue
-			}
+	ids := make([]string, 0, len(state.endpoints))
+	for endpointID := range state.endpoints {
+		ids = append(ids, string(endpointID))
+	}
+	sort.Strings(ids)
+	next := state.endpoints[observer.EndpointID(ids[0])]
+	env, err := next.Env()
+	if err != nil {
+		obs.params.TelemetrySettings.Logger.Error("unable to convert endpoint to environment map", zap.String("endpoint", string(next.ID)), zap.Error(err))
+		return
+	}
+	obs.startAnnotatedReceiver(next, env)
+}
 
-			var receiver component.Component
-			if receiver, err = obs.runner.start(
-				receiverConfig{
-					id:         template.id,
-					config:     resolvedConfig,
-					endpointID: e.ID,
-				},
-				discoveredConfig,
-				consumer,
-			); err != nil {
-				obs.params.TelemetrySettings.Logger.Error("failed to start receiver", zap.String("receiver", template.id.String()), zap.Error(err))
-				continue
-			}
+// startReceiver starts a receiver from the template for the endpoint. The overrides are
+// merged into the resolved template config.
+func (obs *observerHandler) startReceiver(template receiverTemplate, overrides userConfigMap, env observer.EndpointEnv, e observer.Endpoint) error {
+	obs.params.TelemetrySettings.Logger.Info("starting receiver",
+		zap.String("name", template.id.String()),
+		zap.String("endpoint", e.Target),
+		zap.String("endpoint_id", string(e.ID)))
 
-			obs.receiversByEndpointID.Put(e.ID, receiver)
+	resolvedConfig, err := expandConfig(template.config, env)
+	if err != nil {
+		return fmt.Errorf("unable to resolve template config: %w", err)
+	}
+	if len(overrides) > 0 {
+		merged := confmap.NewFromStringMap(resolvedConfig)
+		if err = merged.Merge(confmap.NewFromStringMap(overrides)); err != nil {
+			return fmt.Errorf("unable to merge config overrides: %w", err)
 		}
+		resolvedConfig = merged.ToStringMap()
+	}
+
+	discoveredCfg := userConfigMap{}
+	// If user didn't set endpoint set to default value as well as
+	// flag indicating we've done this for later validation.
+	if _, ok := resolvedConfig[endpointConfigKey]; !ok {
+		discoveredCfg[endpointConfigKey] = e.Target
+		discoveredCfg[tmpSetEndpointConfigKey] = struct{}{}
+	}
+
+	// Though not necessary with contrib provided observers, nothing is stopping custom
+	// ones from using expr in their Target values.
+	discoveredConfig, err := expandConfig(discoveredCfg, env)
+	if err != nil {
+		return fmt.Errorf("unable to resolve discovered config: %w", err)
 	}
+
+	resAttrs := map[string]string{}
+	for k, v := range template.ResourceAttributes {
+		strVal, ok := v.(string)
+		if !ok {
+			obs.params.TelemetrySettings.Logger.Info(fmt.Sprintf("ignoring unsupported `resource_attributes` %q value %v", k, v))
+			continue
+		}
+		resAttrs[k] = strVal
+	}
+
+	// Adds default and/or configured resource attributes (e.g. k8s.pod.uid) to resources
+	// as telemetry is emitted.
+	var consumer *enhancingConsumer
+	if consumer, err = newEnhancingConsumer(
+		obs.config.ResourceAttributes,
+		resAttrs,
+		env,
+		e,
+		obs.nextLogsConsumer,
+		obs.nextMetricsConsumer,
+		obs.nextTracesConsumer,
+	); err != nil {
+		return fmt.Errorf("failed creating resource enhancer: %w", err)
+	}
+
+	var receiver component.Component
+	if receiver, err = obs.runner.start(
+		receiverConfig{
+			id:         template.id,
+			config:     resolvedConfig,
+			endpointID: e.ID,
+		},
+		discoveredConfig,
+		consumer,
+	); err != nil {
+		return err
+	}
+
+	obs.receiversByEndpointID.Put(e.ID, receiver)
+	return nil
 }
 
 // OnRemove responds to endpoint removal notifications.
@@ -190,6 +314,10 @@ func (obs *observerHandler) OnRemove(removed []observer.Endpoint) {
 			}
 		}
 		obs.receiversByEndpointID.RemoveAll(e.ID)
+
+		if container, ok := e.Details.(*observer.Container); ok {
+			obs.removeContainerLogEndpoint(container.ContainerID, e.ID)
+		}
 	}
 }
 
@@ -419,6 +419,310 @@ func TestOnChange(t *testing.T) {
 	assert.Same(t, newRcvr, handler.receiversByEndpointID.Get("port-1")[0])
 }
 
+func TestOnAddFromAnnotations(t *testing.T) {
+	annotatedPod := pod
+	annotatedPod.Annotations = map[string]string{
+		"io.opentelemetry.discovery.metrics/scraper": "with_endpoint/some.name",
+		"io.opentelemetry.discovery.metrics/config":  "int_field: 42",
+	}
+	annotatedPortEndpoint := observer.Endpoint{
+		ID:     "port-1",
+		Target: "localhost:1234",
+		Details: &observer.Port{
+			Name:      "http",
+			Pod:       annotatedPod,
+			Port:      1234,
+			Transport: observer.ProtocolTCP,
+		},
+	}
+
+	for _, test := range []struct {
+		name                   string
+		discoveryEnabled       bool
+		templateRule           string
+		annotations            map[string]string
+		expectedReceivers      int
+		expectedReceiverConfig component.Config
+		expectedError          string
+	}{
+		{
+			name:              "discovery disabled",
+			templateRule:      `type == "pod"`,
+			expectedReceivers: 0,
+		},
+		{
+			name:              "template without rule",
+			discoveryEnabled:  true,
+			expectedReceivers: 1,
+			expectedReceiverConfig: &nopWithEndpointConfig{
+				IntField: 42,
+				Endpoint: "localhost:1234",
+			},
+		},
+		{
+			name:              "annotations take precedence over matching rule",
+			discoveryEnabled:  true,
+			templateRule:      `type == "port"`,
+			expectedReceivers: 1,
+			expectedReceiverConfig: &nopWithEndpointConfig{
+				IntField: 42,
+				Endpoint: "localhost:1234",
+			},
+		},
+		{
+			name:             "port endpoint override",
+			discoveryEnabled: true,
+			annotations: map[string]string{
+				"io.opentelemetry.discovery.metrics/scraper":     "with_endpoint/some.name",
+				"io.opentelemetry.discovery.metrics.1234/config": "endpoint: http://localhost:1234/metrics",
+			},
+			expectedReceivers: 1,
+			expectedReceiverConfig: &nopWithEndpointConfig{
+				IntField: 1234,
+				Endpoint: "http://localhost:1234/metrics",
+			},
+		},
+		{
+			name:             "endpoint override to another host",
+			discoveryEnabled: true,
+			annotations: map[string]string{
+				"io.opentelemetry.discovery.metrics/scraper": "with_endpoint/some.name",
+				"io.opentelemetry.discovery.metrics/config":  "endpoint: http://metadata.internal/localhost:1234",
+			},
+			expectedError: `endpoint http://metadata.internal/localhost:1234 must be the discovered endpoint "localhost:1234"`,
+		},
+		{
+			name:             "config key not allowed",
+			discoveryEnabled: true,
+			annotations: map[string]string{
+				"io.opentelemetry.discovery.metrics/scraper": "with_endpoint/some.name",
+				"io.opentelemetry.discovery.metrics/config":  "other_field: 1",
+			},
+			expectedError: `config key "other_field" is not allowed by discovery::allowed_config_keys`,
+		},
+		{
+			name:             "unknown template",
+			discoveryEnabled: true,
+			annotations: map[string]string{
+				"io.opentelemetry.discovery.metrics/scraper": "redis",
+			},
+			expectedError: `failed to start receiver from annotations of pod default/pod-1: receiver template "redis" is not configured`,
+		},
+		{
+			name:             "invalid config",
+			discoveryEnabled: true,
+			annotations: map[string]string{
+				"io.opentelemetry.discovery.metrics/scraper": "with_endpoint/some.name",
+				"io.opentelemetry.discovery.metrics/config":  "int_field: invalid",
+			},
+			expectedError: "failed to load \"with_endpoint/some.name\" template config",
+		},
+		{
+			name:             "invalid annotations fall back to matching rule",
+			discoveryEnabled: true,
+			templateRule:     `type == "port"`,
+			annotations: map[string]string{
+				"io.opentelemetry.discovery.metrics/scraper": "with_endpoint/some.name",
+				"io.opentelemetry.discovery.metrics/config":  "int_field: invalid",
+			},
+			expectedReceivers: 1,
+			expectedReceiverConfig: &nopWithEndpointConfig{
+				IntField: 1234,
+				Endpoint: "localhost:1234",
+			},
+			expectedError: "failed to load \"with_endpoint/some.name\" template config",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.Discovery.Enabled = test.discoveryEnabled
+			cfg.Discovery.AllowedConfigKeys = append(cfg.Discovery.AllowedConfigKeys, "int_field")
+			rcvrCfg := receiverConfig{
+				id:         component.MustNewIDWithName("with_endpoint", "some.name"),
+				config:     userConfigMap{},
+				endpointID: annotatedPortEndpoint.ID,
+			}
+			template := receiverTemplate{
+				receiverConfig:     rcvrCfg,
+				Rule:               test.templateRule,
+				ResourceAttributes: map[string]any{},
+			}
+			if test.templateRule != "" {
+				var err error
+				template.rule, err = newRule(test.templateRule)
+				require.NoError(t, err)
+			}
+			cfg.receiverTemplates = map[string]receiverTemplate{rcvrCfg.id.String(): template}
+
+			endpoint := annotatedPortEndpoint
+			if test.annotations != nil {
+				port := *endpoint.Details.(*observer.Port)
+				port.Pod.Annotations = test.annotations
+				endpoint.Details = &port
+			}
+
+			handler, mr := newObserverHandler(t, cfg, nil, consumertest.NewNop(), nil)
+			var statusEvents []*component.StatusEvent
+			handler.params.TelemetrySettings.ReportStatus = func(event *component.StatusEvent) {
+				statusEvents = append(statusEvents, event)
+			}
+			handler.OnAdd([]observer.Endpoint{endpoint})
+
+			if test.expectedError != "" {
+				require.Len(t, statusEvents, 1)
+				assert.Equal(t, component.StatusRecoverableError, statusEvents[0].Status())
+				assert.ErrorContains(t, statusEvents[0].Err(), test.expectedError)
+			} else {
+				assert.Empty(t, statusEvents)
+			}
+			assert.Equal(t, test.expectedReceivers, handler.receiversByEndpointID.Size())
+			if test.expectedReceivers == 0 {
+				return
+			}
+			wr, ok := mr.startedComponent.(*wrappedReceiver)
+			require.True(t, ok)
+			metricsReceiver, ok := wr.metrics.(*nopWithEndpointReceiver)
+			require.True(t, ok)
+			assert.Equal(t, test.expectedReceiverConfig, metricsReceiver.cfg)
+		})
+	}
+}
+
+func TestOnAddLogsFromPodAnnotations(t *testing.T) {
+	for _, test := range []struct {
+		name                   string
+		annotations            map[string]string
+		expectedReceivers      int
+		expectedReceiverConfig component.Config
+	}{
+		{
+			name: "pod receiver",
+			annotations: map[string]string{
+				"io.opentelemetry.discovery.logs/receiver": "without_endpoint/logs",
+			},
+			expectedReceivers: 1,
+			expectedReceiverConfig: &nopWithoutEndpointConfig{
+				NotEndpoint: "/var/log/pods/default_pod-1_uid-1/redis/*.log",
+				IntField:    2345,
+			},
+		},
+		{
+			name: "container config",
+			annotations: map[string]string{
+				"io.opentelemetry.discovery.logs/receiver":     "without_endpoint/logs",
+				"io.opentelemetry.discovery.logs.redis/config": "int_field: 7",
+				"io.opentelemetry.discovery.logs.nginx/config": "int_field: 8",
+			},
+			expectedReceivers: 1,
+			expectedReceiverConfig: &nopWithoutEndpointConfig{
+				NotEndpoint: "/var/log/pods/default_pod-1_uid-1/redis/*.log",
+				IntField:    7,
+			},
+		},
+		{
+			name: "container opts out",
+			annotations: map[string]string{
+				"io.opentelemetry.discovery.logs/receiver":       "without_endpoint/logs",
+				"io.opentelemetry.discovery.logs.redis/receiver": "",
+			},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.Discovery.Enabled = true
+			cfg.Discovery.AllowedConfigKeys = append(cfg.Discovery.AllowedConfigKeys, "int_field")
+			rcvrCfg := receiverConfig{
+				id:         component.MustNewIDWithName("without_endpoint", "logs"),
+				config:     userConfigMap{"not_endpoint": "`log_path`"},
+				endpointID: podContainerEndpoint.ID,
+			}
+			cfg.receiverTemplates = map[string]receiverTemplate{
+				rcvrCfg.id.String(): {receiverConfig: rcvrCfg, ResourceAttributes: map[string]any{}},
+			}
+
+			endpoint := podContainerEndpoint
+			podContainer := *endpoint.Details.(*observer.PodContainer)
+			podContainer.Pod.Annotations = test.annotations
+			endpoint.Details = &podContainer
+
+			handler, mr := newObserverHandler(t, cfg, consumertest.NewNop(), nil, nil)
+			handler.OnAdd([]observer.Endpoint{endpoint})
+
+			assert.Equal(t, test.expectedReceivers, handler.receiversByEndpointID.Size())
+			if test.expectedReceivers == 0 {
+				return
+			}
+			wr, ok := mr.startedComponent.(*wrappedReceiver)
+			require.True(t, ok)
+			logsReceiver, ok := wr.logs.(*nopWithoutEndpointReceiver)
+			require.True(t, ok)
+			assert.Equal(t, test.expectedReceiverConfig, logsReceiver.cfg)
+
+			handler.OnRemove([]observer.Endpoint{endpoint})
+			assert.Equal(t, 0, handler.receiversByEndpointID.Size())
+			assert.Equal(t, mr.startedComponent, mr.shutdownComponent)
+		})
+	}
+}
+
+func TestOnAddLogsFromContainerLabels(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Discovery.Enabled = true
+	cfg.Discovery.AllowedConfigKeys = append(cfg.Discovery.AllowedConfigKeys, "int_field")
+	rcvrCfg := receiverConfig{
+		id:     component.MustNewIDWithName("without_endpoint", "logs"),
+		config: userConfigMap{"not_endpoint": "`log_path`"},
+	}
+	cfg.receiverTemplates = map[string]receiverTemplate{
+		rcvrCfg.id.String(): {receiverConfig: rcvrCfg, ResourceAttributes: map[string]any{}},
+	}
+
+	labeled := container
+	labeled.LogPath = "/var/lib/docker/containers/abc123/abc123-json.log"
+	labeled.Labels = map[string]string{
+		"io.opentelemetry.discovery.logs/receiver": "without_endpoint/logs",
+		"io.opentelemetry.discovery.logs/config":   "int_field: 7",
+	}
+	otherPort := labeled
+	otherPort.Port = 8443
+	// The docker observer reports an endpoint for each port of the container.
+	endpoints := []observer.Endpoint{
+		{ID: "container-1:8080", Target: "localhost:8080", Details: &labeled},
+		{ID: "container-1:8443", Target: "localhost:8443", Details: &otherPort},
+	}
+
+	handler, mr := newObserverHandler(t, cfg, consumertest.NewNop(), nil, nil)
+	handler.OnAdd(endpoints)
+	require.Equal(t, 1, handler.receiversByEndpointID.Size())
+	assert.Len(t, handler.receiversByEndpointID.Get(endpoints[0].ID), 1)
+
+	wr, ok := mr.startedComponent.(*wrappedReceiver)
+	require.True(t, ok)
+	logsReceiver, ok := wr.logs.(*nopWithoutEndpointReceiver)
+	require.True(t, ok)
+	assert.Equal(t, &nopWithoutEndpointConfig{
+		NotEndpoint: "/var/lib/docker/containers/abc123/abc123-json.log",
+		IntField:    7,
+	}, logsReceiver.cfg)
+
+	// Removing another port of the container keeps collecting its logs.
+	handler.OnRemove(endpoints[1:])
+	assert.Equal(t, 1, handler.receiversByEndpointID.Size())
+
+	handler.OnAdd(endpoints[1:])
+	assert.Equal(t, 1, handler.receiversByEndpointID.Size())
+
+	// Removing the port that started the receiver hands it over to another port.
+	handler.OnRemove(endpoints[:1])
+	require.Equal(t, 1, handler.receiversByEndpointID.Size())
+	assert.Len(t, handler.receiversByEndpointID.Get(endpoints[1].ID), 1)
+	assert.Equal(t, endpoints[1].ID, handler.containerLogEndpoints[labeled.ContainerID].owner)
+
+	handler.OnRemove(endpoints[1:])
+	assert.Equal(t, 0, handler.receiversByEndpointID.Size())
+	assert.Empty(t, handler.containerLogEndpoints)
+}
+
 type mockRunner struct {
 	receiverRunner
 	startedComponent  component.Component
@@ -497,6 +801,7 @@ func newObserverHandler(
 		params:                set,
 		config:                config,
 		receiversByEndpointID: receiverMap{},
+		containerLogEndpoints: map[string]*containerLogState{},
 		runner:                mr,
 		nextLogsConsumer:      nextLogs,
 		nextMetricsConsumer:   nextMetrics,
@@ -40,6 +40,7 @@ func (rc *receiverCreator) Start(_ context.Context, host component.Host) error {
 		config:                rc.cfg,
 		params:                rc.params,
 		receiversByEndpointID: receiverMap{},
+		containerLogEndpoints: map[string]*containerLogState{},
 		nextLogsConsumer:      rc.nextLogsConsumer,
 		nextMetricsConsumer:   rc.nextMetricsConsumer,
 		nextTracesConsumer:    rc.nextTracesConsumer,
@@ -26,6 +26,7 @@ func Test_ruleEval(t *testing.T) {
 		// Doesn't work yet. See comment in newRule.
 		// {"unknown variable", args{`type == "port" && unknown_var == 1`, portEndpoint}, false, true},
 		{"basic port", args{`type == "port" && name == "http" && pod.labels["app"] == "redis"`, portEndpoint}, true, false},
+		{"basic pod container", args{`type == "pod.container" && container_name == "redis" && pod.labels["app"] == "redis"`, podContainerEndpoint}, true, false},
 		{"basic hostport", args{`type == "hostport" && port == 1234 && process_name == "splunk"`, hostportEndpoint}, true, false},
 		{"basic pod", args{`type == "pod" && labels["region"] == "west-1"`, podEndpoint}, true, false},
 		{"basic service", args{`type == "k8s.service" && labels["region"] == "west-1"`, serviceEndpoint}, true, false},
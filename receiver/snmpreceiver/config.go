@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"go.opentelemetry.io/collector/config/configopaque"
+	"go.opentelemetry.io/collector/confmap"
 	"go.opentelemetry.io/collector/receiver/scraperhelper"
 )
 
@@ -23,6 +24,7 @@ const (
 	defaultSecurityLevel      = "no_auth_no_priv"
 	defaultAuthType           = "MD5"
 	defaultPrivacyType        = "DES"
+	defaultTrapsEndpoint      = "localhost:162"
 )
 
 var (
@@ -67,7 +69,8 @@ var (
 	errEmptyPrivacyType     = errors.New("privacy_type must be specified when security_level is auth_priv")
 	errBadPrivacyType       = errors.New("privacy_type must be either DES, AES, AES192, AES192C, AES256, AES256C")
 	errEmptyPrivacyPassword = errors.New("privacy_password must be specified when security_level is auth_priv")
-	errMetricRequired       = errors.New("must have at least one config under metrics")
+	errMetricRequired       = errors.New("must have at least one config under metrics or traps")
+	errEmptyTrapsEndpoint   = errors.New("traps endpoint must be specified")
 )
 
 // Config defines the configuration for the various elements of the receiver.
@@ -131,6 +134,84 @@ type Config struct {
 	// Metrics defines what SNMP metrics will be collected for this receiver and is composed of metric
 	// names along with their metric configurations
 	Metrics map[string]*MetricConfig `mapstructure:"metrics"`
+
+	// Traps enables the listening of SNMP traps and informs, which are converted to logs.
+	// The OIDs of the ResourceAttributes, Attributes and Metrics are used to name the
+	// received variable bindings.
+	// Only valid if set, in which case Metrics may be left empty
+	Traps *TrapsConfig `mapstructure:"traps"`
+}
+
+var _ confmap.Unmarshaler = (*Config)(nil)
+
+// TrapsConfig contains config info about the listening of SNMP traps.
+// The security settings mirror the ones of the SNMP connection.
+type TrapsConfig struct {
+	// Endpoint is the UDP address on which traps are received, formatted as {host}:{port}.
+	// Default: localhost:162
+	Endpoint string `mapstructure:"endpoint"`
+
+	// Version is the version of SNMP of the received traps. Traps of versions v1 and v2c are
+	// both accepted when the version is v1 or v2c.
+	// Valid options: v1, v2c, v3.
+	// Default: v2c
+	Version string `mapstructure:"version"`
+
+	// Community is the SNMP community string of the received traps.
+	// Only valid for versions "v1" and "v2c"
+	// Default: public
+	Community string `mapstructure:"community"`
+
+	// User is the SNMP User of the received traps.
+	// Only valid for version “v3”
+	User string `mapstructure:"user"`
+
+	// SecurityLevel is the lowest security level of the received traps.
+	// Only valid for version “v3”
+	// Valid options: “no_auth_no_priv”, “auth_no_priv”, “auth_priv”
+	// Default: "no_auth_no_priv"
+	SecurityLevel string `mapstructure:"security_level"`
+
+	// AuthType is the type of authentication protocol of the received traps.
+	// Only valid for version “v3” and if “no_auth_no_priv” is not selected for SecurityLevel
+	// Valid options: “md5”, “sha”, “sha224”, “sha256”, “sha384”, “sha512”
+	// Default: "md5"
+	AuthType string `mapstructure:"auth_type"`
+
+	// AuthPassword is the authentication password of the received traps.
+	// Only valid for version "v3" and if "no_auth_no_priv" is not selected for SecurityLevel
+	AuthPassword configopaque.String `mapstructure:"auth_password"`
+
+	// PrivacyType is the type of privacy protocol of the received traps.
+	// Only valid for version “v3” and if "auth_priv" is selected for SecurityLevel
+	// Valid options: “des”, “aes”, “aes192”, “aes256”, “aes192c”, “aes256c”
+	// Default: "des"
+	PrivacyType string `mapstructure:"privacy_type"`
+
+	// PrivacyPassword is the privacy password of the received traps.
+	// Only valid for version “v3” and if "auth_priv" is selected for SecurityLevel
+	PrivacyPassword configopaque.String `mapstructure:"privacy_password"`
+}
+
+// Unmarshal a config.Parser into the config struct, setting the defaults of the traps
+// only when they are enabled.
+func (cfg *Config) Unmarshal(conf *confmap.Conf) error {
+	if conf.IsSet("traps") && cfg.Traps == nil {
+		cfg.Traps = defaultTrapsConfig()
+	}
+	return conf.Unmarshal(cfg)
+}
+
+// defaultTrapsConfig creates a TrapsConfig with as many default values as possible
+func defaultTrapsConfig() *TrapsConfig {
+	return &TrapsConfig{
+		Endpoint:      defaultTrapsEndpoint,
+		Version:       defaultVersion,
+		Community:     defaultCommunity,
+		SecurityLevel: defaultSecurityLevel,
+		AuthType:      defaultAuthType,
+		PrivacyType:   defaultPrivacyType,
+	}
 }
 
 // ResourceAttributeConfig contains config info about all of the resource attributes that will be used by this receiver.
@@ -250,6 +331,38 @@ func (cfg *Config) Validate() error {
 		combinedErr = errors.Join(combinedErr, validateSecurity(cfg))
 	}
 	combinedErr = errors.Join(combinedErr, validateMetricConfigs(cfg))
+	if cfg.Traps != nil {
+		combinedErr = errors.Join(combinedErr, validateTraps(cfg.Traps))
+	}
+
+	return combinedErr
+}
+
+// validateTraps validates the TrapsConfig, reusing the validation of the connection configs
+func validateTraps(traps *TrapsConfig) error {
+	var combinedErr error
+
+	if traps.Endpoint == "" {
+		combinedErr = errors.Join(combinedErr, errEmptyTrapsEndpoint)
+	}
+
+	connectionCfg := &Config{
+		Version:         traps.Version,
+		Community:       traps.Community,
+		User:            traps.User,
+		SecurityLevel:   traps.SecurityLevel,
+		AuthType:        traps.AuthType,
+		AuthPassword:    traps.AuthPassword,
+		PrivacyType:     traps.PrivacyType,
+		PrivacyPassword: traps.PrivacyPassword,
+	}
+	err := validateVersion(connectionCfg)
+	if err == nil && strings.ToUpper(traps.Version) == "V3" {
+		err = validateSecurity(connectionCfg)
+	}
+	if err != nil {
+		combinedErr = errors.Join(combinedErr, fmt.Errorf("traps: %w", err))
+	}
 
 	return combinedErr
 }
@@ -378,9 +491,12 @@ func validateMetricConfigs(cfg *Config) error {
 	combinedErr = errors.Join(combinedErr, validateAttributeConfigs(cfg))
 	combinedErr = errors.Join(combinedErr, validateResourceAttributeConfigs(cfg))
 
-	// Ensure there is at least one MetricConfig
+	// Ensure there is at least one MetricConfig, unless traps are listened to
 	metrics := cfg.Metrics
 	if len(metrics) == 0 {
+		if cfg.Traps != nil {
+			return combinedErr
+		}
 		return errors.Join(combinedErr, errMetricRequired)
 	}
 
@@ -265,6 +265,131 @@ func TestLoadConfigConnectionConfigs(t *testing.T) {
 	}
 }
 
+func TestLoadConfigTrapsConfigs(t *testing.T) {
+	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config.yaml"))
+	require.NoError(t, err)
+
+	factory := NewFactory()
+
+	type testCase struct {
+		name        string
+		nameVal     string
+		expectedCfg *Config
+		expectedErr string
+	}
+
+	expectedConfigDefaults := factory.CreateDefaultConfig().(*Config)
+	expectedConfigDefaults.Traps = &TrapsConfig{
+		Endpoint:      "localhost:162",
+		Version:       "v2c",
+		Community:     "public",
+		SecurityLevel: "no_auth_no_priv",
+		AuthType:      "MD5",
+		PrivacyType:   "DES",
+	}
+
+	expectedConfigV2CWithMetrics := factory.CreateDefaultConfig().(*Config)
+	expectedConfigV2CWithMetrics.Metrics = map[string]*MetricConfig{
+		"m3": {
+			Unit: "By",
+			Gauge: &GaugeMetric{
+				ValueType: "double",
+			},
+			ScalarOIDs: []ScalarOID{
+				{
+					OID: "1",
+				},
+			},
+		},
+	}
+	expectedConfigV2CWithMetrics.Traps = defaultTrapsConfig()
+	expectedConfigV2CWithMetrics.Traps.Endpoint = "0.0.0.0:1162"
+	expectedConfigV2CWithMetrics.Traps.Community = "private"
+
+	expectedConfigV3Good := factory.CreateDefaultConfig().(*Config)
+	expectedConfigV3Good.Traps = &TrapsConfig{
+		Endpoint:        "0.0.0.0:162",
+		Version:         "v3",
+		Community:       "public",
+		User:            "u",
+		SecurityLevel:   "auth_priv",
+		AuthType:        "SHA",
+		AuthPassword:    "p",
+		PrivacyType:     "AES",
+		PrivacyPassword: "pp",
+	}
+
+	expectedConfigNoEndpoint := factory.CreateDefaultConfig().(*Config)
+	expectedConfigNoEndpoint.Traps = defaultTrapsConfig()
+	expectedConfigNoEndpoint.Traps.Endpoint = ""
+
+	expectedConfigBadVersion := factory.CreateDefaultConfig().(*Config)
+	expectedConfigBadVersion.Traps = defaultTrapsConfig()
+	expectedConfigBadVersion.Traps.Version = "9999"
+
+	expectedConfigV3NoUser := factory.CreateDefaultConfig().(*Config)
+	expectedConfigV3NoUser.Traps = defaultTrapsConfig()
+	expectedConfigV3NoUser.Traps.Version = "v3"
+	expectedConfigV3NoUser.Traps.SecurityLevel = "auth_no_priv"
+	expectedConfigV3NoUser.Traps.AuthPassword = "p"
+
+	testCases := []testCase{
+		{
+			name:        "NoTrapsSettingsUsesDefaults",
+			nameVal:     "traps_defaults",
+			expectedCfg: expectedConfigDefaults,
+			expectedErr: "",
+		},
+		{
+			name:        "GoodV2CTrapsWithMetricsNoErrors",
+			nameVal:     "traps_v2c_with_metrics",
+			expectedCfg: expectedConfigV2CWithMetrics,
+			expectedErr: "",
+		},
+		{
+			name:        "GoodV3TrapsNoErrors",
+			nameVal:     "traps_v3_good",
+			expectedCfg: expectedConfigV3Good,
+			expectedErr: "",
+		},
+		{
+			name:        "NoTrapsEndpointErrors",
+			nameVal:     "traps_no_endpoint",
+			expectedCfg: expectedConfigNoEndpoint,
+			expectedErr: errEmptyTrapsEndpoint.Error(),
+		},
+		{
+			name:        "BadTrapsVersionErrors",
+			nameVal:     "traps_bad_version",
+			expectedCfg: expectedConfigBadVersion,
+			expectedErr: "traps: " + errBadVersion.Error(),
+		},
+		{
+			name:        "V3TrapsNoUserErrors",
+			nameVal:     "traps_v3_no_user",
+			expectedCfg: expectedConfigV3NoUser,
+			expectedErr: "traps: " + errEmptyUser.Error(),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			sub, err := cm.Sub(component.NewIDWithName(metadata.Type, test.nameVal).String())
+			require.NoError(t, err)
+
+			cfg := factory.CreateDefaultConfig()
+			require.NoError(t, component.UnmarshalConfig(sub, cfg))
+			if test.expectedErr == "" {
+				require.NoError(t, component.ValidateConfig(cfg))
+			} else {
+				require.ErrorContains(t, component.ValidateConfig(cfg), test.expectedErr)
+			}
+
+			require.Equal(t, test.expectedCfg, cfg)
+		})
+	}
+}
+
 func getBaseMetricConfig(gauge bool, scalar bool) map[string]*MetricConfig {
 	metricCfg := map[string]*MetricConfig{
 		"m3": {
@@ -0,0 +1,408 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package snmpreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/snmpreceiver"
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gosnmp/gosnmp"
+	collectorclient "go.opentelemetry.io/collector/client"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/receiver"
+	"go.opentelemetry.io/collector/receiver/receiverhelper"
+	"go.uber.org/zap"
+)
+
+const (
+	trapsScopeName = "otelcol/snmpreceiver"
+
+	// The format of the received data reported by the receiver observability.
+	trapsDataFormat = "snmp_trap"
+
+	attributeTrapOID       = "snmp.trap.oid"
+	attributeTrapVersion   = "snmp.version"
+	attributeTrapUptime    = "snmp.trap.uptime"
+	attributeTrapIndex     = "snmp.index"
+	attributeAgentAddress  = "snmp.agent.address"
+	attributeVarbindPrefix = "snmp.varbind."
+
+	// The OIDs of the variable bindings identifying SNMPv2 traps (RFC 3416).
+	oidSysUpTime   = ".1.3.6.1.2.1.1.3.0"
+	oidSnmpTrapOID = ".1.3.6.1.6.3.1.1.4.1.0"
+	// The OID of the generic traps (RFC 3584).
+	oidSnmpTraps = ".1.3.6.1.6.3.1.1.5"
+
+	// The time given to the listener to close its socket.
+	trapsCloseTimeout = 5 * time.Second
+)
+
+var errTrapsNotConfigured = errors.New("traps must be configured to create a logs receiver")
+
+// trapsReceiver listens to SNMP traps and informs, converting each of them to
+// a log record.
+type trapsReceiver struct {
+	cfg          *Config
+	settings     receiver.CreateSettings
+	nextConsumer consumer.Logs
+	obsrecv      *receiverhelper.ObsReport
+	names        *oidNames
+
+	listener *gosnmp.TrapListener
+	wg       sync.WaitGroup
+}
+
+// newTrapsReceiver creates the receiver of the SNMP traps, relying on the
+// config being validated.
+func newTrapsReceiver(set receiver.CreateSettings, cfg *Config, nextConsumer consumer.Logs) (*trapsReceiver, error) {
+	if cfg.Traps == nil {
+		return nil, errTrapsNotConfigured
+	}
+
+	obsrecv, err := receiverhelper.NewObsReport(receiverhelper.ObsReportSettings{
+		ReceiverID:             set.ID,
+		Transport:              "udp",
+		ReceiverCreateSettings: set,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &trapsReceiver{
+		cfg:          cfg,
+		settings:     set,
+		nextConsumer: nextConsumer,
+		obsrecv:      obsrecv,
+		names:        newOIDNames(cfg),
+	}, nil
+}
+
+func (r *trapsReceiver) Start(_ context.Context, _ component.Host) error {
+	listener := gosnmp.NewTrapListener()
+	listener.Params = newTrapsParams(r.cfg.Traps)
+	listener.CloseTimeout = trapsCloseTimeout
+	listener.OnNewTrap = r.handleTrap
+
+	errs := make(chan error, 1)
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		errs <- listener.Listen(r.cfg.Traps.Endpoint)
+	}()
+
+	// The listener reports whether it is listening, or fails to listen.
+	select {
+	case <-listener.Listening():
+		r.listener = listener
+		return nil
+	case err := <-errs:
+		return err
+	}
+}
+
+func (r *trapsReceiver) Shutdown(context.Context) error {
+	if r.listener == nil {
+		return nil
+	}
+	r.listener.Close()
+	r.wg.Wait()
+	return nil
+}
+
+// newTrapsParams creates the gosnmp parameters used to decode the traps.
+func newTrapsParams(traps *TrapsConfig) *gosnmp.GoSNMP {
+	params := &gosnmp.GoSNMP{
+		Community: traps.Community,
+		// The trap listener sends responses to informs.
+		Timeout: defaultTimeout,
+		Retries: gosnmp.Default.Retries,
+		MaxOids: gosnmp.MaxOids,
+	}
+	switch strings.ToUpper(traps.Version) {
+	case "V3":
+		params.Version = gosnmp.Version3
+		params.SecurityModel = gosnmp.UserSecurityModel
+		securityParams := &gosnmp.UsmSecurityParameters{
+			UserName: traps.User,
+		}
+		params.MsgFlags = trapsMsgFlags(traps)
+		if params.MsgFlags&gosnmp.AuthNoPriv != 0 {
+			securityParams.AuthenticationProtocol = getAuthProtocol(traps.AuthType)
+			securityParams.AuthenticationPassphrase = string(traps.AuthPassword)
+		}
+		if params.MsgFlags&gosnmp.AuthPriv == gosnmp.AuthPriv {
+			securityParams.PrivacyProtocol = getPrivacyProtocol(traps.PrivacyType)
+			securityParams.PrivacyPassphrase = string(traps.PrivacyPassword)
+		}
+		params.SecurityParameters = securityParams
+	case "V1":
+		params.Version = gosnmp.Version1
+	default:
+		params.Version = gosnmp.Version2c
+	}
+	return params
+}
+
+// trapsMsgFlags returns the message flags matching the security level of the
+// traps.
+func trapsMsgFlags(traps *TrapsConfig) gosnmp.SnmpV3MsgFlags {
+	switch strings.ToUpper(traps.SecurityLevel) {
+	case "AUTH_NO_PRIV":
+		return gosnmp.AuthNoPriv
+	case "AUTH_PRIV":
+		return gosnmp.AuthPriv
+	default:
+		return gosnmp.NoAuthNoPriv
+	}
+}
+
+// handleTrap converts an authenticated trap to logs and passes them to the
+// next consumer. It is called by the listener for every decoded trap.
+func (r *trapsReceiver) handleTrap(packet *gosnmp.SnmpPacket, addr *net.UDPAddr) {
+	if reason := r.rejectTrap(packet); reason != "" {
+		r.settings.Logger.Debug("Dropping SNMP trap", zap.Stringer("agent", addr), zap.String("reason", reason))
+		return
+	}
+
+	logs := r.newLogs(packet, addr, time.Now())
+	ctx := collectorclient.NewContext(context.Background(), collectorclient.Info{Addr: addr})
+	obsCtx := r.obsrecv.StartLogsOp(ctx)
+	err := r.nextConsumer.ConsumeLogs(obsCtx, logs)
+	r.obsrecv.EndLogsOp(obsCtx, trapsDataFormat, logs.LogRecordCount(), err)
+}
+
+// rejectTrap returns why a trap is rejected, or an empty string if it is
+// accepted. The listener decodes the traps without checking their community
+// nor the user and the security level of SNMPv3 traps.
+func (r *trapsReceiver) rejectTrap(packet *gosnmp.SnmpPacket) string {
+	traps := r.cfg.Traps
+	if strings.ToUpper(traps.Version) == "V3" {
+		if packet.Version != gosnmp.Version3 {
+			return "unexpected version v" + packet.Version.String()
+		}
+		usm, ok := packet.SecurityParameters.(*gosnmp.UsmSecurityParameters)
+		if !ok || usm.UserName != traps.User {
+			return "unknown user"
+		}
+		flags := trapsMsgFlags(traps)
+		if packet.MsgFlags&flags != flags {
+			return "insufficient security level"
+		}
+		return ""
+	}
+
+	if packet.Version == gosnmp.Version3 {
+		return "unexpected version v" + packet.Version.String()
+	}
+	if packet.Community != traps.Community {
+		return "unknown community"
+	}
+	return ""
+}
+
+// newLogs converts a trap to a log record, under a resource describing the
+// agent which sent it.
+func (r *trapsReceiver) newLogs(packet *gosnmp.SnmpPacket, addr *net.UDPAddr, now time.Time) plog.Logs {
+	logs := plog.NewLogs()
+	rl := logs.ResourceLogs().AppendEmpty()
+	resource := rl.Resource().Attributes()
+	agent := addr.IP.String()
+	if packet.Version == gosnmp.Version1 && packet.AgentAddress != "" && packet.AgentAddress != "0.0.0.0" {
+		agent = packet.AgentAddress
+	}
+	resource.PutStr(attributeAgentAddress, agent)
+
+	sl := rl.ScopeLogs().AppendEmpty()
+	sl.Scope().SetName(trapsScopeName)
+	record := sl.LogRecords().AppendEmpty()
+	record.SetObservedTimestamp(pcommon.NewTimestampFromTime(now))
+	record.SetTimestamp(pcommon.NewTimestampFromTime(now))
+
+	attrs := record.Attributes()
+	attrs.PutStr(attributeTrapVersion, "v"+packet.Version.String())
+	if packet.Version == gosnmp.Version1 {
+		attrs.PutStr(attributeTrapOID, v1TrapOID(packet.SnmpTrap))
+		attrs.PutInt(attributeTrapUptime, int64(packet.Timestamp))
+	}
+
+	for _, pdu := range packet.Variables {
+		oid := pdu.Name
+		if !strings.HasPrefix(oid, ".") {
+			oid = "." + oid
+		}
+
+		switch oid {
+		case oidSysUpTime:
+			if uptime, ok := pduValue(pdu); ok && uptime.Type() == pcommon.ValueTypeInt {
+				attrs.PutInt(attributeTrapUptime, uptime.Int())
+			}
+			continue
+		case oidSnmpTrapOID:
+			attrs.PutStr(attributeTrapOID, strings.TrimPrefix(toString(pdu.Value), "."))
+			continue
+		}
+
+		value, ok := pduValue(pdu)
+		if !ok {
+			r.settings.Logger.Debug("Skipping SNMP trap variable binding of unsupported type",
+				zap.String("oid", oid), zap.Stringer("type", pdu.Type))
+			continue
+		}
+
+		name, index, isResource := r.names.resolve(oid)
+		target := attrs
+		if isResource {
+			target = resource
+		}
+		if name == "" {
+			name = attributeVarbindPrefix + strings.TrimPrefix(oid, ".")
+		} else if _, exists := target.Get(name); exists {
+			// Indexed variable bindings of several rows of a table keep their OID.
+			name = attributeVarbindPrefix + strings.TrimPrefix(oid, ".")
+		} else if index != "" && !isResource {
+			if _, exists := attrs.Get(attributeTrapIndex); !exists {
+				attrs.PutStr(attributeTrapIndex, index)
+			}
+		}
+		value.CopyTo(target.PutEmpty(name))
+	}
+	return logs
+}
+
+// v1TrapOID returns the trap OID of an SNMPv1 trap, as it is translated to an
+// SNMPv2 trap (RFC 3584, section 3.1).
+func v1TrapOID(trap gosnmp.SnmpTrap) string {
+	if trap.GenericTrap != 6 { // enterpriseSpecific
+		return strings.TrimPrefix(oidSnmpTraps, ".") + "." + strconv.Itoa(trap.GenericTrap+1)
+	}
+	return strings.TrimPrefix(trap.Enterprise, ".") + ".0." + strconv.Itoa(trap.SpecificTrap)
+}
+
+// pduValue converts the value of a variable binding to an attribute value.
+// Octet strings which are not valid UTF-8, like MAC addresses, are kept as
+// bytes.
+func pduValue(pdu gosnmp.SnmpPDU) (pcommon.Value, bool) {
+	switch pdu.Type { // nolint:exhaustive
+	case gosnmp.Counter64, gosnmp.Counter32, gosnmp.Gauge32, gosnmp.Uinteger32, gosnmp.TimeTicks, gosnmp.Integer:
+		return pcommon.NewValueInt(gosnmp.ToBigInt(pdu.Value).Int64()), true
+	case gosnmp.OctetString:
+		if b, ok := pdu.Value.([]byte); ok && !utf8.Valid(b) {
+			value := pcommon.NewValueBytes()
+			value.Bytes().FromRaw(b)
+			return value, true
+		}
+		return pcommon.NewValueStr(toString(pdu.Value)), true
+	case gosnmp.IPAddress:
+		return pcommon.NewValueStr(toString(pdu.Value)), true
+	case gosnmp.ObjectIdentifier:
+		return pcommon.NewValueStr(strings.TrimPrefix(toString(pdu.Value), ".")), true
+	case gosnmp.OpaqueFloat:
+		if f, ok := pdu.Value.(float32); ok {
+			return pcommon.NewValueDouble(float64(f)), true
+		}
+	case gosnmp.OpaqueDouble:
+		if f, ok := pdu.Value.(float64); ok {
+			return pcommon.NewValueDouble(f), true
+		}
+	}
+	return pcommon.Value{}, false
+}
+
+// oidNames resolves the OIDs of the variable bindings of the traps to the
+// names of the metrics, attributes and resource attributes configured with
+// these OIDs.
+type oidNames struct {
+	scalars map[string]oidName
+	// columns are sorted by decreasing OID length, so that the longest
+	// matching column wins.
+	columns []columnName
+}
+
+type oidName struct {
+	name     string
+	resource bool
+}
+
+type columnName struct {
+	oid string
+	oidName
+}
+
+// newOIDNames collects the OIDs of the config, which are matched exactly for
+// scalar OIDs, and as prefixes of the indexed OIDs for column OIDs.
+func newOIDNames(cfg *Config) *oidNames {
+	n := &oidNames{scalars: map[string]oidName{}}
+	addColumn := func(oid string, name oidName) {
+		n.columns = append(n.columns, columnName{oid: normalizeOID(oid), oidName: name})
+	}
+
+	for name, metricCfg := range cfg.Metrics {
+		for _, scalarOID := range metricCfg.ScalarOIDs {
+			n.scalars[normalizeOID(scalarOID.OID)] = oidName{name: name}
+		}
+		for _, columnOID := range metricCfg.ColumnOIDs {
+			addColumn(columnOID.OID, oidName{name: name})
+		}
+	}
+	for name, attributeCfg := range cfg.Attributes {
+		if attributeCfg.OID == "" {
+			continue
+		}
+		// The value is the key of the attribute, if it is set.
+		if attributeCfg.Value != "" {
+			name = attributeCfg.Value
+		}
+		addColumn(attributeCfg.OID, oidName{name: name})
+	}
+	for name, resourceAttributeCfg := range cfg.ResourceAttributes {
+		if resourceAttributeCfg.ScalarOID != "" {
+			n.scalars[normalizeOID(resourceAttributeCfg.ScalarOID)] = oidName{name: name, resource: true}
+		}
+		if resourceAttributeCfg.OID != "" {
+			addColumn(resourceAttributeCfg.OID, oidName{name: name, resource: true})
+		}
+	}
+
+	sort.Slice(n.columns, func(i, j int) bool {
+		if len(n.columns[i].oid) != len(n.columns[j].oid) {
+			return len(n.columns[i].oid) > len(n.columns[j].oid)
+		}
+		return n.columns[i].oid < n.columns[j].oid
+	})
+	return n
+}
+
+// resolve returns the name of an OID, the index of the OID if it belongs to
+// a column, and whether it is the name of a resource attribute. The name is
+// empty if the OID is unknown.
+func (n *oidNames) resolve(oid string) (name string, index string, resource bool) {
+	if scalar, ok := n.scalars[oid]; ok {
+		return scalar.name, "", scalar.resource
+	}
+	for _, column := range n.columns {
+		if strings.HasPrefix(oid, column.oid+".") {
+			return column.name, strings.TrimPrefix(oid, column.oid+"."), column.resource
+		}
+	}
+	return "", "", false
+}
+
+// normalizeOID prefixes an OID with a dot, as the OIDs of the received
+// variable bindings are.
+func normalizeOID(oid string) string {
+	if strings.HasPrefix(oid, ".") {
+		return oid
+	}
+	return "." + oid
+}
@@ -0,0 +1,420 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package snmpreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/snmpreceiver"
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+)
+
+const (
+	testEngineID = "\x80\x00\x00\x00\x01\x02\x03\x04"
+
+	oidIfIndex       = ".1.3.6.1.2.1.2.2.1.1"
+	oidIfDescr       = ".1.3.6.1.2.1.2.2.1.2"
+	oidIfAdminStatus = ".1.3.6.1.2.1.2.2.1.7"
+	oidIfOperStatus  = ".1.3.6.1.2.1.2.2.1.8"
+	oidSysName       = ".1.3.6.1.2.1.1.5.0"
+	oidLinkDown      = ".1.3.6.1.6.3.1.1.5.3"
+)
+
+// linkDownVariables are the variable bindings of a linkDown trap of the
+// interface 3, as sent by SNMPv2 agents.
+var linkDownVariables = []gosnmp.SnmpPDU{
+	{Name: oidSysUpTime, Type: gosnmp.TimeTicks, Value: uint32(123456)},
+	{Name: oidSnmpTrapOID, Type: gosnmp.ObjectIdentifier, Value: oidLinkDown},
+	{Name: oidIfIndex + ".3", Type: gosnmp.Integer, Value: 3},
+	{Name: oidIfAdminStatus + ".3", Type: gosnmp.Integer, Value: 1},
+	{Name: oidIfOperStatus + ".3", Type: gosnmp.Integer, Value: 2},
+	{Name: oidIfDescr + ".3", Type: gosnmp.OctetString, Value: "eth2"},
+	{Name: oidSysName, Type: gosnmp.OctetString, Value: "switch-1"},
+	{Name: ".1.3.6.1.4.1.9.9.41.1.2.3.1.2.7", Type: gosnmp.OctetString, Value: []byte{0x00, 0x1b, 0xfe}},
+	{Name: ".1.3.6.1.4.1.9.9.41.1.2.3.1.3.7", Type: gosnmp.Gauge32, Value: uint(42)},
+	{Name: ".1.3.6.1.4.1.9.9.41.1.2.3.1.4.7", Type: gosnmp.IPAddress, Value: "10.0.0.1"},
+}
+
+func TestTrapsReceiver(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		traps   TrapsConfig
+		sender  gosnmp.GoSNMP
+		trap    gosnmp.SnmpTrap
+		dropped bool
+		// expectedAttributes are the attributes of the log record, when the
+		// trap is not dropped.
+		expectedAttributes map[string]any
+	}{
+		{
+			desc:  "v2c trap",
+			traps: TrapsConfig{Version: "v2c", Community: "public"},
+			sender: gosnmp.GoSNMP{
+				Version:   gosnmp.Version2c,
+				Community: "public",
+			},
+			trap: gosnmp.SnmpTrap{Variables: linkDownVariables},
+			expectedAttributes: map[string]any{
+				"snmp.version":                       "v2c",
+				"snmp.trap.oid":                      "1.3.6.1.6.3.1.1.5.3",
+				"snmp.trap.uptime":                   int64(123456),
+				"snmp.index":                         "3",
+				"snmp.varbind.1.3.6.1.2.1.2.2.1.1.3": int64(3),
+				"snmp.varbind.1.3.6.1.2.1.2.2.1.7.3": int64(1),
+				"if.oper.status":                     int64(2),
+				"interface":                          "eth2",
+				"snmp.varbind.1.3.6.1.4.1.9.9.41.1.2.3.1.2.7": []byte{0x00, 0x1b, 0xfe},
+				"snmp.varbind.1.3.6.1.4.1.9.9.41.1.2.3.1.3.7": int64(42),
+				"snmp.varbind.1.3.6.1.4.1.9.9.41.1.2.3.1.4.7": "10.0.0.1",
+			},
+		},
+		{
+			desc:  "v2c inform",
+			traps: TrapsConfig{Version: "v2c", Community: "public"},
+			sender: gosnmp.GoSNMP{
+				Version:   gosnmp.Version2c,
+				Community: "public",
+			},
+			trap: gosnmp.SnmpTrap{Variables: linkDownVariables[:2], IsInform: true},
+			expectedAttributes: map[string]any{
+				"snmp.version":     "v2c",
+				"snmp.trap.oid":    "1.3.6.1.6.3.1.1.5.3",
+				"snmp.trap.uptime": int64(123456),
+			},
+		},
+		{
+			desc:  "v1 trap",
+			traps: TrapsConfig{Version: "v1", Community: "public"},
+			sender: gosnmp.GoSNMP{
+				Version:   gosnmp.Version1,
+				Community: "public",
+			},
+			trap: gosnmp.SnmpTrap{
+				Variables:    linkDownVariables[4:5],
+				Enterprise:   ".1.3.6.1.4.1.9",
+				AgentAddress: "192.0.2.1",
+				GenericTrap:  6,
+				SpecificTrap: 1,
+				Timestamp:    300,
+			},
+			expectedAttributes: map[string]any{
+				"snmp.version":     "v1",
+				"snmp.trap.oid":    "1.3.6.1.4.1.9.0.1",
+				"snmp.trap.uptime": int64(300),
+				"snmp.index":       "3",
+				"if.oper.status":   int64(2),
+			},
+		},
+		{
+			desc:  "v2c trap with an unknown community",
+			traps: TrapsConfig{Version: "v2c", Community: "public"},
+			sender: gosnmp.GoSNMP{
+				Version:   gosnmp.Version2c,
+				Community: "private",
+			},
+			trap:    gosnmp.SnmpTrap{Variables: linkDownVariables},
+			dropped: true,
+		},
+		{
+			desc:  "v2c trap when expecting v3",
+			traps: TrapsConfig{Version: "v3", User: "u", SecurityLevel: "no_auth_no_priv"},
+			sender: gosnmp.GoSNMP{
+				Version:   gosnmp.Version2c,
+				Community: "public",
+			},
+			trap:    gosnmp.SnmpTrap{Variables: linkDownVariables},
+			dropped: true,
+		},
+		{
+			desc: "v3 auth_priv trap",
+			traps: TrapsConfig{
+				Version:         "v3",
+				User:            "u",
+				SecurityLevel:   "auth_priv",
+				AuthType:        "SHA",
+				AuthPassword:    "authpassword",
+				PrivacyType:     "AES",
+				PrivacyPassword: "privpassword",
+			},
+			sender: gosnmp.GoSNMP{
+				Version:       gosnmp.Version3,
+				SecurityModel: gosnmp.UserSecurityModel,
+				MsgFlags:      gosnmp.AuthPriv,
+				SecurityParameters: &gosnmp.UsmSecurityParameters{
+					UserName:                 "u",
+					AuthenticationProtocol:   gosnmp.SHA,
+					AuthenticationPassphrase: "authpassword",
+					PrivacyProtocol:          gosnmp.AES,
+					PrivacyPassphrase:        "privpassword",
+					AuthoritativeEngineID:    testEngineID,
+					AuthoritativeEngineBoots: 1,
+					AuthoritativeEngineTime:  1,
+				},
+			},
+			trap: gosnmp.SnmpTrap{Variables: linkDownVariables[:2]},
+			expectedAttributes: map[string]any{
+				"snmp.version":     "v3",
+				"snmp.trap.oid":    "1.3.6.1.6.3.1.1.5.3",
+				"snmp.trap.uptime": int64(123456),
+			},
+		},
+		{
+			desc: "v3 trap with an unknown user",
+			traps: TrapsConfig{
+				Version:       "v3",
+				User:          "u",
+				SecurityLevel: "auth_no_priv",
+				AuthType:      "SHA",
+				AuthPassword:  "authpassword",
+			},
+			sender: gosnmp.GoSNMP{
+				Version:       gosnmp.Version3,
+				SecurityModel: gosnmp.UserSecurityModel,
+				MsgFlags:      gosnmp.AuthNoPriv,
+				SecurityParameters: &gosnmp.UsmSecurityParameters{
+					UserName:                 "other",
+					AuthenticationProtocol:   gosnmp.SHA,
+					AuthenticationPassphrase: "authpassword",
+					AuthoritativeEngineID:    testEngineID,
+					AuthoritativeEngineBoots: 1,
+					AuthoritativeEngineTime:  1,
+				},
+			},
+			trap:    gosnmp.SnmpTrap{Variables: linkDownVariables[:2]},
+			dropped: true,
+		},
+		{
+			desc: "v3 trap with an insufficient security level",
+			traps: TrapsConfig{
+				Version:         "v3",
+				User:            "u",
+				SecurityLevel:   "auth_priv",
+				AuthType:        "SHA",
+				AuthPassword:    "authpassword",
+				PrivacyType:     "AES",
+				PrivacyPassword: "privpassword",
+			},
+			sender: gosnmp.GoSNMP{
+				Version:       gosnmp.Version3,
+				SecurityModel: gosnmp.UserSecurityModel,
+				MsgFlags:      gosnmp.NoAuthNoPriv,
+				SecurityParameters: &gosnmp.UsmSecurityParameters{
+					UserName:                 "u",
+					AuthoritativeEngineID:    testEngineID,
+					AuthoritativeEngineBoots: 1,
+					AuthoritativeEngineTime:  1,
+				},
+			},
+			trap:    gosnmp.SnmpTrap{Variables: linkDownVariables[:2]},
+			dropped: true,
+		},
+		{
+			desc: "v3 trap with a wrong password",
+			traps: TrapsConfig{
+				Version:       "v3",
+				User:          "u",
+				SecurityLevel: "auth_no_priv",
+				AuthType:      "SHA",
+				AuthPassword:  "authpassword",
+			},
+			sender: gosnmp.GoSNMP{
+				Version:       gosnmp.Version3,
+				SecurityModel: gosnmp.UserSecurityModel,
+				MsgFlags:      gosnmp.AuthNoPriv,
+				SecurityParameters: &gosnmp.UsmSecurityParameters{
+					UserName:                 "u",
+					AuthenticationProtocol:   gosnmp.SHA,
+					AuthenticationPassphrase: "wrongpassword",
+					AuthoritativeEngineID:    testEngineID,
+					AuthoritativeEngineBoots: 1,
+					AuthoritativeEngineTime:  1,
+				},
+			},
+			trap:    gosnmp.SnmpTrap{Variables: linkDownVariables[:2]},
+			dropped: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			cfg := newTrapsTestConfig(t)
+			traps := tc.traps
+			traps.Endpoint = cfg.Traps.Endpoint
+			cfg.Traps = &traps
+			require.NoError(t, cfg.Validate())
+
+			sink := new(consumertest.LogsSink)
+			rcv, err := NewFactory().CreateLogsReceiver(context.Background(), receivertest.NewNopCreateSettings(), cfg, sink)
+			require.NoError(t, err)
+			require.NoError(t, rcv.Start(context.Background(), nil))
+			t.Cleanup(func() {
+				require.NoError(t, rcv.Shutdown(context.Background()))
+			})
+
+			sender := tc.sender
+			sendTrap(t, cfg.Traps.Endpoint, &sender, tc.trap)
+
+			if tc.dropped {
+				// The traps are handled in order, the dropped trap would be
+				// received before an accepted one, which has no trap OID.
+				accepted := newTrapsParams(cfg.Traps)
+				if accepted.Version == gosnmp.Version3 {
+					accepted.SecurityParameters = tc.traps.senderSecurityParameters()
+				}
+				sendTrap(t, cfg.Traps.Endpoint, accepted, gosnmp.SnmpTrap{Variables: linkDownVariables[:1]})
+				require.Eventually(t, func() bool { return sink.LogRecordCount() > 0 }, 5*time.Second, 10*time.Millisecond)
+				record := sink.AllLogs()[0].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0)
+				_, ok := record.Attributes().Get("snmp.trap.oid")
+				assert.False(t, ok, "the dropped trap was received")
+				return
+			}
+
+			require.Eventually(t, func() bool { return sink.LogRecordCount() > 0 }, 5*time.Second, 10*time.Millisecond)
+			require.Len(t, sink.AllLogs(), 1)
+			logs := sink.AllLogs()[0]
+			require.Equal(t, 1, logs.LogRecordCount())
+
+			rl := logs.ResourceLogs().At(0)
+			expectedResource := map[string]any{"snmp.agent.address": "127.0.0.1"}
+			if tc.trap.AgentAddress != "" {
+				expectedResource["snmp.agent.address"] = tc.trap.AgentAddress
+			}
+			if len(tc.trap.Variables) > 6 {
+				expectedResource["host.name"] = "switch-1"
+			}
+			assert.Equal(t, expectedResource, rl.Resource().Attributes().AsRaw())
+
+			sl := rl.ScopeLogs().At(0)
+			assert.Equal(t, trapsScopeName, sl.Scope().Name())
+			record := sl.LogRecords().At(0)
+			assert.NotZero(t, record.ObservedTimestamp())
+			assert.Equal(t, tc.expectedAttributes, record.Attributes().AsRaw())
+		})
+	}
+}
+
+func TestTrapsReceiverStartError(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Traps = defaultTrapsConfig()
+	cfg.Traps.Endpoint = conn.LocalAddr().String()
+
+	rcv, err := NewFactory().CreateLogsReceiver(context.Background(), receivertest.NewNopCreateSettings(), cfg, consumertest.NewNop())
+	require.NoError(t, err)
+	require.Error(t, rcv.Start(context.Background(), nil))
+	require.NoError(t, rcv.Shutdown(context.Background()))
+}
+
+func TestV1TrapOID(t *testing.T) {
+	assert.Equal(t, "1.3.6.1.6.3.1.1.5.3", v1TrapOID(gosnmp.SnmpTrap{Enterprise: ".1.3.6.1.4.1.9", GenericTrap: 2}))
+	assert.Equal(t, "1.3.6.1.6.3.1.1.5.1", v1TrapOID(gosnmp.SnmpTrap{GenericTrap: 0}))
+	assert.Equal(t, "1.3.6.1.4.1.9.0.42", v1TrapOID(gosnmp.SnmpTrap{Enterprise: ".1.3.6.1.4.1.9", GenericTrap: 6, SpecificTrap: 42}))
+}
+
+func TestOIDNames(t *testing.T) {
+	cfg := newTrapsTestConfig(t)
+	cfg.Attributes["if.name.prefix"] = &AttributeConfig{IndexedValuePrefix: "if"}
+	cfg.Metrics["sys.uptime"] = &MetricConfig{
+		Unit:       "s",
+		Gauge:      &GaugeMetric{ValueType: "int"},
+		ScalarOIDs: []ScalarOID{{OID: "1.3.6.1.2.1.1.3.0"}},
+	}
+	names := newOIDNames(cfg)
+
+	testCases := []struct {
+		oid              string
+		expectedName     string
+		expectedIndex    string
+		expectedResource bool
+	}{
+		{oid: ".1.3.6.1.2.1.1.3.0", expectedName: "sys.uptime"},
+		{oid: oidSysName, expectedName: "host.name", expectedResource: true},
+		{oid: oidIfOperStatus + ".12", expectedName: "if.oper.status", expectedIndex: "12"},
+		{oid: oidIfDescr + ".1.2", expectedName: "interface", expectedIndex: "1.2"},
+		// Column OIDs only match whole sub-identifiers.
+		{oid: oidIfDescr + "0.1"},
+		{oid: oidIfOperStatus},
+		{oid: ".1.3.6.1.4.1.9"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.oid, func(t *testing.T) {
+			name, index, resource := names.resolve(tc.oid)
+			assert.Equal(t, tc.expectedName, name)
+			assert.Equal(t, tc.expectedIndex, index)
+			assert.Equal(t, tc.expectedResource, resource)
+		})
+	}
+}
+
+// newTrapsTestConfig returns a config listening to traps on an available
+// port, naming some of the variable bindings of the linkDown traps.
+func newTrapsTestConfig(t *testing.T) *Config {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	endpoint := conn.LocalAddr().String()
+	require.NoError(t, conn.Close())
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Traps = defaultTrapsConfig()
+	cfg.Traps.Endpoint = endpoint
+	cfg.ResourceAttributes = map[string]*ResourceAttributeConfig{
+		"host.name": {ScalarOID: oidSysName},
+	}
+	cfg.Attributes = map[string]*AttributeConfig{
+		"if.descr": {Value: "interface", OID: oidIfDescr},
+	}
+	cfg.Metrics = map[string]*MetricConfig{
+		"if.oper.status": {
+			Unit:  "1",
+			Gauge: &GaugeMetric{ValueType: "int"},
+			ColumnOIDs: []ColumnOID{{
+				OID:        oidIfOperStatus,
+				Attributes: []Attribute{{Name: "if.descr"}},
+			}},
+		},
+	}
+	return cfg
+}
+
+// senderSecurityParameters returns the security parameters of an agent
+// sending the traps accepted by the config.
+func (traps TrapsConfig) senderSecurityParameters() *gosnmp.UsmSecurityParameters {
+	params := newTrapsParams(&traps).SecurityParameters.(*gosnmp.UsmSecurityParameters)
+	params.AuthoritativeEngineID = testEngineID
+	params.AuthoritativeEngineBoots = 1
+	params.AuthoritativeEngineTime = 1
+	return params
+}
+
+func sendTrap(t *testing.T, endpoint string, sender *gosnmp.GoSNMP, trap gosnmp.SnmpTrap) {
+	host, port, err := net.SplitHostPort(endpoint)
+	require.NoError(t, err)
+	portNumber, err := strconv.ParseUint(port, 10, 16)
+	require.NoError(t, err)
+
+	sender.Target = host
+	sender.Port = uint16(portNumber)
+	sender.Timeout = 2 * time.Second
+	sender.Retries = 1
+	sender.MaxOids = gosnmp.MaxOids
+	require.NoError(t, sender.Connect())
+	defer sender.Conn.Close()
+
+	result, err := sender.SendTrap(trap)
+	require.NoError(t, err)
+	if trap.IsInform {
+		require.NotNil(t, result)
+		assert.Equal(t, gosnmp.GetResponse, result.PDUType)
+	}
+}
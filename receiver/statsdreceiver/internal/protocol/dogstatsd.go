@@ -0,0 +1,281 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package protocol // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/statsdreceiver/internal/protocol"
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/client"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	semconv "go.opentelemetry.io/collector/semconv/v1.22.0"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	eventPrefix        = "_e{"
+	serviceCheckPrefix = "_sc|"
+
+	attributeEventTitle          = "dogstatsd.event.title"
+	attributeEventPriority       = "dogstatsd.event.priority"
+	attributeEventAlertType      = "dogstatsd.event.alert_type"
+	attributeEventAggregationKey = "dogstatsd.event.aggregation_key"
+	attributeEventSourceType     = "dogstatsd.event.source_type_name"
+	attributeServiceCheckName    = "dogstatsd.service_check.name"
+	attributeServiceCheckStatus  = "dogstatsd.service_check.status"
+)
+
+var (
+	errEmptyEventTitle       = errors.New("empty event title")
+	errEmptyServiceCheckName = errors.New("empty service check name")
+)
+
+// eventSeverities maps the alert types of DogStatsD events to log severities.
+var eventSeverities = map[string]plog.SeverityNumber{
+	"error":   plog.SeverityNumberError,
+	"warning": plog.SeverityNumberWarn,
+	"info":    plog.SeverityNumberInfo,
+	"success": plog.SeverityNumberInfo,
+}
+
+// serviceCheckStatuses are the names of the DogStatsD service check statuses,
+// indexed by status.
+var serviceCheckStatuses = []struct {
+	name     string
+	severity plog.SeverityNumber
+}{
+	{"OK", plog.SeverityNumberInfo},
+	{"WARNING", plog.SeverityNumberWarn},
+	{"CRITICAL", plog.SeverityNumberError},
+	{"UNKNOWN", plog.SeverityNumberUnspecified},
+}
+
+func isEvent(line string) bool {
+	return strings.HasPrefix(line, eventPrefix)
+}
+
+func isServiceCheck(line string) bool {
+	return strings.HasPrefix(line, serviceCheckPrefix)
+}
+
+// aggregateLog parses a DogStatsD event or service check and buffers it as a
+// log record until the next call to GetLogs.
+func (p *StatsDParser) aggregateLog(line string, addr net.Addr) error {
+	record := plog.NewLogRecord()
+
+	var containerID string
+	var err error
+	if isEvent(line) {
+		containerID, err = parseEvent(line, p.enableSimpleTags, record)
+	} else {
+		containerID, err = parseServiceCheck(line, p.enableSimpleTags, record)
+	}
+	if err != nil {
+		return err
+	}
+	record.SetObservedTimestamp(pcommon.NewTimestampFromTime(timeNowFunc()))
+
+	key := instrumentsKey{newNetAddr(addr), containerID}
+	batch, ok := p.logsByAddress[key]
+	if !ok {
+		batch = BatchLogs{
+			Info: client.Info{
+				Addr: addr,
+			},
+			Logs: plog.NewLogs(),
+		}
+		rl := batch.Logs.ResourceLogs().AppendEmpty()
+		if containerID != "" {
+			rl.Resource().Attributes().PutStr(semconv.AttributeContainerID, containerID)
+		}
+		p.setVersionAndNameScope(rl.ScopeLogs().AppendEmpty().Scope())
+		p.logsByAddress[key] = batch
+	}
+	record.MoveTo(batch.Logs.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().AppendEmpty())
+	return nil
+}
+
+// GetLogs gets the logs buffered since the last call and resets them.
+func (p *StatsDParser) GetLogs() []BatchLogs {
+	batchLogs := make([]BatchLogs, 0, len(p.logsByAddress))
+	for _, batch := range p.logsByAddress {
+		batchLogs = append(batchLogs, batch)
+	}
+	p.logsByAddress = make(map[instrumentsKey]BatchLogs)
+	return batchLogs
+}
+
+// parseEvent parses a DogStatsD event into the log record, returning the
+// container ID of the event.
+// https://docs.datadoghq.com/developers/dogstatsd/datagram_shell/?tab=events
+func parseEvent(line string, enableSimpleTags bool, record plog.LogRecord) (string, error) {
+	header, rest, ok := strings.Cut(strings.TrimPrefix(line, eventPrefix), "}:")
+	if !ok {
+		return "", fmt.Errorf("invalid event format: %s", line)
+	}
+
+	// The lengths are the number of bytes of the title and of the text, which
+	// may both contain the '|' separator.
+	lengths := strings.Split(header, ",")
+	if len(lengths) != 2 {
+		return "", fmt.Errorf("invalid event lengths: %s", header)
+	}
+	titleLen, err := strconv.Atoi(lengths[0])
+	if err != nil || titleLen < 0 {
+		return "", fmt.Errorf("invalid event title length: %s", lengths[0])
+	}
+	textLen, err := strconv.Atoi(lengths[1])
+	if err != nil || textLen < 0 {
+		return "", fmt.Errorf("invalid event text length: %s", lengths[1])
+	}
+	if len(rest) < titleLen+1+textLen || rest[titleLen] != '|' {
+		return "", fmt.Errorf("event title and text do not match their lengths: %s", line)
+	}
+
+	title := unescapeNewlines(rest[:titleLen])
+	if title == "" {
+		return "", errEmptyEventTitle
+	}
+	record.Body().SetStr(unescapeNewlines(rest[titleLen+1 : titleLen+1+textLen]))
+
+	alertType := "info"
+	var containerID string
+	var kvs []attribute.KeyValue
+	attrs := record.Attributes()
+
+	if rest = rest[titleLen+1+textLen:]; rest != "" {
+		if rest[0] != '|' {
+			return "", fmt.Errorf("event title and text do not match their lengths: %s", line)
+		}
+		for _, part := range strings.Split(rest[1:], "|") {
+			switch {
+			case strings.HasPrefix(part, "d:"):
+				timestamp, err := parseLogTimestamp(strings.TrimPrefix(part, "d:"))
+				if err != nil {
+					return "", err
+				}
+				record.SetTimestamp(timestamp)
+			case strings.HasPrefix(part, "h:"):
+				attrs.PutStr(semconv.AttributeHostName, strings.TrimPrefix(part, "h:"))
+			case strings.HasPrefix(part, "p:"):
+				priority := strings.TrimPrefix(part, "p:")
+				if priority != "normal" && priority != "low" {
+					return "", fmt.Errorf("invalid event priority: %s", priority)
+				}
+				attrs.PutStr(attributeEventPriority, priority)
+			case strings.HasPrefix(part, "t:"):
+				alertType = strings.TrimPrefix(part, "t:")
+				if _, ok := eventSeverities[alertType]; !ok {
+					return "", fmt.Errorf("invalid event alert type: %s", alertType)
+				}
+			case strings.HasPrefix(part, "k:"):
+				attrs.PutStr(attributeEventAggregationKey, strings.TrimPrefix(part, "k:"))
+			case strings.HasPrefix(part, "s:"):
+				attrs.PutStr(attributeEventSourceType, strings.TrimPrefix(part, "s:"))
+			case strings.HasPrefix(part, "#"):
+				kvs, err = parseTags(strings.TrimPrefix(part, "#"), enableSimpleTags, kvs)
+				if err != nil {
+					return "", err
+				}
+			case strings.HasPrefix(part, "c:"):
+				containerID = strings.TrimPrefix(part, "c:")
+			default:
+				return "", fmt.Errorf("unrecognized event part: %s", part)
+			}
+		}
+	}
+
+	putTags(attrs, kvs)
+	attrs.PutStr(attributeEventTitle, title)
+	attrs.PutStr(attributeEventAlertType, alertType)
+	record.SetSeverityNumber(eventSeverities[alertType])
+	record.SetSeverityText(alertType)
+	return containerID, nil
+}
+
+// parseServiceCheck parses a DogStatsD service check into the log record,
+// returning the container ID of the service check.
+// https://docs.datadoghq.com/developers/dogstatsd/datagram_shell/?tab=servicechecks
+func parseServiceCheck(line string, enableSimpleTags bool, record plog.LogRecord) (string, error) {
+	rest := strings.TrimPrefix(line, serviceCheckPrefix)
+
+	// The message is always the last field and may contain the '|' separator.
+	if before, message, ok := strings.Cut(rest, "|m:"); ok {
+		rest = before
+		record.Body().SetStr(strings.ReplaceAll(unescapeNewlines(message), `m\:`, "m:"))
+	}
+
+	parts := strings.Split(rest, "|")
+	if len(parts) < 2 {
+		return "", fmt.Errorf("invalid service check format: %s", line)
+	}
+
+	name := parts[0]
+	if name == "" {
+		return "", errEmptyServiceCheckName
+	}
+	status, err := strconv.Atoi(parts[1])
+	if err != nil || status < 0 || status >= len(serviceCheckStatuses) {
+		return "", fmt.Errorf("invalid service check status: %s", parts[1])
+	}
+
+	var containerID string
+	var kvs []attribute.KeyValue
+	attrs := record.Attributes()
+
+	for _, part := range parts[2:] {
+		switch {
+		case strings.HasPrefix(part, "d:"):
+			timestamp, err := parseLogTimestamp(strings.TrimPrefix(part, "d:"))
+			if err != nil {
+				return "", err
+			}
+			record.SetTimestamp(timestamp)
+		case strings.HasPrefix(part, "h:"):
+			attrs.PutStr(semconv.AttributeHostName, strings.TrimPrefix(part, "h:"))
+		case strings.HasPrefix(part, "#"):
+			kvs, err = parseTags(strings.TrimPrefix(part, "#"), enableSimpleTags, kvs)
+			if err != nil {
+				return "", err
+			}
+		case strings.HasPrefix(part, "c:"):
+			containerID = strings.TrimPrefix(part, "c:")
+		default:
+			return "", fmt.Errorf("unrecognized service check part: %s", part)
+		}
+	}
+
+	putTags(attrs, kvs)
+	attrs.PutStr(attributeServiceCheckName, name)
+	attrs.PutStr(attributeServiceCheckStatus, serviceCheckStatuses[status].name)
+	record.SetSeverityNumber(serviceCheckStatuses[status].severity)
+	record.SetSeverityText(serviceCheckStatuses[status].name)
+	return containerID, nil
+}
+
+// parseLogTimestamp parses the timestamp of an event or service check, which
+// is in seconds since the epoch.
+func parseLogTimestamp(s string) (pcommon.Timestamp, error) {
+	seconds, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid timestamp: %s", s)
+	}
+	return pcommon.NewTimestampFromTime(time.Unix(seconds, 0)), nil
+}
+
+// unescapeNewlines restores the newlines DogStatsD clients escape as "\n".
+func unescapeNewlines(s string) string {
+	return strings.ReplaceAll(s, `\n`, "\n")
+}
+
+func putTags(attrs pcommon.Map, kvs []attribute.KeyValue) {
+	for _, kv := range kvs {
+		attrs.PutStr(string(kv.Key), kv.Value.AsString())
+	}
+}
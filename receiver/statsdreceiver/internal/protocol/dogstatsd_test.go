@@ -0,0 +1,281 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package protocol
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	semconv "go.opentelemetry.io/collector/semconv/v1.22.0"
+)
+
+func testLogRecord(body string, severity plog.SeverityNumber, severityText string, timestamp int64, attrs map[string]any) plog.LogRecord {
+	record := plog.NewLogRecord()
+	if body != "" {
+		record.Body().SetStr(body)
+	}
+	record.SetSeverityNumber(severity)
+	record.SetSeverityText(severityText)
+	if timestamp != 0 {
+		record.SetTimestamp(pcommon.NewTimestampFromTime(time.Unix(timestamp, 0)))
+	}
+	_ = record.Attributes().FromRaw(attrs)
+	return record
+}
+
+func assertLogRecord(t *testing.T, want plog.LogRecord, got plog.LogRecord) {
+	assert.Equal(t, want.Body().AsRaw(), got.Body().AsRaw())
+	assert.Equal(t, want.SeverityNumber(), got.SeverityNumber())
+	assert.Equal(t, want.SeverityText(), got.SeverityText())
+	assert.Equal(t, want.Timestamp(), got.Timestamp())
+	assert.Equal(t, want.Attributes().AsRaw(), got.Attributes().AsRaw())
+}
+
+func Test_ParseEvent(t *testing.T) {
+	tests := []struct {
+		name            string
+		input           string
+		wantRecord      plog.LogRecord
+		wantContainerID string
+		err             error
+	}{
+		{
+			name:  "minimal event",
+			input: "_e{5,4}:title|text",
+			wantRecord: testLogRecord("text", plog.SeverityNumberInfo, "info", 0, map[string]any{
+				"dogstatsd.event.title":      "title",
+				"dogstatsd.event.alert_type": "info",
+			}),
+		},
+		{
+			name:  "event with all fields",
+			input: `_e{9,12}:the|title|line1\nline2|d:1656581400|h:myhost|p:low|t:error|k:key|s:source|#env:prod,team:a|c:abc123`,
+			wantRecord: testLogRecord("line1\nline2", plog.SeverityNumberError, "error", 1656581400, map[string]any{
+				"dogstatsd.event.title":            "the|title",
+				"dogstatsd.event.alert_type":       "error",
+				"dogstatsd.event.priority":         "low",
+				"dogstatsd.event.aggregation_key":  "key",
+				"dogstatsd.event.source_type_name": "source",
+				"host.name":                        "myhost",
+				"env":                              "prod",
+				"team":                             "a",
+			}),
+			wantContainerID: "abc123",
+		},
+		{
+			name:  "event with multibyte title",
+			input: "_e{6,1}:títle|x",
+			wantRecord: testLogRecord("x", plog.SeverityNumberInfo, "info", 0, map[string]any{
+				"dogstatsd.event.title":      "títle",
+				"dogstatsd.event.alert_type": "info",
+			}),
+		},
+		{
+			name:  "event with warning alert type",
+			input: "_e{5,4}:title|text|t:warning",
+			wantRecord: testLogRecord("text", plog.SeverityNumberWarn, "warning", 0, map[string]any{
+				"dogstatsd.event.title":      "title",
+				"dogstatsd.event.alert_type": "warning",
+			}),
+		},
+		{
+			name:  "missing lengths",
+			input: "_e{}:title|text",
+			err:   errors.New("invalid event lengths: "),
+		},
+		{
+			name:  "invalid title length",
+			input: "_e{a,4}:title|text",
+			err:   errors.New("invalid event title length: a"),
+		},
+		{
+			name:  "lengths longer than the event",
+			input: "_e{5,10}:title|text",
+			err:   errors.New("event title and text do not match their lengths: _e{5,10}:title|text"),
+		},
+		{
+			name:  "lengths shorter than the event",
+			input: "_e{5,2}:title|text",
+			err:   errors.New("event title and text do not match their lengths: _e{5,2}:title|text"),
+		},
+		{
+			name:  "empty title",
+			input: "_e{0,4}:|text",
+			err:   errEmptyEventTitle,
+		},
+		{
+			name:  "invalid alert type",
+			input: "_e{5,4}:title|text|t:fatal",
+			err:   errors.New("invalid event alert type: fatal"),
+		},
+		{
+			name:  "invalid priority",
+			input: "_e{5,4}:title|text|p:high",
+			err:   errors.New("invalid event priority: high"),
+		},
+		{
+			name:  "invalid timestamp",
+			input: "_e{5,4}:title|text|d:now",
+			err:   errors.New("invalid timestamp: now"),
+		},
+		{
+			name:  "unrecognized part",
+			input: "_e{5,4}:title|text|x:y",
+			err:   errors.New("unrecognized event part: x:y"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record := plog.NewLogRecord()
+			containerID, err := parseEvent(tt.input, false, record)
+
+			if tt.err != nil {
+				assert.Equal(t, tt.err, err)
+			} else {
+				assert.NoError(t, err)
+				assertLogRecord(t, tt.wantRecord, record)
+				assert.Equal(t, tt.wantContainerID, containerID)
+			}
+		})
+	}
+}
+
+func Test_ParseServiceCheck(t *testing.T) {
+	tests := []struct {
+		name            string
+		input           string
+		wantRecord      plog.LogRecord
+		wantContainerID string
+		err             error
+	}{
+		{
+			name:  "minimal service check",
+			input: "_sc|my.check|0",
+			wantRecord: testLogRecord("", plog.SeverityNumberInfo, "OK", 0, map[string]any{
+				"dogstatsd.service_check.name":   "my.check",
+				"dogstatsd.service_check.status": "OK",
+			}),
+		},
+		{
+			name:  "service check with all fields",
+			input: `_sc|my.check|2|d:1656581400|h:myhost|#env:prod|c:abc123|m:disk | full\nm\: 95%`,
+			wantRecord: testLogRecord("disk | full\nm: 95%", plog.SeverityNumberError, "CRITICAL", 1656581400, map[string]any{
+				"dogstatsd.service_check.name":   "my.check",
+				"dogstatsd.service_check.status": "CRITICAL",
+				"host.name":                      "myhost",
+				"env":                            "prod",
+			}),
+			wantContainerID: "abc123",
+		},
+		{
+			name:  "warning service check",
+			input: "_sc|my.check|1",
+			wantRecord: testLogRecord("", plog.SeverityNumberWarn, "WARNING", 0, map[string]any{
+				"dogstatsd.service_check.name":   "my.check",
+				"dogstatsd.service_check.status": "WARNING",
+			}),
+		},
+		{
+			name:  "unknown service check",
+			input: "_sc|my.check|3",
+			wantRecord: testLogRecord("", plog.SeverityNumberUnspecified, "UNKNOWN", 0, map[string]any{
+				"dogstatsd.service_check.name":   "my.check",
+				"dogstatsd.service_check.status": "UNKNOWN",
+			}),
+		},
+		{
+			name:  "missing status",
+			input: "_sc|my.check",
+			err:   errors.New("invalid service check format: _sc|my.check"),
+		},
+		{
+			name:  "empty name",
+			input: "_sc||0",
+			err:   errEmptyServiceCheckName,
+		},
+		{
+			name:  "invalid status",
+			input: "_sc|my.check|4",
+			err:   errors.New("invalid service check status: 4"),
+		},
+		{
+			name:  "invalid tags",
+			input: "_sc|my.check|0|#env",
+			err:   errors.New("invalid tag format: \"env\""),
+		},
+		{
+			name:  "unrecognized part",
+			input: "_sc|my.check|0|x:y",
+			err:   errors.New("unrecognized service check part: x:y"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record := plog.NewLogRecord()
+			containerID, err := parseServiceCheck(tt.input, false, record)
+
+			if tt.err != nil {
+				assert.Equal(t, tt.err, err)
+			} else {
+				assert.NoError(t, err)
+				assertLogRecord(t, tt.wantRecord, record)
+				assert.Equal(t, tt.wantContainerID, containerID)
+			}
+		})
+	}
+}
+
+func TestStatsDParser_AggregateLogs(t *testing.T) {
+	timeNowFunc = func() time.Time {
+		return time.Unix(711, 0)
+	}
+
+	p := &StatsDParser{}
+	assert.NoError(t, p.Initialize(false, false, false, nil))
+
+	addr, _ := net.ResolveUDPAddr("udp", "1.2.3.4:5678")
+	assert.NoError(t, p.Aggregate("_e{5,4}:title|text", addr))
+	assert.NoError(t, p.Aggregate("_sc|my.check|0|c:abc123", addr))
+	assert.NoError(t, p.Aggregate("_sc|my.check|1", addr))
+	assert.NoError(t, p.Aggregate("test.metric:1|c", addr))
+	assert.Error(t, p.Aggregate("_sc|my.check", addr))
+
+	// Logs are buffered separately from the metrics.
+	require.Len(t, p.GetMetrics(), 1)
+
+	records := map[string]int{}
+	for _, batch := range p.GetLogs() {
+		assert.Equal(t, addr, batch.Info.Addr)
+		require.Equal(t, 1, batch.Logs.ResourceLogs().Len())
+
+		rl := batch.Logs.ResourceLogs().At(0)
+		var containerID string
+		if v, ok := rl.Resource().Attributes().Get(semconv.AttributeContainerID); ok {
+			containerID = v.Str()
+		}
+
+		require.Equal(t, 1, rl.ScopeLogs().Len())
+		sl := rl.ScopeLogs().At(0)
+		assert.Equal(t, receiverName, sl.Scope().Name())
+		for i := 0; i < sl.LogRecords().Len(); i++ {
+			assert.Equal(t, time.Unix(711, 0).UTC(), sl.LogRecords().At(i).ObservedTimestamp().AsTime())
+		}
+		records[containerID] = sl.LogRecords().Len()
+	}
+	assert.Equal(t, map[string]int{
+		"":       2,
+		"abc123": 1,
+	}, records)
+
+	// The logs are reset once they are got.
+	assert.Empty(t, p.GetLogs())
+}
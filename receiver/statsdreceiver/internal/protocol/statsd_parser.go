@@ -78,18 +78,29 @@ var defaultObserverCategory = ObserverCategory{
 
 // StatsDParser supports the Parse method for parsing StatsD messages with Tags.
 type StatsDParser struct {
-	instrumentsByAddress map[netAddr]*instruments
+	instrumentsByAddress map[instrumentsKey]*instruments
+	logsByAddress        map[instrumentsKey]BatchLogs
 	enableMetricType     bool
 	enableSimpleTags     bool
 	isMonotonicCounter   bool
 	timerEvents          ObserverCategory
 	histogramEvents      ObserverCategory
+	distributionEvents   ObserverCategory
 	lastIntervalTime     time.Time
 	BuildInfo            component.BuildInfo
 }
 
+// instrumentsKey identifies the source of the metrics and logs aggregated
+// together: the client address and, for DogStatsD clients, the container the
+// client runs in.
+type instrumentsKey struct {
+	netAddr
+	containerID string
+}
+
 type instruments struct {
 	addr                   net.Addr
+	containerID            string
 	gauges                 map[statsDMetricDescription]pmetric.ScopeMetrics
 	counters               map[statsDMetricDescription]pmetric.ScopeMetrics
 	summaries              map[statsDMetricDescription]summaryMetric
@@ -97,13 +108,14 @@ type instruments struct {
 	timersAndDistributions []pmetric.ScopeMetrics
 }
 
-func newInstruments(addr net.Addr) *instruments {
+func newInstruments(addr net.Addr, containerID string) *instruments {
 	return &instruments{
-		addr:       addr,
-		gauges:     make(map[statsDMetricDescription]pmetric.ScopeMetrics),
-		counters:   make(map[statsDMetricDescription]pmetric.ScopeMetrics),
-		summaries:  make(map[statsDMetricDescription]summaryMetric),
-		histograms: make(map[statsDMetricDescription]histogramMetric),
+		addr:        addr,
+		containerID: containerID,
+		gauges:      make(map[statsDMetricDescription]pmetric.ScopeMetrics),
+		counters:    make(map[statsDMetricDescription]pmetric.ScopeMetrics),
+		summaries:   make(map[statsDMetricDescription]summaryMetric),
+		histograms:  make(map[statsDMetricDescription]histogramMetric),
 	}
 }
 
@@ -130,6 +142,7 @@ type statsDMetric struct {
 	unit        string
 	sampleRate  float64
 	timestamp   uint64
+	containerID string
 }
 
 type statsDMetricDescription struct {
@@ -156,29 +169,42 @@ func (t MetricType) FullName() TypeName {
 
 func (p *StatsDParser) resetState(when time.Time) {
 	p.lastIntervalTime = when
-	p.instrumentsByAddress = make(map[netAddr]*instruments)
+	p.instrumentsByAddress = make(map[instrumentsKey]*instruments)
 }
 
 func (p *StatsDParser) Initialize(enableMetricType bool, enableSimpleTags bool, isMonotonicCounter bool, sendTimerHistogram []TimerHistogramMapping) error {
 	p.resetState(timeNowFunc())
 
+	p.logsByAddress = make(map[instrumentsKey]BatchLogs)
+
 	p.histogramEvents = defaultObserverCategory
 	p.timerEvents = defaultObserverCategory
+	p.distributionEvents = defaultObserverCategory
 	p.enableMetricType = enableMetricType
 	p.enableSimpleTags = enableSimpleTags
 	p.isMonotonicCounter = isMonotonicCounter
+	// Distributions are aggregated like histograms unless they have a mapping
+	// of their own, as they historically shared the histogram mapping.
+	distributionMapped := false
 	// Note: validation occurs in ("../".Config).validate()
 	for _, eachMap := range sendTimerHistogram {
 		switch eachMap.StatsdType {
-		case HistogramTypeName, DistributionTypeName:
+		case HistogramTypeName:
 			p.histogramEvents.method = eachMap.ObserverType
 			p.histogramEvents.histogramConfig = expoHistogramConfig(eachMap.Histogram)
+		case DistributionTypeName:
+			distributionMapped = true
+			p.distributionEvents.method = eachMap.ObserverType
+			p.distributionEvents.histogramConfig = expoHistogramConfig(eachMap.Histogram)
 		case TimingTypeName, TimingAltTypeName:
 			p.timerEvents.method = eachMap.ObserverType
 			p.timerEvents.histogramConfig = expoHistogramConfig(eachMap.Histogram)
 		case CounterTypeName, GaugeTypeName:
 		}
 	}
+	if !distributionMapped {
+		p.distributionEvents = p.histogramEvents
+	}
 	return nil
 }
 
@@ -202,6 +228,9 @@ func (p *StatsDParser) GetMetrics() []BatchMetrics {
 			Metrics: pmetric.NewMetrics(),
 		}
 		rm := batch.Metrics.ResourceMetrics().AppendEmpty()
+		if instrument.containerID != "" {
+			rm.Resource().Attributes().PutStr(semconv.AttributeContainerID, instrument.containerID)
+		}
 		for _, metric := range instrument.gauges {
 			p.copyMetricAndScope(rm, metric)
 		}
@@ -263,8 +292,10 @@ var timeNowFunc = time.Now
 
 func (p *StatsDParser) observerCategoryFor(t MetricType) ObserverCategory {
 	switch t {
-	case HistogramType, DistributionType:
+	case HistogramType:
 		return p.histogramEvents
+	case DistributionType:
+		return p.distributionEvents
 	case TimingType:
 		return p.timerEvents
 	case CounterType, GaugeType:
@@ -272,17 +303,22 @@ func (p *StatsDParser) observerCategoryFor(t MetricType) ObserverCategory {
 	return defaultObserverCategory
 }
 
-// Aggregate for each metric line.
+// Aggregate for each metric line. DogStatsD events and service checks are
+// buffered as logs instead.
 func (p *StatsDParser) Aggregate(line string, addr net.Addr) error {
+	if isEvent(line) || isServiceCheck(line) {
+		return p.aggregateLog(line, addr)
+	}
+
 	parsedMetric, err := parseMessageToMetric(line, p.enableMetricType, p.enableSimpleTags)
 	if err != nil {
 		return err
 	}
 
-	addrKey := newNetAddr(addr)
+	addrKey := instrumentsKey{newNetAddr(addr), parsedMetric.containerID}
 	instrument, ok := p.instrumentsByAddress[addrKey]
 	if !ok {
-		instrument = newInstruments(addr)
+		instrument = newInstruments(addr, parsedMetric.containerID)
 		p.instrumentsByAddress[addrKey] = instrument
 	}
 
@@ -295,6 +331,9 @@ func (p *StatsDParser) Aggregate(line string, addr net.Addr) error {
 			if parsedMetric.addition {
 				point := instrument.gauges[parsedMetric.description].Metrics().At(0).Gauge().DataPoints().At(0)
 				point.SetDoubleValue(point.DoubleValue() + parsedMetric.gaugeValue())
+				if parsedMetric.timestamp != 0 {
+					point.SetTimestamp(pcommon.Timestamp(parsedMetric.timestamp))
+				}
 			} else {
 				instrument.gauges[parsedMetric.description] = buildGaugeMetric(parsedMetric, timeNowFunc())
 			}
@@ -402,44 +441,16 @@ func parseMessageToMetric(line string, enableMetricType bool, enableSimpleTags b
 
 			result.sampleRate = f
 		case strings.HasPrefix(part, "#"):
-			tagsStr := strings.TrimPrefix(part, "#")
-
-			// handle an empty tag set
-			// where the tags part was still sent (some clients do this)
-			if len(tagsStr) == 0 {
-				continue
-			}
-
-			tagSets := strings.Split(tagsStr, ",")
-
-			for _, tagSet := range tagSets {
-				tagParts := strings.SplitN(tagSet, ":", 2)
-				k := tagParts[0]
-				if k == "" {
-					return result, fmt.Errorf("invalid tag format: %q", tagSet)
-				}
-
-				// support both simple tags (w/o value) and dimension tags (w/ value).
-				// dogstatsd notably allows simple tags.
-				var v string
-				if len(tagParts) == 2 {
-					v = tagParts[1]
-				}
-
-				if v == "" && !enableSimpleTags {
-					return result, fmt.Errorf("invalid tag format: %q", tagSet)
-				}
-
-				kvs = append(kvs, attribute.String(k, v))
+			var err error
+			kvs, err = parseTags(strings.TrimPrefix(part, "#"), enableSimpleTags, kvs)
+			if err != nil {
+				return result, err
 			}
 		case strings.HasPrefix(part, "c:"):
 			// As per DogStatD protocol v1.2:
 			// https://docs.datadoghq.com/developers/dogstatsd/datagram_shell/?tab=metrics#dogstatsd-protocol-v12
-			containerID := strings.TrimPrefix(part, "c:")
-
-			if containerID != "" {
-				kvs = append(kvs, attribute.String(semconv.AttributeContainerID, containerID))
-			}
+			// The container ID is set as a resource attribute of the metric.
+			result.containerID = strings.TrimPrefix(part, "c:")
 		case strings.HasPrefix(part, "T"):
 			// As per DogStatD protocol v1.3:
 			// https://docs.datadoghq.com/developers/dogstatsd/datagram_shell/?tab=metrics#dogstatsd-protocol-v13
@@ -478,6 +489,39 @@ func parseMessageToMetric(line string, enableMetricType bool, enableSimpleTags b
 	return result, nil
 }
 
+// parseTags parses a comma separated list of tags, appending them to kvs.
+func parseTags(tagsStr string, enableSimpleTags bool, kvs []attribute.KeyValue) ([]attribute.KeyValue, error) {
+	// handle an empty tag set
+	// where the tags part was still sent (some clients do this)
+	if len(tagsStr) == 0 {
+		return kvs, nil
+	}
+
+	tagSets := strings.Split(tagsStr, ",")
+
+	for _, tagSet := range tagSets {
+		tagParts := strings.SplitN(tagSet, ":", 2)
+		k := tagParts[0]
+		if k == "" {
+			return kvs, fmt.Errorf("invalid tag format: %q", tagSet)
+		}
+
+		// support both simple tags (w/o value) and dimension tags (w/ value).
+		// dogstatsd notably allows simple tags.
+		var v string
+		if len(tagParts) == 2 {
+			v = tagParts[1]
+		}
+
+		if v == "" && !enableSimpleTags {
+			return kvs, fmt.Errorf("invalid tag format: %q", tagSet)
+		}
+
+		kvs = append(kvs, attribute.String(k, v))
+	}
+	return kvs, nil
+}
+
 type netAddr struct {
 	Network string
 	String  string
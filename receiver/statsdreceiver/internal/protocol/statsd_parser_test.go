@@ -107,16 +107,20 @@ func Test_ParseMessageToMetric(t *testing.T) {
 		{
 			name:  "counter metric with container ID",
 			input: "test.metric:42|c|#key:value|c:abc123",
-			wantMetric: testStatsDMetric(
-				"test.metric",
-				42,
-				false,
-				"c",
-				0,
-				[]string{"key", semconv.AttributeContainerID},
-				[]string{"value", "abc123"},
-				0,
-			),
+			wantMetric: func() statsDMetric {
+				m := testStatsDMetric(
+					"test.metric",
+					42,
+					false,
+					"c",
+					0,
+					[]string{"key"},
+					[]string{"value"},
+					0,
+				)
+				m.containerID = "abc123"
+				return m
+			}(),
 		},
 		{
 			name:  "counter metric with timestamp",
@@ -1043,7 +1047,7 @@ func TestStatsDParser_Aggregate(t *testing.T) {
 			assert.NoError(t, p.Initialize(false, false, false, []TimerHistogramMapping{{StatsdType: "timer", ObserverType: "gauge"}, {StatsdType: "histogram", ObserverType: "gauge"}}))
 			p.lastIntervalTime = time.Unix(611, 0)
 			addr, _ := net.ResolveUDPAddr("udp", "1.2.3.4:5678")
-			addrKey := newNetAddr(addr)
+			addrKey := instrumentsKey{netAddr: newNetAddr(addr)}
 			for _, line := range tt.input {
 				err = p.Aggregate(line, addr)
 			}
@@ -1157,7 +1161,7 @@ func TestStatsDParser_AggregateByAddress(t *testing.T) {
 				}
 			}
 			for i, addr := range tt.addresses {
-				addrKey := newNetAddr(addr)
+				addrKey := instrumentsKey{netAddr: newNetAddr(addr)}
 				assert.Equal(t, tt.expectedGauges[i], p.instrumentsByAddress[addrKey].gauges)
 			}
 		})
@@ -1260,7 +1264,7 @@ func TestStatsDParser_AggregateWithMetricType(t *testing.T) {
 			assert.NoError(t, p.Initialize(true, false, false, []TimerHistogramMapping{{StatsdType: "timer", ObserverType: "gauge"}, {StatsdType: "histogram", ObserverType: "gauge"}}))
 			p.lastIntervalTime = time.Unix(611, 0)
 			addr, _ := net.ResolveUDPAddr("udp", "1.2.3.4:5678")
-			addrKey := newNetAddr(addr)
+			addrKey := instrumentsKey{netAddr: newNetAddr(addr)}
 			for _, line := range tt.input {
 				err = p.Aggregate(line, addr)
 			}
@@ -1330,7 +1334,7 @@ func TestStatsDParser_AggregateWithIsMonotonicCounter(t *testing.T) {
 			assert.NoError(t, p.Initialize(false, false, true, []TimerHistogramMapping{{StatsdType: "timer", ObserverType: "gauge"}, {StatsdType: "histogram", ObserverType: "gauge"}}))
 			p.lastIntervalTime = time.Unix(611, 0)
 			addr, _ := net.ResolveUDPAddr("udp", "1.2.3.4:5678")
-			addrKey := newNetAddr(addr)
+			addrKey := instrumentsKey{netAddr: newNetAddr(addr)}
 			for _, line := range tt.input {
 				err = p.Aggregate(line, addr)
 			}
@@ -1451,7 +1455,7 @@ func TestStatsDParser_AggregateTimerWithSummary(t *testing.T) {
 			p := &StatsDParser{}
 			assert.NoError(t, p.Initialize(false, false, false, []TimerHistogramMapping{{StatsdType: "timer", ObserverType: "summary"}, {StatsdType: "histogram", ObserverType: "summary"}}))
 			addr, _ := net.ResolveUDPAddr("udp", "1.2.3.4:5678")
-			addrKey := newNetAddr(addr)
+			addrKey := instrumentsKey{netAddr: newNetAddr(addr)}
 			for _, line := range tt.input {
 				err = p.Aggregate(line, addr)
 			}
@@ -1473,8 +1477,8 @@ func TestStatsDParser_Initialize(t *testing.T) {
 		attrs:      *attribute.EmptySet(),
 	}
 	addr, _ := net.ResolveUDPAddr("udp", "1.2.3.4:5678")
-	addrKey := newNetAddr(addr)
-	instrument := newInstruments(addr)
+	addrKey := instrumentsKey{netAddr: newNetAddr(addr)}
+	instrument := newInstruments(addr, "")
 	instrument.gauges[teststatsdDMetricdescription] = pmetric.ScopeMetrics{}
 	p.instrumentsByAddress[addrKey] = instrument
 	assert.Equal(t, 1, len(p.instrumentsByAddress))
@@ -1486,7 +1490,7 @@ func TestStatsDParser_Initialize(t *testing.T) {
 func TestStatsDParser_GetMetricsWithMetricType(t *testing.T) {
 	p := &StatsDParser{}
 	assert.NoError(t, p.Initialize(true, false, false, []TimerHistogramMapping{{StatsdType: "timer", ObserverType: "gauge"}, {StatsdType: "histogram", ObserverType: "gauge"}}))
-	instrument := newInstruments(nil)
+	instrument := newInstruments(nil, "")
 	instrument.gauges[testDescription("statsdTestMetric1", "g",
 		[]string{"mykey", "metric_type"}, []string{"myvalue", "gauge"})] = buildGaugeMetric(
 		testStatsDMetric(
@@ -1541,7 +1545,7 @@ func TestStatsDParser_GetMetricsWithMetricType(t *testing.T) {
 			weights: []float64{1, 1, 1, 1},
 		},
 	}
-	p.instrumentsByAddress[netAddr{}] = instrument
+	p.instrumentsByAddress[instrumentsKey{}] = instrument
 	metrics := p.GetMetrics()[0].Metrics
 	assert.Equal(t, 5, metrics.ResourceMetrics().At(0).ScopeMetrics().Len())
 }
@@ -1913,3 +1917,140 @@ func TestStatsDParser_AggregateTimerWithHistogram(t *testing.T) {
 		})
 	}
 }
+
+func TestStatsDParser_DistributionMappings(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		mapping []TimerHistogramMapping
+		expect  map[string]string
+	}{
+		{
+			name: "distribution-follows-histogram",
+			mapping: []TimerHistogramMapping{
+				{StatsdType: "histogram", ObserverType: "summary"},
+			},
+			expect: map[string]string{
+				"H": "Summary",
+				"D": "Summary",
+			},
+		},
+		{
+			name: "distribution-to-histogram",
+			mapping: []TimerHistogramMapping{
+				{StatsdType: "histogram", ObserverType: "gauge"},
+				{StatsdType: "distribution", ObserverType: "histogram"},
+			},
+			expect: map[string]string{
+				"H": "Gauge",
+				"D": "ExponentialHistogram",
+			},
+		},
+		{
+			name: "distribution-only",
+			mapping: []TimerHistogramMapping{
+				{StatsdType: "distribution", ObserverType: "histogram"},
+			},
+			expect: map[string]string{
+				"D": "ExponentialHistogram",
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p := &StatsDParser{}
+
+			assert.NoError(t, p.Initialize(false, false, false, tc.mapping))
+
+			addr, _ := net.ResolveUDPAddr("udp", "1.2.3.4:5678")
+			assert.NoError(t, p.Aggregate("H:10|h", addr))
+			assert.NoError(t, p.Aggregate("D:10|d", addr))
+
+			types := map[string]string{}
+
+			metrics := p.GetMetrics()[0].Metrics
+			ilm := metrics.ResourceMetrics().At(0).ScopeMetrics()
+			for i := 0; i < ilm.Len(); i++ {
+				ilms := ilm.At(i).Metrics()
+				for j := 0; j < ilms.Len(); j++ {
+					m := ilms.At(j)
+					types[m.Name()] = m.Type().String()
+				}
+			}
+
+			assert.Equal(t, tc.expect, types)
+		})
+	}
+}
+
+func TestStatsDParser_AggregateSampledDistribution(t *testing.T) {
+	p := &StatsDParser{}
+	assert.NoError(t, p.Initialize(false, false, false, []TimerHistogramMapping{{StatsdType: "distribution", ObserverType: "histogram"}}))
+
+	addr, _ := net.ResolveUDPAddr("udp", "1.2.3.4:5678")
+	assert.NoError(t, p.Aggregate("test.distribution:2|d|@0.25", addr))
+	assert.NoError(t, p.Aggregate("test.distribution:4|d", addr))
+
+	metrics := p.GetMetrics()[0].Metrics
+	dp := metrics.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).ExponentialHistogram().DataPoints().At(0)
+	assert.Equal(t, uint64(5), dp.Count())
+	assert.Equal(t, float64(12), dp.Sum())
+	assert.Equal(t, float64(2), dp.Min())
+	assert.Equal(t, float64(4), dp.Max())
+}
+
+func TestStatsDParser_AggregateWithContainerID(t *testing.T) {
+	p := &StatsDParser{}
+	assert.NoError(t, p.Initialize(false, false, false, nil))
+
+	addr, _ := net.ResolveUDPAddr("udp", "1.2.3.4:5678")
+	assert.NoError(t, p.Aggregate("test.metric:1|c|c:abc123", addr))
+	assert.NoError(t, p.Aggregate("test.metric:2|c|c:abc123", addr))
+	assert.NoError(t, p.Aggregate("test.metric:4|c|c:def456", addr))
+	assert.NoError(t, p.Aggregate("test.metric:8|c", addr))
+
+	values := map[string]int64{}
+	for _, batch := range p.GetMetrics() {
+		assert.Equal(t, addr, batch.Info.Addr)
+
+		rm := batch.Metrics.ResourceMetrics().At(0)
+		var containerID string
+		if v, ok := rm.Resource().Attributes().Get(semconv.AttributeContainerID); ok {
+			containerID = v.Str()
+		}
+
+		dp := rm.ScopeMetrics().At(0).Metrics().At(0).Sum().DataPoints().At(0)
+		assert.Equal(t, 0, dp.Attributes().Len())
+		values[containerID] = dp.IntValue()
+	}
+
+	assert.Equal(t, map[string]int64{
+		"abc123": 3,
+		"def456": 4,
+		"":       8,
+	}, values)
+}
+
+func TestStatsDParser_AggregateGaugeWithTimestamp(t *testing.T) {
+	timeNowFunc = func() time.Time {
+		return time.Unix(711, 0)
+	}
+
+	p := &StatsDParser{}
+	assert.NoError(t, p.Initialize(false, false, false, nil))
+
+	addr, _ := net.ResolveUDPAddr("udp", "1.2.3.4:5678")
+	assert.NoError(t, p.Aggregate("test.gauge:1|g|T1656581400", addr))
+	assert.NoError(t, p.Aggregate("test.gauge:+2|g|T1656581460", addr))
+	assert.NoError(t, p.Aggregate("other.gauge:1|g", addr))
+
+	timestamps := map[string]time.Time{}
+	sms := p.GetMetrics()[0].Metrics.ResourceMetrics().At(0).ScopeMetrics()
+	for i := 0; i < sms.Len(); i++ {
+		m := sms.At(i).Metrics().At(0)
+		timestamps[m.Name()] = m.Gauge().DataPoints().At(0).Timestamp().AsTime()
+	}
+
+	assert.Equal(t, map[string]time.Time{
+		"test.gauge":  time.Unix(1656581460, 0).UTC(),
+		"other.gauge": time.Unix(711, 0).UTC(),
+	}, timestamps)
+}
@@ -45,6 +45,15 @@ func (s *StatsD) connect() error {
 		if err != nil {
 			return err
 		}
+	case "unixgram":
+		unixAddr, err := net.ResolveUnixAddr(s.transport, s.address)
+		if err != nil {
+			return err
+		}
+		s.conn, err = net.DialUnix(s.transport, nil, unixAddr)
+		if err != nil {
+			return err
+		}
 	case "tcp":
 		var err error
 		s.conn, err = net.Dial(s.transport, s.address)
@@ -6,6 +6,8 @@ package transport
 import (
 	"io"
 	"net"
+	"os"
+	"path/filepath"
 	"runtime"
 	"sync"
 	"testing"
@@ -13,7 +15,6 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
-	"go.opentelemetry.io/collector/consumer/consumertest"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/testutil"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/statsdreceiver/internal/transport/client"
@@ -41,6 +42,13 @@ func Test_Server_ListenAndServe(t *testing.T) {
 			buildServerFn:     NewTCPServer,
 			buildClientFn:     client.NewStatsD,
 		},
+		{
+			name:              "unixgram",
+			transport:         UnixGram,
+			getFreeEndpointFn: getSocketPath,
+			buildServerFn:     NewUDPServer,
+			buildClientFn:     client.NewStatsD,
+		},
 	}
 
 	for _, tt := range tests {
@@ -52,8 +60,6 @@ func Test_Server_ListenAndServe(t *testing.T) {
 			require.NoError(t, err)
 			require.NotNil(t, srv)
 
-			mc := new(consumertest.MetricsSink)
-			require.NoError(t, err)
 			mr := NewMockReporter(1)
 			transferChan := make(chan Metric, 10)
 
@@ -61,7 +67,7 @@ func Test_Server_ListenAndServe(t *testing.T) {
 			wgListenAndServe.Add(1)
 			go func() {
 				defer wgListenAndServe.Done()
-				assert.Error(t, srv.ListenAndServe(mc, mr, transferChan))
+				assert.Error(t, srv.ListenAndServe(mr, transferChan))
 			}()
 
 			runtime.Gosched()
@@ -94,6 +100,31 @@ func Test_Server_ListenAndServe(t *testing.T) {
 	}
 }
 
+func TestUDPServer_UnixGramSocketFile(t *testing.T) {
+	path := getSocketPath(t, "unixgram")
+
+	// Files which are not sockets are not removed.
+	require.NoError(t, os.WriteFile(path, nil, 0600))
+	_, err := NewUDPServer(UnixGram, path)
+	require.EqualError(t, err, path+" exists and is not a socket")
+	require.NoError(t, os.Remove(path))
+
+	// Stale sockets are removed.
+	conn, err := net.ListenPacket("unixgram", path)
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+	require.FileExists(t, path)
+
+	srv, err := NewUDPServer(UnixGram, path)
+	require.NoError(t, err)
+	require.NoError(t, srv.Close())
+	require.NoFileExists(t, path)
+}
+
+func getSocketPath(t testing.TB, _ string) string {
+	return filepath.Join(t.TempDir(), "statsd.sock")
+}
+
 func testFreeEndpoint(t *testing.T, transport string, address string) {
 	t.Helper()
 
@@ -21,13 +21,16 @@ const (
 	TCP  Transport = "tcp"
 	TCP4 Transport = "tcp4"
 	TCP6 Transport = "tcp6"
+	// UnixGram is the Unix domain socket datagram transport, its addresses are
+	// paths in the file system.
+	UnixGram Transport = "unixgram"
 )
 
 // NewTransport creates a Transport based on the transport string or returns an empty Transport.
 func NewTransport(ts string) Transport {
 	trans := Transport(ts)
 	switch trans {
-	case UDP, UDP4, UDP6:
+	case UDP, UDP4, UDP6, UnixGram:
 		return trans
 	case TCP, TCP4, TCP6:
 		return trans
@@ -38,7 +41,7 @@ func NewTransport(ts string) Transport {
 // String casts the transport to a String if the Transport is supported. Return an empty Transport overwise.
 func (trans Transport) String() string {
 	switch trans {
-	case UDP, UDP4, UDP6, TCP, TCP4, TCP6:
+	case UDP, UDP4, UDP6, TCP, TCP4, TCP6, UnixGram:
 		return string(trans)
 	}
 	return ""
@@ -47,7 +50,7 @@ func (trans Transport) String() string {
 // IsPacketTransport returns true if the transport is packet based.
 func (trans Transport) IsPacketTransport() bool {
 	switch trans {
-	case UDP, UDP4, UDP6:
+	case UDP, UDP4, UDP6, UnixGram:
 		return true
 	}
 	return false
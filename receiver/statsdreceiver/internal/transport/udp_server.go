@@ -9,9 +9,10 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"os"
 	"strings"
 
-	"go.opentelemetry.io/collector/consumer"
+	"go.uber.org/multierr"
 )
 
 type udpServer struct {
@@ -22,12 +23,19 @@ type udpServer struct {
 // Ensure that Server is implemented on UDP Server.
 var _ (Server) = (*udpServer)(nil)
 
-// NewUDPServer creates a transport.Server using UDP as its transport.
+// NewUDPServer creates a transport.Server using UDP, or any other packet based
+// transport such as Unix domain socket datagrams, as its transport.
 func NewUDPServer(transport Transport, address string) (Server, error) {
 	if !transport.IsPacketTransport() {
 		return nil, fmt.Errorf("NewUDPServer with %s: %w", transport.String(), ErrUnsupportedPacketTransport)
 	}
 
+	if transport == UnixGram {
+		if err := removeStaleSocket(address); err != nil {
+			return nil, err
+		}
+	}
+
 	conn, err := net.ListenPacket(transport.String(), address)
 	if err != nil {
 		return nil, fmt.Errorf("starting to listen %s socket: %w", transport.String(), err)
@@ -41,17 +49,21 @@ func NewUDPServer(transport Transport, address string) (Server, error) {
 
 // ListenAndServe starts the server ready to receive metrics.
 func (u *udpServer) ListenAndServe(
-	nextConsumer consumer.Metrics,
 	reporter Reporter,
 	transferChan chan<- Metric,
 ) error {
-	if nextConsumer == nil || reporter == nil {
+	if reporter == nil {
 		return errNilListenAndServeParameters
 	}
 
 	buf := make([]byte, 65527) // max size for udp packet body (assuming ipv6)
 	for {
 		n, addr, err := u.packetConn.ReadFrom(buf)
+		if addr == nil {
+			// Unix domain socket clients don't have an address unless they
+			// bind their socket, use the address of the server for them.
+			addr = u.packetConn.LocalAddr()
+		}
 		if n > 0 {
 			bufCopy := make([]byte, n)
 			copy(bufCopy, buf)
@@ -73,9 +85,35 @@ func (u *udpServer) ListenAndServe(
 	}
 }
 
-// Close closes the server.
+// Close closes the server, removing the socket file of Unix domain sockets.
 func (u *udpServer) Close() error {
-	return u.packetConn.Close()
+	err := u.packetConn.Close()
+	if u.transport == UnixGram {
+		if rmErr := os.Remove(u.packetConn.LocalAddr().String()); rmErr != nil && !errors.Is(rmErr, os.ErrNotExist) {
+			err = multierr.Append(err, rmErr)
+		}
+	}
+	return err
+}
+
+// removeStaleSocket removes the socket left at the address by a server that
+// didn't shut down cleanly, as it would prevent listening to the address.
+// Files which are not sockets are left untouched.
+func removeStaleSocket(address string) error {
+	info, err := os.Lstat(address)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("checking %s socket: %w", address, err)
+	}
+	if info.Mode().Type() != os.ModeSocket {
+		return fmt.Errorf("%s exists and is not a socket", address)
+	}
+	if err := os.Remove(address); err != nil {
+		return fmt.Errorf("removing stale %s socket: %w", address, err)
+	}
+	return nil
 }
 
 // handlePacket is helper that parses the buffer and split it line by line to be parsed upstream.
@@ -22,26 +22,32 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/statsdreceiver/internal/transport"
 )
 
-var _ receiver.Metrics = (*statsdReceiver)(nil)
+var (
+	_ receiver.Metrics = (*statsdReceiver)(nil)
+	_ receiver.Logs    = (*statsdReceiver)(nil)
+)
 
-// statsdReceiver implements the receiver.Metrics for StatsD protocol.
+// statsdReceiver implements the receiver.Metrics for StatsD protocol, and the
+// receiver.Logs for the events and service checks of DogStatsD.
 type statsdReceiver struct {
 	settings receiver.CreateSettings
 	config   *Config
 
-	server       transport.Server
-	reporter     transport.Reporter
-	parser       protocol.Parser
-	nextConsumer consumer.Metrics
-	cancel       context.CancelFunc
+	server      transport.Server
+	reporter    transport.Reporter
+	parser      protocol.Parser
+	nextMetrics consumer.Metrics
+	nextLogs    consumer.Logs
+	cancel      context.CancelFunc
 }
 
-// newReceiver creates the StatsD receiver with the given parameters.
+// newReceiver creates the StatsD receiver with the given parameters. The
+// consumers are set by the factory, as the receiver is shared by the metrics
+// and logs pipelines.
 func newReceiver(
 	set receiver.CreateSettings,
 	config Config,
-	nextConsumer consumer.Metrics,
-) (receiver.Metrics, error) {
+) (*statsdReceiver, error) {
 
 	if config.NetAddr.Endpoint == "" {
 		config.NetAddr.Endpoint = "localhost:8125"
@@ -53,10 +59,9 @@ func newReceiver(
 	}
 
 	r := &statsdReceiver{
-		settings:     set,
-		config:       &config,
-		nextConsumer: nextConsumer,
-		reporter:     rep,
+		settings: set,
+		config:   &config,
+		reporter: rep,
 		parser: &protocol.StatsDParser{
 			BuildInfo: set.BuildInfo,
 		},
@@ -65,10 +70,9 @@ func newReceiver(
 }
 
 func buildTransportServer(config Config) (transport.Server, error) {
-	// TODO: Add unix socket transport implementations
 	trans := transport.NewTransport(strings.ToLower(string(config.NetAddr.Transport)))
 	switch trans {
-	case transport.UDP, transport.UDP4, transport.UDP6:
+	case transport.UDP, transport.UDP4, transport.UDP6, transport.UnixGram:
 		return transport.NewUDPServer(trans, config.NetAddr.Endpoint)
 	case transport.TCP, transport.TCP4, transport.TCP6:
 		return transport.NewTCPServer(trans, config.NetAddr.Endpoint)
@@ -77,7 +81,8 @@ func buildTransportServer(config Config) (transport.Server, error) {
 	return nil, fmt.Errorf("unsupported transport %q", string(config.NetAddr.Transport))
 }
 
-// Start starts a UDP server that can process StatsD messages.
+// Start starts a UDP, TCP or Unix domain socket server that can process StatsD
+// messages.
 func (r *statsdReceiver) Start(ctx context.Context, _ component.Host) error {
 	ctx, r.cancel = context.WithCancel(ctx)
 	server, err := buildTransportServer(*r.config)
@@ -97,7 +102,7 @@ func (r *statsdReceiver) Start(ctx context.Context, _ component.Host) error {
 		return err
 	}
 	go func() {
-		if err := r.server.ListenAndServe(r.nextConsumer, r.reporter, transferChan); err != nil {
+		if err := r.server.ListenAndServe(r.reporter, transferChan); err != nil {
 			if !errors.Is(err, net.ErrClosed) {
 				r.settings.TelemetrySettings.ReportStatus(component.NewFatalErrorEvent(err))
 			}
@@ -108,11 +113,23 @@ func (r *statsdReceiver) Start(ctx context.Context, _ component.Host) error {
 			select {
 			case <-ticker.C:
 				batchMetrics := r.parser.GetMetrics()
-				for _, batch := range batchMetrics {
-					batchCtx := client.NewContext(ctx, batch.Info)
+				batchLogs := r.parser.GetLogs()
+				if r.nextMetrics != nil {
+					for _, batch := range batchMetrics {
+						batchCtx := client.NewContext(ctx, batch.Info)
+
+						if err := r.Flush(batchCtx, batch.Metrics, r.nextMetrics); err != nil {
+							r.reporter.OnDebugf("Error flushing metrics", zap.Error(err))
+						}
+					}
+				}
+				if r.nextLogs != nil {
+					for _, batch := range batchLogs {
+						batchCtx := client.NewContext(ctx, batch.Info)
 
-					if err := r.Flush(batchCtx, batch.Metrics, r.nextConsumer); err != nil {
-						r.reporter.OnDebugf("Error flushing metrics", zap.Error(err))
+						if err := r.nextLogs.ConsumeLogs(batchCtx, batch.Logs); err != nil {
+							r.reporter.OnDebugf("Error flushing logs", zap.Error(err))
+						}
 					}
 				}
 			case metric := <-transferChan:
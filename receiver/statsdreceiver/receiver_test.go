@@ -6,6 +6,8 @@ package statsdreceiver
 import (
 	"context"
 	"errors"
+	"net"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -49,8 +51,9 @@ func Test_statsdreceiver_Start(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			receiver, err := newReceiver(receivertest.NewNopCreateSettings(), tt.args.config, tt.args.nextConsumer)
+			receiver, err := newReceiver(receivertest.NewNopCreateSettings(), tt.args.config)
 			require.NoError(t, err)
+			receiver.nextMetrics = tt.args.nextConsumer
 			err = receiver.Start(context.Background(), componenttest.NewNopHost())
 			assert.Equal(t, tt.wantErr, err)
 
@@ -62,10 +65,8 @@ func Test_statsdreceiver_Start(t *testing.T) {
 func TestStatsdReceiver_ShutdownBeforeStart(t *testing.T) {
 	ctx := context.Background()
 	cfg := createDefaultConfig().(*Config)
-	nextConsumer := consumertest.NewNop()
-	rcv, err := newReceiver(receivertest.NewNopCreateSettings(), *cfg, nextConsumer)
+	r, err := newReceiver(receivertest.NewNopCreateSettings(), *cfg)
 	assert.NoError(t, err)
-	r := rcv.(*statsdReceiver)
 	assert.NoError(t, r.Shutdown(ctx))
 }
 
@@ -73,9 +74,9 @@ func TestStatsdReceiver_Flush(t *testing.T) {
 	ctx := context.Background()
 	cfg := createDefaultConfig().(*Config)
 	nextConsumer := consumertest.NewNop()
-	rcv, err := newReceiver(receivertest.NewNopCreateSettings(), *cfg, nextConsumer)
+	r, err := newReceiver(receivertest.NewNopCreateSettings(), *cfg)
 	assert.NoError(t, err)
-	r := rcv.(*statsdReceiver)
+	r.nextMetrics = nextConsumer
 	var metrics = pmetric.NewMetrics()
 	assert.Nil(t, r.Flush(ctx, metrics, nextConsumer))
 	assert.NoError(t, r.Start(ctx, componenttest.NewNopHost()))
@@ -113,9 +114,9 @@ func Test_statsdreceiver_EndToEnd(t *testing.T) {
 			cfg := tt.configFn()
 			cfg.NetAddr.Endpoint = tt.addr
 			sink := new(consumertest.MetricsSink)
-			rcv, err := newReceiver(receivertest.NewNopCreateSettings(), *cfg, sink)
+			r, err := newReceiver(receivertest.NewNopCreateSettings(), *cfg)
 			require.NoError(t, err)
-			r := rcv.(*statsdReceiver)
+			r.nextMetrics = sink
 
 			mr := transport.NewMockReporter(1)
 			r.reporter = mr
@@ -163,3 +164,42 @@ func Test_statsdreceiver_EndToEnd(t *testing.T) {
 		})
 	}
 }
+
+func TestStatsdReceiver_MetricsAndLogs(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "statsd.sock")
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.NetAddr = confignet.AddrConfig{
+		Endpoint:  socket,
+		Transport: confignet.TransportTypeUnixgram,
+	}
+	cfg.AggregationInterval = 100 * time.Millisecond
+
+	metricsSink := new(consumertest.MetricsSink)
+	logsSink := new(consumertest.LogsSink)
+	params := receivertest.NewNopCreateSettings()
+	mr, err := createMetricsReceiver(context.Background(), params, cfg, metricsSink)
+	require.NoError(t, err)
+	lr, err := createLogsReceiver(context.Background(), params, cfg, logsSink)
+	require.NoError(t, err)
+	// The metrics and logs pipelines share the same listener.
+	assert.Same(t, mr, lr)
+
+	require.NoError(t, mr.Start(context.Background(), componenttest.NewNopHost()))
+	require.NoError(t, lr.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() {
+		assert.NoError(t, mr.Shutdown(context.Background()))
+		assert.NoError(t, lr.Shutdown(context.Background()))
+		assert.NoFileExists(t, socket)
+	}()
+
+	conn, err := net.Dial("unixgram", socket)
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = conn.Write([]byte("test.metric:42|c\n_e{5,4}:title|text|t:warning\n_sc|my.check|2|m:down\n"))
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		return metricsSink.DataPointCount() == 1 && logsSink.LogRecordCount() == 2
+	}, 10*time.Second, 50*time.Millisecond)
+}
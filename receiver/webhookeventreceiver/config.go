@@ -5,9 +5,12 @@ package webhookeventreceiver // import "github.com/open-telemetry/opentelemetry-
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/config/configopaque"
 	"go.uber.org/multierr"
 )
 
@@ -16,6 +19,27 @@ var (
 	errReadTimeoutExceedsMaxValue  = errors.New("The duration specified for read_timeout exceeds the maximum allowed value of 10s")
 	errWriteTimeoutExceedsMaxValue = errors.New("The duration specified for write_timeout exceeds the maximum allowed value of 10s")
 	errRequiredHeader              = errors.New("both key and value are required to assign a required_header")
+	errSignatureHeaderSecret       = errors.New("both header and secret are required to verify signatures")
+	errSignedPayloadBody           = errors.New("signed_payload must contain {body}")
+	errSignedPayloadTimestamp      = errors.New("signed_payload must contain {timestamp} if and only if timestamp_header is set")
+	errNonPositiveTolerance        = errors.New("the signature tolerance must be positive")
+	errEmptyHeaderAttribute        = errors.New("attribute names in header_attributes must not be empty")
+	errNonPositiveMaxBodySize      = errors.New("max_decompressed_body_size must be positive")
+)
+
+const (
+	// splitModeLine emits one log per line of the body.
+	splitModeLine = "line"
+	// splitModeJSONArray emits one log per element of a JSON array body.
+	splitModeJSONArray = "json_array"
+	// splitModeNDJSON emits one log per line of the body, which must be JSON.
+	splitModeNDJSON = "ndjson"
+
+	signatureEncodingHex    = "hex"
+	signatureEncodingBase64 = "base64"
+
+	signedPayloadBody      = "{body}"
+	signedPayloadTimestamp = "{timestamp}"
 )
 
 // Config defines configuration for the Generic Webhook receiver.
@@ -26,6 +50,14 @@ type Config struct {
 	Path                    string                   `mapstructure:"path"`            // path for data collection. Default is <host>:<port>/services/collector
 	HealthPath              string                   `mapstructure:"health_path"`     // path for health check api. Default is /services/collector/health
 	RequiredHeader          RequiredHeader           `mapstructure:"required_header"` // optional setting to set a required header for all requests to have
+	Signature               SignatureConfig          `mapstructure:"signature"`       // optional HMAC-SHA256 signature verification of the requests
+	SplitMode               string                   `mapstructure:"split_mode"`      // how the body is split into logs: line, json_array or ndjson. Default is line.
+	// HeaderAttributes maps the names of request headers to the names of the
+	// log attributes their value is set to.
+	HeaderAttributes map[string]string `mapstructure:"header_attributes"`
+	// MaxDecompressedBodySize is the maximum size in bytes of the request
+	// body after its Content-Encoding is decoded. Default is 20 MiB.
+	MaxDecompressedBodySize int64 `mapstructure:"max_decompressed_body_size"`
 }
 
 type RequiredHeader struct {
@@ -33,6 +65,63 @@ type RequiredHeader struct {
 	Value string `mapstructure:"value"`
 }
 
+// SignatureConfig defines how the HMAC-SHA256 signature of the requests is
+// verified. Verification is enabled when the header and secret are set.
+type SignatureConfig struct {
+	// Header is the name of the header holding the signature. Its value can
+	// hold several comma separated signatures, any of which may match.
+	Header string `mapstructure:"header"`
+	// Prefix precedes the signature in the header, e.g. "sha256=".
+	Prefix string `mapstructure:"prefix"`
+	// Secret is the key the signature is computed with.
+	Secret configopaque.String `mapstructure:"secret"`
+	// Encoding of the signature, either hex or base64. Default is hex.
+	Encoding string `mapstructure:"encoding"`
+	// SignedPayload is the content the signature is computed over, where
+	// {body} and {timestamp} are replaced by the request body and timestamp.
+	// Default is {body}.
+	SignedPayload string `mapstructure:"signed_payload"`
+	// TimestampHeader is the name of the header holding the Unix time at which
+	// the request was signed. Its value can hold several comma separated
+	// elements, in which case the one starting with TimestampPrefix is used.
+	TimestampHeader string `mapstructure:"timestamp_header"`
+	// TimestampPrefix precedes the timestamp in its header, e.g. "t=".
+	TimestampPrefix string `mapstructure:"timestamp_prefix"`
+	// Tolerance is the maximum difference between the timestamp and the time
+	// the request is received. Default is five minutes.
+	Tolerance time.Duration `mapstructure:"tolerance"`
+}
+
+func (cfg *SignatureConfig) enabled() bool {
+	return cfg.Header != "" || cfg.Secret != ""
+}
+
+func (cfg *SignatureConfig) validate() error {
+	var errs error
+
+	if cfg.Header == "" || cfg.Secret == "" {
+		errs = multierr.Append(errs, errSignatureHeaderSecret)
+	}
+
+	if cfg.Encoding != signatureEncodingHex && cfg.Encoding != signatureEncodingBase64 {
+		errs = multierr.Append(errs, fmt.Errorf("invalid signature encoding %q, must be %s or %s", cfg.Encoding, signatureEncodingHex, signatureEncodingBase64))
+	}
+
+	if !strings.Contains(cfg.SignedPayload, signedPayloadBody) {
+		errs = multierr.Append(errs, errSignedPayloadBody)
+	}
+
+	if strings.Contains(cfg.SignedPayload, signedPayloadTimestamp) != (cfg.TimestampHeader != "") {
+		errs = multierr.Append(errs, errSignedPayloadTimestamp)
+	}
+
+	if cfg.TimestampHeader != "" && cfg.Tolerance <= 0 {
+		errs = multierr.Append(errs, errNonPositiveTolerance)
+	}
+
+	return errs
+}
+
 func (cfg *Config) Validate() error {
 	var errs error
 
@@ -70,5 +159,25 @@ func (cfg *Config) Validate() error {
 		errs = multierr.Append(errs, errRequiredHeader)
 	}
 
+	if cfg.Signature.enabled() {
+		errs = multierr.Append(errs, cfg.Signature.validate())
+	}
+
+	switch cfg.SplitMode {
+	case "", splitModeLine, splitModeJSONArray, splitModeNDJSON:
+	default:
+		errs = multierr.Append(errs, fmt.Errorf("invalid split_mode %q, must be %s, %s or %s", cfg.SplitMode, splitModeLine, splitModeJSONArray, splitModeNDJSON))
+	}
+
+	if cfg.MaxDecompressedBodySize <= 0 {
+		errs = multierr.Append(errs, errNonPositiveMaxBodySize)
+	}
+
+	for header, attribute := range cfg.HeaderAttributes {
+		if attribute == "" {
+			errs = multierr.Append(errs, fmt.Errorf("%w: header %q", errEmptyHeaderAttribute, header))
+		}
+	}
+
 	return errs
 }
@@ -4,8 +4,10 @@
 package webhookeventreceiver
 
 import (
+	"errors"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
@@ -86,6 +88,16 @@ func TestValidateConfig(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc:   "MaxDecompressedBodySize is not positive",
+			expect: errNonPositiveMaxBodySize,
+			conf: Config{
+				ServerConfig: confighttp.ServerConfig{
+					Endpoint: "localhost:0",
+				},
+				MaxDecompressedBodySize: -1,
+			},
+		},
 		{
 			desc:   "Multiple invalid configs",
 			expect: errs,
@@ -134,6 +146,13 @@ func TestLoadConfig(t *testing.T) {
 			Key:   "key-present",
 			Value: "value-present",
 		},
+		Signature: SignatureConfig{
+			Encoding:      signatureEncodingHex,
+			SignedPayload: signedPayloadBody,
+			Tolerance:     defaultTolerance,
+		},
+		SplitMode:               splitModeLine,
+		MaxDecompressedBodySize: defaultMaxDecompressedBodySize,
 	}
 
 	// create expected config
@@ -144,3 +163,117 @@ func TestLoadConfig(t *testing.T) {
 
 	require.Equal(t, expect, conf)
 }
+
+func TestLoadConfigSigned(t *testing.T) {
+	t.Parallel()
+
+	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config.yaml"))
+	require.NoError(t, err)
+	cmNoStr, err := cm.Sub(component.NewIDWithName(metadata.Type, "signed").String())
+	require.NoError(t, err)
+
+	conf := NewFactory().CreateDefaultConfig().(*Config)
+	require.NoError(t, component.UnmarshalConfig(cmNoStr, conf))
+	require.NoError(t, component.ValidateConfig(conf))
+
+	require.Equal(t, splitModeNDJSON, conf.SplitMode)
+	require.Equal(t, SignatureConfig{
+		Header:          "X-Slack-Signature",
+		Prefix:          "v0=",
+		Secret:          "my-signing-secret",
+		Encoding:        signatureEncodingHex,
+		SignedPayload:   "v0:{timestamp}:{body}",
+		TimestampHeader: "X-Slack-Request-Timestamp",
+		Tolerance:       time.Minute,
+	}, conf.Signature)
+	require.Equal(t, map[string]string{"X-Slack-Retry-Num": "slack.retry_num"}, conf.HeaderAttributes)
+}
+
+func TestValidateSignatureConfig(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		desc   string
+		expect error
+		update func(cfg *Config)
+	}{
+		{
+			desc:   "Secret without header",
+			expect: errSignatureHeaderSecret,
+			update: func(cfg *Config) {
+				cfg.Signature.Secret = "secret"
+			},
+		},
+		{
+			desc:   "Header without secret",
+			expect: errSignatureHeaderSecret,
+			update: func(cfg *Config) {
+				cfg.Signature.Header = "X-Signature"
+			},
+		},
+		{
+			desc:   "Invalid encoding",
+			expect: errors.New(`invalid signature encoding "base32", must be hex or base64`),
+			update: func(cfg *Config) {
+				cfg.Signature.Header = "X-Signature"
+				cfg.Signature.Secret = "secret"
+				cfg.Signature.Encoding = "base32"
+			},
+		},
+		{
+			desc:   "Signed payload without body",
+			expect: errSignedPayloadBody,
+			update: func(cfg *Config) {
+				cfg.Signature.Header = "X-Signature"
+				cfg.Signature.Secret = "secret"
+				cfg.Signature.SignedPayload = "{timestamp}"
+				cfg.Signature.TimestampHeader = "X-Timestamp"
+			},
+		},
+		{
+			desc:   "Timestamp header without timestamp in signed payload",
+			expect: errSignedPayloadTimestamp,
+			update: func(cfg *Config) {
+				cfg.Signature.Header = "X-Signature"
+				cfg.Signature.Secret = "secret"
+				cfg.Signature.TimestampHeader = "X-Timestamp"
+			},
+		},
+		{
+			desc:   "Non positive tolerance",
+			expect: errNonPositiveTolerance,
+			update: func(cfg *Config) {
+				cfg.Signature.Header = "X-Signature"
+				cfg.Signature.Secret = "secret"
+				cfg.Signature.SignedPayload = "{timestamp}.{body}"
+				cfg.Signature.TimestampHeader = "X-Timestamp"
+				cfg.Signature.Tolerance = 0
+			},
+		},
+		{
+			desc:   "Invalid split mode",
+			expect: errors.New(`invalid split_mode "xml", must be line, json_array or ndjson`),
+			update: func(cfg *Config) {
+				cfg.SplitMode = "xml"
+			},
+		},
+		{
+			desc:   "Empty header attribute",
+			expect: errEmptyHeaderAttribute,
+			update: func(cfg *Config) {
+				cfg.HeaderAttributes = map[string]string{"X-Event": ""}
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.Endpoint = "localhost:0"
+			test.update(cfg)
+			err := cfg.Validate()
+			require.Error(t, err)
+			require.Contains(t, err.Error(), test.expect.Error())
+		})
+	}
+}
@@ -5,6 +5,7 @@ package webhookeventreceiver // import "github.com/open-telemetry/opentelemetry-
 
 import (
 	"context"
+	"time"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
@@ -24,6 +25,9 @@ const (
 	defaultWriteTimeout = "500ms"
 	defaultPath         = "/events"
 	defaultHealthPath   = "/health_check"
+	defaultTolerance    = 5 * time.Minute
+	// the same default as the max_request_body_size of the compressed body
+	defaultMaxDecompressedBodySize = 20 * 1024 * 1024
 )
 
 // NewFactory creates a factory for Generic Webhook Receiver.
@@ -42,6 +46,13 @@ func createDefaultConfig() component.Config {
 		HealthPath:   defaultHealthPath,
 		ReadTimeout:  defaultReadTimeout,
 		WriteTimeout: defaultWriteTimeout,
+		Signature: SignatureConfig{
+			Encoding:      signatureEncodingHex,
+			SignedPayload: signedPayloadBody,
+			Tolerance:     defaultTolerance,
+		},
+		SplitMode:               splitModeLine,
+		MaxDecompressedBodySize: defaultMaxDecompressedBodySize,
 	}
 }
 
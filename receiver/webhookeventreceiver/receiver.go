@@ -4,7 +4,6 @@
 package webhookeventreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/webhookeventreceiver"
 
 import (
-	"bufio"
 	"compress/gzip"
 	"context"
 	"errors"
@@ -30,6 +29,7 @@ var (
 	errInvalidRequestMethod  = errors.New("invalid method. Valid method is POST")
 	errInvalidEncodingType   = errors.New("invalid encoding type")
 	errEmptyResponseBody     = errors.New("request body content length is zero")
+	errBodyTooLarge          = errors.New("decompressed request body exceeds max_decompressed_body_size")
 	errMissingRequiredHeader = errors.New("request was missing required header or incorrect header value")
 )
 
@@ -43,6 +43,7 @@ type eventReceiver struct {
 	shutdownWG  sync.WaitGroup
 	obsrecv     *receiverhelper.ObsReport
 	gzipPool    *sync.Pool
+	verifier    *signatureVerifier
 }
 
 func newLogsReceiver(params receiver.CreateSettings, cfg Config, consumer consumer.Logs) (receiver.Logs, error) {
@@ -78,6 +79,10 @@ func newLogsReceiver(params receiver.CreateSettings, cfg Config, consumer consum
 		gzipPool:    &sync.Pool{New: func() any { return new(gzip.Reader) }},
 	}
 
+	if cfg.Signature.enabled() {
+		er.verifier = newSignatureVerifier(cfg.Signature)
+	}
+
 	return er, nil
 }
 
@@ -190,13 +195,40 @@ func (er *eventReceiver) handleReq(w http.ResponseWriter, r *http.Request, _ htt
 		defer er.gzipPool.Put(reader)
 	}
 
+	// read one more byte than allowed to detect the bodies that exceed the limit
+	body, err := io.ReadAll(io.LimitReader(bodyReader, er.cfg.MaxDecompressedBodySize+1))
+	_ = bodyReader.Close()
+	if err != nil {
+		er.failBadReq(ctx, w, http.StatusBadRequest, err)
+		er.obsrecv.EndLogsOp(ctx, metadata.Type.String(), 0, err)
+		return
+	}
+	if int64(len(body)) > er.cfg.MaxDecompressedBodySize {
+		er.failBadReq(ctx, w, http.StatusRequestEntityTooLarge, errBodyTooLarge)
+		er.obsrecv.EndLogsOp(ctx, metadata.Type.String(), 0, errBodyTooLarge)
+		return
+	}
+
+	// the signature is computed over the decompressed body
+	if er.verifier != nil {
+		if err = er.verifier.verify(r.Header, body); err != nil {
+			er.failBadReq(ctx, w, http.StatusUnauthorized, err)
+			er.obsrecv.EndLogsOp(ctx, metadata.Type.String(), 0, err)
+			return
+		}
+	}
+
+	records, err := splitBody(body, er.cfg.SplitMode)
+	if err != nil {
+		er.failBadReq(ctx, w, http.StatusBadRequest, err)
+		er.obsrecv.EndLogsOp(ctx, metadata.Type.String(), 0, err)
+		return
+	}
+
 	// finish reading the body into a log
-	sc := bufio.NewScanner(bodyReader)
-	ld, numLogs := reqToLog(sc, r.URL.Query(), er.cfg, er.settings)
+	ld, numLogs := reqToLog(records, r.Header, r.URL.Query(), er.cfg, er.settings)
 	consumerErr := er.logConsumer.ConsumeLogs(ctx, ld)
 
-	_ = bodyReader.Close()
-
 	if consumerErr != nil {
 		er.failBadReq(ctx, w, http.StatusInternalServerError, consumerErr)
 		er.obsrecv.EndLogsOp(ctx, metadata.Type.String(), numLogs, nil)
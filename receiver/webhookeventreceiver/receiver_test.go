@@ -7,6 +7,7 @@ import (
 	"bytes"
 	"compress/gzip"
 	"context"
+	"encoding/hex"
 	"encoding/json"
 	"io"
 	"net/http"
@@ -74,6 +75,11 @@ func TestCreateNewLogReceiver(t *testing.T) {
 func TestHandleReq(t *testing.T) {
 	cfg := createDefaultConfig().(*Config)
 	cfg.Endpoint = "localhost:0"
+	signedCfg := createDefaultConfig().(*Config)
+	signedCfg.Endpoint = "localhost:0"
+	signedCfg.Signature.Header = "X-Hub-Signature-256"
+	signedCfg.Signature.Prefix = "sha256="
+	signedCfg.Signature.Secret = testSecret
 
 	tests := []struct {
 		desc string
@@ -116,6 +122,15 @@ func TestHandleReq(t *testing.T) {
 			cfg:  *cfg,
 			req:  httptest.NewRequest("POST", "http://localhost/events", strings.NewReader("log1\nlog2")),
 		},
+		{
+			desc: "Good request with signature",
+			cfg:  *signedCfg,
+			req: func() *http.Request {
+				req := httptest.NewRequest("POST", "http://localhost/events", strings.NewReader(testBody))
+				req.Header.Set("X-Hub-Signature-256", "sha256="+hex.EncodeToString(sign(testBody)))
+				return req
+			}(),
+		},
 	}
 
 	for _, test := range tests {
@@ -150,6 +165,17 @@ func TestFailedReq(t *testing.T) {
 	headerCfg.Endpoint = "localhost:0"
 	headerCfg.RequiredHeader.Key = "key-present"
 	headerCfg.RequiredHeader.Value = "value-present"
+	signedCfg := createDefaultConfig().(*Config)
+	signedCfg.Endpoint = "localhost:0"
+	signedCfg.Signature.Header = "X-Hub-Signature-256"
+	signedCfg.Signature.Prefix = "sha256="
+	signedCfg.Signature.Secret = testSecret
+	jsonCfg := createDefaultConfig().(*Config)
+	jsonCfg.Endpoint = "localhost:0"
+	jsonCfg.SplitMode = splitModeJSONArray
+	limitedCfg := createDefaultConfig().(*Config)
+	limitedCfg.Endpoint = "localhost:0"
+	limitedCfg.MaxDecompressedBodySize = 16
 
 	tests := []struct {
 		desc   string
@@ -199,6 +225,49 @@ func TestFailedReq(t *testing.T) {
 			}(),
 			status: http.StatusUnauthorized,
 		},
+		{
+			desc: "Invalid signature",
+			cfg:  *signedCfg,
+			req: func() *http.Request {
+				req := httptest.NewRequest("POST", "http://localhost/events", strings.NewReader("tampered"))
+				req.Header.Set("X-Hub-Signature-256", "sha256="+hex.EncodeToString(sign(testBody)))
+				return req
+			}(),
+			status: http.StatusUnauthorized,
+		},
+		{
+			desc:   "Missing signature",
+			cfg:    *signedCfg,
+			req:    httptest.NewRequest("POST", "http://localhost/events", strings.NewReader(testBody)),
+			status: http.StatusUnauthorized,
+		},
+		{
+			desc:   "Invalid JSON body",
+			cfg:    *jsonCfg,
+			req:    httptest.NewRequest("POST", "http://localhost/events", strings.NewReader("log1\nlog2")),
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:   "Body too large",
+			cfg:    *limitedCfg,
+			req:    httptest.NewRequest("POST", "http://localhost/events", strings.NewReader(strings.Repeat("a", 17))),
+			status: http.StatusRequestEntityTooLarge,
+		},
+		{
+			desc: "Decompressed body too large",
+			cfg:  *limitedCfg,
+			req: func() *http.Request {
+				var msg bytes.Buffer
+				gzipWriter := gzip.NewWriter(&msg)
+				_, err := gzipWriter.Write(bytes.Repeat([]byte("a"), 1024))
+				require.NoError(t, err)
+				require.NoError(t, gzipWriter.Close())
+				req := httptest.NewRequest("POST", "http://localhost/events", &msg)
+				req.Header.Set("Content-Encoding", "gzip")
+				return req
+			}(),
+			status: http.StatusRequestEntityTooLarge,
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.desc, func(t *testing.T) {
@@ -221,6 +290,32 @@ func TestFailedReq(t *testing.T) {
 	}
 }
 
+func TestHandleReqSplitJSONArray(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = "localhost:0"
+	cfg.SplitMode = splitModeJSONArray
+	cfg.HeaderAttributes = map[string]string{"X-GitHub-Event": "github.event"}
+
+	sink := new(consumertest.LogsSink)
+	receiver, err := newLogsReceiver(receivertest.NewNopCreateSettings(), *cfg, sink)
+	require.NoError(t, err, "Failed to create receiver")
+	r := receiver.(*eventReceiver)
+
+	req := httptest.NewRequest("POST", "http://localhost/events", strings.NewReader("[\n  {\"id\": 1},\n  {\"id\": 2}\n]"))
+	req.Header.Set("X-GitHub-Event", "push")
+	w := httptest.NewRecorder()
+	r.handleReq(w, req, httprouter.ParamsFromContext(context.Background()))
+	require.Equal(t, http.StatusOK, w.Result().StatusCode)
+
+	require.Equal(t, 2, sink.LogRecordCount())
+	logRecords := sink.AllLogs()[0].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords()
+	require.Equal(t, `{"id":1}`, logRecords.At(0).Body().Str())
+	require.Equal(t, `{"id":2}`, logRecords.At(1).Body().Str())
+	v, ok := logRecords.At(1).Attributes().Get("github.event")
+	require.True(t, ok)
+	require.Equal(t, "push", v.Str())
+}
+
 func TestHealthCheck(t *testing.T) {
 	defaultConfig := createDefaultConfig().(*Config)
 	defaultConfig.Endpoint = "localhost:0"
@@ -5,6 +5,11 @@ package webhookeventreceiver // import "github.com/open-telemetry/opentelemetry-
 
 import (
 	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
 	"net/url"
 
 	"go.opentelemetry.io/collector/pdata/plog"
@@ -13,9 +18,12 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/webhookeventreceiver/internal/metadata"
 )
 
-func reqToLog(sc *bufio.Scanner,
+var errInvalidJSONBody = errors.New("request body is not valid JSON")
+
+func reqToLog(records []string,
+	header http.Header,
 	query url.Values,
-	_ *Config,
+	cfg *Config,
 	settings receiver.CreateSettings) (plog.Logs, int) {
 	log := plog.NewLogs()
 	resourceLog := log.ResourceLogs().AppendEmpty()
@@ -27,10 +35,10 @@ func reqToLog(sc *bufio.Scanner,
 	scopeLog.Scope().Attributes().PutStr("source", settings.ID.String())
 	scopeLog.Scope().Attributes().PutStr("receiver", metadata.Type.String())
 
-	for sc.Scan() {
+	for _, record := range records {
 		logRecord := scopeLog.LogRecords().AppendEmpty()
-		line := sc.Text()
-		logRecord.Body().SetStr(line)
+		logRecord.Body().SetStr(record)
+		appendHeaders(logRecord, header, cfg.HeaderAttributes)
 	}
 
 	return log, scopeLog.LogRecords().Len()
@@ -45,3 +53,91 @@ func appendMetadata(resourceLog plog.ResourceLogs, query url.Values) {
 	}
 
 }
+
+// append the values of the mapped request headers as log attributes
+func appendHeaders(logRecord plog.LogRecord, header http.Header, headerAttributes map[string]string) {
+	for name, attribute := range headerAttributes {
+		if value := header.Get(name); value != "" {
+			logRecord.Attributes().PutStr(attribute, value)
+		}
+	}
+}
+
+// splitBody splits the request body into the bodies of the logs according to
+// the split mode.
+func splitBody(body []byte, splitMode string) ([]string, error) {
+	switch splitMode {
+	case splitModeJSONArray:
+		return splitJSONArray(body)
+	case splitModeNDJSON:
+		return splitNDJSON(body)
+	default:
+		return splitLines(body), nil
+	}
+}
+
+// splitLines returns one record per line of the body.
+func splitLines(body []byte) []string {
+	var records []string
+	sc := bufio.NewScanner(bytes.NewReader(body))
+	for sc.Scan() {
+		records = append(records, sc.Text())
+	}
+	return records
+}
+
+// splitJSONArray returns one record per element of a JSON array, or a single
+// record if the body is another JSON value. Records are compacted so that
+// pretty printed payloads produce single line logs.
+func splitJSONArray(body []byte) ([]string, error) {
+	body = bytes.TrimSpace(body)
+	if !json.Valid(body) {
+		return nil, errInvalidJSONBody
+	}
+	if len(body) == 0 || body[0] != '[' {
+		record, err := compactJSON(body)
+		if err != nil {
+			return nil, err
+		}
+		return []string{record}, nil
+	}
+
+	var elements []json.RawMessage
+	if err := json.Unmarshal(body, &elements); err != nil {
+		return nil, errInvalidJSONBody
+	}
+	records := make([]string, 0, len(elements))
+	for _, element := range elements {
+		record, err := compactJSON(element)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// splitNDJSON returns one record per non empty line of the body, each of which
+// must be a JSON value.
+func splitNDJSON(body []byte) ([]string, error) {
+	var records []string
+	for i, line := range bytes.Split(body, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		if !json.Valid(line) {
+			return nil, fmt.Errorf("%w: line %d", errInvalidJSONBody, i+1)
+		}
+		records = append(records, string(line))
+	}
+	return records, nil
+}
+
+func compactJSON(data []byte) (string, error) {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, data); err != nil {
+		return "", errInvalidJSONBody
+	}
+	return buf.String(), nil
+}
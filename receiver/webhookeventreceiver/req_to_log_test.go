@@ -4,10 +4,8 @@
 package webhookeventreceiver
 
 import (
-	"bufio"
-	"bytes"
-	"io"
 	"log"
+	"net/http"
 	"net/url"
 	"testing"
 
@@ -19,19 +17,21 @@ import (
 
 func TestReqToLog(t *testing.T) {
 	defaultConfig := createDefaultConfig().(*Config)
+	defaultConfig.HeaderAttributes = map[string]string{
+		"X-GitHub-Event": "github.event",
+		"X-Missing":      "missing",
+	}
 
 	tests := []struct {
-		desc  string
-		sc    *bufio.Scanner
-		query url.Values
-		tt    func(t *testing.T, reqLog plog.Logs, reqLen int, settings receiver.CreateSettings)
+		desc    string
+		records []string
+		header  http.Header
+		query   url.Values
+		tt      func(t *testing.T, reqLog plog.Logs, reqLen int, settings receiver.CreateSettings)
 	}{
 		{
-			desc: "Valid query valid event",
-			sc: func() *bufio.Scanner {
-				reader := io.NopCloser(bytes.NewReader([]byte("this is a: log")))
-				return bufio.NewScanner(reader)
-			}(),
+			desc:    "Valid query valid event",
+			records: splitLines([]byte("this is a: log")),
 			query: func() url.Values {
 				v, err := url.ParseQuery(`qparam1=hello&qparam2=world`)
 				if err != nil {
@@ -61,11 +61,8 @@ func TestReqToLog(t *testing.T) {
 			},
 		},
 		{
-			desc: "Query is empty",
-			sc: func() *bufio.Scanner {
-				reader := io.NopCloser(bytes.NewReader([]byte("this is a: log")))
-				return bufio.NewScanner(reader)
-			}(),
+			desc:    "Query is empty",
+			records: splitLines([]byte("this is a: log")),
 			tt: func(t *testing.T, reqLog plog.Logs, reqLen int, _ receiver.CreateSettings) {
 				require.Equal(t, 1, reqLen)
 
@@ -76,12 +73,94 @@ func TestReqToLog(t *testing.T) {
 				require.Equal(t, 2, scopeLogsScope.Attributes().Len())
 			},
 		},
+		{
+			desc:    "Mapped headers",
+			records: []string{`{"action":"opened"}`, `{"action":"closed"}`},
+			header: http.Header{
+				"X-Github-Event": []string{"pull_request"},
+				"X-Other":        []string{"ignored"},
+			},
+			tt: func(t *testing.T, reqLog plog.Logs, reqLen int, _ receiver.CreateSettings) {
+				require.Equal(t, 2, reqLen)
+
+				logRecords := reqLog.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords()
+				for i := 0; i < logRecords.Len(); i++ {
+					require.Equal(t, map[string]any{"github.event": "pull_request"}, logRecords.At(i).Attributes().AsRaw())
+				}
+				require.Equal(t, `{"action":"closed"}`, logRecords.At(1).Body().Str())
+			},
+		},
 	}
 
 	for _, test := range tests {
 		t.Run(test.desc, func(t *testing.T) {
-			reqLog, reqLen := reqToLog(test.sc, test.query, defaultConfig, receivertest.NewNopCreateSettings())
+			reqLog, reqLen := reqToLog(test.records, test.header, test.query, defaultConfig, receivertest.NewNopCreateSettings())
 			test.tt(t, reqLog, reqLen, receivertest.NewNopCreateSettings())
 		})
 	}
 }
+
+func TestSplitBody(t *testing.T) {
+	tests := []struct {
+		desc      string
+		body      string
+		splitMode string
+		records   []string
+		err       string
+	}{
+		{
+			desc:      "Lines",
+			body:      "log1\nlog2\n",
+			splitMode: splitModeLine,
+			records:   []string{"log1", "log2"},
+		},
+		{
+			desc:      "JSON array",
+			body:      "[\n  {\"id\": 1},\n  {\"id\": 2, \"tags\": [\"a\", \"b\"]},\n  \"text\"\n]",
+			splitMode: splitModeJSONArray,
+			records:   []string{`{"id":1}`, `{"id":2,"tags":["a","b"]}`, `"text"`},
+		},
+		{
+			desc:      "JSON object in json_array mode",
+			body:      "{\n  \"id\": 1\n}\n",
+			splitMode: splitModeJSONArray,
+			records:   []string{`{"id":1}`},
+		},
+		{
+			desc:      "Empty JSON array",
+			body:      "[]",
+			splitMode: splitModeJSONArray,
+			records:   []string{},
+		},
+		{
+			desc:      "Invalid JSON array",
+			body:      `[{"id": 1},`,
+			splitMode: splitModeJSONArray,
+			err:       "request body is not valid JSON",
+		},
+		{
+			desc:      "NDJSON",
+			body:      "{\"id\": 1}\r\n\n{\"id\": 2}\n",
+			splitMode: splitModeNDJSON,
+			records:   []string{`{"id": 1}`, `{"id": 2}`},
+		},
+		{
+			desc:      "Invalid NDJSON",
+			body:      "{\"id\": 1}\nnot json\n",
+			splitMode: splitModeNDJSON,
+			err:       "request body is not valid JSON: line 2",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			records, err := splitBody([]byte(test.body), test.splitMode)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.records, records)
+		})
+	}
+}
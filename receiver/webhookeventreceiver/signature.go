@@ -0,0 +1,108 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package webhookeventreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/webhookeventreceiver"
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	errMissingSignature  = errors.New("request is missing the signature header")
+	errInvalidSignature  = errors.New("request signature does not match")
+	errMissingTimestamp  = errors.New("request is missing the signature timestamp")
+	errInvalidTimestamp  = errors.New("request signature timestamp is not a Unix time")
+	errSignatureTooOld   = errors.New("request signature timestamp is outside of the tolerance")
+	errSignatureEncoding = errors.New("request signature is not correctly encoded")
+)
+
+// signatureVerifier checks the HMAC-SHA256 signature of requests, as sent by
+// GitHub, Stripe or Slack webhooks.
+type signatureVerifier struct {
+	cfg SignatureConfig
+	now func() time.Time
+}
+
+func newSignatureVerifier(cfg SignatureConfig) *signatureVerifier {
+	return &signatureVerifier{cfg: cfg, now: time.Now}
+}
+
+// verify returns an error unless one of the signatures of the request matches
+// the body and, when configured, the timestamp is within the tolerance.
+func (v *signatureVerifier) verify(header http.Header, body []byte) error {
+	signatures := headerElements(header.Get(v.cfg.Header), v.cfg.Prefix)
+	if len(signatures) == 0 {
+		return errMissingSignature
+	}
+
+	var timestamp string
+	if v.cfg.TimestampHeader != "" {
+		timestamps := headerElements(header.Get(v.cfg.TimestampHeader), v.cfg.TimestampPrefix)
+		if len(timestamps) == 0 {
+			return errMissingTimestamp
+		}
+		timestamp = timestamps[0]
+		seconds, err := strconv.ParseInt(timestamp, 10, 64)
+		if err != nil {
+			return errInvalidTimestamp
+		}
+		age := v.now().Sub(time.Unix(seconds, 0))
+		if age > v.cfg.Tolerance || age < -v.cfg.Tolerance {
+			return errSignatureTooOld
+		}
+	}
+
+	mac := hmac.New(sha256.New, []byte(v.cfg.Secret))
+	before, after, _ := strings.Cut(strings.ReplaceAll(v.cfg.SignedPayload, signedPayloadTimestamp, timestamp), signedPayloadBody)
+	mac.Write([]byte(before))
+	mac.Write(body)
+	mac.Write([]byte(after))
+	expected := mac.Sum(nil)
+
+	decodeErr := errSignatureEncoding
+	for _, signature := range signatures {
+		actual, err := v.decode(signature)
+		if err != nil {
+			continue
+		}
+		decodeErr = nil
+		if hmac.Equal(expected, actual) {
+			return nil
+		}
+	}
+	if decodeErr != nil {
+		return decodeErr
+	}
+	return errInvalidSignature
+}
+
+func (v *signatureVerifier) decode(signature string) ([]byte, error) {
+	if v.cfg.Encoding == signatureEncodingBase64 {
+		return base64.StdEncoding.DecodeString(signature)
+	}
+	return hex.DecodeString(signature)
+}
+
+// headerElements returns the comma separated elements of a header value that
+// start with the prefix, without it.
+func headerElements(value, prefix string) []string {
+	var elements []string
+	for _, element := range strings.Split(value, ",") {
+		element = strings.TrimSpace(element)
+		if element == "" {
+			continue
+		}
+		if trimmed, ok := strings.CutPrefix(element, prefix); ok {
+			elements = append(elements, trimmed)
+		}
+	}
+	return elements
+}
@@ -0,0 +1,173 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package webhookeventreceiver
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testSecret    = "It's a Secret to Everybody"
+	testBody      = "Hello, World!"
+	testTimestamp = "1531420618"
+)
+
+func sign(payload string) []byte {
+	mac := hmac.New(sha256.New, []byte(testSecret))
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
+
+func TestSignatureVerifier(t *testing.T) {
+	defaultSignature := createDefaultConfig().(*Config).Signature
+	receivedAt := time.Unix(1531420618, 0).Add(time.Minute)
+
+	github := defaultSignature
+	github.Header = "X-Hub-Signature-256"
+	github.Prefix = "sha256="
+	github.Secret = testSecret
+
+	slack := defaultSignature
+	slack.Header = "X-Slack-Signature"
+	slack.Prefix = "v0="
+	slack.Secret = testSecret
+	slack.SignedPayload = "v0:{timestamp}:{body}"
+	slack.TimestampHeader = "X-Slack-Request-Timestamp"
+
+	stripe := defaultSignature
+	stripe.Header = "Stripe-Signature"
+	stripe.Prefix = "v1="
+	stripe.Secret = testSecret
+	stripe.SignedPayload = "{timestamp}.{body}"
+	stripe.TimestampHeader = "Stripe-Signature"
+	stripe.TimestampPrefix = "t="
+
+	base64Signature := defaultSignature
+	base64Signature.Header = "X-Signature"
+	base64Signature.Secret = testSecret
+	base64Signature.Encoding = signatureEncodingBase64
+
+	tests := []struct {
+		desc   string
+		cfg    SignatureConfig
+		header http.Header
+		err    error
+	}{
+		{
+			desc: "GitHub",
+			cfg:  github,
+			header: http.Header{
+				"X-Hub-Signature-256": []string{"sha256=" + hex.EncodeToString(sign(testBody))},
+			},
+		},
+		{
+			desc: "GitHub with wrong secret",
+			cfg:  github,
+			header: http.Header{
+				// the GitHub documentation example signature, for another secret
+				"X-Hub-Signature-256": []string{"sha256=757107ea0eb2509fc211221cce984b8a37570b6d7586c22c46f4379c8b043e18"},
+			},
+			err: errInvalidSignature,
+		},
+		{
+			desc:   "GitHub without signature",
+			cfg:    github,
+			header: http.Header{},
+			err:    errMissingSignature,
+		},
+		{
+			desc: "GitHub with wrong prefix",
+			cfg:  github,
+			header: http.Header{
+				"X-Hub-Signature-256": []string{"sha1=" + hex.EncodeToString(sign(testBody))},
+			},
+			err: errMissingSignature,
+		},
+		{
+			desc: "GitHub with invalid hex",
+			cfg:  github,
+			header: http.Header{
+				"X-Hub-Signature-256": []string{"sha256=not-hex"},
+			},
+			err: errSignatureEncoding,
+		},
+		{
+			desc: "Slack",
+			cfg:  slack,
+			header: http.Header{
+				"X-Slack-Signature":         []string{"v0=" + hex.EncodeToString(sign("v0:"+testTimestamp+":"+testBody))},
+				"X-Slack-Request-Timestamp": []string{testTimestamp},
+			},
+		},
+		{
+			desc: "Slack with replayed signature",
+			cfg:  slack,
+			header: http.Header{
+				"X-Slack-Signature":         []string{"v0=" + hex.EncodeToString(sign("v0:"+testTimestamp+":"+testBody))},
+				"X-Slack-Request-Timestamp": []string{"1531420000"},
+			},
+			err: errSignatureTooOld,
+		},
+		{
+			desc: "Slack without timestamp",
+			cfg:  slack,
+			header: http.Header{
+				"X-Slack-Signature": []string{"v0=" + hex.EncodeToString(sign("v0:"+testTimestamp+":"+testBody))},
+			},
+			err: errMissingTimestamp,
+		},
+		{
+			desc: "Slack with invalid timestamp",
+			cfg:  slack,
+			header: http.Header{
+				"X-Slack-Signature":         []string{"v0=" + hex.EncodeToString(sign("v0:"+testTimestamp+":"+testBody))},
+				"X-Slack-Request-Timestamp": []string{"yesterday"},
+			},
+			err: errInvalidTimestamp,
+		},
+		{
+			desc: "Stripe with rotated secrets",
+			cfg:  stripe,
+			header: http.Header{
+				"Stripe-Signature": []string{"t=" + testTimestamp + ",v1=" + hex.EncodeToString(sign("old secret")) + ",v1=" + hex.EncodeToString(sign(testTimestamp+"."+testBody)) + ",v0=abc"},
+			},
+		},
+		{
+			desc: "Stripe with tampered timestamp",
+			cfg:  stripe,
+			header: http.Header{
+				"Stripe-Signature": []string{"t=1531420619,v1=" + hex.EncodeToString(sign(testTimestamp+"."+testBody))},
+			},
+			err: errInvalidSignature,
+		},
+		{
+			desc: "Base64",
+			cfg:  base64Signature,
+			header: http.Header{
+				"X-Signature": []string{base64.StdEncoding.EncodeToString(sign(testBody))},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			v := newSignatureVerifier(test.cfg)
+			v.now = func() time.Time { return receivedAt }
+			err := v.verify(test.header, []byte(testBody))
+			if test.err == nil {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, test.err)
+			}
+		})
+	}
+}